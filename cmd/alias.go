@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+// AddAliases registers every alias of the configuration as a hidden subcommand of root. An
+// alias runs root again with its command line followed by the arguments it was given, so
+// that flags are parsed, defaulted and validated by the command it expands to. An alias may
+// expand to another alias, but not back to itself, and can't be named after a command.
+func AddAliases(root *cobra.Command, aliases map[string]string) error {
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	expansions := make(map[string][]string, len(aliases))
+	for name, target := range aliases {
+		args, err := splitCommandLine(target)
+		if err != nil {
+			return fmt.Errorf("alias %q: %w", name, err)
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("alias %q has no command", name)
+		}
+		expansions[name] = args
+	}
+
+	names := make([]string, 0, len(expansions))
+	for name := range expansions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	// The commands cobra adds on execution can't be shadowed either
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+
+	for _, name := range names {
+		if command := findSubcommand(root, name); command != nil {
+			return fmt.Errorf("alias %q shadows the %q command", name, command.Name())
+		}
+
+		// Follow the chain of aliases to the command it ends with
+		chain := []string{name}
+		for next := expansions[name][0]; ; next = expansions[next][0] {
+			if slices.Contains(chain, next) {
+				return fmt.Errorf("alias %q is recursive: %s", name, strings.Join(append(chain, next), " → "))
+			}
+			if _, ok := expansions[next]; !ok {
+				if findSubcommand(root, next) == nil {
+					return fmt.Errorf("alias %q runs unknown command %q", name, next)
+				}
+				break
+			}
+			chain = append(chain, next)
+		}
+	}
+
+	for _, name := range names {
+		root.AddCommand(aliasCmd(root, name, expansions[name]))
+	}
+	return nil
+}
+
+// aliasCmd returns the hidden command of an alias. Flag parsing is left to the command the
+// alias expands to.
+func aliasCmd(root *cobra.Command, name string, expansion []string) *cobra.Command {
+	return &cobra.Command{
+		Use:                name,
+		Short:              fmt.Sprintf("Alias for '%s'", strings.Join(expansion, " ")),
+		Hidden:             true,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root.SetArgs(append(slices.Clone(expansion), args...))
+			return root.ExecuteContext(cmd.Context())
+		},
+	}
+}
+
+// findSubcommand returns the subcommand of root called name or having it as an alias
+func findSubcommand(root *cobra.Command, name string) *cobra.Command {
+	for _, command := range root.Commands() {
+		if command.Name() == name || command.HasAlias(name) {
+			return command
+		}
+	}
+	return nil
+}
+
+// splitCommandLine splits a command line into arguments on spaces, keeping the text
+// between single or double quotes together, e.g. `sum --since "last week"`
+func splitCommandLine(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, line)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
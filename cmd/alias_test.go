@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newTestRoot returns a root command with a few of the real commands
+func newTestRoot() *cobra.Command {
+	root := &cobra.Command{Use: "daily", SilenceErrors: true, SilenceUsage: true}
+	root.AddCommand(HideCmd(), ReviewsCmd(), SumCmd())
+	return root
+}
+
+func TestAddAliases(t *testing.T) {
+	root := newTestRoot()
+	err := AddAliases(root, map[string]string{
+		"today": "sum --since 1d --output text --compact",
+		"rev":   "reviews -o tui",
+		"r":     "rev",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	for _, name := range []string{"today", "rev", "r"} {
+		alias := findSubcommand(root, name)
+		if alias == nil {
+			t.Fatalf("Expected alias %s to be registered", name)
+		}
+		if !alias.Hidden || !alias.DisableFlagParsing {
+			t.Errorf("Expected alias %s to be hidden and leave flags to its command", name)
+		}
+	}
+	if short := findSubcommand(root, "today").Short; short != "Alias for 'sum --since 1d --output text --compact'" {
+		t.Errorf("Expected alias description, got '%s'", short)
+	}
+}
+
+func TestAddAliases_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		aliases  map[string]string
+		expected string
+	}{
+		{"shadows command", map[string]string{"sum": "reviews"}, `alias "sum" shadows the "sum" command`},
+		{"shadows help", map[string]string{"help": "sum"}, `alias "help" shadows the "help" command`},
+		{"calls itself", map[string]string{"loop": "loop --since 1d"}, `alias "loop" is recursive: loop → loop`},
+		{"mutual recursion", map[string]string{"a": "b", "b": "c -o json", "c": "a"}, `alias "a" is recursive: a → b → c → a`},
+		{"unknown command", map[string]string{"x": "nope"}, `alias "x" runs unknown command "nope"`},
+		{"unterminated quote", map[string]string{"x": `sum --since "1d`}, `alias "x": unterminated " quote`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newTestRoot()
+			err := AddAliases(root, tt.aliases)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("Expected error containing '%s', got: %v", tt.expected, err)
+			}
+			for name := range tt.aliases {
+				if alias := findSubcommand(root, name); alias != nil && alias.Hidden {
+					t.Errorf("Expected no alias to be registered, got %s", name)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{"sum --since 1d", []string{"sum", "--since", "1d"}},
+		{"  reviews   -o tui ", []string{"reviews", "-o", "tui"}},
+		{`hide --pattern "release notes"`, []string{"hide", "--pattern", "release notes"}},
+		{`hide -p 'it''s' ""`, []string{"hide", "-p", "its", ""}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := splitCommandLine(tt.line)
+		if err != nil {
+			t.Errorf("Expected no error for '%s', got: %v", tt.line, err)
+			continue
+		}
+		if strings.Join(got, "|") != strings.Join(tt.expected, "|") || len(got) != len(tt.expected) {
+			t.Errorf("Expected %q for '%s', got %q", tt.expected, tt.line, got)
+		}
+	}
+}
+
+func TestAlias_EndToEnd(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // hide loads the config
+	statePath := filepath.Join(t.TempDir(), "hidden.json")
+	fixture := `{"ids": ["jira-PROJ-1"], "rules": [{"pattern": "dependabot", "created_at": "2024-01-15T09:00:00Z"}]}`
+	if err := os.WriteFile(statePath, []byte(fixture), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	originalStatePath := hiddenStatePath
+	hiddenStatePath = func() (string, error) { return statePath, nil }
+	defer func() { hiddenStatePath = originalStatePath }()
+
+	root := newTestRoot()
+	err := AddAliases(root, map[string]string{
+		"hidden": "hide --list",
+		"rev":    "reviews",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	os.Stdout = w
+	root.SetArgs([]string{"hidden"})
+	err = root.Execute()
+	_ = w.Close()
+	os.Stdout = stdout
+	out, _ := io.ReadAll(r)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, want := range []string{`"dependabot" (since 2024-01-15)`, "jira-PROJ-1"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected output to contain '%s', got: %s", want, out)
+		}
+	}
+
+	// Extra arguments are parsed by the command, against its own defaults (tui output)
+	root.SetArgs([]string{"rev", "--out-file", filepath.Join(t.TempDir(), "out.txt")})
+	err = root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--out-file cannot be used with tui output") {
+		t.Errorf("Expected tui conflict error, got: %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/cache"
+	"daily/internal/config"
+	"daily/internal/output"
+	"daily/internal/verboselog"
+)
+
+const (
+	// cachePassphraseEnv holds the passphrase used to encrypt cached summaries
+	cachePassphraseEnv = "DAILY_CACHE_PASSPHRASE"
+
+	// cacheNewPassphraseEnv holds the replacement passphrase for 'cache rotate-key'
+	cacheNewPassphraseEnv = "DAILY_CACHE_NEW_PASSPHRASE"
+)
+
+// newSummaryCache creates the summary cache, encrypted when configured
+func newSummaryCache(cfg *config.Config) (*cache.Cache, error) {
+	switch cfg.Cache.Encryption {
+	case "":
+		return cache.NewCache()
+	case config.CacheEncryptionAge:
+		passphrase := os.Getenv(cachePassphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("cache encryption is enabled but %s is not set", cachePassphraseEnv)
+		}
+		return cache.NewEncryptedCache(passphrase)
+	default:
+		return nil, fmt.Errorf("unsupported cache encryption: %s (must be empty or '%s')", cfg.Cache.Encryption, config.CacheEncryptionAge)
+	}
+}
+
+func CacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage cached summaries",
+		Long:  "Manage the summaries cached for historical dates.",
+	}
+
+	cmd.AddCommand(cacheRotateKeyCmd())
+	cmd.AddCommand(cachePruneCmd())
+
+	return cmd
+}
+
+func cacheRotateKeyCmd() *cobra.Command {
+	var dropUndecryptable bool
+
+	cmd := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Re-encrypt cached summaries with a new passphrase",
+		Long: fmt.Sprintf("Re-encrypt every cached summary with the passphrase in %s. "+
+			"The current passphrase is read from %s. Nothing is written when an entry can't be decrypted with it, "+
+			"unless --drop-undecryptable is given to remove those entries. "+
+			"Leave %s empty to store entries as plain JSON again.", cacheNewPassphraseEnv, cachePassphraseEnv, cacheNewPassphraseEnv),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			newPassphrase := os.Getenv(cacheNewPassphraseEnv)
+			if newPassphrase == "" && cfg.Cache.Encryption == config.CacheEncryptionAge {
+				return fmt.Errorf("%s must be set while cache encryption is enabled", cacheNewPassphraseEnv)
+			}
+
+			// The current cache may be unencrypted (e.g. when enabling encryption for the first time),
+			// RotateKey refuses to go on without the passphrase of an encrypted one
+			passphrase := os.Getenv(cachePassphraseEnv)
+			summaryCache, err := cache.NewCache()
+			if err != nil {
+				return fmt.Errorf("failed to initialize cache: %w", err)
+			}
+			if passphrase != "" {
+				summaryCache, err = cache.NewEncryptedCache(passphrase)
+				if err != nil {
+					return fmt.Errorf("failed to initialize cache: %w", err)
+				}
+			}
+
+			rotated, removed, err := summaryCache.RotateKey(newPassphrase, dropUndecryptable)
+			if errors.Is(err, cache.ErrPassphraseRequired) {
+				return fmt.Errorf("%s must be set to the current passphrase of the encrypted cache", cachePassphraseEnv)
+			}
+			if errors.Is(err, cache.ErrUndecryptable) {
+				return fmt.Errorf("%w; check %s, or use --drop-undecryptable to remove the entries it can't decrypt", err, cachePassphraseEnv)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to rotate cache key: %w", err)
+			}
+
+			fmt.Printf("✅ Re-encrypted %d cached summaries\n", rotated)
+			if removed > 0 {
+				fmt.Printf("⚠️  Removed %d entries that could not be decrypted\n", removed)
+			}
+			fmt.Printf("Remember to update %s to the new passphrase\n", cachePassphraseEnv)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dropUndecryptable, "drop-undecryptable", false, "Remove the entries the current passphrase can't decrypt instead of failing")
+
+	return cmd
+}
+
+func cachePruneCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete old cached summaries and crash reports",
+		Long: fmt.Sprintf("Delete the cached summaries and crash reports of dates older than cache.max_age_days (default %d), "+
+			"then the oldest of them until they fit in cache.max_size_mb (default %d). "+
+			"Only summary_<date>.json and crash-<view>-<time>.txt files are deleted. "+
+			"The cache is also pruned automatically once a day by the commands that write to it: sum, todo, reviews, upnext, mentions and notify.",
+			config.DefaultCacheMaxAgeDays, config.DefaultCacheMaxSizeMB),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			summaryCache, err := cache.NewCache()
+			if err != nil {
+				return fmt.Errorf("failed to initialize cache: %w", err)
+			}
+
+			opts := pruneOptions(cfg)
+			opts.DryRun = dryRun
+			result, err := summaryCache.Prune(time.Now(), opts)
+			if err != nil {
+				return fmt.Errorf("failed to prune cache: %w", err)
+			}
+
+			fmt.Print(output.NewFormatter().FormatPruneResult(result))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the files that would be deleted without deleting them")
+
+	return cmd
+}
+
+// pruneOptions returns the configured cache limits
+func pruneOptions(cfg *config.Config) cache.PruneOptions {
+	return cache.PruneOptions{MaxAge: cfg.Cache.MaxAge(), MaxBytes: cfg.Cache.MaxBytes()}
+}
+
+// autoPruneCache prunes the cache at most once a day, from the commands writing to it.
+// Pruning is best effort: a failure never stops the command, and is only reported to
+// reporter.
+func autoPruneCache(cfg *config.Config, reporter *verboselog.Reporter) {
+	summaryCache, err := cache.NewCache()
+	if err != nil {
+		return
+	}
+	state, err := cache.NewTTLStore("maintenance")
+	if err != nil {
+		return
+	}
+
+	result, pruned, err := summaryCache.PruneDaily(state, pruneOptions(cfg))
+	if err != nil {
+		reporter.Warn("Cache pruning failed: %v", err)
+	} else if pruned && len(result.Entries) > 0 {
+		reporter.Step("Pruned cache files: %d", len(result.Entries))
+		for _, entry := range result.Entries {
+			reporter.Detail("%s: %s", entry.Name, entry.Reason)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"daily/internal/config"
+)
+
+func TestNewSummaryCache(t *testing.T) {
+	tests := []struct {
+		name       string
+		encryption string
+		passphrase string
+		hasError   bool
+	}{
+		{"plain cache", "", "", false},
+		{"encrypted cache", config.CacheEncryptionAge, "secret", false},
+		{"encrypted cache without passphrase", config.CacheEncryptionAge, "", true},
+		{"unknown encryption", "rot13", "secret", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(cachePassphraseEnv, tt.passphrase)
+
+			cfg := config.DefaultConfig()
+			cfg.Cache.Encryption = tt.encryption
+
+			summaryCache, err := newSummaryCache(cfg)
+			if tt.hasError && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.hasError && (err != nil || summaryCache == nil) {
+				t.Errorf("Expected cache, got error: %v", err)
+			}
+		})
+	}
+}
@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"cmp"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
 	"daily/internal/config"
+	"daily/internal/translate"
 )
 
 func ConfigCmd() *cobra.Command {
@@ -15,12 +20,65 @@ func ConfigCmd() *cobra.Command {
 		Long:  "View and manage configuration settings for daily CLI providers.",
 	}
 
+	cmd.AddCommand(configInitCmd())
 	cmd.AddCommand(configShowCmd())
 	cmd.AddCommand(configPathCmd())
+	cmd.AddCommand(configSetCmd())
 
 	return cmd
 }
 
+func configInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Create the configuration file",
+		Long:  "Create the configuration file, with every provider disabled, when it doesn't exist yet, and ask once whether to enable the anonymous usage telemetry.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.GetConfigPath()
+			if err != nil {
+				return fmt.Errorf("failed to get config path: %w", err)
+			}
+
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				if err := config.DefaultConfig().Save(); err != nil {
+					return fmt.Errorf("failed to create config: %w", err)
+				}
+				fmt.Printf("✅ Created %s\n", path)
+			} else {
+				fmt.Printf("Configuration file: %s\n", path)
+			}
+
+			settings, err := config.LoadTelemetry()
+			if err != nil {
+				return err
+			}
+			if settings.Telemetry.Prompted {
+				return nil
+			}
+			// Without a terminal to answer, telemetry stays disabled and the question is asked next time
+			if !isatty.IsTerminal(os.Stdin.Fd()) {
+				fmt.Println("Telemetry is disabled, see 'daily telemetry --help' to enable it")
+				return nil
+			}
+
+			enabled, err := promptTelemetry(os.Stdin, os.Stdout)
+			if err != nil {
+				return err
+			}
+			if err := setTelemetryEnabled(enabled); err != nil {
+				return err
+			}
+			if enabled {
+				fmt.Println("✅ Telemetry enabled, thank you! See what is sent with 'daily telemetry show'")
+			} else {
+				fmt.Println("Telemetry stays disabled")
+			}
+			return nil
+		},
+	}
+}
+
 func configShowCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "show",
@@ -37,12 +95,24 @@ func configShowCmd() *cobra.Command {
 			fmt.Printf("\n  Enabled: %t", cfg.GitHub.Enabled)
 			fmt.Printf("\n  Username: %s", cfg.GitHub.Username)
 			fmt.Printf("\n  Token: %s", maskToken(cfg.GitHub.Token))
+			if cfg.GitHub.TokenCmd != "" {
+				fmt.Printf("\n  Token Command: %s", cfg.GitHub.TokenCmd)
+			}
 
 			fmt.Printf("\n\nJIRA:")
 			fmt.Printf("\n  Enabled: %t", cfg.JIRA.Enabled)
 			fmt.Printf("\n  URL: %s", cfg.JIRA.URL)
 			fmt.Printf("\n  Email: %s", cfg.JIRA.Email)
 			fmt.Printf("\n  Token: %s", maskToken(cfg.JIRA.Token))
+			if cfg.JIRA.TokenCmd != "" {
+				fmt.Printf("\n  Token Command: %s", cfg.JIRA.TokenCmd)
+			}
+			if cfg.JIRA.AuthType != "" {
+				fmt.Printf("\n  Auth Type: %s", cfg.JIRA.AuthType)
+			}
+			if cfg.JIRA.ServerMode {
+				fmt.Printf("\n  Server Mode: %t", cfg.JIRA.ServerMode)
+			}
 
 			fmt.Printf("\n\nObsidian:")
 			fmt.Printf("\n  Enabled: %t", cfg.Obsidian.Enabled)
@@ -53,6 +123,73 @@ func configShowCmd() *cobra.Command {
 			fmt.Printf("\n  URL: %s", cfg.Confluence.URL)
 			fmt.Printf("\n  Email: %s", cfg.Confluence.Email)
 			fmt.Printf("\n  Token: %s", maskToken(cfg.Confluence.Token))
+			if cfg.Confluence.TokenCmd != "" {
+				fmt.Printf("\n  Token Command: %s", cfg.Confluence.TokenCmd)
+			}
+			if cfg.Confluence.AuthType != "" {
+				fmt.Printf("\n  Auth Type: %s", cfg.Confluence.AuthType)
+			}
+			if cfg.Confluence.ServerMode {
+				fmt.Printf("\n  Server Mode: %t", cfg.Confluence.ServerMode)
+			}
+			if cfg.Confluence.ContextPath != "" {
+				fmt.Printf("\n  Context Path: %s", cfg.Confluence.ContextPath)
+			}
+
+			fmt.Printf("\n\nGitLab:")
+			fmt.Printf("\n  Enabled: %t", cfg.GitLab.Enabled)
+			fmt.Printf("\n  URL: %s", cmp.Or(cfg.GitLab.URL, "https://gitlab.com"))
+			fmt.Printf("\n  Token: %s", maskToken(cfg.GitLab.Token))
+			if cfg.GitLab.TokenCmd != "" {
+				fmt.Printf("\n  Token Command: %s", cfg.GitLab.TokenCmd)
+			}
+			if len(cfg.GitLab.Groups) > 0 {
+				fmt.Printf("\n  Groups: %s", strings.Join(cfg.GitLab.Groups, ", "))
+			}
+
+			fmt.Printf("\n\nGoogle Calendar:")
+			fmt.Printf("\n  Enabled: %t", cfg.Calendar.Enabled)
+			if cfg.Calendar.ServiceAccountKey != "" {
+				fmt.Printf("\n  Service Account Key: %s", cfg.Calendar.ServiceAccountKey)
+			} else {
+				fmt.Printf("\n  Client ID: %s", cfg.Calendar.ClientID)
+				fmt.Printf("\n  Client Secret: %s", maskToken(cfg.Calendar.ClientSecret))
+			}
+			if len(cfg.Calendar.Calendars) > 0 {
+				fmt.Printf("\n  Calendars: %s", strings.Join(cfg.Calendar.Calendars, ", "))
+			}
+
+			fmt.Printf("\n\nSlack:")
+			fmt.Printf("\n  Enabled: %t", cfg.Slack.Enabled)
+			fmt.Printf("\n  Token: %s", maskToken(cfg.Slack.Token))
+			if cfg.Slack.TokenCmd != "" {
+				fmt.Printf("\n  Token Command: %s", cfg.Slack.TokenCmd)
+			}
+			if len(cfg.Slack.Channels) > 0 {
+				fmt.Printf("\n  Channels: %s", strings.Join(cfg.Slack.Channels, ", "))
+			}
+			if len(cfg.Slack.ExcludeChannels) > 0 {
+				fmt.Printf("\n  Excluded Channels: %s", strings.Join(cfg.Slack.ExcludeChannels, ", "))
+			}
+
+			fmt.Printf("\n\nTranslate:")
+			fmt.Printf("\n  Target: %s", cfg.Translate.Target)
+			fmt.Printf("\n  Backend: %s", cmp.Or(cfg.Translate.Backend, translate.BackendDictionary))
+			if cfg.Translate.Backend == translate.BackendDeepL {
+				fmt.Printf("\n  Token: %s", maskToken(cfg.Translate.Token))
+				if cfg.Translate.TokenCmd != "" {
+					fmt.Printf("\n  Token Command: %s", cfg.Translate.TokenCmd)
+				}
+				if cfg.Translate.URL != "" {
+					fmt.Printf("\n  URL: %s", cfg.Translate.URL)
+				}
+			}
+
+			fmt.Printf("\n\nSaved Queries:")
+			fmt.Printf("\n  Enabled: %t", cfg.SavedQueries.Enabled)
+			for _, query := range cfg.SavedQueries.Queries {
+				fmt.Printf("\n  %s: %s", query.Name, query.URL)
+			}
 			fmt.Println()
 
 			return nil
@@ -77,6 +214,26 @@ func configPathCmd() *cobra.Command {
 	}
 }
 
+func configSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a configuration setting",
+		Long: `Change a setting of the configuration file, given by its JSON path, e.g. "daily config set github.username octocat". Values of settings other than text are JSON, e.g. true or ["acme/api"].
+
+Only your configuration file is written, never the files it includes: a setting of an included file is overridden in yours.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.Set(args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("failed to set %s: %w", args[0], err)
+			}
+
+			fmt.Printf("Set %s in %s\n", args[0], path)
+			return nil
+		},
+	}
+}
+
 func maskToken(token string) string {
 	if token == "" {
 		return "(not set)"
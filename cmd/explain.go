@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/config"
+	"daily/internal/output"
+	"daily/internal/provider/github"
+	"daily/internal/provider/gitlab"
+)
+
+func ExplainCmd() *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "explain <item-id>",
+		Short: "Explain why an item is in your todo or review list",
+		Long: "Fetch the todo items and review requests, then show why the given item is listed: the provider, " +
+			"the exact query that returned it, the team or file it came from, the configured filter and when it was fetched. " +
+			"The item is matched by ID (e.g. jira-PROJ-123), JIRA key or URL.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadWithTokens()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			todoItems, reviewItems := collectListedItems(context.Background(), cfg, since)
+			item, ok := findItem(todoItems, reviewItems, args[0])
+			if !ok {
+				return fmt.Errorf("no todo item or review request matches %q", args[0])
+			}
+
+			fmt.Print(output.NewFormatter().FormatExplanation(item))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&since, "since", "s", "", "Time range for JIRA and Confluence mentions (e.g., 1d, 2w, 1m). Default: 2w")
+
+	return cmd
+}
+
+// collectListedItems gathers the todo items, with mentions since the time range (default
+// 2w), and the review requests without their details
+func collectListedItems(ctx context.Context, cfg *config.Config, since string) (output.TodoItems, output.ReviewItems) {
+	todoItems := collectTodos(ctx, cfg, todoOptions{since: since})
+
+	var sources []reviewSource
+	if cfg.GitHub.Enabled {
+		githubProvider := github.NewProvider(cfg.GitHub)
+		if githubProvider.IsConfigured() {
+			sources = append(sources, reviewSource{source: githubProvider, staleAfterDays: cfg.GitHub.StaleAfterDays})
+		}
+	}
+	if cfg.GitLab.Enabled {
+		gitlabProvider := gitlab.NewProvider(cfg.GitLab)
+		if gitlabProvider.IsConfigured() {
+			sources = append(sources, reviewSource{source: gitlabProvider})
+		}
+	}
+	return todoItems, collectReviews(ctx, sources, reviewOptions{skipDetails: true})
+}
+
+// itemSections returns the lists of todo items followed by the review requests
+func itemSections(todoItems output.TodoItems, reviewItems output.ReviewItems) [][]output.TodoItem {
+	var sections [][]output.TodoItem
+	for _, section := range todoItems.Sections() {
+		sections = append(sections, *section.Items)
+	}
+	reviews := make([]output.TodoItem, len(reviewItems))
+	for i, review := range reviewItems {
+		reviews[i] = review.TodoItem
+	}
+	return append(sections, reviews)
+}
+
+// findItem returns the todo item or review request matching ref by ID, JIRA key or URL,
+// ignoring case. Subtasks rolled up under a ticket are matched too.
+func findItem(todoItems output.TodoItems, reviewItems output.ReviewItems, ref string) (output.TodoItem, bool) {
+	matches := func(item output.TodoItem) bool {
+		return strings.EqualFold(item.ID, ref) ||
+			(item.Key != "" && strings.EqualFold(item.Key, ref)) ||
+			(item.URL != "" && item.URL == ref)
+	}
+
+	var search func(items []output.TodoItem) (output.TodoItem, bool)
+	search = func(items []output.TodoItem) (output.TodoItem, bool) {
+		for _, item := range items {
+			if matches(item) {
+				return item, true
+			}
+			if subtask, ok := search(item.Subtasks); ok {
+				return subtask, true
+			}
+		}
+		return output.TodoItem{}, false
+	}
+
+	for _, items := range itemSections(todoItems, reviewItems) {
+		if item, ok := search(items); ok {
+			return item, true
+		}
+	}
+	return output.TodoItem{}, false
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"daily/internal/output"
+)
+
+func TestFindItem(t *testing.T) {
+	todoItems := output.TodoItems{
+		JIRA: output.JIRATodos{AssignedTickets: []output.TodoItem{
+			{ID: "jira-PROJ-1", Key: "PROJ-1", URL: "https://jira.example.com/browse/PROJ-1", Subtasks: []output.TodoItem{
+				{ID: "jira-PROJ-2", Key: "PROJ-2"},
+			}},
+		}},
+		Obsidian: output.ObsidianTodos{Tasks: []output.TodoItem{{ID: "obsidian-task-inbox.md:3"}}},
+	}
+	reviewItems := output.ReviewItems{{TodoItem: output.TodoItem{ID: "github-review-7", URL: "https://github.com/acme/api/pull/7"}}}
+
+	tests := []struct {
+		ref        string
+		expectedID string
+	}{
+		{ref: "jira-PROJ-1", expectedID: "jira-PROJ-1"},
+		{ref: "proj-1", expectedID: "jira-PROJ-1"},
+		{ref: "https://jira.example.com/browse/PROJ-1", expectedID: "jira-PROJ-1"},
+		{ref: "PROJ-2", expectedID: "jira-PROJ-2"},
+		{ref: "obsidian-task-inbox.md:3", expectedID: "obsidian-task-inbox.md:3"},
+		{ref: "https://github.com/acme/api/pull/7", expectedID: "github-review-7"},
+		{ref: "PROJ-9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			item, ok := findItem(todoItems, reviewItems, tt.ref)
+			if tt.expectedID == "" {
+				if ok {
+					t.Errorf("Expected no match, got %s", item.ID)
+				}
+				return
+			}
+			if !ok || item.ID != tt.expectedID {
+				t.Errorf("Expected %s, got %s (found: %v)", tt.expectedID, item.ID, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/activity"
+	"daily/internal/config"
+)
+
+func GoalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "goal",
+		Short: "Manage daily activity goals",
+		Long:  "Set daily targets per activity type or tag (e.g. 5 commits a day) and track them in the summary.",
+	}
+
+	cmd.AddCommand(goalSetCmd())
+	cmd.AddCommand(goalStatusCmd())
+
+	return cmd
+}
+
+func goalSetCmd() *cobra.Command {
+	var weekday string
+
+	cmd := &cobra.Command{
+		Use:   "set <name> <target>",
+		Short: "Set a daily goal",
+		Long: "Set the daily target of a goal. The name is an activity type or tag, singular or plural " +
+			"(e.g. commits, prs, tasks, comments). A target of 0 removes the goal, or disables it on the --weekday.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid target: %s (must be a number)", args[1])
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			if err := cfg.Goals.Set(args[0], target, weekday); err != nil {
+				return err
+			}
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+
+			fmt.Println(describeGoal(args[0], target, weekday))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&weekday, "weekday", "", "Only apply the target on this weekday (e.g., friday)")
+
+	return cmd
+}
+
+// describeGoal returns the confirmation printed after setting a goal
+func describeGoal(name string, target int, weekday string) string {
+	when := "per day"
+	if weekday != "" {
+		day, _ := config.ParseWeekday(weekday)
+		when = "on " + day.String() + "s"
+	}
+
+	switch {
+	case target == 0 && weekday == "":
+		return fmt.Sprintf("✅ Goal removed: %s", name)
+	case target == 0:
+		return fmt.Sprintf("✅ Goal disabled %s: %s", when, name)
+	default:
+		return fmt.Sprintf("✅ Goal set: %s %d %s", name, target, when)
+	}
+}
+
+func goalStatusCmd() *cobra.Command {
+	var outputFormat string
+	var date string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show progress toward today's goals",
+		Long:  "Gather the day's activities from every enabled provider and show the progress toward each goal.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if outputFormat != "text" && outputFormat != "json" {
+				return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			targetDate, err := parseDate(date, cfg.DateOrder())
+			if err != nil {
+				return fmt.Errorf("invalid date format: %w", err)
+			}
+
+			var progress []activity.GoalProgress
+			if targets := cfg.Goals.TargetsFor(targetDate.Weekday()); len(targets) > 0 {
+				summary, err := newAggregator(cfg, nil).GetSummary(context.Background(), targetDate)
+				if err != nil {
+					return fmt.Errorf("failed to get activity summary: %w", err)
+				}
+				progress = activity.ComputeGoalProgress(targets, summary.Activities)
+			}
+
+			if outputFormat == "json" {
+				result, err := formatGoalStatusJSON(targetDate, progress)
+				if err != nil {
+					return err
+				}
+				fmt.Println(result)
+				return nil
+			}
+
+			fmt.Print(formatGoalStatus(targetDate, progress))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: 'text' or 'json'")
+	cmd.Flags().StringVarP(&date, "date", "d", "today", "Date to show progress for (yesterday, today, YYYY-MM-DD, or DD/MM/YYYY or MM/DD/YYYY depending on the locale)")
+
+	return cmd
+}
+
+// formatGoalStatus renders goal progress as text, one goal per line
+func formatGoalStatus(date time.Time, progress []activity.GoalProgress) string {
+	if len(progress) == 0 {
+		return "No goals set. Use 'daily goal set <name> <target>' to add one.\n"
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("🎯 Goals for %s\n\n", date.Format("January 2, 2006")))
+	for _, goal := range progress {
+		output.WriteString(fmt.Sprintf("  %s\n", goal))
+	}
+	return output.String()
+}
+
+// formatGoalStatusJSON renders goal progress as JSON
+func formatGoalStatusJSON(date time.Time, progress []activity.GoalProgress) (string, error) {
+	if progress == nil {
+		progress = []activity.GoalProgress{}
+	}
+
+	jsonBytes, err := json.MarshalIndent(struct {
+		Date  string                  `json:"date"`
+		Goals []activity.GoalProgress `json:"goals"`
+	}{date.Format("2006-01-02"), progress}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+)
+
+func TestFormatGoalStatus(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	if result := formatGoalStatus(date, nil); !strings.Contains(result, "No goals set") {
+		t.Errorf("Expected no goals message, got: %s", result)
+	}
+
+	result := formatGoalStatus(date, []activity.GoalProgress{
+		{Name: "commits", Count: 3, Target: 5},
+		{Name: "reviews", Count: 4, Target: 3, Met: true},
+	})
+	for _, want := range []string{"January 15, 2024", "commits 3/5", "reviews 4/3 ✅"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain '%s', got: %s", want, result)
+		}
+	}
+
+	jsonResult, err := formatGoalStatusJSON(date, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(jsonResult, `"goals": []`) {
+		t.Errorf("Expected empty goals array, got: %s", jsonResult)
+	}
+}
+
+func TestDescribeGoal(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   int
+		weekday  string
+		expected string
+	}{
+		{"commits", 5, "", "✅ Goal set: commits 5 per day"},
+		{"commits", 2, "fri", "✅ Goal set: commits 2 on Fridays"},
+		{"commits", 0, "", "✅ Goal removed: commits"},
+		{"commits", 0, "friday", "✅ Goal disabled on Fridays: commits"},
+	}
+
+	for _, tt := range tests {
+		if got := describeGoal(tt.name, tt.target, tt.weekday); got != tt.expected {
+			t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+		}
+	}
+}
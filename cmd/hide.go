@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/config"
+	"daily/internal/hide"
+	"daily/internal/output"
+)
+
+// hiddenStatePath returns the file of the hidden items, overridden in tests
+var hiddenStatePath = hide.DefaultStatePath
+
+// hiddenStore returns the store of the hidden items
+func hiddenStore() (hide.Store, error) {
+	path, err := hiddenStatePath()
+	if err != nil {
+		return hide.Store{}, err
+	}
+	return hide.NewStore(path), nil
+}
+
+// loadPrunedState returns the hidden items, without the ones expired after hide.expire_after.
+// The state is saved when entries expired.
+func loadPrunedState(store hide.Store, cfg *config.Config) (hide.State, error) {
+	state, err := store.Load()
+	if err != nil {
+		return state, err
+	}
+	before := cfg.Hide.ExpiredBefore(nowFunc())
+	if before.IsZero() {
+		return state, nil
+	}
+	if expired := state.Prune(before); len(expired) > 0 {
+		if err := store.Save(state); err != nil {
+			return state, err
+		}
+	}
+	return state, nil
+}
+
+func HideCmd() *cobra.Command {
+	var pattern string
+	var list bool
+	var clear bool
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "hide [item-id]",
+		Short: "Hide items from the todo and reviews lists",
+		Long: "Hide a todo item or review request by ID (e.g. jira-PROJ-123), JIRA key or URL. With --pattern, " +
+			"hide every current and future item whose title or tags contain the pattern, ignoring case. " +
+			"Use --list to show what is hidden, --clear to show everything again, and 'daily unhide' to undo a single hide.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			actions := 0
+			for _, set := range []bool{len(args) == 1, pattern != "", list, clear} {
+				if set {
+					actions++
+				}
+			}
+			if actions != 1 {
+				return fmt.Errorf("give exactly one of an item ID, --pattern, --list or --clear")
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			store, err := hiddenStore()
+			if err != nil {
+				return err
+			}
+			state, err := loadPrunedState(store, cfg)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case list:
+				fmt.Print(formatHiddenState(state, nowFunc()))
+				return nil
+			case clear:
+				if err := store.Save(hide.State{}); err != nil {
+					return err
+				}
+				fmt.Println("✅ Cleared every hidden item and rule")
+				return nil
+			case pattern != "":
+				added, err := state.AddRule(pattern, nowFunc())
+				if err != nil {
+					return err
+				}
+				if !added {
+					fmt.Printf("Already hiding items matching %q\n", pattern)
+					return nil
+				}
+				if err := store.Save(state); err != nil {
+					return err
+				}
+
+				todoItems, reviewItems := collectListedItems(context.Background(), cfg, since)
+				matched := matchingItems(todoItems, reviewItems, state.Rules[len(state.Rules)-1])
+				fmt.Printf("✅ Hiding items matching %q: %d currently listed\n", pattern, len(matched))
+				for _, item := range matched {
+					fmt.Printf("  - %s (%s)\n", item.Title, item.ID)
+				}
+				return nil
+			default:
+				if !state.HideID(args[0], nowFunc()) {
+					fmt.Printf("Already hidden: %s\n", args[0])
+					return nil
+				}
+				if err := store.Save(state); err != nil {
+					return err
+				}
+				fmt.Printf("✅ Hidden: %s\n", args[0])
+				return nil
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&pattern, "pattern", "p", "", "Hide every item whose title or tags contain this text (e.g., dependabot)")
+	cmd.Flags().BoolVar(&list, "list", false, "List the hidden items, rules and exceptions")
+	cmd.Flags().BoolVar(&clear, "clear", false, "Remove every hidden item, rule and exception")
+	cmd.Flags().StringVarP(&since, "since", "s", "", "Time range for JIRA and Confluence mentions (e.g., 1d, 2w, 1m). Default: 2w")
+
+	return cmd
+}
+
+func UnhideCmd() *cobra.Command {
+	var pattern string
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "unhide [item-id]",
+		Short: "Show a hidden item again",
+		Long: "Show an item hidden by ID, JIRA key or URL again. When a rule still matches the item, it is kept as " +
+			"an exception to the rule. With --pattern, remove a rule instead.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (len(args) == 1) == (pattern != "") {
+				return fmt.Errorf("give either an item ID or --pattern")
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			store, err := hiddenStore()
+			if err != nil {
+				return err
+			}
+			state, err := loadPrunedState(store, cfg)
+			if err != nil {
+				return err
+			}
+
+			if pattern != "" {
+				if !state.RemoveRule(pattern) {
+					return fmt.Errorf("no rule hides items matching %q", pattern)
+				}
+				if err := store.Save(state); err != nil {
+					return err
+				}
+				fmt.Printf("✅ No longer hiding items matching %q\n", pattern)
+				return nil
+			}
+
+			// Rules match on the title and tags, which are only known by fetching the item
+			item := hide.Item{ID: args[0]}
+			if len(state.Rules) > 0 {
+				todoItems, reviewItems := collectListedItems(context.Background(), cfg, since)
+				if found, ok := findItem(todoItems, reviewItems, args[0]); ok {
+					item = hiddenItem(found)
+				}
+			}
+
+			if !state.Unhide(item) {
+				return fmt.Errorf("%s is not hidden", args[0])
+			}
+			if err := store.Save(state); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Unhidden: %s\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&pattern, "pattern", "p", "", "Remove the rule hiding items matching this text")
+	cmd.Flags().StringVarP(&since, "since", "s", "", "Time range for JIRA and Confluence mentions (e.g., 1d, 2w, 1m). Default: 2w")
+
+	return cmd
+}
+
+// formatHiddenState lists the rules, hidden items and exceptions, with the age of the hidden
+// items and the title they last matched
+func formatHiddenState(state hide.State, now time.Time) string {
+	if state.Empty() {
+		return "Nothing is hidden. Use 'daily hide <item-id>' or 'daily hide --pattern <text>' to hide items.\n"
+	}
+
+	var out strings.Builder
+	if len(state.Rules) > 0 {
+		out.WriteString("🙈 Rules\n")
+		for _, rule := range state.Rules {
+			out.WriteString(fmt.Sprintf("  - %q (since %s)\n", rule.Pattern, rule.CreatedAt.Format("2006-01-02")))
+		}
+	}
+	if len(state.IDs) > 0 {
+		out.WriteString("🙈 Hidden items\n")
+		for _, entry := range state.IDs {
+			lastMatched := "not listed since"
+			if entry.LastMatched != "" {
+				lastMatched = fmt.Sprintf("last matched %q %s", entry.LastMatched, daysAgo(entry.LastMatchedAt, now))
+			}
+			out.WriteString(fmt.Sprintf("  - %s (hidden %s, %s)\n", entry.Ref, daysAgo(entry.HiddenAt, now), lastMatched))
+		}
+	}
+	if len(state.Exceptions) > 0 {
+		out.WriteString("👀 Shown despite a rule\n")
+		for _, id := range state.Exceptions {
+			out.WriteString(fmt.Sprintf("  - %s\n", id))
+		}
+	}
+	return out.String()
+}
+
+// daysAgo describes how many days ago t was, e.g. "today" or "3 days ago"
+func daysAgo(t, now time.Time) string {
+	switch days := int(now.Sub(t).Hours() / 24); days {
+	case 0:
+		return "today"
+	case 1:
+		return "1 day ago"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
+
+// hiddenItem returns what a todo item or review request is matched on
+func hiddenItem(item output.TodoItem) hide.Item {
+	return hide.Item{ID: item.ID, Key: item.Key, URL: item.URL, Title: item.Title, Tags: item.Tags}
+}
+
+// matchingItems returns the listed items matching a rule, subtasks included
+func matchingItems(todoItems output.TodoItems, reviewItems output.ReviewItems, rule hide.Rule) []output.TodoItem {
+	var matched []output.TodoItem
+	var search func(items []output.TodoItem)
+	search = func(items []output.TodoItem) {
+		for _, item := range items {
+			if rule.Matches(hiddenItem(item)) {
+				matched = append(matched, item)
+			}
+			search(item.Subtasks)
+		}
+	}
+	for _, items := range itemSections(todoItems, reviewItems) {
+		search(items)
+	}
+	return matched
+}
+
+// loadHiddenState returns the hidden items, without the expired ones. An unreadable file
+// hides nothing rather than failing the command.
+func loadHiddenState(cfg *config.Config) hide.State {
+	store, err := hiddenStore()
+	if err == nil {
+		var state hide.State
+		if state, err = loadPrunedState(store, cfg); err == nil {
+			return state
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Warning: hidden items are shown: %v\n", err)
+	return hide.State{}
+}
+
+// recordHiddenMatches records the listed items on the entries hiding them by ID, so that the
+// entries don't expire while their item is listed. The state is only saved when it changed.
+func recordHiddenMatches(state hide.State, todoItems output.TodoItems, reviewItems output.ReviewItems) {
+	now := nowFunc()
+	changed := false
+	var record func(items []output.TodoItem)
+	record = func(items []output.TodoItem) {
+		for _, item := range items {
+			if state.RecordMatch(hiddenItem(item), now) {
+				changed = true
+			}
+			record(item.Subtasks)
+		}
+	}
+	for _, items := range itemSections(todoItems, reviewItems) {
+		record(items)
+	}
+	if !changed {
+		return
+	}
+
+	store, err := hiddenStore()
+	if err == nil {
+		err = store.Save(state)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record the hidden items listed: %v\n", err)
+	}
+}
+
+// withoutHidden drops the hidden items and subtasks, keeping a nil list nil
+func withoutHidden(items []output.TodoItem, state hide.State) []output.TodoItem {
+	if items == nil {
+		return nil
+	}
+	kept := make([]output.TodoItem, 0, len(items))
+	for _, item := range items {
+		if state.Hidden(hiddenItem(item)) {
+			continue
+		}
+		item.Subtasks = withoutHidden(item.Subtasks, state)
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// filterHiddenTodos drops the hidden items of every todo list
+func filterHiddenTodos(todoItems output.TodoItems, state hide.State) output.TodoItems {
+	if state.Empty() {
+		return todoItems
+	}
+	for _, section := range todoItems.Sections() {
+		*section.Items = withoutHidden(*section.Items, state)
+	}
+	return todoItems
+}
+
+// filterHiddenReviews drops the hidden review requests
+func filterHiddenReviews(items output.ReviewItems, state hide.State) output.ReviewItems {
+	if state.Empty() {
+		return items
+	}
+	return slices.DeleteFunc(slices.Clone(items), func(item output.ReviewItem) bool {
+		return state.Hidden(hiddenItem(item.TodoItem))
+	})
+}
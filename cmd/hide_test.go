@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/config"
+	"daily/internal/hide"
+	"daily/internal/output"
+)
+
+func TestFilterHiddenTodos(t *testing.T) {
+	todoItems := output.TodoItems{
+		GitHub: output.GitHubTodos{OpenPRs: []output.TodoItem{
+			{ID: "github-pr-1", Title: "Bump lodash", Tags: []string{"dependabot"}},
+			{ID: "github-pr-2", Title: "Fix login"},
+		}},
+		JIRA: output.JIRATodos{
+			AssignedTickets: []output.TodoItem{
+				{ID: "jira-PROJ-1", Key: "PROJ-1", Subtasks: []output.TodoItem{
+					{ID: "jira-PROJ-2", Key: "PROJ-2"},
+					{ID: "jira-PROJ-3", Key: "PROJ-3"},
+				}},
+			},
+			Mentions: []output.TodoItem{},
+		},
+	}
+	state := hide.State{IDs: []hide.Entry{{Ref: "PROJ-2"}}, Rules: []hide.Rule{{Pattern: "dependabot"}}}
+
+	filtered := filterHiddenTodos(todoItems, state)
+	if len(filtered.GitHub.OpenPRs) != 1 || filtered.GitHub.OpenPRs[0].ID != "github-pr-2" {
+		t.Errorf("Expected the rule to hide the dependabot PR, got %+v", filtered.GitHub.OpenPRs)
+	}
+	subtasks := filtered.JIRA.AssignedTickets[0].Subtasks
+	if len(subtasks) != 1 || subtasks[0].ID != "jira-PROJ-3" {
+		t.Errorf("Expected the hidden subtask to be dropped, got %+v", subtasks)
+	}
+	if filtered.JIRA.Mentions == nil || filtered.JIRA.Reported != nil {
+		t.Error("Expected empty lists to stay empty and missing ones missing")
+	}
+	if len(todoItems.GitHub.OpenPRs) != 2 {
+		t.Error("Expected the unfiltered items to be left alone")
+	}
+}
+
+func TestFilterHiddenReviews(t *testing.T) {
+	items := output.ReviewItems{
+		{TodoItem: output.TodoItem{ID: "github-review-1", Title: "chore(deps): bump lodash", Tags: []string{"dependabot"}}},
+		{TodoItem: output.TodoItem{ID: "github-review-2", Title: "chore(deps): bump react", Tags: []string{"dependabot"}}},
+		{TodoItem: output.TodoItem{ID: "github-review-3", Title: "Add billing"}},
+	}
+	state := hide.State{Rules: []hide.Rule{{Pattern: "dependabot"}}, Exceptions: []string{"github-review-2"}}
+
+	filtered := filterHiddenReviews(items, state)
+	var ids []string
+	for _, item := range filtered {
+		ids = append(ids, item.TodoItem.ID)
+	}
+	if strings.Join(ids, ",") != "github-review-2,github-review-3" {
+		t.Errorf("Expected the exception and the unmatched review, got %v", ids)
+	}
+	if len(items) != 3 || items[0].TodoItem.ID != "github-review-1" {
+		t.Error("Expected the unfiltered reviews to be left alone")
+	}
+}
+
+func TestMatchingItems(t *testing.T) {
+	todoItems := output.TodoItems{Obsidian: output.ObsidianTodos{Tasks: []output.TodoItem{
+		{ID: "obsidian-task-inbox.md:1", Title: "Review Dependabot alerts"},
+		{ID: "obsidian-task-inbox.md:2", Title: "Water plants"},
+	}}}
+	reviewItems := output.ReviewItems{{TodoItem: output.TodoItem{ID: "github-review-1", Tags: []string{"dependabot"}}}}
+
+	matched := matchingItems(todoItems, reviewItems, hide.Rule{Pattern: "dependabot"})
+	if len(matched) != 2 || matched[0].ID != "obsidian-task-inbox.md:1" || matched[1].ID != "github-review-1" {
+		t.Errorf("Expected the task and the review, got %+v", matched)
+	}
+}
+
+func TestFormatHiddenState(t *testing.T) {
+	now := time.Date(2024, 6, 20, 9, 0, 0, 0, time.UTC)
+	if result := formatHiddenState(hide.State{}, now); !strings.Contains(result, "Nothing is hidden") {
+		t.Errorf("Expected the empty message, got %q", result)
+	}
+
+	result := formatHiddenState(hide.State{
+		IDs: []hide.Entry{
+			{Ref: "jira-PROJ-1", HiddenAt: now.AddDate(0, 0, -12), LastMatched: "Fix login", LastMatchedAt: now.AddDate(0, 0, -1)},
+			{Ref: "github-pr-2", HiddenAt: now},
+		},
+		Rules:      []hide.Rule{{Pattern: "dependabot"}},
+		Exceptions: []string{"github-pr-9"},
+	}, now)
+	for _, expected := range []string{
+		`- "dependabot"`,
+		`- jira-PROJ-1 (hidden 12 days ago, last matched "Fix login" 1 day ago)`,
+		"- github-pr-2 (hidden today, not listed since)",
+		"👀 Shown despite a rule\n  - github-pr-9",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, result)
+		}
+	}
+}
+
+func TestHiddenState_ExpiryAndMatches(t *testing.T) {
+	now := time.Date(2024, 6, 20, 9, 0, 0, 0, time.UTC)
+	originalNow, originalPath := nowFunc, hiddenStatePath
+	defer func() { nowFunc, hiddenStatePath = originalNow, originalPath }()
+	nowFunc = func() time.Time { return now }
+	path := filepath.Join(t.TempDir(), "hidden.json")
+	hiddenStatePath = func() (string, error) { return path, nil }
+
+	store := hide.NewStore(path)
+	err := store.Save(hide.State{IDs: []hide.Entry{
+		{Ref: "github-pr-1", HiddenAt: now.AddDate(0, -6, 0)},                                       // Closed long ago
+		{Ref: "github-pr-2", HiddenAt: now.AddDate(0, -6, 0), LastMatchedAt: now.AddDate(0, 0, -1)}, // Still listed
+		{Ref: "jira-PROJ-3", HiddenAt: now.AddDate(0, 0, -3)},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to save hidden items: %v", err)
+	}
+
+	// Nothing expires by default
+	state := loadHiddenState(&config.Config{})
+	if len(state.IDs) != 3 {
+		t.Fatalf("Expected every entry without expiry, got %+v", state.IDs)
+	}
+
+	cfg := &config.Config{Hide: config.HideConfig{ExpireAfter: "90d"}}
+	state = loadHiddenState(cfg)
+	if len(state.IDs) != 2 || state.IDs[0].Ref != "github-pr-2" {
+		t.Fatalf("Expected the entry not listed for 6 months to expire, got %+v", state.IDs)
+	}
+	if saved, _ := store.Load(); len(saved.IDs) != 2 {
+		t.Errorf("Expected the pruned state to be saved, got %+v", saved.IDs)
+	}
+
+	// The PR closed long ago is listed again once reopened
+	reviewItems := output.ReviewItems{
+		{TodoItem: output.TodoItem{ID: "github-pr-1", Title: "Reopened"}},
+		{TodoItem: output.TodoItem{ID: "github-pr-2", Title: "Bump lodash"}},
+	}
+	recordHiddenMatches(state, output.TodoItems{}, reviewItems)
+	if filtered := filterHiddenReviews(reviewItems, state); len(filtered) != 1 || filtered[0].TodoItem.ID != "github-pr-1" {
+		t.Errorf("Expected the reopened PR to be listed, got %+v", filtered)
+	}
+	saved, _ := store.Load()
+	if saved.IDs[0].LastMatched != "Bump lodash" || !saved.IDs[0].LastMatchedAt.Equal(time.Date(2024, 6, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the match to be saved, got %+v", saved.IDs[0])
+	}
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/activity"
+	"daily/internal/config"
+	"daily/internal/metrics"
+	"daily/internal/output"
+	"daily/internal/provider"
+	"daily/internal/provider/confluence"
+	"daily/internal/provider/github"
+	"daily/internal/provider/jira"
+	"daily/internal/verboselog"
+)
+
+func MentionsCmd() *cobra.Command {
+	var verbose bool
+	var outputFormat string
+	var outFile string
+	var progress string
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "mentions",
+		Short: "List recent mentions across providers",
+		Long:  "Collect mentions of you from every configured provider that supports them (GitHub, JIRA, Confluence) into a single list, newest first.",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// Validate output format
+			if outputFormat != "text" && outputFormat != "json" && outputFormat != "tui" {
+				return fmt.Errorf("invalid output format: %s (must be 'text', 'json', or 'tui')", outputFormat)
+			}
+			if err := validateOutFile(outputFormat, outFile); err != nil {
+				return err
+			}
+			if err := validateProgress(outputFormat, progress); err != nil {
+				return err
+			}
+			if _, err := parseSinceDuration(since); err != nil {
+				return fmt.Errorf("invalid since value: %w", err)
+			}
+
+			recorder := newRunRecorder(progress)
+			defer func() { recorder.RunFinished(err) }()
+
+			if outputFormat == "text" {
+				fmt.Println("Gathering mentions...")
+			}
+
+			// Load configuration
+			cfg, err := config.LoadWithTokens()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			ctx := metrics.WithRecorder(context.Background(), recorder)
+			// Verbose output is only shown with text output, the other formats being parsed or full screen
+			showVerbose := verbose && outputFormat == "text"
+			var reporter *verboselog.Reporter
+			if showVerbose {
+				reporter = verboselog.Stderr()
+			}
+			autoPruneCache(cfg, reporter)
+
+			var sources []provider.MentionSource
+			if cfg.GitHub.Enabled {
+				sources = append(sources, github.NewProvider(cfg.GitHub))
+			} else {
+				reporter.ProviderSkipped("github", "disabled")
+			}
+			if cfg.JIRA.Enabled {
+				sources = append(sources, jira.NewProvider(cfg.JIRA))
+			} else {
+				reporter.ProviderSkipped("jira", "disabled")
+			}
+			if cfg.Confluence.Enabled {
+				sources = append(sources, confluence.NewProvider(cfg.Confluence))
+			} else {
+				reporter.ProviderSkipped("confluence", "disabled")
+			}
+
+			mentions := collectMentions(ctx, sources, since, reporter)
+
+			// Format and display results
+			formatter := output.NewFormatter()
+			switch outputFormat {
+			case "json":
+				formatter.SetMeta(recorder.Report())
+				return writeOutput(outFile, formatter.FormatMentionsJSON(mentions))
+			case "tui":
+				return formatter.FormatMentionsTUI(mentions)
+			case "text":
+				if err := writeOutput(outFile, formatter.FormatMentions(mentions)); err != nil {
+					return err
+				}
+				if showVerbose {
+					printRunReport(recorder)
+				}
+				return nil
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output for debugging (text mode only)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "tui", "Output format: 'tui', 'text', or 'json'")
+	cmd.Flags().StringVar(&outFile, "out-file", "", "Write output to this file instead of stdout ('-' for stdout, not available with tui)")
+	cmd.Flags().StringVar(&progress, "progress", "", "Stream progress events to stderr: 'json' for one JSON object per line")
+	cmd.Flags().StringVarP(&since, "since", "s", "3d", "Time range to look back for mentions (e.g., 1d, 2w, 1m)")
+
+	return cmd
+}
+
+// collectMentions gathers mentions from every configured source as activities, newest first,
+// reporting each source to reporter. A failing source is skipped so the others are still shown.
+func collectMentions(ctx context.Context, sources []provider.MentionSource, since string, reporter *verboselog.Reporter) []activity.Activity {
+	var mentions []activity.Activity
+	recorder := metrics.FromContext(ctx)
+
+	for _, source := range sources {
+		if !source.IsConfigured() {
+			reporter.ProviderSkipped(source.Name(), "not configured")
+			continue
+		}
+
+		reporter.ProviderEnabled(source.Name())
+		start := time.Now()
+		recorder.ProviderStarted(source.Name())
+		items, err := source.GetMentions(ctx, since)
+		recorder.ProviderFinished(source.Name(), len(items), err)
+		reporter.ProviderResult(source.Name(), len(items), time.Since(start), err)
+		if err != nil {
+			continue
+		}
+
+		for _, item := range items {
+			mentions = append(mentions, activity.Activity{
+				ID:          item.ID,
+				Type:        activity.ActivityTypeMention,
+				Title:       item.Title,
+				Description: item.Description,
+				URL:         item.URL,
+				Platform:    source.Name(),
+				Timestamp:   item.UpdatedAt,
+				Tags:        item.Tags,
+				Details:     item.Details,
+			})
+		}
+	}
+
+	sort.SliceStable(mentions, func(i, j int) bool {
+		return mentions[i].Timestamp.After(mentions[j].Timestamp)
+	})
+
+	return mentions
+}
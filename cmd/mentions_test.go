@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+type fakeMentionSource struct {
+	name       string
+	configured bool
+	items      []provider.TodoItem
+	err        error
+}
+
+func (f *fakeMentionSource) Name() string       { return f.name }
+func (f *fakeMentionSource) IsConfigured() bool { return f.configured }
+func (f *fakeMentionSource) GetMentions(ctx context.Context, since string) ([]provider.TodoItem, error) {
+	return f.items, f.err
+}
+
+func TestCollectMentions(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	sources := []provider.MentionSource{
+		&fakeMentionSource{
+			name:       "github",
+			configured: true,
+			items: []provider.TodoItem{
+				{ID: "gh-1", Title: "Older", UpdatedAt: base},
+				{ID: "gh-2", Title: "Newest", UpdatedAt: base.Add(2 * time.Hour)},
+			},
+		},
+		&fakeMentionSource{
+			name:       "confluence",
+			configured: true,
+			items:      []provider.TodoItem{{ID: "cf-1", Title: "Middle", UpdatedAt: base.Add(time.Hour), Details: activity.NewDetails(activity.DetailSpace, "ENG")}},
+		},
+		&fakeMentionSource{name: "broken", configured: true, err: fmt.Errorf("boom")},
+		&fakeMentionSource{name: "unconfigured", items: []provider.TodoItem{{ID: "skip"}}},
+	}
+
+	mentions := collectMentions(context.Background(), sources, "3d", nil)
+
+	if len(mentions) != 3 {
+		t.Fatalf("Expected 3 mentions, got %d", len(mentions))
+	}
+
+	expectedIDs := []string{"gh-2", "cf-1", "gh-1"}
+	for i, id := range expectedIDs {
+		if mentions[i].ID != id {
+			t.Errorf("Expected mention %d to be '%s', got '%s'", i, id, mentions[i].ID)
+		}
+		if mentions[i].Type != activity.ActivityTypeMention {
+			t.Errorf("Expected type '%s', got '%s'", activity.ActivityTypeMention, mentions[i].Type)
+		}
+	}
+
+	if mentions[1].Platform != "confluence" {
+		t.Errorf("Expected platform 'confluence', got '%s'", mentions[1].Platform)
+	}
+	if mentions[1].Details[activity.DetailSpace] != "ENG" {
+		t.Errorf("Expected the details of the mention, got %v", mentions[1].Details)
+	}
+}
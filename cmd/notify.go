@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/config"
+	"daily/internal/datetime"
+	"daily/internal/notify"
+	"daily/internal/provider"
+	"daily/internal/provider/github"
+	"daily/internal/provider/gitlab"
+	"daily/internal/provider/jira"
+	"daily/internal/verboselog"
+)
+
+func NotifyCmd() *cobra.Command {
+	var verbose bool
+	var flush bool
+	var dryRun bool
+	var overrideQuiet bool
+
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Send a desktop notification digest of new review requests and tickets",
+		Long: `Detect the review requests and JIRA tickets assigned to you since the last run and batch them into a single desktop notification, sent once the notify window has passed (15 minutes by default).
+
+Meant to be run periodically, e.g. every 5 minutes from cron. The first run only records the existing items.
+
+During the quiet hours of the notify config, changes are held and sent in the first digest after they end. Use --override-quiet to be notified anyway, e.g. during an on-call week.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadWithTokens()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			statePath, err := notify.DefaultStatePath()
+			if err != nil {
+				return err
+			}
+			store := notify.NewStore(statePath)
+			state, err := store.Load()
+			if err != nil {
+				return err
+			}
+
+			var notifier notify.Notifier = notify.NewDesktopNotifier()
+			if dryRun {
+				notifier = notify.NewWriterNotifier(os.Stdout)
+			}
+
+			opts := notifyOptions{
+				window:        cfg.Notify.WindowDuration(),
+				maxBatch:      cfg.Notify.MaxBatchSize(),
+				openCommand:   cfg.Notify.OpenCommand,
+				quietSources:  cfg.Notify.QuietHours.Platforms,
+				overrideQuiet: overrideQuiet,
+				flush:         flush,
+			}
+			if cfg.Notify.QuietHours.IsSet() {
+				quiet, err := cfg.Notify.QuietHours.QuietHours()
+				if err != nil {
+					return fmt.Errorf("invalid notify.quiet_hours: %w", err)
+				}
+				opts.quietHours = &quiet
+			}
+
+			if verbose {
+				opts.reporter = verboselog.Stderr()
+			}
+			autoPruneCache(cfg, opts.reporter)
+
+			ctx := context.Background()
+			err = runNotify(ctx, notifySources(cfg, opts.reporter), &state, notifier, opts)
+
+			// A dry run leaves the state as it was, so the next run notifies the same changes
+			if dryRun {
+				return err
+			}
+
+			// Save what was detected even when the notification failed, it's retried next run
+			if saveErr := store.Save(state); saveErr != nil {
+				return saveErr
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output for debugging")
+	cmd.Flags().BoolVar(&flush, "flush", false, "Send the pending changes now instead of waiting for the end of the window")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the notification instead of showing it, without recording the changes")
+	cmd.Flags().BoolVar(&overrideQuiet, "override-quiet", false, "Notify during quiet hours, sending the changes held so far")
+
+	return cmd
+}
+
+// changeSource lists the current items of one kind of change
+type changeSource struct {
+	name string
+	kind string
+	list func(ctx context.Context) ([]notify.Change, error)
+}
+
+// notifyOptions controls when and how the digest is sent
+type notifyOptions struct {
+	window      time.Duration
+	maxBatch    int
+	openCommand string // Run when the notification is clicked
+	// quietHours hold changes back until they end, nil when not configured
+	quietHours    *datetime.QuietHours
+	quietSources  []string // Sources whose changes are held, all of them when empty
+	overrideQuiet bool     // Ignore the quiet hours and send the held changes
+	flush         bool     // Send the pending changes whether the window has passed or not
+	reporter      *verboselog.Reporter
+}
+
+// quietUntil reports whether the change is held by the quiet hours at now, and when they end
+func (o notifyOptions) quietUntil(change notify.Change, now time.Time) (time.Time, bool) {
+	if o.quietHours == nil || o.overrideQuiet {
+		return time.Time{}, false
+	}
+	if len(o.quietSources) > 0 && !slices.Contains(o.quietSources, change.Source) {
+		return time.Time{}, false
+	}
+	return o.quietHours.Until(now)
+}
+
+// notifySources returns the change sources of the enabled and configured providers, reporting
+// the ones left out to reporter
+func notifySources(cfg *config.Config, reporter *verboselog.Reporter) []changeSource {
+	var sources []changeSource
+
+	if cfg.GitHub.Enabled {
+		githubProvider := github.NewProvider(cfg.GitHub)
+		if githubProvider.IsConfigured() {
+			sources = append(sources, reviewChangeSource(githubProvider))
+		} else {
+			reporter.ProviderSkipped(githubProvider.Name(), "not configured")
+		}
+	}
+
+	if cfg.GitLab.Enabled {
+		gitlabProvider := gitlab.NewProvider(cfg.GitLab)
+		if gitlabProvider.IsConfigured() {
+			sources = append(sources, reviewChangeSource(gitlabProvider))
+		} else {
+			reporter.ProviderSkipped(gitlabProvider.Name(), "not configured")
+		}
+	}
+
+	if cfg.JIRA.Enabled {
+		jiraProvider := jira.NewProvider(cfg.JIRA)
+		if jiraProvider.IsConfigured() {
+			sources = append(sources, jiraAssignedChangeSource(jiraProvider))
+		} else {
+			reporter.ProviderSkipped(jiraProvider.Name(), "not configured")
+		}
+	}
+
+	return sources
+}
+
+// reviewChangeSource lists the review requests of source, without their details
+func reviewChangeSource(source provider.ReviewSource) changeSource {
+	return changeSource{
+		name: source.Name(),
+		kind: notify.KindReviewRequest,
+		list: func(ctx context.Context) ([]notify.Change, error) {
+			items, err := source.GetReviewRequests(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			changes := make([]notify.Change, len(items))
+			for i, item := range items {
+				// Item IDs only hold the PR number, which repeats across repositories
+				id := item.ID
+				if item.Repository != "" {
+					id = fmt.Sprintf("%s-%s#%d", item.Platform, item.Repository, item.Number)
+				}
+				changes[i] = notify.Change{ID: id, Kind: notify.KindReviewRequest, Title: item.Title, URL: item.URL}
+			}
+			return changes, nil
+		},
+	}
+}
+
+// jiraAssignedChangeSource lists the open tickets assigned to the user
+func jiraAssignedChangeSource(jiraProvider *jira.Provider) changeSource {
+	return changeSource{
+		name: jiraProvider.Name(),
+		kind: notify.KindJIRAAssigned,
+		list: func(ctx context.Context) ([]notify.Change, error) {
+			tickets, err := jiraProvider.GetAssignedTickets(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			changes := make([]notify.Change, len(tickets))
+			for i, ticket := range tickets {
+				changes[i] = notify.Change{ID: ticket.ID, Kind: notify.KindJIRAAssigned, Title: ticket.Title, URL: ticket.URL}
+			}
+			return changes, nil
+		},
+	}
+}
+
+// runNotify adds the changes detected since the last run to the digest and sends it when due.
+// Sources that fail keep their previously seen items, so their items aren't reported again
+// once they recover. The batch stays pending when the notification can't be sent.
+//
+// During quiet hours, changes are held in the state instead, and the first run after they end
+// sends them right away.
+func runNotify(ctx context.Context, sources []changeSource, state *notify.State, notifier notify.Notifier, opts notifyOptions) error {
+	now := nowFunc()
+	digest := notify.NewDigest(opts.window, opts.maxBatch, state.Digest)
+
+	// add batches the change, or holds it until the end of the quiet hours
+	add := func(change notify.Change) bool {
+		if until, quiet := opts.quietUntil(change, now); quiet {
+			state.Hold(change, until)
+			return false
+		}
+		digest.Add(change, now)
+		return true
+	}
+
+	// Changes batched before the quiet hours started are held with the ones detected during them
+	for _, change := range digest.Remove(func(change notify.Change) bool {
+		_, quiet := opts.quietUntil(change, now)
+		return quiet
+	}) {
+		add(change)
+	}
+
+	released := 0
+	for _, change := range state.Release(now, opts.overrideQuiet) {
+		if add(change) {
+			released++
+		}
+	}
+
+	for _, src := range sources {
+		opts.reporter.ProviderEnabled(src.name)
+		start := time.Now()
+		current, err := src.list(ctx)
+		opts.reporter.ProviderResult(src.name, len(current), time.Since(start), err)
+		if err != nil {
+			continue
+		}
+
+		changes := state.Detect(src.kind, current)
+		for _, change := range changes {
+			change.Source = src.name
+			add(change)
+		}
+		opts.reporter.Detail("%d new", len(changes))
+	}
+
+	defer func() { state.Digest = digest.State() }()
+
+	if len(state.Held) > 0 {
+		opts.reporter.Step("%d changes held until %s", len(state.Held), state.Held[len(state.Held)-1].Until.Local().Format("Mon 15:04"))
+	}
+
+	if digest.Pending() == 0 || (!opts.flush && released == 0 && !digest.Due(now)) {
+		if digest.Pending() > 0 {
+			opts.reporter.Step("%d changes pending until %s", digest.Pending(), digest.Deadline().Local().Format("15:04"))
+		}
+		return nil
+	}
+
+	notification := notify.NewNotification(digest.State().Pending, opts.openCommand)
+	if err := notifier.Notify(notification); err != nil {
+		return err
+	}
+
+	changes := digest.Flush()
+	opts.reporter.Step("Sent digest of %d changes: %s", len(changes), notify.Summarize(changes))
+	return nil
+}
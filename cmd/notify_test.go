@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/datetime"
+	"daily/internal/notify"
+	"daily/internal/provider"
+	"daily/internal/verboselog"
+)
+
+// recordingNotifier keeps the notifications sent and fails when err is set
+type recordingNotifier struct {
+	sent []notify.Notification
+	err  error
+}
+
+func (r *recordingNotifier) Notify(n notify.Notification) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+// fixedChangeSource returns a change source listing the items, or failing with err
+func fixedChangeSource(kind string, items *[]notify.Change, err *error) changeSource {
+	return changeSource{
+		name: kind,
+		kind: kind,
+		list: func(ctx context.Context) ([]notify.Change, error) {
+			changes := make([]notify.Change, len(*items))
+			for i, change := range *items {
+				change.Kind = kind
+				changes[i] = change
+			}
+			return changes, *err
+		},
+	}
+}
+
+func TestRunNotify_Digest(t *testing.T) {
+	start := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	now := start
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	nowFunc = func() time.Time { return now }
+
+	reviews := []notify.Change{{ID: "pr-1", Title: "Fix login"}}
+	tickets := []notify.Change{{ID: "jira-PROJ-1"}}
+	var reviewsErr, ticketsErr error
+	sources := []changeSource{
+		fixedChangeSource(notify.KindReviewRequest, &reviews, &reviewsErr),
+		fixedChangeSource(notify.KindJIRAAssigned, &tickets, &ticketsErr),
+	}
+
+	var state notify.State
+	notifier := &recordingNotifier{}
+	opts := notifyOptions{window: 15 * time.Minute, maxBatch: 10, openCommand: "kitty daily todo"}
+
+	// The first run records the existing items without notifying
+	if err := runNotify(context.Background(), sources, &state, notifier, opts); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notifier.sent) != 0 || len(state.Digest.Pending) != 0 {
+		t.Fatalf("Expected nothing on the first run, got %+v and %+v", notifier.sent, state.Digest)
+	}
+
+	// New items wait for the end of the window
+	now = start.Add(5 * time.Minute)
+	reviews = append(reviews, notify.Change{ID: "pr-2"}, notify.Change{ID: "pr-3"})
+	if err := runNotify(context.Background(), sources, &state, notifier, opts); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notifier.sent) != 0 || len(state.Digest.Pending) != 2 {
+		t.Fatalf("Expected 2 pending changes, got %+v and %+v", notifier.sent, state.Digest)
+	}
+
+	// A failing source doesn't lose track of its items
+	now = start.Add(10 * time.Minute)
+	tickets = append(tickets, notify.Change{ID: "jira-PROJ-2"})
+	reviewsErr = errors.New("rate limited")
+	if err := runNotify(context.Background(), sources, &state, notifier, opts); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	reviewsErr = nil
+
+	// The digest is sent once the window has passed
+	now = start.Add(20 * time.Minute)
+	if err := runNotify(context.Background(), sources, &state, notifier, opts); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notifier.sent) != 1 {
+		t.Fatalf("Expected a single notification, got %+v", notifier.sent)
+	}
+	if notifier.sent[0].Message != "2 new review requests, 1 JIRA ticket assigned" {
+		t.Errorf("Expected a summary of the batch, got %q", notifier.sent[0].Message)
+	}
+	if notifier.sent[0].Command != "kitty daily todo" {
+		t.Errorf("Expected the open command, got %q", notifier.sent[0].Command)
+	}
+	if len(state.Digest.Pending) != 0 {
+		t.Errorf("Expected the digest to be flushed, got %+v", state.Digest)
+	}
+}
+
+func TestRunNotify_Flush(t *testing.T) {
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	nowFunc = func() time.Time { return time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC) }
+
+	state := notify.State{Seen: map[string][]string{notify.KindReviewRequest: {}}}
+	reviews := []notify.Change{{ID: "pr-1", Title: "Fix login", URL: "https://github.com/org/repo/pull/1"}}
+	var reviewsErr error
+	sources := []changeSource{fixedChangeSource(notify.KindReviewRequest, &reviews, &reviewsErr)}
+	opts := notifyOptions{window: time.Hour, maxBatch: 10}
+
+	// A notification that can't be sent keeps the batch pending
+	failing := &recordingNotifier{err: errors.New("notify-send not found")}
+	if err := runNotify(context.Background(), sources, &state, failing, notifyOptions{window: time.Hour, flush: true}); err == nil {
+		t.Error("Expected the notification error, got nil")
+	}
+	if len(state.Digest.Pending) != 1 {
+		t.Fatalf("Expected the change to stay pending, got %+v", state.Digest)
+	}
+
+	notifier := &recordingNotifier{}
+	if err := runNotify(context.Background(), sources, &state, notifier, opts); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notifier.sent) != 0 {
+		t.Fatalf("Expected the change to wait for the window, got %+v", notifier.sent)
+	}
+
+	opts.flush = true
+	if err := runNotify(context.Background(), sources, &state, notifier, opts); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].URL != "https://github.com/org/repo/pull/1" {
+		t.Errorf("Expected the single change to be sent with its URL, got %+v", notifier.sent)
+	}
+}
+
+func TestRunNotify_QuietHours(t *testing.T) {
+	evening := time.Date(2024, 3, 11, 18, 50, 0, 0, time.UTC)
+	now := evening
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	nowFunc = func() time.Time { return now }
+
+	store := notify.NewStore(filepath.Join(t.TempDir(), "notify.json"))
+	state := notify.State{Seen: map[string][]string{notify.KindReviewRequest: {}, notify.KindJIRAAssigned: {}}}
+	reviews := []notify.Change{{ID: "pr-1"}}
+	var tickets []notify.Change
+	var noErr error
+	sources := []changeSource{
+		fixedChangeSource(notify.KindReviewRequest, &reviews, &noErr),
+		fixedChangeSource(notify.KindJIRAAssigned, &tickets, &noErr),
+	}
+
+	notifier := &recordingNotifier{}
+	quiet := datetime.QuietHours{Start: 19 * time.Hour, End: 8 * time.Hour, Location: time.UTC}
+	opts := notifyOptions{window: 15 * time.Minute, maxBatch: 10, quietHours: &quiet}
+
+	// run runs notify as a new process would, from the stored state
+	run := func() {
+		t.Helper()
+		state, err := store.Load()
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if err := runNotify(context.Background(), sources, &state, notifier, opts); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if err := store.Save(state); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if err := store.Save(state); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Detected before the quiet hours, pr-1 waits for the end of the window
+	run()
+
+	// The window ends during the quiet hours: pr-1 is held with the changes detected since
+	now = evening.Add(20 * time.Minute)
+	reviews = append(reviews, notify.Change{ID: "pr-2"})
+	run()
+	now = time.Date(2024, 3, 12, 7, 59, 0, 0, time.UTC)
+	tickets = append(tickets, notify.Change{ID: "jira-PROJ-1"})
+	run()
+
+	if len(notifier.sent) != 0 {
+		t.Fatalf("Expected nothing sent during the quiet hours, got %+v", notifier.sent)
+	}
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(state.Held) != 3 || len(state.Digest.Pending) != 0 {
+		t.Fatalf("Expected 3 held changes, got %+v and %+v", state.Held, state.Digest)
+	}
+	if until := time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC); !state.Held[0].Until.Equal(until) {
+		t.Errorf("Expected the changes held until %v, got %v", until, state.Held[0].Until)
+	}
+
+	// The first run after the quiet hours, late after a night asleep, sends them right away
+	now = time.Date(2024, 3, 12, 9, 30, 0, 0, time.UTC)
+	run()
+	if len(notifier.sent) != 1 || notifier.sent[0].Message != "2 new review requests, 1 JIRA ticket assigned" {
+		t.Fatalf("Expected a single digest of the held changes, got %+v", notifier.sent)
+	}
+	if state, _ := store.Load(); len(state.Held) != 0 || len(state.Digest.Pending) != 0 {
+		t.Errorf("Expected nothing left held or pending, got %+v", state)
+	}
+}
+
+func TestRunNotify_QuietHoursOverride(t *testing.T) {
+	night := time.Date(2024, 3, 11, 23, 0, 0, 0, time.UTC)
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	nowFunc = func() time.Time { return night }
+
+	state := notify.State{Seen: map[string][]string{notify.KindReviewRequest: {}, notify.KindJIRAAssigned: {}}}
+	state.Hold(notify.Change{ID: "pr-1", Kind: notify.KindReviewRequest}, night.Add(9*time.Hour))
+	reviews := []notify.Change{{ID: "pr-2"}}
+	tickets := []notify.Change{{ID: "jira-PROJ-1"}}
+	var noErr error
+	sources := []changeSource{
+		fixedChangeSource(notify.KindReviewRequest, &reviews, &noErr),
+		fixedChangeSource(notify.KindJIRAAssigned, &tickets, &noErr),
+	}
+	quiet := datetime.QuietHours{Start: 19 * time.Hour, End: 8 * time.Hour, Location: time.UTC}
+
+	// Quiet hours limited to JIRA let the review requests through
+	notifier := &recordingNotifier{}
+	opts := notifyOptions{window: 15 * time.Minute, maxBatch: 10, quietHours: &quiet, quietSources: []string{notify.KindJIRAAssigned}}
+	if err := runNotify(context.Background(), sources, &state, notifier, opts); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(state.Digest.Pending) != 1 || len(state.Held) != 2 || state.Held[1].ID != "jira-PROJ-1" {
+		t.Fatalf("Expected only the new ticket held, got %+v and %+v", state.Digest, state.Held)
+	}
+
+	// Overriding sends everything held at once
+	opts.overrideQuiet = true
+	if err := runNotify(context.Background(), sources, &state, notifier, opts); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].Message != "2 new review requests, 1 JIRA ticket assigned" {
+		t.Errorf("Expected the held and pending changes sent, got %+v", notifier.sent)
+	}
+	if len(state.Held) != 0 {
+		t.Errorf("Expected nothing left held, got %+v", state.Held)
+	}
+}
+
+func TestReviewChangeSource(t *testing.T) {
+	source := plainReviewSource{items: []provider.ReviewItem{
+		{TodoItem: provider.TodoItem{ID: "github-review-12", Title: "Fix login"}, Platform: "github", Repository: "org/api", Number: 12},
+		{TodoItem: provider.TodoItem{ID: "github-review-12"}, Platform: "github", Repository: "org/web", Number: 12},
+	}}
+
+	changes, err := reviewChangeSource(source).list(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(changes) != 2 || changes[0].ID != "github-org/api#12" || changes[1].ID != "github-org/web#12" {
+		t.Errorf("Expected IDs unique across repositories, got %+v", changes)
+	}
+	if changes[0].Kind != notify.KindReviewRequest || changes[0].Title != "Fix login" {
+		t.Errorf("Expected a review request change, got %+v", changes[0])
+	}
+}
+
+func TestRunNotify_Verbose(t *testing.T) {
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	nowFunc = func() time.Time { return time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC) }
+
+	reviews := []notify.Change{{ID: "pr-1"}}
+	reviewsErr := errors.New("rate limited")
+	sources := []changeSource{fixedChangeSource(notify.KindReviewRequest, &reviews, &reviewsErr)}
+
+	// Progress goes to the reporter, never to stdout
+	var out bytes.Buffer
+	opts := notifyOptions{window: 15 * time.Minute, maxBatch: 10, reporter: verboselog.New(&out, true)}
+	var state notify.State
+	if err := runNotify(context.Background(), sources, &state, &recordingNotifier{}, opts); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(out.String(), "✗ "+notify.KindReviewRequest+" failed after") {
+		t.Errorf("Expected the failing source reported, got:\n%s", out.String())
+	}
+}
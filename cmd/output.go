@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"daily/internal/metrics"
+	"daily/internal/output"
+)
+
+// stdoutOutFile is the --out-file value meaning "write to stdout"
+const stdoutOutFile = "-"
+
+// progressJSON is the --progress value streaming progress events as JSON lines
+const progressJSON = "json"
+
+// progressOutput is where progress events are written, overridden in tests
+var progressOutput io.Writer = os.Stderr
+
+// validateOutFile checks that --out-file is compatible with the output format
+func validateOutFile(outputFormat, outFile string) error {
+	if outFile != "" && outputFormat == "tui" {
+		return fmt.Errorf("--out-file cannot be used with tui output (use -o text or -o json)")
+	}
+	return nil
+}
+
+// validateProgress checks the --progress value and that it is compatible with the output format
+func validateProgress(outputFormat, progress string) error {
+	if progress == "" {
+		return nil
+	}
+	if progress != progressJSON {
+		return fmt.Errorf("invalid progress format: %s (must be '%s')", progress, progressJSON)
+	}
+	if outputFormat == "tui" {
+		return fmt.Errorf("--progress cannot be used with tui output (use -o text or -o json)")
+	}
+	return nil
+}
+
+// newRunRecorder creates the recorder of a run, streaming its progress events when requested
+// with --progress
+func newRunRecorder(progress string) *metrics.Recorder {
+	recorder := metrics.NewRecorder()
+	if progress == progressJSON {
+		recorder.SetProgress(progressOutput)
+	}
+	return recorder
+}
+
+// writeOutput prints content to stdout, or writes it atomically to outFile when set
+func writeOutput(outFile, content string) error {
+	return streamOutput(outFile, func(w io.Writer) error {
+		_, err := io.WriteString(w, content)
+		return err
+	})
+}
+
+// streamOutput runs write on stdout, or on a file written atomically to outFile when set, so
+// that large outputs don't have to be held in memory
+func streamOutput(outFile string, write func(w io.Writer) error) error {
+	if outFile == "" || outFile == stdoutOutFile {
+		return write(os.Stdout)
+	}
+
+	if err := streamFileAtomic(outFile, 0644, write); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "✅ Wrote output to %s\n", outFile)
+	return nil
+}
+
+// printRunReport prints the requests, cache lookups and wall time of the run after verbose output
+func printRunReport(recorder *metrics.Recorder) {
+	fmt.Println()
+	fmt.Print(output.NewFormatter().FormatRunReport(recorder.Report()))
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into place,
+// creating parent directories as needed
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return streamFileAtomic(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// streamFileAtomic writes a temporary file next to path with write and renames it into place,
+// like writeFileAtomic
+func streamFileAtomic(path string, perm os.FileMode, write func(w io.Writer) error) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if err := write(tmp); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
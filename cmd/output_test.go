@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestValidateOutFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		outputFormat string
+		outFile      string
+		hasError     bool
+	}{
+		{"no out file with tui", "tui", "", false},
+		{"out file with text", "text", "summary.txt", false},
+		{"out file with json", "json", "summary.json", false},
+		{"stdout with json", "json", "-", false},
+		{"out file with tui", "tui", "summary.txt", true},
+		{"stdout with tui", "tui", "-", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOutFile(tt.outputFormat, tt.outFile)
+			if tt.hasError && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.hasError && err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateProgress(t *testing.T) {
+	tests := []struct {
+		name         string
+		outputFormat string
+		progress     string
+		hasError     bool
+	}{
+		{"no progress with tui", "tui", "", false},
+		{"json progress with json", "json", "json", false},
+		{"json progress with text", "text", "json", false},
+		{"unknown progress format", "json", "text", true},
+		{"json progress with tui", "tui", "json", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateProgress(tt.outputFormat, tt.progress)
+			if tt.hasError && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.hasError && err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestOutFileFlag_RejectedWithTUI(t *testing.T) {
+	commands := []*cobra.Command{SumCmd(), TodoCmd(), ReviewsCmd()}
+
+	for _, cmd := range commands {
+		t.Run(cmd.Use, func(t *testing.T) {
+			if cmd.Flags().Lookup("out-file") == nil {
+				t.Fatal("Expected out-file flag to be defined")
+			}
+
+			cmd.SetArgs([]string{"--out-file", filepath.Join(t.TempDir(), "out.txt")})
+			err := cmd.Execute()
+			if err == nil {
+				t.Fatal("Expected error for --out-file with tui output, got nil")
+			}
+			if !strings.Contains(err.Error(), "--out-file cannot be used with tui output") {
+				t.Errorf("Expected tui conflict error, got '%s'", err.Error())
+			}
+		})
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes", "daily", "today.md")
+
+	// Parent directories are created on demand
+	if err := writeFileAtomic(path, []byte("first"), 0644); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Existing files are replaced
+	if err := writeFileAtomic(path, []byte("second"), 0644); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read written file: %v", err)
+	}
+	if string(data) != "second" {
+		t.Errorf("Expected content 'second', got '%s'", string(data))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat written file: %v", err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("Expected mode 0644, got %o", info.Mode().Perm())
+	}
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("Failed to read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the output file in the directory, got %d entries", len(entries))
+	}
+}
+
+func TestWriteOutput_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.json")
+
+	if err := writeOutput(path, `{"activities":[]}`); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read written file: %v", err)
+	}
+	if string(data) != `{"activities":[]}` {
+		t.Errorf("Expected JSON content, got '%s'", string(data))
+	}
+}
+
+func TestStreamOutput_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.json")
+
+	err := streamOutput(path, func(w io.Writer) error {
+		for _, chunk := range []string{`{"activities":[`, `{"id":"1"}`, `]}`} {
+			if _, err := io.WriteString(w, chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"activities":[{"id":"1"}]}` {
+		t.Errorf("Expected the chunks in the file, got '%s'", string(data))
+	}
+
+	// A failed write leaves the previous file in place
+	err = streamOutput(path, func(w io.Writer) error {
+		_, _ = io.WriteString(w, `{"activities":[`)
+		return errors.New("encoding failed")
+	})
+	if err == nil || !strings.Contains(err.Error(), "encoding failed") {
+		t.Errorf("Expected the write error, got: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"activities":[{"id":"1"}]}` {
+		t.Errorf("Expected the previous file to be kept, got '%s'", string(data))
+	}
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/config"
+	"daily/internal/provider"
+	"daily/internal/provider/calendar"
+	"daily/internal/provider/confluence"
+	"daily/internal/provider/github"
+	"daily/internal/provider/gitlab"
+	"daily/internal/provider/jira"
+	"daily/internal/provider/obsidian"
+	"daily/internal/provider/savedquery"
+	"daily/internal/provider/slack"
+	"daily/internal/verboselog"
+)
+
+// Provider capabilities, matching the commands that can use them
+const (
+	capabilityActivities = "activities"
+	capabilityTodos      = "todos"
+	capabilityReviews    = "reviews"
+	capabilityMentions   = "mentions"
+)
+
+// providerInfo describes a provider for the providers command. It never includes config values.
+type providerInfo struct {
+	Name         string                 `json:"name"`
+	Enabled      bool                   `json:"enabled"`
+	Configured   bool                   `json:"configured"`
+	Capabilities []string               `json:"capabilities"`
+	ConfigFields []provider.ConfigField `json:"config_fields"`
+}
+
+func ProvidersCmd() *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "providers",
+		Short: "List available providers and their capabilities",
+		Long:  "List every provider with whether it is enabled and configured, the commands it supports, and the configuration fields it expects. Secrets are never shown.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if outputFormat != "text" && outputFormat != "json" {
+				return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			providers := describeProviders(cfg)
+
+			if outputFormat == "json" {
+				jsonBytes, err := json.MarshalIndent(struct {
+					Providers []providerInfo `json:"providers"`
+				}{providers}, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(jsonBytes))
+				return nil
+			}
+
+			fmt.Print(formatProviders(providers))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: 'text' or 'json'")
+	cmd.AddCommand(providerScopesCmd())
+	cmd.AddCommand(providerLoginCmd())
+
+	return cmd
+}
+
+// providerScopesCmd documents the token permissions needed by each capability of a provider
+func providerScopesCmd() *cobra.Command {
+	var outputFormat string
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "scopes github",
+		Short: "Show the token permissions needed by each capability of a provider",
+		Long: "Show the minimum classic scopes and fine-grained permissions needed by each capability of the GitHub provider, and what is left out when the token lacks them. " +
+			"Use --check to probe each capability with the configured token.",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"github"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if outputFormat != "text" && outputFormat != "json" {
+				return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", outputFormat)
+			}
+			if args[0] != "github" {
+				return fmt.Errorf("no scopes documented for provider %q (only github)", args[0])
+			}
+
+			var checks []github.CapabilityCheck
+			if check {
+				cfg, err := config.LoadWithTokens()
+				if err != nil {
+					return fmt.Errorf("failed to load config: %w", err)
+				}
+				ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+				defer cancel()
+				if checks, err = github.NewProvider(cfg.GitHub).CheckCapabilities(ctx); err != nil {
+					return err
+				}
+			}
+
+			if outputFormat == "json" {
+				jsonBytes, err := json.MarshalIndent(struct {
+					Permissions []github.Permission      `json:"permissions"`
+					Checks      []github.CapabilityCheck `json:"checks,omitempty"`
+				}{github.Permissions, checks}, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(jsonBytes))
+				return nil
+			}
+
+			fmt.Print(formatScopes(github.Permissions, checks))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: 'text' or 'json'")
+	cmd.Flags().BoolVar(&check, "check", false, "Probe each capability with the configured token")
+
+	return cmd
+}
+
+// providerLoginCmd signs in to the providers using OAuth, caching their token for the next runs
+func providerLoginCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "login calendar",
+		Short: "Sign in to a provider using OAuth",
+		Long: "Sign in to Google Calendar from a browser with a code, using the client_id and client_secret of the calendar config. " +
+			"The token is cached in ~/.config/daily/state/calendar_token.json and refreshed by the next runs. Not needed with a service account key.",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"calendar"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if args[0] != "calendar" {
+				return fmt.Errorf("no sign in for provider %q (only calendar)", args[0])
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			// The device code expires after 30 minutes
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Minute)
+			defer cancel()
+			err = calendar.NewProvider(cfg.Calendar).Login(ctx, func(code calendar.DeviceCode) {
+				fmt.Printf("Open %s and enter the code %s\n", code.VerificationURL, code.UserCode)
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Println("✅ Signed in to Google Calendar")
+			return nil
+		},
+	}
+}
+
+// describeProviders lists the built-in providers with their state for the given config
+func describeProviders(cfg *config.Config) []providerInfo {
+	// Providers are built with Enabled forced on so that "configured" reports whether the
+	// required fields are set, independently of whether the provider is enabled
+	configured := func(c provider.Config) provider.Config {
+		c.Enabled = true
+		return c
+	}
+
+	entries := []struct {
+		provider     provider.Provider
+		enabled      bool
+		capabilities []string
+	}{
+		{github.NewProvider(configured(cfg.GitHub)), cfg.GitHub.Enabled, []string{capabilityActivities, capabilityTodos}},
+		{jira.NewProvider(configured(cfg.JIRA)), cfg.JIRA.Enabled, []string{capabilityActivities, capabilityTodos}},
+		{obsidian.NewProvider(configured(cfg.Obsidian)), cfg.Obsidian.Enabled, []string{capabilityActivities, capabilityTodos}},
+		{confluence.NewProvider(configured(cfg.Confluence)), cfg.Confluence.Enabled, []string{capabilityActivities, capabilityTodos}},
+		{gitlab.NewProvider(configured(cfg.GitLab)), cfg.GitLab.Enabled, []string{capabilityActivities, capabilityTodos}},
+		{calendar.NewProvider(configured(cfg.Calendar)), cfg.Calendar.Enabled, []string{capabilityActivities}},
+		{slack.NewProvider(configured(cfg.Slack)), cfg.Slack.Enabled, []string{capabilityActivities, capabilityTodos}},
+		{savedquery.NewProvider(configured(cfg.SavedQueries)), cfg.SavedQueries.Enabled, []string{capabilityActivities}},
+	}
+
+	providers := make([]providerInfo, len(entries))
+	for i, entry := range entries {
+		capabilities := entry.capabilities
+		if _, ok := entry.provider.(provider.ReviewSource); ok {
+			capabilities = append(capabilities, capabilityReviews)
+		}
+		if _, ok := entry.provider.(provider.MentionSource); ok {
+			capabilities = append(capabilities, capabilityMentions)
+		}
+
+		providers[i] = providerInfo{
+			Name:         entry.provider.Name(),
+			Enabled:      entry.enabled,
+			Configured:   entry.provider.IsConfigured(),
+			Capabilities: capabilities,
+			ConfigFields: entry.provider.ConfigSpec(),
+		}
+	}
+
+	return providers
+}
+
+// formatProviders renders providers as a text table
+func formatProviders(providers []providerInfo) string {
+	var output strings.Builder
+
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PROVIDER\tENABLED\tCONFIGURED\tCAPABILITIES\tCONFIG FIELDS")
+	for _, p := range providers {
+		fields := make([]string, len(p.ConfigFields))
+		for i, field := range p.ConfigFields {
+			fields[i] = field.Name
+			if field.Required {
+				fields[i] += "*"
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			p.Name, yesNo(p.Enabled), yesNo(p.Configured), strings.Join(p.Capabilities, ", "), strings.Join(fields, ", "))
+	}
+	_ = w.Flush()
+
+	output.WriteString("\n* required field\n")
+	return output.String()
+}
+
+// formatScopes renders the permissions of each capability as a text table, with the outcome of
+// their check when checked
+func formatScopes(permissions []github.Permission, checks []github.CapabilityCheck) string {
+	var output strings.Builder
+
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	header := "CAPABILITY\tCLASSIC SCOPE\tFINE-GRAINED PERMISSION\tUSED FOR"
+	if checks != nil {
+		header += "\tCHECK"
+	}
+	_, _ = fmt.Fprintln(w, header)
+	for i, permission := range permissions {
+		row := fmt.Sprintf("%s\t%s\t%s\t%s", permission.Capability, permission.Classic, permission.FineGrained, permission.Usage)
+		if i < len(checks) {
+			row += "\t" + checks[i].Status
+		}
+		_, _ = fmt.Fprintln(w, row)
+	}
+	_ = w.Flush()
+
+	output.WriteString("\nFine-grained tokens also need Metadata: read on the repositories they can access.\n")
+	for _, check := range checks {
+		if check.Detail != "" {
+			fmt.Fprintf(&output, "%s %s: %s\n", checkIcon(check.Status), check.Capability, check.Detail)
+		}
+	}
+	return output.String()
+}
+
+// reportDegraded warns about the GitHub capabilities refused to the token and what they left out
+func reportDegraded(reporter *verboselog.Reporter, degraded []*github.CapabilityError) {
+	for _, capErr := range degraded {
+		permission := capErr.Permission()
+		reporter.Warn("GitHub token lacks %s (classic: %s, fine-grained: %s): %s",
+			capErr.Capability, permission.Classic, permission.FineGrained, permission.Degraded)
+	}
+}
+
+// checkIcon returns the icon of a capability check status
+func checkIcon(status string) string {
+	switch status {
+	case github.CheckForbidden:
+		return "🔒"
+	case github.CheckFailed:
+		return "❌"
+	default:
+		return "⏭️ "
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
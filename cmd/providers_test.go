@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"daily/internal/config"
+	"daily/internal/provider"
+	"daily/internal/provider/github"
+	"daily/internal/verboselog"
+)
+
+func fixtureConfig() *config.Config {
+	return &config.Config{
+		GitHub:     provider.Config{Enabled: true, Username: "octocat", Token: "ghp_secret"},
+		JIRA:       provider.Config{Enabled: true, URL: "https://example.atlassian.net", Token: "jira_secret"}, // Missing email
+		Obsidian:   provider.Config{Enabled: false, URL: "/vault"},
+		Confluence: provider.Config{Enabled: false},
+		SavedQueries: provider.Config{Enabled: true, Queries: []provider.SavedQuery{
+			{Name: "Deprecated API usages", URL: "https://sourcegraph.example.com/.api/search", CountPath: "matchCount"},
+		}},
+	}
+}
+
+func TestDescribeProviders(t *testing.T) {
+	providers := describeProviders(fixtureConfig())
+
+	expected := map[string]struct {
+		enabled      bool
+		configured   bool
+		capabilities []string
+	}{
+		"github":        {true, true, []string{"activities", "todos", "reviews", "mentions"}},
+		"jira":          {true, false, []string{"activities", "todos", "mentions"}},
+		"obsidian":      {false, true, []string{"activities", "todos"}},
+		"confluence":    {false, false, []string{"activities", "todos", "mentions"}},
+		"gitlab":        {false, false, []string{"activities", "todos", "reviews"}},
+		"calendar":      {false, false, []string{"activities"}},
+		"slack":         {false, false, []string{"activities", "todos"}},
+		"saved_queries": {true, true, []string{"activities"}},
+	}
+
+	if len(providers) != len(expected) {
+		t.Fatalf("Expected %d providers, got %d", len(expected), len(providers))
+	}
+
+	for _, p := range providers {
+		want, ok := expected[p.Name]
+		if !ok {
+			t.Errorf("Unexpected provider %s", p.Name)
+			continue
+		}
+		if p.Enabled != want.enabled {
+			t.Errorf("%s: expected enabled %t, got %t", p.Name, want.enabled, p.Enabled)
+		}
+		if p.Configured != want.configured {
+			t.Errorf("%s: expected configured %t, got %t", p.Name, want.configured, p.Configured)
+		}
+		if strings.Join(p.Capabilities, ",") != strings.Join(want.capabilities, ",") {
+			t.Errorf("%s: expected capabilities %v, got %v", p.Name, want.capabilities, p.Capabilities)
+		}
+		if len(p.ConfigFields) == 0 {
+			t.Errorf("%s: expected config fields", p.Name)
+		}
+	}
+}
+
+func TestDescribeProviders_JSONShape(t *testing.T) {
+	jsonBytes, err := json.Marshal(describeProviders(fixtureConfig()))
+	if err != nil {
+		t.Fatalf("Failed to marshal providers: %v", err)
+	}
+
+	if strings.Contains(string(jsonBytes), "ghp_secret") || strings.Contains(string(jsonBytes), "jira_secret") {
+		t.Error("Provider listing must not reveal secrets")
+	}
+
+	var providers []map[string]any
+	if err := json.Unmarshal(jsonBytes, &providers); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+
+	for _, key := range []string{"name", "enabled", "configured", "capabilities", "config_fields"} {
+		if _, ok := providers[0][key]; !ok {
+			t.Errorf("Expected key '%s' in provider JSON", key)
+		}
+	}
+
+	fields, ok := providers[0]["config_fields"].([]any)
+	if !ok || len(fields) == 0 {
+		t.Fatal("Expected config_fields to be a non-empty array")
+	}
+	field := fields[0].(map[string]any)
+	for _, key := range []string{"name", "required", "description"} {
+		if _, ok := field[key]; !ok {
+			t.Errorf("Expected key '%s' in config field JSON", key)
+		}
+	}
+}
+
+func TestFormatProviders(t *testing.T) {
+	result := formatProviders(describeProviders(fixtureConfig()))
+
+	for _, expected := range []string{"PROVIDER", "github", "jira", "obsidian", "confluence", "token*"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected table to contain '%s'", expected)
+		}
+	}
+	if strings.Contains(result, "ghp_secret") {
+		t.Error("Provider table must not reveal secrets")
+	}
+}
+
+func TestFormatScopes(t *testing.T) {
+	result := formatScopes(github.Permissions, nil)
+	for _, expected := range []string{"CAPABILITY", "commit_search", "read:org", "Checks: read", "Actions: read", "Metadata: read"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected table to contain %q, got:\n%s", expected, result)
+		}
+	}
+	if strings.Contains(result, "CHECK\n") {
+		t.Errorf("Expected no check column without checks, got:\n%s", result)
+	}
+
+	checks := make([]github.CapabilityCheck, len(github.Permissions))
+	for i, permission := range github.Permissions {
+		checks[i] = github.CapabilityCheck{Capability: permission.Capability, Status: github.CheckOK}
+	}
+	checks[2] = github.CapabilityCheck{Capability: github.CapabilityTeams, Status: github.CheckForbidden, Detail: "Only the review requests of the user are listed"}
+
+	result = formatScopes(github.Permissions, checks)
+	for _, expected := range []string{"CHECK", "forbidden", "🔒 teams: Only the review requests of the user are listed"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected table to contain %q, got:\n%s", expected, result)
+		}
+	}
+}
+
+func TestReportDegraded(t *testing.T) {
+	var out bytes.Buffer
+	reporter := verboselog.New(&out, true)
+
+	reportDegraded(reporter, nil)
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing without degraded capability, got %q", out.String())
+	}
+
+	reportDegraded(reporter, []*github.CapabilityError{{Capability: github.CapabilityNotifications, Err: errors.New("GitHub API request failed with status 403")}})
+	for _, expected := range []string{"  ⚠ GitHub token lacks notifications", "classic: notifications", "not supported", "Mentions are left out"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got %q", expected, out.String())
+		}
+	}
+}
@@ -3,89 +3,149 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"path"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"daily/internal/config"
+	"daily/internal/enrich"
+	"daily/internal/metrics"
 	"daily/internal/output"
+	"daily/internal/provider"
 	"daily/internal/provider/github"
+	"daily/internal/provider/gitlab"
+	"daily/internal/verboselog"
 )
 
+// defaultStaleAfterDays is the review age threshold used when the GitHub config doesn't set one
+const defaultStaleAfterDays = 3
+
+// nowFunc returns the current time and can be overridden in tests to pin the clock
+var nowFunc = time.Now
+
 func ReviewsCmd() *cobra.Command {
 	var verbose bool
 	var outputFormat string
+	var outFile string
+	var progress string
 	var skipDetails bool
+	var base string
 
 	cmd := &cobra.Command{
 		Use:   "reviews",
 		Short: "Get PRs awaiting review from you and your teams",
 		Long:  "Display pull requests that are awaiting review from you or your teams, including CI status and PR details. Uses concurrent processing with rate limiting for optimal performance. Use --verbose to see detailed progress.",
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			// Validate output format
 			if outputFormat != "text" && outputFormat != "json" && outputFormat != "tui" {
 				return fmt.Errorf("invalid output format: %s (must be 'text', 'json', or 'tui')", outputFormat)
 			}
+			if err := validateOutFile(outputFormat, outFile); err != nil {
+				return err
+			}
+			if err := validateProgress(outputFormat, progress); err != nil {
+				return err
+			}
+			if base != "" {
+				if _, err := path.Match(base, ""); err != nil {
+					return fmt.Errorf("invalid --base pattern %q: %w", base, err)
+				}
+				if skipDetails {
+					return fmt.Errorf("--base cannot be used with --skip-details (the base branch is fetched with PR details)")
+				}
+			}
+
+			recorder := newRunRecorder(progress)
+			defer func() { recorder.RunFinished(err) }()
 
 			if outputFormat == "text" {
 				fmt.Println("Gathering review requests...")
 			}
 
 			// Load configuration
-			cfg, err := config.Load()
+			cfg, err := config.LoadWithTokens()
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
 
-			ctx := context.Background()
+			ctx := metrics.WithRecorder(context.Background(), recorder)
 			showVerbose := verbose && outputFormat == "text"
+			var reporter *verboselog.Reporter
+			if showVerbose {
+				reporter = verboselog.Stderr()
+			}
+			autoPruneCache(cfg, reporter)
 
-			var reviewItems output.ReviewItems
+			var sources []reviewSource
+			var fetchComments output.CommentFetcher
+			var githubProvider *github.Provider
 
-			// Get GitHub review requests
+			// GitHub review requests
 			if cfg.GitHub.Enabled {
-				if showVerbose {
-					fmt.Println("✓ GitHub provider enabled")
-				}
-				githubProvider := github.NewProvider(cfg.GitHub)
+				githubProvider = github.NewProvider(cfg.GitHub)
 				if githubProvider.IsConfigured() {
-					githubReviews, err := getGitHubReviews(ctx, githubProvider, showVerbose, skipDetails)
-					if err != nil {
-						if showVerbose {
-							fmt.Printf("❌ GitHub reviews failed: %v\n", err)
-						}
-					} else {
-						reviewItems.GitHub = githubReviews
-						if showVerbose {
-							totalPRs := len(githubReviews.UserRequests) + len(githubReviews.TeamRequests)
-							fmt.Printf("✅ GitHub returned %d PRs awaiting review\n", totalPRs)
-						}
-					}
-				} else if showVerbose {
-					fmt.Println("⚠️  GitHub provider not configured")
+					fetchComments = prCommentFetcher(githubProvider)
+					sources = append(sources, reviewSource{
+						source:         githubProvider,
+						staleAfterDays: cfg.GitHub.StaleAfterDays,
+					})
+				} else {
+					reporter.ProviderSkipped(githubProvider.Name(), "not configured")
+					githubProvider = nil
 				}
-			} else if showVerbose {
-				fmt.Println("✗ GitHub provider disabled")
+			} else {
+				reporter.ProviderSkipped("github", "disabled")
 			}
 
-			if showVerbose {
-				fmt.Println()
+			// GitLab review requests
+			if cfg.GitLab.Enabled {
+				gitlabProvider := gitlab.NewProvider(cfg.GitLab)
+				if gitlabProvider.IsConfigured() {
+					sources = append(sources, reviewSource{source: gitlabProvider})
+				} else {
+					reporter.ProviderSkipped(gitlabProvider.Name(), "not configured")
+				}
+			} else {
+				reporter.ProviderSkipped("gitlab", "disabled")
 			}
 
+			reviewItems := collectReviews(ctx, sources, reviewOptions{
+				skipDetails: skipDetails,
+				base:        base,
+				reporter:    reporter,
+			})
+			if githubProvider != nil && reporter != nil {
+				reportReviewTeams(ctx, reporter, githubProvider)
+				reportDegraded(reporter, githubProvider.Degraded())
+			}
+			hidden := loadHiddenState(cfg)
+			recordHiddenMatches(hidden, output.TodoItems{}, reviewItems)
+			reviewItems = filterHiddenReviews(reviewItems, hidden)
+			setReviewSeverity(reviewItems, cfg.SeverityRules())
+
 			// Format and display results
 			switch outputFormat {
 			case "json":
 				formatter := output.NewFormatter()
+				formatter.SetMeta(recorder.Report())
 				result := formatter.FormatReviewJSON(reviewItems)
-				fmt.Print(result)
+				return writeOutput(outFile, result)
 			case "tui":
 				formatter := output.NewFormatter()
-				return formatter.FormatReviewTUI(reviewItems)
+				return formatter.FormatReviewTUI(reviewItems, fetchComments)
 			case "text":
 				formatter := output.NewFormatter()
 				result := formatter.FormatReview(reviewItems)
-				fmt.Print(result)
+				if err := writeOutput(outFile, result); err != nil {
+					return err
+				}
+				if showVerbose {
+					printRunReport(recorder)
+				}
+				return nil
 			}
 
 			return nil
@@ -94,250 +154,228 @@ func ReviewsCmd() *cobra.Command {
 
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output for debugging (text mode only)")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "tui", "Output format: 'tui', 'text', or 'json'")
+	cmd.Flags().StringVar(&outFile, "out-file", "", "Write output to this file instead of stdout ('-' for stdout, not available with tui)")
+	cmd.Flags().StringVar(&progress, "progress", "", "Stream progress events to stderr: 'json' for one JSON object per line")
 	cmd.Flags().BoolVar(&skipDetails, "skip-details", false, "Skip fetching CI status and PR details for faster execution")
+	cmd.Flags().StringVar(&base, "base", "", "Only show PRs targeting base branches matching this glob (e.g., 'release/*')")
 
 	return cmd
 }
 
-func getGitHubReviews(ctx context.Context, provider *github.Provider, verbose bool, skipDetails bool) (output.GitHubReviews, error) {
-	var reviews output.GitHubReviews
-
-	// Get user review requests
-	userRequests, err := provider.GetUserReviewRequests(ctx)
+// reportReviewTeams lists the GitHub teams searched for review requests and the ones skipped
+func reportReviewTeams(ctx context.Context, reporter *verboselog.Reporter, githubProvider *github.Provider) {
+	selection, err := githubProvider.ReviewTeams(ctx)
 	if err != nil {
-		return reviews, fmt.Errorf("failed to get user review requests: %w", err)
+		reporter.Warn("GitHub teams failed: %v", err)
+		return
 	}
-
-	// Get team review requests
-	teamRequests, err := provider.GetTeamReviewRequests(ctx)
-	if err != nil {
-		return reviews, fmt.Errorf("failed to get team review requests: %w", err)
+	reporter.Detail("Queried %d GitHub teams: %s", len(selection.Queried), strings.Join(selection.Queried, ", "))
+	if len(selection.Skipped) > 0 {
+		reporter.Detail("Skipped %d GitHub teams: %s", len(selection.Skipped), strings.Join(selection.Skipped, ", "))
 	}
+}
 
-	// Convert and enrich with CI status and PR details
-	reviews.UserRequests = make([]output.ReviewItem, len(userRequests))
-	if verbose && !skipDetails && len(userRequests) > 0 {
-		fmt.Printf("🔄 Fetching additional details for %d user review requests (concurrent)...\n", len(userRequests))
-	}
+// reviewSource is a configured source of review requests
+type reviewSource struct {
+	source         provider.ReviewSource
+	staleAfterDays int // Review age threshold, defaultStaleAfterDays when not set
+}
 
-	if skipDetails {
-		// Fast path: just convert without enrichment
-		for i, pr := range userRequests {
-			reviews.UserRequests[i] = output.ReviewItem{
-				TodoItem: output.TodoItem{
-					ID:          pr.ID,
-					Title:       pr.Title,
-					Description: pr.Description,
-					URL:         pr.URL,
-					UpdatedAt:   pr.UpdatedAt,
-					Tags:        pr.Tags,
-				},
-			}
+// reviewOptions controls how review requests are collected
+type reviewOptions struct {
+	skipDetails bool   // Don't enrich items with CI status and PR details
+	base        string // Only keep items whose base branch matches this glob
+	reporter    *verboselog.Reporter
+}
+
+// collectReviews gathers the review requests of all sources, enriches them with their
+// details and flags the stale ones. Sources that fail are skipped.
+func collectReviews(ctx context.Context, sources []reviewSource, opts reviewOptions) output.ReviewItems {
+	reviewItems := output.ReviewItems{}
+	now := nowFunc()
+	recorder := metrics.FromContext(ctx)
+
+	for _, src := range sources {
+		name := src.source.Name()
+
+		opts.reporter.ProviderEnabled(name)
+		start := time.Now()
+		recorder.ProviderStarted(name)
+		items, err := src.source.GetReviewRequests(ctx)
+		if err != nil {
+			recorder.ProviderFinished(name, 0, err)
+			opts.reporter.ProviderResult(name, 0, time.Since(start), err)
+			continue
 		}
-	} else {
-		// Concurrent enrichment
-		reviews.UserRequests = enrichPRsConcurrently(ctx, provider, userRequests, "user", verbose)
-	}
 
-	reviews.TeamRequests = make([]output.ReviewItem, len(teamRequests))
-	if verbose && !skipDetails && len(teamRequests) > 0 {
-		fmt.Printf("🔄 Fetching additional details for %d team review requests (concurrent)...\n", len(teamRequests))
-	}
+		if enricher, ok := src.source.(provider.ReviewEnricher); ok && !opts.skipDetails && len(items) > 0 {
+			items = enrichReviews(ctx, name, enricher, items, opts.reporter)
+		}
 
-	if skipDetails {
-		// Fast path: just convert without enrichment
-		for i, pr := range teamRequests {
-			reviews.TeamRequests[i] = output.ReviewItem{
-				TodoItem: output.TodoItem{
-					ID:          pr.ID,
-					Title:       pr.Title,
-					Description: pr.Description,
-					URL:         pr.URL,
-					UpdatedAt:   pr.UpdatedAt,
-					Tags:        pr.Tags,
-				},
-			}
+		converted := make([]output.ReviewItem, len(items))
+		for i, item := range items {
+			converted[i] = convertReviewItem(item)
 		}
-	} else {
-		// Concurrent enrichment
-		reviews.TeamRequests = enrichPRsConcurrently(ctx, provider, teamRequests, "team", verbose)
-	}
 
-	if verbose && !skipDetails {
-		totalPRs := len(userRequests) + len(teamRequests)
-		if totalPRs > 0 {
-			fmt.Printf("✅ Completed fetching additional details for all %d PRs\n", totalPRs)
+		staleAfterDays := src.staleAfterDays
+		if staleAfterDays <= 0 {
+			staleAfterDays = defaultStaleAfterDays
 		}
+		markStaleReviews(converted, staleAfterDays, now)
+		if opts.base != "" {
+			converted = filterReviewsByBase(converted, opts.base)
+		}
+
+		recorder.ProviderFinished(name, len(converted), nil)
+		opts.reporter.ProviderResult(name, len(converted), time.Since(start), nil)
+		reviewItems = append(reviewItems, converted...)
 	}
 
-	return reviews, nil
+	return reviewItems
 }
 
-func enrichPRWithDetails(ctx context.Context, provider *github.Provider, pr github.TodoItem) (output.ReviewItem, error) {
-	reviewItem := output.ReviewItem{
-		TodoItem: output.TodoItem{
-			ID:          pr.ID,
-			Title:       pr.Title,
-			Description: pr.Description,
-			URL:         pr.URL,
-			UpdatedAt:   pr.UpdatedAt,
-			Tags:        pr.Tags,
-		},
-	}
+// enrichReviews adds CI status and PR details to the items concurrently with rate limiting
+func enrichReviews(ctx context.Context, name string, enricher provider.ReviewEnricher, items []provider.ReviewItem, reporter *verboselog.Reporter) []provider.ReviewItem {
+	reporter.Detail("Fetching the details of %d review requests", len(items))
 
-	// Get CI status
-	ciStatus, err := provider.GetPRCIStatus(ctx, pr.Repository, pr.Number)
-	if err == nil {
-		// Convert github.CIStatus to output.CIStatus
-		reviewItem.CIStatus = output.CIStatus{
-			State:      ciStatus.State,
-			TotalCount: ciStatus.TotalCount,
-			Checks:     convertCheckRuns(ciStatus.Checks),
-		}
+	progress := &progressEnricher{
+		ReviewEnricher: enricher,
+		recorder:       metrics.FromContext(ctx),
+		provider:       name,
+		total:          len(items),
 	}
+	enriched, errs := enrich.Reviews(ctx, progress, items, enrich.DefaultOptions)
 
-	// Get PR details (additions, deletions, changed files)
-	prDetails, err2 := provider.GetPRDetails(ctx, pr.Repository, pr.Number)
-	if err2 == nil {
-		// Convert github.PRDetails to output.PRDetails
-		reviewItem.PRDetails = output.PRDetails{
-			Additions:    prDetails.Additions,
-			Deletions:    prDetails.Deletions,
-			ChangedFiles: prDetails.ChangedFiles,
+	failed := 0
+	for i, err := range errs {
+		if err == nil {
+			continue
 		}
+		failed++
+		reporter.Warn("Failed to fetch the details of %s: %v", items[i].ID, err)
 	}
+	reporter.Detail("Fetched the details: %d successful, %d failed", len(items)-failed, failed)
 
-	// Return the first error encountered, if any
-	if err != nil {
-		return reviewItem, err
-	}
-	if err2 != nil {
-		return reviewItem, err2
-	}
-
-	return reviewItem, nil
+	return enriched
 }
 
-func convertCheckRuns(githubChecks []github.CheckRun) []output.CheckRun {
-	checks := make([]output.CheckRun, len(githubChecks))
-	for i, check := range githubChecks {
-		checks[i] = output.CheckRun{
-			Name:       check.Name,
-			Status:     check.Status,
-			Conclusion: check.Conclusion,
-			URL:        check.URL,
-		}
-	}
-	return checks
-}
+// progressEnricher records the progress of the enrichment of the review requests of a provider
+type progressEnricher struct {
+	provider.ReviewEnricher
+	recorder *metrics.Recorder
+	provider string
+	total    int
 
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+	mu   sync.Mutex // Keeps the progress events in order
+	done int
 }
 
-// enrichPRsConcurrently processes PRs concurrently with rate limiting
-func enrichPRsConcurrently(ctx context.Context, provider *github.Provider, prs []github.TodoItem, requestType string, verbose bool) []output.ReviewItem {
-	if len(prs) == 0 {
-		return make([]output.ReviewItem, 0)
-	}
+func (e *progressEnricher) EnrichReview(ctx context.Context, item provider.ReviewItem) (provider.ReviewItem, error) {
+	enriched, err := e.ReviewEnricher.EnrichReview(ctx, item)
 
-	// Create a rate limiter: max 5 concurrent requests, 1 request every 200ms
-	const maxWorkers = 5
-	const rateLimitDelay = 200 * time.Millisecond
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.done++
+	e.recorder.EnrichmentProgress(e.provider, e.done, e.total)
+	return enriched, err
+}
 
-	// Channels for work distribution and results
-	type prJob struct {
-		index int
-		pr    github.TodoItem
-	}
+// convertReviewItem converts a provider review item to an output review item
+func convertReviewItem(item provider.ReviewItem) output.ReviewItem {
+	return output.ReviewItem{
+		TodoItem: output.TodoItem{
+			ID:          item.ID,
+			Title:       item.Title,
+			Description: item.Description,
+			URL:         item.URL,
+			UpdatedAt:   item.UpdatedAt,
+			Tags:        item.Tags,
+			Milestone:   item.Milestone,
+			Details:     item.Details,
+			Provenance:  convertProvenance(item.Provenance),
+		},
+		Platform:    item.Platform,
+		RequestType: item.RequestType,
+		CIStatus: output.CIStatus{
+			State:      item.CIStatus.State,
+			TotalCount: item.CIStatus.TotalCount,
+			Checks:     convertCheckRuns(item.CIStatus.Checks),
+		},
+		PRDetails: output.PRDetails{
+			Additions:    item.PRDetails.Additions,
+			Deletions:    item.PRDetails.Deletions,
+			ChangedFiles: item.PRDetails.ChangedFiles,
+		},
+		Base:       item.Base,
+		Repository: item.Repository,
+		Number:     item.Number,
 
-	type prResult struct {
-		index      int
-		reviewItem output.ReviewItem
-		err        error
+		InMergeQueue: item.InMergeQueue,
 	}
+}
 
-	jobs := make(chan prJob, len(prs))
-	results := make(chan prResult, len(prs))
-
-	// Rate limiting ticker
-	ticker := time.NewTicker(rateLimitDelay)
-	defer ticker.Stop()
-
-	// Start worker goroutines
-	var wg sync.WaitGroup
-	for w := 0; w < min(maxWorkers, len(prs)); w++ {
-		wg.Add(1)
-		go func(workerID int) {
-			defer wg.Done()
-
-			for job := range jobs {
-				// Wait for rate limit
-				<-ticker.C
-
-				if verbose {
-					fmt.Printf("  ⏳ [%d/%d] Worker %d processing PR #%d: %s...\n",
-						job.index+1, len(prs), workerID+1, job.pr.Number,
-						job.pr.Title[:min(50, len(job.pr.Title))])
-				}
-
-				reviewItem, err := enrichPRWithDetails(ctx, provider, job.pr)
-				if err != nil {
-					if verbose {
-						fmt.Printf("    ⚠️  Worker %d failed to enrich PR %s: %v\n", workerID+1, job.pr.ID, err)
-					}
-					// Create fallback item
-					reviewItem = output.ReviewItem{
-						TodoItem: output.TodoItem{
-							ID:          job.pr.ID,
-							Title:       job.pr.Title,
-							Description: job.pr.Description,
-							URL:         job.pr.URL,
-							UpdatedAt:   job.pr.UpdatedAt,
-							Tags:        job.pr.Tags,
-						},
-					}
-				}
-
-				results <- prResult{
-					index:      job.index,
-					reviewItem: reviewItem,
-					err:        err,
-				}
+// prCommentFetcher loads PR comment thread previews for the reviews TUI
+func prCommentFetcher(provider *github.Provider) output.CommentFetcher {
+	return func(ctx context.Context, repo string, number, limit int) ([]output.PRComment, error) {
+		comments, err := provider.GetPRComments(ctx, repo, number, limit)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]output.PRComment, len(comments))
+		for i, comment := range comments {
+			result[i] = output.PRComment{
+				Author:    comment.Author,
+				Body:      comment.Body,
+				URL:       comment.URL,
+				CreatedAt: comment.CreatedAt,
+				Path:      comment.Path,
 			}
-		}(w)
+		}
+		return result, nil
 	}
+}
 
-	// Send jobs
-	go func() {
-		defer close(jobs)
-		for i, pr := range prs {
-			jobs <- prJob{index: i, pr: pr}
+// markStaleReviews computes the age of each review item and flags the ones that
+// have been waiting for more than staleAfterDays days
+func markStaleReviews(items []output.ReviewItem, staleAfterDays int, now time.Time) {
+	threshold := time.Duration(staleAfterDays) * 24 * time.Hour
+	for i := range items {
+		age := now.Sub(items[i].TodoItem.UpdatedAt)
+		if age < 0 {
+			age = 0
 		}
-	}()
+		items[i].AgeDays = int(age.Hours() / 24)
+		items[i].IsStale = age > threshold
+	}
+}
 
-	// Collect results
-	reviewItems := make([]output.ReviewItem, len(prs))
-	successCount := 0
+// matchBaseBranch reports whether a PR base branch matches a glob pattern such as "release/*".
+// As with paths, "*" does not match "/", so "release/*" matches "release/1.2" but not "release/1.2/hotfix".
+func matchBaseBranch(pattern, base string) bool {
+	matched, err := path.Match(pattern, base)
+	return err == nil && matched
+}
 
-	for i := 0; i < len(prs); i++ {
-		result := <-results
-		reviewItems[result.index] = result.reviewItem
-		if result.err == nil {
-			successCount++
+// filterReviewsByBase keeps the review items whose base branch matches the glob pattern
+func filterReviewsByBase(items []output.ReviewItem, pattern string) []output.ReviewItem {
+	filtered := make([]output.ReviewItem, 0, len(items))
+	for _, item := range items {
+		if matchBaseBranch(pattern, item.Base) {
+			filtered = append(filtered, item)
 		}
 	}
+	return filtered
+}
 
-	// Wait for all workers to complete
-	wg.Wait()
-
-	if verbose {
-		fmt.Printf("  ✅ Completed %s requests: %d successful, %d failed\n",
-			requestType, successCount, len(prs)-successCount)
+func convertCheckRuns(providerChecks []provider.CheckRun) []output.CheckRun {
+	checks := make([]output.CheckRun, len(providerChecks))
+	for i, check := range providerChecks {
+		checks[i] = output.CheckRun{
+			Name:       check.Name,
+			Status:     check.Status,
+			Conclusion: check.Conclusion,
+			URL:        check.URL,
+		}
 	}
-
-	return reviewItems
+	return checks
 }
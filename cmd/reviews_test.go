@@ -1,64 +1,22 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"daily/internal/metrics"
+	"daily/internal/output"
 	"daily/internal/provider"
-	"daily/internal/provider/github"
 )
 
-func TestGetGitHubReviews(t *testing.T) {
-	tests := []struct {
-		name           string
-		config         provider.Config
-		expectError    bool
-		expectedErrMsg string
-	}{
-		{
-			name: "unconfigured provider",
-			config: provider.Config{
-				Username: "",
-				Token:    "",
-				Enabled:  false,
-			},
-			expectError:    true,
-			expectedErrMsg: "failed to get user review requests: GitHub provider not configured",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			provider := github.NewProvider(tt.config)
-
-			reviews, err := getGitHubReviews(context.Background(), provider, false, false)
-
-			if tt.expectError {
-				if err == nil {
-					t.Error("Expected error, got nil")
-				}
-				if tt.expectedErrMsg != "" && !strings.Contains(err.Error(), tt.expectedErrMsg) {
-					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrMsg, err.Error())
-				}
-			} else {
-				if err != nil {
-					t.Errorf("Expected no error, got: %v", err)
-				}
-				// Verify the structure
-				if reviews.UserRequests == nil {
-					t.Error("Expected non-nil UserRequests slice")
-				}
-				if reviews.TeamRequests == nil {
-					t.Error("Expected non-nil TeamRequests slice")
-				}
-			}
-		})
-	}
-}
-
 func TestReviewsCmd_Creation(t *testing.T) {
 	cmd := ReviewsCmd()
 
@@ -112,45 +70,8 @@ func TestReviewsCmd_FlagValidation(t *testing.T) {
 	}
 }
 
-func TestEnrichPRWithDetails(t *testing.T) {
-	// This tests the structure of enrichPRWithDetails without making real API calls
-	pr := github.TodoItem{
-		ID:          "test-pr-1",
-		Title:       "Test PR Title",
-		Description: "Test PR Description",
-		URL:         "https://github.com/owner/repo/pull/123",
-		Number:      123,
-		Repository:  "owner/repo",
-		UpdatedAt:   time.Now(),
-		Tags:        []string{"bug", "high-priority"},
-	}
-
-	// Test with unconfigured provider (should return error)
-	config := provider.Config{
-		Username: "",
-		Token:    "",
-		Enabled:  false,
-	}
-	provider := github.NewProvider(config)
-
-	reviewItem, err := enrichPRWithDetails(context.Background(), provider, pr)
-
-	// Should get an error due to unconfigured provider
-	if err == nil {
-		t.Error("Expected error for unconfigured provider, got nil")
-	}
-
-	// But should still return a valid review item structure
-	if reviewItem.TodoItem.ID != pr.ID {
-		t.Errorf("Expected ID %s, got %s", pr.ID, reviewItem.TodoItem.ID)
-	}
-	if reviewItem.TodoItem.Title != pr.Title {
-		t.Errorf("Expected Title %s, got %s", pr.Title, reviewItem.TodoItem.Title)
-	}
-}
-
 func TestConvertCheckRuns(t *testing.T) {
-	githubChecks := []github.CheckRun{
+	providerChecks := []provider.CheckRun{
 		{
 			Name:       "CI",
 			Status:     "completed",
@@ -165,194 +86,28 @@ func TestConvertCheckRuns(t *testing.T) {
 		},
 	}
 
-	outputChecks := convertCheckRuns(githubChecks)
+	outputChecks := convertCheckRuns(providerChecks)
 
-	if len(outputChecks) != len(githubChecks) {
-		t.Errorf("Expected %d checks, got %d", len(githubChecks), len(outputChecks))
+	if len(outputChecks) != len(providerChecks) {
+		t.Errorf("Expected %d checks, got %d", len(providerChecks), len(outputChecks))
 	}
 
 	for i, check := range outputChecks {
-		if check.Name != githubChecks[i].Name {
-			t.Errorf("Expected check %d name %s, got %s", i, githubChecks[i].Name, check.Name)
+		if check.Name != providerChecks[i].Name {
+			t.Errorf("Expected check %d name %s, got %s", i, providerChecks[i].Name, check.Name)
 		}
-		if check.Status != githubChecks[i].Status {
-			t.Errorf("Expected check %d status %s, got %s", i, githubChecks[i].Status, check.Status)
+		if check.Status != providerChecks[i].Status {
+			t.Errorf("Expected check %d status %s, got %s", i, providerChecks[i].Status, check.Status)
 		}
-		if check.Conclusion != githubChecks[i].Conclusion {
-			t.Errorf("Expected check %d conclusion %s, got %s", i, githubChecks[i].Conclusion, check.Conclusion)
+		if check.Conclusion != providerChecks[i].Conclusion {
+			t.Errorf("Expected check %d conclusion %s, got %s", i, providerChecks[i].Conclusion, check.Conclusion)
 		}
-		if check.URL != githubChecks[i].URL {
-			t.Errorf("Expected check %d URL %s, got %s", i, githubChecks[i].URL, check.URL)
-		}
-	}
-}
-
-func TestMinFunction(t *testing.T) {
-	tests := []struct {
-		a, b, expected int
-	}{
-		{5, 3, 3},
-		{1, 10, 1},
-		{7, 7, 7},
-		{0, 5, 0},
-		{-1, 2, -1},
-	}
-
-	for _, tt := range tests {
-		result := min(tt.a, tt.b)
-		if result != tt.expected {
-			t.Errorf("min(%d, %d) = %d, expected %d", tt.a, tt.b, result, tt.expected)
+		if check.URL != providerChecks[i].URL {
+			t.Errorf("Expected check %d URL %s, got %s", i, providerChecks[i].URL, check.URL)
 		}
 	}
 }
 
-func TestEnrichPRsConcurrently_EmptySlice(t *testing.T) {
-	config := provider.Config{
-		Username: "testuser",
-		Token:    "testtoken",
-		Enabled:  true,
-	}
-	provider := github.NewProvider(config)
-
-	emptyPRs := []github.TodoItem{}
-	result := enrichPRsConcurrently(context.Background(), provider, emptyPRs, "test", false)
-
-	if len(result) != 0 {
-		t.Errorf("Expected empty result for empty input, got %d items", len(result))
-	}
-}
-
-func TestEnrichPRsConcurrently_ConcurrencyLimits(t *testing.T) {
-	config := provider.Config{
-		Username: "testuser",
-		Token:    "testtoken",
-		Enabled:  true,
-	}
-	provider := github.NewProvider(config)
-
-	// Create a slice with more PRs than max workers
-	prs := make([]github.TodoItem, 10)
-	for i := 0; i < 10; i++ {
-		prs[i] = github.TodoItem{
-			ID:          "pr-" + string(rune(i+'1')),
-			Title:       "Test PR " + string(rune(i+'1')),
-			Description: "Test Description",
-			URL:         "https://github.com/owner/repo/pull/" + string(rune(i+'1')),
-			Number:      i + 1,
-			Repository:  "owner/repo",
-			UpdatedAt:   time.Now(),
-		}
-	}
-
-	// This will fail with unconfigured credentials but should not panic
-	// and should return the same number of items as input
-	result := enrichPRsConcurrently(context.Background(), provider, prs, "test", false)
-
-	if len(result) != len(prs) {
-		t.Errorf("Expected %d results, got %d", len(prs), len(result))
-	}
-
-	// Verify that all items have the basic TodoItem structure preserved
-	for i, item := range result {
-		if item.TodoItem.ID != prs[i].ID {
-			t.Errorf("Item %d: expected ID %s, got %s", i, prs[i].ID, item.TodoItem.ID)
-		}
-	}
-}
-
-func TestEnrichPRsConcurrently_RateLimiting(t *testing.T) {
-	config := provider.Config{
-		Username: "testuser",
-		Token:    "testtoken",
-		Enabled:  true,
-	}
-	provider := github.NewProvider(config)
-
-	// Create a few PRs to test rate limiting timing
-	prs := make([]github.TodoItem, 3)
-	for i := 0; i < 3; i++ {
-		prs[i] = github.TodoItem{
-			ID:          "pr-" + string(rune(i+'1')),
-			Title:       "Test PR " + string(rune(i+'1')),
-			Description: "Test Description",
-			URL:         "https://github.com/owner/repo/pull/" + string(rune(i+'1')),
-			Number:      i + 1,
-			Repository:  "owner/repo",
-			UpdatedAt:   time.Now(),
-		}
-	}
-
-	start := time.Now()
-	result := enrichPRsConcurrently(context.Background(), provider, prs, "test", false)
-	elapsed := time.Since(start)
-
-	// With 200ms rate limiting, processing 3 items should take at least 400ms
-	// (3 ticks minimum, but we give some tolerance for test timing)
-	if elapsed < 300*time.Millisecond {
-		t.Errorf("Expected rate limiting to cause delay of at least 300ms, got %v", elapsed)
-	}
-
-	if len(result) != len(prs) {
-		t.Errorf("Expected %d results, got %d", len(prs), len(result))
-	}
-}
-
-func TestEnrichPRsConcurrently_ContextCancellation(t *testing.T) {
-	config := provider.Config{
-		Username: "testuser",
-		Token:    "testtoken",
-		Enabled:  true,
-	}
-	provider := github.NewProvider(config)
-
-	prs := make([]github.TodoItem, 5)
-	for i := 0; i < 5; i++ {
-		prs[i] = github.TodoItem{
-			ID:          "pr-" + string(rune(i+'1')),
-			Title:       "Test PR " + string(rune(i+'1')),
-			Description: "Test Description",
-			URL:         "https://github.com/owner/repo/pull/" + string(rune(i+'1')),
-			Number:      i + 1,
-			Repository:  "owner/repo",
-			UpdatedAt:   time.Now(),
-		}
-	}
-
-	// Create a context that will be cancelled quickly
-	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
-	defer cancel()
-
-	result := enrichPRsConcurrently(ctx, provider, prs, "test", false)
-
-	// Should still return results even with cancelled context
-	// The workers might complete some items before context cancellation
-	if len(result) != len(prs) {
-		t.Errorf("Expected %d results even with cancelled context, got %d", len(prs), len(result))
-	}
-}
-
-func TestGetGitHubReviews_SkipDetails(t *testing.T) {
-	config := provider.Config{
-		Username: "testuser",
-		Token:    "testtoken",
-		Enabled:  true,
-	}
-	provider := github.NewProvider(config)
-
-	// This will fail due to fake credentials, but we test that skip-details path works
-	_, err := getGitHubReviews(context.Background(), provider, false, true)
-
-	// Should get error from the initial API calls, not from details fetching
-	if err == nil {
-		t.Error("Expected error due to fake credentials, got nil")
-	}
-
-	// Error should be from user review requests, not from enrichment
-	if !strings.Contains(err.Error(), "failed to get user review requests") {
-		t.Errorf("Expected error from user review requests, got: %v", err)
-	}
-}
-
 // Mock helper for testing worker coordination
 func TestWorkerPoolBehavior(t *testing.T) {
 	// Test that worker pool properly coordinates work distribution
@@ -413,3 +168,299 @@ func TestWorkerPoolBehavior(t *testing.T) {
 		t.Errorf("Expected sum %d, got %d", expectedSum, actualSum)
 	}
 }
+
+func TestMarkStaleReviews(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	items := []output.ReviewItem{
+		{TodoItem: output.TodoItem{ID: "fresh", UpdatedAt: now.Add(-6 * time.Hour)}},
+		{TodoItem: output.TodoItem{ID: "exactly-threshold", UpdatedAt: now.Add(-72 * time.Hour)}},
+		{TodoItem: output.TodoItem{ID: "stale", UpdatedAt: now.Add(-5 * 24 * time.Hour)}},
+		{TodoItem: output.TodoItem{ID: "future", UpdatedAt: now.Add(time.Hour)}},
+	}
+
+	markStaleReviews(items, 3, now)
+
+	expected := map[string]struct {
+		ageDays int
+		isStale bool
+	}{
+		"fresh":             {0, false},
+		"exactly-threshold": {3, false},
+		"stale":             {5, true},
+		"future":            {0, false},
+	}
+
+	for _, item := range items {
+		want := expected[item.TodoItem.ID]
+		if item.AgeDays != want.ageDays {
+			t.Errorf("%s: expected AgeDays %d, got %d", item.TodoItem.ID, want.ageDays, item.AgeDays)
+		}
+		if item.IsStale != want.isStale {
+			t.Errorf("%s: expected IsStale %t, got %t", item.TodoItem.ID, want.isStale, item.IsStale)
+		}
+	}
+}
+
+func TestMarkStaleReviews_CustomThreshold(t *testing.T) {
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	nowFunc = func() time.Time { return time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC) }
+
+	items := []output.ReviewItem{
+		{TodoItem: output.TodoItem{ID: "two-days", UpdatedAt: nowFunc().Add(-50 * time.Hour)}},
+	}
+
+	markStaleReviews(items, 1, nowFunc())
+
+	if !items[0].IsStale {
+		t.Error("Expected item older than a 1-day threshold to be stale")
+	}
+	if items[0].AgeDays != 2 {
+		t.Errorf("Expected AgeDays 2, got %d", items[0].AgeDays)
+	}
+}
+
+func TestMatchBaseBranch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		base     string
+		expected bool
+	}{
+		{"release/*", "release/1.2", true},
+		{"release/*", "release/1.2/hotfix", false},
+		{"release/*", "main", false},
+		{"main", "main", true},
+		{"release-?.?", "release-1.2", true},
+		{"*", "main", true},
+		{"release/*", "", false},
+		{"[", "main", false}, // Invalid pattern never matches
+	}
+
+	for _, tt := range tests {
+		if result := matchBaseBranch(tt.pattern, tt.base); result != tt.expected {
+			t.Errorf("matchBaseBranch(%q, %q): expected %t, got %t", tt.pattern, tt.base, tt.expected, result)
+		}
+	}
+}
+
+func TestFilterReviewsByBase(t *testing.T) {
+	items := []output.ReviewItem{
+		{TodoItem: output.TodoItem{ID: "release"}, Base: "release/1.2"},
+		{TodoItem: output.TodoItem{ID: "main"}, Base: "main"},
+		{TodoItem: output.TodoItem{ID: "older-release"}, Base: "release/1.1"},
+		{TodoItem: output.TodoItem{ID: "unknown"}},
+	}
+
+	filtered := filterReviewsByBase(items, "release/*")
+
+	if len(filtered) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(filtered))
+	}
+	if filtered[0].TodoItem.ID != "release" || filtered[1].TodoItem.ID != "older-release" {
+		t.Errorf("Expected release items in original order, got %s and %s", filtered[0].TodoItem.ID, filtered[1].TodoItem.ID)
+	}
+}
+
+func TestReviewsCmd_BaseFlagValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{"invalid pattern", []string{"--base", "["}, "invalid --base pattern"},
+		{"with skip details", []string{"--base", "release/*", "--skip-details"}, "--base cannot be used with --skip-details"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := ReviewsCmd()
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErr, err.Error())
+			}
+		})
+	}
+}
+
+// fakeReviewSource returns fixed review items and sets the base branch of the ones it enriches
+type fakeReviewSource struct {
+	name  string
+	items []provider.ReviewItem
+	err   error
+
+	mu       sync.Mutex
+	enriched []string
+}
+
+func (f *fakeReviewSource) Name() string       { return f.name }
+func (f *fakeReviewSource) IsConfigured() bool { return true }
+
+func (f *fakeReviewSource) GetReviewRequests(ctx context.Context) ([]provider.ReviewItem, error) {
+	return f.items, f.err
+}
+
+func (f *fakeReviewSource) EnrichReview(ctx context.Context, item provider.ReviewItem) (provider.ReviewItem, error) {
+	f.mu.Lock()
+	f.enriched = append(f.enriched, item.ID)
+	f.mu.Unlock()
+	item.Base = "release/" + item.ID
+	return item, nil
+}
+
+// plainReviewSource is a review source that can't enrich its items
+type plainReviewSource struct {
+	items []provider.ReviewItem
+}
+
+func (p plainReviewSource) Name() string       { return "plain" }
+func (p plainReviewSource) IsConfigured() bool { return true }
+
+func (p plainReviewSource) GetReviewRequests(ctx context.Context) ([]provider.ReviewItem, error) {
+	return p.items, nil
+}
+
+func TestCollectReviews(t *testing.T) {
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	nowFunc = func() time.Time { return now }
+
+	forge := &fakeReviewSource{
+		name: "forge",
+		items: []provider.ReviewItem{
+			{TodoItem: provider.TodoItem{ID: "1", UpdatedAt: now.Add(-2 * 24 * time.Hour)}, Platform: "forge", RequestType: provider.ReviewRequestUser},
+			{TodoItem: provider.TodoItem{ID: "2", UpdatedAt: now}, Platform: "forge", RequestType: provider.ReviewRequestTeam},
+		},
+	}
+	failing := &fakeReviewSource{name: "failing", err: errors.New("unauthorized")}
+	plain := plainReviewSource{items: []provider.ReviewItem{
+		{TodoItem: provider.TodoItem{ID: "3", UpdatedAt: now.Add(-2 * 24 * time.Hour)}, Platform: "plain", RequestType: provider.ReviewRequestUser},
+	}}
+
+	sources := []reviewSource{
+		{source: forge, staleAfterDays: 1},
+		{source: failing},
+		{source: plain},
+	}
+
+	items := collectReviews(context.Background(), sources, reviewOptions{})
+
+	// Failing sources are skipped and the others keep their order
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 review items, got %d", len(items))
+	}
+	for i, id := range []string{"1", "2", "3"} {
+		if items[i].TodoItem.ID != id {
+			t.Errorf("Item %d: expected ID %s, got %s", i, id, items[i].TodoItem.ID)
+		}
+	}
+
+	if items[0].Platform != "forge" || items[0].RequestType != output.ReviewRequestUser {
+		t.Errorf("Expected platform and request type to be kept, got %+v", items[0])
+	}
+	if items[1].RequestType != output.ReviewRequestTeam {
+		t.Errorf("Expected team request, got '%s'", items[1].RequestType)
+	}
+
+	// Sources that implement ReviewEnricher are enriched
+	if items[0].Base != "release/1" || items[1].Base != "release/2" {
+		t.Errorf("Expected forge items to be enriched, got bases '%s' and '%s'", items[0].Base, items[1].Base)
+	}
+	if items[2].Base != "" {
+		t.Errorf("Expected items of a source without enricher to be kept as is, got base '%s'", items[2].Base)
+	}
+
+	// Stale thresholds are per source, with the default for sources that don't set one
+	if !items[0].IsStale {
+		t.Error("Expected 2-day-old item to be stale with a 1-day threshold")
+	}
+	if items[2].IsStale || items[2].AgeDays != 2 {
+		t.Errorf("Expected 2-day-old item not to be stale with the default threshold, got %+v", items[2])
+	}
+}
+
+func TestCollectReviews_Options(t *testing.T) {
+	newSource := func() *fakeReviewSource {
+		return &fakeReviewSource{
+			name: "forge",
+			items: []provider.ReviewItem{
+				{TodoItem: provider.TodoItem{ID: "1"}, RequestType: provider.ReviewRequestUser},
+				{TodoItem: provider.TodoItem{ID: "2"}, RequestType: provider.ReviewRequestUser},
+			},
+		}
+	}
+
+	source := newSource()
+	items := collectReviews(context.Background(), []reviewSource{{source: source}}, reviewOptions{skipDetails: true})
+	if len(source.enriched) != 0 {
+		t.Errorf("Expected no enrichment with skipDetails, got %v", source.enriched)
+	}
+	if len(items) != 2 {
+		t.Errorf("Expected 2 items, got %d", len(items))
+	}
+
+	items = collectReviews(context.Background(), []reviewSource{{source: newSource()}}, reviewOptions{base: "release/2"})
+	if len(items) != 1 || items[0].TodoItem.ID != "2" {
+		t.Errorf("Expected only the item targeting release/2, got %+v", items)
+	}
+
+	items = collectReviews(context.Background(), nil, reviewOptions{})
+	if items == nil || len(items) != 0 {
+		t.Errorf("Expected an empty non-nil list without sources, got %#v", items)
+	}
+}
+
+func TestCollectReviews_Progress(t *testing.T) {
+	recorder := metrics.NewRecorder()
+	var stream bytes.Buffer
+	recorder.SetProgress(&stream)
+	ctx := metrics.WithRecorder(context.Background(), recorder)
+
+	forge := &fakeReviewSource{name: "forge", items: []provider.ReviewItem{
+		{TodoItem: provider.TodoItem{ID: "1"}},
+		{TodoItem: provider.TodoItem{ID: "2"}},
+	}}
+	failing := &fakeReviewSource{name: "failing", err: errors.New("unauthorized")}
+
+	collectReviews(ctx, []reviewSource{{source: forge}, {source: failing}}, reviewOptions{})
+
+	var events []metrics.Event
+	for _, line := range strings.Split(strings.TrimSuffix(stream.String(), "\n"), "\n") {
+		var event metrics.Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Expected one JSON event per line, got %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+
+	expected := []string{
+		"provider_started forge",
+		"enrichment_progress forge 1/2",
+		"enrichment_progress forge 2/2",
+		"provider_finished forge 2",
+		"provider_started failing",
+		"provider_finished failing unauthorized",
+	}
+	var got []string
+	for _, event := range events {
+		summary := fmt.Sprintf("%s %s", event.Type, event.Provider)
+		switch {
+		case event.Type == metrics.EventEnrichmentProgress:
+			summary += fmt.Sprintf(" %d/%d", event.Done, event.Total)
+		case event.Error != "":
+			summary += " " + event.Error
+		case event.Count != nil:
+			summary += fmt.Sprintf(" %d", *event.Count)
+		}
+		got = append(got, summary)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected events %v, got %v", expected, got)
+	}
+}
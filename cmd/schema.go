@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/metrics"
+)
+
+// schema is a JSON Schema printed by the schema command
+type schema struct {
+	description string
+	document    string
+}
+
+// schemas are the schemas of the machine-readable streams of the CLI, by name
+var schemas = map[string]schema{
+	"progress": {
+		description: "Events streamed to stderr with --progress json",
+		document:    metrics.ProgressSchema,
+	},
+}
+
+func SchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema [name]",
+		Short: "Print the JSON Schema of machine-readable output",
+		Long: `Print the JSON Schema of a machine-readable stream of the CLI, or list the available schemas.
+
+Schemas:
+  progress   Events streamed to stderr with --progress json by sum, todo, reviews and mentions.
+             Each line is one event: provider_started, provider_finished (with the item count,
+             duration and error), enrichment_progress (done/total) and a final run_finished
+             with the metrics of the run.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names := slices.Sorted(maps.Keys(schemas))
+
+			if len(args) == 0 {
+				for _, name := range names {
+					fmt.Printf("%-10s %s\n", name, schemas[name].description)
+				}
+				return nil
+			}
+
+			s, ok := schemas[args[0]]
+			if !ok {
+				return fmt.Errorf("unknown schema: %s (available: %s)", args[0], strings.Join(names, ", "))
+			}
+			fmt.Print(s.document)
+			return nil
+		},
+	}
+}
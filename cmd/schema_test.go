@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSchemas(t *testing.T) {
+	for name, s := range schemas {
+		if !json.Valid([]byte(s.document)) {
+			t.Errorf("Expected schema %s to be valid JSON", name)
+		}
+		if s.description == "" {
+			t.Errorf("Expected schema %s to have a description", name)
+		}
+	}
+}
+
+func TestSchemaCmd_UnknownSchema(t *testing.T) {
+	cmd := SchemaCmd()
+	cmd.SetArgs([]string{"nope"})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "unknown schema: nope (available: progress)") {
+		t.Errorf("Expected an unknown schema error, got: %v", err)
+	}
+}
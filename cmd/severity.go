@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"daily/internal/output"
+	"daily/internal/severity"
+)
+
+// setSeverity resolves the severity of the items and their subtasks from their priority and tags
+func setSeverity(items []output.TodoItem, rules severity.Rules) {
+	for i := range items {
+		items[i].Severity = string(rules.Resolve(items[i].Priority, items[i].Tags))
+		setSeverity(items[i].Subtasks, rules)
+	}
+}
+
+// setTodoSeverity resolves the severity of the items of every todo list
+func setTodoSeverity(todoItems output.TodoItems, rules severity.Rules) {
+	for _, items := range itemSections(todoItems, nil) {
+		setSeverity(items, rules)
+	}
+}
+
+// setReviewSeverity resolves the severity of the review requests from their labels
+func setReviewSeverity(items output.ReviewItems, rules severity.Rules) {
+	for i := range items {
+		items[i].TodoItem.Severity = string(rules.Resolve(items[i].TodoItem.Priority, items[i].TodoItem.Tags))
+	}
+}
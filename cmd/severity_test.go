@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	"daily/internal/output"
+	"daily/internal/severity"
+)
+
+func TestSetTodoSeverity(t *testing.T) {
+	rules, err := severity.ParseRules(map[string]string{"blocker": "red", "p0": "red", "minor": "gray"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	todoItems := output.TodoItems{
+		GitHub: output.GitHubTodos{OpenPRs: []output.TodoItem{
+			{ID: "github-pr-1", Tags: []string{"owner/repo", "open", "P0"}},
+		}},
+		JIRA: output.JIRATodos{AssignedTickets: []output.TodoItem{
+			{ID: "jira-OPS-1", Priority: "Blocker", Subtasks: []output.TodoItem{
+				{ID: "jira-OPS-2", Priority: "Minor"},
+			}},
+			{ID: "jira-OPS-3", Priority: "Medium"},
+		}},
+	}
+	setTodoSeverity(todoItems, rules)
+
+	tests := []struct {
+		item     output.TodoItem
+		expected string
+	}{
+		{todoItems.GitHub.OpenPRs[0], "critical"},
+		{todoItems.JIRA.AssignedTickets[0], "critical"},
+		{todoItems.JIRA.AssignedTickets[0].Subtasks[0], "low"},
+		{todoItems.JIRA.AssignedTickets[1], ""},
+	}
+	for _, tt := range tests {
+		if tt.item.Severity != tt.expected {
+			t.Errorf("Expected severity '%s' for %s, got '%s'", tt.expected, tt.item.ID, tt.item.Severity)
+		}
+	}
+
+	reviewItems := output.ReviewItems{
+		{TodoItem: output.TodoItem{ID: "github-review-1", Tags: []string{"owner/repo", "review-requested", "p0"}}},
+	}
+	setReviewSeverity(reviewItems, rules)
+	if reviewItems[0].TodoItem.Severity != "critical" {
+		t.Errorf("Expected critical review, got '%s'", reviewItems[0].TodoItem.Severity)
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/config"
+)
+
+func StateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Export and import your daily state",
+		Long: "Move the state that represents your choices, such as goals and hidden items, between machines. " +
+			"Cached summaries are not exported, they are rebuilt on each machine.",
+	}
+
+	cmd.AddCommand(stateExportCmd())
+	cmd.AddCommand(stateImportCmd())
+
+	return cmd
+}
+
+func stateExportCmd() *cobra.Command {
+	var outFile string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export your state as JSON",
+		Long:  "Print your state as a versioned JSON document, e.g. 'daily state export > state.json'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			store, err := hiddenStore()
+			if err != nil {
+				return err
+			}
+			hidden, err := store.Load()
+			if err != nil {
+				return err
+			}
+
+			result, err := formatState(cfg.ExportState(hidden))
+			if err != nil {
+				return err
+			}
+			return writeOutput(outFile, result)
+		},
+	}
+
+	cmd.Flags().StringVar(&outFile, "out-file", "", "Write the state to this file instead of stdout ('-' for stdout)")
+
+	return cmd
+}
+
+func stateImportCmd() *cobra.Command {
+	var replace bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import a state exported on another machine",
+		Long: "Merge a state file created with 'daily state export' into your state. Goals set in both " +
+			"take the imported target, and the hidden items of both are kept. Use --replace to overwrite " +
+			"your state with the file instead.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read state file: %w", err)
+			}
+
+			state, err := config.ParseState(data)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			store, err := hiddenStore()
+			if err != nil {
+				return err
+			}
+			hidden, err := store.Load()
+			if err != nil {
+				return err
+			}
+
+			cfg.ImportState(state, &hidden, replace)
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			if err := store.Save(hidden); err != nil {
+				return err
+			}
+
+			fmt.Println(describeImport(state, replace))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&replace, "replace", false, "Overwrite your state instead of merging into it")
+
+	return cmd
+}
+
+// formatState renders an exported state as indented JSON
+func formatState(state config.State) (string, error) {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal state: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// describeImport returns the confirmation printed after importing a state
+func describeImport(state config.State, replace bool) string {
+	goals := len(state.Goals.Daily)
+	for _, overrides := range state.Goals.Weekdays {
+		goals += len(overrides)
+	}
+
+	hidden := len(state.Hidden.IDs) + len(state.Hidden.Rules)
+
+	action := "Merged"
+	if replace {
+		action = "Replaced state with"
+	}
+	return fmt.Sprintf("✅ %s %d goal targets and %d hidden items", action, goals, hidden)
+}
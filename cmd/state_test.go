@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"daily/internal/config"
+	"daily/internal/hide"
+)
+
+func TestFormatState(t *testing.T) {
+	state := config.State{
+		Version: config.StateVersion,
+		Goals:   config.GoalsConfig{Daily: map[string]int{"commits": 5}},
+	}
+
+	result, err := formatState(state)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	parsed, err := config.ParseState([]byte(result))
+	if err != nil {
+		t.Fatalf("Expected exported state to be importable, got: %v", err)
+	}
+	if parsed.Goals.Daily["commits"] != 5 {
+		t.Errorf("Expected commits goal to round-trip, got %v", parsed.Goals.Daily)
+	}
+	if !strings.Contains(result, `"version": 1`) {
+		t.Errorf("Expected versioned envelope, got: %s", result)
+	}
+}
+
+func TestDescribeImport(t *testing.T) {
+	state := config.State{
+		Version: config.StateVersion,
+		Goals: config.GoalsConfig{
+			Daily:    map[string]int{"commits": 5, "reviews": 3},
+			Weekdays: map[string]map[string]int{"friday": {"commits": 2}},
+		},
+		Hidden: hide.State{IDs: []hide.Entry{{Ref: "jira-PROJ-1"}}, Rules: []hide.Rule{{Pattern: "dependabot"}}},
+	}
+
+	if got := describeImport(state, false); got != "✅ Merged 3 goal targets and 2 hidden items" {
+		t.Errorf("Expected merge message, got '%s'", got)
+	}
+	if got := describeImport(state, true); got != "✅ Replaced state with 3 goal targets and 2 hidden items" {
+		t.Errorf("Expected replace message, got '%s'", got)
+	}
+}
@@ -2,77 +2,113 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"regexp"
-	"strconv"
+	"io"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"daily/internal/activity"
-	"daily/internal/cache"
 	"daily/internal/config"
+	"daily/internal/datetime"
+	"daily/internal/metrics"
 	"daily/internal/output"
 	"daily/internal/provider"
+	"daily/internal/provider/calendar"
 	"daily/internal/provider/confluence"
 	"daily/internal/provider/github"
+	"daily/internal/provider/gitlab"
 	"daily/internal/provider/jira"
 	"daily/internal/provider/obsidian"
+	"daily/internal/provider/savedquery"
+	"daily/internal/provider/slack"
 	"daily/internal/tui"
+	"daily/internal/verboselog"
 )
 
-// sinceDurationRe is a compiled regex for parsing since duration format (e.g., "1d", "2w")
-var sinceDurationRe = regexp.MustCompile(`^(\d+)([hdwm])$`)
-
 func SumCmd() *cobra.Command {
 	var date string
 	var since string
 	var compact bool
+	var groupBy string
 	var verbose bool
 	var outputFormat string
+	var outFile string
+	var progress string
+	var maxItems int
 
 	cmd := &cobra.Command{
 		Use:   "sum",
 		Short: "Get a summary of your daily work activities",
 		Long:  "Gather activity data from JIRA, GitHub, and Obsidian to provide a comprehensive summary of your work for the specified date.",
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			// Validate output format
 			if outputFormat != "text" && outputFormat != "json" && outputFormat != "tui" {
 				return fmt.Errorf("invalid output format: %s (must be 'text', 'json', or 'tui')", outputFormat)
 			}
+			if groupBy != "" && groupBy != output.GroupByEpic {
+				return fmt.Errorf("invalid group-by value: %s (must be '%s')", groupBy, output.GroupByEpic)
+			}
+			if err := validateOutFile(outputFormat, outFile); err != nil {
+				return err
+			}
+			if err := validateProgress(outputFormat, progress); err != nil {
+				return err
+			}
+			if maxItems < 0 {
+				return fmt.Errorf("invalid max items: %d (must not be negative)", maxItems)
+			}
 
 			// Handle --since and --date mutual exclusivity
 			if since != "" && date != "" {
 				return fmt.Errorf("cannot use both --since and --date flags")
 			}
 
-			// Default to --since 1d if neither flag is provided
+			recorder := newRunRecorder(progress)
+			defer func() { recorder.RunFinished(err) }()
+
+			// Load configuration
+			cfg, err := config.LoadWithTokens()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			// Default to the configured time range (1d unless set) if neither flag is provided
 			if since == "" && date == "" {
-				since = "1d"
+				since = cfg.Sum.DefaultSince()
+			}
+			// --max-items 0 lifts the limit of the config
+			if !cmd.Flags().Changed("max-items") {
+				maxItems = cfg.Sum.MaxItems
 			}
 
 			// Determine if we're using since-based or date-based querying
 			var usingSince bool
 			var fromTime, toTime time.Time
 			var targetDate time.Time
+			var period string
 
 			if since != "" {
 				usingSince = true
-				var err error
-				fromTime, err = parseSinceDuration(since)
+				week, err := cfg.WorkWeek.WorkWeek()
+				if err != nil {
+					return fmt.Errorf("invalid work week: %w", err)
+				}
+				toTime = nowFunc()
+				fromTime, err = datetime.ResolveSince(since, toTime, week)
 				if err != nil {
 					return fmt.Errorf("invalid since format: %w", err)
 				}
-				toTime = time.Now()
 				targetDate = fromTime // Use from time as the summary date
+				period = datetime.DescribeWindow(fromTime, toTime)
 
 				if outputFormat == "text" {
-					fmt.Printf("Gathering activities since %s (%s to now)...\n", since, fromTime.Format("2006-01-02 15:04"))
+					fmt.Printf("Gathering activities since %s (%s)...\n", since, period)
 				}
 			} else {
 				usingSince = false
-				var err error
-				targetDate, err = parseDate(date)
+				targetDate, err = parseDate(date, cfg.DateOrder())
 				if err != nil {
 					return fmt.Errorf("invalid date format: %w", err)
 				}
@@ -83,26 +119,40 @@ func SumCmd() *cobra.Command {
 			}
 
 			// Initialize cache
-			summaryCache, err := cache.NewCache()
+			summaryCache, err := newSummaryCache(cfg)
 			if err != nil {
 				return fmt.Errorf("failed to initialize cache: %w", err)
 			}
+			// Verbose output is only shown with text output, the other formats being parsed or full screen
+			showVerbose := verbose && outputFormat == "text"
+			var reporter *verboselog.Reporter
+			if showVerbose {
+				reporter = verboselog.Stderr()
+			}
+			autoPruneCache(cfg, reporter)
+
+			ctx := metrics.WithRecorder(context.Background(), recorder)
+			translator := newTranslator(cfg)
 
 			// Check cache first for historical dates (only when using date-based queries)
 			if !usingSince && summaryCache.ShouldCache(targetDate) {
-				if cachedSummary, err := summaryCache.Get(targetDate); err != nil {
-					if outputFormat == "text" && verbose {
-						fmt.Printf("Cache read error (proceeding with fresh data): %v\n", err)
-					}
+				cachedSummary, err := summaryCache.Get(targetDate)
+				recorder.Cache("summary", err == nil && cachedSummary != nil)
+				if err != nil {
+					reporter.Warn("Cache read error, proceeding with fresh data: %v", err)
 				} else if cachedSummary != nil {
-					if outputFormat == "text" && verbose {
-						fmt.Printf("📋 Using cached summary for %s\n\n", targetDate.Format("2006-01-02"))
-					}
+					cachedSummary.Goals = activity.ComputeGoalProgress(cfg.Goals.TargetsFor(targetDate.Weekday()), cachedSummary.Activities)
+
+					reporter.Step("Using the cached summary of %s", targetDate.Format("2006-01-02"))
+					translateSummary(ctx, translator, cachedSummary, reporter)
 					// Format and display cached results
 					switch outputFormat {
 					case "tui":
 						err := tui.RunTUI(cachedSummary)
 						if err != nil {
+							if errors.Is(err, tui.ErrDumbTerminal) {
+								return err
+							}
 							// Fallback to text output if TUI fails
 							formatter := output.NewFormatter()
 							result := formatter.FormatSummary(cachedSummary)
@@ -111,111 +161,88 @@ func SumCmd() *cobra.Command {
 						return nil
 					case "json":
 						formatter := output.NewFormatter()
-						result := formatter.FormatJSON(cachedSummary)
-						fmt.Print(result)
+						formatter.SetMeta(recorder.Report())
+						formatter.SetMaxItems(maxItems)
+						return streamOutput(outFile, func(w io.Writer) error {
+							return formatter.WriteJSON(w, cachedSummary)
+						})
 					case "text":
 						formatter := output.NewFormatter()
+						formatter.SetGroupBy(groupBy)
+						formatter.SetMaxItems(maxItems)
 						var result string
 						if compact {
 							result = formatter.FormatCompactSummary(cachedSummary)
 						} else {
 							result = formatter.FormatSummary(cachedSummary)
 						}
-						fmt.Print(result)
+						if err := writeOutput(outFile, result); err != nil {
+							return err
+						}
+						if showVerbose {
+							printRunReport(recorder)
+						}
+						return nil
 					}
 					return nil
 				}
 			}
 
-			// Load configuration
-			cfg, err := config.Load()
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
+			if err := validateJIRAFilters(ctx, cfg, reporter); err != nil {
+				return err
 			}
 
 			// Create providers
-			aggregator := provider.NewAggregator()
-
-			showVerbose := verbose && outputFormat == "text"
-
-			if cfg.GitHub.Enabled {
-				if showVerbose {
-					fmt.Println("✓ GitHub provider enabled")
-				}
-				aggregator.AddProvider(github.NewProvider(cfg.GitHub))
-			} else if showVerbose {
-				fmt.Println("✗ GitHub provider disabled")
-			}
-
-			if cfg.JIRA.Enabled {
-				if showVerbose {
-					fmt.Println("✓ JIRA provider enabled")
-				}
-				aggregator.AddProvider(jira.NewProvider(cfg.JIRA))
-			} else if showVerbose {
-				fmt.Println("✗ JIRA provider disabled")
-			}
-
-			if cfg.Obsidian.Enabled {
-				if showVerbose {
-					fmt.Println("✓ Obsidian provider enabled")
-				}
-				aggregator.AddProvider(obsidian.NewProvider(cfg.Obsidian))
-			} else if showVerbose {
-				fmt.Println("✗ Obsidian provider disabled")
-			}
-
-			if cfg.Confluence.Enabled {
-				if showVerbose {
-					fmt.Println("✓ Confluence provider enabled")
-				}
-				aggregator.AddProvider(confluence.NewProvider(cfg.Confluence))
-			} else if showVerbose {
-				fmt.Println("✗ Confluence provider disabled")
-			}
-
-			// Get summary
-			ctx := context.Background()
-			if showVerbose {
-				fmt.Println()
-			}
+			aggregator := newAggregator(cfg, reporter)
+			aggregator.SetProgress(recorder)
 
 			var summary *activity.Summary
 
 			if usingSince {
 				// Use time range method for --since
-				summary, err = aggregator.GetSummaryByTimeRange(ctx, fromTime, toTime, showVerbose)
+				summary, err = aggregator.GetSummaryByTimeRange(ctx, fromTime, toTime, reporter)
 				if err != nil {
 					return fmt.Errorf("failed to get activity summary: %w", err)
 				}
 			} else {
 				// Use date-based method for --date
-				summary, err = aggregator.GetSummaryWithVerbose(ctx, targetDate, showVerbose)
+				summary, err = aggregator.GetSummaryWithVerbose(ctx, targetDate, reporter)
 				if err != nil {
 					return fmt.Errorf("failed to get activity summary: %w", err)
 				}
 			}
 
-			if showVerbose {
-				fmt.Printf("\n📊 Retrieved %d total activities\n\n", len(summary.Activities))
-			}
+			reporter.Step("Retrieved %d activities", len(summary.Activities))
 
 			// Cache the summary if it's for a historical date (only for date-based queries)
 			if !usingSince && summaryCache.ShouldCache(targetDate) {
 				if err := summaryCache.Set(targetDate, summary); err != nil {
-					if outputFormat == "text" && verbose {
-						fmt.Printf("Warning: Failed to cache summary: %v\n", err)
-					}
-				} else if outputFormat == "text" && verbose {
-					fmt.Printf("💾 Cached summary for future use\n\n")
+					reporter.Warn("Failed to cache the summary: %v", err)
+				} else {
+					reporter.Step("Cached the summary of %s", targetDate.Format("2006-01-02"))
 				}
 			}
 
+			// Goals are computed for the summarized day, or today when looking back with --since
+			goalDay := targetDate
+			if usingSince {
+				goalDay = toTime
+			}
+			summary.Goals = activity.ComputeGoalProgress(cfg.Goals.TargetsFor(goalDay.Weekday()), summary.Activities)
+			if usingSince {
+				summary.Period = period
+				summary.From, summary.To = fromTime, toTime
+			}
+			translateSummary(ctx, translator, summary, reporter)
+
 			// Format and display results
 			switch outputFormat {
 			case "tui":
 				err := tui.RunTUI(summary)
 				if err != nil {
+					if errors.Is(err, tui.ErrDumbTerminal) {
+						return err
+					}
 					// Fallback to text output if TUI fails
 					formatter := output.NewFormatter()
 					result := formatter.FormatSummary(summary)
@@ -224,33 +251,49 @@ func SumCmd() *cobra.Command {
 				return nil
 			case "json":
 				formatter := output.NewFormatter()
-				result := formatter.FormatJSON(summary)
-				fmt.Print(result)
+				formatter.SetMeta(recorder.Report())
+				formatter.SetMaxItems(maxItems)
+				return streamOutput(outFile, func(w io.Writer) error {
+					return formatter.WriteJSON(w, summary)
+				})
 			case "text":
 				formatter := output.NewFormatter()
+				formatter.SetGroupBy(groupBy)
+				formatter.SetMaxItems(maxItems)
 				var result string
 				if compact {
 					result = formatter.FormatCompactSummary(summary)
 				} else {
 					result = formatter.FormatSummary(summary)
 				}
-				fmt.Print(result)
+				if err := writeOutput(outFile, result); err != nil {
+					return err
+				}
+				if showVerbose {
+					printRunReport(recorder)
+				}
+				return nil
 			}
 
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&date, "date", "d", "", "Date to get summary for (yesterday, today, or YYYY-MM-DD)")
-	cmd.Flags().StringVarP(&since, "since", "s", "", "Time range to look back (e.g., 1h, 1d, 2w, 1m). Default: 1d")
+	cmd.Flags().StringVarP(&date, "date", "d", "", "Date to get summary for (yesterday, today, YYYY-MM-DD, or DD/MM/YYYY or MM/DD/YYYY depending on the locale)")
+	cmd.Flags().StringVarP(&since, "since", "s", "", "Time range to look back (e.g., 1h, 1d, 2w, 1m), or 'last-workday' for everything since the end of the previous working day. Default: sum.since from the config, or 1d")
 	cmd.Flags().BoolVarP(&compact, "compact", "c", false, "Use compact output format (text mode only)")
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Group activities within a platform: 'epic' nests JIRA activities under their epic (text mode only)")
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output for debugging (text mode only)")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "tui", "Output format: 'tui', 'text', or 'json'")
+	cmd.Flags().StringVar(&outFile, "out-file", "", "Write output to this file instead of stdout ('-' for stdout, not available with tui)")
+	cmd.Flags().StringVar(&progress, "progress", "", "Stream progress events to stderr: 'json' for one JSON object per line")
+	cmd.Flags().IntVar(&maxItems, "max-items", 0, "List at most this many activities, the most recent, in text and JSON output (default sum.max_items, or no limit; 0 lifts sum.max_items)")
 
 	return cmd
 }
 
-func parseDate(dateStr string) (time.Time, error) {
+// parseDate parses today, yesterday or a date, slashed dates being read in order
+func parseDate(dateStr string, order datetime.DateOrder) (time.Time, error) {
 	now := time.Now()
 
 	switch dateStr {
@@ -259,38 +302,100 @@ func parseDate(dateStr string) (time.Time, error) {
 	case "yesterday":
 		return now.AddDate(0, 0, -1), nil
 	default:
-		return time.Parse("2006-01-02", dateStr)
+		return datetime.ParseDate(dateStr, order)
 	}
 }
 
 // parseSinceDuration parses a "since" duration string (e.g., "1d", "2w", "3h", "1m")
 // and returns the "from" time (now - duration)
 func parseSinceDuration(since string) (time.Time, error) {
-	// Match format: number + unit (h/d/w/m)
-	matches := sinceDurationRe.FindStringSubmatch(since)
+	return provider.ParseSinceDuration(since)
+}
+
+// newAggregator creates an aggregator with every enabled provider, reporting the disabled
+// ones to reporter
+func newAggregator(cfg *config.Config, reporter *verboselog.Reporter) *provider.Aggregator {
+	aggregator := provider.NewAggregator()
 
-	if matches == nil {
-		return time.Time{}, fmt.Errorf("invalid since format: %s (expected format: 1h, 1d, 1w, or 1m)", since)
+	if cfg.GitHub.Enabled {
+		aggregator.AddProvider(github.NewProvider(cfg.GitHub), includedTypes(cfg.GitHub)...)
+	} else {
+		reporter.ProviderSkipped("github", "disabled")
 	}
 
-	value, err := strconv.Atoi(matches[1])
-	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid since value: %s", matches[1])
+	if cfg.JIRA.Enabled {
+		aggregator.AddProvider(jira.NewProvider(cfg.JIRA), includedTypes(cfg.JIRA)...)
+	} else {
+		reporter.ProviderSkipped("jira", "disabled")
 	}
 
-	unit := matches[2]
-	now := time.Now()
+	if cfg.Obsidian.Enabled {
+		aggregator.AddProvider(obsidian.NewProvider(cfg.Obsidian), includedTypes(cfg.Obsidian)...)
+	} else {
+		reporter.ProviderSkipped("obsidian", "disabled")
+	}
 
-	switch unit {
-	case "h":
-		return now.Add(-time.Duration(value) * time.Hour), nil
-	case "d":
-		return now.AddDate(0, 0, -value), nil
-	case "w":
-		return now.AddDate(0, 0, -value*7), nil
-	case "m":
-		return now.AddDate(0, -value, 0), nil
-	default:
-		return time.Time{}, fmt.Errorf("invalid since unit: %s (expected h, d, w, or m)", unit)
+	if cfg.Confluence.Enabled {
+		aggregator.AddProvider(confluence.NewProvider(cfg.Confluence), includedTypes(cfg.Confluence)...)
+	} else {
+		reporter.ProviderSkipped("confluence", "disabled")
+	}
+
+	if cfg.GitLab.Enabled {
+		aggregator.AddProvider(gitlab.NewProvider(cfg.GitLab), includedTypes(cfg.GitLab)...)
+	} else {
+		reporter.ProviderSkipped("gitlab", "disabled")
+	}
+
+	if cfg.Calendar.Enabled {
+		aggregator.AddProvider(calendar.NewProvider(cfg.Calendar), includedTypes(cfg.Calendar)...)
+	} else {
+		reporter.ProviderSkipped("calendar", "disabled")
+	}
+
+	if cfg.Slack.Enabled {
+		aggregator.AddProvider(slack.NewProvider(cfg.Slack), includedTypes(cfg.Slack)...)
+	} else {
+		reporter.ProviderSkipped("slack", "disabled")
+	}
+
+	if cfg.SavedQueries.Enabled {
+		aggregator.AddProvider(savedquery.NewProvider(cfg.SavedQueries), includedTypes(cfg.SavedQueries)...)
+	} else {
+		reporter.ProviderSkipped("saved_queries", "disabled")
+	}
+
+	return aggregator
+}
+
+// validateJIRAFilters checks the configured JQL filters before querying JIRA, so invalid JQL
+// fails with the JIRA error message instead of producing empty results. Filters that can't
+// be checked, e.g. because JIRA is unreachable, are reported to reporter and the command goes
+// on without JIRA failing it.
+func validateJIRAFilters(ctx context.Context, cfg *config.Config, reporter *verboselog.Reporter) error {
+	if !cfg.JIRA.Enabled {
+		return nil
 	}
+
+	jiraProvider := jira.NewProvider(cfg.JIRA)
+	if !jiraProvider.IsConfigured() {
+		return nil
+	}
+
+	err := jiraProvider.ValidateFilters(ctx)
+	var filterErr *jira.FilterError
+	if errors.As(err, &filterErr) {
+		return err
+	}
+	if err != nil {
+		reporter.Warn("%v", err)
+	}
+	return nil
+}
+
+// includedTypes returns the activity types a provider is limited to. The config is
+// validated on load, so unknown type names can't reach this point.
+func includedTypes(cfg provider.Config) []activity.ActivityType {
+	types, _ := cfg.IncludedTypes()
+	return types
 }
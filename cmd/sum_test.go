@@ -1,8 +1,18 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"daily/internal/config"
+	"daily/internal/datetime"
+	"daily/internal/provider"
+	"daily/internal/verboselog"
 )
 
 func TestParseDate(t *testing.T) {
@@ -37,7 +47,7 @@ func TestParseDate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result, err := parseDate(tt.input)
+			result, err := parseDate(tt.input, datetime.DateOrderUnknown)
 
 			if tt.hasError && err == nil {
 				t.Errorf("Expected error for input %s, but got none", tt.input)
@@ -62,3 +72,28 @@ func TestParseDate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateJIRAFilters(t *testing.T) {
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{JIRA: provider.Config{Enabled: true, Email: "test@example.com", Token: "testtoken", URL: server.URL, Filter: "statuss = Open"}}
+
+	// JQL rejected by JIRA fails the command
+	if err := validateJIRAFilters(context.Background(), cfg, nil); err == nil || !strings.Contains(err.Error(), "invalid JIRA filter") {
+		t.Errorf("Expected the invalid filter error, got: %v", err)
+	}
+
+	// Other errors are warnings
+	status = http.StatusServiceUnavailable
+	var out bytes.Buffer
+	if err := validateJIRAFilters(context.Background(), cfg, verboselog.New(&out, true)); err != nil {
+		t.Errorf("Expected no error when JIRA is unavailable, got: %v", err)
+	}
+	if !strings.Contains(out.String(), "⚠ failed to check JIRA filter") {
+		t.Errorf("Expected a warning, got %q", out.String())
+	}
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/config"
+	"daily/internal/hide"
+	"daily/internal/output"
+	"daily/internal/taskwarrior"
+)
+
+// runTask runs the task binary, overridden in tests
+var runTask taskwarrior.Runner = taskwarrior.RunTask
+
+func ExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export your todo items to other tools",
+	}
+
+	cmd.AddCommand(exportTaskwarriorCmd())
+
+	return cmd
+}
+
+func exportTaskwarriorCmd() *cobra.Command {
+	var outFile string
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "taskwarrior",
+		Short: "Export your todo items as taskwarrior tasks",
+		Long: "Print the todo items, hidden items left out, in the taskwarrior JSON import format, e.g. " +
+			"'daily export taskwarrior | task import'. The project is the platform of the item and the " +
+			"daily_id attribute its ID, or its URL for GitHub items, which 'daily sync taskwarrior' matches completed tasks on. Importing " +
+			"again updates the tasks instead of duplicating them.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadWithTokens()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			todoItems := collectTodos(context.Background(), cfg, todoOptions{since: since})
+			hidden := loadHiddenState(cfg)
+			recordHiddenMatches(hidden, todoItems, nil)
+			todoItems = filterHiddenTodos(todoItems, hidden)
+
+			result, err := formatTaskwarrior(todoItems)
+			if err != nil {
+				return err
+			}
+			return writeOutput(outFile, result)
+		},
+	}
+
+	cmd.Flags().StringVar(&outFile, "out-file", "", "Write the tasks to this file instead of stdout ('-' for stdout)")
+	cmd.Flags().StringVarP(&since, "since", "s", "", "Time range for JIRA and Confluence mentions (e.g., 1d, 2w, 1m). Default: 2w, or confluence.todo_since for Confluence")
+
+	return cmd
+}
+
+// formatTaskwarrior renders the todo items as a JSON array of taskwarrior tasks, in a project
+// per platform
+func formatTaskwarrior(todoItems output.TodoItems) (string, error) {
+	var projects []taskwarrior.Project
+	for _, section := range todoItems.Sections() {
+		projects = append(projects, taskwarrior.Project{Name: section.Platform, Items: *section.Items})
+	}
+
+	data, err := json.MarshalIndent(taskwarrior.FromTodoItems(projects), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal tasks: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+func SyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Apply what you did in other tools to your todo items",
+	}
+
+	cmd.AddCommand(syncTaskwarriorCmd())
+
+	return cmd
+}
+
+func syncTaskwarriorCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "taskwarrior",
+		Short: "Hide the todo items completed in taskwarrior",
+		Long: "Read the tasks with 'task export' and hide the items whose task, exported with 'daily export " +
+			"taskwarrior', is completed. Items exported again after being completed are left alone. Use " +
+			"--dry-run to list the completions without hiding anything.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			store, err := hiddenStore()
+			if err != nil {
+				return err
+			}
+			state, err := loadPrunedState(store, cfg)
+			if err != nil {
+				return err
+			}
+
+			tasks, err := taskwarrior.Export(context.Background(), runTask)
+			if err != nil {
+				return err
+			}
+
+			completed := hideCompleted(&state, taskwarrior.Completed(tasks), nowFunc())
+			if len(completed) > 0 && !dryRun {
+				if err := store.Save(state); err != nil {
+					return err
+				}
+			}
+
+			fmt.Print(formatSyncResult(completed, dryRun))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the completed items without hiding them")
+
+	return cmd
+}
+
+// hideCompleted hides the items of the completed tasks, returning the tasks of the items that
+// weren't hidden yet
+func hideCompleted(state *hide.State, completed []taskwarrior.Task, now time.Time) []taskwarrior.Task {
+	var hidden []taskwarrior.Task
+	for _, task := range completed {
+		if state.Hidden(hide.Item{ID: task.DailyID, Title: task.Description}) {
+			continue
+		}
+		state.HideID(task.DailyID, now)
+		hidden = append(hidden, task)
+	}
+	return hidden
+}
+
+// formatSyncResult lists the items hidden after being completed in taskwarrior
+func formatSyncResult(completed []taskwarrior.Task, dryRun bool) string {
+	if len(completed) == 0 {
+		return fmt.Sprintf("No new task with a %s completed in taskwarrior\n", taskwarrior.UDA)
+	}
+
+	var out strings.Builder
+	if dryRun {
+		out.WriteString(fmt.Sprintf("Items completed in taskwarrior, not hidden on a dry run: %d\n", len(completed)))
+	} else {
+		out.WriteString(fmt.Sprintf("✅ Hidden the items completed in taskwarrior: %d\n", len(completed)))
+	}
+	for _, task := range completed {
+		out.WriteString(fmt.Sprintf("  - %s (%s)\n", task.Description, task.DailyID))
+	}
+	return out.String()
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/hide"
+	"daily/internal/output"
+	"daily/internal/taskwarrior"
+)
+
+func TestFormatTaskwarrior(t *testing.T) {
+	todoItems := output.TodoItems{
+		GitHub: output.GitHubTodos{OpenPRs: []output.TodoItem{{ID: "github-pr-1", Title: "Add caching", URL: "https://github.com/acme/api/pull/1"}}},
+		Obsidian: output.ObsidianTodos{Tasks: []output.TodoItem{
+			{ID: "obsidian-task-inbox.md:3", Title: "Water the plants", URL: "obsidian://open?vault=notes&file=inbox.md", Tags: []string{"home"}},
+			{ID: "obsidian-task-inbox.md:4", Title: "Call the plumber", URL: "obsidian://open?vault=notes&file=inbox.md"},
+		}},
+		Confluence: output.ConfluenceTodos{Mentions: []output.TodoItem{{ID: "12345", Title: "Release plan"}}},
+	}
+
+	result, err := formatTaskwarrior(todoItems)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var tasks []map[string]any
+	if err := json.Unmarshal([]byte(result), &tasks); err != nil {
+		t.Fatalf("Expected a JSON array, got error: %v\n%s", err, result)
+	}
+	if len(tasks) != 4 || tasks[0]["daily_id"] != "https://github.com/acme/api/pull/1" || tasks[1]["project"] != "obsidian" {
+		t.Fatalf("Expected the PR then the Obsidian tasks, got %v", tasks)
+	}
+	if tasks[1]["daily_id"] != "obsidian-task-inbox.md:3" || tasks[2]["daily_id"] != "obsidian-task-inbox.md:4" {
+		t.Errorf("Expected both tasks of the note keyed on their IDs, got %v", tasks[1:3])
+	}
+	if tasks[3]["project"] != "confluence" {
+		t.Errorf("Expected the project of the Confluence section, got %v", tasks[3])
+	}
+
+	if empty, _ := formatTaskwarrior(output.TodoItems{}); empty != "[]\n" {
+		t.Errorf("Expected an empty array, got %q", empty)
+	}
+}
+
+func TestSyncTaskwarrior(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	originalNow, originalPath, originalRun := nowFunc, hiddenStatePath, runTask
+	defer func() { nowFunc, hiddenStatePath, runTask = originalNow, originalPath, originalRun }()
+	nowFunc = func() time.Time { return now }
+	path := filepath.Join(t.TempDir(), "hidden.json")
+	hiddenStatePath = func() (string, error) { return path, nil }
+	runTask = func(context.Context, ...string) ([]byte, error) {
+		return []byte(`[
+			{"uuid": "1", "description": "Fix login", "status": "completed", "daily_id": "jira-PROJ-1"},
+			{"uuid": "2", "description": "Bump lodash", "status": "completed", "daily_id": "github-pr-2"},
+			{"uuid": "3", "description": "Rotate keys", "status": "pending", "daily_id": "jira-OPS-3"},
+			{"uuid": "4", "description": "Water the plants", "status": "completed", "daily_id": "obsidian-task-inbox.md:3"}
+		]`), nil
+	}
+
+	store := hide.NewStore(path)
+	if err := store.Save(hide.State{Rules: []hide.Rule{{Pattern: "lodash"}}}); err != nil {
+		t.Fatalf("Failed to save hidden items: %v", err)
+	}
+
+	run := func(args ...string) string {
+		stdout := os.Stdout
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("Failed to create pipe: %v", err)
+		}
+		os.Stdout = w
+		cmd := SyncCmd()
+		cmd.SetArgs(args)
+		err = cmd.Execute()
+		_ = w.Close()
+		os.Stdout = stdout
+		out, _ := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return string(out)
+	}
+
+	if out := run("taskwarrior", "--dry-run"); !strings.Contains(out, "not hidden on a dry run: 2") || !strings.Contains(out, "Fix login (jira-PROJ-1)") {
+		t.Errorf("Expected the completed ticket to be listed, got: %s", out)
+	}
+	if state, _ := store.Load(); len(state.IDs) != 0 {
+		t.Errorf("Expected nothing hidden on a dry run, got %+v", state.IDs)
+	}
+
+	if out := run("taskwarrior"); !strings.Contains(out, "✅ Hidden the items completed in taskwarrior: 2") {
+		t.Errorf("Expected the completed ticket to be hidden, got: %s", out)
+	}
+	state, _ := store.Load()
+	if len(state.IDs) != 2 || state.IDs[0].Ref != "jira-PROJ-1" || !state.IDs[0].HiddenAt.Equal(now) {
+		t.Errorf("Expected the completed ticket and task hidden, the PR matching a rule left alone, got %+v", state.IDs)
+	}
+	note := "obsidian://open?vault=notes&file=inbox.md"
+	if !state.Hidden(hide.Item{ID: "obsidian-task-inbox.md:3", URL: note}) || state.Hidden(hide.Item{ID: "obsidian-task-inbox.md:4", URL: note}) {
+		t.Error("Expected only the completed task of the note hidden")
+	}
+
+	if out := run("taskwarrior"); !strings.Contains(out, "No new task with a "+taskwarrior.UDA+" completed") {
+		t.Errorf("Expected nothing new on the second sync, got: %s", out)
+	}
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/config"
+	"daily/internal/telemetry"
+)
+
+// telemetryTimeout bounds sending a batch, which happens at the end of a command
+const telemetryTimeout = 3 * time.Second
+
+// telemetryDescription explains what enabling telemetry sends, shown before asking for it
+const telemetryDescription = `daily can send anonymous usage counts to help its maintainers see which features are used:
+how many times each command ran, which providers were enabled, which output formats were
+used and which kinds of errors occurred (e.g. "auth" or "network"). Titles, URLs, usernames,
+tokens and error messages are never counted. Counts are kept locally and sent at most once a
+day to the configured telemetry endpoint. 'daily telemetry show' prints exactly what would be
+sent and 'daily telemetry off' disables it and deletes the counts.`
+
+func TelemetryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage anonymous usage telemetry",
+		Long:  "Show, enable or disable the anonymous usage telemetry, which is off unless you enable it.\n\n" + telemetryDescription,
+	}
+
+	cmd.AddCommand(telemetryShowCmd())
+	cmd.AddCommand(telemetryOnCmd())
+	cmd.AddCommand(telemetryOffCmd())
+
+	return cmd
+}
+
+func telemetryShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the usage counts that would be sent",
+		Long:  "Print whether telemetry is enabled, where and when the next batch is sent, and the exact JSON document it would send.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := config.LoadTelemetry()
+			if err != nil {
+				return err
+			}
+			store, err := telemetryStore()
+			if err != nil {
+				return err
+			}
+			buffer, err := store.Load()
+			if err != nil {
+				return err
+			}
+
+			result, err := formatTelemetry(settings.Telemetry, buffer, time.Now())
+			if err != nil {
+				return err
+			}
+			fmt.Print(result)
+			return nil
+		},
+	}
+}
+
+func telemetryOnCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "on",
+		Short: "Enable anonymous usage telemetry",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := setTelemetryEnabled(true); err != nil {
+				return err
+			}
+			fmt.Println("✅ Telemetry enabled, see what is sent with 'daily telemetry show'")
+			return nil
+		},
+	}
+}
+
+func telemetryOffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "off",
+		Short: "Disable anonymous usage telemetry and delete the usage counts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := setTelemetryEnabled(false); err != nil {
+				return err
+			}
+			store, err := telemetryStore()
+			if err != nil {
+				return err
+			}
+			if err := store.Clear(); err != nil {
+				return err
+			}
+			fmt.Println("✅ Telemetry disabled, the usage counts not sent yet were deleted")
+			return nil
+		},
+	}
+}
+
+// setTelemetryEnabled enables or disables telemetry in the config file, and records that the
+// user made their choice so that 'daily config init' doesn't ask again
+func setTelemetryEnabled(enabled bool) error {
+	for _, setting := range []struct{ key, value string }{
+		{"telemetry.enabled", fmt.Sprint(enabled)},
+		{"telemetry.prompted", "true"},
+	} {
+		if _, err := config.Set(setting.key, setting.value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", setting.key, err)
+		}
+	}
+	return nil
+}
+
+// promptTelemetry describes the telemetry on out and asks whether to enable it. Any answer but
+// yes keeps it disabled.
+func promptTelemetry(in io.Reader, out io.Writer) (bool, error) {
+	fmt.Fprintf(out, "\n%s\n\nEnable anonymous usage telemetry? [y/N] ", telemetryDescription)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// telemetryStore returns the store of the usage counts
+func telemetryStore() (telemetry.Store, error) {
+	path, err := telemetry.DefaultBufferPath()
+	if err != nil {
+		return telemetry.Store{}, err
+	}
+	return telemetry.NewStore(path), nil
+}
+
+// formatTelemetry describes the telemetry settings and the payload the buffer would send at now
+func formatTelemetry(settings config.TelemetryConfig, buffer telemetry.Buffer, now time.Time) (string, error) {
+	var sb strings.Builder
+
+	if settings.Enabled {
+		sb.WriteString("Telemetry: enabled (disable it with 'daily telemetry off')\n")
+	} else {
+		sb.WriteString("Telemetry: disabled, nothing is counted nor sent (enable it with 'daily telemetry on')\n")
+	}
+
+	switch {
+	case settings.Endpoint == "":
+		sb.WriteString("Endpoint: not set, nothing is sent (set telemetry.endpoint)\n")
+	case !settings.Enabled:
+		fmt.Fprintf(&sb, "Endpoint: %s\n", settings.Endpoint)
+	case buffer.Empty():
+		fmt.Fprintf(&sb, "Endpoint: %s\nNext batch: once a command ran\n", settings.Endpoint)
+	case buffer.Due(now):
+		fmt.Fprintf(&sb, "Endpoint: %s\nNext batch: at the end of the next command\n", settings.Endpoint)
+	default:
+		fmt.Fprintf(&sb, "Endpoint: %s\nNext batch: after %s\n", settings.Endpoint, buffer.NextBatch().Local().Format("2006-01-02 15:04"))
+	}
+
+	data, err := buffer.Payload(now).Marshal()
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal telemetry: %w", err)
+	}
+	fmt.Fprintf(&sb, "\nPayload:\n%s\n", data)
+	return sb.String(), nil
+}
+
+// RecordUsage counts a run of command, which failed with err when not nil, and sends the
+// batch of usage counts when it is due. It does nothing unless telemetry is enabled, and never
+// fails the command: telemetry is best effort.
+func RecordUsage(command *cobra.Command, err error) {
+	settings, loadErr := config.LoadTelemetry()
+	if loadErr != nil || !settings.Telemetry.Enabled {
+		return
+	}
+	store, storeErr := telemetryStore()
+	if storeErr != nil {
+		return
+	}
+
+	usage := usageOf(command, err)
+	usage.Providers = settings.Providers
+	_ = recordUsage(context.Background(), settings.Telemetry, usage, store, http.DefaultClient, time.Now())
+}
+
+// usageOf returns the top-level command and the output format of a run. Without a command,
+// e.g. when it is unknown, the run is counted as telemetry.Other.
+func usageOf(command *cobra.Command, err error) telemetry.Usage {
+	usage := telemetry.Usage{Command: telemetry.Other, Err: err}
+	if command == nil || !command.HasParent() {
+		return usage
+	}
+
+	if flag := command.Flags().Lookup("output"); flag != nil {
+		usage.Output = flag.Value.String()
+	}
+	for command.Parent().HasParent() {
+		command = command.Parent()
+	}
+	usage.Command = command.Name()
+	return usage
+}
+
+// recordUsage counts usage in the buffer of store and sends its batch to the endpoint when
+// due. The counts are kept when sending fails, to be sent again after telemetry.RetryInterval.
+func recordUsage(ctx context.Context, settings config.TelemetryConfig, usage telemetry.Usage, store telemetry.Store, client *http.Client, now time.Time) error {
+	if !settings.Enabled {
+		return nil
+	}
+
+	// A buffer that can't be read is started over
+	buffer, err := store.Load()
+	if err != nil {
+		buffer = telemetry.Buffer{}
+	}
+	buffer.Add(usage, now)
+
+	if settings.Endpoint != "" && buffer.Due(now) {
+		ctx, cancel := context.WithTimeout(ctx, telemetryTimeout)
+		defer cancel()
+		if err := telemetry.Send(ctx, client, settings.Endpoint, buffer.Payload(now)); err != nil {
+			buffer.LastAttempt = now
+		} else {
+			buffer.Sent(now)
+		}
+	}
+	return store.Save(buffer)
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/config"
+	"daily/internal/telemetry"
+)
+
+func TestUsageOf(t *testing.T) {
+	root := &cobra.Command{Use: "daily"}
+	todo := &cobra.Command{Use: "todo", Run: func(cmd *cobra.Command, args []string) {}}
+	todo.Flags().StringP("output", "o", "tui", "")
+	cache := &cobra.Command{Use: "cache"}
+	clearCmd := &cobra.Command{Use: "clear", Run: func(cmd *cobra.Command, args []string) {}}
+	cache.AddCommand(clearCmd)
+	root.AddCommand(todo, cache)
+	_ = todo.Flags().Set("output", "json")
+
+	tests := []struct {
+		name     string
+		command  *cobra.Command
+		expected telemetry.Usage
+	}{
+		{"command with an output", todo, telemetry.Usage{Command: "todo", Output: "json"}},
+		{"subcommand", clearCmd, telemetry.Usage{Command: "cache"}},
+		{"no command", nil, telemetry.Usage{Command: telemetry.Other}},
+		{"root", root, telemetry.Usage{Command: telemetry.Other}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := usageOf(tt.command, nil); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected usage %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRecordUsage(t *testing.T) {
+	var requests []string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	store := telemetry.NewStore(filepath.Join(t.TempDir(), "telemetry.json"))
+	settings := config.TelemetryConfig{Enabled: true, Endpoint: server.URL}
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	record := func(usage telemetry.Usage, at time.Time) telemetry.Buffer {
+		t.Helper()
+		if err := recordUsage(context.Background(), settings, usage, store, server.Client(), at); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		buffer, err := store.Load()
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return buffer
+	}
+
+	// The first run sends its batch right away, the next ones wait for a day
+	buffer := record(telemetry.Usage{Command: "todo", Output: "tui", Providers: []string{"github"}}, now)
+	if len(requests) != 1 || !strings.Contains(requests[0], `"todo": 1`) || !buffer.Empty() {
+		t.Fatalf("Expected the first batch to be sent and cleared, got %v and %+v", requests, buffer)
+	}
+	buffer = record(telemetry.Usage{Command: "sum", Err: errors.New("GitHub API request failed with status 401")}, now.Add(time.Hour))
+	if len(requests) != 1 || buffer.Commands["sum"] != 1 || buffer.Errors[telemetry.ErrorAuth] != 1 {
+		t.Fatalf("Expected the usage to be buffered until the next day, got %v and %+v", requests, buffer)
+	}
+
+	// A failed batch is kept and retried an hour later
+	status = http.StatusInternalServerError
+	buffer = record(telemetry.Usage{Command: "sum"}, now.Add(25*time.Hour))
+	if len(requests) != 2 || buffer.Commands["sum"] != 2 || buffer.LastAttempt.IsZero() {
+		t.Fatalf("Expected the failed batch to be kept, got %v and %+v", requests, buffer)
+	}
+	record(telemetry.Usage{Command: "sum"}, now.Add(25*time.Hour+time.Minute))
+	if len(requests) != 2 {
+		t.Fatalf("Expected no retry within the hour, got %d requests", len(requests))
+	}
+
+	status = http.StatusOK
+	buffer = record(telemetry.Usage{Command: "reviews"}, now.Add(27*time.Hour))
+	if len(requests) != 3 || !buffer.Empty() {
+		t.Fatalf("Expected the batch to be sent again, got %v and %+v", requests, buffer)
+	}
+	if !strings.Contains(requests[2], `"sum": 3`) || !strings.Contains(requests[2], `"auth": 1`) {
+		t.Errorf("Expected the retried batch to hold every usage, got %s", requests[2])
+	}
+}
+
+func TestRecordUsage_Disabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected nothing to be sent while telemetry is disabled")
+	}))
+	defer server.Close()
+
+	store := telemetry.NewStore(filepath.Join(t.TempDir(), "telemetry.json"))
+	settings := config.TelemetryConfig{Endpoint: server.URL}
+	if err := recordUsage(context.Background(), settings, telemetry.Usage{Command: "todo"}, store, server.Client(), time.Now()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if buffer, _ := store.Load(); !buffer.Empty() {
+		t.Errorf("Expected nothing to be counted while telemetry is disabled, got %+v", buffer)
+	}
+}
+
+func TestRecordUsage_NoSecretsSent(t *testing.T) {
+	var sent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+	}))
+	defer server.Close()
+
+	secrets := []string{"ghp_s3cr3tT0k3nValue", "octo-private-user", "https://acme-internal.atlassian.net", "PROJ-123 Fix the billing export"}
+	root := &cobra.Command{Use: "daily"}
+	alias := &cobra.Command{Use: secrets[1], Run: func(cmd *cobra.Command, args []string) {}}
+	alias.Flags().StringP("output", "o", "", "")
+	root.AddCommand(alias)
+	_ = alias.Flags().Set("output", secrets[0])
+
+	usage := usageOf(alias, errors.New("failed to get "+secrets[3]+" from "+secrets[2]+" with token "+secrets[0]))
+	usage.Providers = []string{"github", secrets[2]}
+	store := telemetry.NewStore(filepath.Join(t.TempDir(), "telemetry.json"))
+	if err := recordUsage(context.Background(), config.TelemetryConfig{Enabled: true, Endpoint: server.URL}, usage, store, server.Client(), time.Now()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if sent == "" {
+		t.Fatal("Expected the batch to be sent")
+	}
+	for _, secret := range secrets {
+		if strings.Contains(sent, secret) {
+			t.Errorf("Expected the payload not to contain %q, got:\n%s", secret, sent)
+		}
+	}
+}
+
+func TestPromptTelemetry(t *testing.T) {
+	tests := []struct {
+		answer   string
+		expected bool
+	}{
+		{"y\n", true},
+		{" Yes \n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+		{"sure\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.TrimSpace(tt.answer), func(t *testing.T) {
+			var out strings.Builder
+			enabled, err := promptTelemetry(strings.NewReader(tt.answer), &out)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if enabled != tt.expected {
+				t.Errorf("Expected enabled %v, got %v", tt.expected, enabled)
+			}
+			if !strings.Contains(out.String(), "never counted") || !strings.Contains(out.String(), "[y/N]") {
+				t.Errorf("Expected the description and the question, got: %s", out.String())
+			}
+		})
+	}
+}
+
+func TestFormatTelemetry(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	buffer := telemetry.Buffer{LastSent: now.Add(-time.Hour)}
+	buffer.Add(telemetry.Usage{Command: "todo", Output: "json"}, now)
+
+	result, err := formatTelemetry(config.TelemetryConfig{Enabled: true, Endpoint: "https://telemetry.acme.example"}, buffer, now)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	payload, err := buffer.Payload(now).Marshal()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, expected := range []string{"Telemetry: enabled", "Endpoint: https://telemetry.acme.example", "Next batch: after", string(payload)} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, result)
+		}
+	}
+
+	result, err = formatTelemetry(config.TelemetryConfig{}, telemetry.Buffer{}, now)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(result, "Telemetry: disabled") || !strings.Contains(result, "nothing is sent") {
+		t.Errorf("Expected disabled telemetry without endpoint, got:\n%s", result)
+	}
+}
@@ -1,172 +1,110 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"daily/internal/concurrency"
 	"daily/internal/config"
+	"daily/internal/metrics"
 	"daily/internal/output"
+	"daily/internal/provider"
 	"daily/internal/provider/confluence"
 	"daily/internal/provider/github"
+	"daily/internal/provider/gitlab"
 	"daily/internal/provider/jira"
 	"daily/internal/provider/obsidian"
+	"daily/internal/provider/slack"
+	"daily/internal/verboselog"
 )
 
 func TodoCmd() *cobra.Command {
 	var verbose bool
 	var outputFormat string
+	var outFile string
+	var progress string
 	var since string
+	var details bool
 
 	cmd := &cobra.Command{
 		Use:   "todo",
 		Short: "Get a list of pending work items",
 		Long:  "Display open pull requests, pending reviews, and assigned JIRA tickets that need attention.",
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			// Validate output format
 			if outputFormat != "text" && outputFormat != "json" && outputFormat != "tui" {
 				return fmt.Errorf("invalid output format: %s (must be 'text', 'json', or 'tui')", outputFormat)
 			}
+			if err := validateOutFile(outputFormat, outFile); err != nil {
+				return err
+			}
+			if err := validateProgress(outputFormat, progress); err != nil {
+				return err
+			}
+
+			recorder := newRunRecorder(progress)
+			defer func() { recorder.RunFinished(err) }()
 
 			if outputFormat == "text" {
 				fmt.Println("Gathering pending work items...")
 			}
 
 			// Load configuration
-			cfg, err := config.Load()
+			cfg, err := config.LoadWithTokens()
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
 
-			ctx := context.Background()
+			ctx := metrics.WithRecorder(context.Background(), recorder)
 			showVerbose := verbose && outputFormat == "text"
-
-			// Default to 2w if no since value provided
-			if since == "" {
-				since = "2w"
-			}
-
-			var todoItems output.TodoItems
-
-			// Get GitHub todos
-			if cfg.GitHub.Enabled {
-				if showVerbose {
-					fmt.Println("✓ GitHub provider enabled")
-				}
-				githubProvider := github.NewProvider(cfg.GitHub)
-				if githubProvider.IsConfigured() {
-					githubTodos, err := getGitHubTodos(ctx, githubProvider)
-					if err != nil {
-						if showVerbose {
-							fmt.Printf("❌ GitHub todos failed: %v\n", err)
-						}
-					} else {
-						todoItems.GitHub = githubTodos
-						if showVerbose {
-							fmt.Printf("✅ GitHub returned %d open PRs and %d pending reviews\n",
-								len(githubTodos.OpenPRs), len(githubTodos.PendingReviews))
-						}
-					}
-				} else if showVerbose {
-					fmt.Println("⚠️  GitHub provider not configured")
-				}
-			} else if showVerbose {
-				fmt.Println("✗ GitHub provider disabled")
-			}
-
-			// Get JIRA todos
-			if cfg.JIRA.Enabled {
-				if showVerbose {
-					fmt.Println("✓ JIRA provider enabled")
-				}
-				jiraProvider := jira.NewProvider(cfg.JIRA)
-				if jiraProvider.IsConfigured() {
-					jiraTodos, err := getJIRATodos(ctx, jiraProvider)
-					if err != nil {
-						if showVerbose {
-							fmt.Printf("❌ JIRA todos failed: %v\n", err)
-						}
-					} else {
-						todoItems.JIRA = jiraTodos
-						if showVerbose {
-							fmt.Printf("✅ JIRA returned %d assigned tickets\n", len(jiraTodos.AssignedTickets))
-						}
-					}
-				} else if showVerbose {
-					fmt.Println("⚠️  JIRA provider not configured")
-				}
-			} else if showVerbose {
-				fmt.Println("✗ JIRA provider disabled")
+			var reporter *verboselog.Reporter
+			if showVerbose {
+				reporter = verboselog.Stderr()
 			}
+			autoPruneCache(cfg, reporter)
 
-			// Get Obsidian todos
-			if cfg.Obsidian.Enabled {
-				if showVerbose {
-					fmt.Println("✓ Obsidian provider enabled")
-				}
-				obsidianProvider := obsidian.NewProvider(cfg.Obsidian)
-				if obsidianProvider.IsConfigured() {
-					obsidianTodos, err := getObsidianTodos(ctx, obsidianProvider)
-					if err != nil {
-						if showVerbose {
-							fmt.Printf("❌ Obsidian todos failed: %v\n", err)
-						}
-					} else {
-						todoItems.Obsidian = obsidianTodos
-						if showVerbose {
-							fmt.Printf("✅ Obsidian returned %d tasks\n", len(obsidianTodos.Tasks))
-						}
-					}
-				} else if showVerbose {
-					fmt.Println("⚠️  Obsidian provider not configured")
-				}
-			} else if showVerbose {
-				fmt.Println("✗ Obsidian provider disabled")
+			if err := validateJIRAFilters(ctx, cfg, reporter); err != nil {
+				return err
 			}
 
-			// Get Confluence todos
-			if cfg.Confluence.Enabled {
-				if showVerbose {
-					fmt.Println("✓ Confluence provider enabled")
-				}
-				confluenceProvider := confluence.NewProvider(cfg.Confluence)
-				if confluenceProvider.IsConfigured() {
-					confluenceTodos, err := getConfluenceTodos(ctx, confluenceProvider, since)
-					if err != nil {
-						if showVerbose {
-							fmt.Printf("❌ Confluence todos failed: %v\n", err)
-						}
-					} else {
-						todoItems.Confluence = confluenceTodos
-						if showVerbose {
-							fmt.Printf("✅ Confluence returned %d items (mentions + comments on your pages)\n", len(confluenceTodos.Mentions))
-						}
-					}
-				} else if showVerbose {
-					fmt.Println("⚠️  Confluence provider not configured")
-				}
-			} else if showVerbose {
-				fmt.Println("✗ Confluence provider disabled")
-			}
-
-			if showVerbose {
-				fmt.Println()
-			}
+			todoItems := collectTodos(ctx, cfg, todoOptions{
+				since:    since,
+				details:  details,
+				reporter: reporter,
+			})
+			hidden := loadHiddenState(cfg)
+			recordHiddenMatches(hidden, todoItems, nil)
+			todoItems = filterHiddenTodos(todoItems, hidden)
+			setTodoSeverity(todoItems, cfg.SeverityRules())
+			translateTodos(ctx, newTranslator(cfg), todoItems, reporter)
 
 			// Format and display results
 			switch outputFormat {
 			case "json":
 				formatter := output.NewFormatter()
+				formatter.SetMeta(recorder.Report())
 				result := formatter.FormatTodoJSON(todoItems)
-				fmt.Print(result)
+				return writeOutput(outFile, result)
 			case "tui":
 				formatter := output.NewFormatter()
 				return formatter.FormatTodoTUI(todoItems)
 			case "text":
 				formatter := output.NewFormatter()
 				result := formatter.FormatTodo(todoItems)
-				fmt.Print(result)
+				if err := writeOutput(outFile, result); err != nil {
+					return err
+				}
+				if showVerbose {
+					printRunReport(recorder)
+				}
+				return nil
 			}
 
 			return nil
@@ -175,11 +113,229 @@ func TodoCmd() *cobra.Command {
 
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output for debugging (text mode only)")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "tui", "Output format: 'tui', 'text', or 'json'")
-	cmd.Flags().StringVarP(&since, "since", "s", "", "Time range for Confluence mentions (e.g., 1d, 2w, 1m). Default: 2w")
+	cmd.Flags().StringVar(&outFile, "out-file", "", "Write output to this file instead of stdout ('-' for stdout, not available with tui)")
+	cmd.Flags().StringVar(&progress, "progress", "", "Stream progress events to stderr: 'json' for one JSON object per line")
+	cmd.Flags().StringVarP(&since, "since", "s", "", "Time range for JIRA and Confluence mentions (e.g., 1d, 2w, 1m). Default: 2w, or confluence.todo_since for Confluence")
+	cmd.Flags().BoolVar(&details, "details", false, "Fetch the latest comments of assigned JIRA tickets (one extra request per ticket)")
 
 	return cmd
 }
 
+// todoOptions controls how todo items are collected
+type todoOptions struct {
+	since    string // --since, time range of JIRA and Confluence mentions, e.g. 2w, empty for the defaults
+	details  bool   // Fetch the latest comments of assigned JIRA tickets
+	reporter *verboselog.Reporter
+}
+
+// defaultTodoSince is the time range of mentions without --since nor configured range
+const defaultTodoSince = "2w"
+
+// collectTodos gathers the pending work items of all enabled providers. Providers that
+// fail are skipped.
+func collectTodos(ctx context.Context, cfg *config.Config, opts todoOptions) output.TodoItems {
+	var todoItems output.TodoItems
+	recorder := metrics.FromContext(ctx)
+	reporter := opts.reporter
+	jiraSince := cmp.Or(opts.since, defaultTodoSince)
+	confluenceSince := cmp.Or(opts.since, cfg.Confluence.TodoSince, defaultTodoSince)
+
+	// Get GitHub todos
+	if cfg.GitHub.Enabled {
+		githubProvider := github.NewProvider(cfg.GitHub)
+		if githubProvider.IsConfigured() {
+			reporter.ProviderEnabled(githubProvider.Name())
+			start := time.Now()
+			recorder.ProviderStarted(githubProvider.Name())
+			githubTodos, err := getGitHubTodos(ctx, githubProvider)
+			count := len(githubTodos.OpenPRs) + len(githubTodos.PendingReviews)
+			recorder.ProviderFinished(githubProvider.Name(), count, err)
+			reporter.ProviderResult(githubProvider.Name(), count, time.Since(start), err)
+			if err == nil {
+				todoItems.GitHub = githubTodos
+				reporter.Detail("%d open PRs, %d pending reviews", len(githubTodos.OpenPRs), len(githubTodos.PendingReviews))
+			}
+			reportDegraded(reporter, githubProvider.Degraded())
+		} else {
+			reporter.ProviderSkipped(githubProvider.Name(), "not configured")
+		}
+	} else {
+		reporter.ProviderSkipped("github", "disabled")
+	}
+
+	// Get GitLab todos
+	if cfg.GitLab.Enabled {
+		gitlabProvider := gitlab.NewProvider(cfg.GitLab)
+		if gitlabProvider.IsConfigured() {
+			reporter.ProviderEnabled(gitlabProvider.Name())
+			start := time.Now()
+			recorder.ProviderStarted(gitlabProvider.Name())
+			gitlabTodos, warnings, err := getGitLabTodos(ctx, gitlabProvider)
+			count := len(gitlabTodos.OpenMRs) + len(gitlabTodos.PendingReviews) + len(gitlabTodos.AssignedIssues)
+			recorder.ProviderFinished(gitlabProvider.Name(), count, err)
+			reporter.ProviderResult(gitlabProvider.Name(), count, time.Since(start), err)
+			if err == nil {
+				todoItems.GitLab = gitlabTodos
+				reporter.Detail("%d open MRs, %d pending reviews, %d assigned issues",
+					len(gitlabTodos.OpenMRs), len(gitlabTodos.PendingReviews), len(gitlabTodos.AssignedIssues))
+			}
+			for _, err := range warnings {
+				reporter.Warn("%v", err)
+			}
+		} else {
+			reporter.ProviderSkipped(gitlabProvider.Name(), "not configured")
+		}
+	} else {
+		reporter.ProviderSkipped("gitlab", "disabled")
+	}
+
+	// Get JIRA todos
+	if cfg.JIRA.Enabled {
+		jiraProvider := jira.NewProvider(cfg.JIRA)
+		if jiraProvider.IsConfigured() {
+			reporter.ProviderEnabled(jiraProvider.Name())
+			start := time.Now()
+			recorder.ProviderStarted(jiraProvider.Name())
+			jiraTodos, warnings, err := getJIRATodos(ctx, jiraProvider, jiraSince, jiraTodoOptions{
+				includeWatched:  cfg.JIRA.IncludeWatched,
+				includeReported: cfg.JIRA.IncludeReported,
+				rollupSubtasks:  cfg.JIRA.RollupSubtasks,
+			})
+			var commentErrs []error
+			if err == nil && opts.details {
+				commentErrs = attachJIRAComments(ctx, jiraProvider, jiraTodos.AssignedTickets)
+			}
+			count := len(jiraTodos.AssignedTickets) + len(jiraTodos.Mentions) + len(jiraTodos.Reported) + len(jiraTodos.Watched)
+			recorder.ProviderFinished(jiraProvider.Name(), count, err)
+			reporter.ProviderResult(jiraProvider.Name(), count, time.Since(start), err)
+			if err == nil {
+				todoItems.JIRA = jiraTodos
+				reporter.Detail("%d assigned tickets, %d mentions, %d reported, %d watched",
+					len(jiraTodos.AssignedTickets), len(jiraTodos.Mentions), len(jiraTodos.Reported), len(jiraTodos.Watched))
+			}
+			for _, err := range warnings {
+				reporter.Warn("%v", err)
+			}
+			for _, err := range commentErrs {
+				reporter.Warn("JIRA comments failed: %v", err)
+			}
+		} else {
+			reporter.ProviderSkipped(jiraProvider.Name(), "not configured")
+		}
+	} else {
+		reporter.ProviderSkipped("jira", "disabled")
+	}
+
+	// Get Obsidian todos
+	if cfg.Obsidian.Enabled {
+		obsidianProvider := obsidian.NewProvider(cfg.Obsidian)
+		if obsidianProvider.IsConfigured() {
+			reporter.ProviderEnabled(obsidianProvider.Name())
+			start := time.Now()
+			recorder.ProviderStarted(obsidianProvider.Name())
+			obsidianTodos, err := getObsidianTodos(ctx, obsidianProvider)
+			recorder.ProviderFinished(obsidianProvider.Name(), len(obsidianTodos.Tasks), err)
+			reporter.ProviderResult(obsidianProvider.Name(), len(obsidianTodos.Tasks), time.Since(start), err)
+			if err == nil {
+				todoItems.Obsidian = obsidianTodos
+				if skipped := obsidianProvider.Skipped(); skipped != "" {
+					reporter.Detail("Skipped %s", skipped)
+				}
+			}
+		} else {
+			reporter.ProviderSkipped(obsidianProvider.Name(), "not configured")
+		}
+	} else {
+		reporter.ProviderSkipped("obsidian", "disabled")
+	}
+
+	// Get Confluence todos
+	if cfg.Confluence.Enabled {
+		confluenceProvider := confluence.NewProvider(cfg.Confluence)
+		if confluenceProvider.IsConfigured() {
+			reporter.ProviderEnabled(confluenceProvider.Name())
+			start := time.Now()
+			recorder.ProviderStarted(confluenceProvider.Name())
+			confluenceTodos, warnings, err := getConfluenceTodos(ctx, confluenceProvider, confluenceSince, cfg.Confluence.IncludeWatched)
+			count := len(confluenceTodos.Mentions) + len(confluenceTodos.CommentsOnMyPages) + len(confluenceTodos.Tasks) + len(confluenceTodos.Watched)
+			recorder.ProviderFinished(confluenceProvider.Name(), count, err)
+			reporter.ProviderResult(confluenceProvider.Name(), count, time.Since(start), err)
+			if err == nil {
+				todoItems.Confluence = confluenceTodos
+				reporter.Detail("%d mentions, %d comments on your pages, %d tasks, %d watched",
+					len(confluenceTodos.Mentions), len(confluenceTodos.CommentsOnMyPages), len(confluenceTodos.Tasks), len(confluenceTodos.Watched))
+				for _, search := range confluenceProvider.CappedSearches() {
+					reporter.Warn("Returned %d of %d matches, capped (raise confluence.max_results): %s",
+						search.Returned, search.Total, search.CQL)
+				}
+			}
+			for _, err := range warnings {
+				reporter.Warn("%v", err)
+			}
+		} else {
+			reporter.ProviderSkipped(confluenceProvider.Name(), "not configured")
+		}
+	} else {
+		reporter.ProviderSkipped("confluence", "disabled")
+	}
+
+	// Get Slack todos
+	if cfg.Slack.Enabled {
+		slackProvider := slack.NewProvider(cfg.Slack)
+		if slackProvider.IsConfigured() {
+			reporter.ProviderEnabled(slackProvider.Name())
+			start := time.Now()
+			recorder.ProviderStarted(slackProvider.Name())
+			slackTodos, err := getSlackTodos(ctx, slackProvider)
+			recorder.ProviderFinished(slackProvider.Name(), len(slackTodos.Saved), err)
+			reporter.ProviderResult(slackProvider.Name(), len(slackTodos.Saved), time.Since(start), err)
+			if err == nil {
+				todoItems.Slack = slackTodos
+			}
+		} else {
+			reporter.ProviderSkipped(slackProvider.Name(), "not configured")
+		}
+	} else {
+		reporter.ProviderSkipped("slack", "disabled")
+	}
+
+	uniqueTodoItems(&todoItems)
+	return todoItems
+}
+
+// uniqueTodoItems drops the items listed again in a section under the same ID, like the
+// activities of summaries, so that an item fetched twice is never shown twice
+func uniqueTodoItems(todoItems *output.TodoItems) {
+	sections := []*[]output.TodoItem{
+		&todoItems.GitHub.OpenPRs,
+		&todoItems.GitHub.PendingReviews,
+		&todoItems.JIRA.AssignedTickets,
+		&todoItems.JIRA.Mentions,
+		&todoItems.JIRA.Reported,
+		&todoItems.JIRA.Watched,
+		&todoItems.Obsidian.Tasks,
+		&todoItems.Confluence.Mentions,
+		&todoItems.Confluence.CommentsOnMyPages,
+		&todoItems.Confluence.Tasks,
+		&todoItems.Confluence.Watched,
+		&todoItems.GitLab.OpenMRs,
+		&todoItems.GitLab.PendingReviews,
+		&todoItems.GitLab.AssignedIssues,
+		&todoItems.Slack.Saved,
+	}
+	for _, section := range sections {
+		seen := make(map[string]bool, len(*section))
+		*section = slices.DeleteFunc(*section, func(item output.TodoItem) bool {
+			if item.ID == "" {
+				return false
+			}
+			listed := seen[item.ID]
+			seen[item.ID] = true
+			return listed
+		})
+	}
+}
+
 func getGitHubTodos(ctx context.Context, provider *github.Provider) (output.GitHubTodos, error) {
 	var todos output.GitHubTodos
 
@@ -189,17 +345,27 @@ func getGitHubTodos(ctx context.Context, provider *github.Provider) (output.GitH
 		return todos, fmt.Errorf("failed to get open PRs: %w", err)
 	}
 
-	// Convert from github.TodoItem to output.TodoItem
-	todos.OpenPRs = make([]output.TodoItem, len(openPRs))
-	for i, item := range openPRs {
-		todos.OpenPRs[i] = output.TodoItem{
-			ID:          item.ID,
-			Title:       item.Title,
-			Description: item.Description,
-			URL:         item.URL,
-			UpdatedAt:   item.UpdatedAt,
-			Tags:        item.Tags,
+	// Convert from github.TodoItem to output.TodoItem, looking up the PRs in parallel
+	results := concurrency.Map(ctx, openPRs, githubPREnrichment, func(ctx context.Context, _ int, item github.TodoItem) (output.TodoItem, error) {
+		// Project status is optional: tokens without project scope simply leave it empty
+		if status, err := provider.GetPRProjectStatus(ctx, item.Repository, item.Number); err == nil {
+			item.ProjectStatus = status
 		}
+		// Readiness is best effort too: PRs whose reviews or checks can't be read aren't nudged
+		if ready, err := provider.IsReadyToMerge(ctx, item.Repository, item.Number); err == nil && ready {
+			item.ReadyToMerge = true
+			item.Tags = append(item.Tags, github.ReadyToMergeTag)
+		}
+		return convertGitHubTodoItem(item), nil
+	})
+	todos.OpenPRs = make([]output.TodoItem, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			// Not looked up, e.g. when cancelled
+			todos.OpenPRs[i] = convertGitHubTodoItem(openPRs[i])
+			continue
+		}
+		todos.OpenPRs[i] = result.Value
 	}
 
 	// Get pending reviews
@@ -211,42 +377,378 @@ func getGitHubTodos(ctx context.Context, provider *github.Provider) (output.GitH
 	// Convert from github.TodoItem to output.TodoItem
 	todos.PendingReviews = make([]output.TodoItem, len(pendingReviews))
 	for i, item := range pendingReviews {
-		todos.PendingReviews[i] = output.TodoItem{
+		todos.PendingReviews[i] = convertGitHubTodoItem(item)
+	}
+
+	return todos, nil
+}
+
+// convertGitHubTodoItem converts a github.TodoItem to an output.TodoItem
+func convertGitHubTodoItem(item github.TodoItem) output.TodoItem {
+	return output.TodoItem{
+		ID:            item.ID,
+		Title:         item.Title,
+		Description:   item.Description,
+		URL:           item.URL,
+		UpdatedAt:     item.UpdatedAt,
+		Tags:          item.Tags,
+		Milestone:     item.Milestone,
+		ProjectStatus: item.ProjectStatus,
+		ReadyToMerge:  item.ReadyToMerge,
+		Details:       item.Details,
+		Provenance:    convertProvenance(item.Provenance),
+	}
+}
+
+// getGitLabTodos gathers the open merge requests, pending reviews and assigned issues of the
+// user. The errors of the lists that failed are returned as warnings along with the others,
+// it only fails when every list does.
+func getGitLabTodos(ctx context.Context, provider *gitlab.Provider) (output.GitLabTodos, []error, error) {
+	var todos output.GitLabTodos
+	var warnings []error
+
+	// The three lists are independent lookups
+	lookups := []gitlabLookup{
+		provider.GetOpenMRs,
+		provider.GetPendingReviews,
+		provider.GetAssignedIssues,
+	}
+	results := concurrency.Map(ctx, lookups, gitlabLookups, func(ctx context.Context, _ int, lookup gitlabLookup) ([]gitlab.TodoItem, error) {
+		return lookup(ctx)
+	})
+
+	sections := []struct {
+		items *[]output.TodoItem
+		name  string
+	}{
+		{&todos.OpenMRs, "open MRs"},
+		{&todos.PendingReviews, "pending MR reviews"},
+		{&todos.AssignedIssues, "assigned GitLab issues"},
+	}
+	for i, section := range sections {
+		if err := results[i].Err; err != nil {
+			warnings = append(warnings, fmt.Errorf("failed to get %s: %w", section.name, err))
+			continue
+		}
+		*section.items = convertGitLabTodoItems(results[i].Value)
+	}
+
+	if len(warnings) == len(sections) {
+		return todos, nil, errors.Join(warnings...)
+	}
+	return todos, warnings, nil
+}
+
+// convertGitLabTodoItems converts gitlab.TodoItems to output.TodoItems
+func convertGitLabTodoItems(items []gitlab.TodoItem) []output.TodoItem {
+	converted := make([]output.TodoItem, len(items))
+	for i, item := range items {
+		converted[i] = output.TodoItem{
 			ID:          item.ID,
 			Title:       item.Title,
 			Description: item.Description,
 			URL:         item.URL,
 			UpdatedAt:   item.UpdatedAt,
 			Tags:        item.Tags,
+			Details:     item.Details,
+			Provenance:  convertProvenance(item.Provenance),
 		}
 	}
+	return converted
+}
 
-	return todos, nil
+// convertProvenance converts the provenance of a provider item to the output type
+func convertProvenance(provenance *provider.Provenance) *output.Provenance {
+	if provenance == nil {
+		return nil
+	}
+	result := output.Provenance(*provenance)
+	return &result
+}
+
+// jiraTodoOptions selects the optional JIRA todo sections
+type jiraTodoOptions struct {
+	includeWatched  bool // List recently updated issues the user watches
+	includeReported bool // List recently updated issues the user reported
+	rollupSubtasks  bool // Nest assigned subtasks under their parent issue
 }
 
-func getJIRATodos(ctx context.Context, provider *jira.Provider) (output.JIRATodos, error) {
+// getJIRATodos gathers the JIRA todo sections. It fails when the assigned tickets can't be
+// fetched, the errors of the other sections are returned as warnings along with the sections
+// that succeeded.
+func getJIRATodos(ctx context.Context, provider *jira.Provider, since string, opts jiraTodoOptions) (output.JIRATodos, []error, error) {
 	var todos output.JIRATodos
+	var warnings []error
 
 	// Get assigned tickets that are not done
 	assignedTickets, err := provider.GetAssignedTickets(ctx)
 	if err != nil {
-		return todos, fmt.Errorf("failed to get assigned tickets: %w", err)
+		return todos, nil, fmt.Errorf("failed to get assigned tickets: %w", err)
 	}
 
 	// Convert from jira.TodoItem to output.TodoItem
-	todos.AssignedTickets = make([]output.TodoItem, len(assignedTickets))
-	for i, item := range assignedTickets {
-		todos.AssignedTickets[i] = output.TodoItem{
+	if opts.rollupSubtasks {
+		todos.AssignedTickets = rollupSubtasks(assignedTickets)
+	} else {
+		todos.AssignedTickets = make([]output.TodoItem, len(assignedTickets))
+		for i, item := range assignedTickets {
+			todos.AssignedTickets[i] = convertJIRATicket(item)
+		}
+	}
+
+	markOverdueTickets(todos.AssignedTickets, nowFunc())
+	for i := range todos.AssignedTickets {
+		markOverdueTickets(todos.AssignedTickets[i].Subtasks, nowFunc())
+	}
+
+	// Get issues where the user was mentioned in a comment
+	mentions, err := provider.GetMentions(ctx, since)
+	if err != nil {
+		warnings = append(warnings, fmt.Errorf("failed to get JIRA mentions: %w", err))
+	}
+
+	// Skip mentions on issues already listed as assigned tickets
+	assignedIssues := make(map[string]bool)
+	for _, item := range todos.AssignedTickets {
+		assignedIssues[issueURL(item.URL)] = true
+		for _, subtask := range item.Subtasks {
+			assignedIssues[issueURL(subtask.URL)] = true
+		}
+	}
+
+	todos.Mentions = []output.TodoItem{}
+	for _, item := range mentions {
+		if assignedIssues[issueURL(item.URL)] {
+			continue
+		}
+		todos.Mentions = append(todos.Mentions, output.TodoItem{
 			ID:          item.ID,
 			Title:       item.Title,
 			Description: item.Description,
 			URL:         item.URL,
 			UpdatedAt:   item.UpdatedAt,
 			Tags:        item.Tags,
+			Details:     item.Details,
+			Provenance:  convertProvenance(item.Provenance),
+		})
+	}
+
+	if opts.includeReported {
+		reported, err := provider.GetReportedIssues(ctx, since)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("failed to get reported JIRA issues: %w", err))
+		} else {
+			todos.Reported = convertJIRAFollowedIssues(reported)
 		}
 	}
 
-	return todos, nil
+	if opts.includeWatched {
+		watched, err := provider.GetWatchedIssues(ctx, since)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("failed to get watched JIRA issues: %w", err))
+		} else {
+			todos.Watched = convertJIRAFollowedIssues(watched)
+		}
+	}
+
+	dedupeJIRAIssues(&todos)
+
+	return todos, warnings, nil
+}
+
+// convertJIRATicket converts an assigned jira.TodoItem to an output.TodoItem
+func convertJIRATicket(item jira.TodoItem) output.TodoItem {
+	return output.TodoItem{
+		ID:          item.ID,
+		Key:         item.Key,
+		Title:       item.Title,
+		Description: item.Description,
+		URL:         item.URL,
+		UpdatedAt:   item.UpdatedAt,
+		Tags:        item.Tags,
+		Priority:    item.Priority,
+		DueDate:     item.DueDate,
+		Sprint:      item.Sprint,
+		IssueType:   item.IssueType,
+
+		StatusCategory: item.StatusCategory,
+		StatusSection:  item.StatusSection,
+		Details:        item.Details,
+		Provenance:     convertProvenance(item.Provenance),
+	}
+}
+
+// rollupSubtasks converts assigned tickets, nesting subtasks under their parent issue. Parents
+// that aren't assigned to the user are listed for their subtasks, dated by their latest subtask.
+// Tickets keep the order in which they, or their first subtask, were listed.
+func rollupSubtasks(tickets []jira.TodoItem) []output.TodoItem {
+	var result []output.TodoItem
+	positions := make(map[string]int) // Position of each parent or ticket in result, by key
+	unassigned := make(map[string]bool)
+
+	for _, ticket := range tickets {
+		if ticket.Parent == nil {
+			if i, ok := positions[ticket.Key]; ok {
+				// Replace the parent listed for its subtasks
+				subtasks := result[i].Subtasks
+				result[i] = convertJIRATicket(ticket)
+				result[i].Subtasks = subtasks
+				delete(unassigned, ticket.Key)
+				continue
+			}
+			positions[ticket.Key] = len(result)
+			result = append(result, convertJIRATicket(ticket))
+			continue
+		}
+
+		i, ok := positions[ticket.Parent.Key]
+		if !ok {
+			i = len(result)
+			positions[ticket.Parent.Key] = i
+			unassigned[ticket.Parent.Key] = true
+			result = append(result, convertJIRATicket(*ticket.Parent))
+		}
+		if unassigned[ticket.Parent.Key] && ticket.UpdatedAt.After(result[i].UpdatedAt) {
+			result[i].UpdatedAt = ticket.UpdatedAt
+		}
+		result[i].Subtasks = append(result[i].Subtasks, convertJIRATicket(ticket))
+	}
+
+	return result
+}
+
+// convertJIRAFollowedIssues converts watched or reported issues to output.TodoItem
+func convertJIRAFollowedIssues(items []jira.TodoItem) []output.TodoItem {
+	todos := make([]output.TodoItem, len(items))
+	for i, item := range items {
+		todos[i] = output.TodoItem{
+			ID:             item.ID,
+			Key:            item.Key,
+			Title:          item.Title,
+			Description:    item.Description,
+			URL:            item.URL,
+			UpdatedAt:      item.UpdatedAt,
+			Tags:           item.Tags,
+			Priority:       item.Priority,
+			IssueType:      item.IssueType,
+			StatusCategory: item.StatusCategory,
+			Details:        item.Details,
+			Provenance:     convertProvenance(item.Provenance),
+		}
+	}
+	return todos
+}
+
+// dedupeJIRAIssues lists each issue in a single section: assigned tickets first, then reported
+// and finally watched issues. The tags of dropped duplicates are added to the kept item.
+func dedupeJIRAIssues(todos *output.JIRATodos) {
+	kept := make(map[string]*output.TodoItem)
+
+	dedupe := func(items []output.TodoItem) []output.TodoItem {
+		unique := make([]output.TodoItem, 0, len(items))
+		for _, item := range items {
+			url := issueURL(item.URL)
+			if existing, ok := kept[url]; ok {
+				existing.Tags = mergeTags(existing.Tags, item.Tags)
+				continue
+			}
+			unique = append(unique, item)
+		}
+		return unique
+	}
+
+	// Assigned tickets and their subtasks are never dropped, so only index them
+	for i := range todos.AssignedTickets {
+		kept[issueURL(todos.AssignedTickets[i].URL)] = &todos.AssignedTickets[i]
+		for j := range todos.AssignedTickets[i].Subtasks {
+			subtask := &todos.AssignedTickets[i].Subtasks[j]
+			kept[issueURL(subtask.URL)] = subtask
+		}
+	}
+
+	if todos.Reported != nil {
+		todos.Reported = dedupe(todos.Reported)
+		for i := range todos.Reported {
+			kept[issueURL(todos.Reported[i].URL)] = &todos.Reported[i]
+		}
+	}
+
+	if todos.Watched != nil {
+		todos.Watched = dedupe(todos.Watched)
+	}
+}
+
+// mergeTags appends the tags of other that are not already in tags
+func mergeTags(tags, other []string) []string {
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		seen[tag] = true
+	}
+	for _, tag := range other {
+		if !seen[tag] {
+			tags = append(tags, tag)
+			seen[tag] = true
+		}
+	}
+	return tags
+}
+
+// commentEnrichment limits the comment requests made with --details to 5 concurrent requests, 1 request every 100ms
+var commentEnrichment = concurrency.Options{Workers: 5, Delay: 100 * time.Millisecond}
+
+// githubPREnrichment limits the project status and readiness lookups of open PRs to 5
+// concurrent PRs
+var githubPREnrichment = concurrency.Options{Workers: 5}
+
+// confluenceLookup is a Confluence search made for the todo command
+type confluenceLookup func(ctx context.Context, since string) ([]confluence.TodoItem, error)
+
+// confluenceLookups runs the Confluence searches of the todo command in parallel
+var confluenceLookups = concurrency.Options{Workers: 3}
+
+// gitlabLookup is a GitLab list fetched for the todo command
+type gitlabLookup func(ctx context.Context) ([]gitlab.TodoItem, error)
+
+// gitlabLookups runs the GitLab lookups of the todo command in parallel
+var gitlabLookups = concurrency.Options{Workers: 3}
+
+// attachJIRAComments adds the latest comments to each assigned ticket. Tickets whose
+// comments can't be fetched are left without comments and the errors are returned.
+func attachJIRAComments(ctx context.Context, provider *jira.Provider, tickets []output.TodoItem) []error {
+	results := concurrency.Map(ctx, tickets, commentEnrichment, func(ctx context.Context, _ int, item output.TodoItem) ([]jira.Comment, error) {
+		if item.Key == "" {
+			return nil, nil
+		}
+		return provider.GetRecentComments(ctx, item.Key, jira.DefaultRecentComments)
+	})
+
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		if result.Value == nil {
+			continue
+		}
+
+		tickets[i].Comments = make([]output.Comment, len(result.Value))
+		for j, comment := range result.Value {
+			tickets[i].Comments[j] = output.Comment{
+				Author:    comment.Author,
+				Body:      comment.Body,
+				CreatedAt: comment.CreatedAt,
+			}
+		}
+	}
+
+	return errs
+}
+
+// issueURL strips the query from an issue URL so links to a comment match the issue itself
+func issueURL(rawURL string) string {
+	issue, _, _ := strings.Cut(rawURL, "?")
+	return issue
 }
 
 func getObsidianTodos(ctx context.Context, provider *obsidian.Provider) (output.ObsidianTodos, error) {
@@ -268,62 +770,141 @@ func getObsidianTodos(ctx context.Context, provider *obsidian.Provider) (output.
 			URL:         item.URL,
 			UpdatedAt:   item.UpdatedAt,
 			Tags:        item.Tags,
+			Context:     item.Context,
+			ParentTask:  item.ParentTask,
+			Snippet:     item.Snippet,
+			Line:        item.Line,
+			Details:     item.Details,
+			Provenance:  convertProvenance(item.Provenance),
+
+			RelatedNotes: item.RelatedNotes,
+
+			Priority: string(item.Priority),
+			DueDate:  item.DueDate,
 		}
 	}
 
 	return todos, nil
 }
 
-func getConfluenceTodos(ctx context.Context, provider *confluence.Provider, since string) (output.ConfluenceTodos, error) {
+// getConfluenceTodos gathers the Confluence todo sections, with the watched pages updated by
+// others when includeWatched is set. It fails when the mentions or the comments on the user's
+// pages can't be fetched, the errors of the tasks and watched pages are returned as warnings.
+func getConfluenceTodos(ctx context.Context, provider *confluence.Provider, since string, includeWatched bool) (output.ConfluenceTodos, []error, error) {
 	var todos output.ConfluenceTodos
-
-	// Get mentions from Confluence
-	mentions, err := provider.GetMentions(ctx, since)
-	if err != nil {
-		return todos, fmt.Errorf("failed to get Confluence mentions: %w", err)
+	var warnings []error
+
+	// Mentions, comments on pages created by the user, assigned tasks and watched pages are
+	// independent lookups
+	lookups := []confluenceLookup{
+		provider.GetMentions,
+		provider.GetCommentsOnMyPages,
+		func(ctx context.Context, _ string) ([]confluence.TodoItem, error) {
+			return provider.GetAssignedInlineTasks(ctx)
+		},
 	}
-
-	// Get comments on pages created by the user
-	commentsOnMyPages, err := provider.GetCommentsOnMyPages(ctx, since)
-	if err != nil {
-		return todos, fmt.Errorf("failed to get comments on my pages: %w", err)
+	if includeWatched {
+		lookups = append(lookups, provider.GetWatchedUpdates)
 	}
+	results := concurrency.Map(ctx, lookups, confluenceLookups, func(ctx context.Context, _ int, lookup confluenceLookup) ([]confluence.TodoItem, error) {
+		return lookup(ctx, since)
+	})
 
-	// Combine results and deduplicate by ID
-	seenIDs := make(map[string]bool)
-	var allItems []output.TodoItem
+	if err := results[0].Err; err != nil {
+		return todos, nil, fmt.Errorf("failed to get Confluence mentions: %w", err)
+	}
+	if err := results[1].Err; err != nil {
+		return todos, nil, fmt.Errorf("failed to get comments on my pages: %w", err)
+	}
+	if err := results[2].Err; err != nil {
+		warnings = append(warnings, fmt.Errorf("failed to get Confluence tasks: %w", err))
+	}
+	if includeWatched && results[3].Err != nil {
+		warnings = append(warnings, fmt.Errorf("failed to get watched Confluence pages: %w", results[3].Err))
+	}
+	mentions, commentsOnMyPages := results[0].Value, results[1].Value
 
-	// Add mentions first
+	// A comment that mentions the user is only listed with the mentions
+	listed := make(map[string]bool, len(mentions))
 	for _, item := range mentions {
-		if !seenIDs[item.ID] {
-			allItems = append(allItems, output.TodoItem{
-				ID:          item.ID,
-				Title:       item.Title,
-				Description: item.Description,
-				URL:         item.URL,
-				UpdatedAt:   item.UpdatedAt,
-				Tags:        item.Tags,
-			})
-			seenIDs[item.ID] = true
+		if !listed[item.ID] {
+			todos.Mentions = append(todos.Mentions, convertConfluenceTodoItem(item))
+			listed[item.ID] = true
 		}
 	}
-
-	// Add comments on my pages (skip duplicates)
 	for _, item := range commentsOnMyPages {
-		if !seenIDs[item.ID] {
-			allItems = append(allItems, output.TodoItem{
-				ID:          item.ID,
-				Title:       item.Title,
-				Description: item.Description,
-				URL:         item.URL,
-				UpdatedAt:   item.UpdatedAt,
-				Tags:        item.Tags,
-			})
-			seenIDs[item.ID] = true
+		if !listed[item.ID] {
+			todos.CommentsOnMyPages = append(todos.CommentsOnMyPages, convertConfluenceTodoItem(item))
+			listed[item.ID] = true
+		}
+	}
+	for _, item := range results[2].Value {
+		todos.Tasks = append(todos.Tasks, convertConfluenceTodoItem(item))
+	}
+	if includeWatched {
+		// A watched page that mentions the user is only listed with the mentions
+		for _, item := range results[3].Value {
+			if !listed[item.ID] {
+				todos.Watched = append(todos.Watched, convertConfluenceTodoItem(item))
+				listed[item.ID] = true
+			}
 		}
 	}
 
-	todos.Mentions = allItems
+	return todos, warnings, nil
+}
+
+// convertConfluenceTodoItem converts a confluence.TodoItem to an output.TodoItem
+func convertConfluenceTodoItem(item confluence.TodoItem) output.TodoItem {
+	return output.TodoItem{
+		ID:          item.ID,
+		Title:       item.Title,
+		Description: item.Description,
+		Excerpt:     item.Excerpt,
+		URL:         item.URL,
+		UpdatedAt:   item.UpdatedAt,
+		Tags:        item.Tags,
+		Details:     item.Details,
+		Provenance:  convertProvenance(item.Provenance),
+	}
+}
+
+// getSlackTodos gathers the messages the user saved in Slack
+func getSlackTodos(ctx context.Context, provider *slack.Provider) (output.SlackTodos, error) {
+	var todos output.SlackTodos
+
+	saved, err := provider.GetSavedItems(ctx)
+	if err != nil {
+		return todos, err
+	}
 
+	todos.Saved = make([]output.TodoItem, len(saved))
+	for i, item := range saved {
+		todos.Saved[i] = output.TodoItem{
+			ID:          item.ID,
+			Title:       item.Title,
+			Description: item.Description,
+			Excerpt:     item.Excerpt,
+			URL:         item.URL,
+			UpdatedAt:   item.UpdatedAt,
+			Tags:        item.Tags,
+			Details:     item.Details,
+			Provenance:  convertProvenance(item.Provenance),
+		}
+	}
 	return todos, nil
 }
+
+// markOverdueTickets flags the items whose due date is before the current day
+func markOverdueTickets(items []output.TodoItem, now time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i := range items {
+		if items[i].DueDate == nil {
+			continue
+		}
+		// Due dates are calendar dates, so compare days rather than instants
+		due := *items[i].DueDate
+		dueDay := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, now.Location())
+		items[i].IsOverdue = dueDay.Before(today)
+	}
+}
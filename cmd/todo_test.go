@@ -1,14 +1,27 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"daily/internal/config"
+	"daily/internal/metrics"
 	"daily/internal/output"
 	"daily/internal/provider"
+	"daily/internal/provider/confluence"
 	"daily/internal/provider/github"
+	"daily/internal/provider/gitlab"
 	"daily/internal/provider/jira"
+	"daily/internal/provider/obsidian"
 )
 
 func TestGetGitHubTodos(t *testing.T) {
@@ -83,7 +96,7 @@ func TestGetJIRATodos(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			provider := jira.NewProvider(tt.config)
 
-			todos, err := getJIRATodos(context.Background(), provider)
+			todos, _, err := getJIRATodos(context.Background(), provider, "2w", jiraTodoOptions{})
 
 			if tt.expectError {
 				if err == nil {
@@ -192,3 +205,592 @@ func TestTodoCmd_FlagValidation(t *testing.T) {
 		t.Errorf("Expected error message to contain '%s', got '%s'", expectedErrMsg, err.Error())
 	}
 }
+
+func TestMarkOverdueTickets(t *testing.T) {
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	nowFunc = func() time.Time { return time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC) }
+
+	date := func(year int, month time.Month, day int) *time.Time {
+		d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	items := []output.TodoItem{
+		{ID: "yesterday", DueDate: date(2024, 3, 9)},
+		{ID: "today", DueDate: date(2024, 3, 10)},
+		{ID: "tomorrow", DueDate: date(2024, 3, 11)},
+		{ID: "no-due-date"},
+	}
+
+	markOverdueTickets(items, nowFunc())
+
+	expected := map[string]bool{
+		"yesterday":   true,
+		"today":       false,
+		"tomorrow":    false,
+		"no-due-date": false,
+	}
+
+	for _, item := range items {
+		if item.IsOverdue != expected[item.ID] {
+			t.Errorf("%s: expected IsOverdue %t, got %t", item.ID, expected[item.ID], item.IsOverdue)
+		}
+	}
+}
+
+func TestGetJIRATodos_DeduplicatesMentions(t *testing.T) {
+	created := time.Now().Add(-time.Hour).UTC().Format("2006-01-02T15:04:05.000-0700")
+	issue := func(key string) string {
+		return fmt.Sprintf(`{"key":%q,"fields":{"summary":"Summary %s","updated":%q,"status":{"name":"To Do"}}}`, key, key, created)
+	}
+	comments := fmt.Sprintf(`{"comments":[{"id":"1","author":{"accountId":"alice","displayName":"Alice"},"created":%q,
+		"body":{"type":"doc","content":[{"type":"mention","attrs":{"id":"me"}}]}}]}`, created)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/search/jql":
+			if strings.Contains(r.URL.Query().Get("jql"), "comment ~ currentUser()") {
+				_, _ = fmt.Fprintf(w, `{"issues":[%s,%s],"isLast":true}`, issue("PROJ-1"), issue("PROJ-2"))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"issues":[%s],"isLast":true}`, issue("PROJ-1"))
+		case "/rest/api/3/myself":
+			_, _ = fmt.Fprint(w, `{"accountId":"me"}`)
+		case "/rest/api/3/issue/PROJ-1/comment", "/rest/api/3/issue/PROJ-2/comment":
+			_, _ = fmt.Fprint(w, comments)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	jiraProvider := jira.NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	todos, _, err := getJIRATodos(context.Background(), jiraProvider, "1w", jiraTodoOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(todos.AssignedTickets) != 1 {
+		t.Errorf("Expected 1 assigned ticket, got %d", len(todos.AssignedTickets))
+	}
+	if len(todos.Mentions) != 1 {
+		t.Fatalf("Expected 1 mention after removing assigned issues, got %d", len(todos.Mentions))
+	}
+	if todos.Mentions[0].ID != "jira-PROJ-2-mention-1" {
+		t.Errorf("Expected mention on PROJ-2, got '%s'", todos.Mentions[0].ID)
+	}
+}
+
+func TestAttachJIRAComments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/issue/PROJ-1/comment":
+			_, _ = fmt.Fprint(w, `{"comments":[{"author":{"displayName":"Alice"},"created":"2024-01-15T12:00:00.000+0000","body":"On it"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	jiraProvider := jira.NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	tickets := []output.TodoItem{
+		{ID: "jira-PROJ-1", Key: "PROJ-1"},
+		{ID: "jira-PROJ-2", Key: "PROJ-2"},
+	}
+
+	errs := attachJIRAComments(context.Background(), jiraProvider, tickets)
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error for the failing ticket, got %v", errs)
+	}
+
+	if len(tickets[0].Comments) != 1 || tickets[0].Comments[0].Author != "Alice" || tickets[0].Comments[0].Body != "On it" {
+		t.Errorf("Expected comment from Alice on PROJ-1, got %+v", tickets[0].Comments)
+	}
+	if tickets[1].Comments != nil {
+		t.Errorf("Expected no comments on PROJ-2, got %+v", tickets[1].Comments)
+	}
+}
+
+func TestGetGitLabTodos(t *testing.T) {
+	failAll := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case failAll:
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.URL.Path == "/api/v4/issues":
+			_, _ = fmt.Fprint(w, `[{"id": 3, "iid": 31, "title": "Login fails with SSO", "references": {"full": "acme/api#31"}}]`)
+		case r.URL.Path == "/api/v4/user":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			_, _ = fmt.Fprint(w, `[{"id": 1, "iid": 12, "title": "Add the login form", "references": {"full": "acme/api!12"}}]`)
+		}
+	}))
+	defer server.Close()
+
+	gitlabProvider := gitlab.NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true})
+
+	// The failed pending reviews are a warning, the other lists are kept
+	todos, warnings, err := getGitLabTodos(context.Background(), gitlabProvider)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "failed to get pending MR reviews") {
+		t.Errorf("Expected the pending reviews warning, got %v", warnings)
+	}
+	if len(todos.OpenMRs) != 1 || len(todos.AssignedIssues) != 1 || todos.PendingReviews != nil {
+		t.Errorf("Expected the open MRs and assigned issues, got %+v", todos)
+	}
+
+	// Failing every list fails
+	failAll = true
+	if _, _, err := getGitLabTodos(context.Background(), gitlabProvider); err == nil {
+		t.Error("Expected an error when every list fails, got nil")
+	}
+}
+
+func TestGetConfluenceTodos(t *testing.T) {
+	failComments, failWatched, failTasks := false, false, false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cql := r.URL.Query().Get("cql")
+		switch {
+		case r.URL.Path == "/wiki/rest/api/user/current":
+			_, _ = fmt.Fprint(w, `{"accountId":"me"}`)
+		case r.URL.Path == "/wiki/rest/api/inlinetasks/search" && failTasks:
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.URL.Path == "/wiki/rest/api/inlinetasks/search":
+			_, _ = fmt.Fprint(w, `{"results":[{"id":7,"contentId":10,"status":"incomplete","title":"My page","description":"Update the runbook"}]}`)
+		case strings.HasPrefix(cql, "mention"):
+			_, _ = fmt.Fprint(w, `{"results":[{"content":{"id":"1","title":"Design doc","type":"page"},"url":"/pages/1"}]}`)
+		case strings.HasPrefix(cql, "watcher") && failWatched:
+			w.WriteHeader(http.StatusBadRequest)
+		case strings.HasPrefix(cql, "watcher"):
+			_, _ = fmt.Fprint(w, `{"results":[
+				{"content":{"id":"1","title":"Design doc","type":"page"},"url":"/pages/1"},
+				{"content":{"id":"20","title":"Onboarding","type":"page","version":{"by":{"displayName":"Alice"}}},"url":"/pages/20"}
+			]}`)
+		case strings.HasPrefix(cql, "creator"):
+			_, _ = fmt.Fprint(w, `{"results":[{"content":{"id":"10","title":"My page","type":"page"}}]}`)
+		case strings.HasPrefix(cql, "type = comment"):
+			if failComments {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"results":[
+				{"content":{"id":"1","title":"Design doc","type":"comment"},"resultParentContainer":{"id":"10"}},
+				{"content":{"id":"2","title":"Re: My page","type":"comment"},"resultParentContainer":{"id":"10"}},
+				{"content":{"id":"3","title":"Elsewhere","type":"comment"},"resultParentContainer":{"id":"99"}}
+			]}`)
+		default:
+			t.Errorf("Unexpected CQL: %s", cql)
+		}
+	}))
+	defer server.Close()
+
+	confluenceProvider := confluence.NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	todos, _, err := getConfluenceTodos(context.Background(), confluenceProvider, "1w", false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The comment with the same ID as a mention is only listed with the mentions
+	if len(todos.Mentions) != 1 || todos.Mentions[0].ID != "1" {
+		t.Fatalf("Expected the mention, got %+v", todos.Mentions)
+	}
+	if len(todos.CommentsOnMyPages) != 1 || todos.CommentsOnMyPages[0].ID != "2" {
+		t.Fatalf("Expected the comment on my page, got %+v", todos.CommentsOnMyPages)
+	}
+	if len(todos.Tasks) != 1 || todos.Tasks[0].Title != "Update the runbook" {
+		t.Fatalf("Expected the assigned task, got %+v", todos.Tasks)
+	}
+	if todos.Watched != nil {
+		t.Fatalf("Expected no watched pages without include_watched, got %+v", todos.Watched)
+	}
+
+	// The watched page that mentions the user is only listed with the mentions
+	todos, _, err = getConfluenceTodos(context.Background(), confluenceProvider, "1w", true)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos.Watched) != 1 || todos.Watched[0].Title != "Updated: Onboarding" || todos.Watched[0].Description != "Updated by Alice" {
+		t.Fatalf("Expected the watched page updated by Alice, got %+v", todos.Watched)
+	}
+
+	// Failed tasks and watched searches are warnings, the other sections are kept
+	failWatched, failTasks = true, true
+	todos, warnings, err := getConfluenceTodos(context.Background(), confluenceProvider, "1w", true)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0].Error(), "failed to get Confluence tasks") ||
+		!strings.Contains(warnings[1].Error(), "failed to get watched Confluence pages") {
+		t.Errorf("Expected the tasks and watched search warnings, got %v", warnings)
+	}
+	if len(todos.Mentions) != 1 || len(todos.CommentsOnMyPages) != 1 || todos.Tasks != nil || todos.Watched != nil {
+		t.Errorf("Expected the mentions and comments without tasks nor watched pages, got %+v", todos)
+	}
+
+	failComments = true
+	_, _, err = getConfluenceTodos(context.Background(), confluenceProvider, "1w", false)
+	if err == nil || !strings.Contains(err.Error(), "failed to get comments on my pages") {
+		t.Errorf("Expected comments error, got: %v", err)
+	}
+}
+
+func TestCollectTodos_ConfluenceSince(t *testing.T) {
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cql := r.URL.Query().Get("cql")
+		if strings.HasPrefix(cql, "mention") {
+			_, since, _ := strings.Cut(cql, "now(")
+			ranges = append(ranges, since)
+		}
+		_, _ = fmt.Fprint(w, `{"results":[]}`)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Confluence: provider.Config{
+		Email:     "test@example.com",
+		Token:     "testtoken",
+		URL:       server.URL,
+		Enabled:   true,
+		TodoSince: "3d",
+	}}
+
+	tests := []struct {
+		name     string
+		since    string
+		expected string
+	}{
+		{"configured range", "", `"-3d")`},
+		{"--since wins", "1w", `"-1w")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges = nil
+			collectTodos(context.Background(), cfg, todoOptions{since: tt.since})
+			if len(ranges) != 1 || ranges[0] != tt.expected {
+				t.Errorf("Expected mentions since %s, got %v", tt.expected, ranges)
+			}
+		})
+	}
+
+	cfg.Confluence.TodoSince = ""
+	ranges = nil
+	collectTodos(context.Background(), cfg, todoOptions{})
+	if len(ranges) != 1 || ranges[0] != `"-2w")` {
+		t.Errorf("Expected mentions of the last 2 weeks by default, got %v", ranges)
+	}
+}
+
+func TestDedupeJIRAIssues(t *testing.T) {
+	todos := output.JIRATodos{
+		AssignedTickets: []output.TodoItem{
+			{ID: "jira-PROJ-1", URL: "https://jira/browse/PROJ-1", Tags: []string{"PROJ-1", "In Progress"}},
+		},
+		Reported: []output.TodoItem{
+			{ID: "jira-PROJ-1", URL: "https://jira/browse/PROJ-1", Tags: []string{"PROJ-1", "In Progress", "reported"}},
+			{ID: "jira-PROJ-2", URL: "https://jira/browse/PROJ-2", Tags: []string{"PROJ-2", "Open", "reported"}},
+		},
+		Watched: []output.TodoItem{
+			{ID: "jira-PROJ-1", URL: "https://jira/browse/PROJ-1", Tags: []string{"PROJ-1", "In Progress", "watching"}},
+			{ID: "jira-PROJ-2", URL: "https://jira/browse/PROJ-2", Tags: []string{"PROJ-2", "Open", "watching"}},
+			{ID: "jira-PROJ-3", URL: "https://jira/browse/PROJ-3", Tags: []string{"PROJ-3", "Done", "watching"}},
+		},
+	}
+
+	dedupeJIRAIssues(&todos)
+
+	if len(todos.AssignedTickets) != 1 || len(todos.Reported) != 1 || len(todos.Watched) != 1 {
+		t.Fatalf("Expected 1 issue per section, got %d assigned, %d reported and %d watched",
+			len(todos.AssignedTickets), len(todos.Reported), len(todos.Watched))
+	}
+	if todos.Reported[0].ID != "jira-PROJ-2" || todos.Watched[0].ID != "jira-PROJ-3" {
+		t.Errorf("Expected PROJ-2 reported and PROJ-3 watched, got %s and %s", todos.Reported[0].ID, todos.Watched[0].ID)
+	}
+
+	expectedTags := map[string][]string{
+		"assigned": {"PROJ-1", "In Progress", "reported", "watching"},
+		"reported": {"PROJ-2", "Open", "reported", "watching"},
+	}
+	for section, tags := range map[string][]string{"assigned": todos.AssignedTickets[0].Tags, "reported": todos.Reported[0].Tags} {
+		if strings.Join(tags, ",") != strings.Join(expectedTags[section], ",") {
+			t.Errorf("Expected %s tags %v, got %v", section, expectedTags[section], tags)
+		}
+	}
+}
+
+func TestGetJIRATodos_FollowedIssues(t *testing.T) {
+	issue := func(key string) string {
+		return fmt.Sprintf(`{"key":%q,"fields":{"summary":"Summary","updated":"2024-01-15T10:00:00.000+0000","status":{"name":"Open"}}}`, key)
+	}
+
+	var searches []string
+	failOptional := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jql := r.URL.Query().Get("jql")
+		searches = append(searches, jql)
+		switch {
+		case failOptional && !strings.Contains(jql, "assignee = currentUser()"):
+			w.WriteHeader(http.StatusBadRequest)
+		case strings.Contains(jql, "watcher = currentUser()"):
+			_, _ = fmt.Fprintf(w, `{"issues":[%s,%s],"isLast":true}`, issue("PROJ-1"), issue("PROJ-3"))
+		case strings.Contains(jql, "reporter = currentUser()"):
+			_, _ = fmt.Fprintf(w, `{"issues":[%s],"isLast":true}`, issue("PROJ-2"))
+		case strings.Contains(jql, "assignee = currentUser()"):
+			_, _ = fmt.Fprintf(w, `{"issues":[%s],"isLast":true}`, issue("PROJ-1"))
+		default:
+			_, _ = fmt.Fprint(w, `{"issues":[],"isLast":true}`)
+		}
+	}))
+	defer server.Close()
+
+	jiraProvider := jira.NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	todos, _, err := getJIRATodos(context.Background(), jiraProvider, "1w", jiraTodoOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if todos.Watched != nil || todos.Reported != nil {
+		t.Error("Expected no watched or reported issues when disabled")
+	}
+	for _, jql := range searches {
+		if strings.Contains(jql, "watcher") || strings.Contains(jql, "reporter") {
+			t.Errorf("Expected no watched or reported search when disabled, got '%s'", jql)
+		}
+	}
+
+	todos, _, err = getJIRATodos(context.Background(), jiraProvider, "1w", jiraTodoOptions{includeWatched: true, includeReported: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos.Reported) != 1 || todos.Reported[0].Key != "PROJ-2" {
+		t.Errorf("Expected PROJ-2 to be reported, got %+v", todos.Reported)
+	}
+	// PROJ-1 is assigned, so only PROJ-3 stays in the watched issues
+	if len(todos.Watched) != 1 || todos.Watched[0].Key != "PROJ-3" {
+		t.Errorf("Expected only PROJ-3 to be watched, got %+v", todos.Watched)
+	}
+	if !strings.Contains(strings.Join(todos.AssignedTickets[0].Tags, ","), "watching") {
+		t.Errorf("Expected the assigned ticket to be tagged as watched, got %v", todos.AssignedTickets[0].Tags)
+	}
+
+	// Failed mentions, reported and watched searches are warnings, the assigned tickets are kept
+	failOptional = true
+	todos, warnings, err := getJIRATodos(context.Background(), jiraProvider, "1w", jiraTodoOptions{includeWatched: true, includeReported: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(warnings) != 3 || !strings.Contains(warnings[0].Error(), "failed to get JIRA mentions") ||
+		!strings.Contains(warnings[1].Error(), "failed to get reported JIRA issues") || !strings.Contains(warnings[2].Error(), "failed to get watched JIRA issues") {
+		t.Errorf("Expected a warning per failed search, got %v", warnings)
+	}
+	if len(todos.AssignedTickets) != 1 || len(todos.Mentions) != 0 || todos.Reported != nil || todos.Watched != nil {
+		t.Errorf("Expected only the assigned ticket, got %+v", todos)
+	}
+}
+
+func TestRollupSubtasks(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2024, 1, 15, hour, 0, 0, 0, time.UTC) }
+	story := &jira.TodoItem{ID: "jira-PROJ-1", Key: "PROJ-1", Title: "PROJ-1: Checkout", IssueType: "Story"}
+	epic := &jira.TodoItem{ID: "jira-PROJ-5", Key: "PROJ-5", Title: "PROJ-5: Search"}
+
+	tickets := []jira.TodoItem{
+		{ID: "jira-PROJ-2", Key: "PROJ-2", UpdatedAt: at(12), Parent: story},
+		{ID: "jira-PROJ-9", Key: "PROJ-9", UpdatedAt: at(11)}, // Not a subtask
+		{ID: "jira-PROJ-6", Key: "PROJ-6", UpdatedAt: at(10), Parent: epic},
+		{ID: "jira-PROJ-1", Key: "PROJ-1", UpdatedAt: at(9), Title: "PROJ-1: Checkout", Priority: "High"}, // Assigned parent
+		{ID: "jira-PROJ-3", Key: "PROJ-3", UpdatedAt: at(8), Parent: story},
+		{ID: "jira-PROJ-7", Key: "PROJ-7", UpdatedAt: at(14), Parent: epic},
+		{ID: "jira-PROJ-4", Key: "PROJ-4", UpdatedAt: at(7), Parent: story},
+	}
+
+	result := rollupSubtasks(tickets)
+
+	expected := []struct {
+		key       string
+		subtasks  string
+		updatedAt time.Time
+	}{
+		{"PROJ-1", "PROJ-2,PROJ-3,PROJ-4", at(9)},
+		{"PROJ-9", "", at(11)},
+		{"PROJ-5", "PROJ-6,PROJ-7", at(14)}, // Unassigned parent, dated by its latest subtask
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d tickets, got %d: %+v", len(expected), len(result), result)
+	}
+	for i, want := range expected {
+		var subtasks []string
+		for _, subtask := range result[i].Subtasks {
+			subtasks = append(subtasks, subtask.Key)
+		}
+		if result[i].Key != want.key || strings.Join(subtasks, ",") != want.subtasks {
+			t.Errorf("Expected %s with subtasks %q, got %s with %q", want.key, want.subtasks, result[i].Key, strings.Join(subtasks, ","))
+		}
+		if !result[i].UpdatedAt.Equal(want.updatedAt) {
+			t.Errorf("Expected %s to be updated at %v, got %v", want.key, want.updatedAt, result[i].UpdatedAt)
+		}
+	}
+	if result[0].Priority != "High" {
+		t.Errorf("Expected the assigned parent to replace the one listed for its subtasks, got %+v", result[0])
+	}
+}
+
+func TestGetJIRATodos_RollupSubtasks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Query().Get("jql"), "assignee = currentUser()") {
+			_, _ = fmt.Fprint(w, `{"issues":[],"isLast":true}`)
+			return
+		}
+		subtask := func(key string) string {
+			return fmt.Sprintf(`{"key":%q,"fields":{"summary":"Subtask","updated":"2024-01-15T10:00:00.000+0000",
+				"status":{"name":"Open"},"issuetype":{"name":"Sub-task","subtask":true},
+				"parent":{"key":"PROJ-1","fields":{"summary":"Story","status":{"name":"In Progress"}}}}}`, key)
+		}
+		_, _ = fmt.Fprintf(w, `{"issues":[%s,%s],"isLast":true}`, subtask("PROJ-2"), subtask("PROJ-3"))
+	}))
+	defer server.Close()
+
+	jiraProvider := jira.NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+
+	todos, _, err := getJIRATodos(context.Background(), jiraProvider, "1w", jiraTodoOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos.AssignedTickets) != 2 {
+		t.Errorf("Expected subtasks to be listed when rollup is disabled, got %+v", todos.AssignedTickets)
+	}
+
+	todos, _, err = getJIRATodos(context.Background(), jiraProvider, "1w", jiraTodoOptions{rollupSubtasks: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos.AssignedTickets) != 1 || todos.AssignedTickets[0].Title != "PROJ-1: Story" || len(todos.AssignedTickets[0].Subtasks) != 2 {
+		t.Errorf("Expected PROJ-1 with 2 subtasks, got %+v", todos.AssignedTickets)
+	}
+}
+
+func TestGetObsidianTodos_Line(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // The note index is written under the home directory
+	vault := t.TempDir()
+	if err := os.WriteFile(filepath.Join(vault, "note.md"), []byte("# Notes\n- [ ] Call the bank\n"), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	todos, err := getObsidianTodos(context.Background(), obsidian.NewProvider(provider.Config{URL: vault, Enabled: true}))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos.Tasks) != 1 || todos.Tasks[0].Line != 2 {
+		t.Fatalf("Expected 1 task on line 2, got %+v", todos.Tasks)
+	}
+
+	result := output.NewPlainFormatter().FormatTodoJSON(output.TodoItems{Obsidian: todos})
+	if !strings.Contains(result, `"line": 2`) {
+		t.Errorf("Expected the line in the JSON output, got: %s", result)
+	}
+}
+
+func TestTodoCmd_Progress(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	vault := t.TempDir()
+	if err := os.WriteFile(filepath.Join(vault, "note.md"), []byte("- [ ] Call the bank\n- [ ] Book the venue\n"), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+	configDir := filepath.Join(home, ".config", "daily")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	config := fmt.Sprintf(`{"obsidian": {"enabled": true, "url": %q}}`, vault)
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(config), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	var stream bytes.Buffer
+	originalOutput := progressOutput
+	defer func() { progressOutput = originalOutput }()
+	progressOutput = &stream
+
+	cmd := TodoCmd()
+	cmd.SetArgs([]string{"-o", "json", "--progress", "json", "--out-file", filepath.Join(t.TempDir(), "todo.json")})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var events []metrics.Event
+	for _, line := range strings.Split(strings.TrimSuffix(stream.String(), "\n"), "\n") {
+		var event metrics.Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Expected one JSON event per line, got %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+
+	expected := []metrics.EventType{metrics.EventProviderStarted, metrics.EventProviderFinished, metrics.EventRunFinished}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d: %s", len(expected), len(events), stream.String())
+	}
+	for i, want := range expected {
+		if events[i].Type != want {
+			t.Errorf("Event %d: expected %s, got %s", i, want, events[i].Type)
+		}
+		if events[i].Time.IsZero() {
+			t.Errorf("Event %d: expected a timestamp", i)
+		}
+	}
+	if finished := events[1]; finished.Provider != "obsidian" || finished.Count == nil || *finished.Count != 2 || finished.DurationMS == nil {
+		t.Errorf("Expected Obsidian to return 2 tasks with a duration, got %+v", finished)
+	}
+	if run := events[2]; run.Error != "" || run.Metrics == nil {
+		t.Errorf("Expected a successful run with its metrics, got %+v", run)
+	}
+}
+
+func TestUniqueTodoItems(t *testing.T) {
+	todoItems := output.TodoItems{
+		GitHub: output.GitHubTodos{OpenPRs: []output.TodoItem{
+			{ID: "github-pr-1", Title: "Add caching"},
+			{ID: "github-pr-2", Title: "Fix docs"},
+			{ID: "github-pr-1", Title: "Add caching"},
+		}},
+		JIRA: output.JIRATodos{
+			AssignedTickets: []output.TodoItem{{ID: "jira-PROJ-1"}},
+			Mentions:        []output.TodoItem{{ID: "jira-PROJ-1"}},
+		},
+	}
+
+	uniqueTodoItems(&todoItems)
+
+	if len(todoItems.GitHub.OpenPRs) != 2 || todoItems.GitHub.OpenPRs[1].ID != "github-pr-2" {
+		t.Errorf("Expected the repeated PR dropped, got %+v", todoItems.GitHub.OpenPRs)
+	}
+	// Items of other sections are left alone
+	if len(todoItems.JIRA.AssignedTickets) != 1 || len(todoItems.JIRA.Mentions) != 1 {
+		t.Errorf("Expected the ticket kept in both sections, got %+v", todoItems.JIRA)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+
+	"daily/internal/activity"
+	"daily/internal/cache"
+	"daily/internal/config"
+	"daily/internal/output"
+	"daily/internal/translate"
+	"daily/internal/verboselog"
+)
+
+// newTranslator returns the translator of titles into the configured language, caching its
+// translations in the cache directory, or nil when translation is off. Translations aren't
+// cached when the cache is encrypted, as they hold the titles in plain text.
+func newTranslator(cfg *config.Config) *translate.Translator {
+	if !cfg.Translate.Enabled() {
+		return nil
+	}
+
+	var backend translate.Backend = translate.NewDictionary(cfg.Translate.Dictionary)
+	if cfg.Translate.Backend == translate.BackendDeepL {
+		backend = translate.NewDeepL(cfg.Translate.Token, cfg.Translate.URL)
+	}
+
+	var store translate.Store
+	if cfg.Cache.Encryption == "" {
+		if ttlStore, err := cache.NewTTLStore("translations"); err == nil {
+			store = ttlStore
+		}
+	}
+	return translate.New(backend, cfg.Translate.Target, store, cfg.Translate.BatchSize)
+}
+
+// translateSummary translates the titles of the activities for display, keeping the titles
+// as written in TitleOriginal
+func translateSummary(ctx context.Context, translator *translate.Translator, summary *activity.Summary, reporter *verboselog.Reporter) {
+	if translator == nil {
+		return
+	}
+	titles := make([]*string, len(summary.Activities))
+	originals := make([]*string, len(summary.Activities))
+	for i := range summary.Activities {
+		titles[i] = &summary.Activities[i].Title
+		originals[i] = &summary.Activities[i].TitleOriginal
+	}
+	translateTitles(ctx, translator, titles, originals, reporter)
+}
+
+// translateTodos translates the titles of the todo items and their subtasks for display,
+// keeping the titles as written in TitleOriginal
+func translateTodos(ctx context.Context, translator *translate.Translator, todoItems output.TodoItems, reporter *verboselog.Reporter) {
+	if translator == nil {
+		return
+	}
+	var titles, originals []*string
+	var collect func(items []output.TodoItem)
+	collect = func(items []output.TodoItem) {
+		for i := range items {
+			titles = append(titles, &items[i].Title)
+			originals = append(originals, &items[i].TitleOriginal)
+			collect(items[i].Subtasks)
+		}
+	}
+	for _, items := range itemSections(todoItems, nil) {
+		collect(items)
+	}
+	translateTitles(ctx, translator, titles, originals, reporter)
+}
+
+// translateTitles replaces the titles by their translation, setting the original of each
+// translated title. Titles left untranslated by a failure are shown as written.
+func translateTitles(ctx context.Context, translator *translate.Translator, titles, originals []*string, reporter *verboselog.Reporter) {
+	texts := make([]string, len(titles))
+	for i, title := range titles {
+		texts[i] = *title
+	}
+
+	translated, err := translator.Translate(ctx, texts)
+	if err != nil {
+		reporter.Warn("%v", err)
+	}
+
+	count := 0
+	for i, translation := range translated {
+		if translation == texts[i] {
+			continue
+		}
+		*originals[i] = texts[i]
+		*titles[i] = translation
+		count++
+	}
+	reporter.Step("Translated %d titles", count)
+}
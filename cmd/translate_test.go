@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"daily/internal/activity"
+	"daily/internal/config"
+	"daily/internal/output"
+	"daily/internal/translate"
+)
+
+func TestTranslateTodos(t *testing.T) {
+	translator := translate.New(translate.NewDictionary(map[string]string{
+		"corriger": "fix", "le": "the", "de": "of", "connexion": "login", "ajouter": "add", "des": "some",
+	}), "en", nil, 0)
+
+	todoItems := output.TodoItems{
+		JIRA: output.JIRATodos{AssignedTickets: []output.TodoItem{
+			{ID: "jira-OPS-1", Title: "OPS-1: Corriger le bug de connexion", Subtasks: []output.TodoItem{
+				{ID: "jira-OPS-2", Title: "Ajouter des tests"},
+			}},
+			{ID: "jira-OPS-3", Title: "Fix the deploy script"},
+		}},
+	}
+	translateTodos(context.Background(), translator, todoItems, nil)
+
+	tests := []struct {
+		item            output.TodoItem
+		title, original string
+	}{
+		{todoItems.JIRA.AssignedTickets[0], "OPS-1: fix the bug of login", "OPS-1: Corriger le bug de connexion"},
+		{todoItems.JIRA.AssignedTickets[0].Subtasks[0], "add some tests", "Ajouter des tests"},
+		{todoItems.JIRA.AssignedTickets[1], "Fix the deploy script", ""},
+	}
+	for _, tt := range tests {
+		if tt.item.Title != tt.title || tt.item.TitleOriginal != tt.original {
+			t.Errorf("Expected '%s' translated from '%s' for %s, got '%s' from '%s'", tt.title, tt.original, tt.item.ID, tt.item.Title, tt.item.TitleOriginal)
+		}
+	}
+}
+
+func TestTranslateSummary(t *testing.T) {
+	summary := &activity.Summary{Activities: []activity.Activity{
+		{ID: "1", Title: "Corriger le bug"},
+		{ID: "2", Title: "owner/repo"},
+	}}
+
+	// Without translator, titles are left as they are
+	translateSummary(context.Background(), nil, summary, nil)
+	if summary.Activities[0].Title != "Corriger le bug" || summary.Activities[0].TitleOriginal != "" {
+		t.Errorf("Expected no translation, got %+v", summary.Activities[0])
+	}
+
+	translator := translate.New(translate.NewDictionary(map[string]string{"corriger le bug": "fix the bug"}), "en", nil, 0)
+	translateSummary(context.Background(), translator, summary, nil)
+	if summary.Activities[0].Title != "fix the bug" || summary.Activities[0].TitleOriginal != "Corriger le bug" {
+		t.Errorf("Expected the translated title, got %+v", summary.Activities[0])
+	}
+	if summary.Activities[1].Title != "owner/repo" || summary.Activities[1].TitleOriginal != "" {
+		t.Errorf("Expected the repository name to be left as is, got %+v", summary.Activities[1])
+	}
+}
+
+func TestNewTranslator_Cache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".config", "daily", "cache", "translations.json")
+	cfg := &config.Config{Translate: config.TranslateConfig{Target: "en", Dictionary: map[string]string{"corriger": "fix", "le": "the", "bug": "bug"}}}
+
+	// Titles aren't written in plain text next to an encrypted cache
+	cfg.Cache.Encryption = config.CacheEncryptionAge
+	if _, err := newTranslator(cfg).Translate(context.Background(), []string{"Corriger le bug"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected no translation cached with an encrypted cache, got: %v", err)
+	}
+
+	cfg.Cache.Encryption = ""
+	if _, err := newTranslator(cfg).Translate(context.Background(), []string{"Corriger le bug"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the translations cached with mode 0600, got %v (%v)", info, err)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/concurrency"
+	"daily/internal/config"
+	"daily/internal/notify"
+	"daily/internal/tray"
+	"daily/internal/tui"
+)
+
+// minTrayInterval keeps the tray from hammering the provider APIs
+const minTrayInterval = time.Minute
+
+func TrayCmd() *cobra.Command {
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "tray",
+		Short: "Show pending review requests and tickets in the menu bar or system tray",
+		Long: `Show the number of review requests waiting for you in the macOS menu bar or the system tray,
+with a menu listing the review requests and the JIRA tickets assigned to you. Clicking an item opens it in the browser.
+
+The items are the ones 'daily notify' watches, refreshed every --interval. The tray is only available in builds made with the "tray" build tag (go build -tags tray).`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval < minTrayInterval {
+				return fmt.Errorf("invalid --interval %s: must be at least %s", interval, minTrayInterval)
+			}
+
+			cfg, err := config.LoadWithTokens()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			sources := notifySources(cfg, nil)
+			refresh := func(ctx context.Context) tray.Snapshot {
+				return traySnapshot(ctx, sources)
+			}
+			open := func(url string) { _ = tui.OpenURL(url) }
+			return tray.Run(cmd.Context(), interval, refresh, open)
+		},
+	}
+
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Minute, "How often the items are refreshed")
+
+	return cmd
+}
+
+// traySources lists the change sources of the tray in parallel
+var traySources = concurrency.Options{Workers: 4}
+
+// traySnapshot lists the current items of every source, one section per source
+func traySnapshot(ctx context.Context, sources []changeSource) tray.Snapshot {
+	results := concurrency.Map(ctx, sources, traySources, func(ctx context.Context, _ int, src changeSource) ([]notify.Change, error) {
+		return src.list(ctx)
+	})
+
+	snapshot := make(tray.Snapshot, len(sources))
+	for i, src := range sources {
+		snapshot[i] = tray.Section{Kind: src.kind, Items: results[i].Value, Err: results[i].Err}
+	}
+	return snapshot
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"daily/internal/notify"
+)
+
+func TestTraySnapshot(t *testing.T) {
+	reviews := []notify.Change{{ID: "github-acme/api#1", Title: "Add retries"}}
+	tickets := []notify.Change{}
+	var reviewsErr error
+	ticketsErr := errors.New("JIRA API request failed with status 401")
+
+	snapshot := traySnapshot(context.Background(), []changeSource{
+		fixedChangeSource(notify.KindReviewRequest, &reviews, &reviewsErr),
+		fixedChangeSource(notify.KindJIRAAssigned, &tickets, &ticketsErr),
+	})
+
+	if len(snapshot) != 2 {
+		t.Fatalf("Expected one section per source, got %+v", snapshot)
+	}
+	if snapshot[0].Kind != notify.KindReviewRequest || len(snapshot[0].Items) != 1 || snapshot[0].Err != nil {
+		t.Errorf("Expected the review requests, got %+v", snapshot[0])
+	}
+	if snapshot[1].Kind != notify.KindJIRAAssigned || snapshot[1].Err == nil {
+		t.Errorf("Expected the failed tickets section, got %+v", snapshot[1])
+	}
+}
+
+func TestTrayCmd_Interval(t *testing.T) {
+	cmd := TrayCmd()
+	cmd.SetArgs([]string{"--interval", "10s"})
+	cmd.SilenceUsage = true
+	if err := cmd.Execute(); err == nil {
+		t.Error("Expected an error for an interval under a minute")
+	}
+}
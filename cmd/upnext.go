@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"daily/internal/config"
+	"daily/internal/metrics"
+	"daily/internal/output"
+	"daily/internal/provider/github"
+	"daily/internal/verboselog"
+)
+
+// defaultUpNextTop is the number of items listed by up-next without --top
+const defaultUpNextTop = 15
+
+func UpNextCmd() *cobra.Command {
+	var verbose bool
+	var outputFormat string
+	var outFile string
+	var progress string
+	var since string
+	var top int
+
+	cmd := &cobra.Command{
+		Use:   "up-next",
+		Short: "Rank todo items and review requests in a single list",
+		Long: `Merge the todo items and the review requests into a single list ranked by score, the items
+listed in both (e.g. a review request also in the pending reviews) being kept once.
+
+The score adds up the severity, the due date, review requests and their age, PRs ready to merge,
+tickets in progress, mentions and recent updates. Failing CI and the merge queue lower it. Every
+item lists the signals that made its score.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// Validate output format
+			if outputFormat != "text" && outputFormat != "json" && outputFormat != "tui" {
+				return fmt.Errorf("invalid output format: %s (must be 'text', 'json', or 'tui')", outputFormat)
+			}
+			if err := validateOutFile(outputFormat, outFile); err != nil {
+				return err
+			}
+			if err := validateProgress(outputFormat, progress); err != nil {
+				return err
+			}
+			if top <= 0 {
+				return fmt.Errorf("invalid top value: %d (must be greater than 0)", top)
+			}
+
+			recorder := newRunRecorder(progress)
+			defer func() { recorder.RunFinished(err) }()
+
+			if outputFormat == "text" {
+				fmt.Println("Gathering pending work items and review requests...")
+			}
+
+			// Load configuration
+			cfg, err := config.LoadWithTokens()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			ctx := metrics.WithRecorder(context.Background(), recorder)
+			showVerbose := verbose && outputFormat == "text"
+			var reporter *verboselog.Reporter
+			if showVerbose {
+				reporter = verboselog.Stderr()
+			}
+			autoPruneCache(cfg, reporter)
+
+			if err := validateJIRAFilters(ctx, cfg, reporter); err != nil {
+				return err
+			}
+
+			todoItems := collectTodos(ctx, cfg, todoOptions{since: since, reporter: reporter})
+
+			// Review requests are fetched with their details, the CI status and the merge queue
+			// being part of the score
+			var sources []reviewSource
+			if cfg.GitHub.Enabled {
+				githubProvider := github.NewProvider(cfg.GitHub)
+				if githubProvider.IsConfigured() {
+					sources = append(sources, reviewSource{source: githubProvider, staleAfterDays: cfg.GitHub.StaleAfterDays})
+				}
+			}
+			reviewItems := collectReviews(ctx, sources, reviewOptions{reporter: reporter})
+
+			hidden := loadHiddenState(cfg)
+			recordHiddenMatches(hidden, todoItems, reviewItems)
+			todoItems = filterHiddenTodos(todoItems, hidden)
+			reviewItems = filterHiddenReviews(reviewItems, hidden)
+			setTodoSeverity(todoItems, cfg.SeverityRules())
+			setReviewSeverity(reviewItems, cfg.SeverityRules())
+
+			ranked := output.RankUpNext(todoItems, reviewItems, nowFunc())
+			items := ranked[:min(top, len(ranked))]
+
+			// Format and display results
+			formatter := output.NewFormatter()
+			switch outputFormat {
+			case "json":
+				formatter.SetMeta(recorder.Report())
+				return writeOutput(outFile, formatter.FormatUpNextJSON(items, len(ranked)))
+			case "tui":
+				return formatter.FormatUpNextTUI(items, len(ranked))
+			case "text":
+				if err := writeOutput(outFile, formatter.FormatUpNext(items, len(ranked))); err != nil {
+					return err
+				}
+				if showVerbose {
+					printRunReport(recorder)
+				}
+				return nil
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output for debugging (text mode only)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "tui", "Output format: 'tui', 'text', or 'json'")
+	cmd.Flags().StringVar(&outFile, "out-file", "", "Write output to this file instead of stdout ('-' for stdout, not available with tui)")
+	cmd.Flags().StringVar(&progress, "progress", "", "Stream progress events to stderr: 'json' for one JSON object per line")
+	cmd.Flags().StringVarP(&since, "since", "s", "", "Time range for JIRA and Confluence mentions (e.g., 1d, 2w, 1m). Default: 2w, or confluence.todo_since for Confluence")
+	cmd.Flags().IntVarP(&top, "top", "n", defaultUpNextTop, "Number of items to list")
+
+	return cmd
+}
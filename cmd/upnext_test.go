@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpNextCmd_FlagValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{"invalid output", []string{"--output", "invalid"}, "invalid output format: invalid"},
+		{"zero top", []string{"--output", "json", "--top", "0"}, "invalid top value: 0"},
+		{"out-file with tui", []string{"--out-file", "out.txt"}, "--out-file cannot be used with tui output"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := UpNextCmd()
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+
+	if top := UpNextCmd().Flags().Lookup("top"); top == nil || top.DefValue != "15" {
+		t.Errorf("Expected a top flag defaulting to 15, got %+v", top)
+	}
+}
@@ -1,6 +1,7 @@
 package activity
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -12,11 +13,64 @@ const (
 	ActivityTypePR                     ActivityType = "pull_request"
 	ActivityTypeIssue                  ActivityType = "issue"
 	ActivityTypeJiraTicket             ActivityType = "jira_ticket"
+	ActivityTypeJiraResolved           ActivityType = "jira_resolved"
 	ActivityTypeNote                   ActivityType = "note"
+	ActivityTypeMeeting                ActivityType = "meeting"
 	ActivityTypeTask                   ActivityType = "task"
+	ActivityTypeTaskCompleted          ActivityType = "task_completed"
 	ActivityTypeConfluenceContribution ActivityType = "confluence_contribution"
+	ActivityTypeConfluenceCreated      ActivityType = "confluence_created"
+	ActivityTypeCI                     ActivityType = "ci"
+	ActivityTypeDeployment             ActivityType = "deployment"
+	ActivityTypeWorklog                ActivityType = "worklog"
+	ActivityTypeMention                ActivityType = "mention"
+	ActivityTypeSavedQuery             ActivityType = "saved_query"
+	ActivityTypeComment                ActivityType = "comment" // Comment on a merge request or issue
+	ActivityTypeReview                 ActivityType = "review"  // Approval of a merge request
+	ActivityTypeMessage                ActivityType = "message" // Slack thread the user posted in
 )
 
+// activityTypes lists every known activity type
+var activityTypes = []ActivityType{
+	ActivityTypeCommit,
+	ActivityTypePR,
+	ActivityTypeIssue,
+	ActivityTypeJiraTicket,
+	ActivityTypeJiraResolved,
+	ActivityTypeNote,
+	ActivityTypeMeeting,
+	ActivityTypeTask,
+	ActivityTypeTaskCompleted,
+	ActivityTypeConfluenceContribution,
+	ActivityTypeConfluenceCreated,
+	ActivityTypeCI,
+	ActivityTypeDeployment,
+	ActivityTypeWorklog,
+	ActivityTypeMention,
+	ActivityTypeSavedQuery,
+	ActivityTypeComment,
+	ActivityTypeReview,
+	ActivityTypeMessage,
+}
+
+// activityTypeAliases maps shorthand names accepted in the config to activity types
+var activityTypeAliases = map[string]ActivityType{
+	"pr": ActivityTypePR,
+}
+
+// ParseActivityType converts a type name (e.g., "commit", "pr") to an ActivityType
+func ParseActivityType(name string) (ActivityType, error) {
+	if t, ok := activityTypeAliases[name]; ok {
+		return t, nil
+	}
+	for _, t := range activityTypes {
+		if string(t) == name {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown activity type %q", name)
+}
+
 // Activity represents a single work activity
 type Activity struct {
 	ID          string       `json:"id"`
@@ -27,12 +81,89 @@ type Activity struct {
 	Platform    string       `json:"platform"`
 	Timestamp   time.Time    `json:"timestamp"`
 	Tags        []string     `json:"tags,omitempty"`
+	Changes     *ChangeStats `json:"changes,omitempty"`
+
+	// Details holds structured facts by Detail* key, e.g. {"repo": "owner/repo"}, while the
+	// description stays human text
+	Details map[string]string `json:"details,omitempty"`
+
+	// TimeSpentSeconds is the work logged by a worklog activity
+	TimeSpentSeconds int `json:"time_spent_seconds,omitempty"`
+
+	// DurationSeconds is the length of a calendar meeting
+	DurationSeconds int `json:"duration_seconds,omitempty"`
+
+	// Epic is the epic (or parent issue) of a JIRA activity
+	Epic *Epic `json:"epic,omitempty"`
+
+	// IssueType is the type of the JIRA issue of an activity, e.g. "Bug" or "Story"
+	IssueType string `json:"issue_type,omitempty"`
+
+	// TitleOriginal is the title as written, when Title was translated for display
+	TitleOriginal string `json:"title_original,omitempty"`
+}
+
+// Epic identifies the epic an activity belongs to
+type Epic struct {
+	Key  string `json:"key"`
+	Name string `json:"name,omitempty"`
+}
+
+// ChangeStats summarizes the line changes behind an activity
+type ChangeStats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Commits   int `json:"commits,omitempty"`
+}
+
+// String returns a short diffstat such as "+42/-7 lines"
+func (c ChangeStats) String() string {
+	return fmt.Sprintf("+%d/-%d lines", c.Additions, c.Deletions)
 }
 
 // Summary represents a collection of activities for a specific date
 type Summary struct {
-	Date       time.Time  `json:"date"`
-	Activities []Activity `json:"activities"`
+	Date       time.Time      `json:"date"`
+	Activities []Activity     `json:"activities"`
+	Goals      []GoalProgress `json:"goals,omitempty"`  // Progress toward the daily goals, when set
+	Period     string         `json:"period,omitempty"` // Time range covered when not a whole day, e.g. "covering Fri 18:00 → now"
+	From       time.Time      `json:"from,omitzero"`    // Start of the time range, when not a whole day
+	To         time.Time      `json:"to,omitzero"`      // End of the time range, when not a whole day
+}
+
+// DayHeadingLayout formats the heading of the activities of a day, e.g. "Tuesday, Sep 10"
+const DayHeadingLayout = "Monday, Jan 2"
+
+// SpansMultipleDays reports whether the summary covers a time range longer than a day
+func (s *Summary) SpansMultipleDays() bool {
+	return !s.From.IsZero() && s.To.Sub(s.From) > 24*time.Hour
+}
+
+// DayGroup holds the activities of a calendar day
+type DayGroup struct {
+	Day        time.Time // Start of the day
+	Activities []Activity
+}
+
+// GroupByDay splits activities sorted by time into calendar days, in the location of their timestamps
+func GroupByDay(activities []Activity) []DayGroup {
+	var groups []DayGroup
+	for _, act := range activities {
+		if len(groups) == 0 || !SameDay(groups[len(groups)-1].Day, act.Timestamp) {
+			year, month, day := act.Timestamp.Date()
+			groups = append(groups, DayGroup{Day: time.Date(year, month, day, 0, 0, 0, 0, act.Timestamp.Location())})
+		}
+		last := &groups[len(groups)-1]
+		last.Activities = append(last.Activities, act)
+	}
+	return groups
+}
+
+// SameDay reports whether two times fall on the same calendar day, in the location of b
+func SameDay(a, b time.Time) bool {
+	ay, am, ad := a.In(b.Location()).Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
 }
 
 // GroupByPlatform groups activities by their platform
@@ -1,6 +1,7 @@
 package activity
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -70,3 +71,92 @@ func TestSummary_GroupByType(t *testing.T) {
 		t.Errorf("Expected 1 JIRA ticket activity, got %d", len(groups[ActivityTypeJiraTicket]))
 	}
 }
+
+func TestGroupByDay(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	activities := []Activity{
+		{ID: "1", Timestamp: time.Date(2024, 9, 9, 9, 0, 0, 0, paris)},
+		{ID: "2", Timestamp: time.Date(2024, 9, 9, 23, 30, 0, 0, paris)},
+		// 00:30 on Tuesday in Paris
+		{ID: "3", Timestamp: time.Date(2024, 9, 9, 22, 30, 0, 0, time.UTC).In(paris)},
+		{ID: "4", Timestamp: time.Date(2024, 9, 12, 8, 0, 0, 0, paris)},
+	}
+
+	groups := GroupByDay(activities)
+
+	expected := []struct {
+		day string
+		ids string
+	}{
+		{"Monday, Sep 9", "1,2"},
+		{"Tuesday, Sep 10", "3"},
+		{"Thursday, Sep 12", "4"},
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("Expected %d days, got %d", len(expected), len(groups))
+	}
+	for i, want := range expected {
+		var ids []string
+		for _, act := range groups[i].Activities {
+			ids = append(ids, act.ID)
+		}
+		if groups[i].Day.Format(DayHeadingLayout) != want.day || strings.Join(ids, ",") != want.ids {
+			t.Errorf("Expected %s with %s, got %s with %s", want.day, want.ids, groups[i].Day.Format(DayHeadingLayout), strings.Join(ids, ","))
+		}
+	}
+}
+
+func TestSummary_SpansMultipleDays(t *testing.T) {
+	from := time.Date(2024, 9, 9, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		summary  Summary
+		expected bool
+	}{
+		{"whole day", Summary{Date: from}, false},
+		{"one day", Summary{From: from, To: from.Add(24 * time.Hour)}, false},
+		{"three days", Summary{From: from, To: from.AddDate(0, 0, 3)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.summary.SpansMultipleDays(); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseActivityType(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected ActivityType
+		wantErr  bool
+	}{
+		{"commit", "commit", ActivityTypeCommit, false},
+		{"pull request", "pull_request", ActivityTypePR, false},
+		{"pr alias", "pr", ActivityTypePR, false},
+		{"task", "task", ActivityTypeTask, false},
+		{"note", "note", ActivityTypeNote, false},
+		{"unknown", "tweet", "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseActivityType(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
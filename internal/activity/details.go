@@ -0,0 +1,114 @@
+package activity
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Keys of the structured details of activities and todo items
+const (
+	DetailRepo    = "repo"    // GitHub repository or GitLab project, e.g. "owner/repo"
+	DetailProject = "project" // JIRA project key, e.g. "PROJ"
+	DetailSpace   = "space"   // Confluence space key, e.g. "ENG"
+	DetailStatus  = "status"  // JIRA status or workflow run conclusion, e.g. "In Progress"
+	DetailState   = "state"   // State of a pull request or Obsidian checkbox, e.g. "open" or "/"
+
+	DetailContentType = "content_type" // Confluence content type: "page", "blogpost" or "comment"
+	DetailAttendees   = "attendees"    // Number of people invited to a calendar meeting, e.g. "5"
+	DetailChannel     = "channel"      // Slack channel of a message, e.g. "#platform"
+)
+
+// DetailKeys lists the detail keys in display order
+var DetailKeys = []string{DetailRepo, DetailProject, DetailSpace, DetailContentType, DetailStatus, DetailState, DetailAttendees, DetailChannel}
+
+// detailLabels are the headings of the details in the TUI panels
+var detailLabels = map[string]string{
+	DetailRepo:    "Repository",
+	DetailProject: "Project",
+	DetailSpace:   "Space",
+	DetailStatus:  "Status",
+	DetailState:   "State",
+
+	DetailContentType: "Content Type",
+	DetailAttendees:   "Attendees",
+	DetailChannel:     "Channel",
+}
+
+// DetailLabel returns the heading of a detail key, e.g. "Repository" for DetailRepo
+func DetailLabel(key string) string {
+	if label, ok := detailLabels[key]; ok {
+		return label
+	}
+	return key
+}
+
+// NewDetails returns the details of key/value pairs, leaving out empty values, e.g.
+// NewDetails(DetailRepo, "owner/repo", DetailState, "open"). It returns nil without values.
+func NewDetails(pairs ...string) map[string]string {
+	var details map[string]string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			continue
+		}
+		if details == nil {
+			details = make(map[string]string)
+		}
+		details[pairs[i]] = pairs[i+1]
+	}
+	return details
+}
+
+// Detail returns a structured detail of the activity, e.g. Detail(DetailRepo)
+func (a Activity) Detail(key string) string {
+	return LookupDetail(a.Details, a.Description, a.URL, key)
+}
+
+// LookupDetail returns the detail stored under key. Summaries cached before details existed
+// only have them in their description or URL, which are parsed as a fallback.
+func LookupDetail(details map[string]string, description, itemURL, key string) string {
+	if value, ok := details[key]; ok {
+		return value
+	}
+
+	switch key {
+	case DetailRepo:
+		for _, prefix := range []string{"Commit in ", "Open PR in ", "Review requested in "} {
+			if rest, ok := strings.CutPrefix(description, prefix); ok {
+				if fields := strings.Fields(rest); len(fields) > 0 && strings.Contains(fields[0], "/") {
+					return fields[0]
+				}
+			}
+		}
+		return repoFromURL(itemURL)
+	case DetailStatus:
+		if status, ok := strings.CutPrefix(description, "Status: "); ok {
+			return status
+		}
+	case DetailState:
+		if state, ok := strings.CutPrefix(description, "Pull request: "); ok {
+			return state
+		}
+	case DetailProject:
+		if u, err := url.Parse(itemURL); err == nil {
+			if issueKey, ok := strings.CutPrefix(u.Path, "/browse/"); ok {
+				if project, _, ok := strings.Cut(issueKey, "-"); ok {
+					return project
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// repoFromURL returns the owner/repo of a GitHub URL, e.g. https://github.com/owner/repo/pull/1
+func repoFromURL(itemURL string) string {
+	u, err := url.Parse(itemURL)
+	if err != nil || !strings.Contains(u.Host, "github") {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] == "" || segments[1] == "" {
+		return ""
+	}
+	return segments[0] + "/" + segments[1]
+}
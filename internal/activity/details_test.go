@@ -0,0 +1,47 @@
+package activity
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestNewDetails(t *testing.T) {
+	details := NewDetails(DetailRepo, "owner/repo", DetailState, "", DetailStatus, "success")
+	expected := map[string]string{DetailRepo: "owner/repo", DetailStatus: "success"}
+	if !maps.Equal(details, expected) {
+		t.Errorf("Expected %v, got %v", expected, details)
+	}
+
+	if details := NewDetails(DetailState, ""); details != nil {
+		t.Errorf("Expected nil without values, got %v", details)
+	}
+}
+
+func TestLookupDetail(t *testing.T) {
+	tests := []struct {
+		name        string
+		details     map[string]string
+		description string
+		url         string
+		key         string
+		expected    string
+	}{
+		{name: "structured detail", details: map[string]string{DetailStatus: "Done"}, description: "Status: In Progress", key: DetailStatus, expected: "Done"},
+		{name: "commit description", description: "Commit in owner/repo", key: DetailRepo, expected: "owner/repo"},
+		{name: "review description", description: "Review requested in owner/repo (fork)", key: DetailRepo, expected: "owner/repo"},
+		{name: "GitHub URL", description: "Fix login", url: "https://github.com/owner/repo/pull/1", key: DetailRepo, expected: "owner/repo"},
+		{name: "other URL", url: "https://jira.example.com/browse/PROJ-1", key: DetailRepo},
+		{name: "JIRA status", description: "Status: In Progress", key: DetailStatus, expected: "In Progress"},
+		{name: "JIRA project", url: "https://jira.example.com/browse/PROJ-1?focusedCommentId=2", key: DetailProject, expected: "PROJ"},
+		{name: "pull request state", description: "Pull request: closed", key: DetailState, expected: "closed"},
+		{name: "unknown", description: "Modified page", key: DetailSpace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LookupDetail(tt.details, tt.description, tt.url, tt.key); got != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package activity
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// GoalProgress is the progress toward a daily goal
+type GoalProgress struct {
+	Name   string `json:"name"`
+	Count  int    `json:"count"`
+	Target int    `json:"target"`
+	Met    bool   `json:"met"`
+}
+
+// String returns the progress as "commits 3/5", followed by ✅ once the goal is met
+func (g GoalProgress) String() string {
+	s := fmt.Sprintf("%s %d/%d", g.Name, g.Count, g.Target)
+	if g.Met {
+		s += " ✅"
+	}
+	return s
+}
+
+// ComputeGoalProgress counts the activities matching each goal, sorted by goal name.
+// Goals without a positive target are skipped.
+func ComputeGoalProgress(targets map[string]int, activities []Activity) []GoalProgress {
+	progress := make([]GoalProgress, 0, len(targets))
+	for name, target := range targets {
+		if target <= 0 {
+			continue
+		}
+
+		count := 0
+		for _, act := range activities {
+			if matchesGoal(name, act) {
+				count++
+			}
+		}
+
+		progress = append(progress, GoalProgress{
+			Name:   name,
+			Count:  count,
+			Target: target,
+			Met:    count >= target,
+		})
+	}
+
+	sort.Slice(progress, func(i, j int) bool {
+		return progress[i].Name < progress[j].Name
+	})
+	return progress
+}
+
+// GoalTagPrefix prefixes goals counting the activities with any tag, e.g. "tag:reading"
+const GoalTagPrefix = "tag:"
+
+// goalTags lists the tags providers always set, that goals can name without GoalTagPrefix
+var goalTags = []string{"transition", "my_page", "saved-query"}
+
+// ValidateGoalName checks that a goal can match activities: its name, singular or plural, must
+// be an activity type or one of the tags providers always set, and other tags need GoalTagPrefix.
+func ValidateGoalName(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if tag, ok := strings.CutPrefix(name, GoalTagPrefix); ok {
+		if tag == "" {
+			return fmt.Errorf("goal %q has an empty tag", name)
+		}
+		return nil
+	}
+	for _, candidate := range []string{name, strings.TrimSuffix(name, "s")} {
+		if _, err := ParseActivityType(candidate); err == nil || slices.Contains(goalTags, candidate) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown goal %q: expected an activity type, e.g. \"commits\", or a tag as \"%s<tag>\"", name, GoalTagPrefix)
+}
+
+// matchesGoal reports whether an activity counts toward a goal. The goal name, singular or
+// plural, names either the activity type (e.g. "commits", "prs") or one of its tags (e.g. "comments").
+// A name with GoalTagPrefix only matches the tag after it, as is.
+func matchesGoal(name string, act Activity) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if tag, ok := strings.CutPrefix(name, GoalTagPrefix); ok {
+		return slices.ContainsFunc(act.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+	}
+	for _, candidate := range []string{name, strings.TrimSuffix(name, "s")} {
+		if t, err := ParseActivityType(candidate); err == nil && t == act.Type {
+			return true
+		}
+		for _, tag := range act.Tags {
+			if strings.EqualFold(tag, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package activity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeGoalProgress(t *testing.T) {
+	now := time.Now()
+	activities := []Activity{
+		{ID: "1", Type: ActivityTypeCommit, Timestamp: now},
+		{ID: "2", Type: ActivityTypeCommit, Timestamp: now},
+		{ID: "3", Type: ActivityTypeCommit, Timestamp: now},
+		{ID: "4", Type: ActivityTypePR, Timestamp: now},
+		{ID: "5", Type: ActivityTypeJiraTicket, Tags: []string{"PROJ-1", "comment"}, Timestamp: now},
+		{ID: "6", Type: ActivityTypeJiraTicket, Tags: []string{"PROJ-2", "Comment"}, Timestamp: now},
+	}
+
+	targets := map[string]int{
+		"commits":  5,
+		"prs":      1,
+		"comments": 2,
+		"reviews":  3,
+		"notes":    0, // Disabled goals are skipped
+	}
+
+	progress := ComputeGoalProgress(targets, activities)
+
+	expected := []GoalProgress{
+		{Name: "comments", Count: 2, Target: 2, Met: true},
+		{Name: "commits", Count: 3, Target: 5, Met: false},
+		{Name: "prs", Count: 1, Target: 1, Met: true},
+		{Name: "reviews", Count: 0, Target: 3, Met: false},
+	}
+
+	if len(progress) != len(expected) {
+		t.Fatalf("Expected %d goals, got %d: %+v", len(expected), len(progress), progress)
+	}
+	for i, want := range expected {
+		if progress[i] != want {
+			t.Errorf("Expected %+v, got %+v", want, progress[i])
+		}
+	}
+}
+
+func TestMatchesGoal(t *testing.T) {
+	tests := []struct {
+		name     string
+		goal     string
+		activity Activity
+		expected bool
+	}{
+		{"singular type", "commit", Activity{Type: ActivityTypeCommit}, true},
+		{"plural type", "commits", Activity{Type: ActivityTypeCommit}, true},
+		{"type alias", "prs", Activity{Type: ActivityTypePR}, true},
+		{"full type name", "pull_requests", Activity{Type: ActivityTypePR}, true},
+		{"case insensitive", "Tasks", Activity{Type: ActivityTypeTask}, true},
+		{"tag", "worklogs", Activity{Type: ActivityTypeWorklog, Tags: []string{"PROJ-1", "worklog"}}, true},
+		{"other type", "commits", Activity{Type: ActivityTypePR}, false},
+		{"no matching tag", "reviews", Activity{Type: ActivityTypePR, Tags: []string{"repo"}}, false},
+		{"prefixed tag", "tag:Reading", Activity{Type: ActivityTypeNote, Tags: []string{"reading"}}, true},
+		{"prefixed tag as is", "tag:readings", Activity{Type: ActivityTypeNote, Tags: []string{"reading"}}, false},
+		{"prefixed tag not a type", "tag:notes", Activity{Type: ActivityTypeNote}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesGoal(tt.goal, tt.activity); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateGoalName(t *testing.T) {
+	tests := []struct {
+		goal    string
+		wantErr bool
+	}{
+		{"commits", false},
+		{"PRs", false},
+		{"meeting", false},
+		{"transitions", false},
+		{"tag:reading", false},
+		{"tag:attendee:Sam", false},
+		{"comits", true},
+		{"reading", true},
+		{"tag:", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goal, func(t *testing.T) {
+			if err := ValidateGoalName(tt.goal); (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGoalProgress_String(t *testing.T) {
+	if got := (GoalProgress{Name: "commits", Count: 3, Target: 5}).String(); got != "commits 3/5" {
+		t.Errorf("Expected 'commits 3/5', got '%s'", got)
+	}
+	if got := (GoalProgress{Name: "reviews", Count: 4, Target: 3, Met: true}).String(); got != "reviews 4/3 ✅" {
+		t.Errorf("Expected 'reviews 4/3 ✅', got '%s'", got)
+	}
+}
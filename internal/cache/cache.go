@@ -12,7 +12,8 @@ import (
 
 // Cache manages cached summaries for historical dates
 type Cache struct {
-	cacheDir string
+	cacheDir   string
+	passphrase string // When set, cache files are encrypted at rest
 }
 
 // NewCache creates a new cache instance
@@ -26,6 +27,20 @@ func NewCache() (*Cache, error) {
 	return &Cache{cacheDir: cacheDir}, nil
 }
 
+// NewEncryptedCache creates a cache that encrypts entries with a key derived from passphrase
+func NewEncryptedCache(passphrase string) (*Cache, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("cache encryption requires a passphrase")
+	}
+
+	c, err := NewCache()
+	if err != nil {
+		return nil, err
+	}
+	c.passphrase = passphrase
+	return c, nil
+}
+
 // Get retrieves a cached summary for the given date if it exists
 func (c *Cache) Get(date time.Time) (*activity.Summary, error) {
 	filename := c.getFilename(date)
@@ -41,6 +56,11 @@ func (c *Cache) Get(date time.Time) (*activity.Summary, error) {
 		return nil, fmt.Errorf("failed to read cache file: %w", err)
 	}
 
+	data, err = c.open(data)
+	if err != nil {
+		return nil, nil // Undecryptable entries are treated as misses
+	}
+
 	var summary activity.Summary
 	if err := json.Unmarshal(data, &summary); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal cached summary: %w", err)
@@ -49,6 +69,26 @@ func (c *Cache) Get(date time.Time) (*activity.Summary, error) {
 	return &summary, nil
 }
 
+// open returns the JSON content of a cache file, decrypting it when needed.
+// Plain JSON entries written before encryption was enabled are still readable.
+func (c *Cache) open(data []byte) ([]byte, error) {
+	if !isEncrypted(data) {
+		return data, nil
+	}
+	if c.passphrase == "" {
+		return nil, ErrUndecryptable
+	}
+	return decrypt(c.passphrase, data)
+}
+
+// seal encrypts the JSON content of a cache file when a passphrase is set
+func (c *Cache) seal(data []byte) ([]byte, error) {
+	if c.passphrase == "" {
+		return data, nil
+	}
+	return encrypt(c.passphrase, data)
+}
+
 // Set stores a summary in the cache for the given date
 // Only caches summaries for dates before today
 func (c *Cache) Set(date time.Time, summary *activity.Summary) error {
@@ -71,6 +111,11 @@ func (c *Cache) Set(date time.Time, summary *activity.Summary) error {
 		return fmt.Errorf("failed to marshal summary: %w", err)
 	}
 
+	data, err = c.seal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt summary: %w", err)
+	}
+
 	if err := os.WriteFile(filePath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
@@ -112,3 +157,80 @@ func (c *Cache) Clear() error {
 
 	return nil
 }
+
+// RotateKey re-encrypts every cached summary with newPassphrase and switches the cache to it.
+// An empty newPassphrase stores summaries as plain JSON. Every summary is checked before any
+// is written: when one can't be decrypted with the current passphrase, e.g. a mistyped one,
+// it fails with ErrUndecryptable and leaves the cache as it was. With dropUndecryptable,
+// those summaries are removed instead. Without a current passphrase, it always fails with
+// ErrPassphraseRequired when a summary is encrypted. The other files of the directory, e.g.
+// indexes or crash reports, are left alone. It returns the number of re-encrypted and
+// removed summaries.
+func (c *Cache) RotateKey(newPassphrase string, dropUndecryptable bool) (rotated, removed int, err error) {
+	entries, err := c.entries()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	plaintexts := make(map[string][]byte, len(entries))
+	var undecryptable []string
+	for _, entry := range entries {
+		if !isSummaryFile(entry.Name) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.cacheDir, entry.Name))
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to read cache file %s: %w", entry.Name, err)
+		}
+		plaintext, err := c.open(data)
+		if err != nil {
+			undecryptable = append(undecryptable, entry.Name)
+			continue
+		}
+		plaintexts[entry.Name] = plaintext
+	}
+
+	// Without a passphrase, every encrypted summary would be dropped
+	if len(undecryptable) > 0 && c.passphrase == "" {
+		return 0, 0, fmt.Errorf("cache file %s: %w", undecryptable[0], ErrPassphraseRequired)
+	}
+	if len(undecryptable) > 0 && !dropUndecryptable {
+		return 0, 0, fmt.Errorf("%d cache files, e.g. %s: %w", len(undecryptable), undecryptable[0], ErrUndecryptable)
+	}
+
+	for _, name := range undecryptable {
+		if err := os.Remove(filepath.Join(c.cacheDir, name)); err != nil {
+			return rotated, removed, fmt.Errorf("failed to remove cache file %s: %w", name, err)
+		}
+		removed++
+	}
+
+	next := &Cache{cacheDir: c.cacheDir, passphrase: newPassphrase}
+
+	for _, entry := range entries {
+		plaintext, ok := plaintexts[entry.Name]
+		if !ok {
+			continue
+		}
+		filePath := filepath.Join(c.cacheDir, entry.Name)
+
+		sealed, err := next.seal(plaintext)
+		if err != nil {
+			return rotated, removed, fmt.Errorf("failed to encrypt cache file %s: %w", entry.Name, err)
+		}
+
+		// Write to a temporary file first so an interrupted rotation never leaves a partial entry
+		tmpPath := filePath + ".tmp"
+		if err := os.WriteFile(tmpPath, sealed, 0600); err != nil {
+			return rotated, removed, fmt.Errorf("failed to write cache file %s: %w", entry.Name, err)
+		}
+		if err := os.Rename(tmpPath, filePath); err != nil {
+			_ = os.Remove(tmpPath)
+			return rotated, removed, fmt.Errorf("failed to replace cache file %s: %w", entry.Name, err)
+		}
+		rotated++
+	}
+
+	c.passphrase = newPassphrase
+	return rotated, removed, nil
+}
@@ -1,8 +1,10 @@
 package cache
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -123,3 +125,192 @@ func TestGetNonExistentCache(t *testing.T) {
 		t.Error("Expected nil summary for non-existent cache")
 	}
 }
+
+func TestEncryptedCache_RoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	cache := &Cache{cacheDir: tempDir, passphrase: "correct horse"}
+
+	testDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testSummary := &activity.Summary{
+		Date: testDate,
+		Activities: []activity.Activity{
+			{ID: "test-1", Title: "Confidential ticket", Platform: "jira", Timestamp: testDate},
+		},
+	}
+
+	if err := cache.Set(testDate, testSummary); err != nil {
+		t.Fatalf("Failed to set cache: %v", err)
+	}
+
+	// The file on disk must not contain the plaintext
+	data, err := os.ReadFile(filepath.Join(tempDir, cache.getFilename(testDate)))
+	if err != nil {
+		t.Fatalf("Failed to read cache file: %v", err)
+	}
+	if !isEncrypted(data) {
+		t.Error("Expected cache file to be encrypted")
+	}
+	if strings.Contains(string(data), "Confidential ticket") {
+		t.Error("Expected cache file not to contain plaintext titles")
+	}
+
+	cachedSummary, err := cache.Get(testDate)
+	if err != nil {
+		t.Fatalf("Failed to get cached data: %v", err)
+	}
+	if cachedSummary == nil || len(cachedSummary.Activities) != 1 {
+		t.Fatal("Expected cached summary with 1 activity")
+	}
+	if cachedSummary.Activities[0].Title != "Confidential ticket" {
+		t.Errorf("Expected title 'Confidential ticket', got '%s'", cachedSummary.Activities[0].Title)
+	}
+}
+
+func TestEncryptedCache_WrongKeyIsMiss(t *testing.T) {
+	tempDir := t.TempDir()
+	testDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	writer := &Cache{cacheDir: tempDir, passphrase: "right"}
+	if err := writer.Set(testDate, &activity.Summary{Date: testDate}); err != nil {
+		t.Fatalf("Failed to set cache: %v", err)
+	}
+
+	for name, reader := range map[string]*Cache{
+		"wrong passphrase": {cacheDir: tempDir, passphrase: "wrong"},
+		"no passphrase":    {cacheDir: tempDir},
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := reader.Get(testDate)
+			if err != nil {
+				t.Fatalf("Expected undecryptable entry to be a miss, got error: %v", err)
+			}
+			if summary != nil {
+				t.Error("Expected nil summary for undecryptable entry")
+			}
+		})
+	}
+}
+
+func TestEncryptedCache_CorruptEntryIsMiss(t *testing.T) {
+	tempDir := t.TempDir()
+	cache := &Cache{cacheDir: tempDir, passphrase: "secret"}
+	testDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := cache.Set(testDate, &activity.Summary{Date: testDate}); err != nil {
+		t.Fatalf("Failed to set cache: %v", err)
+	}
+
+	// Flip the last byte of the ciphertext
+	filePath := filepath.Join(tempDir, cache.getFilename(testDate))
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read cache file: %v", err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
+		t.Fatalf("Failed to write cache file: %v", err)
+	}
+
+	summary, err := cache.Get(testDate)
+	if err != nil {
+		t.Fatalf("Expected corrupt entry to be a miss, got error: %v", err)
+	}
+	if summary != nil {
+		t.Error("Expected nil summary for corrupt entry")
+	}
+}
+
+func TestCache_RotateKey(t *testing.T) {
+	tempDir := t.TempDir()
+	firstDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	secondDate := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	thirdDate := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	// A plain entry from before encryption was enabled, an encrypted one and one under an unknown key
+	plain := &Cache{cacheDir: tempDir}
+	if err := plain.Set(firstDate, &activity.Summary{Date: firstDate}); err != nil {
+		t.Fatalf("Failed to set cache: %v", err)
+	}
+	cache := &Cache{cacheDir: tempDir, passphrase: "old"}
+	if err := cache.Set(secondDate, &activity.Summary{Date: secondDate}); err != nil {
+		t.Fatalf("Failed to set cache: %v", err)
+	}
+	other := &Cache{cacheDir: tempDir, passphrase: "unknown"}
+	if err := other.Set(thirdDate, &activity.Summary{Date: thirdDate}); err != nil {
+		t.Fatalf("Failed to set cache: %v", err)
+	}
+
+	// Other files of the cache directory aren't summaries
+	indexPath := filepath.Join(tempDir, "obsidian_index_abc.json")
+	if err := os.WriteFile(indexPath, []byte(`{"version": 1}`), 0600); err != nil {
+		t.Fatalf("Failed to write index: %v", err)
+	}
+	crashPath := filepath.Join(tempDir, "crash-todo-20240101-101500.txt")
+	if err := os.WriteFile(crashPath, []byte("daily crash report"), 0600); err != nil {
+		t.Fatalf("Failed to write crash report: %v", err)
+	}
+
+	// Without the current passphrase, nothing is removed
+	if _, _, err := plain.RotateKey("new", true); !errors.Is(err, ErrPassphraseRequired) {
+		t.Fatalf("Expected ErrPassphraseRequired rotating encrypted entries, got %v", err)
+	}
+	if summary, _ := plain.Get(firstDate); summary == nil {
+		t.Fatal("Expected the plain entry left as is")
+	}
+	if summary, _ := cache.Get(secondDate); summary == nil {
+		t.Fatal("Expected the encrypted entry kept")
+	}
+
+	// A wrong passphrase doesn't touch any file
+	mistyped := &Cache{cacheDir: tempDir, passphrase: "0ld"}
+	if _, _, err := mistyped.RotateKey("new", false); !errors.Is(err, ErrUndecryptable) {
+		t.Fatalf("Expected ErrUndecryptable rotating with a wrong passphrase, got %v", err)
+	}
+	if summary, _ := plain.Get(firstDate); summary == nil {
+		t.Fatal("Expected the plain entry left as is")
+	}
+	for date, reader := range map[time.Time]*Cache{secondDate: cache, thirdDate: other} {
+		if summary, _ := reader.Get(date); summary == nil {
+			t.Fatalf("Expected the encrypted entry of %s kept", date.Format("2006-01-02"))
+		}
+	}
+
+	// Nor does the right one while an entry is under another key
+	if _, _, err := cache.RotateKey("new", false); !errors.Is(err, ErrUndecryptable) {
+		t.Fatalf("Expected ErrUndecryptable with an entry under another key, got %v", err)
+	}
+	if summary, _ := cache.Get(secondDate); summary == nil {
+		t.Fatal("Expected the entry left under the old key")
+	}
+
+	rotated, removed, err := cache.RotateKey("new", true)
+	if err != nil {
+		t.Fatalf("Failed to rotate key: %v", err)
+	}
+	if rotated != 2 {
+		t.Errorf("Expected 2 rotated entries, got %d", rotated)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 removed entry, got %d", removed)
+	}
+
+	reader := &Cache{cacheDir: tempDir, passphrase: "new"}
+	for _, date := range []time.Time{firstDate, secondDate} {
+		summary, err := reader.Get(date)
+		if err != nil || summary == nil {
+			t.Errorf("Expected entry for %s to be readable with the new key, got %v, %v", date.Format("2006-01-02"), summary, err)
+		}
+	}
+
+	if data, err := os.ReadFile(indexPath); err != nil || string(data) != `{"version": 1}` {
+		t.Errorf("Expected the index left alone, got %q, %v", data, err)
+	}
+	if data, err := os.ReadFile(crashPath); err != nil || string(data) != "daily crash report" {
+		t.Errorf("Expected the crash report left alone, got %q, %v", data, err)
+	}
+
+	oldReader := &Cache{cacheDir: tempDir, passphrase: "old"}
+	if summary, _ := oldReader.Get(secondDate); summary != nil {
+		t.Error("Expected entry to be unreadable with the old key after rotation")
+	}
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptedMagic prefixes encrypted cache files so they can be told apart from plain JSON
+var encryptedMagic = []byte("DAILYENC1")
+
+const (
+	saltSize = 16
+
+	// scrypt parameters recommended for interactive logins
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// ErrUndecryptable is returned when a cache entry can't be decrypted with the current passphrase
+var ErrUndecryptable = errors.New("cache entry cannot be decrypted")
+
+// ErrPassphraseRequired is returned when rotating an encrypted cache without its passphrase
+var ErrPassphraseRequired = errors.New("cache is encrypted but no passphrase was given")
+
+// isEncrypted reports whether data was produced by encrypt
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+// deriveKey derives a chacha20poly1305 key from the passphrase and salt using scrypt
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, chacha20poly1305.KeySize)
+}
+
+// encrypt seals plaintext as magic || salt || nonce || ciphertext using XChaCha20-Poly1305
+func encrypt(passphrase string, plaintext []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(encryptedMagic)+saltSize+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, encryptedMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	// The header is authenticated as additional data
+	return aead.Seal(out, nonce, plaintext, out), nil
+}
+
+// decrypt opens data produced by encrypt, returning ErrUndecryptable on any mismatch
+func decrypt(passphrase string, data []byte) ([]byte, error) {
+	headerSize := len(encryptedMagic) + saltSize + chacha20poly1305.NonceSizeX
+	if !isEncrypted(data) || len(data) < headerSize+chacha20poly1305.Overhead {
+		return nil, ErrUndecryptable
+	}
+
+	salt := data[len(encryptedMagic) : len(encryptedMagic)+saltSize]
+	nonce := data[len(encryptedMagic)+saltSize : headerSize]
+
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, nonce, data[headerSize:], data[:headerSize])
+	if err != nil {
+		return nil, ErrUndecryptable
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// pruneInterval is how often PruneDaily actually prunes the cache
+const pruneInterval = 24 * time.Hour
+
+// lastPruneKey is the key of the time of the last pruning in the maintenance store
+const lastPruneKey = "last_prune"
+
+// summaryPattern matches the names of cached summaries, and the temporary files of an
+// interrupted key rotation
+var summaryPattern = regexp.MustCompile(`^summary_(\d{4}-\d{2}-\d{2})\.json(?:\.tmp)?$`)
+
+// prunedFiles are the files pruning deletes, by the pattern of their names and the layout of
+// the date they hold: cached summaries with the temporary files of an interrupted key
+// rotation, and the crash reports of the interactive views. Pruning never touches any other
+// file.
+var prunedFiles = []struct {
+	pattern *regexp.Regexp
+	layout  string
+}{
+	{summaryPattern, "2006-01-02"},
+	{regexp.MustCompile(`^crash-[a-z-]+-(\d{8})-\d{6}\.txt$`), "20060102"},
+}
+
+// PruneOptions limits what the cache keeps
+type PruneOptions struct {
+	MaxAge   time.Duration // Files of dates older than this are deleted, none when 0
+	MaxBytes int64         // The oldest files are deleted until the cache fits, no limit when 0
+	DryRun   bool          // Report what would be deleted without deleting it
+}
+
+// PrunedEntry is a cache file deleted by pruning
+type PrunedEntry struct {
+	Name   string
+	Date   time.Time
+	Bytes  int64
+	Reason string // "expired" or "size limit"
+}
+
+// PruneResult lists the cache files deleted by pruning, oldest first
+type PruneResult struct {
+	Entries []PrunedEntry
+	DryRun  bool
+}
+
+// Bytes returns the size of the deleted files
+func (r PruneResult) Bytes() int64 {
+	var total int64
+	for _, entry := range r.Entries {
+		total += entry.Bytes
+	}
+	return total
+}
+
+// Prune deletes the summaries and crash reports of dates older than MaxAge, then the oldest
+// of them until they take no more than MaxBytes. The age of a file is the date it is named
+// after.
+func (c *Cache) Prune(now time.Time, opts PruneOptions) (PruneResult, error) {
+	result := PruneResult{DryRun: opts.DryRun}
+
+	entries, err := c.entries()
+	if err != nil {
+		return result, err
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.Bytes
+	}
+
+	cutoff := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Add(-opts.MaxAge)
+	for _, entry := range entries {
+		switch {
+		case opts.MaxAge > 0 && entry.Date.Before(cutoff):
+			entry.Reason = "expired"
+		case opts.MaxBytes > 0 && total > opts.MaxBytes:
+			entry.Reason = "size limit"
+		default:
+			continue
+		}
+
+		if !opts.DryRun {
+			if err := os.Remove(filepath.Join(c.cacheDir, entry.Name)); err != nil {
+				return result, fmt.Errorf("failed to remove cache file %s: %w", entry.Name, err)
+			}
+		}
+		total -= entry.Bytes
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result, nil
+}
+
+// PruneDaily prunes the cache unless it was pruned less than a day ago, according to the
+// time of the last pruning kept in state. It reports whether the cache was pruned.
+func (c *Cache) PruneDaily(state *TTLStore, opts PruneOptions) (PruneResult, bool, error) {
+	var lastPrune time.Time
+	if recent, err := state.Get(lastPruneKey, pruneInterval, &lastPrune); err == nil && recent {
+		return PruneResult{}, false, nil
+	}
+
+	result, err := c.Prune(state.now(), opts)
+	if err != nil {
+		return result, true, err
+	}
+	if err := state.Set(lastPruneKey, state.now()); err != nil {
+		return result, true, err
+	}
+	return result, true, nil
+}
+
+// entries returns the cached summaries and crash reports, oldest first
+func (c *Cache) entries() ([]PrunedEntry, error) {
+	dirEntries, err := os.ReadDir(c.cacheDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var entries []PrunedEntry
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.Type().IsRegular() {
+			continue
+		}
+		date, ok := entryDate(dirEntry.Name())
+		if !ok {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, PrunedEntry{Name: dirEntry.Name(), Date: date, Bytes: info.Size()})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// entryDate returns the date a file pruning may delete is named after, and false for the
+// other files
+func entryDate(name string) (time.Time, bool) {
+	for _, file := range prunedFiles {
+		matches := file.pattern.FindStringSubmatch(name)
+		if matches == nil {
+			continue
+		}
+		date, err := time.Parse(file.layout, matches[1])
+		return date, err == nil
+	}
+	return time.Time{}, false
+}
+
+// isSummaryFile reports whether a file is a cached summary, and not a crash report
+func isSummaryFile(name string) bool {
+	return summaryPattern.MatchString(name)
+}
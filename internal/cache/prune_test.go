@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeCacheFiles creates files of the given sizes in dir
+func writeCacheFiles(t *testing.T, dir string, files map[string]int) {
+	t.Helper()
+	for name, size := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Repeat("x", size)), 0600); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+}
+
+func TestCache_Prune(t *testing.T) {
+	now := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		opts     PruneOptions
+		expected []string // Pruned files, oldest first
+	}{
+		{
+			name:     "expired",
+			opts:     PruneOptions{MaxAge: 30 * 24 * time.Hour},
+			expected: []string{"summary_2024-01-01.json", "crash-todo-20240215-101500.txt", "summary_2024-05-30.json.tmp"},
+		},
+		{
+			name:     "size limit",
+			opts:     PruneOptions{MaxBytes: 250},
+			expected: []string{"summary_2024-01-01.json", "crash-todo-20240215-101500.txt", "summary_2024-05-30.json.tmp", "summary_2024-06-01.json"},
+		},
+		{
+			name:     "expired and size limit",
+			opts:     PruneOptions{MaxAge: 150 * 24 * time.Hour, MaxBytes: 350},
+			expected: []string{"summary_2024-01-01.json", "crash-todo-20240215-101500.txt", "summary_2024-05-30.json.tmp"},
+		},
+		{
+			name: "no limits",
+		},
+		{
+			name:     "dry run",
+			opts:     PruneOptions{MaxAge: 30 * 24 * time.Hour, DryRun: true},
+			expected: []string{"summary_2024-01-01.json", "crash-todo-20240215-101500.txt", "summary_2024-05-30.json.tmp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			writeCacheFiles(t, tempDir, map[string]int{
+				"summary_2024-01-01.json":        100,
+				"crash-todo-20240215-101500.txt": 100,
+				"summary_2024-05-30.json.tmp":    100,
+				"summary_2024-06-01.json":        100,
+				"summary_2024-06-29.json":        100,
+				"summary_2024-06-30.json":        100,
+				// Never pruned, whatever their age or size
+				"github_teams.json":       5000,
+				"notify.json":             5000,
+				"summary_latest.json":     5000,
+				"summary_2024-01-01.txt":  5000,
+				"my-notes-2024-01-01.md":  5000,
+				"summary_2024-13-45.json": 5000,
+				"crash-todo.txt":          5000,
+				"crash-todo-20240215.log": 5000,
+			})
+			old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			if err := os.Chtimes(filepath.Join(tempDir, "notify.json"), old, old); err != nil {
+				t.Fatalf("Failed to set mod time: %v", err)
+			}
+			if err := os.Mkdir(filepath.Join(tempDir, "summary_2023-01-01.json"), 0755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+
+			c := &Cache{cacheDir: tempDir}
+			result, err := c.Prune(now, tt.opts)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			var pruned []string
+			for _, entry := range result.Entries {
+				pruned = append(pruned, entry.Name)
+			}
+			if !slices.Equal(pruned, tt.expected) {
+				t.Errorf("Expected %v to be pruned, got %v", tt.expected, pruned)
+			}
+			if result.Bytes() != int64(100*len(tt.expected)) {
+				t.Errorf("Expected %d bytes pruned, got %d", 100*len(tt.expected), result.Bytes())
+			}
+
+			remaining, err := os.ReadDir(tempDir)
+			if err != nil {
+				t.Fatalf("Failed to read cache directory: %v", err)
+			}
+			expectedRemaining := 14 + 1 - len(tt.expected)
+			if tt.opts.DryRun {
+				expectedRemaining = 15
+			}
+			if len(remaining) != expectedRemaining {
+				t.Errorf("Expected %d files left, got %d", expectedRemaining, len(remaining))
+			}
+		})
+	}
+}
+
+func TestCache_Prune_MissingDirectory(t *testing.T) {
+	c := &Cache{cacheDir: filepath.Join(t.TempDir(), "missing")}
+	result, err := c.Prune(time.Now(), PruneOptions{MaxAge: time.Hour, MaxBytes: 1})
+	if err != nil || len(result.Entries) != 0 {
+		t.Errorf("Expected nothing to prune, got %v (%v)", result.Entries, err)
+	}
+}
+
+func TestCache_PruneDaily(t *testing.T) {
+	tempDir := t.TempDir()
+	now := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+	state := NewTTLStoreAt(filepath.Join(tempDir, "maintenance.json"))
+	state.now = func() time.Time { return now }
+	c := &Cache{cacheDir: tempDir}
+	opts := PruneOptions{MaxAge: 24 * time.Hour}
+
+	writeCacheFiles(t, tempDir, map[string]int{"summary_2024-01-01.json": 10})
+	result, pruned, err := c.PruneDaily(state, opts)
+	if err != nil || !pruned || len(result.Entries) != 1 {
+		t.Fatalf("Expected the first run to prune 1 entry, got %v, %v (%v)", pruned, result.Entries, err)
+	}
+
+	// Pruned less than a day ago
+	writeCacheFiles(t, tempDir, map[string]int{"summary_2024-01-02.json": 10})
+	now = now.Add(23 * time.Hour)
+	if _, pruned, err := c.PruneDaily(state, opts); err != nil || pruned {
+		t.Errorf("Expected no pruning within a day, got %v (%v)", pruned, err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	result, pruned, err = c.PruneDaily(state, opts)
+	if err != nil || !pruned || len(result.Entries) != 1 || result.Entries[0].Name != "summary_2024-01-02.json" {
+		t.Errorf("Expected pruning after a day, got %v, %v (%v)", pruned, result.Entries, err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "maintenance.json")); err != nil {
+		t.Errorf("Expected the maintenance state to be kept, got: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TTLStore keeps small JSON values by key in a single file, for data that rarely changes
+// and is only worth fetching again once it is older than a maximum age
+type TTLStore struct {
+	path string
+	now  func() time.Time
+}
+
+// ttlEntry is a value stored with the time it was stored
+type ttlEntry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// NewTTLStore returns the store named name in the cache directory
+func NewTTLStore(name string) (*TTLStore, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return NewTTLStoreAt(filepath.Join(homeDir, ".config", "daily", "cache", name+".json")), nil
+}
+
+// NewTTLStoreAt returns a store writing to path
+func NewTTLStoreAt(path string) *TTLStore {
+	return &TTLStore{path: path, now: time.Now}
+}
+
+// Get decodes the value stored under key into value. It reports false when nothing is
+// stored under key or the value is older than maxAge.
+func (s *TTLStore) Get(key string, maxAge time.Duration, value any) (bool, error) {
+	entries, err := s.load()
+	if err != nil {
+		return false, err
+	}
+
+	entry, ok := entries[key]
+	if !ok || s.now().Sub(entry.StoredAt) > maxAge {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(entry.Value, value); err != nil {
+		return false, fmt.Errorf("failed to parse cached %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set stores value under key, keeping the values of other keys. The file is only readable by
+// the user, like the cached summaries.
+func (s *TTLStore) Set(key string, value any) error {
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	entries[key] = ttlEntry{StoredAt: s.now(), Value: data}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache file: %w", err)
+	}
+
+	// Written aside then renamed, so that files written by older versions lose their 0644 mode
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0600); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+// load returns the stored entries, or an empty map when nothing was stored yet
+func (s *TTLStore) load() (map[string]ttlEntry, error) {
+	entries := make(map[string]ttlEntry)
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse cache file: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTTLStore(t *testing.T) {
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	store := NewTTLStoreAt(filepath.Join(t.TempDir(), "cache", "teams.json"))
+	store.now = func() time.Time { return now }
+
+	var teams []string
+	found, err := store.Get("octocat", time.Hour, &teams)
+	if err != nil || found {
+		t.Fatalf("Expected nothing stored yet, got found=%v err=%v", found, err)
+	}
+
+	if err := store.Set("octocat", []string{"acme/backend"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := store.Set("hubot", []string{"acme/bots"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	now = now.Add(59 * time.Minute)
+	found, err = store.Get("octocat", time.Hour, &teams)
+	if err != nil || !found {
+		t.Fatalf("Expected the value to be found, got found=%v err=%v", found, err)
+	}
+	if len(teams) != 1 || teams[0] != "acme/backend" {
+		t.Errorf("Expected [acme/backend], got %v", teams)
+	}
+
+	// Values expire after the maximum age
+	now = now.Add(2 * time.Minute)
+	if found, _ := store.Get("octocat", time.Hour, &teams); found {
+		t.Error("Expected the value to have expired")
+	}
+	if found, _ := store.Get("hubot", 2*time.Hour, &teams); !found || teams[0] != "acme/bots" {
+		t.Errorf("Expected other keys to be kept, got found=%v teams=%v", found, teams)
+	}
+}
+
+func TestTTLStore_FileMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "translations.json")
+	// Written by an older version
+	if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+		t.Fatalf("Failed to write cache file: %v", err)
+	}
+
+	if err := NewTTLStoreAt(path).Set("hello", "bonjour"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected the cache file, got: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+}
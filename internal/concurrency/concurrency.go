@@ -0,0 +1,90 @@
+// Package concurrency runs API lookups in parallel while staying under the rate limits of the providers.
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Options controls how many items Map processes at once and how fast they are started
+type Options struct {
+	Workers int           // Maximum number of items processed at the same time, defaults to 1
+	Delay   time.Duration // Minimum time between the start of two items, no limit when 0
+}
+
+// Result is the outcome of processing one item
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// Map calls fn for every item using a bounded pool of workers and returns the results in
+// input order. A failing item doesn't stop the others: its error is kept in its result.
+// When ctx is cancelled, items that have not been started yet fail with the context error.
+func Map[In, Out any](ctx context.Context, items []In, opts Options, fn func(ctx context.Context, index int, item In) (Out, error)) []Result[Out] {
+	results := make([]Result[Out], len(items))
+	if len(items) == 0 {
+		return results
+	}
+
+	workers := max(opts.Workers, 1)
+	workers = min(workers, len(items))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				value, err := fn(ctx, i, items[i])
+				results[i] = Result[Out]{Value: value, Err: err}
+			}
+		}()
+	}
+
+	started := dispatch(ctx, len(items), opts.Delay, jobs)
+	close(jobs)
+	wg.Wait()
+
+	for i := started; i < len(items); i++ {
+		results[i].Err = ctx.Err()
+	}
+
+	return results
+}
+
+// dispatch hands out item indexes to the workers, waiting delay between two items, and
+// returns how many items were started before ctx was cancelled
+func dispatch(ctx context.Context, count int, delay time.Duration, jobs chan<- int) int {
+	var tick <-chan time.Time
+	if delay > 0 {
+		ticker := time.NewTicker(delay)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for i := 0; i < count; i++ {
+		// The first item starts right away, the next ones wait for the rate limit
+		if i > 0 && tick != nil {
+			select {
+			case <-ctx.Done():
+				return i
+			case <-tick:
+			}
+		}
+
+		if ctx.Err() != nil {
+			return i
+		}
+
+		select {
+		case <-ctx.Done():
+			return i
+		case jobs <- i:
+		}
+	}
+
+	return count
+}
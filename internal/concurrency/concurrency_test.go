@@ -0,0 +1,134 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMap_PreservesOrder(t *testing.T) {
+	items := []int{5, 1, 4, 2, 3}
+
+	results := Map(context.Background(), items, Options{Workers: 3}, func(ctx context.Context, index int, item int) (string, error) {
+		// Later items finish first
+		time.Sleep(time.Duration(item) * time.Millisecond)
+		return fmt.Sprintf("item-%d", item), nil
+	})
+
+	if len(results) != len(items) {
+		t.Fatalf("Expected %d results, got %d", len(items), len(results))
+	}
+	for i, item := range items {
+		expected := fmt.Sprintf("item-%d", item)
+		if results[i].Value != expected || results[i].Err != nil {
+			t.Errorf("Result %d: expected '%s', got %+v", i, expected, results[i])
+		}
+	}
+}
+
+func TestMap_PerItemErrors(t *testing.T) {
+	items := []string{"ok", "fail", "ok"}
+
+	results := Map(context.Background(), items, Options{Workers: 2}, func(ctx context.Context, index int, item string) (int, error) {
+		if item == "fail" {
+			return 0, errors.New("boom")
+		}
+		return index, nil
+	})
+
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("Expected other items to succeed, got %+v", results)
+	}
+	if results[1].Err == nil || results[1].Err.Error() != "boom" {
+		t.Errorf("Expected error 'boom' for item 1, got %v", results[1].Err)
+	}
+	if results[2].Value != 2 {
+		t.Errorf("Expected index 2 to be passed to fn, got %d", results[2].Value)
+	}
+}
+
+func TestMap_LimitsWorkers(t *testing.T) {
+	var running, peak atomic.Int32
+	items := make([]int, 20)
+
+	Map(context.Background(), items, Options{Workers: 3}, func(ctx context.Context, index int, item int) (int, error) {
+		current := running.Add(1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return item, nil
+	})
+
+	if peak.Load() > 3 {
+		t.Errorf("Expected at most 3 items processed at once, got %d", peak.Load())
+	}
+}
+
+func TestMap_RateLimiting(t *testing.T) {
+	items := make([]int, 4)
+	var calls atomic.Int32
+
+	start := time.Now()
+	Map(context.Background(), items, Options{Workers: 4, Delay: 50 * time.Millisecond}, func(ctx context.Context, index int, item int) (int, error) {
+		calls.Add(1)
+		return item, nil
+	})
+	elapsed := time.Since(start)
+
+	// The first item starts right away, the 3 others wait for the delay
+	if elapsed < 150*time.Millisecond {
+		t.Errorf("Expected rate limiting to take at least 150ms, got %v", elapsed)
+	}
+	if calls.Load() != int32(len(items)) {
+		t.Errorf("Expected %d items to be processed, got %d", len(items), calls.Load())
+	}
+}
+
+func TestMap_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	items := make([]int, 5)
+	var calls atomic.Int32
+
+	results := Map(ctx, items, Options{Workers: 1, Delay: 20 * time.Millisecond}, func(ctx context.Context, index int, item int) (int, error) {
+		calls.Add(1)
+		if index == 1 {
+			cancel()
+		}
+		return index, nil
+	})
+
+	if len(results) != len(items) {
+		t.Fatalf("Expected %d results, got %d", len(items), len(results))
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 items to start before cancellation, got %d", calls.Load())
+	}
+	for i := 2; i < len(items); i++ {
+		if !errors.Is(results[i].Err, context.Canceled) {
+			t.Errorf("Result %d: expected context.Canceled, got %v", i, results[i].Err)
+		}
+	}
+	if results[0].Err != nil || results[1].Err != nil {
+		t.Errorf("Expected started items to succeed, got %v and %v", results[0].Err, results[1].Err)
+	}
+}
+
+func TestMap_Empty(t *testing.T) {
+	results := Map(context.Background(), []int{}, Options{}, func(ctx context.Context, index int, item int) (int, error) {
+		t.Error("Expected fn not to be called")
+		return 0, nil
+	})
+	if len(results) != 0 {
+		t.Errorf("Expected no results, got %d", len(results))
+	}
+}
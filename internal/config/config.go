@@ -1,12 +1,20 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
 
+	"daily/internal/datetime"
 	"daily/internal/provider"
+	"daily/internal/severity"
 )
 
 type Config struct {
@@ -14,6 +22,157 @@ type Config struct {
 	JIRA       provider.Config `json:"jira"`
 	Obsidian   provider.Config `json:"obsidian"`
 	Confluence provider.Config `json:"confluence"`
+	GitLab     provider.Config `json:"gitlab"`
+	// Calendar reports the meetings of Google Calendar
+	Calendar provider.Config `json:"calendar"`
+	// Slack reports the threads posted in and lists the saved messages
+	Slack provider.Config `json:"slack"`
+	// SavedQueries watches the counts returned by HTTP endpoints, e.g. saved code searches
+	SavedQueries provider.Config `json:"saved_queries"`
+	Cache        CacheConfig     `json:"cache,omitempty"`
+	Goals        GoalsConfig     `json:"goals,omitempty"`
+	Notify       NotifyConfig    `json:"notify,omitempty"`
+	Hide         HideConfig      `json:"hide,omitempty"`
+	Sum          SumConfig       `json:"sum,omitempty"`
+	WorkWeek     WorkWeekConfig  `json:"work_week,omitempty"`
+	// Telemetry sends anonymous usage counts to an endpoint, only once enabled in this file
+	Telemetry TelemetryConfig `json:"telemetry,omitempty"`
+	// Translate shows the titles written in other languages in the target language
+	Translate TranslateConfig `json:"translate,omitempty"`
+	// Severity colors items by priority, label or tag, e.g. "blocker": "red" (default severity.DefaultRules)
+	Severity map[string]string `json:"severity,omitempty"`
+	// Aliases are shortcut commands expanding to a command line, e.g. "today": "sum --since 1d"
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Locale picks how slashed dates are read, e.g. "fr-FR" for DD/MM/YYYY or "en-US" for MM/DD/YYYY
+	Locale string `json:"locale,omitempty"`
+	// Include lists config files merged under this one, e.g. a team config committed to a repository
+	Include []string `json:"include,omitempty"`
+
+	// literalTokens holds the tokens of the config file replaced by the output of a token_cmd, by provider or "translate"
+	literalTokens map[string]string
+
+	// personal is the config file as read when other files were merged with it, and loaded
+	// the merged config as loaded, so that Save only writes what changed to the config file
+	personal map[string]any
+	loaded   map[string]any
+}
+
+// CacheEncryptionAge enables passphrase-based encryption of cached summaries
+const CacheEncryptionAge = "age"
+
+// DefaultCacheMaxAgeDays is how many days cached summaries and crash reports are kept
+const DefaultCacheMaxAgeDays = 90
+
+// DefaultCacheMaxSizeMB is the size cached summaries and crash reports are pruned to, oldest
+// first
+const DefaultCacheMaxSizeMB = 100
+
+// CacheConfig holds settings for the summary cache
+type CacheConfig struct {
+	// Encryption is empty for plain JSON files or "age" to encrypt entries with a passphrase
+	Encryption string `json:"encryption,omitempty"`
+
+	MaxAgeDays int `json:"max_age_days,omitempty"` // Days cached summaries and crash reports are kept (default 90)
+	MaxSizeMB  int `json:"max_size_mb,omitempty"`  // Size cached summaries and crash reports are pruned to, oldest first (default 100)
+}
+
+// MaxAge returns how long cached summaries and crash reports are kept, DefaultCacheMaxAgeDays when not set
+func (c CacheConfig) MaxAge() time.Duration {
+	days := c.MaxAgeDays
+	if days <= 0 {
+		days = DefaultCacheMaxAgeDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// MaxBytes returns the size cached summaries and crash reports are pruned to, DefaultCacheMaxSizeMB when not set
+func (c CacheConfig) MaxBytes() int64 {
+	size := c.MaxSizeMB
+	if size <= 0 {
+		size = DefaultCacheMaxSizeMB
+	}
+	return int64(size) << 20
+}
+
+// Validate checks that the limits aren't negative
+func (c CacheConfig) Validate() error {
+	if c.MaxAgeDays < 0 {
+		return fmt.Errorf("max_age_days: must not be negative, got %d", c.MaxAgeDays)
+	}
+	if c.MaxSizeMB < 0 {
+		return fmt.Errorf("max_size_mb: must not be negative, got %d", c.MaxSizeMB)
+	}
+	return nil
+}
+
+// DefaultNotifyWindow is how long changes are collected before a digest notification is sent
+const DefaultNotifyWindow = 15 * time.Minute
+
+// DefaultNotifyMaxBatch is the number of changes that sends the digest before the end of its window
+const DefaultNotifyMaxBatch = 10
+
+// NotifyConfig holds settings for the notification digest of 'daily notify'
+type NotifyConfig struct {
+	Window      string `json:"window,omitempty"`       // How long changes are collected before notifying, e.g. "15m"
+	MaxBatch    int    `json:"max_batch,omitempty"`    // Number of changes that sends the digest early (default 10)
+	OpenCommand string `json:"open_command,omitempty"` // Command run when the notification is clicked, e.g. "kitty daily todo"
+	// QuietHours holds the changes detected at night or on weekends until they end
+	QuietHours QuietHoursConfig `json:"quiet_hours,omitempty"`
+}
+
+// WindowDuration returns the digest window, DefaultNotifyWindow when not set
+func (n NotifyConfig) WindowDuration() time.Duration {
+	if window, err := time.ParseDuration(n.Window); err == nil && window > 0 {
+		return window
+	}
+	return DefaultNotifyWindow
+}
+
+// MaxBatchSize returns the number of changes that sends the digest early, DefaultNotifyMaxBatch when not set
+func (n NotifyConfig) MaxBatchSize() int {
+	if n.MaxBatch > 0 {
+		return n.MaxBatch
+	}
+	return DefaultNotifyMaxBatch
+}
+
+// Validate checks that the window is a positive duration, the batch size isn't negative and
+// the quiet hours are valid when set
+func (n NotifyConfig) Validate() error {
+	if n.Window != "" {
+		window, err := time.ParseDuration(n.Window)
+		if err != nil || window <= 0 {
+			return fmt.Errorf("window: invalid duration %q (e.g. 15m, 1h)", n.Window)
+		}
+	}
+	if n.MaxBatch < 0 {
+		return fmt.Errorf("max_batch: must not be negative, got %d", n.MaxBatch)
+	}
+	if n.QuietHours.IsSet() {
+		if _, err := n.QuietHours.QuietHours(); err != nil {
+			return fmt.Errorf("quiet_hours: %w", err)
+		}
+	}
+	return nil
+}
+
+// HideConfig holds settings for the items hidden with 'daily hide'
+type HideConfig struct {
+	// ExpireAfter forgets the items hidden by ID that weren't listed for this long, e.g. "90d" (default never)
+	ExpireAfter string `json:"expire_after,omitempty"`
+}
+
+// ExpiredBefore returns the time before which hidden items were last active to expire, zero
+// when they never expire
+func (h HideConfig) ExpiredBefore(now time.Time) time.Time {
+	if h.ExpireAfter == "" {
+		return time.Time{}
+	}
+	before, err := datetime.SinceDuration(h.ExpireAfter, now)
+	if err != nil {
+		return time.Time{}
+	}
+	return before
 }
 
 func DefaultConfig() *Config {
@@ -30,6 +189,18 @@ func DefaultConfig() *Config {
 		Confluence: provider.Config{
 			Enabled: false,
 		},
+		GitLab: provider.Config{
+			Enabled: false,
+		},
+		Calendar: provider.Config{
+			Enabled: false,
+		},
+		Slack: provider.Config{
+			Enabled: false,
+		},
+		SavedQueries: provider.Config{
+			Enabled: false,
+		},
 	}
 }
 
@@ -41,26 +212,301 @@ func Load() (*Config, error) {
 
 	// If config file doesn't exist, create default
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		config := DefaultConfig()
-		if err := config.Save(); err != nil {
+		if err := DefaultConfig().Save(); err != nil {
 			return nil, fmt.Errorf("failed to create default config: %w", err)
 		}
-		return config, nil
 	}
 
-	data, err := os.ReadFile(configPath)
+	layers, err := readConfigLayers(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
 	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
+	data, err := json.Marshal(layers.merged)
+	if err == nil {
+		err = json.Unmarshal(data, &config)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	if layers.layered {
+		config.personal = layers.personal
+		if config.loaded, err = toJSONObject(config.withLiteralTokens()); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
 	return &config, nil
 }
 
+// LoadWithTokens loads the configuration like Load, then runs the token commands of the
+// enabled providers. Commands building providers use it, so that the others, e.g. hide or
+// config show, never wait on a password manager.
+func LoadWithTokens() (*Config, error) {
+	config, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if err := config.ResolveTokens(context.Background(), runShellCommand); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate checks provider settings that can't be enforced by the JSON schema
+func (c *Config) Validate() error {
+	providers := c.providerEntries()
+	for _, p := range providers {
+		if _, err := p.config.IncludedTypes(); err != nil {
+			return fmt.Errorf("%s.include_types: %w", p.name, err)
+		}
+	}
+	if err := validateDuplicateEntries(providers); err != nil {
+		return err
+	}
+
+	if err := c.Goals.Validate(); err != nil {
+		return fmt.Errorf("goals: %w", err)
+	}
+
+	if err := validateSavedQueries(c.SavedQueries.Queries); err != nil {
+		return fmt.Errorf("saved_queries: %w", err)
+	}
+
+	for _, field := range []struct {
+		name  string
+		teams []string
+	}{
+		{"review_teams", c.GitHub.ReviewTeams},
+		{"exclude_teams", c.GitHub.ExcludeTeams},
+	} {
+		if err := validateTeams(field.teams); err != nil {
+			return fmt.Errorf("github.%s: %w", field.name, err)
+		}
+	}
+
+	if err := validateGlobs(c.Obsidian.ExcludePaths); err != nil {
+		return fmt.Errorf("obsidian.exclude_paths: %w", err)
+	}
+	for _, field := range []struct {
+		name   string
+		states []string
+	}{
+		{"pending_states", c.Obsidian.PendingStates},
+		{"ignored_states", c.Obsidian.IgnoredStates},
+	} {
+		for _, state := range field.states {
+			if _, err := provider.ParseTaskState(state); err != nil {
+				return fmt.Errorf("obsidian.%s: %w", field.name, err)
+			}
+		}
+	}
+
+	if rules := c.Obsidian.MeetingRules; rules != nil {
+		if err := validateGlobs(rules.Folders); err != nil {
+			return fmt.Errorf("obsidian.meeting_rules.folders: %w", err)
+		}
+		for _, title := range rules.Titles {
+			if _, err := regexp.Compile(title); err != nil {
+				return fmt.Errorf("obsidian.meeting_rules.titles: invalid pattern %q: %w", title, err)
+			}
+		}
+	}
+
+	if err := validateDateLayout(c.Obsidian.DailyNoteFormat); err != nil {
+		return fmt.Errorf("obsidian.daily_note_format: %w", err)
+	}
+	if c.Obsidian.ScanWorkers < 0 {
+		return fmt.Errorf("obsidian.scan_workers: must not be negative, got %d", c.Obsidian.ScanWorkers)
+	}
+	if c.Obsidian.MaxLineLength < 0 {
+		return fmt.Errorf("obsidian.max_line_length: must not be negative, got %d", c.Obsidian.MaxLineLength)
+	}
+
+	if c.Confluence.TodoSince != "" {
+		if _, err := datetime.SinceDuration(c.Confluence.TodoSince, time.Now()); err != nil {
+			return fmt.Errorf("confluence.todo_since: %w", err)
+		}
+	}
+	if err := provider.ValidateSpaceKeys(c.Confluence.Spaces); err != nil {
+		return fmt.Errorf("confluence.spaces: %w", err)
+	}
+
+	if err := c.Cache.Validate(); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+
+	if err := c.Notify.Validate(); err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+
+	if c.Hide.ExpireAfter != "" {
+		if _, err := datetime.SinceDuration(c.Hide.ExpireAfter, time.Now()); err != nil {
+			return fmt.Errorf("hide.expire_after: %w", err)
+		}
+	}
+
+	if err := c.Telemetry.Validate(); err != nil {
+		return fmt.Errorf("telemetry: %w", err)
+	}
+
+	if err := c.Translate.Validate(); err != nil {
+		return fmt.Errorf("translate: %w", err)
+	}
+
+	week, err := c.WorkWeek.WorkWeek()
+	if err != nil {
+		return fmt.Errorf("work_week: %w", err)
+	}
+	if _, err := datetime.ResolveSince(c.Sum.DefaultSince(), time.Now(), week); err != nil {
+		return fmt.Errorf("sum.since: %w", err)
+	}
+	if c.Sum.MaxItems < 0 {
+		return fmt.Errorf("sum.max_items: must not be negative, got %d", c.Sum.MaxItems)
+	}
+
+	if c.JIRA.Timezone != "" {
+		if _, err := time.LoadLocation(c.JIRA.Timezone); err != nil {
+			return fmt.Errorf("jira.timezone: unknown timezone %q", c.JIRA.Timezone)
+		}
+	}
+
+	if _, err := severity.ParseRules(c.Severity); err != nil {
+		return fmt.Errorf("severity: %w", err)
+	}
+
+	if err := validateAliases(c.Aliases); err != nil {
+		return fmt.Errorf("aliases: %w", err)
+	}
+
+	return nil
+}
+
+// SeverityRules returns the configured severity rules, severity.DefaultRules when none are
+// configured. Invalid rules are rejected when the config is loaded.
+func (c *Config) SeverityRules() severity.Rules {
+	config := c.Severity
+	if len(config) == 0 {
+		config = severity.DefaultRules
+	}
+	rules, _ := severity.ParseRules(config)
+	return rules
+}
+
+// DateOrder returns the order of the day and the month in slashed dates, from the configured
+// locale only: the environment locale is often en_US on machines of users who don't write
+// dates that way, so it would silently pick the wrong reading
+func (c *Config) DateOrder() datetime.DateOrder {
+	if c.Locale == "" {
+		return datetime.DateOrderUnknown
+	}
+	return datetime.LocaleDateOrder(c.Locale)
+}
+
+// LoadAliases returns the aliases of the config file and the files merged with it. Unlike
+// Load, it neither creates the file nor resolves tokens, as aliases are needed before any
+// command runs.
+func LoadAliases() (map[string]string, error) {
+	configPath, err := getConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	layers, err := readConfigLayers(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config struct {
+		Aliases map[string]string `json:"aliases"`
+	}
+	data, err := json.Marshal(layers.merged)
+	if err == nil {
+		err = json.Unmarshal(data, &config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	if err := validateAliases(config.Aliases); err != nil {
+		return nil, fmt.Errorf("invalid config: aliases: %w", err)
+	}
+	return config.Aliases, nil
+}
+
+// validateAliases checks that every alias is a single word expanding to a command line
+func validateAliases(aliases map[string]string) error {
+	for name, target := range aliases {
+		if name == "" || strings.ContainsFunc(name, unicode.IsSpace) || strings.HasPrefix(name, "-") {
+			return fmt.Errorf("invalid alias name %q, expected a single word", name)
+		}
+		if strings.TrimSpace(target) == "" {
+			return fmt.Errorf("alias %q has no command", name)
+		}
+	}
+	return nil
+}
+
+// validateSavedQueries checks that every saved query has a unique name and a URL
+func validateSavedQueries(queries []provider.SavedQuery) error {
+	names := make(map[string]bool, len(queries))
+	for i, query := range queries {
+		if query.Name == "" {
+			return fmt.Errorf("query %d has no name", i+1)
+		}
+		if names[query.Name] {
+			return fmt.Errorf("duplicate query name %q", query.Name)
+		}
+		names[query.Name] = true
+		if query.URL == "" {
+			return fmt.Errorf("query %q has no url", query.Name)
+		}
+	}
+	return nil
+}
+
+// validateTeams checks that every team is written as org/slug
+func validateTeams(teams []string) error {
+	for _, team := range teams {
+		org, slug, found := strings.Cut(team, "/")
+		if !found || org == "" || slug == "" || strings.Contains(slug, "/") {
+			return fmt.Errorf("invalid team %q, expected org/slug", team)
+		}
+	}
+	return nil
+}
+
+// validateGlobs checks that every pattern is a valid glob
+func validateGlobs(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// validateDateLayout checks that a Go time layout holds a full date, i.e. a year, a month
+// and a day that survive formatting and parsing back
+func validateDateLayout(layout string) error {
+	if layout == "" {
+		return nil
+	}
+	date := time.Date(2024, time.May, 12, 0, 0, 0, 0, time.UTC)
+	parsed, err := time.Parse(layout, date.Format(layout))
+	if err != nil || !parsed.Equal(date) {
+		return fmt.Errorf("invalid date layout %q (expected a Go layout with a year, month and day, e.g. 2006-01-02)", layout)
+	}
+	return nil
+}
+
+// Save writes the config file. Files merged with it by Load are never written.
 func (c *Config) Save() error {
 	configPath, err := getConfigPath()
 	if err != nil {
@@ -73,11 +519,26 @@ func (c *Config) Save() error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	saved := c.withLiteralTokens()
+	data, err := json.MarshalIndent(saved, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
+	// Only the changes are written when other files were merged with the config file, so
+	// that their settings are never copied into it
+	if c.personal != nil {
+		current, err := toJSONObject(saved)
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		applyChanges(c.personal, c.loaded, current)
+		if data, err = json.MarshalIndent(c.personal, "", "  "); err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		c.loaded = current
+	}
+
 	if err := os.WriteFile(configPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
@@ -4,7 +4,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"daily/internal/datetime"
+	"daily/internal/provider"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -137,3 +142,365 @@ func TestLoad_CreatesDefaultConfig(t *testing.T) {
 		t.Fatalf("Created config file is not valid JSON: %v", err)
 	}
 }
+
+func TestLoad_RejectsUnknownIncludeTypes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalConfigPathFunc := configPathFunc
+	testConfigPath := filepath.Join(tempDir, "config.json")
+	configPathFunc = func() (string, error) {
+		return testConfigPath, nil
+	}
+	defer func() { configPathFunc = originalConfigPathFunc }()
+
+	data := `{"obsidian": {"enabled": true, "url": "/vault", "include_types": ["task", "tweet"]}}`
+	if err := os.WriteFile(testConfigPath, []byte(data), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Expected error for unknown include type, got nil")
+	}
+	if !strings.Contains(err.Error(), "obsidian.include_types") || !strings.Contains(err.Error(), `"tweet"`) {
+		t.Errorf("Expected error to name the provider and type, got: %v", err)
+	}
+}
+
+func TestValidate_IncludeTypes(t *testing.T) {
+	config := &Config{}
+	config.GitHub.IncludeTypes = []string{"pr", "commit"}
+	config.Obsidian.IncludeTypes = []string{"task"}
+
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestValidate_JIRATimezone(t *testing.T) {
+	config := &Config{}
+	config.JIRA.Timezone = "Europe/Paris"
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	config.JIRA.Timezone = "Mars/Olympus"
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "jira.timezone") {
+		t.Errorf("Expected jira.timezone error, got: %v", err)
+	}
+}
+
+func TestValidate_GitHubTeams(t *testing.T) {
+	config := &Config{}
+	config.GitHub.ReviewTeams = []string{"acme/platform", "acme/api-reviewers"}
+	config.GitHub.ExcludeTeams = []string{"acme/everyone"}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	for _, team := range []string{"platform", "acme/", "/platform", "acme/platform/api"} {
+		config.GitHub.ExcludeTeams = []string{team}
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "github.exclude_teams") {
+			t.Errorf("Expected github.exclude_teams error for %q, got: %v", team, err)
+		}
+	}
+}
+
+func TestValidate_SavedQueries(t *testing.T) {
+	tests := []struct {
+		name     string
+		queries  []provider.SavedQuery
+		expected string
+	}{
+		{"valid", []provider.SavedQuery{{Name: "usages", URL: "https://example.com"}, {Name: "alerts", URL: "https://example.com"}}, ""},
+		{"missing name", []provider.SavedQuery{{URL: "https://example.com"}}, "query 1 has no name"},
+		{"missing url", []provider.SavedQuery{{Name: "usages"}}, `query "usages" has no url`},
+		{"duplicate name", []provider.SavedQuery{{Name: "usages", URL: "https://a"}, {Name: "usages", URL: "https://b"}}, `duplicate query name "usages"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{}
+			config.SavedQueries.Queries = tt.queries
+
+			err := config.Validate()
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "saved_queries: "+tt.expected) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestValidate_Notify(t *testing.T) {
+	tests := []struct {
+		name     string
+		notify   NotifyConfig
+		expected string
+	}{
+		{"defaults", NotifyConfig{}, ""},
+		{"valid", NotifyConfig{Window: "30m", MaxBatch: 5}, ""},
+		{"invalid window", NotifyConfig{Window: "soon"}, `window: invalid duration "soon"`},
+		{"zero window", NotifyConfig{Window: "0s"}, `window: invalid duration "0s"`},
+		{"negative batch", NotifyConfig{MaxBatch: -1}, "max_batch: must not be negative"},
+		{"quiet hours", NotifyConfig{QuietHours: QuietHoursConfig{Start: "19:00", End: "08:00", Days: []string{"monday"}, Timezone: "UTC"}}, ""},
+		{"quiet hours without end", NotifyConfig{QuietHours: QuietHoursConfig{Start: "19:00"}}, "quiet_hours: start and end are both required"},
+		{"invalid quiet start", NotifyConfig{QuietHours: QuietHoursConfig{Start: "7pm", End: "08:00"}}, `quiet_hours: start: invalid time "7pm"`},
+		{"invalid quiet day", NotifyConfig{QuietHours: QuietHoursConfig{Start: "19:00", End: "08:00", Days: []string{"someday"}}}, "quiet_hours: days:"},
+		{"unknown quiet timezone", NotifyConfig{QuietHours: QuietHoursConfig{Start: "19:00", End: "08:00", Timezone: "Mars/Olympus"}}, `quiet_hours: timezone: unknown time zone "Mars/Olympus"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Notify: tt.notify}
+
+			err := config.Validate()
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "notify: "+tt.expected) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestNotifyConfig_Defaults(t *testing.T) {
+	if window := (NotifyConfig{}).WindowDuration(); window != DefaultNotifyWindow {
+		t.Errorf("Expected default window %v, got %v", DefaultNotifyWindow, window)
+	}
+	if window := (NotifyConfig{Window: "1h"}).WindowDuration(); window != time.Hour {
+		t.Errorf("Expected window 1h, got %v", window)
+	}
+	if batch := (NotifyConfig{}).MaxBatchSize(); batch != DefaultNotifyMaxBatch {
+		t.Errorf("Expected default batch size %d, got %d", DefaultNotifyMaxBatch, batch)
+	}
+}
+
+func TestValidate_ObsidianExcludePaths(t *testing.T) {
+	config := &Config{}
+	config.Obsidian.ExcludePaths = []string{"Templates", "**/node_modules", "*.excalidraw.md"}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	config.Obsidian.ExcludePaths = []string{"Archive/[2023"}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "obsidian.exclude_paths") {
+		t.Errorf("Expected obsidian.exclude_paths error, got: %v", err)
+	}
+}
+
+func TestValidate_ObsidianMeetingRules(t *testing.T) {
+	config := &Config{}
+	config.Obsidian.MeetingRules = &provider.MeetingRules{Folders: []string{"Work/**/Meetings"}, Titles: []string{`(?i)^1:1 with`}}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	config.Obsidian.MeetingRules = &provider.MeetingRules{Titles: []string{"^sync (with"}}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "obsidian.meeting_rules.titles") {
+		t.Errorf("Expected obsidian.meeting_rules.titles error, got: %v", err)
+	}
+
+	config.Obsidian.MeetingRules = &provider.MeetingRules{Folders: []string{"Meetings/[2024"}}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "obsidian.meeting_rules.folders") {
+		t.Errorf("Expected obsidian.meeting_rules.folders error, got: %v", err)
+	}
+}
+
+func TestValidate_ObsidianTaskStates(t *testing.T) {
+	config := &Config{}
+	config.Obsidian.PendingStates = []string{" ", "[/]", ">"}
+	config.Obsidian.IgnoredStates = []string{"[-]"}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	config.Obsidian.IgnoredStates = []string{"cancelled"}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "obsidian.ignored_states") {
+		t.Errorf("Expected obsidian.ignored_states error, got: %v", err)
+	}
+}
+
+func TestValidate_ObsidianDailyNoteFormat(t *testing.T) {
+	for _, format := range []string{"", "2006-01-02", "02.01.2006", "2006/01/2006-01-02 Monday"} {
+		config := &Config{}
+		config.Obsidian.DailyNoteFormat = format
+		if err := config.Validate(); err != nil {
+			t.Errorf("Expected no error for %q, got: %v", format, err)
+		}
+	}
+
+	for _, format := range []string{"YYYY-MM-DD", "2006-01", "15:04"} {
+		config := &Config{}
+		config.Obsidian.DailyNoteFormat = format
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "obsidian.daily_note_format") {
+			t.Errorf("Expected obsidian.daily_note_format error for %q, got: %v", format, err)
+		}
+	}
+}
+
+func TestValidate_ObsidianScanWorkers(t *testing.T) {
+	config := &Config{}
+	config.Obsidian.ScanWorkers = 4
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	config.Obsidian.ScanWorkers = -1
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "obsidian.scan_workers") {
+		t.Errorf("Expected obsidian.scan_workers error, got: %v", err)
+	}
+}
+
+func TestValidate_ObsidianMaxLineLength(t *testing.T) {
+	config := &Config{}
+	config.Obsidian.MaxLineLength = 4096
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	config.Obsidian.MaxLineLength = -1
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "obsidian.max_line_length") {
+		t.Errorf("Expected obsidian.max_line_length error, got: %v", err)
+	}
+}
+
+func TestValidate_ConfluenceTodoSince(t *testing.T) {
+	config := &Config{}
+	config.Confluence.TodoSince = "1w"
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	config.Confluence.TodoSince = "soon"
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "confluence.todo_since") {
+		t.Errorf("Expected confluence.todo_since error, got: %v", err)
+	}
+}
+
+func TestValidate_ConfluenceSpaces(t *testing.T) {
+	config := &Config{}
+	config.Confluence.Spaces = []string{"ENG", "PLAT"}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	config.Confluence.Spaces = []string{"ENG", `PLAT") OR space = "HR`}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "confluence.spaces") {
+		t.Errorf("Expected confluence.spaces error, got: %v", err)
+	}
+}
+
+func TestValidate_Severity(t *testing.T) {
+	config := &Config{Severity: map[string]string{"blocker": "red", "p2": "normal"}}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if level := config.SeverityRules().Resolve("", []string{"P2"}); level != "normal" {
+		t.Errorf("Expected the configured rules, got '%s'", level)
+	}
+
+	config.Severity = map[string]string{"p0": "scarlet"}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "severity: p0") {
+		t.Errorf("Expected severity error, got: %v", err)
+	}
+
+	defaults := (&Config{}).SeverityRules()
+	if level := defaults.Resolve("Highest", nil); level != "critical" {
+		t.Errorf("Expected the default rules without configuration, got '%s'", level)
+	}
+}
+
+func TestValidate_Aliases(t *testing.T) {
+	config := &Config{Aliases: map[string]string{"today": "sum --since 1d"}}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	for _, aliases := range []map[string]string{
+		{"two words": "sum"},
+		{"--today": "sum"},
+		{"today": "  "},
+	} {
+		config := &Config{Aliases: aliases}
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "aliases") {
+			t.Errorf("Expected aliases error for %v, got: %v", aliases, err)
+		}
+	}
+}
+
+func TestLoadAliases(t *testing.T) {
+	originalConfigPathFunc := configPathFunc
+	testConfigPath := filepath.Join(t.TempDir(), "config.json")
+	configPathFunc = func() (string, error) {
+		return testConfigPath, nil
+	}
+	defer func() { configPathFunc = originalConfigPathFunc }()
+
+	aliases, err := LoadAliases()
+	if err != nil || aliases != nil {
+		t.Errorf("Expected no aliases without a config file, got %v, %v", aliases, err)
+	}
+	if _, err := os.Stat(testConfigPath); !os.IsNotExist(err) {
+		t.Error("Expected the config file not to be created")
+	}
+
+	if err := os.WriteFile(testConfigPath, []byte(`{"aliases": {"rev": "reviews -o tui"}}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	aliases, err = LoadAliases()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if aliases["rev"] != "reviews -o tui" {
+		t.Errorf("Expected the rev alias, got %v", aliases)
+	}
+}
+
+func TestCacheConfig_Limits(t *testing.T) {
+	var defaults CacheConfig
+	if defaults.MaxAge() != 90*24*time.Hour || defaults.MaxBytes() != 100<<20 {
+		t.Errorf("Expected 90 days and 100 MB by default, got %v and %d", defaults.MaxAge(), defaults.MaxBytes())
+	}
+
+	custom := CacheConfig{MaxAgeDays: 7, MaxSizeMB: 5}
+	if custom.MaxAge() != 7*24*time.Hour || custom.MaxBytes() != 5<<20 {
+		t.Errorf("Expected 7 days and 5 MB, got %v and %d", custom.MaxAge(), custom.MaxBytes())
+	}
+
+	config := &Config{Cache: CacheConfig{MaxAgeDays: -1}}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "cache: max_age_days") {
+		t.Errorf("Expected cache.max_age_days error, got: %v", err)
+	}
+	config = &Config{Cache: CacheConfig{MaxSizeMB: -1}}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "cache: max_size_mb") {
+		t.Errorf("Expected cache.max_size_mb error, got: %v", err)
+	}
+}
+
+func TestConfig_DateOrder(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_TIME", "de_DE.UTF-8")
+	t.Setenv("LANG", "en_US.UTF-8")
+
+	if order := (&Config{Locale: "en-US"}).DateOrder(); order != datetime.MonthFirst {
+		t.Errorf("Expected the order of the configured locale, got %s", order)
+	}
+	if order := (&Config{Locale: "fr-FR"}).DateOrder(); order != datetime.DayFirst {
+		t.Errorf("Expected the order of the configured locale, got %s", order)
+	}
+
+	// The environment locale is ignored
+	if order := (&Config{}).DateOrder(); order != datetime.DateOrderUnknown {
+		t.Errorf("Expected an unknown order without locale, got %s", order)
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"daily/internal/provider"
+)
+
+// providerEntry is a provider instance of the config, named by its config key
+type providerEntry struct {
+	name   string
+	kind   string // Provider type, e.g. github
+	config provider.Config
+}
+
+// providerEntries returns the provider instances of the config
+func (c *Config) providerEntries() []providerEntry {
+	return []providerEntry{
+		{"github", "github", c.GitHub},
+		{"jira", "jira", c.JIRA},
+		{"obsidian", "obsidian", c.Obsidian},
+		{"confluence", "confluence", c.Confluence},
+		{"gitlab", "gitlab", c.GitLab},
+		{"calendar", "calendar", c.Calendar},
+		{"slack", "slack", c.Slack},
+		{"saved_queries", "saved_queries", c.SavedQueries},
+	}
+}
+
+// listedEntries returns the list settings of a provider whose entries are each fetched, by
+// JSON key, e.g. the repositories of GitHub
+func listedEntries(cfg provider.Config) []struct {
+	key    string
+	values []string
+} {
+	return []struct {
+		key    string
+		values []string
+	}{
+		{"repos", cfg.Repos},
+		{"review_teams", cfg.ReviewTeams},
+		{"spaces", cfg.Spaces},
+		{"groups", cfg.Groups},
+		{"calendars", cfg.Calendars},
+		{"channels", cfg.Channels},
+	}
+}
+
+// validateDuplicateEntries rejects the entries listed twice in the list settings of a
+// provider, ignoring case and surrounding spaces, e.g. a repository whose workflow runs would
+// be reported twice
+func validateDuplicateEntries(entries []providerEntry) error {
+	for _, entry := range entries {
+		for _, list := range listedEntries(entry.config) {
+			seen := make(map[string]bool, len(list.values))
+			for _, value := range list.values {
+				key := strings.ToLower(strings.TrimSpace(value))
+				if seen[key] {
+					return fmt.Errorf("%s.%s: %q is listed twice", entry.name, list.key, value)
+				}
+				seen[key] = true
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"daily/internal/provider"
+)
+
+func TestValidateDuplicateEntries(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{
+			name: "distinct entries",
+			config: Config{
+				GitHub:     provider.Config{Enabled: true, Repos: []string{"acme/api", "acme/web"}, ReviewTeams: []string{"acme/platform"}},
+				Confluence: provider.Config{Spaces: []string{"ENG", "PLAT"}},
+				Slack:      provider.Config{Channels: []string{"platform", "random"}},
+			},
+		},
+		{
+			name:     "same repository twice",
+			config:   Config{GitHub: provider.Config{Repos: []string{"acme/api", "Acme/API "}}},
+			expected: `github.repos: "Acme/API " is listed twice`,
+		},
+		{
+			name:     "same space twice",
+			config:   Config{Confluence: provider.Config{Spaces: []string{"ENG", "eng"}}},
+			expected: `confluence.spaces: "eng" is listed twice`,
+		},
+		{
+			name:     "same calendar twice",
+			config:   Config{Calendar: provider.Config{Calendars: []string{"primary", "team@group.calendar.google.com", "primary"}}},
+			expected: `calendar.calendars: "primary" is listed twice`,
+		},
+		{
+			name: "same entry in different settings",
+			config: Config{
+				GitHub: provider.Config{Repos: []string{"acme/api"}, ReviewTeams: []string{"acme/api"}},
+				GitLab: provider.Config{Groups: []string{"acme/api"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDuplicateEntries(tt.config.providerEntries())
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Expected error %q, got: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_DuplicateEntries(t *testing.T) {
+	config := DefaultConfig()
+	config.Slack.Channels = []string{"C01", "c01"}
+
+	if err := config.Validate(); err == nil || err.Error() != `slack.channels: "c01" is listed twice` {
+		t.Errorf("Expected the duplicate channel rejected, got: %v", err)
+	}
+}
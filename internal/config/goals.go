@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"daily/internal/activity"
+)
+
+// GoalsConfig holds the daily activity targets shown in the summary
+type GoalsConfig struct {
+	// Daily maps goal names to their daily target, e.g. {"commits": 5, "reviews": 3}
+	Daily map[string]int `json:"daily,omitempty"`
+	// Weekdays overrides targets on given days, e.g. {"friday": {"commits": 2}}; 0 disables a goal that day
+	Weekdays map[string]map[string]int `json:"weekdays,omitempty"`
+}
+
+// TargetsFor returns the goal targets that apply on the given weekday
+func (g GoalsConfig) TargetsFor(day time.Weekday) map[string]int {
+	targets := make(map[string]int, len(g.Daily))
+	for name, target := range g.Daily {
+		targets[name] = target
+	}
+
+	for weekday, overrides := range g.Weekdays {
+		if d, err := ParseWeekday(weekday); err != nil || d != day {
+			continue
+		}
+		for name, target := range overrides {
+			targets[name] = target
+		}
+	}
+
+	return targets
+}
+
+// Set sets the target of a goal, every day or on a single weekday when weekday is not empty.
+// A target of 0 removes a daily goal, or disables it on the weekday.
+func (g *GoalsConfig) Set(name string, target int, weekday string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("goal name cannot be empty")
+	}
+	if target < 0 {
+		return fmt.Errorf("goal target cannot be negative: %d", target)
+	}
+	// Daily goals can always be removed, even when no longer valid
+	if weekday != "" || target > 0 {
+		if err := activity.ValidateGoalName(name); err != nil {
+			return err
+		}
+	}
+
+	if weekday == "" {
+		if target == 0 {
+			delete(g.Daily, name)
+			return nil
+		}
+		if g.Daily == nil {
+			g.Daily = make(map[string]int)
+		}
+		g.Daily[name] = target
+		return nil
+	}
+
+	day, err := ParseWeekday(weekday)
+	if err != nil {
+		return err
+	}
+	key := strings.ToLower(day.String())
+	if g.Weekdays == nil {
+		g.Weekdays = make(map[string]map[string]int)
+	}
+	if g.Weekdays[key] == nil {
+		g.Weekdays[key] = make(map[string]int)
+	}
+	g.Weekdays[key][name] = target
+	return nil
+}
+
+// Validate checks that goal and weekday names are valid and targets are not negative
+func (g GoalsConfig) Validate() error {
+	for _, name := range slices.Sorted(maps.Keys(g.Daily)) {
+		if target := g.Daily[name]; target < 0 {
+			return fmt.Errorf("goal %q has a negative target", name)
+		}
+		if err := activity.ValidateGoalName(name); err != nil {
+			return err
+		}
+	}
+	for _, weekday := range slices.Sorted(maps.Keys(g.Weekdays)) {
+		if _, err := ParseWeekday(weekday); err != nil {
+			return err
+		}
+		overrides := g.Weekdays[weekday]
+		for _, name := range slices.Sorted(maps.Keys(overrides)) {
+			if target := overrides[name]; target < 0 {
+				return fmt.Errorf("goal %q on %s has a negative target", name, weekday)
+			}
+			if err := activity.ValidateGoalName(name); err != nil {
+				return fmt.Errorf("%s: %w", weekday, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseWeekday converts an English weekday name (e.g., "friday", "Fri") to a time.Weekday
+func ParseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || (len(name) >= 3 && strings.HasPrefix(full, name)) {
+			return day, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("unknown weekday %q", name)
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoalsConfig_TargetsFor(t *testing.T) {
+	goals := GoalsConfig{
+		Daily: map[string]int{"commits": 5, "reviews": 3},
+		Weekdays: map[string]map[string]int{
+			"friday": {"commits": 2, "reviews": 0},
+			"Mon":    {"notes": 1},
+		},
+	}
+
+	tests := []struct {
+		day      time.Weekday
+		expected map[string]int
+	}{
+		{time.Wednesday, map[string]int{"commits": 5, "reviews": 3}},
+		{time.Friday, map[string]int{"commits": 2, "reviews": 0}},
+		{time.Monday, map[string]int{"commits": 5, "reviews": 3, "notes": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.day.String(), func(t *testing.T) {
+			targets := goals.TargetsFor(tt.day)
+			if len(targets) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, targets)
+			}
+			for name, target := range tt.expected {
+				if targets[name] != target {
+					t.Errorf("Expected %s target %d, got %d", name, target, targets[name])
+				}
+			}
+		})
+	}
+}
+
+func TestGoalsConfig_Set(t *testing.T) {
+	var goals GoalsConfig
+
+	if err := goals.Set("commits", 5, ""); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := goals.Set("commits", 2, "Fri"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if goals.Daily["commits"] != 5 || goals.Weekdays["friday"]["commits"] != 2 {
+		t.Errorf("Expected daily and friday targets to be stored, got %+v", goals)
+	}
+
+	if err := goals.Set("commits", 0, ""); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, ok := goals.Daily["commits"]; ok {
+		t.Error("Expected a target of 0 to remove the daily goal")
+	}
+
+	if err := goals.Set("commits", -1, ""); err == nil {
+		t.Error("Expected error for negative target")
+	}
+	if err := goals.Set("commits", 1, "someday"); err == nil {
+		t.Error("Expected error for unknown weekday")
+	}
+	if err := goals.Set(" ", 1, ""); err == nil {
+		t.Error("Expected error for empty name")
+	}
+	if err := goals.Set("comits", 1, ""); err == nil {
+		t.Error("Expected error for unknown goal")
+	}
+
+	// A goal no longer valid can still be removed
+	goals.Daily["comits"] = 2
+	if err := goals.Set("comits", 0, ""); err != nil || len(goals.Daily) != 0 {
+		t.Errorf("Expected the unknown goal to be removed, got %v and %+v", err, goals.Daily)
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Weekday
+		wantErr  bool
+	}{
+		{"friday", time.Friday, false},
+		{"Sunday", time.Sunday, false},
+		{"tue", time.Tuesday, false},
+		{"fr", time.Sunday, true},
+		{"someday", time.Sunday, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			day, err := ParseWeekday(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && day != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, day)
+			}
+		})
+	}
+}
+
+func TestValidate_Goals(t *testing.T) {
+	config := &Config{Goals: GoalsConfig{Weekdays: map[string]map[string]int{"caturday": {"naps": 3}}}}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected error for unknown weekday")
+	}
+
+	config = &Config{Goals: GoalsConfig{Daily: map[string]int{"commits": 5, "tag:reading": 1}}}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	config = &Config{Goals: GoalsConfig{Weekdays: map[string]map[string]int{"friday": {"reveiws": 2}}}}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), `unknown goal "reveiws"`) {
+		t.Errorf("Expected error for unknown goal, got %v", err)
+	}
+}
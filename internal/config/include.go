@@ -0,0 +1,302 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ExtraConfigEnv lists config files merged over the config file, separated like PATH
+// entries, e.g. to inject settings in CI
+const ExtraConfigEnv = "DAILY_EXTRA_CONFIG"
+
+// configLayers is the config file merged with the files it includes and the extra files
+type configLayers struct {
+	personal map[string]any // The config file itself, the only one ever written
+	merged   map[string]any
+	layered  bool // Other files were merged with the config file
+}
+
+// readConfigLayers reads the config file and merges it with the files of its include list,
+// in order and overridden by the config file, then with the files of ExtraConfigEnv, which
+// override it. Included paths are relative to the directory of the config file, extra paths
+// to the working directory. A missing config file is read as an empty one.
+func readConfigLayers(configPath string) (configLayers, error) {
+	personal, err := readJSONObject(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		personal = map[string]any{}
+	} else if err != nil {
+		return configLayers{}, err
+	}
+	return mergeConfigLayers(configPath, personal)
+}
+
+// mergeConfigLayers merges the content of the config file with the other files, see
+// readConfigLayers
+func mergeConfigLayers(configPath string, personal map[string]any) (configLayers, error) {
+	var include []string
+	if value, ok := personal["include"]; ok {
+		data, _ := json.Marshal(value)
+		if err := json.Unmarshal(data, &include); err != nil {
+			return configLayers{}, fmt.Errorf("include: expected a list of paths")
+		}
+	}
+
+	layers := configLayers{personal: personal, merged: map[string]any{}}
+	for _, path := range include {
+		if strings.TrimSpace(path) == "" {
+			return configLayers{}, fmt.Errorf("include: empty path")
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(configPath), path)
+		}
+		if err := layers.mergeFile(path); err != nil {
+			return configLayers{}, fmt.Errorf("include: %w", err)
+		}
+	}
+
+	layers.merged = mergeJSON(layers.merged, personal)
+
+	for _, path := range filepath.SplitList(os.Getenv(ExtraConfigEnv)) {
+		if path == "" {
+			continue
+		}
+		if err := layers.mergeFile(path); err != nil {
+			return configLayers{}, fmt.Errorf("%s: %w", ExtraConfigEnv, err)
+		}
+	}
+
+	return layers, nil
+}
+
+// mergeFile merges an included or extra file over the layers merged so far
+func (l *configLayers) mergeFile(path string) error {
+	object, err := readJSONObject(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := object["include"]; ok {
+		return fmt.Errorf("%s: included files can't include other files", path)
+	}
+	if key := credentialKey(object, ""); key != "" {
+		return fmt.Errorf("%s: %s can only be set in the config file", path, key)
+	}
+	l.merged = mergeJSON(l.merged, object)
+	l.layered = true
+	return nil
+}
+
+// credentialKeys are the settings only the config file can set: a token shared in a team file
+// would be used by everyone, and a token command would run whatever the file says
+var credentialKeys = []string{"token", "token_cmd"}
+
+// credentialKey returns the dotted path of the first credential setting of a JSON object,
+// prefixed by prefix, "" when there is none
+func credentialKey(object map[string]any, prefix string) string {
+	for _, key := range slices.Sorted(maps.Keys(object)) {
+		if slices.Contains(credentialKeys, key) {
+			return prefix + key
+		}
+		if child, ok := object[key].(map[string]any); ok {
+			if found := credentialKey(child, prefix+key+"."); found != "" {
+				return found
+			}
+		}
+	}
+	return ""
+}
+
+// readJSONObject reads a file holding a JSON object
+func readJSONObject(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if object == nil {
+		object = map[string]any{}
+	}
+	return object, nil
+}
+
+// mergeJSON returns base with the values of overlay. Objects are merged key by key, any
+// other value of overlay, lists included, replaces the value of base.
+func mergeJSON(base, overlay map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		baseObject, baseIsObject := merged[key].(map[string]any)
+		overlayObject, overlayIsObject := value.(map[string]any)
+		if baseIsObject && overlayIsObject {
+			merged[key] = mergeJSON(baseObject, overlayObject)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// applyChanges sets in personal the values that differ between the before and after
+// versions of a merged config, and removes the keys removed from it, so that the values
+// coming from other files are never copied into personal
+func applyChanges(personal, before, after map[string]any) {
+	for key, value := range after {
+		previous, existed := before[key]
+		if existed && reflect.DeepEqual(previous, value) {
+			continue
+		}
+
+		previousObject, previousIsObject := previous.(map[string]any)
+		object, isObject := value.(map[string]any)
+		if previousIsObject && isObject {
+			child, ok := personal[key].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+			}
+			applyChanges(child, previousObject, object)
+			personal[key] = child
+			continue
+		}
+		personal[key] = value
+	}
+
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			delete(personal, key)
+		}
+	}
+}
+
+// toJSONObject returns the JSON object a value is marshalled to
+func toJSONObject(value any) (map[string]any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// Set sets a setting of the config file, given by its dotted JSON path, e.g.
+// "github.username", and returns the path of the file written. The value of a text setting is
+// kept as is, other values are parsed as JSON. Only the config file is ever written: settings of included files are
+// overridden, never edited, so that personal secrets can't end up in a shared file.
+func Set(key, value string) (string, error) {
+	configPath, err := getConfigPath()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	path := strings.Split(key, ".")
+	setting, ok := settingType(reflect.TypeFor[Config](), path)
+	if !ok {
+		return "", fmt.Errorf("unknown setting %q", key)
+	}
+
+	personal, err := readJSONObject(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		personal = map[string]any{}
+	} else if err != nil {
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var parsed any = value
+	if setting.Kind() != reflect.String {
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			return "", fmt.Errorf("%s: invalid value %q: %w", key, value, err)
+		}
+	}
+	if err := setJSONPath(personal, path, parsed); err != nil {
+		return "", fmt.Errorf("%s: %w", key, err)
+	}
+
+	layers, err := mergeConfigLayers(configPath, personal)
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+	var config Config
+	data, err := json.Marshal(layers.merged)
+	if err == nil {
+		err = json.Unmarshal(data, &config)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: invalid value %q: %w", key, value, err)
+	}
+	if err := config.Validate(); err != nil {
+		return "", fmt.Errorf("invalid config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if data, err = json.MarshalIndent(personal, "", "  "); err != nil {
+		return "", fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.WriteFile(configPath, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write config file: %w", err)
+	}
+	return configPath, nil
+}
+
+// settingType returns the type of the setting a dotted JSON path names in a config type, if
+// any. Any key of a map is a setting.
+func settingType(t reflect.Type, path []string) (reflect.Type, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if len(path) == 0 {
+		return t, true
+	}
+	if path[0] == "" {
+		return nil, false
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		return settingType(t.Elem(), path[1:])
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if field.IsExported() && name == path[0] {
+				return settingType(field.Type, path[1:])
+			}
+		}
+	}
+	return nil, false
+}
+
+// setJSONPath sets the value at a path of a JSON object, creating the objects on the way
+func setJSONPath(object map[string]any, path []string, value any) error {
+	for _, key := range path[:len(path)-1] {
+		child, ok := object[key].(map[string]any)
+		if !ok {
+			if _, exists := object[key]; exists {
+				return fmt.Errorf("%s is not an object", key)
+			}
+			child = map[string]any{}
+			object[key] = child
+		}
+		object = child
+	}
+	object[path[len(path)-1]] = value
+	return nil
+}
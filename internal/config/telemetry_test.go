@@ -0,0 +1,89 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidate_Telemetry(t *testing.T) {
+	config := &Config{Telemetry: TelemetryConfig{Enabled: true, Endpoint: "https://telemetry.acme.example/daily"}}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	for _, endpoint := range []string{"telemetry.acme.example", "ftp://telemetry.acme.example", "https://"} {
+		config.Telemetry.Endpoint = endpoint
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "telemetry: endpoint") {
+			t.Errorf("Expected telemetry endpoint error for %q, got: %v", endpoint, err)
+		}
+	}
+}
+
+func TestLoadTelemetry(t *testing.T) {
+	dir := useConfigDir(t)
+	writeJSONFile(t, filepath.Join(dir, "config.json"), `{
+		"include": ["team.json"],
+		"github": {"enabled": true, "token": "personal"},
+		"jira": {"token_cmd": "exit 1"},
+		"obsidian": {"enabled": false},
+		"telemetry": {"enabled": true, "prompted": true}
+	}`)
+	writeJSONFile(t, filepath.Join(dir, "team.json"), `{
+		"jira": {"enabled": true},
+		"telemetry": {"endpoint": "https://telemetry.acme.example/daily"}
+	}`)
+
+	settings, err := LoadTelemetry()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !settings.Telemetry.Enabled || !settings.Telemetry.Prompted {
+		t.Errorf("Expected telemetry enabled by the config file, got %+v", settings.Telemetry)
+	}
+	if settings.Telemetry.Endpoint != "https://telemetry.acme.example/daily" {
+		t.Errorf("Expected the endpoint of the team config, got %q", settings.Telemetry.Endpoint)
+	}
+	if !slices.Equal(settings.Providers, []string{"github", "jira"}) {
+		t.Errorf("Expected the enabled providers, got %v", settings.Providers)
+	}
+}
+
+func TestLoadTelemetry_OptInOnlyFromConfigFile(t *testing.T) {
+	dir := useConfigDir(t)
+	writeJSONFile(t, filepath.Join(dir, "config.json"), `{"include": ["team.json"]}`)
+	writeJSONFile(t, filepath.Join(dir, "team.json"), `{"telemetry": {"enabled": true, "prompted": true, "endpoint": "https://telemetry.acme.example/daily"}}`)
+	t.Setenv(ExtraConfigEnv, writeExtraConfig(t, `{"telemetry": {"enabled": true}}`))
+
+	settings, err := LoadTelemetry()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if settings.Telemetry.Enabled || settings.Telemetry.Prompted {
+		t.Errorf("Expected other files not to enable telemetry, got %+v", settings.Telemetry)
+	}
+}
+
+// writeExtraConfig writes an extra config file and returns its path
+func writeExtraConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "extra.json")
+	writeJSONFile(t, path, content)
+	return path
+}
+
+func TestLoadTelemetry_NoConfigFile(t *testing.T) {
+	dir := useConfigDir(t)
+
+	settings, err := LoadTelemetry()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if settings.Telemetry.Enabled || len(settings.Providers) != 0 {
+		t.Errorf("Expected telemetry disabled without config file, got %+v", settings)
+	}
+	if _, err := readJSONObject(filepath.Join(dir, "config.json")); err == nil {
+		t.Error("Expected LoadTelemetry not to create the config file")
+	}
+}
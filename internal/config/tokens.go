@@ -0,0 +1,151 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"daily/internal/provider"
+	"daily/internal/translate"
+)
+
+// tokenCommandTimeout bounds how long a token command may run, leaving time to unlock a
+// password manager
+const tokenCommandTimeout = 30 * time.Second
+
+// CommandRunner runs a shell command and returns what it printed on stdout
+type CommandRunner func(ctx context.Context, command string) ([]byte, error)
+
+// runShellCommand runs a command with sh, capturing its stderr into the error. Stdin is
+// kept so that password managers can prompt for a passphrase.
+func runShellCommand(ctx context.Context, command string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s", tokenCommandTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// providerConfigs returns the provider settings by name, for updating them in place
+func (c *Config) providerConfigs() []struct {
+	name   string
+	config *provider.Config
+} {
+	return []struct {
+		name   string
+		config *provider.Config
+	}{
+		{"github", &c.GitHub},
+		{"jira", &c.JIRA},
+		{"obsidian", &c.Obsidian},
+		{"confluence", &c.Confluence},
+		{"gitlab", &c.GitLab},
+		{"slack", &c.Slack},
+		{"saved_queries", &c.SavedQueries},
+	}
+}
+
+// tokenSetting is a token of the config that the output of a command can replace
+type tokenSetting struct {
+	name    string
+	enabled bool   // Whether the token is used, commands of unused tokens aren't run
+	command string // token_cmd
+	token   *string
+}
+
+// tokenSettings returns the tokens of the providers and of the DeepL translations, for
+// updating them in place
+func (c *Config) tokenSettings() []tokenSetting {
+	var settings []tokenSetting
+	for _, p := range c.providerConfigs() {
+		settings = append(settings, tokenSetting{name: p.name, enabled: p.config.Enabled, command: p.config.TokenCmd, token: &p.config.Token})
+	}
+	return append(settings, tokenSetting{
+		name:    "translate",
+		enabled: c.Translate.Enabled() && c.Translate.Backend == translate.BackendDeepL,
+		command: c.Translate.TokenCmd,
+		token:   &c.Translate.Token,
+	})
+}
+
+// ResolveTokens replaces the token of the enabled providers and translations with a token_cmd
+// by the output of the command, ignoring the literal token. Each command is run once, so
+// settings sharing a command (e.g. JIRA and Confluence) run it a single time. The token is
+// never part of the errors returned.
+func (c *Config) ResolveTokens(ctx context.Context, run CommandRunner) error {
+	tokens := make(map[string]string)
+
+	for _, setting := range c.tokenSettings() {
+		command := strings.TrimSpace(setting.command)
+		if !setting.enabled || command == "" {
+			continue
+		}
+
+		token, ok := tokens[command]
+		if !ok {
+			var err error
+			if token, err = runTokenCommand(ctx, run, command); err != nil {
+				return fmt.Errorf("%s: token command failed: %w", setting.name, err)
+			}
+			tokens[command] = token
+		}
+
+		if c.literalTokens == nil {
+			c.literalTokens = make(map[string]string)
+		}
+		c.literalTokens[setting.name] = *setting.token
+		*setting.token = token
+	}
+
+	return nil
+}
+
+// runTokenCommand runs a token command, which must print the token on a single line
+func runTokenCommand(ctx context.Context, run CommandRunner, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, tokenCommandTimeout)
+	defer cancel()
+
+	out, err := run(ctx, command)
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimRight(string(out), "\r\n")
+	switch {
+	case strings.TrimSpace(token) == "":
+		return "", fmt.Errorf("%q printed no token", command)
+	case strings.ContainsAny(token, "\r\n"):
+		// pass prints extra lines after the password, e.g. usernames or URLs
+		return "", fmt.Errorf("%q printed several lines, expected only the token (e.g. pass show ... | head -n 1)", command)
+	}
+	return token, nil
+}
+
+// withLiteralTokens returns a copy of the config with the tokens read from the config file,
+// so that tokens obtained from commands are never saved
+func (c *Config) withLiteralTokens() Config {
+	saved := *c
+	saved.literalTokens = nil
+	for _, setting := range saved.tokenSettings() {
+		if token, ok := c.literalTokens[setting.name]; ok {
+			*setting.token = token
+		}
+	}
+	return saved
+}
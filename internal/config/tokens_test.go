@@ -0,0 +1,172 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+func TestConfig_ResolveTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		err      error
+		expected string // Token, or error when wantErr
+		wantErr  bool
+	}{
+		{name: "trailing newline", output: "ghp_secret\n", expected: "ghp_secret"},
+		{name: "windows newline", output: "ghp_secret\r\n", expected: "ghp_secret"},
+		{name: "no newline", output: "ghp_secret", expected: "ghp_secret"},
+		{name: "multi-line output", output: "ghp_secret\nusername: me\n", expected: "printed several lines", wantErr: true},
+		{name: "empty output", output: "\n", expected: "printed no token", wantErr: true},
+		{name: "command error", err: errors.New("exit status 1: gpg: decryption failed"), expected: "gpg: decryption failed", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{GitHub: provider.Config{Enabled: true, Token: "literal", TokenCmd: "pass show github/daily-pat"}}
+			run := func(ctx context.Context, command string) ([]byte, error) {
+				return []byte(tt.output), tt.err
+			}
+
+			err := config.ResolveTokens(context.Background(), run)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.expected) || !strings.Contains(err.Error(), "github: token command failed") {
+					t.Errorf("Expected github token command error containing '%s', got: %v", tt.expected, err)
+				}
+				if err != nil && strings.Contains(err.Error(), "ghp_secret") {
+					t.Errorf("Expected the error not to contain the token, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if config.GitHub.Token != tt.expected {
+				t.Errorf("Expected token '%s', got '%s'", tt.expected, config.GitHub.Token)
+			}
+		})
+	}
+}
+
+func TestConfig_ResolveTokens_RunsEachCommandOnce(t *testing.T) {
+	config := &Config{
+		GitHub:     provider.Config{Enabled: false, TokenCmd: "pass show github"},
+		JIRA:       provider.Config{Enabled: true, TokenCmd: "pass show atlassian"},
+		Confluence: provider.Config{Enabled: true, TokenCmd: "pass show atlassian"},
+		Obsidian:   provider.Config{Enabled: true, Token: "untouched"},
+	}
+
+	var commands []string
+	run := func(ctx context.Context, command string) ([]byte, error) {
+		commands = append(commands, command)
+		return []byte("atlassian-token\n"), nil
+	}
+
+	if err := config.ResolveTokens(context.Background(), run); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(commands) != 1 || commands[0] != "pass show atlassian" {
+		t.Errorf("Expected the shared command to run once, got %v", commands)
+	}
+	if config.JIRA.Token != "atlassian-token" || config.Confluence.Token != "atlassian-token" {
+		t.Errorf("Expected JIRA and Confluence to share the token, got '%s' and '%s'", config.JIRA.Token, config.Confluence.Token)
+	}
+	if config.GitHub.Token != "" || config.Obsidian.Token != "untouched" {
+		t.Errorf("Expected providers without an enabled token command to be left alone, got '%s' and '%s'", config.GitHub.Token, config.Obsidian.Token)
+	}
+}
+
+func TestConfig_ResolveTokens_Translate(t *testing.T) {
+	config := &Config{Translate: TranslateConfig{Target: "en", Backend: "deepl", Token: "literal", TokenCmd: "pass show deepl"}}
+	run := func(ctx context.Context, command string) ([]byte, error) {
+		return []byte("deepl-key:fx\n"), nil
+	}
+
+	if err := config.ResolveTokens(context.Background(), run); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Translate.Token != "deepl-key:fx" {
+		t.Errorf("Expected the DeepL key from the command, got '%s'", config.Translate.Token)
+	}
+	if saved := config.withLiteralTokens(); saved.Translate.Token != "literal" {
+		t.Errorf("Expected the literal DeepL key to be saved, got '%s'", saved.Translate.Token)
+	}
+
+	// The command isn't run when translations don't use DeepL
+	config = &Config{Translate: TranslateConfig{Target: "en", TokenCmd: "pass show deepl"}}
+	run = func(ctx context.Context, command string) ([]byte, error) {
+		t.Errorf("Expected no command to run, got %q", command)
+		return nil, nil
+	}
+	if err := config.ResolveTokens(context.Background(), run); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+}
+
+func TestConfig_Save_KeepsLiteralTokens(t *testing.T) {
+	// Override the config path for testing
+	originalConfigPathFunc := configPathFunc
+	testConfigPath := filepath.Join(t.TempDir(), "config.json")
+	configPathFunc = func() (string, error) {
+		return testConfigPath, nil
+	}
+	defer func() { configPathFunc = originalConfigPathFunc }()
+
+	config := &Config{GitHub: provider.Config{Enabled: true, Token: "literal", TokenCmd: "pass show github"}}
+	run := func(ctx context.Context, command string) ([]byte, error) {
+		return []byte("ghp_secret\n"), nil
+	}
+	if err := config.ResolveTokens(context.Background(), run); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := config.Save(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(testConfigPath)
+	if err != nil {
+		t.Fatalf("Failed to read saved config: %v", err)
+	}
+	if strings.Contains(string(data), "ghp_secret") || !strings.Contains(string(data), `"token": "literal"`) {
+		t.Errorf("Expected the literal token to be saved instead of the command output, got:\n%s", data)
+	}
+	if config.GitHub.Token != "ghp_secret" {
+		t.Errorf("Expected the loaded config to keep the token, got '%s'", config.GitHub.Token)
+	}
+}
+
+func TestRunShellCommand(t *testing.T) {
+	out, err := runShellCommand(context.Background(), "printf 'token\\n'")
+	if err != nil || string(out) != "token\n" {
+		t.Errorf("Expected 'token\\n', got %q (%v)", out, err)
+	}
+
+	_, err = runShellCommand(context.Background(), "echo 'entry not found' >&2; exit 1")
+	if err == nil || !strings.Contains(err.Error(), "entry not found") {
+		t.Errorf("Expected stderr in the error, got: %v", err)
+	}
+}
+
+func TestLoad_LeavesTokenCommands(t *testing.T) {
+	dir := useConfigDir(t)
+	writeJSONFile(t, filepath.Join(dir, "config.json"), `{"github": {"enabled": true, "token": "literal", "token_cmd": "exit 1"}}`)
+
+	// Commands that don't build providers never run token commands
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.GitHub.Token != "literal" {
+		t.Errorf("Expected the literal token, got '%s'", config.GitHub.Token)
+	}
+
+	if _, err := LoadWithTokens(); err == nil || !strings.Contains(err.Error(), "github: token command failed") {
+		t.Errorf("Expected github token command error, got: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	"daily/internal/translate"
+)
+
+// languageCode matches the language codes titles are translated into, e.g. "en" or "pt-BR"
+var languageCode = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z]{2,4})?$`)
+
+// TranslateConfig holds the settings of the translation of titles in other languages, off
+// unless a target language is set
+type TranslateConfig struct {
+	Target   string `json:"target,omitempty"`    // Language titles are shown in, e.g. "en"
+	Backend  string `json:"backend,omitempty"`   // "dictionary" (default) or "deepl"
+	Token    string `json:"token,omitempty"`     // DeepL API key
+	TokenCmd string `json:"token_cmd,omitempty"` // Command printing the DeepL API key, e.g. "pass show deepl", overriding Token
+	URL      string `json:"url,omitempty"`       // DeepL API endpoint (default from the key, free or pro)
+
+	// Dictionary translates words and phrases with the dictionary backend, e.g. {"corriger": "fix"}
+	Dictionary map[string]string `json:"dictionary,omitempty"`
+	BatchSize  int               `json:"batch_size,omitempty"` // Titles sent per request (default 50)
+}
+
+// Enabled reports whether titles are translated
+func (t TranslateConfig) Enabled() bool {
+	return t.Target != ""
+}
+
+// Validate checks the target language, the backend and its credentials
+func (t TranslateConfig) Validate() error {
+	if t.Target != "" && !languageCode.MatchString(t.Target) {
+		return fmt.Errorf("target: expected a language code, e.g. en or pt-BR, got %q", t.Target)
+	}
+	switch t.Backend {
+	case "", translate.BackendDictionary:
+	case translate.BackendDeepL:
+		if t.Enabled() && t.Token == "" && t.TokenCmd == "" {
+			return fmt.Errorf("token: required by the deepl backend, or token_cmd")
+		}
+	default:
+		return fmt.Errorf("backend: unknown backend %q (must be '%s' or '%s')", t.Backend, translate.BackendDictionary, translate.BackendDeepL)
+	}
+	if t.BatchSize < 0 {
+		return fmt.Errorf("batch_size: must not be negative, got %d", t.BatchSize)
+	}
+	return nil
+}
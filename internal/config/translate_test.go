@@ -0,0 +1,40 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate_Translate(t *testing.T) {
+	tests := []struct {
+		name      string
+		translate TranslateConfig
+		expected  string
+	}{
+		{"disabled", TranslateConfig{}, ""},
+		{"dictionary", TranslateConfig{Target: "en", Dictionary: map[string]string{"corriger": "fix"}}, ""},
+		{"deepl", TranslateConfig{Target: "pt-BR", Backend: "deepl", Token: "abc:fx"}, ""},
+		{"invalid target", TranslateConfig{Target: "english"}, `target: expected a language code`},
+		{"unknown backend", TranslateConfig{Target: "en", Backend: "google"}, `backend: unknown backend "google"`},
+		{"deepl without token", TranslateConfig{Target: "en", Backend: "deepl"}, "token: required by the deepl backend"},
+		{"deepl with token command", TranslateConfig{Target: "en", Backend: "deepl", TokenCmd: "pass show deepl"}, ""},
+		{"negative batch", TranslateConfig{Target: "en", BatchSize: -1}, "batch_size: must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Translate: tt.translate}
+
+			err := config.Validate()
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "translate: "+tt.expected) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"daily/internal/datetime"
+)
+
+// DefaultSumSince is the time range summarized when neither --since nor --date is given
+const DefaultSumSince = "1d"
+
+// SumConfig holds settings for 'daily sum'
+type SumConfig struct {
+	// Since is the default time range, e.g. "1d" or "last-workday" (default 1d)
+	Since string `json:"since,omitempty"`
+	// MaxItems caps the activities listed in text and JSON output, 0 for no limit (default)
+	MaxItems int `json:"max_items,omitempty"`
+}
+
+// DefaultSince returns the default time range, DefaultSumSince when not set
+func (s SumConfig) DefaultSince() string {
+	if s.Since != "" {
+		return s.Since
+	}
+	return DefaultSumSince
+}
+
+// WorkWeekConfig describes the working week, used to find the previous working day
+type WorkWeekConfig struct {
+	Days     []string `json:"days,omitempty"`       // Working weekdays, e.g. ["monday", "tuesday"] (default monday to friday)
+	EndOfDay string   `json:"end_of_day,omitempty"` // Time a working day ends, e.g. "18:00" (default)
+	Holidays []string `json:"holidays,omitempty"`   // Days off (YYYY-MM-DD)
+}
+
+// WorkWeek returns the configured working week, with defaults for the fields not set
+func (w WorkWeekConfig) WorkWeek() (datetime.WorkWeek, error) {
+	week := datetime.DefaultWorkWeek()
+
+	if len(w.Days) > 0 {
+		week.Days = nil
+		for _, name := range w.Days {
+			day, err := ParseWeekday(name)
+			if err != nil {
+				return week, fmt.Errorf("days: %w", err)
+			}
+			week.Days = append(week.Days, day)
+		}
+	}
+
+	if w.EndOfDay != "" {
+		end, err := parseTimeOfDay(w.EndOfDay)
+		if err != nil {
+			return week, fmt.Errorf("end_of_day: %w", err)
+		}
+		week.EndOfDay = end
+	}
+
+	for _, holiday := range w.Holidays {
+		date, err := time.Parse("2006-01-02", holiday)
+		if err != nil {
+			return week, fmt.Errorf("holidays: invalid date %q (expected YYYY-MM-DD)", holiday)
+		}
+		week.Holidays = append(week.Holidays, date)
+	}
+
+	return week, nil
+}
+
+// QuietHoursConfig describes when 'daily notify' holds notifications back, e.g. at night
+type QuietHoursConfig struct {
+	Start    string   `json:"start,omitempty"`    // Time the quiet hours start, e.g. "19:00"
+	End      string   `json:"end,omitempty"`      // Time the quiet hours end, e.g. "08:00" the next day
+	Days     []string `json:"days,omitempty"`     // Weekdays the quiet hours start on (default every day)
+	Timezone string   `json:"timezone,omitempty"` // Time zone of start and end, e.g. "Europe/Paris" (default local)
+	// Platforms are the providers whose changes are held, e.g. ["github"] (default all)
+	Platforms []string `json:"platforms,omitempty"`
+}
+
+// IsSet reports whether quiet hours are configured
+func (q QuietHoursConfig) IsSet() bool {
+	return q.Start != "" || q.End != ""
+}
+
+// QuietHours returns the configured quiet hours
+func (q QuietHoursConfig) QuietHours() (datetime.QuietHours, error) {
+	var quiet datetime.QuietHours
+
+	if q.Start == "" || q.End == "" {
+		return quiet, fmt.Errorf("start and end are both required")
+	}
+	start, err := parseTimeOfDay(q.Start)
+	if err != nil {
+		return quiet, fmt.Errorf("start: %w", err)
+	}
+	end, err := parseTimeOfDay(q.End)
+	if err != nil {
+		return quiet, fmt.Errorf("end: %w", err)
+	}
+	quiet.Start, quiet.End = start, end
+
+	for _, name := range q.Days {
+		day, err := ParseWeekday(name)
+		if err != nil {
+			return quiet, fmt.Errorf("days: %w", err)
+		}
+		quiet.Days = append(quiet.Days, day)
+	}
+
+	if q.Timezone != "" {
+		location, err := time.LoadLocation(q.Timezone)
+		if err != nil {
+			return quiet, fmt.Errorf("timezone: unknown time zone %q", q.Timezone)
+		}
+		quiet.Location = location
+	}
+
+	return quiet, nil
+}
+
+// parseTimeOfDay parses a time such as "18:00" into the duration since midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (e.g. 18:00)", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/datetime"
+)
+
+func TestWorkWeekConfig_WorkWeek(t *testing.T) {
+	week, err := (WorkWeekConfig{}).WorkWeek()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !slices.Equal(week.Days, datetime.DefaultWorkWeek().Days) || week.EndOfDay != 18*time.Hour {
+		t.Errorf("Expected the default week, got %+v", week)
+	}
+
+	week, err = WorkWeekConfig{
+		Days:     []string{"Sunday", "mon", "tuesday"},
+		EndOfDay: "16:45",
+		Holidays: []string{"2024-12-25"},
+	}.WorkWeek()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !slices.Equal(week.Days, []time.Weekday{time.Sunday, time.Monday, time.Tuesday}) {
+		t.Errorf("Expected Sunday to Tuesday, got %v", week.Days)
+	}
+	if week.EndOfDay != 16*time.Hour+45*time.Minute {
+		t.Errorf("Expected the day to end at 16:45, got %v", week.EndOfDay)
+	}
+	if len(week.Holidays) != 1 || week.IsWorkday(time.Date(2024, 12, 25, 10, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected Christmas to be a holiday, got %v", week.Holidays)
+	}
+}
+
+func TestValidate_WorkWeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{name: "defaults"},
+		{name: "last workday", config: Config{Sum: SumConfig{Since: datetime.LastWorkday}}},
+		{name: "unknown day", config: Config{WorkWeek: WorkWeekConfig{Days: []string{"someday"}}}, expected: "work_week: days"},
+		{name: "invalid end of day", config: Config{WorkWeek: WorkWeekConfig{EndOfDay: "6pm"}}, expected: "work_week: end_of_day"},
+		{name: "invalid holiday", config: Config{WorkWeek: WorkWeekConfig{Holidays: []string{"25/12/2024"}}}, expected: "work_week: holidays"},
+		{name: "invalid since", config: Config{Sum: SumConfig{Since: "yesterday"}}, expected: "sum.since"},
+		{name: "max items", config: Config{Sum: SumConfig{MaxItems: 500}}},
+		{name: "negative max items", config: Config{Sum: SumConfig{MaxItems: -1}}, expected: "sum.max_items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.expected, err)
+			}
+		})
+	}
+}
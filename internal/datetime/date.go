@@ -0,0 +1,127 @@
+package datetime
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is the ISO layout of dates, always accepted whatever the locale
+const DateLayout = "2006-01-02"
+
+// DateOrder is the order of the day and the month in slashed dates such as 12/09/2024
+type DateOrder int
+
+const (
+	// DateOrderUnknown only accepts the slashed dates with a single valid reading
+	DateOrderUnknown DateOrder = iota
+	// DayFirst reads slashed dates as DD/MM/YYYY, like most of Europe
+	DayFirst
+	// MonthFirst reads slashed dates as MM/DD/YYYY, like en-US
+	MonthFirst
+)
+
+func (o DateOrder) String() string {
+	switch o {
+	case DayFirst:
+		return "DD/MM/YYYY"
+	case MonthFirst:
+		return "MM/DD/YYYY"
+	default:
+		return "unknown"
+	}
+}
+
+// monthFirstRegions are the regions writing the month before the day
+var monthFirstRegions = []string{"US", "PH", "BZ", "FM", "MH", "PW"}
+
+// LocaleDateOrder returns the order of slashed dates in a locale, e.g. "en-US", "fr_FR" or
+// "de_DE.UTF-8". Locales without region, like "C" or "en", have an unknown order.
+func LocaleDateOrder(locale string) DateOrder {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	_, region, ok := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	if !ok || region == "" {
+		return DateOrderUnknown
+	}
+	if slices.Contains(monthFirstRegions, strings.ToUpper(region)) {
+		return MonthFirst
+	}
+	return DayFirst
+}
+
+// slashedDateRe matches the dates written A/B/YYYY
+var slashedDateRe = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})/(\d{4})$`)
+
+// AmbiguousDateError is returned for a slashed date whose day and month can be swapped when
+// the locale doesn't tell which comes first
+type AmbiguousDateError struct {
+	Value      string
+	Candidates []time.Time // Valid readings, day first then month first
+}
+
+func (e *AmbiguousDateError) Error() string {
+	return fmt.Sprintf("ambiguous date %q, did you mean %s or %s? (set locale in the config, or use YYYY-MM-DD)",
+		e.Value, e.Candidates[0].Format(DateLayout), e.Candidates[1].Format(DateLayout))
+}
+
+// ParseDate parses a date in the ISO layout YYYY-MM-DD, YYYY/MM/DD, or A/B/YYYY read in the
+// given order. Without order, a slashed date is only accepted when a single reading is valid,
+// e.g. 25/12/2024. Dates are returned at midnight UTC.
+func ParseDate(value string, order DateOrder) (time.Time, error) {
+	if t, err := time.Parse(DateLayout, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006/01/02", value); err == nil {
+		return t, nil
+	}
+
+	matches := slashedDateRe.FindStringSubmatch(value)
+	if matches == nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", value)
+	}
+	first, _ := strconv.Atoi(matches[1])
+	second, _ := strconv.Atoi(matches[2])
+	year, _ := strconv.Atoi(matches[3])
+
+	dayFirst, dayFirstOK := date(year, second, first)
+	monthFirst, monthFirstOK := date(year, first, second)
+
+	switch order {
+	case DayFirst:
+		return pickReading(value, order, dayFirst, dayFirstOK, monthFirst, monthFirstOK)
+	case MonthFirst:
+		return pickReading(value, order, monthFirst, monthFirstOK, dayFirst, dayFirstOK)
+	}
+
+	switch {
+	case dayFirstOK && monthFirstOK && !dayFirst.Equal(monthFirst):
+		return time.Time{}, &AmbiguousDateError{Value: value, Candidates: []time.Time{dayFirst, monthFirst}}
+	case dayFirstOK:
+		return dayFirst, nil
+	case monthFirstOK:
+		return monthFirst, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", value)
+}
+
+// pickReading returns the reading of a slashed date in the locale order, suggesting the other
+// reading when only it is valid, e.g. 25/12/2024 read month first
+func pickReading(value string, order DateOrder, t time.Time, ok bool, other time.Time, otherOK bool) (time.Time, error) {
+	if ok {
+		return t, nil
+	}
+	if otherOK {
+		return time.Time{}, fmt.Errorf("invalid date %q for %s, did you mean %s?", value, order, other.Format(DateLayout))
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", value)
+}
+
+// date returns the date of year, month and day, and false when it doesn't exist
+func date(year, month, day int) (time.Time, bool) {
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return t, t.Month() == time.Month(month) && t.Day() == day
+}
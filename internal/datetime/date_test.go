@@ -0,0 +1,98 @@
+package datetime
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocaleDateOrder(t *testing.T) {
+	tests := []struct {
+		locale   string
+		expected DateOrder
+	}{
+		{"en-US", MonthFirst},
+		{"en_US.UTF-8", MonthFirst},
+		{"en_PH", MonthFirst},
+		{"fr-FR", DayFirst},
+		{"de_DE.UTF-8", DayFirst},
+		{"en_GB", DayFirst},
+		{"ca_ES@valencia", DayFirst},
+		{"en", DateOrderUnknown},
+		{"C", DateOrderUnknown},
+		{"C.UTF-8", DateOrderUnknown},
+		{"", DateOrderUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if order := LocaleDateOrder(tt.locale); order != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, order)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		order    DateOrder
+		expected time.Time
+		err      string // Substring of the expected error, empty when the date is valid
+	}{
+		{"ISO", "2024-09-12", DateOrderUnknown, day(2024, 9, 12), ""},
+		{"ISO day first", "2024-09-12", DayFirst, day(2024, 9, 12), ""},
+		{"ISO month first", "2024-09-12", MonthFirst, day(2024, 9, 12), ""},
+		{"ISO with slashes", "2024/09/12", MonthFirst, day(2024, 9, 12), ""},
+		{"day first", "12/09/2024", DayFirst, day(2024, 9, 12), ""},
+		{"month first", "12/09/2024", MonthFirst, day(2024, 12, 9), ""},
+		{"single digits", "1/2/2024", DayFirst, day(2024, 2, 1), ""},
+		{"ambiguous", "12/09/2024", DateOrderUnknown, time.Time{}, "did you mean 2024-09-12 or 2024-12-09?"},
+		{"same either way", "05/05/2024", DateOrderUnknown, day(2024, 5, 5), ""},
+		{"only day first", "25/12/2024", DateOrderUnknown, day(2024, 12, 25), ""},
+		{"only month first", "12/25/2024", DateOrderUnknown, day(2024, 12, 25), ""},
+		{"day first read month first", "25/12/2024", MonthFirst, time.Time{}, `invalid date "25/12/2024" for MM/DD/YYYY, did you mean 2024-12-25?`},
+		{"month first read day first", "12/25/2024", DayFirst, time.Time{}, `invalid date "12/25/2024" for DD/MM/YYYY, did you mean 2024-12-25?`},
+		{"no reading", "31/31/2024", DayFirst, time.Time{}, "expected YYYY-MM-DD"},
+		{"leap day", "29/02/2023", DayFirst, time.Time{}, "expected YYYY-MM-DD"},
+		{"invalid ISO", "2024-13-01", DateOrderUnknown, time.Time{}, "expected YYYY-MM-DD"},
+		{"two-digit year", "12/09/24", DayFirst, time.Time{}, "expected YYYY-MM-DD"},
+		{"not a date", "tomorrow", DayFirst, time.Time{}, "expected YYYY-MM-DD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDate(tt.value, tt.order)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Expected error containing %q, got %v (%s)", tt.err, err, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %s, got %s", tt.expected.Format(DateLayout), result.Format(DateLayout))
+			}
+		})
+	}
+}
+
+func TestParseDate_AmbiguousError(t *testing.T) {
+	_, err := ParseDate("03/04/2024", DateOrderUnknown)
+
+	var ambiguous *AmbiguousDateError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected an AmbiguousDateError, got %v", err)
+	}
+	if len(ambiguous.Candidates) != 2 || ambiguous.Candidates[0].Month() != time.April || ambiguous.Candidates[1].Month() != time.March {
+		t.Errorf("Expected the day first then the month first reading, got %v", ambiguous.Candidates)
+	}
+}
@@ -0,0 +1,74 @@
+package datetime
+
+import (
+	"slices"
+	"time"
+)
+
+// maxQuietDays bounds the quiet hours following each other that Until joins, e.g. a whole
+// weekend made of two full days
+const maxQuietDays = 7
+
+// QuietHours is a daily window of wall-clock time, e.g. 19:00 to 08:00, during which nothing
+// should interrupt the user. An end before the start wraps past midnight, and an end equal to
+// the start covers the whole day.
+type QuietHours struct {
+	Start    time.Duration  // Time of day the quiet hours start, from midnight
+	End      time.Duration  // Time of day the quiet hours end, from midnight
+	Days     []time.Weekday // Days the quiet hours start on, every day when empty
+	Location *time.Location // Location of the times of day, the local time zone when nil
+}
+
+// Until reports whether now is in quiet hours and returns when they end. Quiet hours starting
+// as others end are joined, so that a weekend quiet day by day ends on Monday.
+func (q QuietHours) Until(now time.Time) (time.Time, bool) {
+	end, ok := q.window(now)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	for i := 0; i < maxQuietDays; i++ {
+		next, ok := q.window(end)
+		if !ok {
+			break
+		}
+		end = next
+	}
+	return end, true
+}
+
+// window returns the end of the quiet hours around t, started on the day of t or, when they
+// wrap past midnight, on the day before
+func (q QuietHours) window(t time.Time) (time.Time, bool) {
+	location := q.Location
+	if location == nil {
+		location = time.Local
+	}
+	local := t.In(location)
+	year, month, day := local.Date()
+
+	for offset := 0; offset >= -1; offset-- {
+		// time.Date keeps the time of day across DST changes, the quiet hours follow the clock
+		start := clock(year, month, day+offset, q.Start, location)
+		if len(q.Days) > 0 && !slices.Contains(q.Days, start.Weekday()) {
+			continue
+		}
+
+		endDay := day + offset
+		if q.End <= q.Start {
+			endDay++
+		}
+		end := clock(year, month, endDay, q.End, location)
+
+		if !local.Before(start) && local.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// clock returns the time of day on a date, in location
+func clock(year int, month time.Month, day int, timeOfDay time.Duration, location *time.Location) time.Time {
+	hours, minutes := int(timeOfDay/time.Hour), int(timeOfDay%time.Hour/time.Minute)
+	return time.Date(year, month, day, hours, minutes, 0, 0, location)
+}
@@ -0,0 +1,70 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHours_Until(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	// Week of Monday, March 11, 2024, Paris being UTC+1
+	at := func(d, hour, minute int) time.Time { return time.Date(2024, 3, d, hour, minute, 0, 0, paris) }
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	evenings := QuietHours{Start: 19 * time.Hour, End: 8 * time.Hour, Days: weekdays, Location: paris}
+	weekend := QuietHours{Days: []time.Weekday{time.Saturday, time.Sunday}, Location: paris}
+	lunch := QuietHours{Start: 12 * time.Hour, End: 13*time.Hour + 30*time.Minute, Location: paris}
+	nights := QuietHours{Start: 22 * time.Hour, End: 8 * time.Hour, Location: paris}
+
+	tests := []struct {
+		name     string
+		quiet    QuietHours
+		now      time.Time
+		expected time.Time // Zero outside quiet hours
+	}{
+		{"before the start", evenings, at(11, 18, 59), time.Time{}},
+		{"at the start", evenings, at(11, 19, 0), at(12, 8, 0)},
+		{"after midnight", evenings, at(12, 7, 59), at(12, 8, 0)},
+		{"at the end", evenings, at(12, 8, 0), time.Time{}},
+		{"started on friday", evenings, at(16, 7, 0), at(16, 8, 0)},
+		{"saturday evening", evenings, at(16, 20, 0), time.Time{}},
+		{"sunday evening", evenings, at(17, 23, 0), time.Time{}},
+		{"in another time zone", evenings, time.Date(2024, 3, 11, 18, 30, 0, 0, time.UTC), at(12, 8, 0)},
+		{"whole days joined", weekend, at(16, 10, 0), at(18, 0, 0)},
+		{"whole day ending", weekend, at(17, 23, 59), at(18, 0, 0)},
+		{"whole day on a weekday", weekend, at(15, 12, 0), time.Time{}},
+		{"within a day", lunch, at(13, 13, 0), at(13, 13, 30)},
+		{"after a day window", lunch, at(13, 13, 30), time.Time{}},
+		// Clocks go forward on March 31 at 02:00 and back on October 27 at 03:00
+		{"night shortened by DST", nights, at(30, 23, 0), time.Date(2024, 3, 31, 6, 0, 0, 0, time.UTC)},
+		{"just before the end after DST", nights, time.Date(2024, 3, 31, 5, 59, 0, 0, time.UTC), time.Date(2024, 3, 31, 6, 0, 0, 0, time.UTC)},
+		{"end after DST", nights, time.Date(2024, 3, 31, 6, 0, 0, 0, time.UTC), time.Time{}},
+		{"night lengthened by DST", nights, time.Date(2024, 10, 26, 21, 0, 0, 0, time.UTC), time.Date(2024, 10, 27, 7, 0, 0, 0, time.UTC)},
+		{"repeated hour", nights, time.Date(2024, 10, 27, 1, 30, 0, 0, time.UTC), time.Date(2024, 10, 27, 7, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until, quiet := tt.quiet.Until(tt.now)
+			if quiet != !tt.expected.IsZero() {
+				t.Fatalf("Expected quiet %t, got %t", !tt.expected.IsZero(), quiet)
+			}
+			if !until.Equal(tt.expected) {
+				t.Errorf("Expected quiet hours ending at %v, got %v", tt.expected, until)
+			}
+		})
+	}
+}
+
+func TestQuietHours_Always(t *testing.T) {
+	// Quiet hours covering every day still end, a week later
+	now := time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC)
+	until, quiet := QuietHours{Location: time.UTC}.Until(now)
+	if !quiet || !until.Equal(time.Date(2024, 3, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected quiet hours ending after a week, got %t and %v", quiet, until)
+	}
+}
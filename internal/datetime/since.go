@@ -0,0 +1,42 @@
+package datetime
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// sinceDurationRe is a compiled regex for parsing since duration format (e.g., "1d", "2w")
+var sinceDurationRe = regexp.MustCompile(`^(\d+)([hdwm])$`)
+
+// SinceDuration parses a "since" duration string (e.g., "1d", "2w", "3h", "1m")
+// and returns the "from" time (now - duration)
+func SinceDuration(since string, now time.Time) (time.Time, error) {
+	// Match format: number + unit (h/d/w/m)
+	matches := sinceDurationRe.FindStringSubmatch(since)
+
+	if matches == nil {
+		return time.Time{}, fmt.Errorf("invalid since format: %s (expected format: 1h, 1d, 1w, or 1m)", since)
+	}
+
+	value, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since value: %s", matches[1])
+	}
+
+	unit := matches[2]
+
+	switch unit {
+	case "h":
+		return now.Add(-time.Duration(value) * time.Hour), nil
+	case "d":
+		return now.AddDate(0, 0, -value), nil
+	case "w":
+		return now.AddDate(0, 0, -value*7), nil
+	case "m":
+		return now.AddDate(0, -value, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid since unit: %s (expected h, d, w, or m)", unit)
+	}
+}
@@ -0,0 +1,76 @@
+package datetime
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// LastWorkday is the since keyword resolving to the end of the previous working day
+const LastWorkday = "last-workday"
+
+// maxDaysOff bounds the search for the previous working day
+const maxDaysOff = 366
+
+// WorkWeek describes the working days and when they end
+type WorkWeek struct {
+	Days     []time.Weekday
+	EndOfDay time.Duration // Time of day a working day ends, from midnight
+	Holidays []time.Time   // Days off, only the date matters
+}
+
+// DefaultWorkWeek returns a Monday to Friday week ending at 18:00
+func DefaultWorkWeek() WorkWeek {
+	return WorkWeek{
+		Days:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		EndOfDay: 18 * time.Hour,
+	}
+}
+
+// IsWorkday reports whether the day of t is a working day that isn't a holiday
+func (w WorkWeek) IsWorkday(t time.Time) bool {
+	if !slices.Contains(w.Days, t.Weekday()) {
+		return false
+	}
+
+	year, month, day := t.Date()
+	return !slices.ContainsFunc(w.Holidays, func(holiday time.Time) bool {
+		y, m, d := holiday.Date()
+		return y == year && m == month && d == day
+	})
+}
+
+// PreviousWorkdayEnd returns the end of the last working day before the day of now, in the
+// location of now, e.g. Friday 18:00 on a Monday
+func (w WorkWeek) PreviousWorkdayEnd(now time.Time) (time.Time, error) {
+	year, month, day := now.Date()
+	hours, minutes := int(w.EndOfDay/time.Hour), int(w.EndOfDay%time.Hour/time.Minute)
+
+	for offset := 1; offset <= maxDaysOff; offset++ {
+		// time.Date normalizes the day, and keeps the time of day across DST changes
+		end := time.Date(year, month, day-offset, hours, minutes, 0, 0, now.Location())
+		if w.IsWorkday(end) {
+			return end, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no working day in the last %d days", maxDaysOff)
+}
+
+// ResolveSince returns the start of the window described by since: the end of the previous
+// working day for LastWorkday, or a duration back from now (e.g., "1d", "2w")
+func ResolveSince(since string, now time.Time, week WorkWeek) (time.Time, error) {
+	if since == LastWorkday {
+		return week.PreviousWorkdayEnd(now)
+	}
+	return SinceDuration(since, now)
+}
+
+// DescribeWindow describes the window from a time until now, e.g. "covering Fri 18:00 → now".
+// Windows of a week or more show the date instead of the weekday.
+func DescribeWindow(from, now time.Time) string {
+	layout := "Mon 15:04"
+	if now.Sub(from) >= 6*24*time.Hour {
+		layout = "Jan 2 15:04"
+	}
+	return fmt.Sprintf("covering %s → now", from.Format(layout))
+}
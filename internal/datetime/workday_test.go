@@ -0,0 +1,108 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkWeek_PreviousWorkdayEnd(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	// Week of Monday, March 11, 2024
+	day := func(d, hour int) time.Time { return time.Date(2024, 3, d, hour, 0, 0, 0, time.UTC) }
+
+	standard := DefaultWorkWeek()
+	withHolidays := DefaultWorkWeek()
+	withHolidays.Holidays = []time.Time{day(8, 0), day(11, 0)}
+	sundayToThursday := WorkWeek{
+		Days:     []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday},
+		EndOfDay: 17*time.Hour + 30*time.Minute,
+	}
+
+	tests := []struct {
+		name     string
+		week     WorkWeek
+		now      time.Time
+		expected time.Time
+	}{
+		{"monday", standard, day(11, 9), day(8, 18)},
+		{"tuesday", standard, day(12, 9), day(11, 18)},
+		{"wednesday", standard, day(13, 9), day(12, 18)},
+		{"thursday", standard, day(14, 9), day(13, 18)},
+		{"friday", standard, day(15, 9), day(14, 18)},
+		{"saturday", standard, day(16, 9), day(15, 18)},
+		{"sunday", standard, day(17, 9), day(15, 18)},
+		{"friday evening", standard, day(15, 20), day(14, 18)},
+		{"holiday on the previous day", withHolidays, day(12, 9), day(7, 18)},
+		{"holiday today", withHolidays, day(11, 9), day(7, 18)},
+		{"custom week on sunday", sundayToThursday, day(17, 9), day(14, 17).Add(30 * time.Minute)},
+		{"custom week on monday", sundayToThursday, day(18, 9), day(17, 17).Add(30 * time.Minute)},
+		{"custom week on saturday", sundayToThursday, day(16, 9), day(14, 17).Add(30 * time.Minute)},
+		{"location of now across DST", standard, time.Date(2024, 4, 1, 9, 0, 0, 0, paris), time.Date(2024, 3, 29, 18, 0, 0, 0, paris)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, err := tt.week.PreviousWorkdayEnd(tt.now)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !end.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, end)
+			}
+		})
+	}
+}
+
+func TestWorkWeek_PreviousWorkdayEnd_NoWorkdays(t *testing.T) {
+	if _, err := (WorkWeek{}).PreviousWorkdayEnd(time.Now()); err == nil {
+		t.Error("Expected error for a week without working days, got nil")
+	}
+}
+
+func TestResolveSince(t *testing.T) {
+	now := time.Date(2024, 3, 11, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		since       string
+		expected    time.Time
+		expectError bool
+	}{
+		{since: LastWorkday, expected: time.Date(2024, 3, 8, 18, 0, 0, 0, time.UTC)},
+		{since: "1d", expected: time.Date(2024, 3, 10, 9, 30, 0, 0, time.UTC)},
+		{since: "3h", expected: time.Date(2024, 3, 11, 6, 30, 0, 0, time.UTC)},
+		{since: "last-week", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.since, func(t *testing.T) {
+			from, err := ResolveSince(tt.since, now, DefaultWorkWeek())
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got %v", from)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !from.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, from)
+			}
+		})
+	}
+}
+
+func TestDescribeWindow(t *testing.T) {
+	now := time.Date(2024, 3, 11, 9, 30, 0, 0, time.UTC)
+
+	if got := DescribeWindow(time.Date(2024, 3, 8, 18, 0, 0, 0, time.UTC), now); got != "covering Fri 18:00 → now" {
+		t.Errorf("Expected the weekday of a recent start, got %q", got)
+	}
+	if got := DescribeWindow(now.AddDate(0, 0, -14), now); got != "covering Feb 26 09:30 → now" {
+		t.Errorf("Expected the date of an older start, got %q", got)
+	}
+}
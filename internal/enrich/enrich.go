@@ -0,0 +1,36 @@
+// Package enrich adds details to review items with the per-item requests of their source,
+// staying under the rate limits of the platforms.
+package enrich
+
+import (
+	"context"
+	"time"
+
+	"daily/internal/concurrency"
+	"daily/internal/provider"
+)
+
+// DefaultOptions limits enrichment to 5 concurrent requests, 1 request every 200ms
+var DefaultOptions = concurrency.Options{Workers: 5, Delay: 200 * time.Millisecond}
+
+// Reviews enriches the items concurrently and returns them in input order. Items that
+// can't be enriched are returned unchanged, with their error at the same index in errs.
+func Reviews(ctx context.Context, enricher provider.ReviewEnricher, items []provider.ReviewItem, opts concurrency.Options) (enriched []provider.ReviewItem, errs []error) {
+	results := concurrency.Map(ctx, items, opts, func(ctx context.Context, _ int, item provider.ReviewItem) (provider.ReviewItem, error) {
+		return enricher.EnrichReview(ctx, item)
+	})
+
+	enriched = make([]provider.ReviewItem, len(items))
+	errs = make([]error, len(items))
+	for i, result := range results {
+		if result.Err != nil {
+			// Fall back to the item without details
+			enriched[i] = items[i]
+			errs[i] = result.Err
+			continue
+		}
+		enriched[i] = result.Value
+	}
+
+	return enriched, errs
+}
@@ -0,0 +1,83 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"daily/internal/concurrency"
+	"daily/internal/provider"
+)
+
+// fakeEnricher sets the base branch of items and fails for the ones listed in failures
+type fakeEnricher struct {
+	failures map[string]bool
+}
+
+func (f fakeEnricher) EnrichReview(ctx context.Context, item provider.ReviewItem) (provider.ReviewItem, error) {
+	item.Base = "main"
+	if f.failures[item.ID] {
+		return item, errors.New("not found")
+	}
+	return item, nil
+}
+
+func testItems(count int) []provider.ReviewItem {
+	items := make([]provider.ReviewItem, count)
+	for i := range items {
+		items[i] = provider.ReviewItem{TodoItem: provider.TodoItem{ID: fmt.Sprintf("pr-%d", i+1)}}
+	}
+	return items
+}
+
+func TestReviews(t *testing.T) {
+	items := testItems(4)
+	enricher := fakeEnricher{failures: map[string]bool{"pr-2": true}}
+
+	enriched, errs := Reviews(context.Background(), enricher, items, concurrency.Options{Workers: 2})
+
+	if len(enriched) != len(items) || len(errs) != len(items) {
+		t.Fatalf("Expected %d items and errors, got %d and %d", len(items), len(enriched), len(errs))
+	}
+	for i, item := range enriched {
+		if item.ID != items[i].ID {
+			t.Errorf("Item %d: expected ID %s, got %s", i, items[i].ID, item.ID)
+		}
+	}
+
+	if enriched[0].Base != "main" || errs[0] != nil {
+		t.Errorf("Expected pr-1 to be enriched, got %+v (error %v)", enriched[0], errs[0])
+	}
+	// Failed items are returned as they were, even when partially enriched
+	if enriched[1].Base != "" || errs[1] == nil {
+		t.Errorf("Expected pr-2 to be returned unchanged with an error, got %+v (error %v)", enriched[1], errs[1])
+	}
+}
+
+func TestReviews_RateLimiting(t *testing.T) {
+	start := time.Now()
+	Reviews(context.Background(), fakeEnricher{}, testItems(3), concurrency.Options{Workers: 5, Delay: 50 * time.Millisecond})
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected rate limiting to cause a delay of at least 100ms, got %v", elapsed)
+	}
+}
+
+func TestReviews_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	items := testItems(3)
+	enriched, errs := Reviews(ctx, fakeEnricher{}, items, DefaultOptions)
+
+	for i := range items {
+		if enriched[i].ID != items[i].ID {
+			t.Errorf("Item %d: expected the original item to be kept, got %+v", i, enriched[i])
+		}
+		if !errors.Is(errs[i], context.Canceled) {
+			t.Errorf("Item %d: expected context.Canceled, got %v", i, errs[i])
+		}
+	}
+}
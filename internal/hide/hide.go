@@ -0,0 +1,276 @@
+package hide
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Item is what a todo item or review request is matched on
+type Item struct {
+	ID    string
+	Key   string // Issue key of JIRA tickets
+	URL   string
+	Title string
+	Tags  []string
+}
+
+// Rule hides the current and future items whose title or tags contain its pattern
+type Rule struct {
+	Pattern   string    `json:"pattern"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Matches reports whether the title or a tag of the item contains the pattern, ignoring case
+func (r Rule) Matches(item Item) bool {
+	pattern := strings.ToLower(r.Pattern)
+	if strings.Contains(strings.ToLower(item.Title), pattern) {
+		return true
+	}
+	return slices.ContainsFunc(item.Tags, func(tag string) bool {
+		return strings.Contains(strings.ToLower(tag), pattern)
+	})
+}
+
+// Entry is an item hidden by ID, JIRA key or URL
+type Entry struct {
+	Ref           string    `json:"ref"`
+	HiddenAt      time.Time `json:"hidden_at"`
+	LastMatched   string    `json:"last_matched,omitempty"`   // Title of the item last hidden by the entry
+	LastMatchedAt time.Time `json:"last_matched_at,omitzero"` // Day the entry last hid an item
+}
+
+// UnmarshalJSON reads an entry, or the bare reference of the files written before entries
+// had timestamps. Those entries have no HiddenAt until Store.Load sets it.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	var ref string
+	if err := json.Unmarshal(data, &ref); err == nil {
+		*e = Entry{Ref: ref}
+		return nil
+	}
+	type entry Entry // Without the UnmarshalJSON method
+	return json.Unmarshal(data, (*entry)(e))
+}
+
+// LastActive returns when the entry was added or last hid an item, whichever is later
+func (e Entry) LastActive() time.Time {
+	if e.LastMatchedAt.After(e.HiddenAt) {
+		return e.LastMatchedAt
+	}
+	return e.HiddenAt
+}
+
+// State is what is hidden from the todo and reviews lists. Items are referenced by ID,
+// JIRA key or URL, ignoring case.
+type State struct {
+	IDs        []Entry  `json:"ids,omitempty"`        // Items hidden one by one
+	Rules      []Rule   `json:"rules,omitempty"`      // Patterns hiding every matching item
+	Exceptions []string `json:"exceptions,omitempty"` // Items shown although a rule matches them
+}
+
+// refers reports whether ref is the ID, key or URL of the item
+func refers(ref string, item Item) bool {
+	return strings.EqualFold(ref, item.ID) ||
+		(item.Key != "" && strings.EqualFold(ref, item.Key)) ||
+		(item.URL != "" && ref == item.URL)
+}
+
+// Hidden reports whether the item is hidden: by ID, or by a rule unless it is an exception
+func (s State) Hidden(item Item) bool {
+	if slices.ContainsFunc(s.IDs, func(e Entry) bool { return refers(e.Ref, item) }) {
+		return true
+	}
+	if slices.ContainsFunc(s.Exceptions, func(ref string) bool { return refers(ref, item) }) {
+		return false
+	}
+	return s.Rule(item) != nil
+}
+
+// Rule returns the first rule matching the item, nil when none does
+func (s State) Rule(item Item) *Rule {
+	for i := range s.Rules {
+		if s.Rules[i].Matches(item) {
+			return &s.Rules[i]
+		}
+	}
+	return nil
+}
+
+// HideID hides an item by ID, key or URL, dropping its exception if any. It returns false
+// when the item was already hidden by ID.
+func (s *State) HideID(ref string, now time.Time) bool {
+	ref = strings.TrimSpace(ref)
+	s.Exceptions = slices.DeleteFunc(s.Exceptions, func(e string) bool { return strings.EqualFold(e, ref) })
+	if slices.ContainsFunc(s.IDs, func(e Entry) bool { return strings.EqualFold(e.Ref, ref) }) {
+		return false
+	}
+	s.IDs = append(s.IDs, Entry{Ref: ref, HiddenAt: now})
+	return true
+}
+
+// RecordMatch records the item on the entries hiding it by ID, so that they stay active while
+// the item is listed. It returns false when nothing changed: entries are updated at most once
+// a day unless the title of the item changed.
+func (s *State) RecordMatch(item Item, now time.Time) bool {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	changed := false
+	for i := range s.IDs {
+		entry := &s.IDs[i]
+		if !refers(entry.Ref, item) || (entry.LastMatched == item.Title && !entry.LastMatchedAt.Before(today)) {
+			continue
+		}
+		entry.LastMatched = item.Title
+		entry.LastMatchedAt = today
+		changed = true
+	}
+	return changed
+}
+
+// Prune removes the entries that neither were added nor hid an item since before, so that
+// items resolved long ago are forgotten and show again if they come back. It returns the
+// removed entries.
+func (s *State) Prune(before time.Time) []Entry {
+	var expired []Entry
+	s.IDs = slices.DeleteFunc(s.IDs, func(e Entry) bool {
+		if e.LastActive().Before(before) {
+			expired = append(expired, e)
+			return true
+		}
+		return false
+	})
+	return expired
+}
+
+// AddRule adds a rule hiding the items matching pattern. It returns false when the rule
+// already exists.
+func (s *State) AddRule(pattern string, now time.Time) (bool, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return false, fmt.Errorf("pattern cannot be empty")
+	}
+	if slices.ContainsFunc(s.Rules, func(r Rule) bool { return strings.EqualFold(r.Pattern, pattern) }) {
+		return false, nil
+	}
+	s.Rules = append(s.Rules, Rule{Pattern: pattern, CreatedAt: now})
+	return true, nil
+}
+
+// RemoveRule removes the rule of pattern. It returns false when there is no such rule.
+func (s *State) RemoveRule(pattern string) bool {
+	count := len(s.Rules)
+	s.Rules = slices.DeleteFunc(s.Rules, func(r Rule) bool { return strings.EqualFold(r.Pattern, strings.TrimSpace(pattern)) })
+	return len(s.Rules) < count
+}
+
+// Unhide shows an item hidden by ID or by a rule. When a rule still matches the item, its
+// ID is recorded as an exception so the rule doesn't hide it again. It returns false when
+// the item wasn't hidden.
+func (s *State) Unhide(item Item) bool {
+	count := len(s.IDs)
+	s.IDs = slices.DeleteFunc(s.IDs, func(e Entry) bool { return refers(e.Ref, item) })
+	unhidden := len(s.IDs) < count
+
+	if s.Rule(item) != nil && !slices.ContainsFunc(s.Exceptions, func(ref string) bool { return refers(ref, item) }) {
+		s.Exceptions = append(s.Exceptions, item.ID)
+		unhidden = true
+	}
+	return unhidden
+}
+
+// Merge adds the entries, rules and exceptions of other that the state doesn't have yet,
+// comparing references and patterns ignoring case. Entries already there are kept as is.
+func (s *State) Merge(other State) {
+	for _, entry := range other.IDs {
+		if !slices.ContainsFunc(s.IDs, func(e Entry) bool { return strings.EqualFold(e.Ref, entry.Ref) }) {
+			s.IDs = append(s.IDs, entry)
+		}
+	}
+	for _, rule := range other.Rules {
+		if !slices.ContainsFunc(s.Rules, func(r Rule) bool { return strings.EqualFold(r.Pattern, rule.Pattern) }) {
+			s.Rules = append(s.Rules, rule)
+		}
+	}
+	for _, ref := range other.Exceptions {
+		if !slices.ContainsFunc(s.Exceptions, func(e string) bool { return strings.EqualFold(e, ref) }) {
+			s.Exceptions = append(s.Exceptions, ref)
+		}
+	}
+}
+
+// Empty reports whether nothing is hidden
+func (s State) Empty() bool {
+	return len(s.IDs) == 0 && len(s.Rules) == 0 && len(s.Exceptions) == 0
+}
+
+// Store persists the state in a JSON file
+type Store struct {
+	path string
+}
+
+// NewStore returns a store writing to path
+func NewStore(path string) Store {
+	return Store{path: path}
+}
+
+// DefaultStatePath returns the file next to the configuration holding the hidden items.
+// It isn't in the cache directory as it can't be rebuilt.
+func DefaultStatePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "daily", "hidden.json"), nil
+}
+
+// Load returns the stored state, or an empty state when nothing was hidden yet. The entries
+// of files written before entries had timestamps are dated now, and the file is rewritten.
+func (s Store) Load() (State, error) {
+	var state State
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read hidden items: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse hidden items: %w", err)
+	}
+
+	migrated := false
+	for i := range state.IDs {
+		if state.IDs[i].HiddenAt.IsZero() {
+			state.IDs[i].HiddenAt = time.Now()
+			migrated = true
+		}
+	}
+	if migrated {
+		if err := s.Save(state); err != nil {
+			return state, err
+		}
+	}
+	return state, nil
+}
+
+// Save writes the state, creating its directory if needed
+func (s Store) Save(state State) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal hidden items: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write hidden items: %w", err)
+	}
+	return nil
+}
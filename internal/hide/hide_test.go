@@ -0,0 +1,220 @@
+package hide
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRule_Matches(t *testing.T) {
+	rule := Rule{Pattern: "Dependabot"}
+	tests := []struct {
+		name     string
+		item     Item
+		expected bool
+	}{
+		{name: "title", item: Item{Title: "Bump lodash from 4.17.20 (dependabot)"}, expected: true},
+		{name: "tag", item: Item{Title: "Bump lodash", Tags: []string{"api", "dependabot[bot]"}}, expected: true},
+		{name: "neither", item: Item{ID: "dependabot-1", Title: "Fix login", Tags: []string{"api"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rule.Matches(tt.item); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestState_Hidden(t *testing.T) {
+	state := State{
+		IDs:        []Entry{{Ref: "PROJ-1"}, {Ref: "https://github.com/acme/api/pull/7"}},
+		Rules:      []Rule{{Pattern: "dependabot"}},
+		Exceptions: []string{"github-pr-9"},
+	}
+
+	tests := []struct {
+		name     string
+		item     Item
+		expected bool
+	}{
+		{name: "by key", item: Item{ID: "jira-PROJ-1", Key: "proj-1"}, expected: true},
+		{name: "by URL", item: Item{ID: "github-review-7", URL: "https://github.com/acme/api/pull/7"}, expected: true},
+		{name: "by rule", item: Item{ID: "github-pr-8", Title: "Bump lodash", Tags: []string{"dependabot"}}, expected: true},
+		{name: "exception to a rule", item: Item{ID: "github-pr-9", Title: "Bump lodash", Tags: []string{"dependabot"}}},
+		{name: "not hidden", item: Item{ID: "github-pr-10", Title: "Fix login"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := state.Hidden(tt.item); got != tt.expected {
+				t.Errorf("Expected hidden %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestState_Unhide(t *testing.T) {
+	now := time.Date(2024, 5, 30, 9, 0, 0, 0, time.UTC)
+	bump := Item{ID: "github-pr-8", Title: "Bump lodash", Tags: []string{"dependabot"}}
+	fix := Item{ID: "github-pr-10", Title: "Fix login"}
+
+	var state State
+	if added, err := state.AddRule("dependabot", now); err != nil || !added {
+		t.Fatalf("Expected the rule to be added, got %v, %v", added, err)
+	}
+	if added, _ := state.AddRule("Dependabot", now); added {
+		t.Error("Expected the same pattern not to be added twice")
+	}
+	if _, err := state.AddRule("  ", now); err == nil {
+		t.Error("Expected an error for an empty pattern")
+	}
+	state.HideID("github-pr-10", now)
+
+	// Unhiding an item hidden by a rule makes it an exception
+	if !state.Unhide(bump) {
+		t.Fatal("Expected the item hidden by a rule to be unhidden")
+	}
+	if state.Hidden(bump) {
+		t.Error("Expected the exception to show the item")
+	}
+	if len(state.Exceptions) != 1 || state.Exceptions[0] != "github-pr-8" {
+		t.Errorf("Expected an exception for github-pr-8, got %v", state.Exceptions)
+	}
+	if state.Unhide(bump) {
+		t.Error("Expected an item already shown not to be unhidden again")
+	}
+
+	// Unhiding an item hidden by ID needs no exception
+	if !state.Unhide(fix) || state.Hidden(fix) {
+		t.Error("Expected the item hidden by ID to be shown")
+	}
+	if len(state.Exceptions) != 1 {
+		t.Errorf("Expected no new exception, got %v", state.Exceptions)
+	}
+
+	// Hiding the exception by ID again drops it
+	state.HideID("GITHUB-PR-8", now)
+	if !state.Hidden(bump) || len(state.Exceptions) != 0 {
+		t.Errorf("Expected the item to be hidden without exception, got %+v", state)
+	}
+
+	if !state.RemoveRule("DEPENDABOT") || len(state.Rules) != 0 {
+		t.Errorf("Expected the rule to be removed, got %v", state.Rules)
+	}
+	if state.RemoveRule("dependabot") {
+		t.Error("Expected no rule to remove")
+	}
+}
+
+func TestStore_LoadSave(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "daily", "hidden.json"))
+
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("Expected no error for a missing file, got: %v", err)
+	}
+	if !state.Empty() {
+		t.Errorf("Expected an empty state, got %+v", state)
+	}
+
+	createdAt := time.Date(2024, 5, 30, 9, 0, 0, 0, time.UTC)
+	state = State{IDs: []Entry{{Ref: "jira-PROJ-1", HiddenAt: createdAt}}, Rules: []Rule{{Pattern: "dependabot", CreatedAt: createdAt}}, Exceptions: []string{"github-pr-9"}}
+	if err := store.Save(state); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(loaded.IDs) != 1 || len(loaded.Exceptions) != 1 || len(loaded.Rules) != 1 {
+		t.Fatalf("Expected the saved state, got %+v", loaded)
+	}
+	if loaded.Rules[0].Pattern != "dependabot" || !loaded.Rules[0].CreatedAt.Equal(createdAt) {
+		t.Errorf("Expected the saved rule, got %+v", loaded.Rules[0])
+	}
+	if loaded.IDs[0].Ref != "jira-PROJ-1" || !loaded.IDs[0].HiddenAt.Equal(createdAt) {
+		t.Errorf("Expected the saved entry, got %+v", loaded.IDs[0])
+	}
+}
+
+func TestStore_MigratesFlatIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hidden.json")
+	if err := os.WriteFile(path, []byte(`{"ids": ["jira-PROJ-1", "https://github.com/acme/api/pull/7"], "exceptions": ["github-pr-9"]}`), 0644); err != nil {
+		t.Fatalf("Failed to write hidden items: %v", err)
+	}
+	store := NewStore(path)
+
+	before := time.Now()
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(state.IDs) != 2 || state.IDs[1].Ref != "https://github.com/acme/api/pull/7" {
+		t.Fatalf("Expected the flat IDs as entries, got %+v", state.IDs)
+	}
+	if state.IDs[0].HiddenAt.Before(before) {
+		t.Errorf("Expected the migrated entries to be dated now, got %v", state.IDs[0].HiddenAt)
+	}
+	if !state.Hidden(Item{ID: "jira-PROJ-1"}) || len(state.Exceptions) != 1 {
+		t.Errorf("Expected the migrated state to hide the same items, got %+v", state)
+	}
+
+	// The file is rewritten, so that the migrated entries keep their date
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"hidden_at"`) {
+		t.Errorf("Expected the file to be migrated, got: %s", data)
+	}
+	reloaded, _ := store.Load()
+	if !reloaded.IDs[0].HiddenAt.Equal(state.IDs[0].HiddenAt) {
+		t.Errorf("Expected the migration date to be kept, got %v and %v", reloaded.IDs[0].HiddenAt, state.IDs[0].HiddenAt)
+	}
+}
+
+func TestState_RecordMatchAndPrune(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 9, 0, 0, 0, time.UTC) }
+	listed := Item{ID: "github-pr-1", URL: "https://github.com/acme/api/pull/1", Title: "Fix login"}
+
+	var state State
+	state.HideID("https://github.com/acme/api/pull/1", day(1))
+	state.HideID("jira-PROJ-2", day(1))
+	state.HideID("jira-PROJ-3", day(20))
+
+	if !state.RecordMatch(listed, day(10)) {
+		t.Fatal("Expected the first match to be recorded")
+	}
+	if state.RecordMatch(listed, day(10).Add(3*time.Hour)) {
+		t.Error("Expected a second match on the same day not to change the entry")
+	}
+	if !state.RecordMatch(Item{ID: "github-pr-1", URL: listed.URL, Title: "Fix login on Safari"}, day(10)) {
+		t.Error("Expected a new title to be recorded")
+	}
+	if state.IDs[0].LastMatched != "Fix login on Safari" || !state.IDs[0].LastMatchedAt.Equal(time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the title and day of the match, got %+v", state.IDs[0])
+	}
+	if state.IDs[1].LastMatched != "" {
+		t.Errorf("Expected other entries to be left alone, got %+v", state.IDs[1])
+	}
+
+	// PROJ-2 was hidden on the 1st and never listed since
+	expired := state.Prune(day(5))
+	if len(expired) != 1 || expired[0].Ref != "jira-PROJ-2" {
+		t.Errorf("Expected jira-PROJ-2 to expire, got %+v", expired)
+	}
+	if len(state.IDs) != 2 || state.Hidden(Item{ID: "jira-PROJ-2"}) {
+		t.Errorf("Expected the expired item to show again, got %+v", state.IDs)
+	}
+
+	// A PR reopened after its entry expired is listed again
+	state.Prune(day(15))
+	if state.Hidden(listed) {
+		t.Error("Expected the PR not listed since the 10th to show again")
+	}
+	if !state.Hidden(Item{ID: "jira-PROJ-3"}) {
+		t.Error("Expected the recent entry to be kept")
+	}
+}
@@ -0,0 +1,184 @@
+// Package metrics counts what a command run costs: the requests made to each provider, the
+// bytes downloaded, the remaining rate limit and the effectiveness of the caches.
+package metrics
+
+import (
+	"context"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ProviderUsage is the traffic to a provider
+type ProviderUsage struct {
+	Requests int   `json:"requests"`
+	Bytes    int64 `json:"bytes"`
+	// RateLimitRemaining is taken from the X-RateLimit-Remaining header of the last response, when sent
+	RateLimitRemaining *int `json:"rate_limit_remaining,omitempty"`
+}
+
+// CacheUsage counts the lookups of a cache
+type CacheUsage struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+}
+
+// Report is a snapshot of the usage of a run
+type Report struct {
+	Providers  map[string]ProviderUsage `json:"providers"`
+	Caches     map[string]CacheUsage    `json:"caches,omitempty"`
+	WallTimeMS int64                    `json:"wall_time_ms"`
+}
+
+// Requests returns the number of requests made to all providers
+func (r Report) Requests() int {
+	total := 0
+	for _, usage := range r.Providers {
+		total += usage.Requests
+	}
+	return total
+}
+
+// Bytes returns the number of bytes downloaded from all providers
+func (r Report) Bytes() int64 {
+	var total int64
+	for _, usage := range r.Providers {
+		total += usage.Bytes
+	}
+	return total
+}
+
+// CacheTotals returns the hits and misses of all caches
+func (r Report) CacheTotals() CacheUsage {
+	var total CacheUsage
+	for _, usage := range r.Caches {
+		total.Hits += usage.Hits
+		total.Misses += usage.Misses
+	}
+	return total
+}
+
+// Recorder aggregates the usage of a command run. It is safe for concurrent use, and
+// its methods do nothing on a nil Recorder.
+type Recorder struct {
+	mu        sync.Mutex
+	start     time.Time
+	now       func() time.Time
+	providers map[string]*ProviderUsage
+	caches    map[string]*CacheUsage
+	started   map[string]time.Time // Start of the providers being queried
+	progress  io.Writer            // Where progress events are written, none when nil
+}
+
+// NewRecorder creates a recorder, measuring the wall time from now
+func NewRecorder() *Recorder {
+	return &Recorder{
+		start:     time.Now(),
+		now:       time.Now,
+		providers: make(map[string]*ProviderUsage),
+		caches:    make(map[string]*CacheUsage),
+		started:   make(map[string]time.Time),
+	}
+}
+
+func (r *Recorder) provider(name string) *ProviderUsage {
+	usage, ok := r.providers[name]
+	if !ok {
+		usage = &ProviderUsage{}
+		r.providers[name] = usage
+	}
+	return usage
+}
+
+// Request counts a request made to a provider
+func (r *Recorder) Request(provider string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.provider(provider).Requests++
+}
+
+// Downloaded counts bytes received from a provider
+func (r *Recorder) Downloaded(provider string, n int64) {
+	if r == nil || n == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.provider(provider).Bytes += n
+}
+
+// RateLimit records the remaining rate limit of a provider
+func (r *Recorder) RateLimit(provider string, remaining int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.provider(provider).RateLimitRemaining = &remaining
+}
+
+// Cache counts a lookup of a cache
+func (r *Recorder) Cache(name string, hit bool) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage, ok := r.caches[name]
+	if !ok {
+		usage = &CacheUsage{}
+		r.caches[name] = usage
+	}
+	if hit {
+		usage.Hits++
+	} else {
+		usage.Misses++
+	}
+}
+
+// Report returns the usage recorded so far, nil for a nil Recorder
+func (r *Recorder) Report() *Report {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &Report{
+		Providers:  make(map[string]ProviderUsage, len(r.providers)),
+		WallTimeMS: r.now().Sub(r.start).Milliseconds(),
+	}
+	for name, usage := range r.providers {
+		report.Providers[name] = *usage
+	}
+	if len(r.caches) > 0 {
+		report.Caches = make(map[string]CacheUsage, len(r.caches))
+		for name, usage := range r.caches {
+			report.Caches[name] = *usage
+		}
+	}
+	return report
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a context carrying the recorder of the run
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// FromContext returns the recorder of the run, nil when the context has none
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// ProviderNames returns the names of the providers of the report, sorted
+func (r Report) ProviderNames() []string {
+	return slices.Sorted(maps.Keys(r.Providers))
+}
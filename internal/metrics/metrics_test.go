@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"daily/internal/concurrency"
+	"daily/internal/enrich"
+	"daily/internal/provider"
+)
+
+// fakeTransport answers every request with body, counting down the rate limit when set
+type fakeTransport struct {
+	body      string
+	rateLimit atomic.Int64
+}
+
+func (f *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	header := http.Header{}
+	if f.rateLimit.Load() > 0 {
+		header.Set("X-RateLimit-Remaining", fmt.Sprint(f.rateLimit.Add(-1)))
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(f.body)),
+		Request:    req,
+	}, nil
+}
+
+// fetchingEnricher makes requests per item with client, like the review enrichers of the providers
+type fetchingEnricher struct {
+	client          *http.Client
+	requestsPerItem int
+}
+
+func (e fetchingEnricher) EnrichReview(ctx context.Context, item provider.ReviewItem) (provider.ReviewItem, error) {
+	for i := 0; i < e.requestsPerItem; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com/"+item.ID, nil)
+		if err != nil {
+			return item, err
+		}
+		resp, err := e.client.Do(req)
+		if err != nil {
+			return item, err
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+	return item, nil
+}
+
+func TestTransport_ConcurrentEnrichment(t *testing.T) {
+	github := &fakeTransport{body: strings.Repeat("x", 100)}
+	github.rateLimit.Store(5000)
+	jira := &fakeTransport{body: strings.Repeat("y", 10)}
+
+	recorder := NewRecorder()
+	ctx := WithRecorder(context.Background(), recorder)
+
+	items := make([]provider.ReviewItem, 20)
+	for i := range items {
+		items[i] = provider.ReviewItem{TodoItem: provider.TodoItem{ID: fmt.Sprintf("pr-%d", i+1)}}
+	}
+	opts := concurrency.Options{Workers: 5}
+
+	enrich.Reviews(ctx, fetchingEnricher{client: &http.Client{Transport: &Transport{Provider: "github", Base: github}}, requestsPerItem: 2}, items, opts)
+	enrich.Reviews(ctx, fetchingEnricher{client: &http.Client{Transport: &Transport{Provider: "jira", Base: jira}}, requestsPerItem: 1}, items, opts)
+
+	report := recorder.Report()
+	if usage := report.Providers["github"]; usage.Requests != 40 || usage.Bytes != 4000 {
+		t.Errorf("Expected 40 GitHub requests and 4000 bytes, got %+v", usage)
+	}
+	if usage := report.Providers["github"]; usage.RateLimitRemaining == nil || *usage.RateLimitRemaining < 4960 {
+		t.Errorf("Expected the remaining GitHub rate limit, got %v", usage.RateLimitRemaining)
+	}
+	if usage := report.Providers["jira"]; usage.Requests != 20 || usage.Bytes != 200 || usage.RateLimitRemaining != nil {
+		t.Errorf("Expected 20 JIRA requests, 200 bytes and no rate limit, got %+v", usage)
+	}
+	if report.Requests() != 60 || report.Bytes() != 4200 {
+		t.Errorf("Expected 60 requests and 4200 bytes in total, got %d and %d", report.Requests(), report.Bytes())
+	}
+}
+
+func TestTransport_WithoutRecorder(t *testing.T) {
+	client := &http.Client{Transport: &Transport{Provider: "github", Base: &fakeTransport{body: "ok"}}}
+
+	resp, err := client.Get("https://example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("Expected the response to be passed through, got %q", body)
+	}
+}
+
+func TestRecorder_Report(t *testing.T) {
+	recorder := NewRecorder()
+	start := recorder.start
+	recorder.now = func() time.Time { return start.Add(1500 * time.Millisecond) }
+
+	recorder.Cache("summary", false)
+	recorder.Cache("github_teams", true)
+	recorder.Cache("github_teams", true)
+
+	report := recorder.Report()
+	if report.WallTimeMS != 1500 {
+		t.Errorf("Expected a wall time of 1500ms, got %d", report.WallTimeMS)
+	}
+	if totals := report.CacheTotals(); totals.Hits != 2 || totals.Misses != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %+v", totals)
+	}
+	if len(report.Providers) != 0 {
+		t.Errorf("Expected no provider usage, got %v", report.Providers)
+	}
+
+	// A nil recorder ignores the counts
+	var none *Recorder
+	none.Request("github")
+	none.Cache("summary", true)
+	if none.Report() != nil {
+		t.Error("Expected no report from a nil recorder")
+	}
+}
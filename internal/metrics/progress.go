@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	_ "embed"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// EventType is the type of a progress event
+type EventType string
+
+const (
+	EventProviderStarted    EventType = "provider_started"
+	EventProviderFinished   EventType = "provider_finished"
+	EventEnrichmentProgress EventType = "enrichment_progress"
+	EventRunFinished        EventType = "run_finished"
+)
+
+// ProgressSchema is the JSON Schema of the progress events
+//
+//go:embed progress.schema.json
+var ProgressSchema string
+
+// Event is a progress event of a run, written as one JSON line by --progress json
+type Event struct {
+	Type     EventType `json:"event"`
+	Time     time.Time `json:"time"`
+	Provider string    `json:"provider,omitempty"`
+	// Count is the number of items a provider returned, on provider_finished
+	Count      *int   `json:"count,omitempty"`
+	DurationMS *int64 `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// Done and Total are the enriched items and the items to enrich, on enrichment_progress
+	Done    int     `json:"done,omitempty"`
+	Total   int     `json:"total,omitempty"`
+	Metrics *Report `json:"metrics,omitempty"`
+}
+
+// SetProgress makes the recorder write its progress events to w as JSON lines
+func (r *Recorder) SetProgress(w io.Writer) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress = w
+}
+
+// emit writes a progress event, when a progress writer is set, timed now unless its time is
+// set. The caller holds the lock.
+func (r *Recorder) emit(event Event) {
+	if r.progress == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = r.now()
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	// Progress is best effort: a closed pipe mustn't fail the run
+	_, _ = r.progress.Write(append(data, '\n'))
+}
+
+// ProviderStarted records that a provider is being queried
+func (r *Recorder) ProviderStarted(provider string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	r.started[provider] = now
+	r.emit(Event{Type: EventProviderStarted, Time: now, Provider: provider})
+}
+
+// ProviderFinished records that a provider returned count items, or failed with err
+func (r *Recorder) ProviderFinished(provider string, count int, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	event := Event{Type: EventProviderFinished, Time: now, Provider: provider, Count: &count}
+	if start, ok := r.started[provider]; ok {
+		duration := now.Sub(start).Milliseconds()
+		event.DurationMS = &duration
+		delete(r.started, provider)
+	}
+	if err != nil {
+		event.Count = nil
+		event.Error = err.Error()
+	}
+	r.emit(event)
+}
+
+// EnrichmentProgress records that done of the total items of a provider are enriched
+func (r *Recorder) EnrichmentProgress(provider string, done, total int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emit(Event{Type: EventEnrichmentProgress, Provider: provider, Done: done, Total: total})
+}
+
+// RunFinished records the end of the run with its usage, and the error it failed with
+func (r *Recorder) RunFinished(err error) {
+	if r == nil {
+		return
+	}
+	report := r.Report()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event := Event{Type: EventRunFinished, DurationMS: &report.WallTimeMS, Metrics: report}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	r.emit(event)
+}
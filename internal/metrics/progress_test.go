@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readEvents parses the JSON lines of a progress stream
+func readEvents(t *testing.T, stream string) []Event {
+	t.Helper()
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSuffix(stream, "\n"), "\n") {
+		var event Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Failed to parse event %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestRecorder_Progress(t *testing.T) {
+	recorder := NewRecorder()
+	clock := recorder.start
+	recorder.now = func() time.Time {
+		clock = clock.Add(100 * time.Millisecond)
+		return clock
+	}
+	var stream bytes.Buffer
+	recorder.SetProgress(&stream)
+
+	recorder.ProviderStarted("GitHub")
+	recorder.Request("GitHub")
+	recorder.ProviderFinished("GitHub", 2, nil)
+	recorder.EnrichmentProgress("GitHub", 1, 2)
+	recorder.EnrichmentProgress("GitHub", 2, 2)
+	recorder.ProviderStarted("JIRA")
+	recorder.ProviderFinished("JIRA", 0, errors.New("unauthorized"))
+	recorder.RunFinished(nil)
+
+	events := readEvents(t, stream.String())
+	expected := []EventType{
+		EventProviderStarted, EventProviderFinished, EventEnrichmentProgress, EventEnrichmentProgress,
+		EventProviderStarted, EventProviderFinished, EventRunFinished,
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d: %s", len(expected), len(events), stream.String())
+	}
+	for i, want := range expected {
+		if events[i].Type != want {
+			t.Errorf("Event %d: expected %s, got %s", i, want, events[i].Type)
+		}
+		if i > 0 && !events[i].Time.After(events[i-1].Time) {
+			t.Errorf("Event %d: expected a time after the previous event, got %v", i, events[i].Time)
+		}
+	}
+
+	github := events[1]
+	if github.Provider != "GitHub" || github.Count == nil || *github.Count != 2 || github.Error != "" {
+		t.Errorf("Expected GitHub to return 2 items, got %+v", github)
+	}
+	if github.DurationMS == nil || *github.DurationMS != 100 {
+		t.Errorf("Expected a duration of 100ms, got %v", github.DurationMS)
+	}
+	if progress := events[3]; progress.Done != 2 || progress.Total != 2 {
+		t.Errorf("Expected 2 of 2 items enriched, got %+v", progress)
+	}
+	if jira := events[5]; jira.Error != "unauthorized" || jira.Count != nil {
+		t.Errorf("Expected the JIRA error without count, got %+v", jira)
+	}
+
+	run := events[6]
+	if run.Error != "" || run.Metrics == nil || run.Metrics.Providers["GitHub"].Requests != 1 {
+		t.Errorf("Expected the metrics of the run, got %+v", run)
+	}
+	if run.DurationMS == nil || *run.DurationMS != run.Metrics.WallTimeMS {
+		t.Errorf("Expected the wall time as duration, got %v", run.DurationMS)
+	}
+}
+
+func TestRecorder_RunFinishedWithError(t *testing.T) {
+	recorder := NewRecorder()
+	var stream bytes.Buffer
+	recorder.SetProgress(&stream)
+
+	recorder.RunFinished(errors.New("failed to load config"))
+
+	events := readEvents(t, stream.String())
+	if len(events) != 1 || events[0].Type != EventRunFinished || events[0].Error != "failed to load config" {
+		t.Errorf("Expected a failed run_finished event, got %s", stream.String())
+	}
+}
+
+func TestRecorder_WithoutProgress(t *testing.T) {
+	// Without progress writer, the events are only used for the metrics
+	recorder := NewRecorder()
+	recorder.ProviderStarted("GitHub")
+	recorder.ProviderFinished("GitHub", 1, nil)
+	recorder.EnrichmentProgress("GitHub", 1, 1)
+	recorder.RunFinished(nil)
+
+	// A nil recorder ignores the events
+	var none *Recorder
+	none.SetProgress(&bytes.Buffer{})
+	none.ProviderStarted("GitHub")
+	none.ProviderFinished("GitHub", 1, nil)
+	none.EnrichmentProgress("GitHub", 1, 1)
+	none.RunFinished(nil)
+}
+
+func TestProgressSchema(t *testing.T) {
+	var schema struct {
+		Properties struct {
+			Event struct {
+				Enum []EventType `json:"enum"`
+			} `json:"event"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(ProgressSchema), &schema); err != nil {
+		t.Fatalf("Expected a valid JSON schema, got: %v", err)
+	}
+
+	expected := []EventType{EventProviderStarted, EventProviderFinished, EventEnrichmentProgress, EventRunFinished}
+	if !slices.Equal(schema.Properties.Event.Enum, expected) {
+		t.Errorf("Expected the schema to list %v, got %v", expected, schema.Properties.Event.Enum)
+	}
+}
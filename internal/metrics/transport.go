@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// rateLimitHeader holds the number of requests left in the current rate limit window
+const rateLimitHeader = "X-RateLimit-Remaining"
+
+// Transport counts the requests of a provider and the bytes of their responses in the
+// recorder of the request context. Requests without a recorder are passed through.
+type Transport struct {
+	Provider string
+	Base     http.RoundTripper // http.DefaultTransport when nil
+}
+
+// NewTransport creates a transport counting the requests of a provider
+func NewTransport(provider string) *Transport {
+	return &Transport{Provider: provider}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	recorder := FromContext(req.Context())
+	if recorder == nil {
+		return base.RoundTrip(req)
+	}
+
+	recorder.Request(t.Provider)
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if remaining, err := strconv.Atoi(resp.Header.Get(rateLimitHeader)); err == nil {
+		recorder.RateLimit(t.Provider, remaining)
+	}
+	resp.Body = &countingBody{ReadCloser: resp.Body, provider: t.Provider, recorder: recorder}
+	return resp, nil
+}
+
+// countingBody counts the bytes read from a response body
+type countingBody struct {
+	io.ReadCloser
+	provider string
+	recorder *Recorder
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.recorder.Downloaded(b.provider, int64(n))
+	return n, err
+}
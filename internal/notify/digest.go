@@ -0,0 +1,114 @@
+// Package notify batches the changes detected between runs into a single desktop notification,
+// so a burst of new review requests or tickets doesn't turn into a burst of alerts.
+package notify
+
+import "time"
+
+// Change kinds
+const (
+	KindReviewRequest = "review_request" // A pull request awaiting the user's review
+	KindJIRAAssigned  = "jira_assigned"  // A JIRA ticket assigned to the user
+)
+
+// Change is something new that deserves the user's attention
+type Change struct {
+	ID         string    `json:"id"`               // Stable identifier, a change seen twice is only counted once
+	Kind       string    `json:"kind"`             // KindReviewRequest, KindJIRAAssigned...
+	Source     string    `json:"source,omitempty"` // Provider that listed the change, e.g. "github"
+	Title      string    `json:"title"`
+	URL        string    `json:"url,omitempty"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// DigestState is the batch waiting to be sent, persisted between runs
+type DigestState struct {
+	Pending  []Change  `json:"pending,omitempty"`
+	Deadline time.Time `json:"deadline,omitempty"` // When the batch is sent; zero when nothing is pending
+}
+
+// Digest batches changes over a window. It doesn't read the clock or send anything itself,
+// the caller passes the current time and sends the flushed changes:
+//   - the window starts with the first change added to an empty batch
+//   - later changes join the batch without pushing its deadline back, so a steady stream
+//     of changes can't hold the notification forever
+//   - a batch reaching maxBatch changes is due right away
+//   - flushing empties the batch and the next change starts a new window
+type Digest struct {
+	window   time.Duration
+	maxBatch int
+	state    DigestState
+}
+
+// NewDigest returns a digest resuming from state. A maxBatch of 0 or less disables early sending.
+func NewDigest(window time.Duration, maxBatch int, state DigestState) *Digest {
+	return &Digest{window: window, maxBatch: maxBatch, state: state}
+}
+
+// Add puts the change in the batch, ignoring changes already pending
+func (d *Digest) Add(change Change, now time.Time) {
+	for _, pending := range d.state.Pending {
+		if pending.ID == change.ID {
+			return
+		}
+	}
+
+	if len(d.state.Pending) == 0 {
+		d.state.Deadline = now.Add(d.window)
+	}
+	if change.DetectedAt.IsZero() {
+		change.DetectedAt = now
+	}
+	d.state.Pending = append(d.state.Pending, change)
+}
+
+// Due reports whether the batch should be sent at now
+func (d *Digest) Due(now time.Time) bool {
+	if len(d.state.Pending) == 0 {
+		return false
+	}
+	if d.maxBatch > 0 && len(d.state.Pending) >= d.maxBatch {
+		return true
+	}
+	return !now.Before(d.state.Deadline)
+}
+
+// Flush returns the pending changes in the order they were added and empties the batch
+func (d *Digest) Flush() []Change {
+	changes := d.state.Pending
+	d.state = DigestState{}
+	return changes
+}
+
+// Remove takes the pending changes matching match out of the batch and returns them. The
+// deadline of the changes left is kept.
+func (d *Digest) Remove(match func(Change) bool) []Change {
+	var removed, kept []Change
+	for _, change := range d.state.Pending {
+		if match(change) {
+			removed = append(removed, change)
+		} else {
+			kept = append(kept, change)
+		}
+	}
+
+	d.state.Pending = kept
+	if len(kept) == 0 {
+		d.state = DigestState{}
+	}
+	return removed
+}
+
+// Pending returns the number of changes waiting to be sent
+func (d *Digest) Pending() int {
+	return len(d.state.Pending)
+}
+
+// Deadline returns when the pending batch is sent, zero when nothing is pending
+func (d *Digest) Deadline() time.Time {
+	return d.state.Deadline
+}
+
+// State returns the state to persist until the next run
+func (d *Digest) State() DigestState {
+	return d.state
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDigest_Window(t *testing.T) {
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	digest := NewDigest(15*time.Minute, 0, DigestState{})
+
+	if digest.Due(start) {
+		t.Error("Expected an empty digest not to be due")
+	}
+
+	digest.Add(Change{ID: "pr-1", Kind: KindReviewRequest}, start)
+	if !digest.Deadline().Equal(start.Add(15 * time.Minute)) {
+		t.Errorf("Expected the first change to start the window, got deadline %v", digest.Deadline())
+	}
+
+	// Later changes join the batch without pushing the deadline back
+	digest.Add(Change{ID: "pr-2", Kind: KindReviewRequest}, start.Add(10*time.Minute))
+	if !digest.Deadline().Equal(start.Add(15 * time.Minute)) {
+		t.Errorf("Expected the deadline to stay at the end of the first window, got %v", digest.Deadline())
+	}
+
+	if digest.Due(start.Add(14 * time.Minute)) {
+		t.Error("Expected the digest not to be due before the end of the window")
+	}
+	if !digest.Due(start.Add(15 * time.Minute)) {
+		t.Error("Expected the digest to be due at the end of the window")
+	}
+
+	changes := digest.Flush()
+	if len(changes) != 2 || changes[0].ID != "pr-1" || changes[1].ID != "pr-2" {
+		t.Errorf("Expected pr-1 and pr-2 in order, got %+v", changes)
+	}
+	if digest.Pending() != 0 || !digest.Deadline().IsZero() || digest.Due(start.Add(time.Hour)) {
+		t.Errorf("Expected an empty digest after flushing, got %+v", digest.State())
+	}
+
+	// The next change starts a new window
+	next := start.Add(time.Hour)
+	digest.Add(Change{ID: "pr-3", Kind: KindReviewRequest}, next)
+	if !digest.Deadline().Equal(next.Add(15 * time.Minute)) {
+		t.Errorf("Expected a new window after flushing, got deadline %v", digest.Deadline())
+	}
+}
+
+func TestDigest_MaxBatch(t *testing.T) {
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	digest := NewDigest(time.Hour, 3, DigestState{})
+
+	digest.Add(Change{ID: "pr-1"}, now)
+	digest.Add(Change{ID: "pr-2"}, now)
+	if digest.Due(now) {
+		t.Error("Expected the digest not to be due below the batch size")
+	}
+
+	digest.Add(Change{ID: "pr-3"}, now)
+	if !digest.Due(now) {
+		t.Error("Expected a full batch to be due before the end of the window")
+	}
+}
+
+func TestDigest_IgnoresDuplicates(t *testing.T) {
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	digest := NewDigest(time.Hour, 0, DigestState{})
+
+	digest.Add(Change{ID: "pr-1", Title: "first"}, now)
+	digest.Add(Change{ID: "pr-1", Title: "again"}, now.Add(time.Minute))
+
+	changes := digest.Flush()
+	if len(changes) != 1 || changes[0].Title != "first" {
+		t.Errorf("Expected the duplicate to be ignored, got %+v", changes)
+	}
+	if !changes[0].DetectedAt.Equal(now) {
+		t.Errorf("Expected detection time to default to the time added, got %v", changes[0].DetectedAt)
+	}
+}
+
+func TestDigest_ResumesFromState(t *testing.T) {
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	digest := NewDigest(15*time.Minute, 0, DigestState{})
+	digest.Add(Change{ID: "pr-1", Kind: KindReviewRequest}, start)
+
+	// The state survives a round trip through the state file of the next run
+	data, err := json.Marshal(digest.State())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var state DigestState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	resumed := NewDigest(15*time.Minute, 0, state)
+	resumed.Add(Change{ID: "pr-2", Kind: KindReviewRequest}, start.Add(5*time.Minute))
+
+	if resumed.Pending() != 2 || !resumed.Deadline().Equal(start.Add(15*time.Minute)) {
+		t.Errorf("Expected 2 pending changes due at the end of the first window, got %+v", resumed.State())
+	}
+}
+
+func TestDigest_Remove(t *testing.T) {
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	digest := NewDigest(15*time.Minute, 0, DigestState{})
+	digest.Add(Change{ID: "pr-1", Source: "github"}, start)
+	digest.Add(Change{ID: "jira-PROJ-1", Source: "jira"}, start.Add(5*time.Minute))
+
+	fromGitHub := func(change Change) bool { return change.Source == "github" }
+	removed := digest.Remove(fromGitHub)
+	if len(removed) != 1 || removed[0].ID != "pr-1" {
+		t.Errorf("Expected the GitHub change to be removed, got %+v", removed)
+	}
+	if digest.Pending() != 1 || !digest.Deadline().Equal(start.Add(15*time.Minute)) {
+		t.Errorf("Expected the other change to keep the deadline, got %+v", digest.State())
+	}
+
+	digest.Remove(func(Change) bool { return true })
+	if digest.Pending() != 0 || !digest.Deadline().IsZero() {
+		t.Errorf("Expected an empty batch without deadline, got %+v", digest.State())
+	}
+}
@@ -0,0 +1,103 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+)
+
+// Notifier delivers notifications to the user
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// DesktopNotifier shows notifications with the notification tool of the platform:
+// terminal-notifier or osascript on macOS, notify-send on Linux. Clicking the notification
+// runs its command or opens its URL, except with osascript which doesn't support actions.
+type DesktopNotifier struct {
+	goos     string
+	lookPath func(file string) (string, error)
+}
+
+// NewDesktopNotifier returns a notifier for the current platform
+func NewDesktopNotifier() *DesktopNotifier {
+	return &DesktopNotifier{goos: runtime.GOOS, lookPath: exec.LookPath}
+}
+
+// Notify shows the notification. With a click action on Linux, notify-send keeps running in
+// the background until the notification is clicked or dismissed.
+func (d *DesktopNotifier) Notify(n Notification) error {
+	cmd, background, err := d.command(n)
+	if err != nil {
+		return err
+	}
+
+	if !background {
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to show notification: %w: %s", err, output)
+		}
+		return nil
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to show notification: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// command returns the command showing the notification, and whether it waits for a click
+func (d *DesktopNotifier) command(n Notification) (*exec.Cmd, bool, error) {
+	switch d.goos {
+	case "darwin":
+		if path, err := d.lookPath("terminal-notifier"); err == nil {
+			args := []string{"-title", n.Title, "-message", n.Message, "-group", "daily"}
+			if n.Command != "" {
+				args = append(args, "-execute", n.Command)
+			} else if n.URL != "" {
+				args = append(args, "-open", n.URL)
+			}
+			return exec.Command(path, args...), false, nil
+		}
+		// Arguments are passed to the script rather than quoted into it
+		return exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			n.Title, n.Message), false, nil
+
+	case "linux":
+		path, err := d.lookPath("notify-send")
+		if err != nil {
+			return nil, false, fmt.Errorf("notify-send not found: install libnotify to get desktop notifications")
+		}
+
+		// notify-send prints the name of the action clicked when waiting for it
+		wait := `test "$("$1" --app-name=daily --wait --action=open=Open "$2" "$3")" = open && `
+		if n.Command != "" {
+			return exec.Command("sh", "-c", wait+`exec sh -c "$4"`, "sh", path, n.Title, n.Message, n.Command), true, nil
+		}
+		if n.URL != "" {
+			return exec.Command("sh", "-c", wait+`exec xdg-open "$4"`, "sh", path, n.Title, n.Message, n.URL), true, nil
+		}
+		return exec.Command(path, "--app-name=daily", n.Title, n.Message), false, nil
+	}
+
+	return nil, false, fmt.Errorf("desktop notifications are not supported on %s", d.goos)
+}
+
+// WriterNotifier prints notifications instead of showing them, e.g. to preview a digest
+type WriterNotifier struct {
+	w io.Writer
+}
+
+// NewWriterNotifier returns a notifier printing to w
+func NewWriterNotifier(w io.Writer) *WriterNotifier {
+	return &WriterNotifier{w: w}
+}
+
+// Notify prints the notification
+func (p *WriterNotifier) Notify(n Notification) error {
+	_, err := fmt.Fprintf(p.w, "🔔 %s: %s\n", n.Title, n.Message)
+	return err
+}
@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDesktopNotifier_Command(t *testing.T) {
+	found := func(tools ...string) func(string) (string, error) {
+		return func(file string) (string, error) {
+			for _, tool := range tools {
+				if tool == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	tests := []struct {
+		name         string
+		goos         string
+		tools        []string
+		notification Notification
+		expectedArgs []string
+		background   bool
+		expectError  bool
+	}{
+		{
+			name:         "terminal-notifier with command",
+			goos:         "darwin",
+			tools:        []string{"terminal-notifier"},
+			notification: Notification{Title: "daily", Message: "2 new review requests", Command: "open -a Terminal"},
+			expectedArgs: []string{"/usr/bin/terminal-notifier", "-title", "daily", "-message", "2 new review requests", "-group", "daily", "-execute", "open -a Terminal"},
+		},
+		{
+			name:         "terminal-notifier with URL",
+			goos:         "darwin",
+			tools:        []string{"terminal-notifier"},
+			notification: Notification{Title: "daily", Message: "1 new review request", URL: "https://github.com/org/repo/pull/1"},
+			expectedArgs: []string{"/usr/bin/terminal-notifier", "-title", "daily", "-message", "1 new review request", "-group", "daily", "-open", "https://github.com/org/repo/pull/1"},
+		},
+		{
+			name:         "osascript fallback",
+			goos:         "darwin",
+			notification: Notification{Title: "daily", Message: `say "hi"`},
+			expectedArgs: []string{"osascript", "-e", "on run argv", "-e", "display notification (item 2 of argv) with title (item 1 of argv)", "-e", "end run", "daily", `say "hi"`},
+		},
+		{
+			name:         "notify-send without action",
+			goos:         "linux",
+			tools:        []string{"notify-send"},
+			notification: Notification{Title: "daily", Message: "1 JIRA ticket assigned"},
+			expectedArgs: []string{"/usr/bin/notify-send", "--app-name=daily", "daily", "1 JIRA ticket assigned"},
+		},
+		{
+			name:         "notify-send waiting for a click",
+			goos:         "linux",
+			tools:        []string{"notify-send"},
+			notification: Notification{Title: "daily", Message: "1 JIRA ticket assigned", Command: "kitty daily todo"},
+			expectedArgs: []string{"sh", "-c", `test "$("$1" --app-name=daily --wait --action=open=Open "$2" "$3")" = open && exec sh -c "$4"`, "sh", "/usr/bin/notify-send", "daily", "1 JIRA ticket assigned", "kitty daily todo"},
+			background:   true,
+		},
+		{
+			name:        "notify-send missing",
+			goos:        "linux",
+			expectError: true,
+		},
+		{
+			name:        "unsupported platform",
+			goos:        "windows",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &DesktopNotifier{goos: tt.goos, lookPath: found(tt.tools...)}
+
+			cmd, background, err := notifier.command(tt.notification)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if strings.Join(cmd.Args, "|") != strings.Join(tt.expectedArgs, "|") {
+				t.Errorf("Expected args %q, got %q", tt.expectedArgs, cmd.Args)
+			}
+			if background != tt.background {
+				t.Errorf("Expected background %v, got %v", tt.background, background)
+			}
+		})
+	}
+}
+
+func TestWriterNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewWriterNotifier(&buf).Notify(Notification{Title: "daily", Message: "1 new review request"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if buf.String() != "🔔 daily: 1 new review request\n" {
+		t.Errorf("Expected the notification to be printed, got %q", buf.String())
+	}
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"daily/internal/statedir"
+)
+
+// State is what 'daily notify' remembers between runs
+type State struct {
+	Seen   map[string][]string `json:"seen,omitempty"` // IDs of the items listed by the last run, by change kind
+	Digest DigestState         `json:"digest"`
+	Held   []HeldChange        `json:"held,omitempty"` // Changes detected during quiet hours
+}
+
+// HeldChange is a change kept from the digest until the quiet hours it was detected in end
+type HeldChange struct {
+	Change
+	Until time.Time `json:"until"`
+}
+
+// Hold keeps the change until the end of the quiet hours, ignoring changes already held
+func (s *State) Hold(change Change, until time.Time) {
+	for _, held := range s.Held {
+		if held.ID == change.ID {
+			return
+		}
+	}
+	s.Held = append(s.Held, HeldChange{Change: change, Until: until})
+}
+
+// Release removes the changes whose quiet hours ended at now, or every held change when all
+// is set, and returns them in the order they were held
+func (s *State) Release(now time.Time, all bool) []Change {
+	var released []Change
+	var kept []HeldChange
+	for _, held := range s.Held {
+		if all || !now.Before(held.Until) {
+			released = append(released, held.Change)
+		} else {
+			kept = append(kept, held)
+		}
+	}
+	s.Held = kept
+	return released
+}
+
+// Detect returns the current items of kind that weren't seen by the last run, and records
+// the current items as seen. The first run of a kind only records them, so that already
+// existing items don't trigger a notification.
+func (s *State) Detect(kind string, current []Change) []Change {
+	if s.Seen == nil {
+		s.Seen = make(map[string][]string)
+	}
+	previous, known := s.Seen[kind]
+
+	seen := make(map[string]bool, len(previous))
+	for _, id := range previous {
+		seen[id] = true
+	}
+
+	var changes []Change
+	ids := make([]string, 0, len(current))
+	for _, change := range current {
+		ids = append(ids, change.ID)
+		if known && !seen[change.ID] {
+			changes = append(changes, change)
+		}
+	}
+
+	// Only the current items are kept, an item that comes back later is reported again
+	s.Seen[kind] = ids
+	return changes
+}
+
+// Store persists the state in a JSON file
+type Store struct {
+	path string
+}
+
+// NewStore returns a store writing to path
+func NewStore(path string) Store {
+	return Store{path: path}
+}
+
+// DefaultStatePath returns the file of the state directory holding the notification state
+func DefaultStatePath() (string, error) {
+	return statedir.Path("notify.json")
+}
+
+// Load returns the stored state, or an empty state when nothing was stored yet
+func (s Store) Load() (State, error) {
+	var state State
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read notification state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse notification state: %w", err)
+	}
+	return state, nil
+}
+
+// Save writes the state, creating the cache directory if needed
+func (s Store) Save(state State) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification state: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write notification state: %w", err)
+	}
+	return nil
+}
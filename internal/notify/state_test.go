@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestState_Detect(t *testing.T) {
+	var state State
+
+	// The first run only records the existing items
+	if changes := state.Detect(KindReviewRequest, []Change{{ID: "pr-1"}, {ID: "pr-2"}}); len(changes) != 0 {
+		t.Errorf("Expected no changes on the first run, got %+v", changes)
+	}
+
+	changes := state.Detect(KindReviewRequest, []Change{{ID: "pr-2"}, {ID: "pr-3"}})
+	if len(changes) != 1 || changes[0].ID != "pr-3" {
+		t.Errorf("Expected pr-3 to be new, got %+v", changes)
+	}
+
+	// Kinds are tracked separately
+	if changes := state.Detect(KindJIRAAssigned, []Change{{ID: "jira-PROJ-1"}}); len(changes) != 0 {
+		t.Errorf("Expected no changes on the first run of a kind, got %+v", changes)
+	}
+
+	// An item that went away is reported again when it comes back
+	state.Detect(KindReviewRequest, []Change{{ID: "pr-3"}})
+	changes = state.Detect(KindReviewRequest, []Change{{ID: "pr-2"}, {ID: "pr-3"}})
+	if len(changes) != 1 || changes[0].ID != "pr-2" {
+		t.Errorf("Expected pr-2 to be new again, got %+v", changes)
+	}
+
+	// Having nothing is a known state, the next item is new
+	state.Detect(KindJIRAAssigned, nil)
+	if changes := state.Detect(KindJIRAAssigned, []Change{{ID: "jira-PROJ-2"}}); len(changes) != 1 {
+		t.Errorf("Expected jira-PROJ-2 to be new, got %+v", changes)
+	}
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "cache", "notify.json"))
+
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("Expected no error for missing state, got: %v", err)
+	}
+	if len(state.Seen) != 0 || len(state.Digest.Pending) != 0 {
+		t.Errorf("Expected empty state, got %+v", state)
+	}
+
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	state.Detect(KindReviewRequest, []Change{{ID: "pr-1"}})
+	digest := NewDigest(15*time.Minute, 0, state.Digest)
+	digest.Add(Change{ID: "pr-2", Kind: KindReviewRequest, Title: "Fix login"}, now)
+	state.Digest = digest.State()
+
+	if err := store.Save(state); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(loaded.Seen[KindReviewRequest]) != 1 || loaded.Seen[KindReviewRequest][0] != "pr-1" {
+		t.Errorf("Expected seen pr-1, got %v", loaded.Seen)
+	}
+	if len(loaded.Digest.Pending) != 1 || loaded.Digest.Pending[0].Title != "Fix login" {
+		t.Errorf("Expected pending pr-2, got %+v", loaded.Digest.Pending)
+	}
+	if !loaded.Digest.Deadline.Equal(now.Add(15 * time.Minute)) {
+		t.Errorf("Expected deadline to be kept, got %v", loaded.Digest.Deadline)
+	}
+}
+
+func TestState_HoldAndRelease(t *testing.T) {
+	morning := time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC)
+	var state State
+
+	state.Hold(Change{ID: "pr-1", Title: "Fix login"}, morning)
+	state.Hold(Change{ID: "pr-1", Title: "again"}, morning)
+	state.Hold(Change{ID: "pr-2"}, morning.Add(24*time.Hour))
+	if len(state.Held) != 2 || state.Held[0].Title != "Fix login" {
+		t.Fatalf("Expected the duplicate to be ignored, got %+v", state.Held)
+	}
+
+	if released := state.Release(morning.Add(-time.Minute), false); len(released) != 0 {
+		t.Errorf("Expected nothing released before the end of the quiet hours, got %+v", released)
+	}
+
+	released := state.Release(morning, false)
+	if len(released) != 1 || released[0].ID != "pr-1" {
+		t.Errorf("Expected pr-1 released at the end of its quiet hours, got %+v", released)
+	}
+
+	if released := state.Release(morning, true); len(released) != 1 || released[0].ID != "pr-2" || len(state.Held) != 0 {
+		t.Errorf("Expected every held change released, got %+v and %+v", released, state.Held)
+	}
+}
+
+func TestStore_KeepsHeldChanges(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "notify.json"))
+	morning := time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC)
+
+	var state State
+	state.Hold(Change{ID: "pr-1", Kind: KindReviewRequest, Source: "github"}, morning)
+	if err := store.Save(state); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// A run after a night asleep releases what was held by the previous one
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	released := loaded.Release(morning.Add(3*time.Hour), false)
+	if len(released) != 1 || released[0].ID != "pr-1" || released[0].Source != "github" {
+		t.Errorf("Expected the held change to survive the restart, got %+v", released)
+	}
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// kindLabel describes a count of changes of one kind
+type kindLabel struct {
+	singular string
+	plural   string
+}
+
+// kindLabels are listed in the order they appear in summaries
+var kindLabels = []struct {
+	kind  string
+	label kindLabel
+}{
+	{KindReviewRequest, kindLabel{"%d new review request", "%d new review requests"}},
+	{KindJIRAAssigned, kindLabel{"%d JIRA ticket assigned", "%d JIRA tickets assigned"}},
+}
+
+// otherLabel describes changes of kinds without a label
+var otherLabel = kindLabel{"%d other change", "%d other changes"}
+
+// Summarize counts the changes by kind, e.g. "3 new review requests, 1 JIRA ticket assigned"
+func Summarize(changes []Change) string {
+	counts := make(map[string]int)
+	for _, change := range changes {
+		counts[change.Kind]++
+	}
+
+	var parts []string
+	others := len(changes)
+	for _, entry := range kindLabels {
+		count := counts[entry.kind]
+		if count == 0 {
+			continue
+		}
+		parts = append(parts, entry.label.format(count))
+		others -= count
+	}
+	if others > 0 {
+		parts = append(parts, otherLabel.format(others))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (l kindLabel) format(count int) string {
+	if count == 1 {
+		return fmt.Sprintf(l.singular, count)
+	}
+	return fmt.Sprintf(l.plural, count)
+}
+
+// Notification is a desktop notification summarizing a batch of changes
+type Notification struct {
+	Title   string
+	Message string
+	URL     string // Opened on click when no command is set, only for single changes
+	Command string // Run on click, e.g. a terminal showing 'daily todo'
+}
+
+// NewNotification builds the notification for a flushed batch. A single change is
+// named in the message and can be opened directly.
+func NewNotification(changes []Change, command string) Notification {
+	n := Notification{
+		Title:   "daily",
+		Message: Summarize(changes),
+		Command: command,
+	}
+	if len(changes) == 1 {
+		n.Message = fmt.Sprintf("%s\n%s", n.Message, changes[0].Title)
+		n.URL = changes[0].URL
+	}
+	return n
+}
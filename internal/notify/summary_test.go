@@ -0,0 +1,65 @@
+package notify
+
+import "testing"
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name     string
+		changes  []Change
+		expected string
+	}{
+		{
+			name:     "single review request",
+			changes:  []Change{{Kind: KindReviewRequest}},
+			expected: "1 new review request",
+		},
+		{
+			name: "kinds in fixed order",
+			changes: []Change{
+				{Kind: KindJIRAAssigned},
+				{Kind: KindReviewRequest},
+				{Kind: KindReviewRequest},
+				{Kind: KindReviewRequest},
+			},
+			expected: "3 new review requests, 1 JIRA ticket assigned",
+		},
+		{
+			name:     "unknown kinds",
+			changes:  []Change{{Kind: KindJIRAAssigned}, {Kind: KindJIRAAssigned}, {Kind: "mention"}},
+			expected: "2 JIRA tickets assigned, 1 other change",
+		},
+		{
+			name:     "no changes",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(tt.changes); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewNotification(t *testing.T) {
+	single := NewNotification([]Change{{Kind: KindReviewRequest, Title: "org/repo#12: Fix login", URL: "https://github.com/org/repo/pull/12"}}, "")
+	if single.Message != "1 new review request\norg/repo#12: Fix login" {
+		t.Errorf("Expected the single change to be named, got %q", single.Message)
+	}
+	if single.URL != "https://github.com/org/repo/pull/12" {
+		t.Errorf("Expected the single change to be opened on click, got %q", single.URL)
+	}
+
+	batch := NewNotification([]Change{
+		{Kind: KindReviewRequest, URL: "https://github.com/org/repo/pull/12"},
+		{Kind: KindJIRAAssigned, URL: "https://example.atlassian.net/browse/PROJ-1"},
+	}, "kitty daily todo")
+	if batch.Message != "1 new review request, 1 JIRA ticket assigned" {
+		t.Errorf("Expected a summary only, got %q", batch.Message)
+	}
+	if batch.URL != "" || batch.Command != "kitty daily todo" {
+		t.Errorf("Expected the command to be run on click, got %+v", batch)
+	}
+}
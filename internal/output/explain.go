@@ -0,0 +1,44 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatExplanation formats why an item is listed, e.g.
+//
+//	🔎 PROJ-1: Checkout
+//	   Provider: jira
+//	   Query:    assignee = currentUser() AND statusCategory != Done ORDER BY updated DESC
+//	   Fetched:  Oct 16, 2026 09:30:12
+func (f *Formatter) FormatExplanation(item TodoItem) string {
+	var output strings.Builder
+	output.WriteString(f.titleStyle.Render("🔎 " + item.Title))
+	output.WriteString("\n")
+
+	provenance := item.Provenance
+	if provenance == nil {
+		output.WriteString("   No provenance was recorded for this item.\n")
+		return output.String()
+	}
+
+	fields := []struct{ name, value string }{
+		{"Provider", provenance.Provider},
+		{"Query", provenance.Query},
+		{"Team", provenance.Team},
+		{"Filter", provenance.Filter},
+		{"Source", provenance.Source},
+		{"Fetched", provenance.FetchedAt.Format("Jan 2, 2006 15:04:05")},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		output.WriteString(fmt.Sprintf("   %-9s %s\n", field.name+":", field.value))
+	}
+	if item.URL != "" {
+		output.WriteString(fmt.Sprintf("   %-9s %s\n", "URL:", f.urlStyle.Render(item.URL)))
+	}
+
+	return output.String()
+}
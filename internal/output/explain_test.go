@@ -0,0 +1,41 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatter_FormatExplanation(t *testing.T) {
+	item := TodoItem{
+		ID:    "jira-PROJ-1",
+		Title: "PROJ-1: Checkout",
+		Provenance: &Provenance{
+			Provider:  "jira",
+			Query:     "assignee = currentUser() AND statusCategory != Done AND (project = PROJ) ORDER BY updated DESC",
+			Filter:    "project = PROJ",
+			FetchedAt: time.Date(2024, 3, 11, 9, 30, 12, 0, time.UTC),
+		},
+	}
+
+	result := NewPlainFormatter().FormatExplanation(item)
+	for _, expected := range []string{
+		"🔎 PROJ-1: Checkout",
+		"   Provider: jira\n",
+		"   Query:    assignee = currentUser() AND statusCategory != Done AND (project = PROJ) ORDER BY updated DESC\n",
+		"   Filter:   project = PROJ\n",
+		"   Fetched:  Mar 11, 2024 09:30:12\n",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected explanation to contain %q, got:\n%s", expected, result)
+		}
+	}
+	if strings.Contains(result, "Team:") {
+		t.Errorf("Expected no team for a JIRA ticket, got:\n%s", result)
+	}
+
+	item.Provenance = nil
+	if result := NewPlainFormatter().FormatExplanation(item); !strings.Contains(result, "No provenance") {
+		t.Errorf("Expected a note for items without provenance, got:\n%s", result)
+	}
+}
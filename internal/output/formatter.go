@@ -1,9 +1,14 @@
 package output
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -12,6 +17,9 @@ import (
 	"github.com/charmbracelet/lipgloss/v2"
 
 	"daily/internal/activity"
+	"daily/internal/metrics"
+	"daily/internal/severity"
+	"daily/internal/terminal"
 	"daily/internal/tui"
 	"daily/internal/tui/types"
 )
@@ -27,8 +35,25 @@ type Formatter struct {
 	urlStyle         lipgloss.Style
 	tagStyle         lipgloss.Style
 	borderStyle      lipgloss.Style
+	faintStyle       lipgloss.Style
+
+	// severityStyles color the titles of items by severity, nil when titles are marked instead
+	severityStyles map[severity.Level]lipgloss.Style
+
+	// groupBy nests the activities of a platform section under sub-headings (e.g. GroupByEpic)
+	groupBy string
+	// meta is the usage of the run, added to JSON output under "meta" when set
+	meta *metrics.Report
+	// maxItems caps the activities listed by summaries, 0 for no limit (see SetMaxItems)
+	maxItems int
 }
 
+// GroupByEpic nests JIRA activities under their epic in text summaries
+const GroupByEpic = "epic"
+
+// noEpicHeading is the heading of the JIRA activities that have no epic
+const noEpicHeading = "No epic"
+
 // isDarkMode detects if the terminal is using a dark theme
 func isDarkMode() bool {
 	// Check for explicit dark mode environment variables
@@ -56,6 +81,11 @@ func isDarkMode() bool {
 }
 
 func NewFormatter() *Formatter {
+	// Escape codes render as garbage on dumb terminals and terminals without colors
+	if terminal.Stdout().Plain() {
+		return NewPlainFormatter()
+	}
+
 	isDark := isDarkMode()
 
 	if isDark {
@@ -99,6 +129,8 @@ func NewFormatter() *Formatter {
 				Italic(true),
 			borderStyle: lipgloss.NewStyle().
 				Foreground(lipgloss.Color(mocha.Surface2().Hex)),
+			faintStyle:     lipgloss.NewStyle().Faint(true),
+			severityStyles: newSeverityStyles(mocha),
 		}
 	}
 
@@ -142,6 +174,34 @@ func NewFormatter() *Formatter {
 			Italic(true),
 		borderStyle: lipgloss.NewStyle().
 			Foreground(lipgloss.Color(latte.Surface2().Hex)),
+		faintStyle:     lipgloss.NewStyle().Faint(true),
+		severityStyles: newSeverityStyles(latte),
+	}
+}
+
+// newSeverityStyles returns the title colors of each severity in a theme
+func newSeverityStyles(flavor catppuccin.Flavor) map[severity.Level]lipgloss.Style {
+	return map[severity.Level]lipgloss.Style{
+		severity.Critical: lipgloss.NewStyle().Foreground(lipgloss.Color(flavor.Red().Hex)).Bold(true),
+		severity.High:     lipgloss.NewStyle().Foreground(lipgloss.Color(flavor.Peach().Hex)),
+		severity.Normal:   lipgloss.NewStyle().Foreground(lipgloss.Color(flavor.Yellow().Hex)),
+		severity.Low:      lipgloss.NewStyle().Foreground(lipgloss.Color(flavor.Overlay1().Hex)),
+	}
+}
+
+// NewPlainFormatter creates a formatter without colors, text decorations or borders
+func NewPlainFormatter() *Formatter {
+	return &Formatter{
+		titleStyle:       lipgloss.NewStyle().MarginBottom(1),
+		headerStyle:      lipgloss.NewStyle().MarginTop(1).MarginBottom(1),
+		platformStyle:    lipgloss.NewStyle(),
+		activityStyle:    lipgloss.NewStyle().PaddingLeft(2).PaddingTop(1).MarginBottom(1),
+		timeStyle:        lipgloss.NewStyle(),
+		descriptionStyle: lipgloss.NewStyle().PaddingLeft(5),
+		urlStyle:         lipgloss.NewStyle().PaddingLeft(5),
+		tagStyle:         lipgloss.NewStyle().PaddingLeft(5),
+		borderStyle:      lipgloss.NewStyle(),
+		faintStyle:       lipgloss.NewStyle(),
 	}
 }
 
@@ -159,21 +219,38 @@ func (f *Formatter) FormatSummary(summary *activity.Summary) string {
 		return activities[i].Timestamp.Before(activities[j].Timestamp)
 	})
 
-	// Group by platform
-	groups := make(map[string][]activity.Activity)
+	// Group by platform, platforms being counted over every activity
+	platformTotals := make(map[string]int)
 	for _, act := range activities {
+		platformTotals[act.Platform]++
+	}
+	shown, omitted := f.limitActivities(activities)
+	groups := make(map[string][]activity.Activity)
+	for _, act := range shown {
 		groups[act.Platform] = append(groups[act.Platform], act)
 	}
 
 	// Title with styling
 	title := fmt.Sprintf("📊 Daily Summary for %s", summary.Date.Format("January 2, 2006"))
+	if summary.Period != "" {
+		title += fmt.Sprintf(" (%s)", summary.Period)
+	}
 	output.WriteString(f.titleStyle.Render(title))
 	output.WriteString("\n")
 
 	// Summary stats
-	stats := fmt.Sprintf("Found %d activities across %d platforms", len(activities), len(groups))
+	stats := fmt.Sprintf("Found %d activities across %d platforms", len(activities), len(platformTotals))
 	output.WriteString(f.headerStyle.Render(stats))
-	output.WriteString("\n\n")
+	output.WriteString("\n")
+	if omitted > 0 {
+		output.WriteString(f.faintStyle.Render(truncationNotice(len(shown), len(activities))))
+		output.WriteString("\n")
+	}
+	if goals := formatGoals(summary.Goals); goals != "" {
+		output.WriteString(f.headerStyle.Render(goals))
+		output.WriteString("\n")
+	}
+	output.WriteString("\n")
 
 	// Display by platform
 	platforms := []string{"github", "jira", "obsidian"}
@@ -183,25 +260,42 @@ func (f *Formatter) FormatSummary(summary *activity.Summary) string {
 			continue
 		}
 
-		output.WriteString(f.formatPlatformSection(platform, platformActivities))
+		output.WriteString(f.formatPlatformSection(platform, platformActivities, platformTotals[platform], summary.SpansMultipleDays()))
 	}
 
-	// Add any other platforms not in the main list
-	for platform, platformActivities := range groups {
+	// Add any other platforms not in the main list, by name
+	for _, platform := range slices.Sorted(maps.Keys(groups)) {
 		if platform != "github" && platform != "jira" && platform != "obsidian" {
-			output.WriteString(f.formatPlatformSection(platform, platformActivities))
+			output.WriteString(f.formatPlatformSection(platform, groups[platform], platformTotals[platform], summary.SpansMultipleDays()))
 		}
 	}
 
 	return output.String()
 }
 
-func (f *Formatter) formatPlatformSection(platform string, activities []activity.Activity) string {
+// SetGroupBy sets how FormatSummary groups activities within a platform ("" or GroupByEpic)
+func (f *Formatter) SetGroupBy(groupBy string) {
+	f.groupBy = groupBy
+}
+
+// SetMeta sets the usage of the run added to JSON output
+func (f *Formatter) SetMeta(report *metrics.Report) {
+	f.meta = report
+}
+
+// formatPlatformSection formats the activities of a platform, under a heading per day when
+// the summary spans several days. total counts the activities of the platform before the max
+// items, so that the header reads "(3 of 10)" when some are left out.
+func (f *Formatter) formatPlatformSection(platform string, activities []activity.Activity, total int, multiDay bool) string {
 	var section strings.Builder
 
 	// Platform header with icon and styling
 	icon := f.getPlatformIcon(platform)
-	platformHeader := fmt.Sprintf("%s %s (%d)", icon, strings.Title(platform), len(activities))
+	count := formatCount(len(activities))
+	if total > len(activities) {
+		count = fmt.Sprintf("%s of %s", count, formatCount(total))
+	}
+	platformHeader := fmt.Sprintf("%s %s (%s)", icon, strings.Title(platform), count)
 	section.WriteString(f.platformStyle.Render(platformHeader))
 	section.WriteString("\n")
 
@@ -210,20 +304,87 @@ func (f *Formatter) formatPlatformSection(platform string, activities []activity
 	section.WriteString(f.borderStyle.Render(border))
 	section.WriteString("\n")
 
-	for _, act := range activities {
-		section.WriteString(f.formatActivity(act))
+	if platform == "jira" && f.groupBy == GroupByEpic {
+		for _, group := range groupByEpic(activities) {
+			section.WriteString(f.headerStyle.Render(fmt.Sprintf("📦 %s (%d)", group.heading, len(group.activities))))
+			section.WriteString("\n")
+			section.WriteString(f.formatActivities(group.activities, multiDay))
+		}
+	} else {
+		section.WriteString(f.formatActivities(activities, multiDay))
 	}
 
 	section.WriteString("\n")
 	return section.String()
 }
 
+// formatActivities formats activities, under a heading per day when multiDay is set
+func (f *Formatter) formatActivities(activities []activity.Activity, multiDay bool) string {
+	var content strings.Builder
+	if !multiDay {
+		for _, act := range activities {
+			content.WriteString(f.formatActivity(act))
+		}
+		return content.String()
+	}
+
+	for _, day := range activity.GroupByDay(activities) {
+		content.WriteString(f.headerStyle.Render("📅 " + day.Day.Format(activity.DayHeadingLayout)))
+		content.WriteString("\n")
+		for _, act := range day.Activities {
+			content.WriteString(f.formatActivity(act))
+		}
+	}
+	return content.String()
+}
+
+// epicGroup holds the activities of an epic
+type epicGroup struct {
+	heading    string
+	activities []activity.Activity
+}
+
+// groupByEpic splits activities by epic, in order of first appearance, with the activities
+// without an epic last under "No epic"
+func groupByEpic(activities []activity.Activity) []epicGroup {
+	var groups []epicGroup
+	index := make(map[string]int)
+	var noEpic []activity.Activity
+
+	for _, act := range activities {
+		if act.Epic == nil {
+			noEpic = append(noEpic, act)
+			continue
+		}
+
+		i, ok := index[act.Epic.Key]
+		if !ok {
+			heading := act.Epic.Key
+			if act.Epic.Name != "" {
+				heading = fmt.Sprintf("%s %s", act.Epic.Key, act.Epic.Name)
+			}
+			i = len(groups)
+			index[act.Epic.Key] = i
+			groups = append(groups, epicGroup{heading: heading})
+		} else if groups[i].heading == act.Epic.Key && act.Epic.Name != "" {
+			// Activities linked through the epic link field don't know the epic name
+			groups[i].heading = fmt.Sprintf("%s %s", act.Epic.Key, act.Epic.Name)
+		}
+		groups[i].activities = append(groups[i].activities, act)
+	}
+
+	if len(noEpic) > 0 {
+		groups = append(groups, epicGroup{heading: noEpicHeading, activities: noEpic})
+	}
+	return groups
+}
+
 func (f *Formatter) formatActivity(act activity.Activity) string {
 	var activityContent strings.Builder
 
 	// Time and type with styling
 	timeStr := f.timeStyle.Render(act.Timestamp.Format("15:04"))
-	typeIcon := f.getTypeIcon(act.Type)
+	typeIcon := f.getActivityIcon(act)
 
 	// Main activity line
 	mainLine := fmt.Sprintf("%s %s  %s", timeStr, typeIcon, act.Title)
@@ -236,6 +397,12 @@ func (f *Formatter) formatActivity(act activity.Activity) string {
 		activityContent.WriteString("\n")
 	}
 
+	if act.Changes != nil {
+		changes := f.descriptionStyle.Render("📊 " + act.Changes.String())
+		activityContent.WriteString(changes)
+		activityContent.WriteString("\n")
+	}
+
 	if act.URL != "" {
 		url := f.urlStyle.Render("🔗 " + act.URL)
 		activityContent.WriteString(url)
@@ -254,9 +421,14 @@ func (f *Formatter) formatActivity(act activity.Activity) string {
 
 func (f *Formatter) getPlatformIcon(platform string) string {
 	icons := map[string]string{
-		"github":   "🐙",
-		"jira":     "🎫",
-		"obsidian": "📝",
+		"github":        "🐙",
+		"jira":          "🎫",
+		"obsidian":      "📝",
+		"confluence":    "📚",
+		"gitlab":        "🦊",
+		"calendar":      "🗓️",
+		"slack":         "💬",
+		"saved_queries": "🔎",
 	}
 
 	if icon, exists := icons[platform]; exists {
@@ -267,11 +439,23 @@ func (f *Formatter) getPlatformIcon(platform string) string {
 
 func (f *Formatter) getTypeIcon(actType activity.ActivityType) string {
 	icons := map[activity.ActivityType]string{
-		activity.ActivityTypeCommit:     "💾",
-		activity.ActivityTypePR:         "🔀",
-		activity.ActivityTypeIssue:      "🐛",
-		activity.ActivityTypeJiraTicket: "🎯",
-		activity.ActivityTypeNote:       "📄",
+		activity.ActivityTypeCommit:            "💾",
+		activity.ActivityTypePR:                "🔀",
+		activity.ActivityTypeIssue:             "🐛",
+		activity.ActivityTypeJiraTicket:        "🎯",
+		activity.ActivityTypeJiraResolved:      "🏁",
+		activity.ActivityTypeNote:              "📄",
+		activity.ActivityTypeMeeting:           "📅",
+		activity.ActivityTypeTaskCompleted:     "☑️",
+		activity.ActivityTypeConfluenceCreated: "🆕",
+		activity.ActivityTypeCI:                "⚙️",
+		activity.ActivityTypeDeployment:        "🚀",
+		activity.ActivityTypeWorklog:           "⏱️",
+		activity.ActivityTypeMention:           "💬",
+		activity.ActivityTypeSavedQuery:        "🔢",
+		activity.ActivityTypeComment:           "🗨️",
+		activity.ActivityTypeReview:            "👍",
+		activity.ActivityTypeMessage:           "🧵",
 	}
 
 	if icon, exists := icons[actType]; exists {
@@ -280,6 +464,63 @@ func (f *Formatter) getTypeIcon(actType activity.ActivityType) string {
 	return "📋"
 }
 
+// getActivityIcon returns the icon of an activity: its issue type for JIRA tickets and its
+// content type for Confluence contributions when the type has an icon, its activity type otherwise
+func (f *Formatter) getActivityIcon(act activity.Activity) string {
+	if act.Type == activity.ActivityTypeJiraTicket {
+		if icon := issueTypeIcon(act.IssueType); icon != "" {
+			return icon
+		}
+	}
+	if act.Type == activity.ActivityTypeConfluenceContribution {
+		if icon := contentTypeIcon(act.Detail(activity.DetailContentType)); icon != "" {
+			return icon
+		}
+	}
+	return f.getTypeIcon(act.Type)
+}
+
+// contentTypeIcon returns the icon of a Confluence content type, or "" for other types
+func contentTypeIcon(contentType string) string {
+	switch contentType {
+	case "page":
+		return "📄"
+	case "blogpost":
+		return "📰"
+	case "comment":
+		return "💬"
+	default:
+		return ""
+	}
+}
+
+// issueTypeIcon returns the icon of a JIRA issue type, or "" for other types
+func issueTypeIcon(issueType string) string {
+	switch strings.ToLower(issueType) {
+	case "bug":
+		return "🐛"
+	case "story":
+		return "📖"
+	case "task", "sub-task", "subtask":
+		return "✅"
+	default:
+		return ""
+	}
+}
+
+// formatGoals renders goal progress as "🎯 commits 3/5 · reviews 4/3 ✅", or "" without goals
+func formatGoals(goals []activity.GoalProgress) string {
+	if len(goals) == 0 {
+		return ""
+	}
+
+	parts := make([]string, len(goals))
+	for i, goal := range goals {
+		parts[i] = goal.String()
+	}
+	return "🎯 " + strings.Join(parts, " · ")
+}
+
 func (f *Formatter) FormatCompactSummary(summary *activity.Summary) string {
 	if len(summary.Activities) == 0 {
 		return f.headerStyle.Render("No activities found for this date.")
@@ -296,13 +537,32 @@ func (f *Formatter) FormatCompactSummary(summary *activity.Summary) string {
 
 	// Header with styling
 	header := fmt.Sprintf("Daily Summary - %d activities:", len(activities))
+	if summary.Period != "" {
+		header = fmt.Sprintf("Daily Summary - %d activities (%s):", len(activities), summary.Period)
+	}
 	output.WriteString(f.titleStyle.Render(header))
-	output.WriteString("\n\n")
+	output.WriteString("\n")
+	if goals := formatGoals(summary.Goals); goals != "" {
+		output.WriteString(goals)
+		output.WriteString("\n")
+	}
+	shown, omitted := f.limitActivities(activities)
+	if omitted > 0 {
+		output.WriteString(truncationNotice(len(shown), len(activities)))
+		output.WriteString("\n")
+	}
+	output.WriteString("\n")
 
-	for _, act := range activities {
-		timeStr := f.timeStyle.Render(act.Timestamp.Format("15:04"))
+	// Times alone are ambiguous when the summary spans several days
+	timeLayout := "15:04"
+	if summary.SpansMultipleDays() {
+		timeLayout = "Jan 2 15:04"
+	}
+
+	for _, act := range shown {
+		timeStr := f.timeStyle.Render(act.Timestamp.Format(timeLayout))
 		platformIcon := f.getPlatformIcon(act.Platform)
-		typeIcon := f.getTypeIcon(act.Type)
+		typeIcon := f.getActivityIcon(act)
 		platformStr := fmt.Sprintf("%s %s", platformIcon, act.Platform)
 		output.WriteString(fmt.Sprintf("%s %s %s %s\n", timeStr, typeIcon, platformStr, act.Title))
 	}
@@ -310,45 +570,39 @@ func (f *Formatter) FormatCompactSummary(summary *activity.Summary) string {
 	return output.String()
 }
 
+// FormatJSON formats a summary as JSON, see WriteJSON
 func (f *Formatter) FormatJSON(summary *activity.Summary) string {
-	// Sort activities by timestamp for consistent output
-	activities := make([]activity.Activity, len(summary.Activities))
-	copy(activities, summary.Activities)
-	sort.Slice(activities, func(i, j int) bool {
-		return activities[i].Timestamp.Before(activities[j].Timestamp)
-	})
-
-	// Create a JSON-friendly structure
-	jsonOutput := struct {
-		Date       string              `json:"date"`
-		Activities []activity.Activity `json:"activities"`
-		Summary    struct {
-			Total      int            `json:"total"`
-			ByPlatform map[string]int `json:"by_platform"`
-			ByType     map[string]int `json:"by_type"`
-		} `json:"summary"`
-	}{
-		Date:       summary.Date.Format("2006-01-02"),
-		Activities: activities,
+	var output strings.Builder
+	if err := f.WriteJSON(&output, summary); err != nil {
+		return fmt.Sprintf(`{"error": "Failed to marshal JSON: %s"}`, err.Error())
 	}
+	return output.String()
+}
 
-	// Calculate summary statistics
-	jsonOutput.Summary.Total = len(activities)
-	jsonOutput.Summary.ByPlatform = make(map[string]int)
-	jsonOutput.Summary.ByType = make(map[string]int)
-
+// timeLoggedByDay sums the time logged by worklog activities per day
+func timeLoggedByDay(activities []activity.Activity) map[string]int {
+	var totals map[string]int
 	for _, act := range activities {
-		jsonOutput.Summary.ByPlatform[act.Platform]++
-		jsonOutput.Summary.ByType[string(act.Type)]++
+		if act.TimeSpentSeconds == 0 {
+			continue
+		}
+		if totals == nil {
+			totals = make(map[string]int)
+		}
+		totals[act.Timestamp.Format("2006-01-02")] += act.TimeSpentSeconds
 	}
+	return totals
+}
 
-	// Marshal to JSON with proper indentation
-	jsonBytes, err := json.MarshalIndent(jsonOutput, "", "  ")
-	if err != nil {
-		return fmt.Sprintf(`{"error": "Failed to marshal JSON: %s"}`, err.Error())
+// meetingHours sums the duration of meeting activities, in hours rounded to the hundredth
+func meetingHours(activities []activity.Activity) float64 {
+	seconds := 0
+	for _, act := range activities {
+		if act.Type == activity.ActivityTypeMeeting {
+			seconds += act.DurationSeconds
+		}
 	}
-
-	return string(jsonBytes) + "\n"
+	return math.Round(float64(seconds)/36) / 100
 }
 
 // FormatTodo formats todo items for text output
@@ -360,7 +614,11 @@ func (f *Formatter) FormatTodo(todoItems TodoItems) string {
 	output.WriteString(f.titleStyle.Render(title))
 	output.WriteString("\n")
 
-	totalItems := len(todoItems.GitHub.OpenPRs) + len(todoItems.GitHub.PendingReviews) + len(todoItems.JIRA.AssignedTickets) + len(todoItems.Obsidian.Tasks) + len(todoItems.Confluence.Mentions)
+	totalItems := len(todoItems.GitHub.OpenPRs) + len(todoItems.GitHub.PendingReviews) + len(todoItems.JIRA.AssignedTickets) + len(todoItems.JIRA.Mentions) +
+		len(todoItems.JIRA.Reported) + len(todoItems.JIRA.Watched) + len(todoItems.Obsidian.Tasks) + len(todoItems.Confluence.Mentions) +
+		len(todoItems.Confluence.CommentsOnMyPages) + len(todoItems.Confluence.Tasks) + len(todoItems.Confluence.Watched) +
+		len(todoItems.GitLab.OpenMRs) + len(todoItems.GitLab.PendingReviews) + len(todoItems.GitLab.AssignedIssues) +
+		len(todoItems.Slack.Saved)
 	if totalItems == 0 {
 		output.WriteString(f.headerStyle.Render("No pending items found."))
 		output.WriteString("\n")
@@ -373,27 +631,81 @@ func (f *Formatter) FormatTodo(todoItems TodoItems) string {
 
 	// GitHub Open PRs
 	if len(todoItems.GitHub.OpenPRs) > 0 {
-		output.WriteString(f.formatTodoSection("🐙 Open Pull Requests", todoItems.GitHub.OpenPRs))
+		output.WriteString(f.formatTodoSection("🐙 Open Pull Requests", sortOpenPRs(todoItems.GitHub.OpenPRs)))
 	}
 
 	// GitHub Pending Reviews
 	if len(todoItems.GitHub.PendingReviews) > 0 {
-		output.WriteString(f.formatTodoSection("👁️ Pending Reviews", todoItems.GitHub.PendingReviews))
+		output.WriteString(f.formatTodoSection("👁️ Pending Reviews", sortTodoItems(todoItems.GitHub.PendingReviews)))
+	}
+
+	// GitLab Open MRs
+	if len(todoItems.GitLab.OpenMRs) > 0 {
+		output.WriteString(f.formatTodoSection("🦊 Open Merge Requests", sortTodoItems(todoItems.GitLab.OpenMRs)))
+	}
+
+	// GitLab merge requests awaiting the user's approval
+	if len(todoItems.GitLab.PendingReviews) > 0 {
+		output.WriteString(f.formatTodoSection("👁️ Pending MR Reviews", sortTodoItems(todoItems.GitLab.PendingReviews)))
 	}
 
-	// JIRA Assigned Tickets
-	if len(todoItems.JIRA.AssignedTickets) > 0 {
-		output.WriteString(f.formatTodoSection("🎫 Assigned Tickets", todoItems.JIRA.AssignedTickets))
+	// GitLab Assigned Issues
+	if len(todoItems.GitLab.AssignedIssues) > 0 {
+		output.WriteString(f.formatTodoSection("🦊 Assigned GitLab Issues", sortTodoItems(todoItems.GitLab.AssignedIssues)))
+	}
+
+	// JIRA Assigned Tickets, grouped by status section
+	for _, group := range groupTicketsBySection(sortTodoItemsByDueDate(todoItems.JIRA.AssignedTickets)) {
+		title := "🎫 Assigned Tickets"
+		if group.section != "" {
+			title = fmt.Sprintf("%s · %s", title, group.section)
+		}
+		output.WriteString(f.formatTodoSection(title, group.items))
+	}
+
+	// JIRA Mentions
+	if len(todoItems.JIRA.Mentions) > 0 {
+		output.WriteString(f.formatTodoSection("💬 JIRA Mentions", sortTodoItems(todoItems.JIRA.Mentions)))
+	}
+
+	// JIRA Reported Issues
+	if len(todoItems.JIRA.Reported) > 0 {
+		output.WriteString(f.formatTodoSection("📣 Reported Issues", sortTodoItems(todoItems.JIRA.Reported)))
+	}
+
+	// JIRA Watched Issues
+	if len(todoItems.JIRA.Watched) > 0 {
+		output.WriteString(f.formatTodoSection("👀 Watched Issues", sortTodoItems(todoItems.JIRA.Watched)))
 	}
 
 	// Obsidian Tasks
 	if len(todoItems.Obsidian.Tasks) > 0 {
-		output.WriteString(f.formatTodoSection("📝 Obsidian Tasks", todoItems.Obsidian.Tasks))
+		output.WriteString(f.formatTodoSection("📝 Obsidian Tasks", sortTasksByPriority(todoItems.Obsidian.Tasks)))
 	}
 
 	// Confluence Mentions
 	if len(todoItems.Confluence.Mentions) > 0 {
-		output.WriteString(f.formatTodoSection("📋 Confluence Mentions", todoItems.Confluence.Mentions))
+		output.WriteString(f.formatTodoSection("📋 Confluence Mentions", sortTodoItems(todoItems.Confluence.Mentions)))
+	}
+
+	// Comments on the user's Confluence pages
+	if len(todoItems.Confluence.CommentsOnMyPages) > 0 {
+		output.WriteString(f.formatTodoSection("💭 Comments on My Pages", sortTodoItems(todoItems.Confluence.CommentsOnMyPages)))
+	}
+
+	// Inline tasks of Confluence pages assigned to the user
+	if len(todoItems.Confluence.Tasks) > 0 {
+		output.WriteString(f.formatTodoSection("☑️ Confluence Tasks", sortTodoItems(todoItems.Confluence.Tasks)))
+	}
+
+	// Watched Confluence pages updated by others
+	if len(todoItems.Confluence.Watched) > 0 {
+		output.WriteString(f.formatTodoSection("🔄 Watched Pages", sortTodoItems(todoItems.Confluence.Watched)))
+	}
+
+	// Slack messages saved for later
+	if len(todoItems.Slack.Saved) > 0 {
+		output.WriteString(f.formatTodoSection("🔖 Saved Slack Messages", sortTodoItems(todoItems.Slack.Saved)))
 	}
 
 	return output.String()
@@ -411,14 +723,7 @@ func (f *Formatter) formatTodoSection(sectionTitle string, items []TodoItem) str
 	section.WriteString(f.borderStyle.Render(border))
 	section.WriteString("\n")
 
-	// Sort items by updated time (most recent first)
-	sortedItems := make([]TodoItem, len(items))
-	copy(sortedItems, items)
-	sort.Slice(sortedItems, func(i, j int) bool {
-		return sortedItems[i].UpdatedAt.After(sortedItems[j].UpdatedAt)
-	})
-
-	for _, item := range sortedItems {
+	for _, item := range items {
 		section.WriteString(f.formatTodoItem(item))
 	}
 
@@ -431,7 +736,18 @@ func (f *Formatter) formatTodoItem(item TodoItem) string {
 
 	// Updated time and title
 	timeStr := f.timeStyle.Render(item.UpdatedAt.Format("Jan 2 15:04"))
-	mainLine := fmt.Sprintf("%s  %s", timeStr, item.Title)
+	title := item.Title + types.SubtasksSuffix(len(item.Subtasks))
+	if icon := issueTypeIcon(item.IssueType); icon != "" {
+		title = icon + " " + title
+	}
+	if item.IsOverdue {
+		title = "⚠️  " + title
+	}
+	if item.ReadyToMerge {
+		title = "🎉 " + title
+	}
+	title = f.severityTitle(title, item.Severity)
+	mainLine := fmt.Sprintf("%s  %s", timeStr, title)
 	itemContent.WriteString(mainLine)
 	itemContent.WriteString("\n")
 
@@ -441,6 +757,11 @@ func (f *Formatter) formatTodoItem(item TodoItem) string {
 		itemContent.WriteString("\n")
 	}
 
+	if planning := formatTodoPlanning(item); planning != "" {
+		itemContent.WriteString(f.descriptionStyle.Render(planning))
+		itemContent.WriteString("\n")
+	}
+
 	if item.URL != "" {
 		url := f.urlStyle.Render("🔗 " + item.URL)
 		itemContent.WriteString(url)
@@ -453,22 +774,145 @@ func (f *Formatter) formatTodoItem(item TodoItem) string {
 		itemContent.WriteString("\n")
 	}
 
+	for _, subtask := range item.Subtasks {
+		itemContent.WriteString(f.descriptionStyle.Render(fmt.Sprintf("  ↳ %s · %s", subtask.Title, subtask.Description)))
+		itemContent.WriteString("\n")
+	}
+
 	// Wrap the entire item in the activity style
 	return f.activityStyle.Render(itemContent.String())
 }
 
-// FormatTodoJSON formats todo items for JSON output
-func (f *Formatter) FormatTodoJSON(todoItems TodoItems) string {
-	// Sort all items by updated time for consistent output
-	sortTodoItems := func(items []TodoItem) []TodoItem {
-		sorted := make([]TodoItem, len(items))
-		copy(sorted, items)
-		sort.Slice(sorted, func(i, j int) bool {
-			return sorted[i].UpdatedAt.After(sorted[j].UpdatedAt)
-		})
-		return sorted
+// severityTitle colors a title by the severity of its item. Without colors, the title is
+// prefixed with the marker of the severity instead, e.g. "[CRIT] Outage".
+func (f *Formatter) severityTitle(title, level string) string {
+	if level == "" {
+		return title
+	}
+	if style, ok := f.severityStyles[severity.Level(level)]; ok {
+		return style.Render(title)
 	}
+	if marker := severity.Level(level).Marker(); marker != "" {
+		return marker + " " + title
+	}
+	return title
+}
+
+// formatTodoPlanning describes the priority, due date and sprint of an item, e.g. "📅 Priority: High • Due Jan 20 • Sprint 5"
+func formatTodoPlanning(item TodoItem) string {
+	var parts []string
+	if item.Priority != "" {
+		parts = append(parts, "Priority: "+item.Priority)
+	}
+	if item.DueDate != nil {
+		due := "Due " + item.DueDate.Format("Jan 2")
+		if item.IsOverdue {
+			due += " (overdue)"
+		}
+		parts = append(parts, due)
+	}
+	if item.Sprint != "" {
+		parts = append(parts, item.Sprint)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "📅 " + strings.Join(parts, " • ")
+}
 
+// sortTodoItems returns a copy of items sorted by updated time (most recent first)
+func sortTodoItems(items []TodoItem) []TodoItem {
+	sorted := make([]TodoItem, len(items))
+	copy(sorted, items)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].UpdatedAt.After(sorted[j].UpdatedAt)
+	})
+	return sorted
+}
+
+// sortOpenPRs returns a copy of open PRs sorted with the ones ready to merge first, then by
+// updated time
+func sortOpenPRs(items []TodoItem) []TodoItem {
+	sorted := sortTodoItems(items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ReadyToMerge && !sorted[j].ReadyToMerge
+	})
+	return sorted
+}
+
+// sortTodoItemsByDueDate returns a copy of items sorted by due date (soonest first),
+// followed by the items without a due date sorted by updated time
+func sortTodoItemsByDueDate(items []TodoItem) []TodoItem {
+	sorted := sortTodoItems(items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].DueDate, sorted[j].DueDate
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.Before(*b)
+	})
+	return sorted
+}
+
+// sortTasksByPriority returns a copy of Obsidian tasks sorted by priority, then by due date
+// (soonest first, tasks without one last), then by updated time
+func sortTasksByPriority(items []TodoItem) []TodoItem {
+	sorted := sortTodoItemsByDueDate(items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return types.TaskPriorityRank(sorted[i].Priority) < types.TaskPriorityRank(sorted[j].Priority)
+	})
+	return sorted
+}
+
+// statusSectionOrder lists the default JIRA status sections in display order.
+// Custom sections follow in alphabetical order, then tickets without a section.
+var statusSectionOrder = []string{"To Do", "In Progress", "Blocked"}
+
+// ticketGroup holds the assigned tickets of a status section
+type ticketGroup struct {
+	section string
+	items   []TodoItem
+}
+
+// groupTicketsBySection splits tickets by status section, keeping their order within each group
+func groupTicketsBySection(items []TodoItem) []ticketGroup {
+	bySection := make(map[string][]TodoItem)
+	for _, item := range items {
+		bySection[item.StatusSection] = append(bySection[item.StatusSection], item)
+	}
+
+	rank := func(section string) int {
+		for i, s := range statusSectionOrder {
+			if s == section {
+				return i
+			}
+		}
+		if section == "" {
+			return len(statusSectionOrder) + 1
+		}
+		return len(statusSectionOrder)
+	}
+
+	sections := make([]string, 0, len(bySection))
+	for section := range bySection {
+		sections = append(sections, section)
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		if rank(sections[i]) != rank(sections[j]) {
+			return rank(sections[i]) < rank(sections[j])
+		}
+		return sections[i] < sections[j]
+	})
+
+	groups := make([]ticketGroup, len(sections))
+	for i, section := range sections {
+		groups[i] = ticketGroup{section: section, items: bySection[section]}
+	}
+	return groups
+}
+
+// FormatTodoJSON formats todo items for JSON output
+func (f *Formatter) FormatTodoJSON(todoItems TodoItems) string {
 	jsonOutput := struct {
 		GitHub struct {
 			OpenPRs        []TodoItem `json:"open_prs"`
@@ -476,37 +920,88 @@ func (f *Formatter) FormatTodoJSON(todoItems TodoItems) string {
 		} `json:"github"`
 		JIRA struct {
 			AssignedTickets []TodoItem `json:"assigned_tickets"`
+			Mentions        []TodoItem `json:"mentions"`
+			Reported        []TodoItem `json:"reported,omitempty"`
+			Watched         []TodoItem `json:"watched,omitempty"`
 		} `json:"jira"`
 		Obsidian struct {
 			Tasks []TodoItem `json:"tasks"`
 		} `json:"obsidian"`
 		Confluence struct {
-			Mentions []TodoItem `json:"mentions"`
+			Mentions          []TodoItem `json:"mentions"`
+			CommentsOnMyPages []TodoItem `json:"comments_on_my_pages"`
+			Tasks             []TodoItem `json:"tasks"`
+			Watched           []TodoItem `json:"watched,omitempty"`
 		} `json:"confluence"`
+		GitLab struct {
+			OpenMRs        []TodoItem `json:"open_mrs"`
+			PendingReviews []TodoItem `json:"pending_reviews"`
+			AssignedIssues []TodoItem `json:"assigned_issues"`
+		} `json:"gitlab"`
+		Slack struct {
+			Saved []TodoItem `json:"saved"`
+		} `json:"slack"`
 		Summary struct {
 			Total              int `json:"total"`
 			OpenPRs            int `json:"open_prs"`
 			PendingReviews     int `json:"pending_reviews"`
 			AssignedTickets    int `json:"assigned_tickets"`
+			JIRAMentions       int `json:"jira_mentions"`
+			ReportedIssues     int `json:"reported_issues"`
+			WatchedIssues      int `json:"watched_issues"`
 			ObsidianTasks      int `json:"obsidian_tasks"`
 			ConfluenceMentions int `json:"confluence_mentions"`
+			ConfluenceComments int `json:"confluence_comments"`
+			ConfluenceTasks    int `json:"confluence_tasks"`
+			WatchedPages       int `json:"watched_pages"`
+			OpenMRs            int `json:"open_mrs"`
+			PendingMRReviews   int `json:"pending_mr_reviews"`
+			GitLabIssues       int `json:"gitlab_issues"`
+			SavedMessages      int `json:"saved_messages"`
 		} `json:"summary"`
-	}{}
+		Meta *metrics.Report `json:"meta,omitempty"`
+	}{
+		Meta: f.meta,
+	}
 
 	// Sort and assign items
-	jsonOutput.GitHub.OpenPRs = sortTodoItems(todoItems.GitHub.OpenPRs)
+	jsonOutput.GitHub.OpenPRs = sortOpenPRs(todoItems.GitHub.OpenPRs)
 	jsonOutput.GitHub.PendingReviews = sortTodoItems(todoItems.GitHub.PendingReviews)
-	jsonOutput.JIRA.AssignedTickets = sortTodoItems(todoItems.JIRA.AssignedTickets)
-	jsonOutput.Obsidian.Tasks = sortTodoItems(todoItems.Obsidian.Tasks)
+	jsonOutput.JIRA.AssignedTickets = sortTodoItemsByDueDate(todoItems.JIRA.AssignedTickets)
+	jsonOutput.JIRA.Mentions = sortTodoItems(todoItems.JIRA.Mentions)
+	jsonOutput.JIRA.Reported = sortTodoItems(todoItems.JIRA.Reported)
+	jsonOutput.JIRA.Watched = sortTodoItems(todoItems.JIRA.Watched)
+	jsonOutput.Obsidian.Tasks = sortTasksByPriority(todoItems.Obsidian.Tasks)
 	jsonOutput.Confluence.Mentions = sortTodoItems(todoItems.Confluence.Mentions)
+	jsonOutput.Confluence.CommentsOnMyPages = sortTodoItems(todoItems.Confluence.CommentsOnMyPages)
+	jsonOutput.Confluence.Tasks = sortTodoItems(todoItems.Confluence.Tasks)
+	jsonOutput.Confluence.Watched = sortTodoItems(todoItems.Confluence.Watched)
+	jsonOutput.GitLab.OpenMRs = sortTodoItems(todoItems.GitLab.OpenMRs)
+	jsonOutput.GitLab.PendingReviews = sortTodoItems(todoItems.GitLab.PendingReviews)
+	jsonOutput.GitLab.AssignedIssues = sortTodoItems(todoItems.GitLab.AssignedIssues)
+	jsonOutput.Slack.Saved = sortTodoItems(todoItems.Slack.Saved)
 
 	// Calculate summary
 	jsonOutput.Summary.OpenPRs = len(todoItems.GitHub.OpenPRs)
 	jsonOutput.Summary.PendingReviews = len(todoItems.GitHub.PendingReviews)
 	jsonOutput.Summary.AssignedTickets = len(todoItems.JIRA.AssignedTickets)
+	jsonOutput.Summary.JIRAMentions = len(todoItems.JIRA.Mentions)
+	jsonOutput.Summary.ReportedIssues = len(todoItems.JIRA.Reported)
+	jsonOutput.Summary.WatchedIssues = len(todoItems.JIRA.Watched)
 	jsonOutput.Summary.ObsidianTasks = len(todoItems.Obsidian.Tasks)
 	jsonOutput.Summary.ConfluenceMentions = len(todoItems.Confluence.Mentions)
-	jsonOutput.Summary.Total = jsonOutput.Summary.OpenPRs + jsonOutput.Summary.PendingReviews + jsonOutput.Summary.AssignedTickets + jsonOutput.Summary.ObsidianTasks + jsonOutput.Summary.ConfluenceMentions
+	jsonOutput.Summary.ConfluenceComments = len(todoItems.Confluence.CommentsOnMyPages)
+	jsonOutput.Summary.ConfluenceTasks = len(todoItems.Confluence.Tasks)
+	jsonOutput.Summary.WatchedPages = len(todoItems.Confluence.Watched)
+	jsonOutput.Summary.OpenMRs = len(todoItems.GitLab.OpenMRs)
+	jsonOutput.Summary.PendingMRReviews = len(todoItems.GitLab.PendingReviews)
+	jsonOutput.Summary.GitLabIssues = len(todoItems.GitLab.AssignedIssues)
+	jsonOutput.Summary.SavedMessages = len(todoItems.Slack.Saved)
+	jsonOutput.Summary.Total = jsonOutput.Summary.OpenPRs + jsonOutput.Summary.PendingReviews + jsonOutput.Summary.AssignedTickets + jsonOutput.Summary.JIRAMentions +
+		jsonOutput.Summary.ReportedIssues + jsonOutput.Summary.WatchedIssues + jsonOutput.Summary.ObsidianTasks + jsonOutput.Summary.ConfluenceMentions +
+		jsonOutput.Summary.ConfluenceComments + jsonOutput.Summary.ConfluenceTasks + jsonOutput.Summary.WatchedPages +
+		jsonOutput.Summary.OpenMRs + jsonOutput.Summary.PendingMRReviews + jsonOutput.Summary.GitLabIssues +
+		jsonOutput.Summary.SavedMessages
 
 	// Marshal to JSON with proper indentation
 	jsonBytes, err := json.MarshalIndent(jsonOutput, "", "  ")
@@ -517,30 +1012,27 @@ func (f *Formatter) FormatTodoJSON(todoItems TodoItems) string {
 	return string(jsonBytes) + "\n"
 }
 
-// FormatTodoTUI launches an interactive TUI for browsing todo items
+// runTodoTUI and runReviewsTUI start the TUIs, overridden in tests
+var (
+	runTodoTUI    = tui.RunTodoTUI
+	runReviewsTUI = tui.RunReviewsTUI
+)
+
+// FormatTodoTUI launches an interactive TUI for browsing todo items. When the TUI crashes,
+// the items are printed as text instead.
 func (f *Formatter) FormatTodoTUI(todoItems TodoItems) error {
 	// Convert output types to tui types to avoid import cycle
 	tuiTodoItems := f.convertToTUITypes(todoItems)
-	return tui.RunTodoTUI(tuiTodoItems)
+	err := runTodoTUI(tuiTodoItems)
+	if errors.Is(err, tui.ErrCrashed) {
+		fmt.Print(f.FormatTodo(todoItems))
+		return nil
+	}
+	return err
 }
 
 // convertToTUITypes converts output types to TUI types to avoid import cycles
 func (f *Formatter) convertToTUITypes(todoItems TodoItems) types.TodoItems {
-	convertTodoItems := func(items []TodoItem) []types.TodoItem {
-		result := make([]types.TodoItem, len(items))
-		for i, item := range items {
-			result[i] = types.TodoItem{
-				ID:          item.ID,
-				Title:       item.Title,
-				Description: item.Description,
-				URL:         item.URL,
-				UpdatedAt:   item.UpdatedAt,
-				Tags:        item.Tags,
-			}
-		}
-		return result
-	}
-
 	return types.TodoItems{
 		GitHub: types.GitHubTodos{
 			OpenPRs:        convertTodoItems(todoItems.GitHub.OpenPRs),
@@ -548,16 +1040,67 @@ func (f *Formatter) convertToTUITypes(todoItems TodoItems) types.TodoItems {
 		},
 		JIRA: types.JIRATodos{
 			AssignedTickets: convertTodoItems(todoItems.JIRA.AssignedTickets),
+			Mentions:        convertTodoItems(todoItems.JIRA.Mentions),
+			Reported:        convertTodoItems(todoItems.JIRA.Reported),
+			Watched:         convertTodoItems(todoItems.JIRA.Watched),
 		},
 		Obsidian: types.ObsidianTodos{
 			Tasks: convertTodoItems(todoItems.Obsidian.Tasks),
 		},
 		Confluence: types.ConfluenceTodos{
-			Mentions: convertTodoItems(todoItems.Confluence.Mentions),
+			Mentions:          convertTodoItems(todoItems.Confluence.Mentions),
+			CommentsOnMyPages: convertTodoItems(todoItems.Confluence.CommentsOnMyPages),
+			Tasks:             convertTodoItems(todoItems.Confluence.Tasks),
+			Watched:           convertTodoItems(todoItems.Confluence.Watched),
+		},
+		GitLab: types.GitLabTodos{
+			OpenMRs:        convertTodoItems(todoItems.GitLab.OpenMRs),
+			PendingReviews: convertTodoItems(todoItems.GitLab.PendingReviews),
+			AssignedIssues: convertTodoItems(todoItems.GitLab.AssignedIssues),
+		},
+		Slack: types.SlackTodos{
+			Saved: convertTodoItems(todoItems.Slack.Saved),
 		},
 	}
 }
 
+// convertTodoItems converts todo items and their subtasks to the TUI types
+func convertTodoItems(items []TodoItem) []types.TodoItem {
+	result := make([]types.TodoItem, len(items))
+	for i, item := range items {
+		result[i] = types.TodoItem{
+			ID:            item.ID,
+			Title:         item.Title,
+			Description:   item.Description,
+			URL:           item.URL,
+			UpdatedAt:     item.UpdatedAt,
+			Tags:          item.Tags,
+			Milestone:     item.Milestone,
+			ProjectStatus: item.ProjectStatus,
+			Priority:      item.Priority,
+			DueDate:       item.DueDate,
+			Sprint:        item.Sprint,
+			IssueType:     item.IssueType,
+			IsOverdue:     item.IsOverdue,
+			ReadyToMerge:  item.ReadyToMerge,
+
+			StatusCategory: item.StatusCategory,
+			StatusSection:  item.StatusSection,
+			Comments:       convertComments(item.Comments),
+			Subtasks:       convertTodoItems(item.Subtasks),
+			Context:        item.Context,
+			ParentTask:     item.ParentTask,
+			Snippet:        item.Snippet,
+			RelatedNotes:   item.RelatedNotes,
+			Excerpt:        item.Excerpt,
+			Details:        item.Details,
+			Severity:       item.Severity,
+			Provenance:     convertProvenance(item.Provenance),
+		}
+	}
+	return result
+}
+
 // FormatReview formats review items for text output
 func (f *Formatter) FormatReview(reviewItems ReviewItems) string {
 	var output strings.Builder
@@ -567,7 +1110,7 @@ func (f *Formatter) FormatReview(reviewItems ReviewItems) string {
 	output.WriteString(f.titleStyle.Render(title))
 	output.WriteString("\n")
 
-	totalItems := len(reviewItems.GitHub.UserRequests) + len(reviewItems.GitHub.TeamRequests)
+	totalItems := len(reviewItems)
 	if totalItems == 0 {
 		output.WriteString(f.headerStyle.Render("No review requests found."))
 		output.WriteString("\n")
@@ -579,13 +1122,13 @@ func (f *Formatter) FormatReview(reviewItems ReviewItems) string {
 	output.WriteString("\n\n")
 
 	// User Review Requests
-	if len(reviewItems.GitHub.UserRequests) > 0 {
-		output.WriteString(f.formatReviewSection("🫵 Direct Review Requests", reviewItems.GitHub.UserRequests))
+	if userRequests := reviewItems.ByRequestType(ReviewRequestUser); len(userRequests) > 0 {
+		output.WriteString(f.formatReviewSection("🫵 Direct Review Requests", userRequests))
 	}
 
 	// Team Review Requests
-	if len(reviewItems.GitHub.TeamRequests) > 0 {
-		output.WriteString(f.formatReviewSection("👥 Team Review Requests", reviewItems.GitHub.TeamRequests))
+	if teamRequests := reviewItems.ByRequestType(ReviewRequestTeam); len(teamRequests) > 0 {
+		output.WriteString(f.formatReviewSection("👥 Team Review Requests", teamRequests))
 	}
 
 	return output.String()
@@ -603,10 +1146,16 @@ func (f *Formatter) formatReviewSection(sectionTitle string, items []ReviewItem)
 	section.WriteString(f.borderStyle.Render(border))
 	section.WriteString("\n")
 
-	// Sort items by updated time (most recent first)
+	// Sort items queued to be merged last and stale reviews first, then by updated time (most recent first)
 	sortedItems := make([]ReviewItem, len(items))
 	copy(sortedItems, items)
-	sort.Slice(sortedItems, func(i, j int) bool {
+	sort.SliceStable(sortedItems, func(i, j int) bool {
+		if sortedItems[i].InMergeQueue != sortedItems[j].InMergeQueue {
+			return sortedItems[j].InMergeQueue
+		}
+		if sortedItems[i].IsStale != sortedItems[j].IsStale {
+			return sortedItems[i].IsStale
+		}
 		return sortedItems[i].TodoItem.UpdatedAt.After(sortedItems[j].TodoItem.UpdatedAt)
 	})
 
@@ -624,13 +1173,29 @@ func (f *Formatter) formatReviewItem(item ReviewItem) string {
 	// Updated time and title
 	timeStr := f.timeStyle.Render(item.TodoItem.UpdatedAt.Format("Jan 2 15:04"))
 
-	// CI status indicator
+	// CI status indicator, followed by the stale and merge queue markers, like the TUI
 	ciIcon := f.getCIStatusIcon(item.CIStatus.State)
+	if item.IsStale {
+		ciIcon += " 🔥"
+	}
+	if item.InMergeQueue {
+		ciIcon += " 🚦"
+	}
 
-	mainLine := fmt.Sprintf("%s %s %s", timeStr, ciIcon, item.TodoItem.Title)
+	title := f.severityTitle(item.TodoItem.Title, item.TodoItem.Severity)
+	mainLine := fmt.Sprintf("%s %s %s", timeStr, ciIcon, title)
+	if item.Base != "" {
+		mainLine += f.faintStyle.Render(" → " + item.Base)
+	}
 	itemContent.WriteString(mainLine)
 	itemContent.WriteString("\n")
 
+	if item.IsStale {
+		waiting := f.descriptionStyle.Render(fmt.Sprintf("⏰ Waiting for %d days", item.AgeDays))
+		itemContent.WriteString(waiting)
+		itemContent.WriteString("\n")
+	}
+
 	if item.TodoItem.Description != "" {
 		description := f.descriptionStyle.Render(item.TodoItem.Description)
 		itemContent.WriteString(description)
@@ -686,35 +1251,40 @@ func (f *Formatter) getCIStatusIcon(state string) string {
 // FormatReviewJSON formats review items for JSON output
 func (f *Formatter) FormatReviewJSON(reviewItems ReviewItems) string {
 	// Sort all items by updated time for consistent output
-	sortReviewItems := func(items []ReviewItem) []ReviewItem {
-		sorted := make([]ReviewItem, len(items))
-		copy(sorted, items)
-		sort.Slice(sorted, func(i, j int) bool {
-			return sorted[i].TodoItem.UpdatedAt.After(sorted[j].TodoItem.UpdatedAt)
-		})
-		return sorted
-	}
+	sorted := make([]ReviewItem, len(reviewItems))
+	copy(sorted, reviewItems)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TodoItem.UpdatedAt.After(sorted[j].TodoItem.UpdatedAt)
+	})
 
 	jsonOutput := struct {
-		GitHub struct {
-			UserRequests []ReviewItem `json:"user_requests"`
-			TeamRequests []ReviewItem `json:"team_requests"`
-		} `json:"github"`
+		Version int          `json:"version"`
+		Reviews []ReviewItem `json:"reviews"`
 		Summary struct {
-			Total        int `json:"total"`
-			UserRequests int `json:"user_requests"`
-			TeamRequests int `json:"team_requests"`
+			Total        int            `json:"total"`
+			UserRequests int            `json:"user_requests"`
+			TeamRequests int            `json:"team_requests"`
+			ByPlatform   map[string]int `json:"by_platform"`
 		} `json:"summary"`
-	}{}
-
-	// Sort and assign items
-	jsonOutput.GitHub.UserRequests = sortReviewItems(reviewItems.GitHub.UserRequests)
-	jsonOutput.GitHub.TeamRequests = sortReviewItems(reviewItems.GitHub.TeamRequests)
+		Meta *metrics.Report `json:"meta,omitempty"`
+	}{
+		Version: ReviewJSONVersion,
+		Reviews: sorted,
+		Meta:    f.meta,
+	}
 
 	// Calculate summary
-	jsonOutput.Summary.UserRequests = len(reviewItems.GitHub.UserRequests)
-	jsonOutput.Summary.TeamRequests = len(reviewItems.GitHub.TeamRequests)
-	jsonOutput.Summary.Total = jsonOutput.Summary.UserRequests + jsonOutput.Summary.TeamRequests
+	jsonOutput.Summary.ByPlatform = make(map[string]int)
+	for _, item := range reviewItems {
+		switch item.RequestType {
+		case ReviewRequestUser:
+			jsonOutput.Summary.UserRequests++
+		case ReviewRequestTeam:
+			jsonOutput.Summary.TeamRequests++
+		}
+		jsonOutput.Summary.ByPlatform[item.Platform]++
+	}
+	jsonOutput.Summary.Total = len(reviewItems)
 
 	// Marshal to JSON with proper indentation
 	jsonBytes, err := json.MarshalIndent(jsonOutput, "", "  ")
@@ -725,30 +1295,37 @@ func (f *Formatter) FormatReviewJSON(reviewItems ReviewItems) string {
 	return string(jsonBytes) + "\n"
 }
 
-// FormatReviewTUI launches an interactive TUI for browsing review items
-func (f *Formatter) FormatReviewTUI(reviewItems ReviewItems) error {
-	// Convert output.ReviewItems to types.ReviewItems
-	typesReviewItems := types.ReviewItems{
-		GitHub: types.GitHubReviews{
-			UserRequests: convertReviewItems(reviewItems.GitHub.UserRequests),
-			TeamRequests: convertReviewItems(reviewItems.GitHub.TeamRequests),
-		},
+// FormatReviewTUI launches an interactive TUI for browsing review items. fetchComments
+// loads the comment thread previews and may be nil when comments aren't available. When the
+// TUI crashes, the review items are printed as text instead.
+func (f *Formatter) FormatReviewTUI(reviewItems ReviewItems, fetchComments CommentFetcher) error {
+	err := runReviewsTUI(convertReviewItems(reviewItems), convertCommentFetcher(fetchComments))
+	if errors.Is(err, tui.ErrCrashed) {
+		fmt.Print(f.FormatReview(reviewItems))
+		return nil
 	}
-	return tui.RunReviewsTUI(typesReviewItems)
+	return err
 }
 
-func convertReviewItems(items []ReviewItem) []types.ReviewItem {
-	result := make([]types.ReviewItem, len(items))
+func convertReviewItems(items ReviewItems) types.ReviewItems {
+	result := make(types.ReviewItems, len(items))
 	for i, item := range items {
 		result[i] = types.ReviewItem{
 			TodoItem: types.TodoItem{
-				ID:          item.TodoItem.ID,
-				Title:       item.TodoItem.Title,
-				Description: item.TodoItem.Description,
-				URL:         item.TodoItem.URL,
-				UpdatedAt:   item.TodoItem.UpdatedAt,
-				Tags:        item.TodoItem.Tags,
+				ID:            item.TodoItem.ID,
+				Title:         item.TodoItem.Title,
+				Description:   item.TodoItem.Description,
+				URL:           item.TodoItem.URL,
+				UpdatedAt:     item.TodoItem.UpdatedAt,
+				Tags:          item.TodoItem.Tags,
+				Milestone:     item.TodoItem.Milestone,
+				ProjectStatus: item.TodoItem.ProjectStatus,
+				Details:       item.TodoItem.Details,
+				Severity:      item.TodoItem.Severity,
+				Provenance:    convertProvenance(item.TodoItem.Provenance),
 			},
+			Platform:    item.Platform,
+			RequestType: item.RequestType,
 			CIStatus: types.CIStatus{
 				State:      item.CIStatus.State,
 				TotalCount: item.CIStatus.TotalCount,
@@ -759,11 +1336,67 @@ func convertReviewItems(items []ReviewItem) []types.ReviewItem {
 				Deletions:    item.PRDetails.Deletions,
 				ChangedFiles: item.PRDetails.ChangedFiles,
 			},
+			AgeDays:    item.AgeDays,
+			IsStale:    item.IsStale,
+			Base:       item.Base,
+			Repository: item.Repository,
+			Number:     item.Number,
+
+			InMergeQueue: item.InMergeQueue,
+		}
+	}
+	return result
+}
+
+// convertComments converts todo item comments to the TUI comment type
+func convertComments(comments []Comment) []types.Comment {
+	if len(comments) == 0 {
+		return nil
+	}
+	result := make([]types.Comment, len(comments))
+	for i, comment := range comments {
+		result[i] = types.Comment{
+			Author:    comment.Author,
+			Body:      comment.Body,
+			CreatedAt: comment.CreatedAt,
 		}
 	}
 	return result
 }
 
+// convertProvenance converts the provenance of an item to the TUI type
+func convertProvenance(provenance *Provenance) *types.Provenance {
+	if provenance == nil {
+		return nil
+	}
+	result := types.Provenance(*provenance)
+	return &result
+}
+
+// convertCommentFetcher adapts a comment fetcher to the TUI comment types
+func convertCommentFetcher(fetchComments CommentFetcher) types.CommentFetcher {
+	if fetchComments == nil {
+		return nil
+	}
+	return func(ctx context.Context, repo string, number, limit int) ([]types.PRComment, error) {
+		comments, err := fetchComments(ctx, repo, number, limit)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]types.PRComment, len(comments))
+		for i, comment := range comments {
+			result[i] = types.PRComment{
+				Author:    comment.Author,
+				Body:      comment.Body,
+				URL:       comment.URL,
+				CreatedAt: comment.CreatedAt,
+				Path:      comment.Path,
+			}
+		}
+		return result, nil
+	}
+}
+
 func convertCheckRuns(checks []CheckRun) []types.CheckRun {
 	result := make([]types.CheckRun, len(checks))
 	for i, check := range checks {
@@ -779,12 +1412,60 @@ func convertCheckRuns(checks []CheckRun) []types.CheckRun {
 
 // TodoItem represents a single todo item (avoiding import cycles)
 type TodoItem struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	URL         string    `json:"url,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Tags        []string  `json:"tags,omitempty"`
+	ID            string     `json:"id"`
+	Key           string     `json:"key,omitempty"` // Issue key of JIRA tickets
+	Title         string     `json:"title"`
+	TitleOriginal string     `json:"title_original,omitempty"` // Title as written, when Title was translated for display
+	Description   string     `json:"description"`
+	URL           string     `json:"url,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Tags          []string   `json:"tags,omitempty"`
+	Milestone     string     `json:"milestone,omitempty"`
+	ProjectStatus string     `json:"project_status,omitempty"`
+	Priority      string     `json:"priority,omitempty"`
+	DueDate       *time.Time `json:"due_date,omitempty"`
+	Sprint        string     `json:"sprint,omitempty"`
+	IssueType     string     `json:"issue_type,omitempty"`     // JIRA issue type, e.g. Bug, Story, Task
+	IsOverdue     bool       `json:"is_overdue,omitempty"`     // Due date has passed
+	ReadyToMerge  bool       `json:"ready_to_merge,omitempty"` // Open PR approved with passing checks and no conflict
+
+	StatusCategory string `json:"status_category,omitempty"` // JIRA status category: new, indeterminate or done
+	StatusSection  string `json:"status_section,omitempty"`  // Todo section of a JIRA ticket, e.g. "In Progress"
+
+	Comments []Comment `json:"comments,omitempty"` // Latest comments, newest first, when details are requested
+
+	Subtasks []TodoItem `json:"subtasks,omitempty"` // Pending subtasks rolled up under a JIRA ticket
+
+	Context    string `json:"context,omitempty"`     // Headings an Obsidian task is under, e.g. "Client X › Meeting 2024-05-12"
+	ParentTask string `json:"parent_task,omitempty"` // Task an indented Obsidian subtask is nested under
+	Line       int    `json:"line,omitempty"`        // Line number of an Obsidian task in its note, from 1
+	Snippet    string `json:"snippet,omitempty"`     // Lines around an Obsidian task in its note, other tasks left out
+
+	RelatedNotes []string `json:"related_notes,omitempty"` // Notes an Obsidian task links to, e.g. "Project Phoenix" for [[Project Phoenix]]
+
+	Excerpt string `json:"excerpt,omitempty"` // Text around a Confluence mention, or of a saved Slack message, at most 200 characters
+
+	Details  map[string]string `json:"details,omitempty"`  // Repo, project, space, status or state, see activity.DetailKeys
+	Severity string            `json:"severity,omitempty"` // critical, high, normal or low, from the priority, labels or tags
+
+	Provenance *Provenance `json:"provenance,omitempty"` // Why the item was listed
+}
+
+// Comment represents a snippet of a comment on a todo item
+type Comment struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Provenance records why an item was listed: where it was fetched from and with which query
+type Provenance struct {
+	Provider  string    `json:"provider"`
+	Query     string    `json:"query,omitempty"`  // Search query, JQL or CQL that returned the item
+	Team      string    `json:"team,omitempty"`   // Team (org/slug) whose review request listed the item
+	Filter    string    `json:"filter,omitempty"` // Configured filter included in the query
+	Source    string    `json:"source,omitempty"` // Where an item not returned by a query was found, e.g. a vault file
+	FetchedAt time.Time `json:"fetched_at"`
 }
 
 // TodoItems represents all pending work items
@@ -793,6 +1474,37 @@ type TodoItems struct {
 	JIRA       JIRATodos       `json:"jira"`
 	Obsidian   ObsidianTodos   `json:"obsidian"`
 	Confluence ConfluenceTodos `json:"confluence"`
+	GitLab     GitLabTodos     `json:"gitlab"`
+	Slack      SlackTodos      `json:"slack"`
+}
+
+// TodoSection is a list of todo items with the platform and up-next kind of its items. Items
+// points into the TodoItems it was returned from, so that a section can be replaced.
+type TodoSection struct {
+	Platform string // Provider of the items, e.g. "jira"
+	Kind     string // KindOpenPR, KindAssignedTicket...
+	Items    *[]TodoItem
+}
+
+// Sections returns every list of todo items, in the order they are displayed
+func (t *TodoItems) Sections() []TodoSection {
+	return []TodoSection{
+		{"github", KindOpenPR, &t.GitHub.OpenPRs},
+		{"github", KindPendingReview, &t.GitHub.PendingReviews},
+		{"jira", KindAssignedTicket, &t.JIRA.AssignedTickets},
+		{"jira", KindJIRAMention, &t.JIRA.Mentions},
+		{"jira", KindReportedIssue, &t.JIRA.Reported},
+		{"jira", KindWatchedIssue, &t.JIRA.Watched},
+		{"obsidian", KindObsidianTask, &t.Obsidian.Tasks},
+		{"confluence", KindConfluenceMention, &t.Confluence.Mentions},
+		{"confluence", KindConfluenceComment, &t.Confluence.CommentsOnMyPages},
+		{"confluence", KindConfluenceTask, &t.Confluence.Tasks},
+		{"confluence", KindWatchedPage, &t.Confluence.Watched},
+		{"gitlab", KindOpenMR, &t.GitLab.OpenMRs},
+		{"gitlab", KindPendingMRReview, &t.GitLab.PendingReviews},
+		{"gitlab", KindGitLabIssue, &t.GitLab.AssignedIssues},
+		{"slack", KindSavedMessage, &t.Slack.Saved},
+	}
 }
 
 // GitHubTodos represents pending GitHub work items
@@ -801,9 +1513,24 @@ type GitHubTodos struct {
 	PendingReviews []TodoItem `json:"pending_reviews"`
 }
 
+// GitLabTodos represents pending GitLab work items
+type GitLabTodos struct {
+	OpenMRs        []TodoItem `json:"open_mrs"`
+	PendingReviews []TodoItem `json:"pending_reviews"` // Merge requests the user reviews and hasn't approved
+	AssignedIssues []TodoItem `json:"assigned_issues"`
+}
+
+// SlackTodos represents pending Slack work items
+type SlackTodos struct {
+	Saved []TodoItem `json:"saved"` // Messages the user saved for later
+}
+
 // JIRATodos represents pending JIRA work items
 type JIRATodos struct {
 	AssignedTickets []TodoItem `json:"assigned_tickets"`
+	Mentions        []TodoItem `json:"mentions"`
+	Reported        []TodoItem `json:"reported,omitempty"` // Recently updated issues reported by the user, when enabled
+	Watched         []TodoItem `json:"watched,omitempty"`  // Recently updated issues watched by the user, when enabled
 }
 
 // ObsidianTodos represents pending Obsidian work items
@@ -813,27 +1540,67 @@ type ObsidianTodos struct {
 
 // ConfluenceTodos represents pending Confluence work items
 type ConfluenceTodos struct {
-	Mentions []TodoItem `json:"mentions"`
+	Mentions          []TodoItem `json:"mentions"`
+	CommentsOnMyPages []TodoItem `json:"comments_on_my_pages"` // Recent comments on pages created by the user
+	Tasks             []TodoItem `json:"tasks"`                // Incomplete inline tasks assigned to the user
+	Watched           []TodoItem `json:"watched,omitempty"`    // Recent updates by others of pages the user watches
 }
 
-// ReviewItems represents all review items
-type ReviewItems struct {
-	GitHub GitHubReviews `json:"github"`
-}
+// Review request types
+const (
+	ReviewRequestUser = "user" // The user was asked to review directly
+	ReviewRequestTeam = "team" // One of the user's teams was asked to review
+)
 
-// GitHubReviews represents review items from GitHub
-type GitHubReviews struct {
-	UserRequests []ReviewItem `json:"user_requests"`
-	TeamRequests []ReviewItem `json:"team_requests"`
+// ReviewJSONVersion is the version of the JSON document of the reviews command.
+// Version 2 replaced the per-platform "github" object with a flat "reviews" list,
+// version 3 added the structured "details" of each item, version 4 their "severity" and
+// version 5 "in_merge_queue".
+const ReviewJSONVersion = 5
+
+// ReviewItems represents the review items of all platforms
+type ReviewItems []ReviewItem
+
+// ByRequestType returns the review items of the given request type, in order
+func (r ReviewItems) ByRequestType(requestType string) []ReviewItem {
+	items := make([]ReviewItem, 0, len(r))
+	for _, item := range r {
+		if item.RequestType == requestType {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 // ReviewItem represents a pull request awaiting review with additional details
 type ReviewItem struct {
-	TodoItem  TodoItem  `json:"todo_item"`
-	CIStatus  CIStatus  `json:"ci_status"`
-	PRDetails PRDetails `json:"pr_details"`
+	TodoItem    TodoItem  `json:"todo_item"`
+	Platform    string    `json:"platform"`     // Platform of the review, e.g. "github"
+	RequestType string    `json:"request_type"` // ReviewRequestUser or ReviewRequestTeam
+	CIStatus    CIStatus  `json:"ci_status"`
+	PRDetails   PRDetails `json:"pr_details"`
+	AgeDays     int       `json:"age_days"`       // Days since the PR was last updated
+	IsStale     bool      `json:"is_stale"`       // Waiting longer than the configured stale threshold
+	Base        string    `json:"base,omitempty"` // Target branch of the PR, e.g. release/1.2
+
+	InMergeQueue bool `json:"in_merge_queue"` // Queued to be merged, listed last as it no longer needs a review
+
+	Repository string `json:"repository,omitempty"` // owner/repo of the PR
+	Number     int    `json:"number,omitempty"`     // PR number within the repository
+}
+
+// PRComment represents a conversation or inline review comment on a PR
+type PRComment struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Path      string    `json:"path,omitempty"` // File of an inline review comment
 }
 
+// CommentFetcher loads the most recent comments of a PR, newest first
+type CommentFetcher func(ctx context.Context, repo string, number, limit int) ([]PRComment, error)
+
 // CIStatus represents CI check status for a PR
 type CIStatus struct {
 	State      string     `json:"state"` // success, failure, pending
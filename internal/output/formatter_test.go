@@ -2,13 +2,22 @@ package output
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	catppuccin "github.com/catppuccin/go"
 	"github.com/charmbracelet/lipgloss/v2"
 
 	"daily/internal/activity"
+	"daily/internal/severity"
+	"daily/internal/tui"
+	"daily/internal/tui/types"
 )
 
 func TestFormatter_FormatSummary(t *testing.T) {
@@ -94,6 +103,29 @@ func TestFormatter_FormatSummary_Empty(t *testing.T) {
 	}
 }
 
+func TestFormatter_FormatSummary_OtherPlatformsOrder(t *testing.T) {
+	formatter := NewPlainFormatter()
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	summary := &activity.Summary{
+		Date: day,
+		Activities: []activity.Activity{
+			{ID: "1", Type: activity.ActivityTypeMessage, Title: "Deploy is done", Platform: "slack", Timestamp: day.Add(9 * time.Hour)},
+			{ID: "2", Type: activity.ActivityTypeMeeting, Title: "Daily standup", Platform: "calendar", Timestamp: day.Add(10 * time.Hour)},
+			{ID: "3", Type: activity.ActivityTypePR, Title: "Add the login form", Platform: "gitlab", Timestamp: day.Add(11 * time.Hour)},
+		},
+	}
+
+	// The platforms after GitHub, JIRA and Obsidian are sorted by name, whatever the map order
+	for range 10 {
+		result := formatter.FormatSummary(summary)
+		calendar, gitlab, slack := strings.Index(result, "Daily standup"), strings.Index(result, "Add the login form"), strings.Index(result, "Deploy is done")
+		if calendar < 0 || !(calendar < gitlab && gitlab < slack) {
+			t.Fatalf("Expected the calendar, gitlab and slack sections in order, got:\n%s", result)
+		}
+	}
+}
+
 func TestFormatter_FormatCompactSummary(t *testing.T) {
 	formatter := NewFormatter()
 
@@ -134,6 +166,50 @@ func TestFormatter_FormatCompactSummary(t *testing.T) {
 	}
 }
 
+func TestFormatter_FormatSummary_MultipleDays(t *testing.T) {
+	formatter := NewFormatter()
+
+	activities := []activity.Activity{
+		{ID: "1", Type: activity.ActivityTypeCommit, Platform: "github", Title: "Fix bug", Timestamp: time.Date(2024, 9, 9, 16, 0, 0, 0, time.UTC)},
+		{ID: "2", Type: activity.ActivityTypeCommit, Platform: "github", Title: "Add tests", Timestamp: time.Date(2024, 9, 10, 9, 30, 0, 0, time.UTC)},
+		{ID: "3", Type: activity.ActivityTypeJiraTicket, Platform: "jira", Title: "PROJ-1: Login", Timestamp: time.Date(2024, 9, 10, 11, 0, 0, 0, time.UTC)},
+	}
+
+	singleDay := &activity.Summary{
+		Date:       time.Date(2024, 9, 9, 12, 0, 0, 0, time.UTC),
+		Activities: activities,
+		From:       time.Date(2024, 9, 9, 12, 0, 0, 0, time.UTC),
+		To:         time.Date(2024, 9, 10, 12, 0, 0, 0, time.UTC),
+	}
+	multiDay := *singleDay
+	multiDay.From = multiDay.From.AddDate(0, 0, -2)
+
+	// A day or less keeps the flat list
+	full := formatter.FormatSummary(singleDay)
+	if strings.Contains(full, "📅") {
+		t.Errorf("Expected no day headings for a single day, got:\n%s", full)
+	}
+	if compact := formatter.FormatCompactSummary(singleDay); !strings.Contains(compact, "16:00") || strings.Contains(compact, "Sep 9") {
+		t.Errorf("Expected times without dates for a single day, got:\n%s", compact)
+	}
+
+	full = formatter.FormatSummary(&multiDay)
+	monday := strings.Index(full, "📅 Monday, Sep 9")
+	tuesday := strings.Index(full, "📅 Tuesday, Sep 10")
+	if monday < 0 || tuesday < monday || strings.Index(full, "Fix bug") < monday || strings.Index(full, "Add tests") < tuesday {
+		t.Errorf("Expected activities under their day heading, got:\n%s", full)
+	}
+	// Each platform section has its own day headings
+	if strings.Count(full, "📅 Tuesday, Sep 10") != 2 {
+		t.Errorf("Expected a Tuesday heading in both platform sections, got:\n%s", full)
+	}
+
+	compact := formatter.FormatCompactSummary(&multiDay)
+	if !strings.Contains(compact, "Sep 9 16:00") || !strings.Contains(compact, "Sep 10 09:30") {
+		t.Errorf("Expected dates in compact timestamps, got:\n%s", compact)
+	}
+}
+
 func TestFormatter_GetPlatformIcon(t *testing.T) {
 	formatter := NewFormatter()
 
@@ -181,6 +257,108 @@ func TestFormatter_GetTypeIcon(t *testing.T) {
 	}
 }
 
+func TestFormatter_GetActivityIcon(t *testing.T) {
+	formatter := NewFormatter()
+
+	tests := []struct {
+		name     string
+		act      activity.Activity
+		expected string
+	}{
+		{"jira bug", activity.Activity{Type: activity.ActivityTypeJiraTicket, IssueType: "Bug"}, "🐛"},
+		{"jira story", activity.Activity{Type: activity.ActivityTypeJiraTicket, IssueType: "story"}, "📖"},
+		{"jira task", activity.Activity{Type: activity.ActivityTypeJiraTicket, IssueType: "Task"}, "✅"},
+		{"unknown issue type", activity.Activity{Type: activity.ActivityTypeJiraTicket, IssueType: "Epic"}, "🎯"},
+		{"no issue type", activity.Activity{Type: activity.ActivityTypeJiraTicket}, "🎯"},
+		{"resolved keeps its icon", activity.Activity{Type: activity.ActivityTypeJiraResolved, IssueType: "Bug"}, "🏁"},
+		{"confluence page", confluenceContribution("page"), "📄"},
+		{"confluence blogpost", confluenceContribution("blogpost"), "📰"},
+		{"confluence comment", confluenceContribution("comment"), "💬"},
+		{"confluence without content type", activity.Activity{Type: activity.ActivityTypeConfluenceContribution}, "📋"},
+		{"confluence created page", activity.Activity{Type: activity.ActivityTypeConfluenceCreated,
+			Details: activity.NewDetails(activity.DetailContentType, "page")}, "🆕"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatter.getActivityIcon(tt.act)
+			if result != tt.expected {
+				t.Errorf("Expected icon '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+// confluenceContribution returns a Confluence contribution to content of a type
+func confluenceContribution(contentType string) activity.Activity {
+	return activity.Activity{
+		Type:    activity.ActivityTypeConfluenceContribution,
+		Details: activity.NewDetails(activity.DetailContentType, contentType),
+	}
+}
+
+func TestFormatter_FormatTodoItem_IssueType(t *testing.T) {
+	formatter := NewFormatter()
+
+	bug := formatter.formatTodoItem(TodoItem{Title: "PROJ-1: Crash on login", IssueType: "Bug"})
+	if !strings.Contains(bug, "🐛 PROJ-1: Crash on login") {
+		t.Errorf("Expected bug icon before the title, got: %s", bug)
+	}
+
+	epic := formatter.formatTodoItem(TodoItem{Title: "PROJ-2: Roadmap", IssueType: "Epic"})
+	if !strings.Contains(epic, "  PROJ-2: Roadmap") {
+		t.Errorf("Expected no icon for unknown issue types, got: %s", epic)
+	}
+
+	data, err := json.Marshal(TodoItem{ID: "jira-PROJ-1", IssueType: "Bug"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(string(data), `"issue_type":"Bug"`) {
+		t.Errorf("Expected issue_type in JSON, got: %s", data)
+	}
+}
+
+func TestFormatter_FormatTodoItem_Subtasks(t *testing.T) {
+	formatter := NewFormatter()
+
+	story := TodoItem{
+		ID:    "jira-PROJ-1",
+		Title: "PROJ-1: Checkout",
+		Subtasks: []TodoItem{
+			{ID: "jira-PROJ-2", Title: "PROJ-2: API", Description: "Status: In Review"},
+			{ID: "jira-PROJ-3", Title: "PROJ-3: UI", Description: "Status: Open"},
+			{ID: "jira-PROJ-4", Title: "PROJ-4: Docs", Description: "Status: Open"},
+		},
+	}
+
+	result := formatter.formatTodoItem(story)
+	if !strings.Contains(result, "PROJ-1: Checkout (3 subtasks pending)") {
+		t.Errorf("Expected the pending subtasks count after the title, got: %s", result)
+	}
+	if !strings.Contains(result, "↳ PROJ-2: API · Status: In Review") {
+		t.Errorf("Expected subtasks to be listed with their status, got: %s", result)
+	}
+
+	single := formatter.formatTodoItem(TodoItem{Title: "PROJ-5: Search", Subtasks: story.Subtasks[:1]})
+	if !strings.Contains(single, "PROJ-5: Search (1 subtask pending)") {
+		t.Errorf("Expected a singular count, got: %s", single)
+	}
+
+	data, err := json.Marshal(story)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(string(data), `"subtasks":[{"id":"jira-PROJ-2"`) {
+		t.Errorf("Expected subtasks nested in JSON, got: %s", data)
+	}
+
+	tuiItems := formatter.convertToTUITypes(TodoItems{JIRA: JIRATodos{AssignedTickets: []TodoItem{story}}})
+	if len(tuiItems.JIRA.AssignedTickets[0].Subtasks) != 3 {
+		t.Errorf("Expected subtasks to be converted for the TUI, got %+v", tuiItems.JIRA.AssignedTickets[0])
+	}
+}
+
 func TestFormatter_FormatJSON(t *testing.T) {
 	formatter := NewFormatter()
 
@@ -243,6 +421,30 @@ func TestFormatter_FormatJSON(t *testing.T) {
 	}
 }
 
+func TestFormatter_FormatJSON_ConfluenceCreatedAndEdited(t *testing.T) {
+	formatter := NewFormatter()
+	summary := &activity.Summary{
+		Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Activities: []activity.Activity{
+			{ID: "1", Type: activity.ActivityTypeConfluenceCreated, Title: "Runbook", Platform: "confluence"},
+			{ID: "2", Type: activity.ActivityTypeConfluenceContribution, Title: "Architecture", Platform: "confluence"},
+			{ID: "3", Type: activity.ActivityTypeConfluenceContribution, Title: "Onboarding", Platform: "confluence"},
+		},
+	}
+
+	var result struct {
+		Summary struct {
+			ByType map[string]int `json:"by_type"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatJSON(summary)), &result); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v", err)
+	}
+	if result.Summary.ByType["confluence_created"] != 1 || result.Summary.ByType["confluence_contribution"] != 2 {
+		t.Errorf("Expected created and edited pages counted apart, got %v", result.Summary.ByType)
+	}
+}
+
 func TestFormatter_FormatJSON_Empty(t *testing.T) {
 	formatter := NewFormatter()
 
@@ -482,3 +684,1003 @@ func TestFormatter_FormatTodoJSON_Empty(t *testing.T) {
 		t.Errorf("Expected total 0, got %v", summary["total"])
 	}
 }
+
+func TestFormatter_FormatTodo_Confluence(t *testing.T) {
+	formatter := NewFormatter()
+
+	updated := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	todoItems := TodoItems{
+		Confluence: ConfluenceTodos{
+			Mentions: []TodoItem{
+				{ID: "1", Title: "Design doc", Description: "Mentioned in page", UpdatedAt: updated},
+			},
+			CommentsOnMyPages: []TodoItem{
+				{ID: "2", Title: "Re: Runbook", Description: "Comment on: Runbook", UpdatedAt: updated.Add(time.Hour)},
+				{ID: "3", Title: "Re: Roadmap", Description: "Comment on: Roadmap", UpdatedAt: updated},
+			},
+			Tasks: []TodoItem{
+				{ID: "confluence-task-7", Title: "Update the runbook", Description: "Task on: Runbook", UpdatedAt: updated, Tags: []string{"task", "due:2024-01-20"}},
+			},
+			Watched: []TodoItem{
+				{ID: "4", Title: "Updated: Onboarding", Description: "Updated by Alice Martin", UpdatedAt: updated, Tags: []string{"watching"}},
+			},
+		},
+	}
+
+	result := formatter.FormatTodo(todoItems)
+	for _, expected := range []string{"Confluence Mentions", "Design doc", "Comments on My Pages", "Re: Runbook", "Re: Roadmap", "Confluence Tasks", "Update the runbook",
+		"Watched Pages", "Updated: Onboarding", "Updated by Alice Martin"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, result)
+		}
+	}
+
+	var jsonOutput struct {
+		Confluence struct {
+			Mentions          []TodoItem `json:"mentions"`
+			CommentsOnMyPages []TodoItem `json:"comments_on_my_pages"`
+			Tasks             []TodoItem `json:"tasks"`
+			Watched           []TodoItem `json:"watched"`
+		} `json:"confluence"`
+		Summary struct {
+			Total              int `json:"total"`
+			ConfluenceMentions int `json:"confluence_mentions"`
+			ConfluenceComments int `json:"confluence_comments"`
+			ConfluenceTasks    int `json:"confluence_tasks"`
+			WatchedPages       int `json:"watched_pages"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatTodoJSON(todoItems)), &jsonOutput); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(jsonOutput.Confluence.Mentions) != 1 || len(jsonOutput.Confluence.CommentsOnMyPages) != 2 || len(jsonOutput.Confluence.Tasks) != 1 || len(jsonOutput.Confluence.Watched) != 1 {
+		t.Fatalf("Expected 1 mention, 2 comments, 1 task and 1 watched page, got %+v", jsonOutput.Confluence)
+	}
+	if jsonOutput.Confluence.CommentsOnMyPages[0].ID != "2" {
+		t.Errorf("Expected the most recent comment first, got %s", jsonOutput.Confluence.CommentsOnMyPages[0].ID)
+	}
+	if jsonOutput.Summary.ConfluenceMentions != 1 || jsonOutput.Summary.ConfluenceComments != 2 || jsonOutput.Summary.ConfluenceTasks != 1 ||
+		jsonOutput.Summary.WatchedPages != 1 || jsonOutput.Summary.Total != 5 {
+		t.Errorf("Expected summary counts 1, 2, 1, 1 and 5, got %+v", jsonOutput.Summary)
+	}
+}
+
+func TestFormatter_FormatTodo_GitLab(t *testing.T) {
+	formatter := NewFormatter()
+
+	updated := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	todoItems := TodoItems{
+		GitLab: GitLabTodos{
+			OpenMRs: []TodoItem{
+				{ID: "gitlab-mr-1", Title: "Add the login form", Description: "Open MR in acme/api", UpdatedAt: updated},
+			},
+			PendingReviews: []TodoItem{
+				{ID: "gitlab-review-2", Title: "Add invoice exports", Description: "Review requested in acme/billing", UpdatedAt: updated},
+				{ID: "gitlab-review-3", Title: "Migrate the billing jobs", Description: "Review requested in acme/billing", UpdatedAt: updated.Add(time.Hour)},
+			},
+			AssignedIssues: []TodoItem{
+				{ID: "gitlab-issue-4", Title: "Login fails with SSO", Description: "Issue #31 in acme/api", UpdatedAt: updated},
+			},
+		},
+	}
+
+	result := formatter.FormatTodo(todoItems)
+	for _, expected := range []string{"Found 4 pending items", "Open Merge Requests", "Add the login form", "Pending MR Reviews", "Add invoice exports",
+		"Assigned GitLab Issues", "Login fails with SSO"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, result)
+		}
+	}
+
+	var jsonOutput struct {
+		GitLab struct {
+			OpenMRs        []TodoItem `json:"open_mrs"`
+			PendingReviews []TodoItem `json:"pending_reviews"`
+			AssignedIssues []TodoItem `json:"assigned_issues"`
+		} `json:"gitlab"`
+		Summary struct {
+			Total            int `json:"total"`
+			OpenMRs          int `json:"open_mrs"`
+			PendingMRReviews int `json:"pending_mr_reviews"`
+			GitLabIssues     int `json:"gitlab_issues"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatTodoJSON(todoItems)), &jsonOutput); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(jsonOutput.GitLab.OpenMRs) != 1 || len(jsonOutput.GitLab.PendingReviews) != 2 || len(jsonOutput.GitLab.AssignedIssues) != 1 {
+		t.Fatalf("Expected 1 open MR, 2 pending reviews and 1 issue, got %+v", jsonOutput.GitLab)
+	}
+	if jsonOutput.GitLab.PendingReviews[0].ID != "gitlab-review-3" {
+		t.Errorf("Expected the most recent review first, got %s", jsonOutput.GitLab.PendingReviews[0].ID)
+	}
+	if jsonOutput.Summary.OpenMRs != 1 || jsonOutput.Summary.PendingMRReviews != 2 || jsonOutput.Summary.GitLabIssues != 1 || jsonOutput.Summary.Total != 4 {
+		t.Errorf("Expected summary counts 1, 2, 1 and 4, got %+v", jsonOutput.Summary)
+	}
+}
+
+func TestFormatter_FormatTodo_Slack(t *testing.T) {
+	formatter := NewFormatter()
+
+	saved := time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC)
+	todoItems := TodoItems{
+		Slack: SlackTodos{
+			Saved: []TodoItem{
+				{ID: "slack-saved-C01-1705300000.000100", Title: "Postmortem draft for the checkout outage", Description: "Saved from #platform", UpdatedAt: saved},
+				{ID: "slack-saved-C01-1705415000.000300", Title: "Rotate the keys", Description: "Saved from #platform", UpdatedAt: saved.Add(time.Hour)},
+			},
+		},
+	}
+
+	result := formatter.FormatTodo(todoItems)
+	for _, expected := range []string{"Found 2 pending items", "Saved Slack Messages", "Postmortem draft for the checkout outage", "Rotate the keys"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, result)
+		}
+	}
+
+	var jsonOutput struct {
+		Slack struct {
+			Saved []TodoItem `json:"saved"`
+		} `json:"slack"`
+		Summary struct {
+			Total         int `json:"total"`
+			SavedMessages int `json:"saved_messages"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatTodoJSON(todoItems)), &jsonOutput); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(jsonOutput.Slack.Saved) != 2 || jsonOutput.Slack.Saved[0].ID != "slack-saved-C01-1705415000.000300" {
+		t.Errorf("Expected the most recently saved message first, got %+v", jsonOutput.Slack.Saved)
+	}
+	if jsonOutput.Summary.SavedMessages != 2 || jsonOutput.Summary.Total != 2 {
+		t.Errorf("Expected summary counts 2 and 2, got %+v", jsonOutput.Summary)
+	}
+}
+
+func TestFormatter_FormatTodo_ConfluenceEmpty(t *testing.T) {
+	formatter := NewFormatter()
+	todoItems := TodoItems{Confluence: ConfluenceTodos{Mentions: []TodoItem{{ID: "1", Title: "Design doc"}}}}
+
+	if result := formatter.FormatTodo(todoItems); strings.Contains(result, "Comments on My Pages") {
+		t.Errorf("Expected no section without comments, got:\n%s", result)
+	}
+
+	var parsed map[string]any
+	if err := json.Unmarshal([]byte(formatter.FormatTodoJSON(TodoItems{})), &parsed); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	confluence, ok := parsed["confluence"].(map[string]any)
+	if !ok {
+		t.Fatal("JSON should contain confluence section")
+	}
+	for _, key := range []string{"mentions", "comments_on_my_pages"} {
+		if items, ok := confluence[key].([]any); !ok || len(items) != 0 {
+			t.Errorf("Expected %s to be an empty array, got %v", key, confluence[key])
+		}
+	}
+	if summary := parsed["summary"].(map[string]any); summary["confluence_comments"] != float64(0) {
+		t.Errorf("Expected 0 confluence comments, got %v", summary["confluence_comments"])
+	}
+}
+
+func TestFormatter_FormatTodoJSON_Milestone(t *testing.T) {
+	formatter := NewFormatter()
+
+	todoItems := TodoItems{
+		GitHub: GitHubTodos{
+			OpenPRs: []TodoItem{
+				{ID: "github-pr-1", Title: "With milestone", Milestone: "v1.2", ProjectStatus: "In Review"},
+				{ID: "github-pr-2", Title: "Without milestone"},
+			},
+		},
+	}
+
+	result := formatter.FormatTodoJSON(todoItems)
+
+	var parsed struct {
+		GitHub struct {
+			OpenPRs []map[string]interface{} `json:"open_prs"`
+		} `json:"github"`
+	}
+	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+
+	for _, pr := range parsed.GitHub.OpenPRs {
+		switch pr["id"] {
+		case "github-pr-1":
+			if pr["milestone"] != "v1.2" {
+				t.Errorf("Expected milestone 'v1.2', got %v", pr["milestone"])
+			}
+			if pr["project_status"] != "In Review" {
+				t.Errorf("Expected project_status 'In Review', got %v", pr["project_status"])
+			}
+		case "github-pr-2":
+			if _, ok := pr["milestone"]; ok {
+				t.Error("Missing milestone should be omitted from JSON")
+			}
+			if _, ok := pr["project_status"]; ok {
+				t.Error("Missing project status should be omitted from JSON")
+			}
+		}
+	}
+}
+
+func TestFormatter_FormatReview_Stale(t *testing.T) {
+	formatter := NewFormatter()
+
+	reviewItems := ReviewItems{
+		{
+			TodoItem:    TodoItem{ID: "fresh", Title: "Fresh PR", UpdatedAt: time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)},
+			RequestType: ReviewRequestUser,
+		},
+		{
+			TodoItem:    TodoItem{ID: "old", Title: "Old PR", UpdatedAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+			RequestType: ReviewRequestUser,
+			AgeDays:     9,
+			IsStale:     true,
+		},
+	}
+
+	result := formatter.FormatReview(reviewItems)
+
+	if !strings.Contains(result, "🔥") {
+		t.Error("Stale review should be rendered with a 🔥 marker")
+	}
+
+	if !strings.Contains(result, "Waiting for 9 days") {
+		t.Error("Stale review should show its age")
+	}
+
+	if strings.Index(result, "Old PR") > strings.Index(result, "Fresh PR") {
+		t.Error("Stale review should be sorted before fresher reviews")
+	}
+
+	jsonResult := formatter.FormatReviewJSON(reviewItems)
+	if !strings.Contains(jsonResult, `"age_days": 9`) || !strings.Contains(jsonResult, `"is_stale": true`) {
+		t.Error("JSON output should include age_days and is_stale")
+	}
+}
+
+func TestFormatter_FormatReview_MergeQueue(t *testing.T) {
+	formatter := NewPlainFormatter()
+
+	reviewItems := ReviewItems{
+		{
+			TodoItem:     TodoItem{ID: "queued", Title: "Queued PR", UpdatedAt: time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC), Tags: []string{"in-merge-queue"}},
+			RequestType:  ReviewRequestUser,
+			AgeDays:      9,
+			IsStale:      true,
+			InMergeQueue: true,
+		},
+		{
+			TodoItem:    TodoItem{ID: "old", Title: "Old PR", UpdatedAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+			RequestType: ReviewRequestUser,
+		},
+	}
+
+	result := formatter.FormatReview(reviewItems)
+	if !strings.Contains(result, "🔥 🚦 Queued PR") {
+		t.Errorf("Stale queued review should be rendered with both the 🔥 and 🚦 markers, got:\n%s", result)
+	}
+	if strings.Index(result, "Queued PR") < strings.Index(result, "Old PR") {
+		t.Error("Queued review should be sorted after the other reviews, even when stale")
+	}
+
+	var document struct {
+		Version int `json:"version"`
+		Reviews []struct {
+			InMergeQueue bool `json:"in_merge_queue"`
+		} `json:"reviews"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatReviewJSON(reviewItems)), &document); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if document.Version != 5 || len(document.Reviews) != 2 || !document.Reviews[0].InMergeQueue || document.Reviews[1].InMergeQueue {
+		t.Errorf("Expected version 5 with in_merge_queue set on the queued review only, got %+v", document)
+	}
+}
+
+func TestFormatter_FormatJSON_TimeLogged(t *testing.T) {
+	formatter := NewFormatter()
+
+	summary := &activity.Summary{
+		Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Activities: []activity.Activity{
+			{ID: "1", Type: activity.ActivityTypeWorklog, Platform: "jira", Timestamp: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), TimeSpentSeconds: 7200},
+			{ID: "2", Type: activity.ActivityTypeWorklog, Platform: "jira", Timestamp: time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC), TimeSpentSeconds: 1800},
+			{ID: "3", Type: activity.ActivityTypeWorklog, Platform: "jira", Timestamp: time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC), TimeSpentSeconds: 3600},
+			{ID: "4", Type: activity.ActivityTypeCommit, Platform: "github", Timestamp: time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	var result struct {
+		Summary struct {
+			TimeLoggedByDay map[string]int `json:"time_logged_seconds_by_day"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatJSON(summary)), &result); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	if result.Summary.TimeLoggedByDay["2024-01-15"] != 9000 {
+		t.Errorf("Expected 9000 seconds on 2024-01-15, got %d", result.Summary.TimeLoggedByDay["2024-01-15"])
+	}
+	if result.Summary.TimeLoggedByDay["2024-01-16"] != 3600 {
+		t.Errorf("Expected 3600 seconds on 2024-01-16, got %d", result.Summary.TimeLoggedByDay["2024-01-16"])
+	}
+}
+
+func TestFormatter_FormatJSON_NoTimeLogged(t *testing.T) {
+	formatter := NewFormatter()
+
+	summary := &activity.Summary{
+		Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Activities: []activity.Activity{
+			{ID: "1", Type: activity.ActivityTypeCommit, Platform: "github", Timestamp: time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	if strings.Contains(formatter.FormatJSON(summary), "time_logged_seconds_by_day") {
+		t.Error("Expected no time logged block without worklogs")
+	}
+}
+
+func TestFormatter_FormatJSON_MeetingHours(t *testing.T) {
+	formatter := NewFormatter()
+
+	at := func(hour int) time.Time { return time.Date(2024, 1, 15, hour, 0, 0, 0, time.UTC) }
+	summary := &activity.Summary{
+		Date: at(0),
+		Activities: []activity.Activity{
+			{ID: "1", Type: activity.ActivityTypeMeeting, Platform: "calendar", Timestamp: at(9), DurationSeconds: 900},
+			{ID: "2", Type: activity.ActivityTypeMeeting, Platform: "calendar", Timestamp: at(14), DurationSeconds: 5400},
+			{ID: "3", Type: activity.ActivityTypeMeeting, Platform: "calendar", Timestamp: at(16), DurationSeconds: 1200},
+			// Meeting notes have no duration
+			{ID: "4", Type: activity.ActivityTypeMeeting, Platform: "obsidian", Timestamp: at(17)},
+			{ID: "5", Type: activity.ActivityTypeCommit, Platform: "github", Timestamp: at(11)},
+		},
+	}
+
+	var result struct {
+		Summary struct {
+			MeetingHours float64 `json:"meeting_hours"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatJSON(summary)), &result); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if result.Summary.MeetingHours != 2.08 {
+		t.Errorf("Expected 2.08 meeting hours, got %v", result.Summary.MeetingHours)
+	}
+
+	summary.Activities = summary.Activities[3:]
+	if strings.Contains(formatter.FormatJSON(summary), "meeting_hours") {
+		t.Error("Expected no meeting hours without meeting durations")
+	}
+}
+
+func TestFormatter_FormatTodo_JIRADueDates(t *testing.T) {
+	formatter := NewFormatter()
+
+	updated := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	due := func(day int) *time.Time {
+		d := time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	todoItems := TodoItems{
+		JIRA: JIRATodos{
+			AssignedTickets: []TodoItem{
+				{ID: "none", Title: "PROJ-3: No due date", UpdatedAt: updated.Add(time.Hour)},
+				{ID: "later", Title: "PROJ-2: Due later", UpdatedAt: updated, DueDate: due(25), Priority: "Low"},
+				{ID: "overdue", Title: "PROJ-1: Overdue", UpdatedAt: updated, DueDate: due(10), IsOverdue: true, Sprint: "Sprint 5"},
+			},
+		},
+	}
+
+	result := formatter.FormatTodo(todoItems)
+
+	if !strings.Contains(result, "⚠️  PROJ-1: Overdue") {
+		t.Error("Expected overdue ticket to be marked with ⚠️")
+	}
+	if strings.Contains(result, "⚠️  PROJ-2") {
+		t.Error("Expected ticket due later not to be marked as overdue")
+	}
+	if !strings.Contains(result, "Priority: Low") || !strings.Contains(result, "Sprint 5") {
+		t.Error("Expected priority and sprint to be shown")
+	}
+
+	overdue := strings.Index(result, "PROJ-1")
+	later := strings.Index(result, "PROJ-2")
+	none := strings.Index(result, "PROJ-3")
+	if overdue > later || later > none {
+		t.Error("Expected tickets sorted by due date, with undated tickets last")
+	}
+}
+
+func TestFormatter_FormatTodo_ObsidianPriorities(t *testing.T) {
+	formatter := NewFormatter()
+
+	updated := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	due := func(day int) *time.Time {
+		d := time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	todoItems := TodoItems{
+		Obsidian: ObsidianTodos{
+			Tasks: []TodoItem{
+				{ID: "low", Title: "Tidy the wiki", UpdatedAt: updated.Add(3 * time.Hour), Priority: "low"},
+				{ID: "none", Title: "Water the plants", UpdatedAt: updated.Add(2 * time.Hour)},
+				{ID: "high-undated", Title: "Ship the release", UpdatedAt: updated.Add(time.Hour), Priority: "high"},
+				{ID: "high-due", Title: "Renew the domain", UpdatedAt: updated, Priority: "high", DueDate: due(20)},
+				{ID: "highest", Title: "Fix the outage", UpdatedAt: updated, Priority: "highest"},
+			},
+		},
+	}
+
+	expected := []string{"Fix the outage", "Renew the domain", "Ship the release", "Water the plants", "Tidy the wiki"}
+
+	result := formatter.FormatTodo(todoItems)
+	for i := 1; i < len(expected); i++ {
+		if strings.Index(result, expected[i-1]) > strings.Index(result, expected[i]) {
+			t.Errorf("Expected %q before %q in:\n%s", expected[i-1], expected[i], result)
+		}
+	}
+
+	var jsonOutput struct {
+		Obsidian struct {
+			Tasks []TodoItem `json:"tasks"`
+		} `json:"obsidian"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatTodoJSON(todoItems)), &jsonOutput); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	var titles []string
+	for _, task := range jsonOutput.Obsidian.Tasks {
+		titles = append(titles, task.Title)
+	}
+	if strings.Join(titles, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected JSON tasks %v, got %v", expected, titles)
+	}
+	if jsonOutput.Obsidian.Tasks[0].Priority != "highest" {
+		t.Errorf("Expected the priority in the JSON output, got %q", jsonOutput.Obsidian.Tasks[0].Priority)
+	}
+}
+
+func TestFormatter_FormatTodo_ReadyToMerge(t *testing.T) {
+	formatter := NewFormatter()
+
+	updated := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	todoItems := TodoItems{
+		GitHub: GitHubTodos{
+			OpenPRs: []TodoItem{
+				{ID: "recent", Title: "Add caching", UpdatedAt: updated.Add(time.Hour)},
+				{ID: "ready", Title: "Fix login", UpdatedAt: updated, ReadyToMerge: true, Tags: []string{"ready-to-merge"}},
+			},
+		},
+	}
+
+	result := formatter.FormatTodo(todoItems)
+	if !strings.Contains(result, "🎉 Fix login") {
+		t.Errorf("Expected the ready PR to be marked, got:\n%s", result)
+	}
+	if strings.Index(result, "Fix login") > strings.Index(result, "Add caching") {
+		t.Errorf("Expected the ready PR first, got:\n%s", result)
+	}
+
+	var jsonOutput struct {
+		GitHub struct {
+			OpenPRs []TodoItem `json:"open_prs"`
+		} `json:"github"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatTodoJSON(todoItems)), &jsonOutput); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(jsonOutput.GitHub.OpenPRs) != 2 || jsonOutput.GitHub.OpenPRs[0].ID != "ready" || !jsonOutput.GitHub.OpenPRs[0].ReadyToMerge {
+		t.Errorf("Expected the ready PR first with its state in the JSON output, got %+v", jsonOutput.GitHub.OpenPRs)
+	}
+	if jsonOutput.GitHub.OpenPRs[1].ReadyToMerge {
+		t.Errorf("Expected the other PR not to be ready to merge")
+	}
+}
+
+func TestFormatter_FormatTodo_JIRAStatusSections(t *testing.T) {
+	formatter := NewFormatter()
+
+	updated := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	todoItems := TodoItems{
+		JIRA: JIRATodos{
+			AssignedTickets: []TodoItem{
+				{ID: "blocked", Title: "PROJ-1: Stuck", UpdatedAt: updated, StatusCategory: "indeterminate", StatusSection: "Blocked"},
+				{ID: "review", Title: "PROJ-2: Reviewing", UpdatedAt: updated, StatusCategory: "indeterminate", StatusSection: "In Review"},
+				{ID: "todo", Title: "PROJ-3: Planned", UpdatedAt: updated, StatusCategory: "new", StatusSection: "To Do"},
+				{ID: "progress", Title: "PROJ-4: Started", UpdatedAt: updated, StatusCategory: "indeterminate", StatusSection: "In Progress"},
+				{ID: "unknown", Title: "PROJ-5: Unknown", UpdatedAt: updated},
+			},
+		},
+	}
+
+	result := formatter.FormatTodo(todoItems)
+
+	var positions []int
+	for _, section := range []string{"Assigned Tickets · To Do (1)", "Assigned Tickets · In Progress (1)", "Assigned Tickets · Blocked (1)", "Assigned Tickets · In Review (1)", "Assigned Tickets (1)"} {
+		pos := strings.Index(result, section)
+		if pos == -1 {
+			t.Fatalf("Expected section '%s' in output", section)
+		}
+		positions = append(positions, pos)
+	}
+	for i := 1; i < len(positions); i++ {
+		if positions[i] < positions[i-1] {
+			t.Error("Expected default sections first, then custom sections, then tickets without a section")
+		}
+	}
+
+	jsonResult := formatter.FormatTodoJSON(todoItems)
+	if !strings.Contains(jsonResult, `"status_category": "new"`) {
+		t.Error("JSON output should include status_category")
+	}
+}
+
+func TestFormatter_FormatSummary_Goals(t *testing.T) {
+	formatter := NewFormatter()
+
+	summary := &activity.Summary{
+		Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Activities: []activity.Activity{
+			{ID: "1", Type: activity.ActivityTypeCommit, Platform: "github", Title: "Fix", Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+		},
+		Goals: []activity.GoalProgress{
+			{Name: "commits", Count: 1, Target: 5},
+			{Name: "reviews", Count: 4, Target: 3, Met: true},
+		},
+	}
+
+	for name, result := range map[string]string{
+		"full":    formatter.FormatSummary(summary),
+		"compact": formatter.FormatCompactSummary(summary),
+	} {
+		if !strings.Contains(result, "commits 1/5 · reviews 4/3 ✅") {
+			t.Errorf("Expected %s summary header to show goal progress, got:\n%s", name, result)
+		}
+	}
+
+	if !strings.Contains(formatter.FormatJSON(summary), `"target": 5`) {
+		t.Error("JSON output should include goals")
+	}
+}
+
+func TestFormatter_FormatSummary_Period(t *testing.T) {
+	formatter := NewFormatter()
+
+	summary := &activity.Summary{
+		Date: time.Date(2024, 3, 8, 18, 0, 0, 0, time.UTC),
+		Activities: []activity.Activity{
+			{ID: "1", Type: activity.ActivityTypeCommit, Platform: "github", Title: "Fix", Timestamp: time.Date(2024, 3, 9, 10, 0, 0, 0, time.UTC)},
+		},
+		Period: "covering Fri 18:00 → now",
+	}
+
+	for name, result := range map[string]string{
+		"full":    formatter.FormatSummary(summary),
+		"compact": formatter.FormatCompactSummary(summary),
+	} {
+		if !strings.Contains(result, "(covering Fri 18:00 → now)") {
+			t.Errorf("Expected %s summary header to show the covered period, got:\n%s", name, result)
+		}
+	}
+}
+
+func TestGroupByEpic(t *testing.T) {
+	checkout := &activity.Epic{Key: "PROJ-100", Name: "Checkout revamp"}
+	activities := []activity.Activity{
+		{ID: "1", Epic: &activity.Epic{Key: "PROJ-200"}},
+		{ID: "2"},
+		{ID: "3", Epic: checkout},
+		{ID: "4", Epic: &activity.Epic{Key: "PROJ-200", Name: "Search"}},
+		{ID: "5"},
+		{ID: "6", Epic: checkout},
+	}
+
+	groups := groupByEpic(activities)
+
+	expected := []struct {
+		heading string
+		ids     string
+	}{
+		{"PROJ-200 Search", "1,4"},
+		{"PROJ-100 Checkout revamp", "3,6"},
+		{"No epic", "2,5"},
+	}
+
+	if len(groups) != len(expected) {
+		t.Fatalf("Expected %d groups, got %d", len(expected), len(groups))
+	}
+	for i, want := range expected {
+		var ids []string
+		for _, act := range groups[i].activities {
+			ids = append(ids, act.ID)
+		}
+		if groups[i].heading != want.heading || strings.Join(ids, ",") != want.ids {
+			t.Errorf("Expected group %q with %s, got %q with %s", want.heading, want.ids, groups[i].heading, strings.Join(ids, ","))
+		}
+	}
+}
+
+func TestFormatter_FormatSummary_GroupByEpic(t *testing.T) {
+	timestamp := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	summary := &activity.Summary{
+		Date: timestamp,
+		Activities: []activity.Activity{
+			{ID: "1", Type: activity.ActivityTypeJiraTicket, Platform: "jira", Title: "PROJ-1: Pay", Timestamp: timestamp,
+				Epic: &activity.Epic{Key: "PROJ-100", Name: "Checkout revamp"}},
+			{ID: "2", Type: activity.ActivityTypeJiraTicket, Platform: "jira", Title: "PROJ-2: Fix", Timestamp: timestamp},
+		},
+	}
+
+	formatter := NewFormatter()
+	if result := formatter.FormatSummary(summary); strings.Contains(result, "No epic") {
+		t.Error("Expected no epic headings without grouping")
+	}
+
+	formatter.SetGroupBy(GroupByEpic)
+	result := formatter.FormatSummary(summary)
+	epic := strings.Index(result, "PROJ-100 Checkout revamp (1)")
+	noEpic := strings.Index(result, "No epic (1)")
+	if epic == -1 || noEpic == -1 || epic > noEpic {
+		t.Errorf("Expected epic heading before 'No epic', got:\n%s", result)
+	}
+}
+
+func TestFormatter_FormatReview_Base(t *testing.T) {
+	formatter := NewFormatter()
+
+	reviewItems := ReviewItems{
+		{
+			TodoItem:    TodoItem{ID: "release", Title: "Backport fix", UpdatedAt: time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)},
+			RequestType: ReviewRequestUser,
+			Base:        "release/1.2",
+		},
+		{
+			TodoItem:    TodoItem{ID: "unknown", Title: "No details", UpdatedAt: time.Date(2024, 3, 9, 10, 0, 0, 0, time.UTC)},
+			RequestType: ReviewRequestUser,
+		},
+	}
+
+	result := formatter.FormatReview(reviewItems)
+
+	if !strings.Contains(result, "→ release/1.2") {
+		t.Error("Review should show its base branch")
+	}
+	if strings.Count(result, "→") != 1 {
+		t.Error("Reviews without a known base branch should not show a suffix")
+	}
+
+	jsonResult := formatter.FormatReviewJSON(reviewItems)
+	if !strings.Contains(jsonResult, `"base": "release/1.2"`) {
+		t.Error("JSON output should include base")
+	}
+}
+
+func TestFormatter_FormatTodo_JIRAMentions(t *testing.T) {
+	formatter := NewFormatter()
+
+	todoItems := TodoItems{
+		JIRA: JIRATodos{
+			Mentions: []TodoItem{
+				{ID: "jira-PROJ-2-mention-1", Title: "PROJ-2: Summary", Description: "Alice mentioned you", UpdatedAt: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	result := formatter.FormatTodo(todoItems)
+	if !strings.Contains(result, "JIRA Mentions (1)") {
+		t.Error("Expected a JIRA Mentions section")
+	}
+	if !strings.Contains(result, "Found 1 pending items") {
+		t.Error("Expected JIRA mentions to be counted in the total")
+	}
+
+	var jsonResult struct {
+		JIRA struct {
+			Mentions []TodoItem `json:"mentions"`
+		} `json:"jira"`
+		Summary struct {
+			Total        int `json:"total"`
+			JIRAMentions int `json:"jira_mentions"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatTodoJSON(todoItems)), &jsonResult); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if len(jsonResult.JIRA.Mentions) != 1 || jsonResult.Summary.JIRAMentions != 1 || jsonResult.Summary.Total != 1 {
+		t.Errorf("Expected 1 JIRA mention in JSON output, got %d (summary %d, total %d)",
+			len(jsonResult.JIRA.Mentions), jsonResult.Summary.JIRAMentions, jsonResult.Summary.Total)
+	}
+}
+
+func TestFormatter_FormatReviewJSON(t *testing.T) {
+	formatter := NewFormatter()
+
+	reviewItems := ReviewItems{
+		{
+			TodoItem:    TodoItem{ID: "older", Title: "Older PR", UpdatedAt: time.Date(2024, 3, 9, 10, 0, 0, 0, time.UTC)},
+			Platform:    "github",
+			RequestType: ReviewRequestTeam,
+		},
+		{
+			TodoItem:    TodoItem{ID: "newer", Title: "Newer PR", UpdatedAt: time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)},
+			Platform:    "github",
+			RequestType: ReviewRequestUser,
+		},
+	}
+
+	var result struct {
+		Version int `json:"version"`
+		Reviews []struct {
+			TodoItem struct {
+				ID string `json:"id"`
+			} `json:"todo_item"`
+			Platform    string `json:"platform"`
+			RequestType string `json:"request_type"`
+		} `json:"reviews"`
+		Summary struct {
+			Total        int            `json:"total"`
+			UserRequests int            `json:"user_requests"`
+			TeamRequests int            `json:"team_requests"`
+			ByPlatform   map[string]int `json:"by_platform"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatReviewJSON(reviewItems)), &result); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	if result.Version != ReviewJSONVersion {
+		t.Errorf("Expected version %d, got %d", ReviewJSONVersion, result.Version)
+	}
+	if len(result.Reviews) != 2 {
+		t.Fatalf("Expected 2 reviews, got %d", len(result.Reviews))
+	}
+	if result.Reviews[0].TodoItem.ID != "newer" {
+		t.Errorf("Expected most recently updated review first, got '%s'", result.Reviews[0].TodoItem.ID)
+	}
+	if result.Reviews[0].Platform != "github" || result.Reviews[0].RequestType != ReviewRequestUser {
+		t.Errorf("Expected platform and request type to be included, got %+v", result.Reviews[0])
+	}
+	if result.Summary.Total != 2 || result.Summary.UserRequests != 1 || result.Summary.TeamRequests != 1 {
+		t.Errorf("Expected 1 user and 1 team request, got %+v", result.Summary)
+	}
+	if result.Summary.ByPlatform["github"] != 2 {
+		t.Errorf("Expected 2 GitHub reviews, got %v", result.Summary.ByPlatform)
+	}
+
+	text := formatter.FormatReview(reviewItems)
+	if !strings.Contains(text, "Direct Review Requests (1)") || !strings.Contains(text, "Team Review Requests (1)") {
+		t.Errorf("Expected reviews to be split by request type, got:\n%s", text)
+	}
+}
+
+func TestFormatter_FormatTodo_FollowedIssues(t *testing.T) {
+	formatter := NewFormatter()
+
+	todoItems := TodoItems{
+		JIRA: JIRATodos{
+			Reported: []TodoItem{{ID: "jira-PROJ-2", Title: "PROJ-2: Reported", UpdatedAt: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)}},
+			Watched:  []TodoItem{{ID: "jira-PROJ-3", Title: "PROJ-3: Watched", UpdatedAt: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)}},
+		},
+	}
+
+	result := formatter.FormatTodo(todoItems)
+	for _, want := range []string{"Reported Issues (1)", "Watched Issues (1)", "Found 2 pending items"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected text output to contain '%s'", want)
+		}
+	}
+
+	var jsonResult struct {
+		JIRA struct {
+			Reported []TodoItem `json:"reported"`
+			Watched  []TodoItem `json:"watched"`
+		} `json:"jira"`
+		Summary struct {
+			Total          int `json:"total"`
+			ReportedIssues int `json:"reported_issues"`
+			WatchedIssues  int `json:"watched_issues"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatTodoJSON(todoItems)), &jsonResult); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if len(jsonResult.JIRA.Reported) != 1 || len(jsonResult.JIRA.Watched) != 1 {
+		t.Errorf("Expected 1 reported and 1 watched issue in JSON, got %d and %d", len(jsonResult.JIRA.Reported), len(jsonResult.JIRA.Watched))
+	}
+	if jsonResult.Summary.Total != 2 || jsonResult.Summary.ReportedIssues != 1 || jsonResult.Summary.WatchedIssues != 1 {
+		t.Errorf("Unexpected summary: %+v", jsonResult.Summary)
+	}
+
+	// Sections that are not enabled are left out of the JSON output
+	if strings.Contains(formatter.FormatTodoJSON(TodoItems{}), `"watched":`) {
+		t.Error("Expected watched issues to be omitted when not enabled")
+	}
+}
+
+func TestFormatter_PlainFormatter(t *testing.T) {
+	formatter := NewPlainFormatter()
+
+	summary := &activity.Summary{
+		Date: time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC),
+		Activities: []activity.Activity{
+			{
+				ID:          "1",
+				Type:        activity.ActivityTypeCommit,
+				Title:       "Fix bug in authentication",
+				Description: "Commit in user-service",
+				URL:         "https://github.com/user/repo/commit/123",
+				Platform:    "github",
+				Timestamp:   time.Date(2023, 12, 25, 9, 30, 0, 0, time.UTC),
+				Tags:        []string{"user-service"},
+			},
+		},
+	}
+
+	for name, result := range map[string]string{
+		"summary":         formatter.FormatSummary(summary),
+		"compact summary": formatter.FormatCompactSummary(summary),
+	} {
+		if strings.Contains(result, "\x1b[") {
+			t.Errorf("Expected no escape codes in the plain %s, got %q", name, result)
+		}
+		if strings.Contains(result, "╭") {
+			t.Errorf("Expected no borders in the plain %s, got %q", name, result)
+		}
+		if !strings.Contains(result, "Fix bug in authentication") {
+			t.Errorf("Expected the plain %s to contain the activity, got %q", name, result)
+		}
+	}
+}
+
+func TestFormatter_Severity(t *testing.T) {
+	updated := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+	todoItems := TodoItems{
+		JIRA: JIRATodos{AssignedTickets: []TodoItem{
+			{ID: "jira-OPS-1", Title: "Checkout is down", Priority: "Blocker", Severity: "critical", UpdatedAt: updated},
+			{ID: "jira-OPS-2", Title: "Update the docs", Priority: "Medium", UpdatedAt: updated},
+		}},
+	}
+	reviewItems := ReviewItems{
+		{TodoItem: TodoItem{ID: "github-review-1", Title: "Hotfix payments", Tags: []string{"P1"}, Severity: "high", UpdatedAt: updated}, RequestType: ReviewRequestUser},
+	}
+
+	plain := NewPlainFormatter()
+	result := plain.FormatTodo(todoItems) + plain.FormatReview(reviewItems)
+	for _, want := range []string{"[CRIT] Checkout is down", "[HIGH] Hotfix payments"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected plain output to contain '%s', got: %s", want, result)
+		}
+	}
+	if strings.Contains(result, "] Update the docs") {
+		t.Errorf("Expected no marker on items without severity, got: %s", result)
+	}
+
+	colored := NewPlainFormatter()
+	colored.severityStyles = newSeverityStyles(catppuccin.Mocha)
+	result = colored.FormatTodo(todoItems)
+	if strings.Contains(result, "[CRIT]") {
+		t.Errorf("Expected colors instead of markers, got: %s", result)
+	}
+	if want := colored.severityStyles[severity.Critical].Render("Checkout is down"); !strings.Contains(result, want) || !strings.Contains(want, "\x1b[") {
+		t.Errorf("Expected the title to be colored, got: %q", result)
+	}
+
+	jsonResult := plain.FormatTodoJSON(todoItems) + plain.FormatReviewJSON(reviewItems)
+	for _, want := range []string{`"severity": "critical"`, `"severity": "high"`} {
+		if !strings.Contains(jsonResult, want) {
+			t.Errorf("Expected JSON output to contain '%s', got: %s", want, jsonResult)
+		}
+	}
+	if strings.Count(jsonResult, `"severity"`) != 2 {
+		t.Errorf("Expected severity only on items having one, got: %s", jsonResult)
+	}
+}
+
+func TestFormatter_TUICrashFallsBackToText(t *testing.T) {
+	originalTodo, originalReviews := runTodoTUI, runReviewsTUI
+	runTodoTUI = func(types.TodoItems) error {
+		return fmt.Errorf("%w: index out of range", tui.ErrCrashed)
+	}
+	runReviewsTUI = func(types.ReviewItems, types.CommentFetcher) error {
+		return fmt.Errorf("%w: index out of range", tui.ErrCrashed)
+	}
+	defer func() { runTodoTUI, runReviewsTUI = originalTodo, originalReviews }()
+
+	formatter := NewPlainFormatter()
+	updated := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+	todoItems := TodoItems{JIRA: JIRATodos{AssignedTickets: []TodoItem{{ID: "jira-OPS-1", Title: "Checkout is down", UpdatedAt: updated}}}}
+	reviewItems := ReviewItems{{TodoItem: TodoItem{ID: "github-review-1", Title: "Hotfix payments", UpdatedAt: updated}, RequestType: ReviewRequestUser}}
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	os.Stdout = w
+	todoErr := formatter.FormatTodoTUI(todoItems)
+	reviewErr := formatter.FormatReviewTUI(reviewItems, nil)
+	_ = w.Close()
+	os.Stdout = stdout
+	out, _ := io.ReadAll(r)
+
+	if todoErr != nil || reviewErr != nil {
+		t.Fatalf("Expected no error after falling back, got %v and %v", todoErr, reviewErr)
+	}
+	for _, want := range []string{"Checkout is down", "Hotfix payments"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected the text output to contain '%s', got: %s", want, out)
+		}
+	}
+
+	runTodoTUI = func(types.TodoItems) error { return tui.ErrDumbTerminal }
+	if err := formatter.FormatTodoTUI(todoItems); !errors.Is(err, tui.ErrDumbTerminal) {
+		t.Errorf("Expected other errors to be returned, got: %v", err)
+	}
+}
+
+func TestFormatter_UpNextTUICrashFallsBackToText(t *testing.T) {
+	original := runUpNextTUI
+	var received []types.UpNextItem
+	runUpNextTUI = func(items []types.UpNextItem) error {
+		received = items
+		return fmt.Errorf("%w: index out of range", tui.ErrCrashed)
+	}
+	defer func() { runUpNextTUI = original }()
+
+	formatter := NewPlainFormatter()
+	items := []UpNextItem{{Kind: KindAssignedTicket, Item: TodoItem{ID: "jira-OPS-1", Title: "Checkout is down"}, Score: 50, Reasons: []string{"critical severity +50"}}}
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	os.Stdout = w
+	err = formatter.FormatUpNextTUI(items, 1)
+	_ = w.Close()
+	os.Stdout = stdout
+	out, _ := io.ReadAll(r)
+
+	if err != nil {
+		t.Fatalf("Expected no error after falling back, got %v", err)
+	}
+	if len(received) != 1 || received[0].Kind != KindAssignedTicket || received[0].Score != 50 || received[0].Item.Title != "Checkout is down" {
+		t.Errorf("Expected the item converted for the TUI, got %+v", received)
+	}
+	if !strings.Contains(string(out), "Checkout is down") {
+		t.Errorf("Expected the text output to contain the item, got: %s", out)
+	}
+}
+
+func TestTodoItems_Sections(t *testing.T) {
+	var todoItems TodoItems
+	sections := todoItems.Sections()
+
+	// Every list of every platform is a section, so that a new list can't be left out
+	lists := 0
+	platforms := reflect.ValueOf(todoItems)
+	for i := range platforms.NumField() {
+		lists += platforms.Field(i).NumField()
+	}
+	if len(sections) != lists {
+		t.Errorf("Expected %d sections, got %d", lists, len(sections))
+	}
+
+	for _, section := range sections {
+		*section.Items = append(*section.Items, TodoItem{ID: section.Kind})
+	}
+	if len(todoItems.JIRA.Watched) != 1 || todoItems.JIRA.Watched[0].ID != KindWatchedIssue || len(todoItems.Slack.Saved) != 1 {
+		t.Errorf("Expected the sections to point into the todo items, got %+v", todoItems)
+	}
+}
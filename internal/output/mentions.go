@@ -0,0 +1,94 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"daily/internal/activity"
+	"daily/internal/metrics"
+	"daily/internal/tui"
+)
+
+// sortMentions returns a copy of mentions sorted newest first
+func sortMentions(mentions []activity.Activity) []activity.Activity {
+	sorted := make([]activity.Activity, len(mentions))
+	copy(sorted, mentions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.After(sorted[j].Timestamp)
+	})
+	return sorted
+}
+
+// FormatMentions formats mentions for text output, newest first
+func (f *Formatter) FormatMentions(mentions []activity.Activity) string {
+	if len(mentions) == 0 {
+		return f.headerStyle.Render("No mentions found.")
+	}
+
+	var output strings.Builder
+
+	output.WriteString(f.titleStyle.Render(fmt.Sprintf("💬 Mentions (%d)", len(mentions))))
+	output.WriteString("\n")
+
+	for _, mention := range sortMentions(mentions) {
+		var item strings.Builder
+
+		timeStr := f.timeStyle.Render(mention.Timestamp.Format("Jan 02 15:04"))
+		item.WriteString(fmt.Sprintf("%s %s  %s\n", timeStr, f.getPlatformIcon(mention.Platform), mention.Title))
+
+		if mention.Description != "" {
+			item.WriteString(f.descriptionStyle.Render(mention.Description))
+			item.WriteString("\n")
+		}
+
+		if mention.URL != "" {
+			item.WriteString(f.urlStyle.Render("🔗 " + mention.URL))
+			item.WriteString("\n")
+		}
+
+		output.WriteString(f.activityStyle.Render(item.String()))
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// FormatMentionsJSON formats mentions as JSON, newest first
+func (f *Formatter) FormatMentionsJSON(mentions []activity.Activity) string {
+	sorted := sortMentions(mentions)
+
+	jsonOutput := struct {
+		Mentions []activity.Activity `json:"mentions"`
+		Summary  struct {
+			Total      int            `json:"total"`
+			ByPlatform map[string]int `json:"by_platform"`
+		} `json:"summary"`
+		Meta *metrics.Report `json:"meta,omitempty"`
+	}{
+		Mentions: sorted,
+		Meta:     f.meta,
+	}
+
+	jsonOutput.Summary.Total = len(sorted)
+	jsonOutput.Summary.ByPlatform = make(map[string]int)
+	for _, mention := range sorted {
+		jsonOutput.Summary.ByPlatform[mention.Platform]++
+	}
+
+	jsonBytes, err := json.MarshalIndent(jsonOutput, "", "  ")
+	if err != nil {
+		return fmt.Sprintf(`{"error": "Failed to marshal JSON: %s"}`, err.Error())
+	}
+
+	return string(jsonBytes) + "\n"
+}
+
+// FormatMentionsTUI displays mentions in the interactive TUI, falling back to text output
+func (f *Formatter) FormatMentionsTUI(mentions []activity.Activity) error {
+	if err := tui.RunMentionsTUI(sortMentions(mentions)); err != nil {
+		fmt.Print(f.FormatMentions(mentions))
+	}
+	return nil
+}
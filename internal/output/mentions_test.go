@@ -0,0 +1,67 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+)
+
+func testMentions() []activity.Activity {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	return []activity.Activity{
+		{ID: "1", Type: activity.ActivityTypeMention, Title: "Older mention", Platform: "confluence", Timestamp: base, URL: "https://example.atlassian.net/wiki/1"},
+		{ID: "2", Type: activity.ActivityTypeMention, Title: "Newer mention", Description: "Mentioned in owner/repo PullRequest", Platform: "github", Timestamp: base.Add(time.Hour)},
+	}
+}
+
+func TestFormatter_FormatMentions(t *testing.T) {
+	formatter := NewFormatter()
+
+	result := formatter.FormatMentions(testMentions())
+
+	for _, expected := range []string{"Mentions (2)", "Older mention", "Newer mention", "Mentioned in owner/repo PullRequest"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain '%s'", expected)
+		}
+	}
+
+	if strings.Index(result, "Newer mention") > strings.Index(result, "Older mention") {
+		t.Error("Expected mentions to be sorted newest first")
+	}
+}
+
+func TestFormatter_FormatMentions_Empty(t *testing.T) {
+	formatter := NewFormatter()
+
+	if result := formatter.FormatMentions(nil); !strings.Contains(result, "No mentions found") {
+		t.Errorf("Expected empty message, got '%s'", result)
+	}
+}
+
+func TestFormatter_FormatMentionsJSON(t *testing.T) {
+	formatter := NewFormatter()
+
+	var result struct {
+		Mentions []activity.Activity `json:"mentions"`
+		Summary  struct {
+			Total      int            `json:"total"`
+			ByPlatform map[string]int `json:"by_platform"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatMentionsJSON(testMentions())), &result); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	if result.Summary.Total != 2 {
+		t.Errorf("Expected total 2, got %d", result.Summary.Total)
+	}
+	if result.Summary.ByPlatform["github"] != 1 || result.Summary.ByPlatform["confluence"] != 1 {
+		t.Errorf("Expected one mention per platform, got %v", result.Summary.ByPlatform)
+	}
+	if result.Mentions[0].ID != "2" {
+		t.Errorf("Expected newest mention first, got '%s'", result.Mentions[0].ID)
+	}
+}
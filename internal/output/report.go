@@ -0,0 +1,89 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"daily/internal/cache"
+	"daily/internal/metrics"
+)
+
+// FormatRunReport formats the usage of a run as a trailer for verbose output, e.g.
+//
+//	📈 12 requests, 48.2 KB downloaded in 2.3s
+//	   github: 9 requests, 40.1 KB, 4987 rate limit remaining
+//	   cache: 1 hit, 1 miss
+func (f *Formatter) FormatRunReport(report *metrics.Report) string {
+	if report == nil {
+		return ""
+	}
+
+	var output strings.Builder
+	wallTime := time.Duration(report.WallTimeMS) * time.Millisecond
+	output.WriteString(fmt.Sprintf("📈 %s, %s downloaded in %s\n",
+		plural(report.Requests(), "request"), formatBytes(report.Bytes()), wallTime.Round(100*time.Millisecond)))
+
+	for _, name := range report.ProviderNames() {
+		usage := report.Providers[name]
+		line := fmt.Sprintf("   %s: %s, %s", name, plural(usage.Requests, "request"), formatBytes(usage.Bytes))
+		if usage.RateLimitRemaining != nil {
+			line += fmt.Sprintf(", %d rate limit remaining", *usage.RateLimitRemaining)
+		}
+		output.WriteString(line + "\n")
+	}
+
+	if len(report.Caches) > 0 {
+		totals := report.CacheTotals()
+		output.WriteString(fmt.Sprintf("   cache: %s, %s\n", plural(totals.Hits, "hit"), plural(totals.Misses, "miss")))
+	}
+
+	return output.String()
+}
+
+// FormatPruneResult formats the cache files deleted by pruning, e.g.
+//
+//	🧹 Pruned 2 cache files (48.2 KB)
+//	   summary_2024-01-02.json: expired
+//	   crash-todo-20240304-101500.txt: size limit
+func (f *Formatter) FormatPruneResult(result cache.PruneResult) string {
+	if len(result.Entries) == 0 {
+		return "🧹 Nothing to prune\n"
+	}
+
+	verb := "Pruned"
+	if result.DryRun {
+		verb = "Would prune"
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("🧹 %s %s (%s)\n", verb, plural(len(result.Entries), "cache file"), formatBytes(result.Bytes())))
+	for _, entry := range result.Entries {
+		output.WriteString(fmt.Sprintf("   %s: %s\n", entry.Name, entry.Reason))
+	}
+	return output.String()
+}
+
+// plural formats a count with its noun, e.g. "1 request" or "3 misses"
+func plural(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	if strings.HasSuffix(noun, "s") {
+		return fmt.Sprintf("%d %ses", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// formatBytes formats a size with a binary unit, e.g. "512 B" or "48.2 KB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	size, suffix := float64(n)/unit, "KB"
+	if size >= unit {
+		size, suffix = size/unit, "MB"
+	}
+	return fmt.Sprintf("%.1f %s", size, suffix)
+}
@@ -0,0 +1,86 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/cache"
+	"daily/internal/metrics"
+)
+
+func TestFormatter_FormatRunReport(t *testing.T) {
+	remaining := 4987
+	report := &metrics.Report{
+		Providers: map[string]metrics.ProviderUsage{
+			"jira":   {Requests: 1, Bytes: 512},
+			"github": {Requests: 9, Bytes: 41062, RateLimitRemaining: &remaining},
+		},
+		Caches: map[string]metrics.CacheUsage{
+			"github_teams": {Hits: 1},
+			"summary":      {Misses: 1},
+		},
+		WallTimeMS: 2340,
+	}
+
+	expected := "📈 10 requests, 40.6 KB downloaded in 2.3s\n" +
+		"   github: 9 requests, 40.1 KB, 4987 rate limit remaining\n" +
+		"   jira: 1 request, 512 B\n" +
+		"   cache: 1 hit, 1 miss\n"
+	if got := NewFormatter().FormatRunReport(report); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	if got := NewFormatter().FormatRunReport(nil); got != "" {
+		t.Errorf("Expected nothing without a report, got %q", got)
+	}
+}
+
+func TestFormatter_FormatPruneResult(t *testing.T) {
+	result := cache.PruneResult{Entries: []cache.PrunedEntry{
+		{Name: "summary_2024-01-02.json", Bytes: 40000, Reason: "expired"},
+		{Name: "crash-todo-20240304-101500.txt", Bytes: 9357, Reason: "size limit"},
+	}}
+
+	expected := "🧹 Pruned 2 cache files (48.2 KB)\n" +
+		"   summary_2024-01-02.json: expired\n" +
+		"   crash-todo-20240304-101500.txt: size limit\n"
+	if got := NewFormatter().FormatPruneResult(result); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	result.DryRun = true
+	result.Entries = result.Entries[:1]
+	if got := NewFormatter().FormatPruneResult(result); !strings.HasPrefix(got, "🧹 Would prune 1 cache file (39.1 KB)") {
+		t.Errorf("Expected a dry run of 1 file, got %q", got)
+	}
+
+	if got := NewFormatter().FormatPruneResult(cache.PruneResult{}); got != "🧹 Nothing to prune\n" {
+		t.Errorf("Expected nothing to prune, got %q", got)
+	}
+}
+
+func TestFormatter_FormatJSON_Meta(t *testing.T) {
+	summary := &activity.Summary{Date: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)}
+
+	formatter := NewFormatter()
+	if strings.Contains(formatter.FormatJSON(summary), `"meta"`) {
+		t.Error("Expected no meta key without a report")
+	}
+
+	formatter.SetMeta(&metrics.Report{
+		Providers:  map[string]metrics.ProviderUsage{"github": {Requests: 3, Bytes: 1200}},
+		WallTimeMS: 800,
+	})
+	var result struct {
+		Meta metrics.Report `json:"meta"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatJSON(summary)), &result); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if result.Meta.Providers["github"].Requests != 3 || result.Meta.WallTimeMS != 800 {
+		t.Errorf("Expected the run usage under meta, got %+v", result.Meta)
+	}
+}
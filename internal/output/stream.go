@@ -0,0 +1,159 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"daily/internal/activity"
+)
+
+// SetMaxItems caps the activities listed by summaries, the most recent being kept. Counts and
+// statistics still cover every activity. Zero or less lists them all.
+func (f *Formatter) SetMaxItems(maxItems int) {
+	f.maxItems = maxItems
+}
+
+// limitActivities returns the activities listed under the max items, from activities sorted by
+// time, and the number of activities left out
+func (f *Formatter) limitActivities(activities []activity.Activity) ([]activity.Activity, int) {
+	if f.maxItems <= 0 || len(activities) <= f.maxItems {
+		return activities, 0
+	}
+	omitted := len(activities) - f.maxItems
+	return activities[omitted:], omitted
+}
+
+// truncationNotice describes a list of activities cut by the max items, e.g. "Showing 500 of
+// 12,431 activities (the most recent)"
+func truncationNotice(shown, total int) string {
+	return fmt.Sprintf("Showing %s of %s activities (the most recent)", formatCount(shown), formatCount(total))
+}
+
+// formatCount formats a number with thousands separators, e.g. "12,431"
+func formatCount(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return sign + digits
+}
+
+// jsonSummary holds the statistics of a summary in JSON output
+type jsonSummary struct {
+	Total      int            `json:"total"`
+	ByPlatform map[string]int `json:"by_platform"`
+	ByType     map[string]int `json:"by_type"`
+	// Seconds of work logged per day (YYYY-MM-DD), only present when worklogs exist
+	TimeLoggedByDay map[string]int `json:"time_logged_seconds_by_day,omitempty"`
+	// Hours spent in calendar meetings, only present when meetings have a duration
+	MeetingHours float64 `json:"meeting_hours,omitempty"`
+}
+
+// jsonField is a field of the JSON object written by WriteJSON
+type jsonField struct {
+	name  string
+	value any
+	omit  bool // Leave the field out, like omitempty
+}
+
+// WriteJSON writes a summary as JSON to w, in the layout of FormatJSON. Activities are encoded
+// one at a time so that memory doesn't grow with the size of the output. When activities are
+// left out by the max items, "truncated" and "omitted" tell so, and the statistics under
+// "summary" still count every activity.
+func (f *Formatter) WriteJSON(w io.Writer, summary *activity.Summary) error {
+	// Sort activities by timestamp for consistent output
+	activities := make([]activity.Activity, len(summary.Activities))
+	copy(activities, summary.Activities)
+	sort.Slice(activities, func(i, j int) bool {
+		return activities[i].Timestamp.Before(activities[j].Timestamp)
+	})
+	shown, omitted := f.limitActivities(activities)
+
+	// Calculate summary statistics
+	stats := jsonSummary{
+		Total:           len(activities),
+		ByPlatform:      make(map[string]int),
+		ByType:          make(map[string]int),
+		TimeLoggedByDay: timeLoggedByDay(activities),
+		MeetingHours:    meetingHours(activities),
+	}
+	for _, act := range activities {
+		stats.ByPlatform[act.Platform]++
+		stats.ByType[string(act.Type)]++
+	}
+
+	fields := []jsonField{
+		{name: "date", value: summary.Date.Format("2006-01-02")},
+		{name: "activities", value: shown},
+		{name: "truncated", value: true, omit: omitted == 0},
+		{name: "omitted", value: omitted, omit: omitted == 0},
+		{name: "summary", value: stats},
+		{name: "goals", value: summary.Goals, omit: len(summary.Goals) == 0},
+		{name: "meta", value: f.meta, omit: f.meta == nil},
+	}
+
+	// Write errors are kept by the buffered writer and returned by the final flush
+	out := bufio.NewWriter(w)
+	_, _ = out.WriteString("{")
+	first := true
+	for _, field := range fields {
+		if field.omit {
+			continue
+		}
+		if !first {
+			_, _ = out.WriteString(",")
+		}
+		first = false
+		_, _ = fmt.Fprintf(out, "\n  %q: ", field.name)
+
+		var err error
+		if field.name == "activities" {
+			err = writeJSONArray(out, shown)
+		} else {
+			err = writeJSONValue(out, field.value, "  ")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+	}
+	_, _ = out.WriteString("\n}\n")
+	return out.Flush()
+}
+
+// writeJSONArray writes activities as an indented JSON array, one element at a time
+func writeJSONArray(out *bufio.Writer, activities []activity.Activity) error {
+	if len(activities) == 0 {
+		_, _ = out.WriteString("[]")
+		return nil
+	}
+	_, _ = out.WriteString("[")
+	for i, act := range activities {
+		if i > 0 {
+			_, _ = out.WriteString(",")
+		}
+		_, _ = out.WriteString("\n    ")
+		if err := writeJSONValue(out, act, "    "); err != nil {
+			return err
+		}
+	}
+	_, _ = out.WriteString("\n  ]")
+	return nil
+}
+
+// writeJSONValue writes a value as JSON indented by two spaces, nested under prefix
+func writeJSONValue(out *bufio.Writer, value any, prefix string) error {
+	data, err := json.MarshalIndent(value, prefix, "  ")
+	if err != nil {
+		return err
+	}
+	_, _ = out.Write(data)
+	return nil
+}
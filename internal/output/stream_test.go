@@ -0,0 +1,117 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+)
+
+// manyActivities returns count commits one minute apart, the last being the most recent
+func manyActivities(count int) *activity.Summary {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	summary := &activity.Summary{Date: start}
+	for i := range count {
+		platform := "github"
+		if i%2 == 1 {
+			platform = "jira"
+		}
+		summary.Activities = append(summary.Activities, activity.Activity{
+			ID:        string(rune('a' + i)),
+			Type:      activity.ActivityTypeCommit,
+			Title:     "Activity " + string(rune('A'+i)),
+			Platform:  platform,
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	return summary
+}
+
+func TestFormatter_MaxItems_JSON(t *testing.T) {
+	formatter := NewFormatter()
+	formatter.SetMaxItems(3)
+
+	var jsonOutput struct {
+		Activities []activity.Activity `json:"activities"`
+		Truncated  bool                `json:"truncated"`
+		Omitted    int                 `json:"omitted"`
+		Summary    struct {
+			Total      int            `json:"total"`
+			ByPlatform map[string]int `json:"by_platform"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatJSON(manyActivities(10))), &jsonOutput); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+
+	if len(jsonOutput.Activities) != 3 || jsonOutput.Activities[0].Title != "Activity H" || jsonOutput.Activities[2].Title != "Activity J" {
+		t.Errorf("Expected the 3 most recent activities, got %+v", jsonOutput.Activities)
+	}
+	if !jsonOutput.Truncated || jsonOutput.Omitted != 7 {
+		t.Errorf("Expected the truncation marker with 7 omitted, got %t and %d", jsonOutput.Truncated, jsonOutput.Omitted)
+	}
+	// Statistics cover every activity
+	if jsonOutput.Summary.Total != 10 || jsonOutput.Summary.ByPlatform["github"] != 5 || jsonOutput.Summary.ByPlatform["jira"] != 5 {
+		t.Errorf("Expected the statistics of the 10 activities, got %+v", jsonOutput.Summary)
+	}
+
+	// Below the limit, there is no marker
+	if result := formatter.FormatJSON(manyActivities(3)); strings.Contains(result, "truncated") || strings.Contains(result, "omitted") {
+		t.Errorf("Expected no truncation marker, got:\n%s", result)
+	}
+}
+
+func TestFormatter_MaxItems_Text(t *testing.T) {
+	formatter := NewFormatter()
+	formatter.SetMaxItems(3)
+	summary := manyActivities(10)
+
+	for name, result := range map[string]string{
+		"full":    formatter.FormatSummary(summary),
+		"compact": formatter.FormatCompactSummary(summary),
+	} {
+		if !strings.Contains(result, "Showing 3 of 10 activities") {
+			t.Errorf("%s: expected the truncation notice, got:\n%s", name, result)
+		}
+		if !strings.Contains(result, "10 activities") || !strings.Contains(result, "Activity J") || strings.Contains(result, "Activity G") {
+			t.Errorf("%s: expected the full count and the 3 most recent activities, got:\n%s", name, result)
+		}
+	}
+	if result := formatter.FormatSummary(summary); !strings.Contains(result, "across 2 platforms") {
+		t.Errorf("Expected the platforms of every activity, got:\n%s", result)
+	}
+	// Platform headers count the activities shown of the ones of the platform
+	if result := formatter.FormatSummary(summary); !strings.Contains(result, "Github (1 of 5)") || !strings.Contains(result, "Jira (2 of 5)") {
+		t.Errorf("Expected the shown and total counts of each platform, got:\n%s", result)
+	}
+
+	formatter.SetMaxItems(0)
+	if result := formatter.FormatSummary(summary); strings.Contains(result, "Showing") || !strings.Contains(result, "Activity A") || !strings.Contains(result, "Jira (5)") {
+		t.Errorf("Expected every activity without limit, got:\n%s", result)
+	}
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestFormatter_WriteJSON_WriteError(t *testing.T) {
+	if err := NewFormatter().WriteJSON(failingWriter{}, manyActivities(2)); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Expected the write error, got %v", err)
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := map[int]string{0: "0", 500: "500", 1000: "1,000", 12431: "12,431", 1234567: "1,234,567", -4200: "-4,200"}
+	for n, expected := range tests {
+		if result := formatCount(n); result != expected {
+			t.Errorf("formatCount(%d): expected %q, got %q", n, expected, result)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"daily/internal/metrics"
+	"daily/internal/severity"
+	"daily/internal/tui"
+	"daily/internal/tui/types"
+)
+
+// Kinds of the items of the up-next list: the todo section or review list they come from
+const (
+	KindReviewRequest     = "review_request"
+	KindOpenPR            = "open_pr"
+	KindPendingReview     = "pending_review"
+	KindAssignedTicket    = "assigned_ticket"
+	KindJIRAMention       = "jira_mention"
+	KindReportedIssue     = "reported_issue"
+	KindWatchedIssue      = "watched_issue"
+	KindObsidianTask      = "obsidian_task"
+	KindConfluenceMention = "confluence_mention"
+	KindConfluenceComment = "confluence_comment"
+	KindConfluenceTask    = "confluence_task"
+	KindWatchedPage       = "watched_page"
+	KindOpenMR            = "open_mr"
+	KindPendingMRReview   = "pending_mr_review"
+	KindGitLabIssue       = "gitlab_issue"
+	KindSavedMessage      = "saved_message"
+)
+
+// kindIcons are the icons of the kinds of items in the up-next list
+var kindIcons = map[string]string{
+	KindReviewRequest:     "👁️",
+	KindOpenPR:            "🔀",
+	KindPendingReview:     "👁️",
+	KindAssignedTicket:    "🎯",
+	KindJIRAMention:       "💬",
+	KindReportedIssue:     "📣",
+	KindWatchedIssue:      "👀",
+	KindObsidianTask:      "📝",
+	KindConfluenceMention: "📋",
+	KindConfluenceComment: "💭",
+	KindConfluenceTask:    "☑️",
+	KindWatchedPage:       "🔄",
+	KindOpenMR:            "🔀",
+	KindPendingMRReview:   "👁️",
+	KindGitLabIssue:       "🦊",
+	KindSavedMessage:      "🔖",
+}
+
+// Weights of the signals ranking the up-next list
+const (
+	weightCritical      = 50
+	weightHigh          = 30
+	weightNormal        = 10
+	weightOverdue       = 40
+	weightDueSoon       = 20 // Due within dueSoonDays
+	weightReadyToMerge  = 35
+	weightDirectReview  = 25
+	weightTeamReview    = 15
+	weightStaleReview   = 15
+	weightInProgress    = 15
+	weightMention       = 10
+	weightCommentOnPage = 5
+	weightRecent        = 5 // Updated within the last day
+	weightCIFailing     = -10
+	weightMergeQueue    = -30
+
+	dueSoonDays = 2
+)
+
+// urlKinds are the kinds of forge and ticket items, which are identified by their URL: a PR
+// has different IDs as review request and open PR, and PR numbers repeat across repositories.
+// The other items are identified by their ID, as their URL may be shared, e.g. by the tasks of
+// an Obsidian note.
+var urlKinds = map[string]bool{
+	KindReviewRequest:   true,
+	KindOpenPR:          true,
+	KindPendingReview:   true,
+	KindOpenMR:          true,
+	KindPendingMRReview: true,
+	KindGitLabIssue:     true,
+	KindAssignedTicket:  true,
+	KindJIRAMention:     true,
+	KindReportedIssue:   true,
+	KindWatchedIssue:    true,
+}
+
+// severityWeights are the weights of the severity levels
+var severityWeights = map[string]int{
+	string(severity.Critical): weightCritical,
+	string(severity.High):     weightHigh,
+	string(severity.Normal):   weightNormal,
+}
+
+// UpNextItem is a todo item or review request ranked in the up-next list
+type UpNextItem struct {
+	Kind    string   `json:"kind"` // KindReviewRequest, KindAssignedTicket...
+	Item    TodoItem `json:"item"`
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"` // Signals that made the score, e.g. "overdue +40"
+}
+
+// RankUpNext merges the todo items and review requests into a single list, highest score
+// first then most recently updated. An item listed in several places, like a review request
+// also in the pending reviews, is kept once, as a review request when it is one since review
+// requests hold the most details.
+func RankUpNext(todoItems TodoItems, reviewItems ReviewItems, now time.Time) []UpNextItem {
+	var ranked []UpNextItem
+	seen := make(map[string]bool)
+	add := func(item UpNextItem) {
+		key := item.Item.ID
+		if urlKinds[item.Kind] && item.Item.URL != "" {
+			key = item.Item.URL
+		}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		ranked = append(ranked, item)
+	}
+
+	for _, review := range reviewItems {
+		item := UpNextItem{Kind: KindReviewRequest, Item: review.TodoItem}
+		scoreReview(&item, review)
+		scoreItem(&item, now)
+		add(item)
+	}
+	for _, section := range todoItems.Sections() {
+		for _, todo := range *section.Items {
+			item := UpNextItem{Kind: section.Kind, Item: todo}
+			scoreKind(&item)
+			scoreItem(&item, now)
+			add(item)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Item.UpdatedAt.After(ranked[j].Item.UpdatedAt)
+	})
+	return ranked
+}
+
+// addScore adds a signal to the score of an item and to its reasons
+func (item *UpNextItem) addScore(reason string, weight int) {
+	item.Score += weight
+	item.Reasons = append(item.Reasons, fmt.Sprintf("%s %+d", reason, weight))
+}
+
+// scoreReview scores the signals of a review request
+func scoreReview(item *UpNextItem, review ReviewItem) {
+	if review.RequestType == ReviewRequestTeam {
+		item.addScore("team review requested", weightTeamReview)
+	} else {
+		item.addScore("review requested", weightDirectReview)
+	}
+	if review.IsStale {
+		item.addScore(fmt.Sprintf("waiting %d days", review.AgeDays), weightStaleReview)
+	}
+	if review.CIStatus.State == "failure" {
+		item.addScore("CI failing", weightCIFailing)
+	}
+	if review.InMergeQueue {
+		item.addScore("in merge queue", weightMergeQueue)
+	}
+}
+
+// scoreKind scores the kind of a todo item
+func scoreKind(item *UpNextItem) {
+	switch item.Kind {
+	case KindPendingReview, KindPendingMRReview:
+		item.addScore("review requested", weightDirectReview)
+	case KindOpenPR:
+		if item.Item.ReadyToMerge {
+			item.addScore("ready to merge", weightReadyToMerge)
+		}
+	case KindAssignedTicket:
+		if item.Item.StatusCategory == "indeterminate" {
+			item.addScore("in progress", weightInProgress)
+		}
+	case KindJIRAMention, KindConfluenceMention:
+		item.addScore("mentioned", weightMention)
+	case KindConfluenceComment:
+		item.addScore("comment on your page", weightCommentOnPage)
+	}
+}
+
+// scoreItem scores the signals shared by every item: severity, due date and recency
+func scoreItem(item *UpNextItem, now time.Time) {
+	if weight, ok := severityWeights[item.Item.Severity]; ok {
+		item.addScore(item.Item.Severity+" severity", weight)
+	}
+
+	if due := item.Item.DueDate; due != nil {
+		switch days := int(due.Sub(now).Hours() / 24); {
+		case item.Item.IsOverdue || due.Before(now):
+			item.addScore("overdue", weightOverdue)
+		case days < dueSoonDays:
+			item.addScore("due soon", weightDueSoon)
+		}
+	}
+
+	if !item.Item.UpdatedAt.IsZero() && now.Sub(item.Item.UpdatedAt) < 24*time.Hour {
+		item.addScore("updated in the last day", weightRecent)
+	}
+}
+
+// upNextIcon returns the icon of an item of the up-next list
+func upNextIcon(item UpNextItem) string {
+	if item.Item.IsOverdue {
+		return "⚠️"
+	}
+	if icon := issueTypeIcon(item.Item.IssueType); icon != "" {
+		return icon
+	}
+	if icon, ok := kindIcons[item.Kind]; ok {
+		return icon
+	}
+	return "📋"
+}
+
+// FormatUpNext formats the up-next list for text output, total being the number of items
+// ranked before the list was cut
+func (f *Formatter) FormatUpNext(items []UpNextItem, total int) string {
+	var output strings.Builder
+
+	output.WriteString(f.titleStyle.Render("⏭️ Up Next"))
+	output.WriteString("\n")
+
+	if len(items) == 0 {
+		output.WriteString(f.headerStyle.Render("No pending items found."))
+		output.WriteString("\n")
+		return output.String()
+	}
+
+	output.WriteString(f.headerStyle.Render(fmt.Sprintf("Top %d of %d pending items", len(items), total)))
+	output.WriteString("\n\n")
+
+	for i, item := range items {
+		var itemContent strings.Builder
+
+		title := f.severityTitle(item.Item.Title, item.Item.Severity)
+		itemContent.WriteString(fmt.Sprintf("%2d. %s %s", i+1, upNextIcon(item), title))
+		itemContent.WriteString("\n")
+
+		reasons := fmt.Sprintf("Score %d", item.Score)
+		if len(item.Reasons) > 0 {
+			reasons += ": " + strings.Join(item.Reasons, " · ")
+		}
+		itemContent.WriteString(f.descriptionStyle.Render(reasons))
+		itemContent.WriteString("\n")
+
+		if item.Item.URL != "" {
+			itemContent.WriteString(f.urlStyle.Render("🔗 " + item.Item.URL))
+			itemContent.WriteString("\n")
+		}
+
+		output.WriteString(f.activityStyle.Render(itemContent.String()))
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// FormatUpNextJSON formats the up-next list as JSON, total being the number of items ranked
+// before the list was cut
+func (f *Formatter) FormatUpNextJSON(items []UpNextItem, total int) string {
+	jsonOutput := struct {
+		UpNext  []UpNextItem `json:"up_next"`
+		Summary struct {
+			Total  int            `json:"total"`
+			Listed int            `json:"listed"`
+			ByKind map[string]int `json:"by_kind"`
+		} `json:"summary"`
+		Meta *metrics.Report `json:"meta,omitempty"`
+	}{
+		UpNext: items,
+		Meta:   f.meta,
+	}
+	if jsonOutput.UpNext == nil {
+		jsonOutput.UpNext = []UpNextItem{}
+	}
+
+	jsonOutput.Summary.Total = total
+	jsonOutput.Summary.Listed = len(items)
+	jsonOutput.Summary.ByKind = make(map[string]int)
+	for _, item := range items {
+		jsonOutput.Summary.ByKind[item.Kind]++
+	}
+
+	jsonBytes, err := json.MarshalIndent(jsonOutput, "", "  ")
+	if err != nil {
+		return fmt.Sprintf(`{"error": "Failed to marshal JSON: %s"}`, err.Error())
+	}
+
+	return string(jsonBytes) + "\n"
+}
+
+// runUpNextTUI is replaced in tests
+var runUpNextTUI = tui.RunUpNextTUI
+
+// FormatUpNextTUI displays the up-next list in the interactive TUI. When the TUI crashes, the
+// list is printed as text instead.
+func (f *Formatter) FormatUpNextTUI(items []UpNextItem, total int) error {
+	err := runUpNextTUI(convertUpNextItems(items))
+	if errors.Is(err, tui.ErrCrashed) {
+		fmt.Print(f.FormatUpNext(items, total))
+		return nil
+	}
+	return err
+}
+
+// convertUpNextItems converts the up-next list to the TUI types
+func convertUpNextItems(items []UpNextItem) []types.UpNextItem {
+	result := make([]types.UpNextItem, len(items))
+	for i, item := range items {
+		result[i] = types.UpNextItem{
+			Kind:    item.Kind,
+			Item:    convertTodoItems([]TodoItem{item.Item})[0],
+			Score:   item.Score,
+			Reasons: item.Reasons,
+		}
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testUpNext() (TodoItems, ReviewItems, time.Time) {
+	now := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	tomorrow := now.Add(24 * time.Hour)
+	lastWeek := now.Add(-7 * 24 * time.Hour)
+
+	todoItems := TodoItems{
+		GitHub: GitHubTodos{
+			OpenPRs: []TodoItem{{ID: "github-pr-1", Title: "Add caching", URL: "https://github.com/acme/app/pull/1", UpdatedAt: lastWeek, ReadyToMerge: true}},
+			// Also listed as a review request
+			PendingReviews: []TodoItem{{ID: "github-review-2", Title: "Fix login", URL: "https://github.com/acme/app/pull/2", UpdatedAt: lastWeek}},
+		},
+		JIRA: JIRATodos{
+			AssignedTickets: []TodoItem{
+				{ID: "jira-OPS-1", Title: "Checkout is down", URL: "https://acme.atlassian.net/browse/OPS-1", UpdatedAt: now.Add(-time.Hour), Severity: "critical", StatusCategory: "indeterminate"},
+				{ID: "jira-OPS-2", Title: "Rotate keys", URL: "https://acme.atlassian.net/browse/OPS-2", UpdatedAt: lastWeek, DueDate: &tomorrow},
+			},
+		},
+		// Tasks of the same note share its URL
+		Obsidian: ObsidianTodos{Tasks: []TodoItem{
+			{ID: "obsidian-task-inbox.md:3", Title: "Water the plants", URL: "obsidian://open?vault=notes&file=inbox.md", UpdatedAt: lastWeek},
+			{ID: "obsidian-task-inbox.md:4", Title: "Call the plumber", URL: "obsidian://open?vault=notes&file=inbox.md", UpdatedAt: lastWeek},
+		}},
+	}
+	reviewItems := ReviewItems{
+		{TodoItem: TodoItem{ID: "github-review-2", Title: "Fix login", URL: "https://github.com/acme/app/pull/2", UpdatedAt: lastWeek}, RequestType: ReviewRequestUser, IsStale: true, AgeDays: 7, CIStatus: CIStatus{State: "failure"}},
+		{TodoItem: TodoItem{ID: "github-review-3", Title: "Bump deps", URL: "https://github.com/acme/app/pull/3", UpdatedAt: lastWeek}, RequestType: ReviewRequestTeam, InMergeQueue: true},
+	}
+	return todoItems, reviewItems, now
+}
+
+func TestRankUpNext(t *testing.T) {
+	todoItems, reviewItems, now := testUpNext()
+
+	ranked := RankUpNext(todoItems, reviewItems, now)
+
+	var ids []string
+	for _, item := range ranked {
+		ids = append(ids, item.Item.ID)
+	}
+	// OPS-1: critical 50 + in progress 15 + recent 5, PR 1: ready to merge 35, review 2: review 25
+	// + stale 15 - CI 10, OPS-2: due soon 20, review 3: team 15 - merge queue 30
+	expected := []string{"jira-OPS-1", "github-pr-1", "github-review-2", "jira-OPS-2", "obsidian-task-inbox.md:3", "obsidian-task-inbox.md:4", "github-review-3"}
+	if strings.Join(ids, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected items %v, got %v", expected, ids)
+	}
+
+	scores := map[string]int{"jira-OPS-1": 70, "github-review-2": 30, "github-pr-1": 35, "jira-OPS-2": 20, "obsidian-task-inbox.md:3": 0, "obsidian-task-inbox.md:4": 0, "github-review-3": -15}
+	for _, item := range ranked {
+		if item.Score != scores[item.Item.ID] {
+			t.Errorf("Expected %s to score %d, got %d (%v)", item.Item.ID, scores[item.Item.ID], item.Score, item.Reasons)
+		}
+	}
+
+	if ranked[2].Kind != KindReviewRequest {
+		t.Errorf("Expected the PR listed twice to be kept as a review request, got %s", ranked[2].Kind)
+	}
+	expectedReasons := []string{"review requested +25", "waiting 7 days +15", "CI failing -10"}
+	if strings.Join(ranked[2].Reasons, ",") != strings.Join(expectedReasons, ",") {
+		t.Errorf("Expected reasons %v, got %v", expectedReasons, ranked[2].Reasons)
+	}
+	if len(ranked[4].Reasons) != 0 {
+		t.Errorf("Expected no reason for an item without signal, got %v", ranked[4].Reasons)
+	}
+}
+
+func TestRankUpNext_DueDates(t *testing.T) {
+	now := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	nextWeek := now.Add(7 * 24 * time.Hour)
+	todoItems := TodoItems{JIRA: JIRATodos{AssignedTickets: []TodoItem{
+		{ID: "overdue", DueDate: &yesterday, IsOverdue: true},
+		{ID: "later", DueDate: &nextWeek},
+	}}}
+
+	ranked := RankUpNext(todoItems, nil, now)
+
+	if ranked[0].Item.ID != "overdue" || ranked[0].Score != weightOverdue {
+		t.Errorf("Expected the overdue ticket first with %d, got %s with %d", weightOverdue, ranked[0].Item.ID, ranked[0].Score)
+	}
+	if ranked[1].Score != 0 {
+		t.Errorf("Expected no score for a ticket due next week, got %d (%v)", ranked[1].Score, ranked[1].Reasons)
+	}
+}
+
+func TestFormatter_FormatUpNext(t *testing.T) {
+	todoItems, reviewItems, now := testUpNext()
+	ranked := RankUpNext(todoItems, reviewItems, now)
+	formatter := NewPlainFormatter()
+
+	result := formatter.FormatUpNext(ranked[:2], len(ranked))
+
+	for _, expected := range []string{"Up Next", "Top 2 of 7 pending items", " 1. ", "Checkout is down", "Score 70: in progress +15 · critical severity +50", "https://github.com/acme/app/pull/1"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, result)
+		}
+	}
+	if strings.Contains(result, "Fix login") {
+		t.Error("Expected the items past the top to be left out")
+	}
+
+	if result := formatter.FormatUpNext(nil, 0); !strings.Contains(result, "No pending items found") {
+		t.Errorf("Expected empty message, got '%s'", result)
+	}
+}
+
+func TestFormatter_FormatUpNextJSON(t *testing.T) {
+	todoItems, reviewItems, now := testUpNext()
+	ranked := RankUpNext(todoItems, reviewItems, now)
+	formatter := NewFormatter()
+
+	var result struct {
+		UpNext  []UpNextItem `json:"up_next"`
+		Summary struct {
+			Total  int            `json:"total"`
+			Listed int            `json:"listed"`
+			ByKind map[string]int `json:"by_kind"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(formatter.FormatUpNextJSON(ranked[:3], len(ranked))), &result); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v", err)
+	}
+
+	if result.Summary.Total != 7 || result.Summary.Listed != 3 {
+		t.Errorf("Expected 3 of 7 items, got %d of %d", result.Summary.Listed, result.Summary.Total)
+	}
+	if result.Summary.ByKind[KindReviewRequest] != 1 || result.Summary.ByKind[KindAssignedTicket] != 1 {
+		t.Errorf("Expected the items counted by kind, got %v", result.Summary.ByKind)
+	}
+	if result.UpNext[0].Item.ID != "jira-OPS-1" || len(result.UpNext[0].Reasons) != 3 {
+		t.Errorf("Expected the first item with its reasons, got %+v", result.UpNext[0])
+	}
+
+	if empty := formatter.FormatUpNextJSON(nil, 0); !strings.Contains(empty, `"up_next": []`) {
+		t.Errorf("Expected an empty list, got: %s", empty)
+	}
+}
@@ -0,0 +1,380 @@
+package calendar
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"daily/internal/statedir"
+)
+
+// defaultOAuthURL is the Google OAuth server signing in with the device flow
+const defaultOAuthURL = "https://oauth2.googleapis.com"
+
+// scope grants read access to the calendars and their events
+const scope = "https://www.googleapis.com/auth/calendar.readonly"
+
+// Grant types of the token requests
+const (
+	grantDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+	grantJWTBearer  = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	grantRefresh    = "refresh_token"
+)
+
+// Ways the cached token was obtained, a token of the other one is never used
+const (
+	methodDevice         = "device"
+	methodServiceAccount = "service_account"
+)
+
+// expiryMargin renews access tokens a little before they expire, so that they don't expire
+// during the run
+const expiryMargin = time.Minute
+
+// ErrNotSignedIn is returned when the device flow is configured but no token was obtained yet
+var ErrNotSignedIn = errors.New("not signed in to Google Calendar, run 'daily providers login calendar'")
+
+// token is an access token cached on disk, with the refresh token of the device flow
+type token struct {
+	Method       string    `json:"method"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// valid reports whether the token was obtained by method and can still be used at now
+func (t token) valid(method string, now time.Time) bool {
+	return t.Method == method && t.AccessToken != "" && now.Add(expiryMargin).Before(t.Expiry)
+}
+
+// tokenCache keeps the token in a JSON file only readable by the user
+type tokenCache struct {
+	path string // No caching when empty
+}
+
+// DefaultTokenPath returns the file of the state directory holding the calendar token
+func DefaultTokenPath() (string, error) {
+	return statedir.Path("calendar_token.json")
+}
+
+// Load returns the cached token, an empty token when none was cached yet
+func (c tokenCache) Load() (token, error) {
+	var cached token
+	if c.path == "" {
+		return cached, nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return cached, nil
+	}
+	if err != nil {
+		return cached, fmt.Errorf("failed to read the calendar token: %w", err)
+	}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return cached, fmt.Errorf("failed to parse the calendar token: %w", err)
+	}
+	return cached, nil
+}
+
+// Save writes the token, creating the cache directory if needed
+func (c tokenCache) Save(cached token) error {
+	if c.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the calendar token: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write the calendar token: %w", err)
+	}
+	return nil
+}
+
+// accessToken returns a valid access token: the cached one, or a new one from the service
+// account key or the refresh token of the device flow, cached for the next runs
+func (p *Provider) accessToken(ctx context.Context) (string, error) {
+	p.tokenMu.Lock()
+	defer p.tokenMu.Unlock()
+
+	cached, err := p.tokens.Load()
+	if err != nil {
+		return "", err
+	}
+
+	method := methodDevice
+	if p.config.ServiceAccountKey != "" {
+		method = methodServiceAccount
+	}
+	if cached.valid(method, time.Now()) {
+		return cached.AccessToken, nil
+	}
+
+	var renewed token
+	switch {
+	case method == methodServiceAccount:
+		renewed, err = p.serviceAccountToken(ctx)
+	case cached.Method == methodDevice && cached.RefreshToken != "":
+		renewed, err = p.refreshToken(ctx, cached.RefreshToken)
+	default:
+		return "", ErrNotSignedIn
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.tokens.Save(renewed); err != nil {
+		return "", err
+	}
+	return renewed.AccessToken, nil
+}
+
+// serviceAccountKey is the part of the JSON key of a service account used to sign in
+type serviceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// serviceAccountToken exchanges a JWT signed with the service account key for an access
+// token, impersonating the configured email when set
+func (p *Provider) serviceAccountToken(ctx context.Context) (token, error) {
+	data, err := os.ReadFile(p.config.ServiceAccountKey)
+	if err != nil {
+		return token{}, fmt.Errorf("failed to read the service account key: %w", err)
+	}
+	var key serviceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return token{}, fmt.Errorf("failed to parse the service account key: %w", err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return token{}, fmt.Errorf("the service account key has no client_email or private_key")
+	}
+
+	tokenURI := key.TokenURI
+	if tokenURI == "" {
+		tokenURI = p.oauthURL + "/token"
+	}
+
+	now := time.Now()
+	claims := map[string]any{
+		"iss":   key.ClientEmail,
+		"scope": scope,
+		"aud":   tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+	if p.config.Email != "" {
+		claims["sub"] = p.config.Email
+	}
+	assertion, err := signJWT(claims, key.PrivateKey)
+	if err != nil {
+		return token{}, fmt.Errorf("failed to sign in with the service account key: %w", err)
+	}
+
+	return p.requestToken(ctx, tokenURI, methodServiceAccount, url.Values{
+		"grant_type": {grantJWTBearer},
+		"assertion":  {assertion},
+	})
+}
+
+// signJWT returns the claims signed with RS256 by a PEM encoded PKCS#8 RSA key
+func signJWT(claims map[string]any, privateKey string) (string, error) {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return "", fmt.Errorf("invalid private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("invalid private key: %w", err)
+	}
+	rsaKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", fmt.Errorf("the private key isn't an RSA key")
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encode := base64.RawURLEncoding.EncodeToString
+	unsigned := encode([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." + encode(payload)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, rsaKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + encode(signature), nil
+}
+
+// refreshToken exchanges the refresh token of the device flow for a new access token
+func (p *Provider) refreshToken(ctx context.Context, refresh string) (token, error) {
+	renewed, err := p.requestToken(ctx, p.oauthURL+"/token", methodDevice, url.Values{
+		"grant_type":    {grantRefresh},
+		"refresh_token": {refresh},
+		"client_id":     {p.config.ClientID},
+		"client_secret": {p.config.ClientSecret},
+	})
+	if err != nil {
+		return token{}, fmt.Errorf("failed to refresh the calendar token, run 'daily providers login calendar' again: %w", err)
+	}
+	// Google only returns a refresh token on sign in, it stays valid after a refresh
+	if renewed.RefreshToken == "" {
+		renewed.RefreshToken = refresh
+	}
+	return renewed, nil
+}
+
+// DeviceCode is what the user is asked to do to sign in with the device flow
+type DeviceCode struct {
+	VerificationURL string
+	UserCode        string
+}
+
+// Login signs in with the OAuth device flow: prompt shows the code to enter at the
+// verification URL, then the token server is polled until the user has signed in. The token
+// is cached for the next runs.
+func (p *Provider) Login(ctx context.Context, prompt func(DeviceCode)) error {
+	if p.config.ClientID == "" {
+		return fmt.Errorf("calendar.client_id is required to sign in with the device flow")
+	}
+
+	var device struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURL string `json:"verification_url"`
+		ExpiresIn       int    `json:"expires_in"`
+		Interval        int    `json:"interval"`
+	}
+	form := url.Values{"client_id": {p.config.ClientID}, "scope": {scope}}
+	if err := p.postForm(ctx, p.oauthURL+"/device/code", form, &device); err != nil {
+		return fmt.Errorf("failed to start the device flow: %w", err)
+	}
+	prompt(DeviceCode{VerificationURL: device.VerificationURL, UserCode: device.UserCode})
+
+	interval := time.Duration(device.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(device.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		if err := p.sleep(ctx, interval); err != nil {
+			return err
+		}
+
+		signedIn, err := p.requestToken(ctx, p.oauthURL+"/token", methodDevice, url.Values{
+			"grant_type":    {grantDeviceCode},
+			"device_code":   {device.DeviceCode},
+			"client_id":     {p.config.ClientID},
+			"client_secret": {p.config.ClientSecret},
+		})
+
+		var oauthErr *oauthError
+		switch {
+		case errors.As(err, &oauthErr) && oauthErr.Code == "authorization_pending":
+			continue
+		case errors.As(err, &oauthErr) && oauthErr.Code == "slow_down":
+			interval += 5 * time.Second
+			continue
+		case err != nil:
+			return fmt.Errorf("failed to sign in to Google Calendar: %w", err)
+		}
+
+		p.tokenMu.Lock()
+		err = p.tokens.Save(signedIn)
+		p.tokenMu.Unlock()
+		return err
+	}
+	return fmt.Errorf("the device code expired before signing in, try again")
+}
+
+// oauthError is an error response of the OAuth server, e.g. "authorization_pending"
+type oauthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *oauthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Description)
+	}
+	return e.Code
+}
+
+// requestToken posts a token request and returns the token it grants
+func (p *Provider) requestToken(ctx context.Context, tokenURL, method string, form url.Values) (token, error) {
+	var granted struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := p.postForm(ctx, tokenURL, form, &granted); err != nil {
+		return token{}, err
+	}
+
+	return token{
+		Method:       method,
+		AccessToken:  granted.AccessToken,
+		RefreshToken: granted.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(granted.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// postForm posts a form to the OAuth server and decodes its JSON response into result, or
+// returns its error as an *oauthError
+func (p *Provider) postForm(ctx context.Context, postURL string, form url.Values, result any) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", postURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr oauthError
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Code != "" {
+			return &oauthErr
+		}
+		return fmt.Errorf("Google OAuth server returned status %d: %s", resp.StatusCode, resp.Status)
+	}
+	return json.Unmarshal(body, result)
+}
+
+// sleep waits for d, or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
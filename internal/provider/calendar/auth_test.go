@@ -0,0 +1,227 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestProvider_AccessToken_Refresh(t *testing.T) {
+	var grants []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.URL.Path != "/oauth/token" {
+			t.Errorf("Unexpected request %s: %v", r.URL, err)
+		}
+		grants = append(grants, r.PostForm.Get("grant_type"))
+		if r.PostForm.Get("refresh_token") != "1//refresh" || r.PostForm.Get("client_secret") != "GOCSPX-secret" {
+			t.Errorf("Expected the refresh token and client secret, got %v", r.PostForm)
+		}
+		_, _ = w.Write([]byte(`{"access_token": "ya29.refreshed", "expires_in": 3599, "token_type": "Bearer"}`))
+	}))
+	defer server.Close()
+
+	expired := signedIn
+	expired.Expiry = time.Now().Add(30 * time.Second)
+	p := newTestProvider(t, server.URL, provider.Config{}, expired)
+
+	// A token about to expire is refreshed and cached with the refresh token
+	accessToken, err := p.accessToken(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if accessToken != "ya29.refreshed" {
+		t.Errorf("Expected the refreshed token, got %q", accessToken)
+	}
+	cached, err := p.tokens.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cached.AccessToken != "ya29.refreshed" || cached.RefreshToken != "1//refresh" || !cached.valid(methodDevice, time.Now()) {
+		t.Errorf("Expected the refreshed token cached, got %+v", cached)
+	}
+
+	// The next runs use the cached token
+	if _, err := p.accessToken(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !slices.Equal(grants, []string{grantRefresh}) {
+		t.Errorf("Expected a single refresh, got %v", grants)
+	}
+}
+
+func TestProvider_AccessToken_Revoked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": "invalid_grant", "error_description": "Token has been expired or revoked."}`))
+	}))
+	defer server.Close()
+
+	revoked := token{Method: methodDevice, AccessToken: "ya29.old", RefreshToken: "1//revoked"}
+	p := newTestProvider(t, server.URL, provider.Config{}, revoked)
+
+	_, err := p.accessToken(context.Background())
+	if err == nil || err.Error() != "failed to refresh the calendar token, run 'daily providers login calendar' again: invalid_grant: Token has been expired or revoked." {
+		t.Errorf("Expected the refresh error, got %v", err)
+	}
+}
+
+func TestProvider_ServiceAccount(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate a key: %v", err)
+	}
+
+	var grants []string
+	var claims map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			if err := r.ParseForm(); err != nil {
+				t.Errorf("Failed to parse the token request: %v", err)
+			}
+			grants = append(grants, r.PostForm.Get("grant_type"))
+			if claims, err = verifyJWT(r.PostForm.Get("assertion"), &privateKey.PublicKey); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": "invalid_grant", "error_description": "Invalid JWT Signature."}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token": "ya29.service", "expires_in": 3599, "token_type": "Bearer"}`))
+		case "/calendar/v3/calendars/primary/events":
+			if auth := r.Header.Get("Authorization"); auth != "Bearer ya29.service" {
+				t.Errorf("Expected the token of the service account, got %q", auth)
+			}
+			writePrimaryEvents(w, r)
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("Failed to encode the key: %v", err)
+	}
+	key, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "daily@acme-calendar.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    server.URL + "/oauth/token",
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode the key file: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(keyPath, key, 0600); err != nil {
+		t.Fatalf("Failed to write the key file: %v", err)
+	}
+
+	// A token of the device flow isn't used by the service account
+	config := provider.Config{ServiceAccountKey: keyPath, Email: "jdoe@example.com"}
+	p := newTestProvider(t, server.URL, config, signedIn)
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 3 {
+		t.Errorf("Expected the meetings of the primary calendar, got %+v", activities)
+	}
+
+	if !slices.Equal(grants, []string{grantJWTBearer}) {
+		t.Fatalf("Expected a single JWT token request, got %v", grants)
+	}
+	if claims["iss"] != "daily@acme-calendar.iam.gserviceaccount.com" || claims["sub"] != "jdoe@example.com" ||
+		claims["scope"] != scope || claims["aud"] != server.URL+"/oauth/token" {
+		t.Errorf("Expected the service account impersonating the user, got %v", claims)
+	}
+	if cached, _ := p.tokens.Load(); cached.Method != methodServiceAccount || cached.AccessToken != "ya29.service" {
+		t.Errorf("Expected the service account token cached, got %+v", cached)
+	}
+}
+
+func TestProvider_Login(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/device/code":
+			_, _ = w.Write([]byte(`{"device_code": "AH-1Ng2Fq3s4t5u6v7w8x9y0z", "user_code": "GQVQ-JKEC", "verification_url": "https://www.google.com/device", "expires_in": 1800, "interval": 5}`))
+		case "/oauth/token":
+			if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != grantDeviceCode {
+				t.Errorf("Expected a device code token request, got %v: %v", r.PostForm, err)
+			}
+			// Pending, then too fast, then signed in
+			polls++
+			switch polls {
+			case 1:
+				w.WriteHeader(http.StatusPreconditionRequired)
+				_, _ = w.Write([]byte(`{"error": "authorization_pending", "error_description": "Precondition Required"}`))
+			case 2:
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"error": "slow_down", "error_description": "Forbidden"}`))
+			default:
+				_, _ = w.Write([]byte(`{"access_token": "ya29.device", "expires_in": 3599, "refresh_token": "1//refresh", "token_type": "Bearer"}`))
+			}
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := newTestProvider(t, server.URL, provider.Config{}, token{})
+
+	var waits []time.Duration
+	p.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	var code DeviceCode
+	if err := p.Login(context.Background(), func(c DeviceCode) { code = c }); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if code.VerificationURL != "https://www.google.com/device" || code.UserCode != "GQVQ-JKEC" {
+		t.Errorf("Expected the user code to enter, got %+v", code)
+	}
+
+	// Polled at the interval of the server, slowed down when asked to
+	if !slices.Equal(waits, []time.Duration{5 * time.Second, 5 * time.Second, 10 * time.Second}) {
+		t.Errorf("Expected 3 polls slowed down once, got %v", waits)
+	}
+
+	cached, err := p.tokens.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cached.AccessToken != "ya29.device" || cached.RefreshToken != "1//refresh" || cached.Method != methodDevice {
+		t.Errorf("Expected the signed in token cached, got %+v", cached)
+	}
+	info, err := os.Stat(p.tokens.path)
+	if err != nil {
+		t.Fatalf("Expected the token file, got: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the token only readable by the user, got %v", info.Mode())
+	}
+}
+
+func TestProvider_Login_RequiresClientID(t *testing.T) {
+	p := NewProvider(provider.Config{Enabled: true, ServiceAccountKey: "key.json"})
+	if err := p.Login(context.Background(), func(DeviceCode) {}); err == nil {
+		t.Error("Expected an error without client ID, got nil")
+	}
+}
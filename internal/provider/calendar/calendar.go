@@ -0,0 +1,304 @@
+// Package calendar reports the meetings of Google Calendar as activities.
+package calendar
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/metrics"
+	"daily/internal/provider"
+)
+
+// defaultURL is the Google Calendar API used when none is configured
+const defaultURL = "https://www.googleapis.com/calendar/v3"
+
+// defaultCalendar is the calendar read when none is configured, the user's own
+const defaultCalendar = "primary"
+
+// pageSize is the number of events fetched per request, the default of the API
+const pageSize = 250
+
+type Provider struct {
+	config provider.Config
+	client *http.Client
+
+	// oauthURL is the Google OAuth server of the device flow and token refreshes
+	oauthURL string
+	// tokens caches the access token on disk between runs, see accessToken
+	tokens  tokenCache
+	tokenMu sync.Mutex
+	// sleep waits between two polls of the device flow
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func NewProvider(config provider.Config) *Provider {
+	// Without a home directory the token is only kept for the run
+	tokenPath, _ := DefaultTokenPath()
+
+	return &Provider{
+		config: config,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: metrics.NewTransport("calendar"),
+		},
+		oauthURL: defaultOAuthURL,
+		tokens:   tokenCache{path: tokenPath},
+		sleep:    sleep,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "calendar"
+}
+
+func (p *Provider) IsConfigured() bool {
+	return p.config.Enabled && (p.config.ServiceAccountKey != "" || p.config.ClientID != "")
+}
+
+// ConfigSpec describes the configuration fields of the Google Calendar provider
+func (p *Provider) ConfigSpec() []provider.ConfigField {
+	return []provider.ConfigField{
+		{Name: "client_id", Description: "OAuth client ID (TVs and Limited Input devices) to sign in with 'daily providers login calendar', required without service_account_key"},
+		{Name: "client_secret", Secret: true, Description: "OAuth client secret of client_id"},
+		{Name: "service_account_key", Description: "Path to the JSON key of a service account the calendars are shared with, instead of client_id"},
+		{Name: "email", Description: "User impersonated by the service account, with domain-wide delegation"},
+		{Name: "calendars", Description: "Calendar IDs read, e.g. [\"primary\", \"team@group.calendar.google.com\"] (default primary)"},
+		{Name: "include_declined", Description: "Report the events you declined (default false)"},
+		{Name: "include_all_day", Description: "Report all-day events (default false)"},
+	}
+}
+
+// apiURL returns the URL of a Calendar API endpoint, e.g. "/calendars/primary/events"
+func (p *Provider) apiURL(path string, query url.Values) string {
+	apiURL := strings.TrimRight(cmp.Or(p.config.URL, defaultURL), "/") + path
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+	return apiURL
+}
+
+// calendars returns the IDs of the calendars read
+func (p *Provider) calendars() []string {
+	if len(p.config.Calendars) == 0 {
+		return []string{defaultCalendar}
+	}
+	return p.config.Calendars
+}
+
+// event is the part of a calendar event the activities use
+type event struct {
+	ID          string    `json:"id"`
+	Status      string    `json:"status"` // "confirmed", "tentative" or "cancelled"
+	Summary     string    `json:"summary"`
+	HTMLLink    string    `json:"htmlLink"`
+	HangoutLink string    `json:"hangoutLink"`
+	Start       eventTime `json:"start"`
+	End         eventTime `json:"end"`
+	Attendees   []struct {
+		Email          string `json:"email"`
+		Self           bool   `json:"self"`
+		Resource       bool   `json:"resource"` // A meeting room rather than a person
+		ResponseStatus string `json:"responseStatus"`
+	} `json:"attendees"`
+}
+
+// eventTime is when an event starts or ends: a time, or a date for all-day events
+type eventTime struct {
+	DateTime time.Time `json:"dateTime"`
+	Date     string    `json:"date"`
+}
+
+// allDay reports whether the event lasts whole days
+func (e event) allDay() bool {
+	return e.Start.DateTime.IsZero() && e.Start.Date != ""
+}
+
+// declined reports whether the user declined the event
+func (e event) declined() bool {
+	for _, attendee := range e.Attendees {
+		if attendee.Self {
+			return attendee.ResponseStatus == "declined"
+		}
+	}
+	return false
+}
+
+// attendees returns the number of people invited, rooms left out
+func (e event) attendees() int {
+	count := 0
+	for _, attendee := range e.Attendees {
+		if !attendee.Resource {
+			count++
+		}
+	}
+	return count
+}
+
+// GetActivities retrieves the meetings of the configured calendars starting in the time range
+// (for summary). Declined and all-day events are left out unless configured otherwise.
+func (p *Provider) GetActivities(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("Google Calendar provider not configured")
+	}
+
+	seen := make(map[string]bool)
+	var activities []activity.Activity
+	for _, calendarID := range p.calendars() {
+		events, err := p.listEvents(ctx, calendarID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the events of calendar %s: %w", calendarID, err)
+		}
+
+		for _, evt := range events {
+			// An event shared by several calendars is reported once
+			if seen[evt.ID] || evt.Status == "cancelled" {
+				continue
+			}
+			if evt.declined() && !p.config.IncludeDeclined {
+				continue
+			}
+			if evt.allDay() && !p.config.IncludeAllDay {
+				continue
+			}
+
+			act, err := newMeetingActivity(evt, from.Location())
+			if err != nil {
+				return nil, err
+			}
+			// The API lists the events overlapping the range, only those starting in it are kept
+			if act.Timestamp.Before(from) || !act.Timestamp.Before(to) {
+				continue
+			}
+			seen[evt.ID] = true
+			activities = append(activities, act)
+		}
+	}
+
+	return activities, nil
+}
+
+// newMeetingActivity converts an event into a meeting activity. All-day events are dated at
+// the start of their first day in location, without duration.
+func newMeetingActivity(evt event, location *time.Location) (activity.Activity, error) {
+	start, duration := evt.Start.DateTime, evt.End.DateTime.Sub(evt.Start.DateTime)
+	description := formatDuration(duration) + " meeting"
+	if evt.allDay() {
+		day, err := time.ParseInLocation("2006-01-02", evt.Start.Date, location)
+		if err != nil {
+			return activity.Activity{}, fmt.Errorf("invalid date %q of event %s", evt.Start.Date, evt.ID)
+		}
+		start, duration, description = day, 0, "All-day event"
+	}
+
+	attendees := evt.attendees()
+	switch attendees {
+	case 0:
+	case 1:
+		description += " with 1 attendee"
+	default:
+		description += fmt.Sprintf(" with %d attendees", attendees)
+	}
+
+	attendeeCount := ""
+	if attendees > 0 {
+		attendeeCount = strconv.Itoa(attendees)
+	}
+
+	return activity.Activity{
+		ID:              fmt.Sprintf("calendar-%s", evt.ID),
+		Type:            activity.ActivityTypeMeeting,
+		Title:           cmp.Or(evt.Summary, "(No title)"),
+		Description:     description,
+		URL:             cmp.Or(evt.HTMLLink, evt.HangoutLink),
+		Platform:        "calendar",
+		Timestamp:       start,
+		DurationSeconds: int(duration.Seconds()),
+		Details:         activity.NewDetails(activity.DetailAttendees, attendeeCount),
+	}, nil
+}
+
+// formatDuration formats a meeting duration, e.g. "45m", "1h" or "1h30m"
+func formatDuration(d time.Duration) string {
+	hours, minutes := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%02dm", hours, minutes)
+	}
+}
+
+// listEvents lists the events of a calendar overlapping the time range, recurring events
+// expanded into their occurrences, following the page tokens
+func (p *Provider) listEvents(ctx context.Context, calendarID string, from, to time.Time) ([]event, error) {
+	query := url.Values{
+		"timeMin":      {from.Format(time.RFC3339)},
+		"timeMax":      {to.Format(time.RFC3339)},
+		"singleEvents": {"true"},
+		"orderBy":      {"startTime"},
+		"maxResults":   {strconv.Itoa(pageSize)},
+	}
+
+	var events []event
+	for {
+		var page struct {
+			Items         []event `json:"items"`
+			NextPageToken string  `json:"nextPageToken"`
+		}
+		if err := p.get(ctx, p.apiURL("/calendars/"+url.PathEscape(calendarID)+"/events", query), &page); err != nil {
+			return nil, err
+		}
+		events = append(events, page.Items...)
+
+		if page.NextPageToken == "" {
+			return events, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
+
+// get decodes the JSON response of a GET request to the Calendar API into result
+func (p *Provider) get(ctx context.Context, getURL string, result any) error {
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", getURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("Google Calendar API returned status %d: %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("Google Calendar API returned status %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
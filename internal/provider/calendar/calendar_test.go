@@ -0,0 +1,283 @@
+package calendar
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+// primaryEventsPage1 is the first page of the events of the primary calendar, linked to the
+// second by its page token
+const primaryEventsPage1 = `{
+	"nextPageToken": "CigKGjRxYzVhZ2I",
+	"items": [
+		{"id": "4f1u0s7standup_20240115T093000Z", "status": "confirmed", "htmlLink": "https://www.google.com/calendar/event?eid=NGYxdTBzN3N0YW5kdXA", "summary": "Daily standup",
+		 "start": {"dateTime": "2024-01-15T10:30:00+01:00"}, "end": {"dateTime": "2024-01-15T10:45:00+01:00"},
+		 "attendees": [
+			{"email": "jdoe@example.com", "self": true, "responseStatus": "accepted"},
+			{"email": "asmith@example.com", "responseStatus": "accepted"},
+			{"email": "bmartin@example.com", "responseStatus": "needsAction"},
+			{"email": "cnguyen@example.com", "responseStatus": "tentative"},
+			{"email": "c_188@resource.calendar.google.com", "displayName": "Room Orion", "resource": true, "responseStatus": "accepted"}
+		 ]},
+		{"id": "7h2k9declined", "status": "confirmed", "htmlLink": "https://www.google.com/calendar/event?eid=N2gyazlkZWNsaW5lZA", "summary": "Quarterly all hands",
+		 "start": {"dateTime": "2024-01-15T11:00:00+01:00"}, "end": {"dateTime": "2024-01-15T12:00:00+01:00"},
+		 "attendees": [
+			{"email": "ceo@example.com", "organizer": true, "responseStatus": "accepted"},
+			{"email": "jdoe@example.com", "self": true, "responseStatus": "declined"}
+		 ]},
+		{"id": "9a8b7offsite", "status": "confirmed", "htmlLink": "https://www.google.com/calendar/event?eid=OWE4YjdvZmZzaXRl", "summary": "Team offsite",
+		 "start": {"date": "2024-01-15"}, "end": {"date": "2024-01-16"}}
+	]
+}`
+
+// primaryEventsPage2 is the second page of the events of the primary calendar
+const primaryEventsPage2 = `{
+	"items": [
+		{"id": "2c3d4design", "status": "confirmed", "htmlLink": "https://www.google.com/calendar/event?eid=MmMzZDRkZXNpZ24", "summary": "Design review: billing exports",
+		 "start": {"dateTime": "2024-01-15T14:00:00Z"}, "end": {"dateTime": "2024-01-15T15:30:00Z"},
+		 "attendees": [
+			{"email": "jdoe@example.com", "self": true, "organizer": true, "responseStatus": "accepted"},
+			{"email": "asmith@example.com", "responseStatus": "accepted"},
+			{"email": "bmartin@example.com", "responseStatus": "accepted"},
+			{"email": "cnguyen@example.com", "responseStatus": "accepted"},
+			{"email": "dlee@example.com", "responseStatus": "needsAction"},
+			{"email": "efischer@example.com", "responseStatus": "accepted"}
+		 ]},
+		{"id": "5e6f7cancelled", "status": "cancelled"},
+		{"id": "8g9h0latenight", "status": "confirmed", "htmlLink": "https://www.google.com/calendar/event?eid=OGc5aDBsYXRlbmlnaHQ", "summary": "Incident follow-up",
+		 "start": {"dateTime": "2024-01-14T23:30:00Z"}, "end": {"dateTime": "2024-01-15T00:30:00Z"}},
+		{"id": "1i2j3focus", "status": "confirmed", "htmlLink": "https://www.google.com/calendar/event?eid=MWkyajNmb2N1cw",
+		 "start": {"dateTime": "2024-01-15T16:00:00Z"}, "end": {"dateTime": "2024-01-15T17:00:00Z"}}
+	]
+}`
+
+// writePrimaryEvents answers a request of the events of the primary calendar with the page
+// of its page token
+func writePrimaryEvents(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("pageToken") == "CigKGjRxYzVhZ2I" {
+		_, _ = w.Write([]byte(primaryEventsPage2))
+		return
+	}
+	_, _ = w.Write([]byte(primaryEventsPage1))
+}
+
+// verifyJWT checks the RS256 signature of a JWT and returns its claims
+func verifyJWT(jwt string, publicKey *rsa.PublicKey) (map[string]any, error) {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 || publicKey == nil {
+		return nil, errors.New("invalid JWT")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	var claims map[string]any
+	return claims, json.Unmarshal(payload, &claims)
+}
+
+// newTestProvider returns a provider of the test server, with its Calendar API under
+// /calendar/v3 and its OAuth server under /oauth, signed in with the device flow when cached
+// is not empty
+func newTestProvider(t *testing.T, serverURL string, config provider.Config, cached token) *Provider {
+	t.Helper()
+
+	config.Enabled = true
+	config.URL = serverURL + "/calendar/v3"
+	if config.ServiceAccountKey == "" {
+		config.ClientID, config.ClientSecret = "client-id.apps.googleusercontent.com", "GOCSPX-secret"
+	}
+
+	p := NewProvider(config)
+	p.oauthURL = serverURL + "/oauth"
+	p.tokens = tokenCache{path: filepath.Join(t.TempDir(), "cache", "calendar_token.json")}
+	p.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	if cached != (token{}) {
+		if err := p.tokens.Save(cached); err != nil {
+			t.Fatalf("Failed to cache the token: %v", err)
+		}
+	}
+	return p
+}
+
+// signedIn is a device flow token valid for the tests
+var signedIn = token{Method: methodDevice, AccessToken: "ya29.cached", RefreshToken: "1//refresh", Expiry: time.Now().Add(time.Hour)}
+
+func TestProvider_IsConfigured(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   provider.Config
+		expected bool
+	}{
+		{"device flow", provider.Config{Enabled: true, ClientID: "client-id"}, true},
+		{"service account", provider.Config{Enabled: true, ServiceAccountKey: "key.json"}, true},
+		{"without credentials", provider.Config{Enabled: true}, false},
+		{"disabled", provider.Config{ClientID: "client-id"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := NewProvider(tt.config).IsConfigured(); result != tt.expected {
+				t.Errorf("Expected %t, got %t", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestProvider_GetActivities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer ya29.cached" {
+			t.Errorf("Expected the cached token, got %q", auth)
+		}
+		query := r.URL.Query()
+		if query.Get("singleEvents") != "true" || query.Get("timeMin") == "" || query.Get("timeMax") == "" {
+			t.Errorf("Expected expanded events of the time range, got %s", r.URL.RawQuery)
+		}
+
+		switch r.URL.Path {
+		case "/calendar/v3/calendars/primary/events":
+			writePrimaryEvents(w, r)
+		case "/calendar/v3/calendars/team@group.calendar.google.com/events":
+			_, _ = w.Write([]byte(`{"items": [
+				{"id": "6k7l8sync", "status": "confirmed", "htmlLink": "https://www.google.com/calendar/event?eid=Nms3bDhzeW5j", "summary": "Platform sync",
+				 "start": {"dateTime": "2024-01-15T11:00:00Z"}, "end": {"dateTime": "2024-01-15T11:30:00Z"},
+				 "attendees": [{"email": "asmith@example.com", "organizer": true, "responseStatus": "accepted"}]},
+				{"id": "2c3d4design", "status": "confirmed", "htmlLink": "https://www.google.com/calendar/event?eid=MmMzZDRkZXNpZ24", "summary": "Design review: billing exports",
+				 "start": {"dateTime": "2024-01-15T14:00:00Z"}, "end": {"dateTime": "2024-01-15T15:30:00Z"}}
+			]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := provider.Config{Calendars: []string{"primary", "team@group.calendar.google.com"}}
+	p := newTestProvider(t, server.URL, config, signedIn)
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The declined, all-day, cancelled and overlapping events are left out, and the design
+	// review shared with the team calendar is reported once
+	expected := []struct {
+		id          string
+		title       string
+		description string
+		duration    time.Duration
+		attendees   string
+	}{
+		{"calendar-4f1u0s7standup_20240115T093000Z", "Daily standup", "15m meeting with 4 attendees", 15 * time.Minute, "4"},
+		{"calendar-2c3d4design", "Design review: billing exports", "1h30m meeting with 6 attendees", 90 * time.Minute, "6"},
+		{"calendar-1i2j3focus", "(No title)", "1h meeting", time.Hour, ""},
+		{"calendar-6k7l8sync", "Platform sync", "30m meeting with 1 attendee", 30 * time.Minute, "1"},
+	}
+	if len(activities) != len(expected) {
+		t.Fatalf("Expected %d meetings, got %+v", len(expected), activities)
+	}
+	for i, want := range expected {
+		act := activities[i]
+		if act.ID != want.id || act.Title != want.title || act.Description != want.description {
+			t.Errorf("Meeting %d: expected %+v, got %+v", i, want, act)
+		}
+		if act.Type != activity.ActivityTypeMeeting || act.Platform != "calendar" || act.URL == "" {
+			t.Errorf("Meeting %d: expected a calendar meeting with a link, got %+v", i, act)
+		}
+		if act.DurationSeconds != int(want.duration.Seconds()) || act.Detail(activity.DetailAttendees) != want.attendees {
+			t.Errorf("Meeting %d: expected %v with %q attendees, got %ds with %q", i, want.duration, want.attendees, act.DurationSeconds, act.Detail(activity.DetailAttendees))
+		}
+	}
+	if !activities[0].Timestamp.Equal(time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the standup at its start time, got %v", activities[0].Timestamp)
+	}
+}
+
+func TestProvider_GetActivities_DeclinedAndAllDay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(writePrimaryEvents))
+	defer server.Close()
+
+	p := newTestProvider(t, server.URL, provider.Config{IncludeDeclined: true, IncludeAllDay: true}, signedIn)
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 5 {
+		t.Fatalf("Expected the declined and all-day events too, got %+v", activities)
+	}
+
+	declined, allDay := activities[1], activities[2]
+	if declined.ID != "calendar-7h2k9declined" || declined.DurationSeconds != 3600 {
+		t.Errorf("Expected the declined all hands, got %+v", declined)
+	}
+	if allDay.ID != "calendar-9a8b7offsite" || allDay.Description != "All-day event" || allDay.DurationSeconds != 0 {
+		t.Errorf("Expected the offsite without duration, got %+v", allDay)
+	}
+	if !allDay.Timestamp.Equal(from) {
+		t.Errorf("Expected the offsite at the start of its day, got %v", allDay.Timestamp)
+	}
+}
+
+func TestProvider_GetActivities_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": {"code": 404, "message": "Not Found"}}`))
+	}))
+	defer server.Close()
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	// The device flow needs signing in first
+	p := newTestProvider(t, server.URL, provider.Config{}, token{})
+	if _, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour)); !errors.Is(err, ErrNotSignedIn) {
+		t.Errorf("Expected ErrNotSignedIn, got %v", err)
+	}
+
+	p = newTestProvider(t, server.URL, provider.Config{Calendars: []string{"unknown"}}, signedIn)
+	_, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour))
+	if err == nil || !strings.Contains(err.Error(), "calendar unknown: Google Calendar API returned status 404: Not Found") {
+		t.Errorf("Expected the API error of the calendar, got %v", err)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{25 * time.Minute, "25m"},
+		{time.Hour, "1h"},
+		{65 * time.Minute, "1h05m"},
+		{150 * time.Minute, "2h30m"},
+	}
+	for _, tt := range tests {
+		if result := formatDuration(tt.duration); result != tt.expected {
+			t.Errorf("formatDuration(%v): expected %q, got %q", tt.duration, tt.expected, result)
+		}
+	}
+}
@@ -4,27 +4,44 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"daily/internal/activity"
+	"daily/internal/metrics"
 	"daily/internal/provider"
 )
 
 type Provider struct {
 	config provider.Config
 	client *http.Client
+
+	spaces    string // CQL clause restricting searches to the configured spaces, empty for all spaces
+	spacesErr error  // Set when a configured space key can't be used in CQL
+
+	mu     sync.Mutex
+	capped []SearchStats // Searches capped by max_results
+
+	warnings []string // Parts of the last GetActivities that failed
 }
 
 func NewProvider(config provider.Config) *Provider {
+	spaces, spacesErr := spacesClause(config.Spaces)
 	return &Provider{
 		config: config,
 		client: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout:   60 * time.Second,
+			Transport: metrics.NewTransport("confluence"),
 		},
+		spaces:    spaces,
+		spacesErr: spacesErr,
 	}
 }
 
@@ -33,10 +50,50 @@ func (p *Provider) Name() string {
 }
 
 func (p *Provider) IsConfigured() bool {
-	return p.config.Enabled &&
-		p.config.Token != "" &&
-		p.config.Email != "" &&
-		p.config.URL != ""
+	if !p.config.Enabled || p.config.Token == "" || p.config.URL == "" {
+		return false
+	}
+
+	switch p.authType() {
+	case authTypeBasic:
+		return p.config.Email != ""
+	case authTypeBearer:
+		// Personal Access Tokens identify the user on their own
+		return true
+	default:
+		return false
+	}
+}
+
+// Supported authentication schemes
+const (
+	authTypeBasic  = "basic"
+	authTypeBearer = "bearer"
+)
+
+// authType returns the configured authentication scheme, defaulting to basic auth
+func (p *Provider) authType() string {
+	if p.config.AuthType == "" {
+		return authTypeBasic
+	}
+	return strings.ToLower(p.config.AuthType)
+}
+
+// ConfigSpec describes the configuration fields of the Confluence provider
+func (p *Provider) ConfigSpec() []provider.ConfigField {
+	return []provider.ConfigField{
+		{Name: "url", Required: true, Description: "Atlassian instance URL"},
+		{Name: "email", Required: true, Description: "Atlassian account email (not needed with bearer auth)"},
+		{Name: "token", Required: true, Secret: true, Description: "Atlassian API token, or Personal Access Token with bearer auth"},
+		{Name: "token_cmd", Description: "Command printing the token, overriding token, e.g. pass show atlassian/token"},
+		{Name: "auth_type", Description: "basic (default) or bearer"},
+		{Name: "server_mode", Description: "Confluence Server / Data Center, served at the root of its site"},
+		{Name: "context_path", Description: "Path Confluence is served under, e.g. /confluence (default /wiki, none with server_mode)"},
+		{Name: "max_results", Description: "Maximum number of results fetched per search (default 200)"},
+		{Name: "spaces", Description: "Keys of the spaces searched, e.g. [\"ENG\", \"PLAT\"] (default every space)"},
+		{Name: "todo_since", Description: "Time range of the mentions and page comments listed by todo without --since (default 2w)"},
+		{Name: "include_watched", Description: "List pages you watch that others updated in todos"},
+	}
 }
 
 // GetActivities retrieves pages that the user contributed to (for summary)
@@ -46,19 +103,44 @@ func (p *Provider) GetActivities(ctx context.Context, from, to time.Time) ([]act
 	}
 
 	activities := make([]activity.Activity, 0)
+	p.warnings = nil
+
+	// Get pages and blogposts created by the user in the time range
+	created, err := p.getCreated(ctx, from, to)
+	if err != nil {
+		p.warnings = append(p.warnings, fmt.Sprintf("Failed to fetch created pages: %v", err))
+	} else {
+		activities = append(activities, created...)
+	}
 
 	// Get pages contributed to by the user in the time range
 	contributions, err := p.getContributions(ctx, from, to)
 	if err != nil {
-		// Log error but continue with empty results - warning handled by aggregator
-		fmt.Printf("Confluence: error fetching contributions: %v", err)
+		p.warnings = append(p.warnings, fmt.Sprintf("Failed to fetch contributions: %v", err))
 	} else {
-		activities = append(activities, contributions...)
+		activities = append(activities, withoutCreated(contributions, created)...)
 	}
 
 	return activities, nil
 }
 
+// Warnings describes the parts of the last GetActivities that failed, reported by the aggregator
+func (p *Provider) Warnings() []string {
+	return p.warnings
+}
+
+// withoutCreated drops the contributions to content the user created, which the contributor
+// search also returns, so that each page is listed once, as created
+func withoutCreated(contributions, created []activity.Activity) []activity.Activity {
+	createdIDs := make(map[string]bool, len(created))
+	for _, act := range created {
+		createdIDs[act.ID] = true
+	}
+	return slices.DeleteFunc(contributions, func(act activity.Activity) bool {
+		return createdIDs[act.ID]
+	})
+}
+
 // GetMentions retrieves pages that mention the user (for todos)
 func (p *Provider) GetMentions(ctx context.Context, since string) ([]TodoItem, error) {
 	if !p.IsConfigured() {
@@ -71,13 +153,15 @@ func (p *Provider) GetMentions(ctx context.Context, since string) ([]TodoItem, e
 	}
 
 	// CQL to find mentions of current user
-	cql := fmt.Sprintf("mention = currentUser() AND lastModified >= now(\"%s\")", since)
+	cql := p.inSpaces(fmt.Sprintf("mention = currentUser() AND lastModified >= now(\"%s\")", since))
 
 	searchResults, err := p.searchConfluence(ctx, cql)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search for mentions: %w", err)
 	}
 
+	provenance := provenance(cql, "")
+
 	var mentions []TodoItem
 	for _, result := range searchResults.Results {
 		priority := "normal"
@@ -85,19 +169,71 @@ func (p *Provider) GetMentions(ctx context.Context, since string) ([]TodoItem, e
 			priority = "high" // Comments usually need responses
 		}
 
+		// The text around the mention, falling back to the content type without excerpt
+		excerpt := cleanExcerpt(result.Excerpt)
+		description := fmt.Sprintf("Type: %s", strings.Title(result.Content.Type))
+		if excerpt != "" {
+			description = excerpt
+		}
+
 		mentions = append(mentions, TodoItem{
 			ID:          result.Content.ID,
 			Title:       result.Content.Title,
-			Description: fmt.Sprintf("Type: %s", strings.Title(result.Content.Type)),
-			URL:         fmt.Sprintf("%s/wiki%s", p.getBaseURL(), result.URL),
-			UpdatedAt:   time.Now(), // Confluence search doesn't provide lastModified in this format
+			Description: description,
+			Excerpt:     excerpt,
+			URL:         p.siteURL() + result.URL,
+			UpdatedAt:   parseLastModified(result.LastModified),
 			Tags:        []string{priority},
+			Details:     spaceDetails(result.ResultGlobalContainer.DisplayURL, result.URL),
+			Provenance:  provenance,
 		})
 	}
 
 	return mentions, nil
 }
 
+// provenance describes a CQL search of the provider, and where its results were kept from
+func provenance(cql, source string) *provider.Provenance {
+	return &provider.Provenance{
+		Provider:  "confluence",
+		Query:     cql,
+		Source:    source,
+		FetchedAt: time.Now(),
+	}
+}
+
+// parseLastModified parses a search result's lastModified timestamp, falling back to now
+// since older Confluence versions don't return it
+func parseLastModified(lastModified string) time.Time {
+	if t, err := time.Parse(time.RFC3339, lastModified); err == nil {
+		return t
+	}
+	return time.Now()
+}
+
+// maxExcerpt is the maximum length of the excerpt kept for a mention
+const maxExcerpt = 200
+
+// Markers around the matches of a search in the excerpts returned with excerpt=highlight
+const (
+	highlightStart = "@@@hl@@@"
+	highlightEnd   = "@@@endhl@@@"
+)
+
+// cleanExcerpt turns the excerpt of a search result into plain text: the highlight markers
+// are dropped, HTML entities decoded and whitespace collapsed, then it is truncated to
+// maxExcerpt characters
+func cleanExcerpt(excerpt string) string {
+	excerpt = strings.NewReplacer(highlightStart, "", highlightEnd, "").Replace(excerpt)
+	excerpt = strings.Join(strings.Fields(html.UnescapeString(excerpt)), " ")
+
+	runes := []rune(excerpt)
+	if len(runes) <= maxExcerpt {
+		return excerpt
+	}
+	return strings.TrimSpace(string(runes[:maxExcerpt-1])) + "…"
+}
+
 // GetCommentsOnMyPages retrieves comments on pages created by the user
 func (p *Provider) GetCommentsOnMyPages(ctx context.Context, since string) ([]TodoItem, error) {
 	if !p.IsConfigured() {
@@ -105,7 +241,7 @@ func (p *Provider) GetCommentsOnMyPages(ctx context.Context, since string) ([]To
 	}
 
 	// Step 1: Get all pages created by current user
-	myPagesCQL := "creator = currentUser() AND type = page"
+	myPagesCQL := p.inSpaces("creator = currentUser() AND type = page")
 	myPages, err := p.searchConfluence(ctx, myPagesCQL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user pages: %w", err)
@@ -127,7 +263,7 @@ func (p *Provider) GetCommentsOnMyPages(ctx context.Context, since string) ([]To
 	}
 
 	// Step 2: Get all recent comments
-	commentsCQL := fmt.Sprintf("type = comment AND lastModified > now(\"%s\")", since)
+	commentsCQL := p.inSpaces(fmt.Sprintf("type = comment AND lastModified > now(\"%s\")", since))
 	allComments, err := p.searchConfluence(ctx, commentsCQL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent comments: %w", err)
@@ -143,9 +279,11 @@ func (p *Provider) GetCommentsOnMyPages(ctx context.Context, since string) ([]To
 				ID:          comment.Content.ID,
 				Title:       comment.Content.Title,
 				Description: fmt.Sprintf("Comment on: %s", pageTitle),
-				URL:         fmt.Sprintf("%s/wiki%s", p.getBaseURL(), comment.URL),
+				URL:         p.siteURL() + comment.URL,
 				UpdatedAt:   time.Now(), // Confluence search doesn't provide lastModified in this format
 				Tags:        []string{"comment", "my_page"},
+				Details:     spaceDetails(comment.ResultGlobalContainer.DisplayURL, comment.URL),
+				Provenance:  provenance(commentsCQL, fmt.Sprintf("Page %q, found with: %s", pageTitle, myPagesCQL)),
 			})
 		}
 	}
@@ -153,12 +291,33 @@ func (p *Provider) GetCommentsOnMyPages(ctx context.Context, since string) ([]To
 	return commentsOnMyPages, nil
 }
 
-// getContributions retrieves pages that the user contributed to
+// getCreated retrieves the pages and blogposts that the user created
+func (p *Provider) getCreated(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
+	cql := p.inSpaces(fmt.Sprintf("creator = currentUser() AND type in (page, blogpost) AND created >= \"%s\" AND created < \"%s\"",
+		from.Format("2006-01-02"),
+		to.Format("2006-01-02")))
+
+	searchResults, err := p.searchConfluence(ctx, cql)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for created pages: %w", err)
+	}
+
+	var activities []activity.Activity
+	for _, result := range searchResults.Results {
+		act := p.contentActivity(result, activity.ActivityTypeConfluenceCreated, "created")
+		act.Description = "Created " + act.Detail(activity.DetailContentType)
+		activities = append(activities, act)
+	}
+
+	return activities, nil
+}
+
+// getContributions retrieves the pages, blogposts and comments that the user contributed to
 func (p *Provider) getContributions(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
 	// CQL to find pages contributed to by current user in date range
-	cql := fmt.Sprintf("contributor = currentUser() AND lastModified >= \"%s\" AND lastModified < \"%s\"",
+	cql := p.inSpaces(fmt.Sprintf("contributor = currentUser() AND type in (page, blogpost, comment) AND lastModified >= \"%s\" AND lastModified < \"%s\"",
 		from.Format("2006-01-02"),
-		to.Format("2006-01-02"))
+		to.Format("2006-01-02")))
 
 	searchResults, err := p.searchConfluence(ctx, cql)
 	if err != nil {
@@ -167,21 +326,78 @@ func (p *Provider) getContributions(ctx context.Context, from, to time.Time) ([]
 
 	var activities []activity.Activity
 	for _, result := range searchResults.Results {
-		activities = append(activities, activity.Activity{
-			ID:          result.Content.ID,
-			Type:        activity.ActivityTypeConfluenceContribution,
-			Title:       result.Content.Title,
-			Description: fmt.Sprintf("Modified %s", strings.ToLower(result.Content.Type)),
-			URL:         fmt.Sprintf("%s/wiki%s", p.getBaseURL(), result.URL),
-			Platform:    "confluence",
-			Timestamp:   time.Now(), // Will be updated when we can parse lastModified properly
-			Tags:        []string{result.Content.Type},
-		})
+		act := p.contentActivity(result, activity.ActivityTypeConfluenceContribution)
+		act.Description = contributionDescription(act.Detail(activity.DetailContentType), result.ResultParentContainer.Type)
+		activities = append(activities, act)
 	}
 
 	return activities, nil
 }
 
+// contentActivity returns the activity of a search result, tagged with its content type, the
+// extra tags and its space
+func (p *Provider) contentActivity(result searchResult, actType activity.ActivityType, tags ...string) activity.Activity {
+	contentType := strings.ToLower(result.Content.Type)
+	space := spaceDetails(result.ResultGlobalContainer.DisplayURL, result.URL)[activity.DetailSpace]
+	tags = append([]string{contentType}, tags...)
+	if space != "" {
+		tags = append(tags, "space:"+space) // Lets output be grouped per space
+	}
+
+	return activity.Activity{
+		ID:        result.Content.ID,
+		Type:      actType,
+		Title:     result.Content.Title,
+		URL:       p.siteURL() + result.URL,
+		Platform:  "confluence",
+		Timestamp: time.Now(), // Will be updated when we can parse lastModified properly
+		Tags:      tags,
+		Details:   activity.NewDetails(activity.DetailSpace, space, activity.DetailContentType, contentType),
+	}
+}
+
+// contributionDescription describes a contribution of the user to content of a type, e.g.
+// "Edited blogpost", or "Commented on page" for a comment on content of containerType
+func contributionDescription(contentType, containerType string) string {
+	switch contentType {
+	case "comment":
+		if containerType == "" {
+			return "Commented"
+		}
+		return "Commented on " + strings.ToLower(containerType)
+	case "":
+		return "Edited content"
+	default:
+		return "Edited " + contentType
+	}
+}
+
+// spacesClause returns the CQL clause restricting a search to the spaces with the given keys,
+// e.g. space in ("ENG","PLAT"), or an empty clause without keys. Keys failing
+// provider.ValidateSpaceKeys are rejected.
+func spacesClause(keys []string) (string, error) {
+	if len(keys) == 0 {
+		return "", nil
+	}
+	if err := provider.ValidateSpaceKeys(keys); err != nil {
+		return "", err
+	}
+
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = `"` + strings.TrimSpace(key) + `"`
+	}
+	return fmt.Sprintf("space in (%s)", strings.Join(quoted, ",")), nil
+}
+
+// inSpaces restricts a CQL query to the configured spaces
+func (p *Provider) inSpaces(cql string) string {
+	if p.spaces == "" {
+		return cql
+	}
+	return cql + " AND " + p.spaces
+}
+
 // getBaseURL returns the properly formatted base URL with https prefix
 func (p *Provider) getBaseURL() string {
 	baseURL := strings.TrimSuffix(p.config.URL, "/")
@@ -191,28 +407,168 @@ func (p *Provider) getBaseURL() string {
 	return baseURL
 }
 
-// searchConfluence performs a CQL search against Confluence
-func (p *Provider) searchConfluence(ctx context.Context, cql string) (*ConfluenceSearchResult, error) {
-	// Properly encode URL parameters
-	apiURL := fmt.Sprintf("%s/wiki/rest/api/search", p.getBaseURL())
+// siteURL returns the URL Confluence is served under, which the REST API and the relative
+// URLs of its responses start from, e.g. https://company.atlassian.net/wiki on Confluence
+// Cloud or https://wiki.corp.example.com on Data Center
+func (p *Provider) siteURL() string {
+	contextPath := "/wiki"
+	if p.config.ServerMode {
+		contextPath = ""
+	}
+	if p.config.ContextPath != "" {
+		// "/" serves Confluence at the root of its site, like server_mode
+		contextPath = strings.TrimSuffix("/"+strings.Trim(p.config.ContextPath, "/"), "/")
+	}
+	return p.getBaseURL() + contextPath
+}
+
+// spaceDetails returns the structured details of a search result: the key of its space, read
+// from the first of its URLs under /spaces/KEY or /display/KEY
+func spaceDetails(urls ...string) map[string]string {
+	for _, u := range urls {
+		for _, prefix := range []string{"/spaces/", "/display/"} {
+			_, rest, ok := strings.Cut(u, prefix)
+			if !ok {
+				continue
+			}
+			if space, _, _ := strings.Cut(rest, "/"); space != "" {
+				return activity.NewDetails(activity.DetailSpace, space)
+			}
+		}
+	}
+	return nil
+}
+
+const (
+	// pageSize is the number of results requested per search page
+	pageSize = 50
+
+	// defaultMaxResults caps the total number of results fetched per search
+	defaultMaxResults = 200
+)
+
+// SearchStats describes a CQL search whose results were capped by max_results
+type SearchStats struct {
+	CQL      string
+	Returned int // Results fetched
+	Total    int // Matches reported by Confluence
+}
+
+// maxResults returns the configured cap on fetched results
+func (p *Provider) maxResults() int {
+	if p.config.MaxResults > 0 {
+		return p.config.MaxResults
+	}
+	return defaultMaxResults
+}
+
+// CappedSearches returns the searches made so far that left matches out because of max_results
+func (p *Provider) CappedSearches() []SearchStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.capped)
+}
+
+// searchConfluence performs a CQL search against Confluence, following the next links of the
+// result pages until every match or max_results results were fetched. The expand properties
+// are returned with each result, e.g. content.version.
+func (p *Provider) searchConfluence(ctx context.Context, cql string, expand ...string) (*ConfluenceSearchResult, error) {
+	if p.spacesErr != nil {
+		return nil, fmt.Errorf("invalid spaces: %w", p.spacesErr)
+	}
+
+	limit := p.maxResults()
 	params := url.Values{}
 	params.Add("cql", cql)
-	params.Add("limit", "50")
-	fullURL := apiURL + "?" + params.Encode()
+	params.Add("limit", strconv.Itoa(min(pageSize, limit)))
+	params.Add("excerpt", "highlight")
+	if len(expand) > 0 {
+		params.Add("expand", strings.Join(expand, ","))
+	}
+	pageURL := fmt.Sprintf("%s/rest/api/search?%s", p.siteURL(), params.Encode())
 
-	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	var result ConfluenceSearchResult
+	for {
+		var page ConfluenceSearchResult
+		if err := p.getJSON(ctx, pageURL, &page); err != nil {
+			return nil, err
+		}
+
+		result.Results = append(result.Results, page.Results...)
+		result.TotalSize = max(result.TotalSize, page.TotalSize)
+		if len(result.Results) >= limit || page.Links.Next == "" || len(page.Results) == 0 {
+			break
+		}
+		// Next links are relative to the context path, and are kept on the configured site
+		pageURL = p.siteURL() + page.Links.Next
+	}
+
+	if len(result.Results) > limit {
+		result.Results = result.Results[:limit]
+	}
+	if result.TotalSize > len(result.Results) && len(result.Results) == limit {
+		p.mu.Lock()
+		p.capped = append(p.capped, SearchStats{CQL: cql, Returned: len(result.Results), Total: result.TotalSize})
+		p.mu.Unlock()
+	}
+
+	return &result, nil
+}
+
+// Retries of the requests rate limited by Confluence (429 Too Many Requests)
+const (
+	maxRetries        = 3
+	defaultRetryDelay = 2 * time.Second  // Without Retry-After header
+	maxRetryDelay     = 30 * time.Second // Longer Retry-After delays fail the request instead
+)
+
+// getJSON fetches a Confluence API URL and decodes its JSON response into result. Rate limited
+// requests are retried after the delay of their Retry-After header, up to maxRetries times.
+func (p *Provider) getJSON(ctx context.Context, apiURL string, result any) error {
+	for attempt := 0; ; attempt++ {
+		body, retryAfter, err := p.get(ctx, apiURL)
+		if err == nil {
+			if err := json.Unmarshal(body, result); err != nil {
+				return fmt.Errorf("failed to parse Confluence response: %w", err)
+			}
+			return nil
+		}
+		if retryAfter < 0 || attempt == maxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryAfter):
+		}
+	}
+}
+
+// get fetches a Confluence API URL and returns its body. When the request is rate limited, it
+// also returns the delay to wait before retrying, which is negative for other errors.
+func (p *Provider) get(ctx context.Context, apiURL string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Confluence request: %w", err)
+		return nil, -1, fmt.Errorf("failed to create Confluence request: %w", err)
 	}
 
-	// Basic auth with email + API token
-	req.SetBasicAuth(p.config.Email, p.config.Token)
+	switch p.authType() {
+	case authTypeBasic:
+		// Confluence Cloud uses basic auth with email and API token
+		req.SetBasicAuth(p.config.Email, p.config.Token)
+	case authTypeBearer:
+		// Confluence Data Center Personal Access Tokens are sent as bearer tokens
+		req.Header.Set("Authorization", "Bearer "+p.config.Token)
+	default:
+		return nil, -1, fmt.Errorf("unsupported Confluence auth type: %s", p.config.AuthType)
+	}
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute Confluence request: %w", err)
+		return nil, -1, fmt.Errorf("failed to execute Confluence request: %w", err)
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -221,46 +577,87 @@ func (p *Provider) searchConfluence(ctx context.Context, cql string) (*Confluenc
 		}
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Confluence API returned status %d: %s", resp.StatusCode, resp.Status)
-	}
-
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read Confluence response: %w", err)
+		return nil, -1, fmt.Errorf("failed to read Confluence response: %w", err)
 	}
 
-	var result ConfluenceSearchResult
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse Confluence response: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("Confluence API returned status %d: %s", resp.StatusCode, resp.Status)
+		if message := errorMessage(body); message != "" {
+			err = fmt.Errorf("Confluence API returned status %d: %s", resp.StatusCode, message)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if delay, ok := retryDelay(resp.Header.Get("Retry-After"), time.Now()); ok {
+				return nil, delay, err
+			}
+		}
+		return nil, -1, err
 	}
 
-	return &result, nil
+	return body, -1, nil
+}
+
+// errorMessage returns the message of a Confluence error response, e.g.
+// {"statusCode": 401, "message": "Current user not permitted to use Confluence"}
+func errorMessage(body []byte) string {
+	var response struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(response.Message)
+}
+
+// retryDelay returns the delay of a Retry-After header, in seconds or as an HTTP date, and
+// false when it is longer than maxRetryDelay
+func retryDelay(header string, now time.Time) (time.Duration, bool) {
+	delay := defaultRetryDelay
+	if seconds, err := strconv.Atoi(header); err == nil {
+		delay = time.Duration(max(seconds, 0)) * time.Second
+	} else if date, err := http.ParseTime(header); err == nil {
+		delay = max(date.Sub(now), 0)
+	}
+	return delay, delay <= maxRetryDelay
 }
 
 // ConfluenceSearchResult represents Confluence search API response
 type ConfluenceSearchResult struct {
-	Results []struct {
-		Content struct {
-			ID    string `json:"id"`
-			Title string `json:"title"`
-			Type  string `json:"type"`
-		} `json:"content"`
-		ResultParentContainer struct {
-			ID    string `json:"id"`
-			Title string `json:"title"`
-			Type  string `json:"type"`
-		} `json:"resultParentContainer"`
-		URL string `json:"url"`
-	} `json:"results"`
-}
-
-// TodoItem represents a single todo item (avoiding import cycles)
-type TodoItem struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	URL         string    `json:"url,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Tags        []string  `json:"tags,omitempty"`
+	Results   []searchResult `json:"results"`
+	TotalSize int            `json:"totalSize"` // Matches of the search, across every page
+	Links     struct {
+		Next string `json:"next"` // Next page, relative to the context path, empty on the last page
+	} `json:"_links"`
 }
+
+// searchResult is a page, blogpost or comment found by a search
+type searchResult struct {
+	Content struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+		Type  string `json:"type"`
+		// Latest version, only returned with expand=content.version
+		Version struct {
+			By user `json:"by"`
+		} `json:"version"`
+	} `json:"content"`
+	ResultParentContainer struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+		Type  string `json:"type"`
+	} `json:"resultParentContainer"`
+	ResultGlobalContainer struct {
+		Title      string `json:"title"`
+		DisplayURL string `json:"displayUrl"` // e.g. "/spaces/ENG"
+	} `json:"resultGlobalContainer"`
+	URL          string `json:"url"`
+	LastModified string `json:"lastModified"`
+	Excerpt      string `json:"excerpt"` // Text around the match, see cleanExcerpt
+}
+
+// TodoItem represents a single todo item
+type TodoItem = provider.TodoItem
+
+// Ensure the provider can be used as a mention source
+var _ provider.MentionSource = (*Provider)(nil)
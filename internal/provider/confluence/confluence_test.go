@@ -2,9 +2,14 @@ package confluence
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"daily/internal/activity"
 	"daily/internal/provider"
 )
 
@@ -99,6 +104,27 @@ func TestProvider_IsConfigured(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name: "bearer auth without email",
+			config: provider.Config{
+				Token:    "testtoken",
+				URL:      "https://wiki.corp.example.com",
+				Enabled:  true,
+				AuthType: "bearer",
+			},
+			expected: true,
+		},
+		{
+			name: "unsupported auth type",
+			config: provider.Config{
+				Email:    "test@example.com",
+				Token:    "testtoken",
+				URL:      "https://wiki.corp.example.com",
+				Enabled:  true,
+				AuthType: "oauth",
+			},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -165,6 +191,11 @@ func TestProvider_GetActivities(t *testing.T) {
 			if activities == nil {
 				t.Error("Expected activities slice to be initialized, got nil")
 			}
+
+			// The failed searches are left to the aggregator to report
+			if warnings := p.Warnings(); len(warnings) != 2 || !strings.HasPrefix(warnings[0], "Failed to fetch created pages: ") {
+				t.Errorf("Expected a warning per failed search, got %v", warnings)
+			}
 		})
 	}
 }
@@ -497,6 +528,58 @@ func TestProvider_GetBaseURL(t *testing.T) {
 	}
 }
 
+func TestProvider_SiteURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   provider.Config
+		expected string
+	}{
+		{"cloud", provider.Config{URL: "example.atlassian.net"}, "https://example.atlassian.net/wiki"},
+		{"data center", provider.Config{URL: "https://wiki.corp.example.com/", ServerMode: true}, "https://wiki.corp.example.com"},
+		{"context path", provider.Config{URL: "https://corp.example.com", ServerMode: true, ContextPath: "/confluence/"}, "https://corp.example.com/confluence"},
+		{"context path without slash", provider.Config{URL: "https://corp.example.com", ContextPath: "confluence"}, "https://corp.example.com/confluence"},
+		{"root context path", provider.Config{URL: "https://wiki.corp.example.com", ContextPath: "/"}, "https://wiki.corp.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := NewProvider(tt.config).siteURL(); result != tt.expected {
+				t.Errorf("Expected URL '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestProvider_DataCenter(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		if auth := r.Header.Get("Authorization"); auth != "Bearer pat-token" {
+			t.Errorf("Expected the Personal Access Token as bearer token, got %q", auth)
+		}
+		if r.URL.Query().Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{"results": [{"content": {"id": "1", "title": "Runbook", "type": "page"}, "url": "/display/ENG/Runbook"}],
+				"totalSize": 2, "_links": {"next": "/rest/api/search?cql=mention&cursor=abc&limit=50"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [{"content": {"id": "2", "title": "Roadmap", "type": "page"}, "url": "/display/ENG/Roadmap"}], "totalSize": 2, "_links": {}}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Token: "pat-token", URL: server.URL, Enabled: true, AuthType: "bearer", ServerMode: true})
+
+	mentions, err := p.GetMentions(context.Background(), "1w")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(requests) != 2 || !strings.HasPrefix(requests[0], "/rest/api/search?") || !strings.HasPrefix(requests[1], "/rest/api/search?") {
+		t.Fatalf("Expected both pages to be requested without /wiki, got %v", requests)
+	}
+	if len(mentions) != 2 || mentions[0].URL != server.URL+"/display/ENG/Runbook" {
+		t.Errorf("Expected the page URLs without /wiki, got %+v", mentions)
+	}
+}
+
 func TestProvider_GetCommentsOnMyPages(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -549,6 +632,201 @@ func TestProvider_GetCommentsOnMyPages(t *testing.T) {
 	}
 }
 
+func TestProvider_GetCommentsOnMyPages_Provenance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Query().Get("cql"), "creator") {
+			_, _ = w.Write([]byte(`{"results": [{"content": {"id": "1", "title": "Runbook", "type": "page"}}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [{"content": {"id": "2", "title": "Re: Runbook", "type": "comment"},
+			"resultParentContainer": {"id": "1"}, "url": "/spaces/OPS/pages/1"}]}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+
+	comments, err := p.GetCommentsOnMyPages(context.Background(), "2d")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("Expected 1 comment, got %d", len(comments))
+	}
+
+	provenance := comments[0].Provenance
+	if provenance == nil {
+		t.Fatal("Expected provenance, got nil")
+	}
+	if provenance.Provider != "confluence" || provenance.Query != `type = comment AND lastModified > now("-2d")` {
+		t.Errorf("Expected the comments CQL, got %+v", provenance)
+	}
+	if !strings.Contains(provenance.Source, `"Runbook"`) {
+		t.Errorf("Expected the page the comment is on, got %q", provenance.Source)
+	}
+	if space := comments[0].Details[activity.DetailSpace]; space != "OPS" {
+		t.Errorf("Expected the space of the comment, got '%s'", space)
+	}
+}
+
+func TestProvider_SearchConfluence_Pagination(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		if r.URL.Query().Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{"results": [{"content": {"id": "1", "title": "Runbook"}}, {"content": {"id": "2", "title": "Roadmap"}}],
+				"totalSize": 3, "_links": {"next": "/rest/api/search?cql=type+%3D+page&cursor=abc&limit=50"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [{"content": {"id": "3", "title": "Postmortem"}}], "totalSize": 3, "_links": {}}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+
+	result, err := p.searchConfluence(context.Background(), "type = page")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(requests) != 2 || !strings.HasPrefix(requests[1], "/wiki/rest/api/search?") {
+		t.Fatalf("Expected the next page to be requested under /wiki, got %v", requests)
+	}
+	var titles []string
+	for _, r := range result.Results {
+		titles = append(titles, r.Content.Title)
+	}
+	if strings.Join(titles, ",") != "Runbook,Roadmap,Postmortem" {
+		t.Errorf("Expected the results of both pages, got %v", titles)
+	}
+	if capped := p.CappedSearches(); len(capped) != 0 {
+		t.Errorf("Expected no capped search, got %+v", capped)
+	}
+}
+
+func TestProvider_SearchConfluence_MaxResults(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if limit := r.URL.Query().Get("limit"); limit != "2" {
+			t.Errorf("Expected pages of max_results, got limit %s", limit)
+		}
+		_, _ = w.Write([]byte(`{"results": [{"content": {"id": "1"}}, {"content": {"id": "2"}}],
+			"totalSize": 340, "_links": {"next": "/rest/api/search?cursor=abc&limit=2"}}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true, MaxResults: 2})
+
+	result, err := p.searchConfluence(context.Background(), "type = comment")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if requests != 1 || len(result.Results) != 2 {
+		t.Errorf("Expected the search to stop at max_results, got %d results in %d requests", len(result.Results), requests)
+	}
+	capped := p.CappedSearches()
+	if len(capped) != 1 || capped[0] != (SearchStats{CQL: "type = comment", Returned: 2, Total: 340}) {
+		t.Errorf("Expected the capped search to be reported, got %+v", capped)
+	}
+}
+
+func TestSpacesClause(t *testing.T) {
+	tests := []struct {
+		name        string
+		keys        []string
+		expected    string
+		expectError bool
+	}{
+		{name: "no space", keys: nil, expected: ""},
+		{name: "one space", keys: []string{"ENG"}, expected: `space in ("ENG")`},
+		{name: "several spaces", keys: []string{"ENG", " PLAT ", "~jdoe"}, expected: `space in ("ENG","PLAT","~jdoe")`},
+		{name: "double quote", keys: []string{`ENG") OR space = ("HR`}, expectError: true},
+		{name: "single quote", keys: []string{"ENG'"}, expectError: true},
+		{name: "backslash", keys: []string{`ENG\`}, expectError: true},
+		{name: "empty key", keys: []string{"ENG", " "}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clause, err := spacesClause(tt.keys)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected an error, got clause '%s'", clause)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if clause != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, clause)
+			}
+		})
+	}
+}
+
+func TestProvider_Spaces(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("cql"))
+		if strings.HasPrefix(r.URL.Query().Get("cql"), "creator") {
+			_, _ = w.Write([]byte(`{"results": [{"content": {"id": "1", "title": "Runbook", "type": "page"}}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": []}`))
+	}))
+	defer server.Close()
+
+	config := provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true, Spaces: []string{"ENG", "PLAT"}}
+	p := NewProvider(config)
+	ctx := context.Background()
+	if _, err := p.GetMentions(ctx, "2w"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := p.GetCommentsOnMyPages(ctx, "2w"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := p.getContributions(ctx, time.Now().AddDate(0, 0, -1), time.Now()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(queries) != 4 {
+		t.Fatalf("Expected 4 searches, got %v", queries)
+	}
+	for _, cql := range queries {
+		if !strings.HasSuffix(cql, ` AND space in ("ENG","PLAT")`) {
+			t.Errorf("Expected the search to be restricted to the spaces, got: %s", cql)
+		}
+	}
+
+	// Keys that can't be quoted fail the searches instead of widening them
+	config.Spaces = []string{`ENG" OR space != "X`}
+	if _, err := NewProvider(config).GetMentions(ctx, "2w"); err == nil || !strings.Contains(err.Error(), "invalid spaces") {
+		t.Errorf("Expected invalid spaces error, got: %v", err)
+	}
+}
+
+func TestSpaceDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		urls     []string
+		expected string
+	}{
+		{name: "global container", urls: []string{"/spaces/ENG", "/pages/viewpage.action?pageId=1"}, expected: "ENG"},
+		{name: "page URL", urls: []string{"", "/spaces/OPS/pages/1/Runbook"}, expected: "OPS"},
+		{name: "legacy display URL", urls: []string{"/display/DOCS/Home"}, expected: "DOCS"},
+		{name: "no space", urls: []string{"", "/pages/viewpage.action?pageId=1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := spaceDetails(tt.urls...)
+			if details[activity.DetailSpace] != tt.expected {
+				t.Errorf("Expected space '%s', got %v", tt.expected, details)
+			}
+		})
+	}
+}
+
 func TestProvider_GetMentions_SinceFormat(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -606,3 +884,286 @@ func TestProvider_GetMentions_SinceFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestProvider_GetContributions_ContentTypes(t *testing.T) {
+	var cql string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cql = r.URL.Query().Get("cql")
+		_, _ = w.Write([]byte(`{"results": [
+			{"content": {"id": "1", "title": "Runbook", "type": "page"},
+				"resultGlobalContainer": {"title": "Operations", "displayUrl": "/spaces/OPS"},
+				"url": "/spaces/OPS/pages/1/Runbook"},
+			{"content": {"id": "2", "title": "Weekly update #12", "type": "blogpost"},
+				"resultGlobalContainer": {"title": "Engineering", "displayUrl": "/spaces/ENG"},
+				"url": "/spaces/ENG/blog/2024/01/15/2/Weekly+update+12"},
+			{"content": {"id": "3", "title": "Re: Weekly update #11", "type": "comment"},
+				"resultParentContainer": {"id": "4", "title": "Weekly update #11", "type": "blogpost"},
+				"resultGlobalContainer": {"title": "Engineering", "displayUrl": "/spaces/ENG"},
+				"url": "/spaces/ENG/blog/2024/01/08/4?focusedCommentId=3"}
+		]}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+	activities, err := p.getContributions(context.Background(), time.Now().AddDate(0, 0, -1), time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(cql, "type in (page, blogpost, comment)") {
+		t.Errorf("Expected the search to include pages, blogposts and comments, got: %s", cql)
+	}
+
+	expected := []struct {
+		description string
+		contentType string
+		tags        []string
+	}{
+		{"Edited page", "page", []string{"page", "space:OPS"}},
+		{"Edited blogpost", "blogpost", []string{"blogpost", "space:ENG"}},
+		{"Commented on blogpost", "comment", []string{"comment", "space:ENG"}},
+	}
+	if len(activities) != len(expected) {
+		t.Fatalf("Expected %d activities, got %d", len(expected), len(activities))
+	}
+	for i, want := range expected {
+		act := activities[i]
+		if act.Description != want.description {
+			t.Errorf("Expected description %q, got %q", want.description, act.Description)
+		}
+		if act.Detail(activity.DetailContentType) != want.contentType {
+			t.Errorf("Expected content type %q, got %v", want.contentType, act.Details)
+		}
+		if !slices.Equal(act.Tags, want.tags) {
+			t.Errorf("Expected tags %v, got %v", want.tags, act.Tags)
+		}
+	}
+}
+
+func TestProvider_GetActivities_CreatedAndEdited(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cql := r.URL.Query().Get("cql")
+		queries = append(queries, cql)
+		if strings.HasPrefix(cql, "creator") {
+			_, _ = w.Write([]byte(`{"results": [
+				{"content": {"id": "1", "title": "Runbook", "type": "page"},
+					"resultGlobalContainer": {"displayUrl": "/spaces/OPS"}, "url": "/spaces/OPS/pages/1/Runbook"},
+				{"content": {"id": "5", "title": "Release notes", "type": "blogpost"},
+					"resultGlobalContainer": {"displayUrl": "/spaces/ENG"}, "url": "/spaces/ENG/blog/2024/01/15/5/Release+notes"}
+			]}`))
+			return
+		}
+		// The pages the user created are also pages they contributed to
+		_, _ = w.Write([]byte(`{"results": [
+			{"content": {"id": "1", "title": "Runbook", "type": "page"},
+				"resultGlobalContainer": {"displayUrl": "/spaces/OPS"}, "url": "/spaces/OPS/pages/1/Runbook"},
+			{"content": {"id": "2", "title": "Architecture", "type": "page"}, "url": "/spaces/ENG/pages/2/Architecture"},
+			{"content": {"id": "5", "title": "Release notes", "type": "blogpost"}, "url": "/spaces/ENG/blog/2024/01/15/5/Release+notes"},
+			{"content": {"id": "3", "title": "Re: Runbook", "type": "comment"},
+				"resultParentContainer": {"id": "1", "title": "Runbook", "type": "page"}, "url": "/spaces/OPS/pages/1?focusedCommentId=3"}
+		]}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(context.Background(), from, from.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(queries) != 2 || queries[0] != `creator = currentUser() AND type in (page, blogpost) AND created >= "2024-01-15" AND created < "2024-01-22"` {
+		t.Errorf("Expected the created pages then the contributions to be searched, got %v", queries)
+	}
+
+	expected := []struct {
+		id          string
+		actType     activity.ActivityType
+		description string
+		tags        []string
+	}{
+		{"1", activity.ActivityTypeConfluenceCreated, "Created page", []string{"page", "created", "space:OPS"}},
+		{"5", activity.ActivityTypeConfluenceCreated, "Created blogpost", []string{"blogpost", "created", "space:ENG"}},
+		{"2", activity.ActivityTypeConfluenceContribution, "Edited page", []string{"page", "space:ENG"}},
+		{"3", activity.ActivityTypeConfluenceContribution, "Commented on page", []string{"comment", "space:OPS"}},
+	}
+	if len(activities) != len(expected) {
+		t.Fatalf("Expected %d activities, each page once, got %+v", len(expected), activities)
+	}
+	counts := make(map[activity.ActivityType]int)
+	for i, want := range expected {
+		act := activities[i]
+		counts[act.Type]++
+		if act.ID != want.id || act.Type != want.actType || act.Description != want.description {
+			t.Errorf("Expected %s %s %q, got %s %s %q", want.id, want.actType, want.description, act.ID, act.Type, act.Description)
+		}
+		if !slices.Equal(act.Tags, want.tags) {
+			t.Errorf("Expected tags %v, got %v", want.tags, act.Tags)
+		}
+	}
+	if counts[activity.ActivityTypeConfluenceCreated] != 2 || counts[activity.ActivityTypeConfluenceContribution] != 2 {
+		t.Errorf("Expected 2 created and 2 edited, got %v", counts)
+	}
+}
+
+func TestContributionDescription(t *testing.T) {
+	tests := []struct {
+		contentType   string
+		containerType string
+		expected      string
+	}{
+		{"page", "", "Edited page"},
+		{"blogpost", "", "Edited blogpost"},
+		{"comment", "page", "Commented on page"},
+		{"comment", "", "Commented"},
+		{"", "", "Edited content"},
+	}
+
+	for _, tt := range tests {
+		if got := contributionDescription(tt.contentType, tt.containerType); got != tt.expected {
+			t.Errorf("Expected %q for %s on %s, got %q", tt.expected, tt.contentType, tt.containerType, got)
+		}
+	}
+}
+
+func TestCleanExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		excerpt  string
+		expected string
+	}{
+		{"highlight markers", "Thanks @@@hl@@@Jane Doe@@@endhl@@@ for the review", "Thanks Jane Doe for the review"},
+		{"HTML entities", "Ping &quot;ops&quot; &amp; @@@hl@@@Jane@@@endhl@@@ &#39;asap&#39; &lt;3", `Ping "ops" & Jane 'asap' <3`},
+		{"whitespace", "  First line\n\n  second\tline  ", "First line second line"},
+		{"empty", "", ""},
+		{"truncated", strings.Repeat("word ", 60), strings.TrimSpace(strings.Repeat("word ", 40)[:199]) + "…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := cleanExcerpt(tt.excerpt); result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestProvider_GetMentions_Excerpt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("excerpt") != "highlight" {
+			t.Errorf("Expected highlighted excerpts to be requested, got %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"results": [
+			{"content": {"id": "1", "title": "Design doc", "type": "page"}, "url": "/spaces/ENG/pages/1",
+				"excerpt": "Can @@@hl@@@Jane@@@endhl@@@ review the &quot;cache&quot; section?"},
+			{"content": {"id": "2", "title": "Runbook", "type": "page"}, "url": "/spaces/ENG/pages/2"}
+		]}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+	mentions, err := p.GetMentions(context.Background(), "2w")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(mentions) != 2 {
+		t.Fatalf("Expected 2 mentions, got %d", len(mentions))
+	}
+
+	expected := `Can Jane review the "cache" section?`
+	if mentions[0].Excerpt != expected || mentions[0].Description != expected {
+		t.Errorf("Expected the excerpt as description, got %q and %q", mentions[0].Excerpt, mentions[0].Description)
+	}
+	if mentions[1].Excerpt != "" || mentions[1].Description != "Type: Page" {
+		t.Errorf("Expected the content type without excerpt, got %q and %q", mentions[1].Excerpt, mentions[1].Description)
+	}
+}
+
+func TestProvider_SearchRetriesRateLimits(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [{"content": {"id": "1", "title": "Design doc", "type": "page"}, "url": "/spaces/ENG/pages/1"}]}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+	mentions, err := p.GetMentions(context.Background(), "2w")
+	if err != nil {
+		t.Fatalf("Expected the rate limited search to be retried, got: %v", err)
+	}
+	if requests != 2 || len(mentions) != 1 {
+		t.Errorf("Expected 1 mention after 2 requests, got %d after %d", len(mentions), requests)
+	}
+}
+
+func TestProvider_SearchGivesUpOnRateLimits(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message": "Rate limit exceeded"}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+	_, err := p.GetMentions(context.Background(), "2w")
+	if err == nil || !strings.Contains(err.Error(), "status 429: Rate limit exceeded") {
+		t.Errorf("Expected the rate limit error, got: %v", err)
+	}
+	if requests != maxRetries+1 {
+		t.Errorf("Expected %d requests, got %d", maxRetries+1, requests)
+	}
+}
+
+func TestProvider_ErrorBodyMessage(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"statusCode": 401, "message": "Current user not permitted to use Confluence"}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+	_, err := p.GetMentions(context.Background(), "2w")
+	if err == nil || !strings.Contains(err.Error(), "status 401: Current user not permitted to use Confluence") {
+		t.Errorf("Expected the message of the error body, got: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected no retry on 401, got %d requests", requests)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected time.Duration
+		ok       bool
+	}{
+		{"seconds", "5", 5 * time.Second, true},
+		{"HTTP date", now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second, true},
+		{"past HTTP date", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"missing", "", defaultRetryDelay, true},
+		{"too long", "120", 2 * time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := retryDelay(tt.header, now)
+			if delay != tt.expected || ok != tt.ok {
+				t.Errorf("Expected %v and %v, got %v and %v", tt.expected, tt.ok, delay, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package confluence
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// htmlTag matches the tags of the storage format of a task, e.g. <span> or <ac:link>
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// inlineTask is an inline task (<ac:task>) of a page, as returned by the inline tasks search
+type inlineTask struct {
+	ID          int64  `json:"id"`
+	ContentID   int64  `json:"contentId"` // Page holding the task
+	Status      string `json:"status"`    // "incomplete" or "complete", in any case
+	Title       string `json:"title"`     // Title of the page
+	Description string `json:"description"`
+	Body        string `json:"body"`
+	CreateDate  *int64 `json:"createDate"` // Milliseconds since epoch
+	UpdateDate  *int64 `json:"updateDate"`
+	DueDate     *int64 `json:"dueDate"`
+}
+
+// inlineTasksPage is a page of the inline tasks search
+type inlineTasksPage struct {
+	Results []inlineTask `json:"results"`
+	Links   struct {
+		Next string `json:"next"` // Next page, relative to the context path, empty on the last page
+	} `json:"_links"`
+}
+
+// user identifies a Confluence user
+type user struct {
+	AccountID   string `json:"accountId"`
+	UserKey     string `json:"userKey"` // Identifies users on Confluence Data Center, without account IDs
+	DisplayName string `json:"displayName"`
+}
+
+// id returns the account ID of the user, or their user key on Confluence Data Center
+func (u user) id() string {
+	return cmp.Or(u.AccountID, u.UserKey)
+}
+
+// currentUserID returns the ID of the current user, see user.id
+func (p *Provider) currentUserID(ctx context.Context) (string, error) {
+	var current user
+	if err := p.getJSON(ctx, p.siteURL()+"/rest/api/user/current", &current); err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+	if current.id() == "" {
+		return "", fmt.Errorf("failed to get current user: no account ID returned")
+	}
+	return current.id(), nil
+}
+
+// GetAssignedInlineTasks retrieves the incomplete inline tasks of pages assigned to the user
+func (p *Provider) GetAssignedInlineTasks(ctx context.Context) ([]TodoItem, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("Confluence provider not configured")
+	}
+
+	assignee, err := p.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := p.maxResults()
+	params := url.Values{}
+	params.Set("assignee", assignee)
+	params.Set("status", "incomplete")
+	params.Set("limit", strconv.Itoa(min(pageSize, limit)))
+	query := "/rest/api/inlinetasks/search?" + params.Encode()
+	pageURL := p.siteURL() + query
+
+	var tasks []TodoItem
+	for len(tasks) < limit {
+		var page inlineTasksPage
+		if err := p.getJSON(ctx, pageURL, &page); err != nil {
+			return nil, fmt.Errorf("failed to search inline tasks: %w", err)
+		}
+
+		for _, task := range page.Results {
+			// The status filter isn't applied by every Confluence version
+			if strings.EqualFold(task.Status, "complete") {
+				continue
+			}
+			tasks = append(tasks, p.inlineTaskItem(task, query))
+		}
+		if page.Links.Next == "" || len(page.Results) == 0 {
+			break
+		}
+		pageURL = p.siteURL() + page.Links.Next
+	}
+
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return tasks, nil
+}
+
+// inlineTaskItem converts an inline task to a todo item linking to the task on its page
+func (p *Provider) inlineTaskItem(task inlineTask, query string) TodoItem {
+	text := taskText(cmp.Or(task.Description, task.Body))
+	if text == "" {
+		text = "Untitled task"
+	}
+
+	tags := []string{"task"}
+	if task.DueDate != nil {
+		tags = append(tags, "due:"+time.UnixMilli(*task.DueDate).Format("2006-01-02"))
+	}
+
+	updatedAt := time.Now()
+	if task.UpdateDate != nil {
+		updatedAt = time.UnixMilli(*task.UpdateDate)
+	} else if task.CreateDate != nil {
+		updatedAt = time.UnixMilli(*task.CreateDate)
+	}
+
+	return TodoItem{
+		ID:          fmt.Sprintf("confluence-task-%d", task.ID),
+		Title:       text,
+		Description: fmt.Sprintf("Task on: %s", task.Title),
+		URL:         fmt.Sprintf("%s/pages/viewpage.action?pageId=%d&focusedTaskId=%d", p.siteURL(), task.ContentID, task.ID),
+		UpdatedAt:   updatedAt,
+		Tags:        tags,
+		Provenance:  provenance(query, ""),
+	}
+}
+
+// taskText returns the plain text of a task written in the storage format
+func taskText(storage string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(storage, " "))), " ")
+}
@@ -0,0 +1,98 @@
+package confluence
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestProvider_GetAssignedInlineTasks(t *testing.T) {
+	due := time.Date(2024, 1, 20, 0, 0, 0, 0, time.Local).UnixMilli()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wiki/rest/api/user/current":
+			_, _ = w.Write([]byte(`{"accountId": "557058:abc", "displayName": "Jane Doe"}`))
+		case "/wiki/rest/api/inlinetasks/search":
+			if r.URL.Query().Get("assignee") != "557058:abc" || r.URL.Query().Get("status") != "incomplete" {
+				t.Errorf("Expected the incomplete tasks of the current user, got %s", r.URL.RawQuery)
+			}
+			if r.URL.Query().Get("start") == "" {
+				_, _ = w.Write([]byte(`{"results": [
+					{"id": 7, "contentId": 1001, "status": "INCOMPLETE", "title": "Release checklist",
+					 "description": "<span>Update the <strong>changelog</strong> &amp; tag v2</span>", "updateDate": 1705312800000, "dueDate": ` + strconv.FormatInt(due, 10) + `},
+					{"id": 8, "contentId": 1001, "status": "COMPLETE", "title": "Release checklist", "description": "Freeze the branch"}
+				], "_links": {"next": "/rest/api/inlinetasks/search?assignee=557058%3Aabc&status=incomplete&start=2&limit=50"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"results": [
+				{"id": 9, "contentId": 2002, "status": "incomplete", "title": "Team sync", "body": "<p>Book the room</p>", "dueDate": null}
+			], "_links": {}}`))
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+
+	tasks, err := p.GetAssignedInlineTasks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Expected the 2 incomplete tasks of both pages, got %+v", tasks)
+	}
+
+	task := tasks[0]
+	if task.Title != "Update the changelog & tag v2" || task.Description != "Task on: Release checklist" {
+		t.Errorf("Expected the task text and its page, got '%s' and '%s'", task.Title, task.Description)
+	}
+	if task.URL != server.URL+"/wiki/pages/viewpage.action?pageId=1001&focusedTaskId=7" {
+		t.Errorf("Expected a link to the task, got '%s'", task.URL)
+	}
+	if !slices.Equal(task.Tags, []string{"task", "due:2024-01-20"}) {
+		t.Errorf("Expected the due date tag, got %v", task.Tags)
+	}
+	if !task.UpdatedAt.Equal(time.UnixMilli(1705312800000)) {
+		t.Errorf("Expected the update date of the task, got %v", task.UpdatedAt)
+	}
+	if task.Provenance == nil || !strings.Contains(task.Provenance.Query, "inlinetasks/search") {
+		t.Errorf("Expected the search in the provenance, got %+v", task.Provenance)
+	}
+
+	if tasks[1].Title != "Book the room" || !slices.Equal(tasks[1].Tags, []string{"task"}) {
+		t.Errorf("Expected the task without due date, got %+v", tasks[1])
+	}
+}
+
+func TestProvider_GetAssignedInlineTasks_NotConfigured(t *testing.T) {
+	p := NewProvider(provider.Config{})
+	if _, err := p.GetAssignedInlineTasks(context.Background()); err == nil {
+		t.Error("Expected error for unconfigured provider, got nil")
+	}
+}
+
+func TestTaskText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Plain task", "Plain task"},
+		{`<span class="placeholder-inline-tasks">Ping <ac:link><ri:user ri:account-id="abc" /></ac:link> about it</span>`, "Ping about it"},
+		{"<p>Fix&nbsp;the\n  <em>build</em></p>", "Fix the build"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if result := taskText(tt.input); result != tt.expected {
+			t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package confluence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// GetWatchedUpdates retrieves the pages watched by the user that someone else updated within
+// the since range (for todos)
+func (p *Provider) GetWatchedUpdates(ctx context.Context, since string) ([]TodoItem, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("Confluence provider not configured")
+	}
+
+	// Ensure since has "-" prefix for CQL format
+	if !strings.HasPrefix(since, "-") {
+		since = "-" + since
+	}
+
+	cql := p.inSpaces(fmt.Sprintf("watcher = currentUser() AND lastModified >= now(\"%s\")", since))
+
+	// CQL can't filter on the last modifier, so the user's own edits are left out by comparing
+	// the author of the latest version, the pages they last changed being known to them
+	currentUser, err := p.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	searchResults, err := p.searchConfluence(ctx, cql, "content.version")
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for watched pages: %w", err)
+	}
+
+	provenance := provenance(cql, "")
+
+	var updates []TodoItem
+	for _, result := range searchResults.Results {
+		if result.Content.Version.By.id() == currentUser {
+			continue
+		}
+
+		description := "Updated"
+		if modifier := result.Content.Version.By.DisplayName; modifier != "" {
+			description = fmt.Sprintf("Updated by %s", modifier)
+		}
+
+		updates = append(updates, TodoItem{
+			ID:          result.Content.ID,
+			Title:       fmt.Sprintf("Updated: %s", result.Content.Title),
+			Description: description,
+			URL:         p.siteURL() + result.URL,
+			UpdatedAt:   parseLastModified(result.LastModified),
+			Tags:        []string{"watching"},
+			Details:     spaceDetails(result.ResultGlobalContainer.DisplayURL, result.URL),
+			Provenance:  provenance,
+		})
+	}
+
+	return updates, nil
+}
@@ -0,0 +1,60 @@
+package confluence
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+func TestProvider_GetWatchedUpdates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/wiki/rest/api/user/current" {
+			_, _ = w.Write([]byte(`{"accountId": "me"}`))
+			return
+		}
+		query := r.URL.Query()
+		expected := `watcher = currentUser() AND lastModified >= now("-1w")`
+		if query.Get("cql") != expected {
+			t.Errorf("Expected CQL '%s', got '%s'", expected, query.Get("cql"))
+		}
+		if query.Get("expand") != "content.version" {
+			t.Errorf("Expected the versions to be expanded, got '%s'", query.Get("expand"))
+		}
+		_, _ = w.Write([]byte(`{"results": [
+			{"content": {"id": "101", "title": "Runbook", "type": "page", "version": {"by": {"displayName": "Alice Martin"}}},
+			 "resultGlobalContainer": {"title": "Engineering", "displayUrl": "/spaces/ENG"},
+			 "url": "/spaces/ENG/pages/101", "lastModified": "2024-01-15T10:00:00.000Z"},
+			{"content": {"id": "102", "title": "Roadmap", "type": "page"}, "url": "/spaces/ENG/pages/102"},
+			{"content": {"id": "103", "title": "Notes", "type": "page", "version": {"by": {"accountId": "me", "displayName": "Me"}}}, "url": "/spaces/ENG/pages/103"}
+		]}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+
+	updates, err := p.GetWatchedUpdates(context.Background(), "1w")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// The page the user last changed is left out
+	if len(updates) != 2 {
+		t.Fatalf("Expected 2 updates, got %+v", updates)
+	}
+
+	update := updates[0]
+	if update.Title != "Updated: Runbook" || update.Description != "Updated by Alice Martin" {
+		t.Errorf("Expected the page and its last modifier, got '%s' and '%s'", update.Title, update.Description)
+	}
+	if update.URL != server.URL+"/wiki/spaces/ENG/pages/101" || update.Details["space"] != "ENG" {
+		t.Errorf("Expected the page URL and space, got '%s' and %v", update.URL, update.Details)
+	}
+	if update.UpdatedAt.Year() != 2024 || update.Provenance == nil {
+		t.Errorf("Expected the modification time and provenance, got %v and %+v", update.UpdatedAt, update.Provenance)
+	}
+	if updates[1].Description != "Updated" {
+		t.Errorf("Expected no modifier without version, got '%s'", updates[1].Description)
+	}
+}
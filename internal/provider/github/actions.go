@@ -0,0 +1,187 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"daily/internal/activity"
+)
+
+const (
+	// workflowRunsPageSize is the number of runs requested per page
+	workflowRunsPageSize = 50
+
+	// maxWorkflowRunsPerRepo caps the runs fetched from a single repository
+	maxWorkflowRunsPerRepo = 200
+
+	// maxConcurrentRepoFetches limits parallel workflow run requests
+	maxConcurrentRepoFetches = 5
+)
+
+// workflowRun is the subset of a GitHub Actions workflow run used for activities
+type workflowRun struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Event      string    `json:"event"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	HeadBranch string    `json:"head_branch"`
+	HTMLURL    string    `json:"html_url"`
+	CreatedAt  time.Time `json:"created_at"`
+	Repository struct {
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// getWorkflowRuns fetches workflow runs triggered by the user in each repository
+func (p *Provider) getWorkflowRuns(ctx context.Context, repos []string, from, to time.Time) ([]activity.Activity, error) {
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		activities []activity.Activity
+		firstErr   error
+	)
+	semaphore := make(chan struct{}, maxConcurrentRepoFetches)
+
+	for _, repo := range repos {
+		wg.Add(1)
+		go func(repo string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			runs, err := p.fetchRepoWorkflowRuns(ctx, repo, from, to)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			for _, run := range runs {
+				activities = append(activities, workflowRunToActivity(run))
+			}
+		}(repo)
+	}
+	wg.Wait()
+
+	// Only report an error when nothing could be fetched
+	if len(activities) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(activities, func(i, j int) bool {
+		return activities[i].Timestamp.After(activities[j].Timestamp)
+	})
+
+	return activities, nil
+}
+
+// fetchRepoWorkflowRuns pages through the runs API for a repository up to maxWorkflowRunsPerRepo
+func (p *Provider) fetchRepoWorkflowRuns(ctx context.Context, repo string, from, to time.Time) ([]workflowRun, error) {
+	created := fmt.Sprintf("%s..%s", from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+
+	var runs []workflowRun
+	for page := 1; len(runs) < maxWorkflowRunsPerRepo; page++ {
+		runsURL := fmt.Sprintf("%s/repos/%s/actions/runs?actor=%s&created=%s&per_page=%d&page=%d",
+			p.baseURL, repo, url.QueryEscape(p.config.Username), url.QueryEscape(created), workflowRunsPageSize, page)
+
+		var result struct {
+			WorkflowRuns []workflowRun `json:"workflow_runs"`
+		}
+		if err := p.makeRequest(ctx, runsURL, &result); err != nil {
+			return nil, p.forbidden(CapabilityWorkflowRuns, err)
+		}
+
+		runs = append(runs, result.WorkflowRuns...)
+		if len(result.WorkflowRuns) < workflowRunsPageSize {
+			break
+		}
+	}
+
+	if len(runs) > maxWorkflowRunsPerRepo {
+		runs = runs[:maxWorkflowRunsPerRepo]
+	}
+	return runs, nil
+}
+
+// workflowRunToActivity maps a workflow run to a CI or deployment activity
+func workflowRunToActivity(run workflowRun) activity.Activity {
+	actType := activity.ActivityTypeCI
+	lowerName := strings.ToLower(run.Name)
+	if strings.Contains(lowerName, "deploy") || strings.Contains(lowerName, "release") {
+		actType = activity.ActivityTypeDeployment
+	}
+
+	// In-progress runs have no conclusion yet, so fall back to their status
+	outcome := run.Conclusion
+	if outcome == "" {
+		outcome = run.Status
+	}
+
+	description := fmt.Sprintf("%s Workflow run %s in %s", workflowOutcomeIcon(outcome), outcome, run.Repository.FullName)
+	if run.HeadBranch != "" {
+		description = fmt.Sprintf("%s on %s", description, run.HeadBranch)
+	}
+	if run.Event != "" {
+		description = fmt.Sprintf("%s (%s)", description, run.Event)
+	}
+
+	return activity.Activity{
+		ID:          fmt.Sprintf("github-run-%d", run.ID),
+		Type:        actType,
+		Title:       run.Name,
+		Description: description,
+		URL:         run.HTMLURL,
+		Platform:    "github",
+		Timestamp:   run.CreatedAt,
+		Tags:        []string{run.Repository.Name, outcome},
+		Details:     activity.NewDetails(activity.DetailRepo, run.Repository.FullName, activity.DetailStatus, outcome),
+	}
+}
+
+// workflowOutcomeIcon returns an icon for a run conclusion or status
+func workflowOutcomeIcon(outcome string) string {
+	switch outcome {
+	case "success":
+		return "✅"
+	case "failure", "timed_out", "startup_failure":
+		return "❌"
+	case "cancelled", "skipped":
+		return "⏹️"
+	case "queued", "in_progress", "waiting", "requested", "pending":
+		return "🟡"
+	default:
+		return "⚪"
+	}
+}
+
+// activityRepos returns the owner/repo names referenced by the activities plus the configured repos
+func (p *Provider) activityRepos(activities []activity.Activity) []string {
+	seen := make(map[string]bool)
+	var repos []string
+
+	add := func(repo string) {
+		if repo != "" && !seen[repo] {
+			seen[repo] = true
+			repos = append(repos, repo)
+		}
+	}
+
+	for _, repo := range p.config.Repos {
+		add(repo)
+	}
+	for _, act := range activities {
+		add(extractRepoFromURL(act.URL))
+	}
+
+	return repos
+}
@@ -0,0 +1,183 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+const workflowRunsPayload = `{
+	"total_count": 2,
+	"workflow_runs": [
+		{
+			"id": 101,
+			"name": "Deploy production",
+			"event": "workflow_dispatch",
+			"status": "completed",
+			"conclusion": "success",
+			"head_branch": "main",
+			"html_url": "https://github.com/owner/repo/actions/runs/101",
+			"created_at": "2024-01-15T10:00:00Z",
+			"repository": {"name": "repo", "full_name": "owner/repo"}
+		},
+		{
+			"id": 102,
+			"name": "CI",
+			"event": "push",
+			"status": "in_progress",
+			"conclusion": null,
+			"head_branch": "feature",
+			"html_url": "https://github.com/owner/repo/actions/runs/102",
+			"created_at": "2024-01-15T11:00:00Z",
+			"repository": {"name": "repo", "full_name": "owner/repo"}
+		}
+	]
+}`
+
+func TestWorkflowRunToActivity(t *testing.T) {
+	var result struct {
+		WorkflowRuns []workflowRun `json:"workflow_runs"`
+	}
+	if err := json.Unmarshal([]byte(workflowRunsPayload), &result); err != nil {
+		t.Fatalf("Failed to parse payload: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		run         workflowRun
+		expectType  activity.ActivityType
+		expectTag   string
+		expectInDes string
+	}{
+		{"completed deploy", result.WorkflowRuns[0], activity.ActivityTypeDeployment, "success", "✅ Workflow run success in owner/repo on main (workflow_dispatch)"},
+		{"in-progress CI", result.WorkflowRuns[1], activity.ActivityTypeCI, "in_progress", "🟡 Workflow run in_progress in owner/repo on feature (push)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			act := workflowRunToActivity(tt.run)
+
+			if act.Type != tt.expectType {
+				t.Errorf("Expected type '%s', got '%s'", tt.expectType, act.Type)
+			}
+			if act.Title != tt.run.Name {
+				t.Errorf("Expected title '%s', got '%s'", tt.run.Name, act.Title)
+			}
+			if act.Description != tt.expectInDes {
+				t.Errorf("Expected description '%s', got '%s'", tt.expectInDes, act.Description)
+			}
+			if act.URL != tt.run.HTMLURL {
+				t.Errorf("Expected URL '%s', got '%s'", tt.run.HTMLURL, act.URL)
+			}
+			if len(act.Tags) != 2 || act.Tags[1] != tt.expectTag {
+				t.Errorf("Expected tags [repo %s], got %v", tt.expectTag, act.Tags)
+			}
+			if act.Details[activity.DetailRepo] != "owner/repo" || act.Details[activity.DetailStatus] != tt.expectTag {
+				t.Errorf("Expected repo and status details, got %v", act.Details)
+			}
+		})
+	}
+}
+
+func TestProvider_GetWorkflowRuns(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Query().Get("actor") != "testuser" {
+			t.Errorf("Expected actor=testuser, got %s", r.URL.Query().Get("actor"))
+		}
+		switch r.URL.Path {
+		case "/repos/owner/repo/actions/runs":
+			_, _ = fmt.Fprint(w, workflowRunsPayload)
+		case "/repos/owner/broken/actions/runs":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "token", Enabled: true})
+	p.baseURL = server.URL
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.getWorkflowRuns(context.Background(), []string{"owner/repo", "owner/broken"}, from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error when one repo succeeds, got: %v", err)
+	}
+
+	if requests.Load() != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests.Load())
+	}
+	if len(activities) != 2 {
+		t.Fatalf("Expected 2 activities, got %d", len(activities))
+	}
+	// Newest run first
+	if activities[0].ID != "github-run-102" {
+		t.Errorf("Expected newest run first, got '%s'", activities[0].ID)
+	}
+}
+
+func TestProvider_FetchRepoWorkflowRuns_Pagination(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+
+		count := workflowRunsPageSize
+		if page == "2" {
+			count = 3
+		}
+		runs := make([]string, count)
+		for i := range runs {
+			runs[i] = fmt.Sprintf(`{"id":%s%02d,"name":"CI","status":"completed","conclusion":"success"}`, page, i)
+		}
+		_, _ = fmt.Fprintf(w, `{"workflow_runs":[%s]}`, strings.Join(runs, ","))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "token", Enabled: true})
+	p.baseURL = server.URL
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	runs, err := p.fetchRepoWorkflowRuns(context.Background(), "owner/repo", from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(pages) != 2 {
+		t.Errorf("Expected 2 pages, got %v", pages)
+	}
+	if len(runs) != workflowRunsPageSize+3 {
+		t.Errorf("Expected %d runs, got %d", workflowRunsPageSize+3, len(runs))
+	}
+}
+
+func TestProvider_ActivityRepos(t *testing.T) {
+	p := NewProvider(provider.Config{Repos: []string{"owner/configured", "owner/repo"}})
+
+	repos := p.activityRepos([]activity.Activity{
+		{URL: "https://github.com/owner/repo/pull/1"},
+		{URL: "https://github.com/other/project/commit/abc"},
+		{URL: ""},
+	})
+
+	expected := []string{"owner/configured", "owner/repo", "other/project"}
+	if len(repos) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, repos)
+	}
+	for i, repo := range expected {
+		if repos[i] != repo {
+			t.Errorf("Expected repo %d to be '%s', got '%s'", i, repo, repos[i])
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxIssueCommentsFetched caps the conversation comments requested for a PR; the issue
+// comments API can't be sorted newest first, so the most recent are kept from this page
+const maxIssueCommentsFetched = 100
+
+// PRComment is a conversation or inline review comment on a pull request
+type PRComment struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+	Path      string    `json:"path,omitempty"` // File of an inline review comment
+}
+
+// prCommentItem is the subset of an issue or review comment returned by the REST API
+type prCommentItem struct {
+	Body      string    `json:"body"`
+	HTMLURL   string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+	Path      string    `json:"path"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+// GetPRComments returns the most recent issue and review comments of a pull request, newest first
+func (p *Provider) GetPRComments(ctx context.Context, repo string, prNumber int, limit int) ([]PRComment, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("GitHub provider not configured")
+	}
+
+	if repo == "" || prNumber == 0 {
+		return nil, fmt.Errorf("repository and PR number are required")
+	}
+
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	issueCommentsURL := fmt.Sprintf("%s/repos/%s/issues/%d/comments?per_page=%d",
+		p.baseURL, repo, prNumber, maxIssueCommentsFetched)
+	var issueComments []prCommentItem
+	if err := p.makeRequest(ctx, issueCommentsURL, &issueComments); err != nil {
+		return nil, fmt.Errorf("failed to get PR comments: %w", err)
+	}
+
+	reviewCommentsURL := fmt.Sprintf("%s/repos/%s/pulls/%d/comments?sort=created&direction=desc&per_page=%d",
+		p.baseURL, repo, prNumber, limit)
+	var reviewComments []prCommentItem
+	if err := p.makeRequest(ctx, reviewCommentsURL, &reviewComments); err != nil {
+		return nil, fmt.Errorf("failed to get PR review comments: %w", err)
+	}
+
+	comments := make([]PRComment, 0, len(issueComments)+len(reviewComments))
+	for _, item := range append(issueComments, reviewComments...) {
+		comments = append(comments, PRComment{
+			Author:    item.User.Login,
+			Body:      item.Body,
+			URL:       item.HTMLURL,
+			CreatedAt: item.CreatedAt,
+			Path:      item.Path,
+		})
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.After(comments[j].CreatedAt)
+	})
+	if len(comments) > limit {
+		comments = comments[:limit]
+	}
+
+	return comments, nil
+}
@@ -0,0 +1,99 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+func newCommentsTestProvider(serverURL string) *Provider {
+	p := NewProvider(provider.Config{
+		Enabled:  true,
+		Token:    "token",
+		Username: "testuser",
+	})
+	p.baseURL = serverURL
+	return p
+}
+
+func TestProvider_GetPRComments(t *testing.T) {
+	var reviewQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/42/comments":
+			_, _ = fmt.Fprint(w, `[
+				{"body": "First!", "html_url": "https://github.com/owner/repo/pull/42#issuecomment-1", "created_at": "2024-01-15T09:00:00Z", "user": {"login": "alice"}},
+				{"body": "LGTM", "html_url": "https://github.com/owner/repo/pull/42#issuecomment-2", "created_at": "2024-01-15T12:00:00Z", "user": {"login": "bob"}}
+			]`)
+		case "/repos/owner/repo/pulls/42/comments":
+			reviewQuery = r.URL.RawQuery
+			_, _ = fmt.Fprint(w, `[
+				{"body": "Nit: rename this", "html_url": "https://github.com/owner/repo/pull/42#discussion_r3", "created_at": "2024-01-15T10:00:00Z", "path": "main.go", "user": {"login": "carol"}}
+			]`)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := newCommentsTestProvider(server.URL)
+
+	comments, err := p.GetPRComments(context.Background(), "owner/repo", 42, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(comments) != 3 {
+		t.Fatalf("Expected 3 comments, got %d", len(comments))
+	}
+
+	expectedAuthors := []string{"bob", "carol", "alice"}
+	for i, author := range expectedAuthors {
+		if comments[i].Author != author {
+			t.Errorf("Expected comment %d by '%s', got '%s'", i, author, comments[i].Author)
+		}
+	}
+	if comments[1].Path != "main.go" {
+		t.Errorf("Expected review comment path 'main.go', got '%s'", comments[1].Path)
+	}
+	if comments[0].Body != "LGTM" {
+		t.Errorf("Expected body 'LGTM', got '%s'", comments[0].Body)
+	}
+	if !strings.Contains(reviewQuery, "direction=desc") || !strings.Contains(reviewQuery, "per_page=10") {
+		t.Errorf("Expected newest review comments to be requested, got query '%s'", reviewQuery)
+	}
+
+	// The limit applies across both kinds of comments
+	comments, err = p.GetPRComments(context.Background(), "owner/repo", 42, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(comments) != 2 || comments[0].Author != "bob" || comments[1].Author != "carol" {
+		t.Errorf("Expected the 2 most recent comments, got %+v", comments)
+	}
+}
+
+func TestProvider_GetPRComments_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p := newCommentsTestProvider(server.URL)
+
+	if _, err := p.GetPRComments(context.Background(), "owner/repo", 42, 10); err == nil {
+		t.Error("Expected error for failed request, got nil")
+	}
+	if _, err := p.GetPRComments(context.Background(), "", 42, 10); err == nil {
+		t.Error("Expected error for missing repository, got nil")
+	}
+	if _, err := NewProvider(provider.Config{}).GetPRComments(context.Background(), "owner/repo", 42, 10); err == nil {
+		t.Error("Expected error for unconfigured provider, got nil")
+	}
+}
@@ -1,29 +1,53 @@
 package github
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"daily/internal/activity"
+	"daily/internal/cache"
+	"daily/internal/metrics"
 	"daily/internal/provider"
 )
 
+// defaultBaseURL is the GitHub REST API root used unless overridden in tests
+const defaultBaseURL = "https://api.github.com"
+
 type Provider struct {
-	config provider.Config
-	client *http.Client
+	config    provider.Config
+	client    *http.Client
+	baseURL   string
+	teamCache *cache.TTLStore // Team memberships, not cached when nil
+
+	mergeQueueUnsupported atomic.Bool // The GraphQL API has no merge queues, e.g. on older GitHub Enterprise servers
+
+	degradedMu sync.Mutex
+	degraded   map[string]*CapabilityError // Capabilities refused to the token, see Degraded
+
+	warnings []string // Parts of the last GetActivities that failed
 }
 
 func NewProvider(config provider.Config) *Provider {
+	// The cache is optional: teams are fetched on every run when it can't be located
+	teamCache, _ := cache.NewTTLStore("github_teams")
+
 	return &Provider{
 		config: config,
 		client: &http.Client{
-			Timeout: 30 * time.Second, // Reasonable timeout for API calls
+			Timeout:   30 * time.Second, // Reasonable timeout for API calls
+			Transport: metrics.NewTransport("github"),
 		},
+		baseURL:   defaultBaseURL,
+		teamCache: teamCache,
 	}
 }
 
@@ -35,32 +59,64 @@ func (p *Provider) IsConfigured() bool {
 	return p.config.Enabled && p.config.Token != "" && p.config.Username != ""
 }
 
+// ConfigSpec describes the configuration fields of the GitHub provider
+func (p *Provider) ConfigSpec() []provider.ConfigField {
+	return []provider.ConfigField{
+		{Name: "username", Required: true, Description: "GitHub username"},
+		{Name: "token", Required: true, Secret: true, Description: "GitHub Personal Access Token"},
+		{Name: "token_cmd", Description: "Command printing the token, overriding token, e.g. pass show github/daily-pat"},
+		{Name: "filter", Description: "GitHub search filter added to every query"},
+		{Name: "stale_after_days", Description: "Days before a pending review is highlighted as stale (default 3)"},
+		{Name: "repos", Description: "Repositories (owner/repo) always checked for workflow runs"},
+		{Name: "review_teams", Description: "Teams (org/slug) searched for review requests, instead of every team of the user"},
+		{Name: "exclude_teams", Description: "Teams (org/slug) never searched for review requests"},
+	}
+}
+
 func (p *Provider) GetActivities(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
 	if !p.IsConfigured() {
 		return nil, fmt.Errorf("GitHub provider not configured")
 	}
 
 	activities := make([]activity.Activity, 0)
+	p.warnings = nil
 
 	// Get commits - continue even if this fails
 	commits, err := p.getCommits(ctx, from, to)
 	if err != nil {
-		// Log error but continue with pull requests - warning handled by aggregator
-	} else {
-		activities = append(activities, commits...)
+		p.warn("commits", err)
 	}
+	activities = append(activities, commits...)
 
 	// Get pull requests - continue even if this fails
 	pullRequests, err := p.getPullRequests(ctx, from, to)
 	if err != nil {
-		// Log error but continue with partial results - warning handled by aggregator
-	} else {
-		activities = append(activities, pullRequests...)
+		p.warn("pull requests", err)
+	}
+	activities = append(activities, pullRequests...)
+
+	// Get workflow runs in repos seen above or configured - continue even if this fails
+	if repos := p.activityRepos(activities); len(repos) > 0 {
+		runs, err := p.getWorkflowRuns(ctx, repos, from, to)
+		if err != nil {
+			p.warn("workflow runs", err)
+		}
+		activities = append(activities, runs...)
 	}
 
 	return activities, nil
 }
 
+// warn records that a part of GetActivities failed, reported by the aggregator
+func (p *Provider) warn(part string, err error) {
+	p.warnings = append(p.warnings, fmt.Sprintf("Failed to fetch %s: %v", part, err))
+}
+
+// Warnings describes the parts of the last GetActivities that failed
+func (p *Provider) Warnings() []string {
+	return p.warnings
+}
+
 func (p *Provider) getCommits(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
 	// Search for commits by the user in the specified time range
 	// For single day: use just the date. For range: use from..to format
@@ -80,34 +136,33 @@ func (p *Provider) getCommits(ctx context.Context, from, to time.Time) ([]activi
 		query = fmt.Sprintf("%s %s", query, p.config.Filter)
 	}
 
-	searchURL := fmt.Sprintf("https://api.github.com/search/commits?q=%s&sort=committer-date&order=desc",
+	searchURL := fmt.Sprintf("%s/search/commits?q=%s&sort=committer-date&order=desc", p.baseURL,
 		url.QueryEscape(query))
 
-	var searchResult struct {
-		Items []struct {
-			SHA    string `json:"sha"`
-			Commit struct {
-				Message   string `json:"message"`
-				Committer struct {
-					Date time.Time `json:"date"`
-				} `json:"committer"`
-			} `json:"commit"`
-			Repository struct {
-				Name     string `json:"name"`
-				FullName string `json:"full_name"`
-				HTMLURL  string `json:"html_url"`
-			} `json:"repository"`
-		} `json:"items"`
-	}
-
-	if err := p.makeRequestWithHeaders(ctx, searchURL, map[string]string{
+	type commitSearchItem struct {
+		SHA    string `json:"sha"`
+		Commit struct {
+			Message   string `json:"message"`
+			Committer struct {
+				Date time.Time `json:"date"`
+			} `json:"committer"`
+		} `json:"commit"`
+		Repository struct {
+			Name     string `json:"name"`
+			FullName string `json:"full_name"`
+			HTMLURL  string `json:"html_url"`
+		} `json:"repository"`
+	}
+
+	items, err := searchItems[commitSearchItem](ctx, p, searchURL, map[string]string{
 		"Accept": "application/vnd.github.cloak-preview+json", // Required for commit search
-	}, &searchResult); err != nil {
-		return nil, err
+	})
+	if err != nil {
+		return nil, p.forbidden(CapabilityCommitSearch, err)
 	}
 
 	var activities []activity.Activity
-	for _, item := range searchResult.Items {
+	for _, item := range items {
 		// Only include commits from the specified time range
 		if item.Commit.Committer.Date.Before(from) || item.Commit.Committer.Date.After(to) {
 			continue
@@ -122,6 +177,7 @@ func (p *Provider) getCommits(ctx context.Context, from, to time.Time) ([]activi
 			Platform:    "github",
 			Timestamp:   item.Commit.Committer.Date,
 			Tags:        []string{item.Repository.Name},
+			Details:     activity.NewDetails(activity.DetailRepo, item.Repository.FullName),
 		})
 	}
 
@@ -147,31 +203,26 @@ func (p *Provider) getPullRequests(ctx context.Context, from, to time.Time) ([]a
 		query = fmt.Sprintf("%s %s", query, p.config.Filter)
 	}
 
-	searchURL := fmt.Sprintf("https://api.github.com/search/issues?q=%s&sort=created&order=desc",
+	searchURL := fmt.Sprintf("%s/search/issues?q=%s&sort=created&order=desc", p.baseURL,
 		url.QueryEscape(query))
 
-	var searchResult struct {
-		Items []struct {
-			Number     int       `json:"number"`
-			Title      string    `json:"title"`
-			Body       string    `json:"body"`
-			HTMLURL    string    `json:"html_url"`
-			State      string    `json:"state"`
-			CreatedAt  time.Time `json:"created_at"`
-			UpdatedAt  time.Time `json:"updated_at"`
-			Repository struct {
-				Name     string `json:"name"`
-				FullName string `json:"full_name"`
-			} `json:"repository_url"`
-		} `json:"items"`
+	type prSearchItem struct {
+		Number    int       `json:"number"`
+		Title     string    `json:"title"`
+		Body      string    `json:"body"`
+		HTMLURL   string    `json:"html_url"`
+		State     string    `json:"state"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
 	}
 
-	if err := p.makeRequest(ctx, searchURL, &searchResult); err != nil {
-		return nil, err
+	items, err := searchItems[prSearchItem](ctx, p, searchURL, nil)
+	if err != nil {
+		return nil, p.forbidden(CapabilityPRSearch, err)
 	}
 
 	var activities []activity.Activity
-	for _, item := range searchResult.Items {
+	for _, item := range items {
 		// Only include PRs from the specified time range
 		if item.CreatedAt.Before(from) || item.CreatedAt.After(to) {
 			continue
@@ -189,12 +240,37 @@ func (p *Provider) getPullRequests(ctx context.Context, from, to time.Time) ([]a
 			Platform:    "github",
 			Timestamp:   item.CreatedAt,
 			Tags:        []string{repoName},
+			Details:     activity.NewDetails(activity.DetailRepo, extractRepoFromURL(item.HTMLURL), activity.DetailState, item.State),
 		})
 	}
 
 	return activities, nil
 }
 
+// searchItems fetches a page of search results and decodes each item on its own, so a
+// malformed item (e.g., from a deleted repository) is skipped with a warning instead of
+// failing the whole page
+func searchItems[T any](ctx context.Context, p *Provider, searchURL string, headers map[string]string) ([]T, error) {
+	var searchResult struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := p.makeRequestWithHeaders(ctx, searchURL, headers, &searchResult); err != nil {
+		return nil, err
+	}
+
+	items := make([]T, 0, len(searchResult.Items))
+	for i, raw := range searchResult.Items {
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping malformed GitHub search result %d: %v\n", i+1, err)
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
 func (p *Provider) makeRequest(ctx context.Context, url string, result any) error {
 	return p.makeRequestWithHeaders(ctx, url, nil, result)
 }
@@ -221,7 +297,7 @@ func (p *Provider) makeRequestWithHeaders(ctx context.Context, url string, extra
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("GitHub API request failed with status %d", resp.StatusCode)
+		return newAPIError(resp, false)
 	}
 
 	return json.NewDecoder(resp.Body).Decode(result)
@@ -240,30 +316,32 @@ func (p *Provider) GetOpenPRs(ctx context.Context) ([]TodoItem, error) {
 		query = fmt.Sprintf("%s %s", query, p.config.Filter)
 	}
 
-	searchURL := fmt.Sprintf("https://api.github.com/search/issues?q=%s&sort=updated&order=desc&per_page=50",
+	searchURL := fmt.Sprintf("%s/search/issues?q=%s&sort=updated&order=desc&per_page=50", p.baseURL,
 		url.QueryEscape(query))
 
-	var searchResult struct {
-		Items []struct {
-			Number     int       `json:"number"`
-			Title      string    `json:"title"`
-			HTMLURL    string    `json:"html_url"`
-			UpdatedAt  time.Time `json:"updated_at"`
-			Repository struct {
-				Name     string `json:"name"`
-				FullName string `json:"full_name"`
-			} `json:"repository"`
-		} `json:"items"`
+	type openPRSearchItem struct {
+		Number     int       `json:"number"`
+		Title      string    `json:"title"`
+		HTMLURL    string    `json:"html_url"`
+		UpdatedAt  time.Time `json:"updated_at"`
+		Repository struct {
+			Name     string `json:"name"`
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		Milestone *searchMilestone `json:"milestone"`
+		Labels    []searchLabel    `json:"labels"`
 	}
 
 	// Note: GitHub Search API sometimes returns repository data in different fields
 	// We'll need to extract the repo name from the HTML URL if needed
-	if err := p.makeRequest(ctx, searchURL, &searchResult); err != nil {
-		return nil, err
+	items, err := searchItems[openPRSearchItem](ctx, p, searchURL, nil)
+	if err != nil {
+		return nil, p.forbidden(CapabilityPRSearch, err)
 	}
+	provenance := p.provenance(query, "")
 
 	var todos []TodoItem
-	for _, item := range searchResult.Items {
+	for _, item := range items {
 		// Extract repository name from URL or repository field
 		repoName := fmt.Sprintf("PR #%d", item.Number)
 		repoFullName := ""
@@ -288,9 +366,12 @@ func (p *Provider) GetOpenPRs(ctx context.Context) ([]TodoItem, error) {
 			Description: fmt.Sprintf("Open PR in %s", repoName),
 			URL:         item.HTMLURL,
 			UpdatedAt:   item.UpdatedAt,
-			Tags:        []string{repoName, "open"},
+			Tags:        append([]string{repoName, "open"}, labelNames(item.Labels)...),
 			Number:      item.Number,
 			Repository:  repoFullName,
+			Milestone:   milestoneTitle(item.Milestone),
+			Details:     activity.NewDetails(activity.DetailRepo, repoFullName, activity.DetailState, "open"),
+			Provenance:  provenance,
 		})
 	}
 
@@ -310,28 +391,30 @@ func (p *Provider) GetPendingReviews(ctx context.Context) ([]TodoItem, error) {
 		query = fmt.Sprintf("%s %s", query, p.config.Filter)
 	}
 
-	searchURL := fmt.Sprintf("https://api.github.com/search/issues?q=%s&sort=updated&order=desc&per_page=50",
+	searchURL := fmt.Sprintf("%s/search/issues?q=%s&sort=updated&order=desc&per_page=50", p.baseURL,
 		url.QueryEscape(query))
 
-	var searchResult struct {
-		Items []struct {
-			Number     int       `json:"number"`
-			Title      string    `json:"title"`
-			HTMLURL    string    `json:"html_url"`
-			UpdatedAt  time.Time `json:"updated_at"`
-			Repository struct {
-				Name     string `json:"name"`
-				FullName string `json:"full_name"`
-			} `json:"repository"`
-		} `json:"items"`
+	type reviewSearchItem struct {
+		Number     int       `json:"number"`
+		Title      string    `json:"title"`
+		HTMLURL    string    `json:"html_url"`
+		UpdatedAt  time.Time `json:"updated_at"`
+		Repository struct {
+			Name     string `json:"name"`
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		Milestone *searchMilestone `json:"milestone"`
+		Labels    []searchLabel    `json:"labels"`
 	}
 
-	if err := p.makeRequest(ctx, searchURL, &searchResult); err != nil {
-		return nil, err
+	items, err := searchItems[reviewSearchItem](ctx, p, searchURL, nil)
+	if err != nil {
+		return nil, p.forbidden(CapabilityPRSearch, err)
 	}
+	provenance := p.provenance(query, "")
 
 	var todos []TodoItem
-	for _, item := range searchResult.Items {
+	for _, item := range items {
 		// Extract repository name from URL or repository field
 		repoName := fmt.Sprintf("PR #%d", item.Number)
 		repoFullName := ""
@@ -356,9 +439,12 @@ func (p *Provider) GetPendingReviews(ctx context.Context) ([]TodoItem, error) {
 			Description: fmt.Sprintf("Review requested in %s", repoName),
 			URL:         item.HTMLURL,
 			UpdatedAt:   item.UpdatedAt,
-			Tags:        []string{repoName, "review-requested"},
+			Tags:        append([]string{repoName, "review-requested"}, labelNames(item.Labels)...),
 			Number:      item.Number,
 			Repository:  repoFullName,
+			Milestone:   milestoneTitle(item.Milestone),
+			Details:     activity.NewDetails(activity.DetailRepo, repoFullName),
+			Provenance:  provenance,
 		})
 	}
 
@@ -378,10 +464,10 @@ func (p *Provider) GetUserReviewRequests(ctx context.Context) ([]TodoItem, error
 		query = fmt.Sprintf("%s %s", query, p.config.Filter)
 	}
 
-	searchURL := fmt.Sprintf("https://api.github.com/search/issues?q=%s&sort=updated&order=desc&per_page=50",
+	searchURL := fmt.Sprintf("%s/search/issues?q=%s&sort=updated&order=desc&per_page=50", p.baseURL,
 		url.QueryEscape(query))
 
-	return p.fetchReviewRequests(ctx, searchURL)
+	return p.fetchReviewRequests(ctx, searchURL, p.provenance(query, ""))
 }
 
 // GetTeamReviewRequests retrieves pull requests where the user's teams are requested as reviewers
@@ -390,16 +476,15 @@ func (p *Provider) GetTeamReviewRequests(ctx context.Context) ([]TodoItem, error
 		return nil, fmt.Errorf("GitHub provider not configured")
 	}
 
-	// First, get user's teams
-	teams, err := p.getUserTeams(ctx)
+	selection, err := p.ReviewTeams(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user teams: %w", err)
+		return nil, err
 	}
 
 	var allTodos []TodoItem
 
 	// Search for team review requests
-	for _, team := range teams {
+	for _, team := range selection.Queried {
 		query := fmt.Sprintf("team-review-requested:%s state:open type:pr -is:draft", team)
 
 		// Add filter if configured and validate it's not malformed
@@ -407,10 +492,10 @@ func (p *Provider) GetTeamReviewRequests(ctx context.Context) ([]TodoItem, error
 			query = fmt.Sprintf("%s %s", query, p.config.Filter)
 		}
 
-		searchURL := fmt.Sprintf("https://api.github.com/search/issues?q=%s&sort=updated&order=desc&per_page=20",
+		searchURL := fmt.Sprintf("%s/search/issues?q=%s&sort=updated&order=desc&per_page=20", p.baseURL,
 			url.QueryEscape(query))
 
-		teamTodos, err := p.fetchReviewRequests(ctx, searchURL)
+		teamTodos, err := p.fetchReviewRequests(ctx, searchURL, p.provenance(query, team))
 		if err != nil {
 			// Log error but continue with other teams
 			continue
@@ -427,87 +512,82 @@ func (p *Provider) GetTeamReviewRequests(ctx context.Context) ([]TodoItem, error
 	return allTodos, nil
 }
 
-// fetchReviewRequests is a helper method to fetch review requests from the GitHub API
-func (p *Provider) fetchReviewRequests(ctx context.Context, searchURL string) ([]TodoItem, error) {
-	var searchResult struct {
-		Items []struct {
-			Number     int       `json:"number"`
-			Title      string    `json:"title"`
-			Body       string    `json:"body"`
-			HTMLURL    string    `json:"html_url"`
-			UpdatedAt  time.Time `json:"updated_at"`
-			Repository struct {
-				Name     string `json:"name"`
-				FullName string `json:"full_name"`
-			} `json:"repository"`
-			User struct {
-				Login string `json:"login"`
-			} `json:"user"`
-		} `json:"items"`
+// fetchReviewRequests is a helper method to fetch review requests from the GitHub API,
+// recording the search that listed them in provenance
+func (p *Provider) fetchReviewRequests(ctx context.Context, searchURL string, provenance *provider.Provenance) ([]TodoItem, error) {
+	type reviewRequestSearchItem struct {
+		Number        int       `json:"number"`
+		Title         string    `json:"title"`
+		Body          string    `json:"body"`
+		HTMLURL       string    `json:"html_url"`
+		RepositoryURL string    `json:"repository_url"`
+		UpdatedAt     time.Time `json:"updated_at"`
+		Repository    struct {
+			Name     string `json:"name"`
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		Milestone *searchMilestone `json:"milestone"`
+		Labels    []searchLabel    `json:"labels"`
+		User      struct {
+			Login string `json:"login"`
+		} `json:"user"`
 	}
 
-	if err := p.makeRequest(ctx, searchURL, &searchResult); err != nil {
-		return nil, err
+	items, err := searchItems[reviewRequestSearchItem](ctx, p, searchURL, nil)
+	if err != nil {
+		return nil, p.forbidden(CapabilityPRSearch, err)
 	}
 
 	var todos []TodoItem
-	for _, item := range searchResult.Items {
-		// Extract repository name from URL or repository field
-		repoName := fmt.Sprintf("PR #%d", item.Number)
-		repoFullName := ""
+	for _, item := range items {
+		// The base repository of the PR, which the checks are reported on. Search items of
+		// fork PRs often come without a repository object, so it is read from the URLs.
+		repoFullName := item.Repository.FullName
+		if repoFullName == "" {
+			repoFullName = extractRepoFromAPIURL(item.RepositoryURL)
+		}
+		if repoFullName == "" {
+			repoFullName = extractRepoFromURL(item.HTMLURL)
+		}
 
-		if item.Repository.FullName != "" {
-			repoName = item.Repository.FullName
-			repoFullName = item.Repository.FullName
-		} else if item.Repository.Name != "" {
+		// A name without owner is only good for display, items without any are not enriched
+		repoName := repoFullName
+		if repoName == "" {
 			repoName = item.Repository.Name
-			repoFullName = item.Repository.Name
-		} else {
-			// Extract from HTML URL: https://github.com/owner/repo/pull/123
-			repoFullName = extractRepoFromURL(item.HTMLURL)
-			if repoFullName != "" {
-				repoName = repoFullName
-			}
 		}
+		location, tags := repoName, []string{repoName, "review-requested"}
+		if repoName == "" {
+			location, tags = "an unknown repository", []string{"review-requested"}
+		}
+		tags = append(tags, labelNames(item.Labels)...)
 
 		todos = append(todos, TodoItem{
 			ID:          fmt.Sprintf("github-review-%d", item.Number),
 			Title:       item.Title,
-			Description: fmt.Sprintf("Review requested in %s (by %s)", repoName, item.User.Login),
+			Description: fmt.Sprintf("Review requested in %s (by %s)", location, item.User.Login),
 			URL:         item.HTMLURL,
 			UpdatedAt:   item.UpdatedAt,
-			Tags:        []string{repoName, "review-requested"},
+			Tags:        tags,
 			Number:      item.Number,
 			Repository:  repoFullName,
+			Milestone:   milestoneTitle(item.Milestone),
+			Details:     activity.NewDetails(activity.DetailRepo, repoFullName),
+			Provenance:  provenance,
 		})
 	}
 
 	return todos, nil
 }
 
-// getUserTeams retrieves the teams that the user belongs to
-func (p *Provider) getUserTeams(ctx context.Context) ([]string, error) {
-	teamsURL := "https://api.github.com/user/teams"
-
-	var teams []struct {
-		Slug         string `json:"slug"`
-		Organization struct {
-			Login string `json:"login"`
-		} `json:"organization"`
-	}
-
-	if err := p.makeRequest(ctx, teamsURL, &teams); err != nil {
-		return nil, err
-	}
-
-	var teamNames []string
-	for _, team := range teams {
-		// Format as "org/team"
-		teamName := fmt.Sprintf("%s/%s", team.Organization.Login, team.Slug)
-		teamNames = append(teamNames, teamName)
+// provenance describes a search of the provider, and the team it was made for
+func (p *Provider) provenance(query, team string) *provider.Provenance {
+	return &provider.Provenance{
+		Provider:  "github",
+		Query:     query,
+		Team:      team,
+		Filter:    p.config.Filter,
+		FetchedAt: time.Now(),
 	}
-
-	return teamNames, nil
 }
 
 // extractRepoFromURL extracts the owner/repo from a GitHub URL
@@ -530,33 +610,232 @@ func extractRepoFromURL(htmlURL string) string {
 	return ""
 }
 
-// GetPRCIStatus retrieves CI status for a specific pull request
-func (p *Provider) GetPRCIStatus(ctx context.Context, repo string, prNumber int) (CIStatus, error) {
-	var ciStatus CIStatus
+// extractRepoFromAPIURL extracts the owner/repo from a GitHub API repository URL
+// e.g., https://api.github.com/repos/owner/repo -> owner/repo
+func extractRepoFromAPIURL(apiURL string) string {
+	_, repo, found := strings.Cut(apiURL, "/repos/")
+	if !found || strings.Count(repo, "/") != 1 {
+		return ""
+	}
+	return repo
+}
+
+// searchMilestone is the milestone object attached to search result items (null when unset)
+type searchMilestone struct {
+	Title string `json:"title"`
+}
+
+// searchLabel is a label attached to search result items
+type searchLabel struct {
+	Name string `json:"name"`
+}
+
+// labelNames returns the names of the labels, e.g. "P0" or "bug"
+func labelNames(labels []searchLabel) []string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label.Name != "" {
+			names = append(names, label.Name)
+		}
+	}
+	return names
+}
+
+// milestoneTitle returns the milestone title or an empty string when no milestone is set
+func milestoneTitle(m *searchMilestone) string {
+	if m == nil {
+		return ""
+	}
+	return m.Title
+}
+
+// maxPRProjects is the number of projects whose status is read for a pull request
+const maxPRProjects = 10
 
+// GetPRProjectStatus retrieves the ProjectsV2 "Status" field value of a pull request via GraphQL.
+// A PR in several projects with a status gets every status followed by its project, e.g.
+// "In Review (Platform), Todo (Roadmap)". It returns an empty string when the PR is not part
+// of any project with a status.
+func (p *Provider) GetPRProjectStatus(ctx context.Context, repo string, prNumber int) (string, error) {
 	if !p.IsConfigured() {
-		return ciStatus, fmt.Errorf("GitHub provider not configured")
+		return "", fmt.Errorf("GitHub provider not configured")
+	}
+
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || prNumber == 0 {
+		return "", fmt.Errorf("repository and PR number are required")
+	}
+
+	query := `query($owner: String!, $name: String!, $number: Int!, $projects: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      projectItems(first: $projects) {
+        nodes {
+          project { title }
+          fieldValueByName(name: "Status") {
+            ... on ProjectV2ItemFieldSingleSelectValue { name }
+          }
+        }
+      }
+    }
+  }
+}`
+
+	var result struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					ProjectItems struct {
+						Nodes []struct {
+							Project struct {
+								Title string `json:"title"`
+							} `json:"project"`
+							FieldValueByName *struct {
+								Name string `json:"name"`
+							} `json:"fieldValueByName"`
+						} `json:"nodes"`
+					} `json:"projectItems"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
 	}
 
-	if repo == "" || prNumber == 0 {
-		return ciStatus, fmt.Errorf("repository and PR number are required")
+	variables := map[string]any{"owner": owner, "name": name, "number": prNumber, "projects": maxPRProjects}
+	if err := p.makeGraphQLRequest(ctx, query, variables, &result); err != nil {
+		return "", err
 	}
 
-	// Get PR details first to get the head SHA
-	prURL := fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d", repo, prNumber)
+	if len(result.Errors) > 0 {
+		return "", fmt.Errorf("GitHub GraphQL error: %s", result.Errors[0].Message)
+	}
+
+	var statuses, withProject []string
+	for _, node := range result.Data.Repository.PullRequest.ProjectItems.Nodes {
+		if node.FieldValueByName != nil && node.FieldValueByName.Name != "" {
+			statuses = append(statuses, node.FieldValueByName.Name)
+			withProject = append(withProject, fmt.Sprintf("%s (%s)", node.FieldValueByName.Name, node.Project.Title))
+		}
+	}
+
+	if len(statuses) == 1 {
+		return statuses[0], nil
+	}
+	return strings.Join(withProject, ", "), nil
+}
+
+// makeGraphQLRequest posts a GraphQL query to the GitHub API and decodes the response
+func (p *Provider) makeGraphQLRequest(ctx context.Context, query string, variables map[string]any, result any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "bearer "+p.config.Token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "daily-cli/1.0")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp, true)
+	}
 
-	var prData struct {
-		Head struct {
-			SHA string `json:"sha"`
-		} `json:"head"`
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// pullRequest is the part of a pull request payload used to enrich review requests
+type pullRequest struct {
+	Additions      int       `json:"additions"`
+	Deletions      int       `json:"deletions"`
+	ChangedFiles   int       `json:"changed_files"`
+	MergeableState string    `json:"mergeable_state"` // clean when every merge requirement is met
+	AutoMerge      *struct{} `json:"auto_merge"`      // null unless the PR is set to merge when ready
+	Head           struct {
+		SHA  string        `json:"sha"`
+		Repo *prRepository `json:"repo"` // null when the fork was deleted
+	} `json:"head"`
+	Base struct {
+		Ref  string        `json:"ref"`
+		Repo *prRepository `json:"repo"`
+	} `json:"base"`
+}
+
+// prRepository is the head or base repository of a pull request
+type prRepository struct {
+	FullName string `json:"full_name"`
+	Owner    struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+// baseRepository returns the owner/repo the pull request targets, where its checks are
+// reported whatever repository the head commit lives in, or repo when not in the payload
+func (pr pullRequest) baseRepository(repo string) string {
+	if pr.Base.Repo != nil && pr.Base.Repo.FullName != "" {
+		return pr.Base.Repo.FullName
+	}
+	return repo
+}
+
+// fork reports whether the head of the pull request lives in another repository than its
+// base, and the owner of that fork. The owner is empty when the fork was deleted.
+func (pr pullRequest) fork() (string, bool) {
+	if pr.Head.Repo == nil {
+		return "", pr.Base.Repo != nil
+	}
+	if pr.Base.Repo == nil || pr.Head.Repo.FullName == pr.Base.Repo.FullName {
+		return "", false
 	}
+	return pr.Head.Repo.Owner.Login, true
+}
+
+// getPullRequest retrieves the payload of a pull request of repo
+func (p *Provider) getPullRequest(ctx context.Context, repo string, prNumber int) (pullRequest, error) {
+	var pr pullRequest
+
+	if !p.IsConfigured() {
+		return pr, fmt.Errorf("GitHub provider not configured")
+	}
+
+	if repo == "" || prNumber == 0 {
+		return pr, fmt.Errorf("repository and PR number are required")
+	}
+
+	prURL := fmt.Sprintf("%s/repos/%s/pulls/%d", p.baseURL, repo, prNumber)
+	if err := p.makeRequest(ctx, prURL, &pr); err != nil {
+		return pr, fmt.Errorf("failed to get PR details: %w", err)
+	}
+	return pr, nil
+}
 
-	if err := p.makeRequest(ctx, prURL, &prData); err != nil {
-		return ciStatus, fmt.Errorf("failed to get PR details: %w", err)
+// GetPRCIStatus retrieves CI status for a specific pull request
+func (p *Provider) GetPRCIStatus(ctx context.Context, repo string, prNumber int) (CIStatus, error) {
+	// Get PR details first to get the head SHA
+	pr, err := p.getPullRequest(ctx, repo, prNumber)
+	if err != nil {
+		return CIStatus{}, err
 	}
+	return p.getCheckRuns(ctx, pr.baseRepository(repo), pr.Head.SHA)
+}
+
+// getCheckRuns retrieves the CI status of a commit from the check runs reported on repo
+func (p *Provider) getCheckRuns(ctx context.Context, repo, sha string) (CIStatus, error) {
+	var ciStatus CIStatus
 
 	// Get check runs for the commit
-	checksURL := fmt.Sprintf("https://api.github.com/repos/%s/commits/%s/check-runs", repo, prData.Head.SHA)
+	checksURL := fmt.Sprintf("%s/repos/%s/commits/%s/check-runs", p.baseURL, repo, sha)
 
 	var checksResult struct {
 		TotalCount int `json:"total_count"`
@@ -569,7 +848,7 @@ func (p *Provider) GetPRCIStatus(ctx context.Context, repo string, prNumber int)
 	}
 
 	if err := p.makeRequest(ctx, checksURL, &checksResult); err != nil {
-		return ciStatus, fmt.Errorf("failed to get check runs: %w", err)
+		return ciStatus, fmt.Errorf("failed to get check runs: %w", p.forbidden(CapabilityCheckRuns, err))
 	}
 
 	ciStatus.TotalCount = checksResult.TotalCount
@@ -625,65 +904,56 @@ func (p *Provider) calculateOverallCIState(checks []struct {
 
 // GetPRDetails retrieves additional details about a pull request (additions, deletions, changed files)
 func (p *Provider) GetPRDetails(ctx context.Context, repo string, prNumber int) (PRDetails, error) {
-	var details PRDetails
-
-	if !p.IsConfigured() {
-		return details, fmt.Errorf("GitHub provider not configured")
-	}
-
-	if repo == "" || prNumber == 0 {
-		return details, fmt.Errorf("repository and PR number are required")
-	}
-
-	prURL := fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d", repo, prNumber)
-
-	var prData struct {
-		Additions    int `json:"additions"`
-		Deletions    int `json:"deletions"`
-		ChangedFiles int `json:"changed_files"`
+	pr, err := p.getPullRequest(ctx, repo, prNumber)
+	if err != nil {
+		return PRDetails{}, err
 	}
+	return newPRDetails(pr), nil
+}
 
-	if err := p.makeRequest(ctx, prURL, &prData); err != nil {
-		return details, fmt.Errorf("failed to get PR details: %w", err)
+// newPRDetails returns the details of a pull request payload
+func newPRDetails(pr pullRequest) PRDetails {
+	forkOwner, fork := pr.fork()
+	return PRDetails{
+		Additions:    pr.Additions,
+		Deletions:    pr.Deletions,
+		ChangedFiles: pr.ChangedFiles,
+		Base:         pr.Base.Ref,
+		Fork:         fork,
+		ForkOwner:    forkOwner,
 	}
-
-	details.Additions = prData.Additions
-	details.Deletions = prData.Deletions
-	details.ChangedFiles = prData.ChangedFiles
-
-	return details, nil
 }
 
 // CIStatus represents CI check status for a PR
-type CIStatus struct {
-	State      string     `json:"state"` // success, failure, pending
-	TotalCount int        `json:"total_count"`
-	Checks     []CheckRun `json:"checks"`
-}
+type CIStatus = provider.CIStatus
 
 // CheckRun represents a single CI check
-type CheckRun struct {
-	Name       string `json:"name"`
-	Status     string `json:"status"`     // completed, in_progress, queued
-	Conclusion string `json:"conclusion"` // success, failure, cancelled, etc.
-	URL        string `json:"url,omitempty"`
-}
+type CheckRun = provider.CheckRun
 
 // PRDetails represents additional PR information
 type PRDetails struct {
-	Additions    int `json:"additions"`
-	Deletions    int `json:"deletions"`
-	ChangedFiles int `json:"changed_files"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	ChangedFiles int    `json:"changed_files"`
+	Base         string `json:"base"`                 // Target branch of the PR
+	Fork         bool   `json:"fork,omitempty"`       // The head branch lives in a fork of the base repository
+	ForkOwner    string `json:"fork_owner,omitempty"` // Owner of the fork, empty when it was deleted
 }
 
 // TodoItem represents a single todo item (avoiding import cycles)
 type TodoItem struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	URL         string    `json:"url,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Tags        []string  `json:"tags,omitempty"`
-	Number      int       `json:"number,omitempty"`     // PR number
-	Repository  string    `json:"repository,omitempty"` // Repository full name
+	ID            string    `json:"id"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	URL           string    `json:"url,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Tags          []string  `json:"tags,omitempty"`
+	Number        int       `json:"number,omitempty"`         // PR number
+	Repository    string    `json:"repository,omitempty"`     // Repository full name
+	Milestone     string    `json:"milestone,omitempty"`      // Milestone title
+	ProjectStatus string    `json:"project_status,omitempty"` // ProjectsV2 "Status" field value
+	ReadyToMerge  bool      `json:"ready_to_merge,omitempty"` // Open PR of the user approved and mergeable, see IsReadyToMerge
+
+	Details    map[string]string    `json:"details,omitempty"`    // Structured facts by activity.Detail* key
+	Provenance *provider.Provenance `json:"provenance,omitempty"` // Search that listed the item
 }
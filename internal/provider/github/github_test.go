@@ -2,6 +2,10 @@ package github
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -105,6 +109,34 @@ func TestProvider_GetActivities(t *testing.T) {
 	}
 }
 
+func TestProvider_GetActivities_Warnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search/commits":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		case "/search/issues":
+			_, _ = fmt.Fprint(w, `{"total_count": 0, "items": []}`)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "token", Enabled: true})
+	p.baseURL = server.URL
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	if _, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The failing commit search is reported, the pull requests are still fetched
+	warnings := p.Warnings()
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "Failed to fetch commits: ") {
+		t.Errorf("Expected a warning for the commits, got %v", warnings)
+	}
+}
+
 func TestProvider_GetActivities_NotConfigured(t *testing.T) {
 	config := provider.Config{
 		Username: "",
@@ -329,6 +361,7 @@ func TestProvider_GetTeamReviewRequests(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := NewProvider(tt.config)
+			p.teamCache = nil
 
 			requests, err := p.GetTeamReviewRequests(context.Background())
 
@@ -498,3 +531,219 @@ func TestProvider_GetPRDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestProvider_GetOpenPRs_Milestone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items": [
+			{"number": 1, "title": "With milestone", "html_url": "https://github.com/owner/repo/pull/1",
+			 "updated_at": "2024-01-01T10:00:00Z", "milestone": {"title": "v1.2"}},
+			{"number": 2, "title": "Without milestone", "html_url": "https://github.com/owner/repo/pull/2",
+			 "updated_at": "2024-01-01T09:00:00Z", "milestone": null}
+		]}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+	p.baseURL = server.URL
+
+	todos, err := p.GetOpenPRs(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 todos, got %d", len(todos))
+	}
+
+	if todos[0].Milestone != "v1.2" {
+		t.Errorf("Expected milestone 'v1.2', got '%s'", todos[0].Milestone)
+	}
+
+	if todos[1].Milestone != "" {
+		t.Errorf("Expected empty milestone, got '%s'", todos[1].Milestone)
+	}
+
+	if todos[0].Repository != "owner/repo" {
+		t.Errorf("Expected repository 'owner/repo', got '%s'", todos[0].Repository)
+	}
+}
+
+func TestProvider_GetOpenPRs_Labels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items": [
+			{"number": 1, "title": "Fix outage", "html_url": "https://github.com/owner/repo/pull/1",
+			 "updated_at": "2024-01-01T10:00:00Z", "labels": [{"name": "P0"}, {"name": "bug"}]}
+		]}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+	p.baseURL = server.URL
+
+	todos, err := p.GetOpenPRs(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos) != 1 {
+		t.Fatalf("Expected 1 todo, got %d", len(todos))
+	}
+	if got := strings.Join(todos[0].Tags, ","); got != "owner/repo,open,P0,bug" {
+		t.Errorf("Expected the labels after the repository and state tags, got '%s'", got)
+	}
+}
+
+func TestProvider_GetPRProjectStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "status present",
+			response: `{"data": {"repository": {"pullRequest": {"projectItems": {"nodes": [{"fieldValueByName": {"name": "In Review"}}]}}}}}`,
+			expected: "In Review",
+		},
+		{
+			name:     "several projects",
+			response: `{"data": {"repository": {"pullRequest": {"projectItems": {"nodes": [{"project": {"title": "Platform"}, "fieldValueByName": {"name": "In Review"}}, {"project": {"title": "Backlog"}, "fieldValueByName": null}, {"project": {"title": "Roadmap"}, "fieldValueByName": {"name": "Todo"}}]}}}}}`,
+			expected: "In Review (Platform), Todo (Roadmap)",
+		},
+		{
+			name:     "not in any project",
+			response: `{"data": {"repository": {"pullRequest": {"projectItems": {"nodes": []}}}}}`,
+			expected: "",
+		},
+		{
+			name:     "graphql error",
+			response: `{"errors": [{"message": "insufficient scopes"}]}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+			p.baseURL = server.URL
+
+			status, err := p.GetPRProjectStatus(context.Background(), "owner/repo", 1)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if status != tt.expected {
+				t.Errorf("Expected status '%s', got '%s'", tt.expected, status)
+			}
+		})
+	}
+}
+
+func TestProvider_GetPRDetails_Base(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/pulls/123" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"additions":10,"deletions":2,"changed_files":3,"base":{"ref":"release/1.2"}}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+	p.baseURL = server.URL
+
+	details, err := p.GetPRDetails(context.Background(), "owner/repo", 123)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if details.Base != "release/1.2" {
+		t.Errorf("Expected base 'release/1.2', got '%s'", details.Base)
+	}
+	if details.ChangedFiles != 3 {
+		t.Errorf("Expected 3 changed files, got %d", details.ChangedFiles)
+	}
+}
+
+func TestProvider_Search_SkipsMalformedItems(t *testing.T) {
+	// The middle item has a repository of the wrong type, as returned for some deleted repositories
+	issuesResponse := `{"items": [
+		{"number": 1, "title": "First", "html_url": "https://github.com/owner/repo/pull/1",
+		 "created_at": "2024-01-15T10:00:00Z", "updated_at": "2024-01-15T10:00:00Z",
+		 "repository_url": "https://api.github.com/repos/owner/repo"},
+		{"number": 2, "title": "Corrupt", "repository": "deleted", "updated_at": "2024-01-15T09:00:00Z"},
+		{"number": 3, "title": "Third", "html_url": "https://github.com/owner/repo/pull/3",
+		 "created_at": "2024-01-15T08:00:00Z", "updated_at": "2024-01-15T08:00:00Z"}
+	]}`
+	commitsResponse := `{"items": [
+		{"sha": "abc", "commit": {"message": "First", "committer": {"date": "2024-01-15T10:00:00Z"}}},
+		{"sha": "def", "commit": "corrupt"},
+		{"sha": "ghi", "commit": {"message": "Third", "committer": {"date": "2024-01-15T08:00:00Z"}}}
+	]}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/search/commits") {
+			_, _ = w.Write([]byte(commitsResponse))
+			return
+		}
+		_, _ = w.Write([]byte(issuesResponse))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+	p.baseURL = server.URL
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name  string
+		fetch func() (int, error)
+	}{
+		{"commits", func() (int, error) {
+			activities, err := p.getCommits(context.Background(), from, to)
+			return len(activities), err
+		}},
+		{"pull requests", func() (int, error) {
+			activities, err := p.getPullRequests(context.Background(), from, to)
+			return len(activities), err
+		}},
+		{"open PRs", func() (int, error) {
+			todos, err := p.GetOpenPRs(context.Background())
+			return len(todos), err
+		}},
+		{"pending reviews", func() (int, error) {
+			todos, err := p.GetPendingReviews(context.Background())
+			return len(todos), err
+		}},
+		{"user review requests", func() (int, error) {
+			todos, err := p.GetUserReviewRequests(context.Background())
+			return len(todos), err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := tt.fetch()
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if count != 2 {
+				t.Errorf("Expected the 2 valid items to survive, got %d", count)
+			}
+		})
+	}
+}
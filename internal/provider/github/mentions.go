@@ -0,0 +1,90 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+// Ensure the provider can be used as a mention source
+var _ provider.MentionSource = (*Provider)(nil)
+
+// GetMentions retrieves notifications where the user or one of their teams was mentioned
+func (p *Provider) GetMentions(ctx context.Context, since string) ([]provider.TodoItem, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("GitHub provider not configured")
+	}
+
+	sinceTime, err := provider.ParseSinceDuration(since)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationsURL := fmt.Sprintf("%s/notifications?all=true&participating=true&per_page=50&since=%s",
+		p.baseURL, url.QueryEscape(sinceTime.UTC().Format(time.RFC3339)))
+
+	var notifications []struct {
+		ID        string    `json:"id"`
+		Reason    string    `json:"reason"`
+		UpdatedAt time.Time `json:"updated_at"`
+		Subject   struct {
+			Title string `json:"title"`
+			URL   string `json:"url"`
+			Type  string `json:"type"`
+		} `json:"subject"`
+		Repository struct {
+			Name     string `json:"name"`
+			FullName string `json:"full_name"`
+			HTMLURL  string `json:"html_url"`
+		} `json:"repository"`
+	}
+
+	if err := p.makeRequest(ctx, notificationsURL, &notifications); err != nil {
+		if err = p.forbidden(CapabilityNotifications, err); IsForbidden(err) {
+			// Fine-grained tokens can't read notifications, mentions are left out
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var mentions []provider.TodoItem
+	for _, n := range notifications {
+		if n.Reason != "mention" && n.Reason != "team_mention" {
+			continue
+		}
+
+		htmlURL := subjectHTMLURL(n.Subject.URL)
+		if htmlURL == "" {
+			htmlURL = n.Repository.HTMLURL
+		}
+
+		mentions = append(mentions, provider.TodoItem{
+			ID:          fmt.Sprintf("github-notification-%s", n.ID),
+			Title:       n.Subject.Title,
+			Description: fmt.Sprintf("Mentioned in %s %s", n.Repository.FullName, n.Subject.Type),
+			URL:         htmlURL,
+			UpdatedAt:   n.UpdatedAt,
+			Tags:        []string{n.Repository.Name, n.Reason},
+			Details:     activity.NewDetails(activity.DetailRepo, n.Repository.FullName),
+		})
+	}
+
+	return mentions, nil
+}
+
+// subjectHTMLURL converts a notification subject API URL to its web URL
+// e.g., https://api.github.com/repos/owner/repo/pulls/12 -> https://github.com/owner/repo/pull/12
+func subjectHTMLURL(apiURL string) string {
+	_, path, found := strings.Cut(apiURL, "/repos/")
+	if !found {
+		return ""
+	}
+
+	path = strings.Replace(path, "/pulls/", "/pull/", 1)
+	return "https://github.com/" + path
+}
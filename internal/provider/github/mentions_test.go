@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+func TestSubjectHTMLURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://api.github.com/repos/owner/repo/pulls/12", "https://github.com/owner/repo/pull/12"},
+		{"https://api.github.com/repos/owner/repo/issues/7", "https://github.com/owner/repo/issues/7"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if result := subjectHTMLURL(tt.input); result != tt.expected {
+			t.Errorf("Expected '%s' for '%s', got '%s'", tt.expected, tt.input, result)
+		}
+	}
+}
+
+func TestProvider_GetMentions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/notifications" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("participating") != "true" {
+			t.Errorf("Expected participating=true, got '%s'", r.URL.Query().Get("participating"))
+		}
+		if r.URL.Query().Get("since") == "" {
+			t.Error("Expected since parameter to be set")
+		}
+		_, _ = fmt.Fprint(w, `[
+			{"id":"1","reason":"mention","updated_at":"2024-01-15T10:00:00Z",
+			 "subject":{"title":"Fix parser","url":"https://api.github.com/repos/owner/repo/pulls/12","type":"PullRequest"},
+			 "repository":{"name":"repo","full_name":"owner/repo","html_url":"https://github.com/owner/repo"}},
+			{"id":"2","reason":"review_requested","updated_at":"2024-01-15T11:00:00Z",
+			 "subject":{"title":"Other","url":"https://api.github.com/repos/owner/repo/pulls/13","type":"PullRequest"},
+			 "repository":{"name":"repo","full_name":"owner/repo","html_url":"https://github.com/owner/repo"}},
+			{"id":"3","reason":"team_mention","updated_at":"2024-01-15T12:00:00Z",
+			 "subject":{"title":"Discussion","url":"","type":"Discussion"},
+			 "repository":{"name":"repo","full_name":"owner/repo","html_url":"https://github.com/owner/repo"}}
+		]`)
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "token", Enabled: true})
+	p.baseURL = server.URL
+
+	mentions, err := p.GetMentions(context.Background(), "3d")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(mentions) != 2 {
+		t.Fatalf("Expected 2 mentions, got %d", len(mentions))
+	}
+
+	if mentions[0].ID != "github-notification-1" {
+		t.Errorf("Expected ID 'github-notification-1', got '%s'", mentions[0].ID)
+	}
+	if mentions[0].URL != "https://github.com/owner/repo/pull/12" {
+		t.Errorf("Expected pull request URL, got '%s'", mentions[0].URL)
+	}
+	if mentions[0].Description != "Mentioned in owner/repo PullRequest" {
+		t.Errorf("Expected description 'Mentioned in owner/repo PullRequest', got '%s'", mentions[0].Description)
+	}
+
+	// Subjects without an API URL fall back to the repository page
+	if mentions[1].URL != "https://github.com/owner/repo" {
+		t.Errorf("Expected repository URL, got '%s'", mentions[1].URL)
+	}
+	if mentions[1].Tags[1] != "team_mention" {
+		t.Errorf("Expected tag 'team_mention', got '%s'", mentions[1].Tags[1])
+	}
+}
+
+func TestProvider_GetMentions_InvalidSince(t *testing.T) {
+	p := NewProvider(provider.Config{Username: "testuser", Token: "token", Enabled: true})
+
+	if _, err := p.GetMentions(context.Background(), "soon"); err == nil {
+		t.Error("Expected error for invalid since value, got none")
+	}
+}
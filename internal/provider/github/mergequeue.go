@@ -0,0 +1,68 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// mergeQueueTag tags the review requests of pull requests waiting in a merge queue
+const mergeQueueTag = "in-merge-queue"
+
+// inMergeQueue reports whether a pull request of repo is queued to be merged. Merge queues
+// are only exposed by the GraphQL API: when it can't tell, a PR set to merge when ready with
+// every merge requirement met is considered queued, as it is about to be merged.
+func (p *Provider) inMergeQueue(ctx context.Context, repo string, number int, pr pullRequest) bool {
+	if !p.mergeQueueUnsupported.Load() {
+		queued, err := p.getMergeQueueState(ctx, repo, number)
+		if err == nil {
+			return queued
+		}
+	}
+	return pr.AutoMerge != nil && pr.MergeableState == "clean"
+}
+
+// getMergeQueueState retrieves whether a pull request is in the merge queue of repo via
+// GraphQL. Servers whose schema has no merge queues are remembered, so that they are only
+// asked once.
+func (p *Provider) getMergeQueueState(ctx context.Context, repo string, number int) (bool, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || number == 0 {
+		return false, fmt.Errorf("repository and PR number are required")
+	}
+
+	query := `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      isInMergeQueue
+    }
+  }
+}`
+
+	var result struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					IsInMergeQueue bool `json:"isInMergeQueue"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	variables := map[string]any{"owner": owner, "name": name, "number": number}
+	if err := p.makeGraphQLRequest(ctx, query, variables, &result); err != nil {
+		return false, err
+	}
+
+	if len(result.Errors) > 0 {
+		if strings.Contains(result.Errors[0].Message, "isInMergeQueue") {
+			p.mergeQueueUnsupported.Store(true)
+		}
+		return false, fmt.Errorf("GitHub GraphQL error: %s", result.Errors[0].Message)
+	}
+
+	return result.Data.Repository.PullRequest.IsInMergeQueue, nil
+}
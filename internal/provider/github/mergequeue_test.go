@@ -0,0 +1,125 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+func TestProvider_EnrichReview_MergeQueue(t *testing.T) {
+	tests := []struct {
+		name             string
+		pullRequest      string
+		graphQLStatus    int
+		graphQLResponse  string
+		expectedQueued   bool
+		expectedGraphQLs int // GraphQL requests for two PRs
+	}{
+		{
+			name:             "in merge queue",
+			pullRequest:      `{"head": {"sha": "abc"}, "mergeable_state": "blocked", "auto_merge": null}`,
+			graphQLResponse:  `{"data": {"repository": {"pullRequest": {"isInMergeQueue": true}}}}`,
+			expectedQueued:   true,
+			expectedGraphQLs: 2,
+		},
+		{
+			name:             "not queued with auto-merge",
+			pullRequest:      `{"head": {"sha": "abc"}, "mergeable_state": "clean", "auto_merge": {"merge_method": "squash"}}`,
+			graphQLResponse:  `{"data": {"repository": {"pullRequest": {"isInMergeQueue": false}}}}`,
+			expectedQueued:   false,
+			expectedGraphQLs: 2,
+		},
+		{
+			name:             "no merge queues, auto-merge ready",
+			pullRequest:      `{"head": {"sha": "abc"}, "mergeable_state": "clean", "auto_merge": {"merge_method": "merge"}}`,
+			graphQLResponse:  `{"errors": [{"message": "Field 'isInMergeQueue' doesn't exist on type 'PullRequest'"}]}`,
+			expectedQueued:   true,
+			expectedGraphQLs: 1,
+		},
+		{
+			name:             "no merge queues, auto-merge waiting for reviews",
+			pullRequest:      `{"head": {"sha": "abc"}, "mergeable_state": "blocked", "auto_merge": {"merge_method": "merge"}}`,
+			graphQLResponse:  `{"errors": [{"message": "Field 'isInMergeQueue' doesn't exist on type 'PullRequest'"}]}`,
+			expectedQueued:   false,
+			expectedGraphQLs: 1,
+		},
+		{
+			name:             "no merge queues, no auto-merge",
+			pullRequest:      `{"head": {"sha": "abc"}, "mergeable_state": "clean", "auto_merge": null}`,
+			graphQLResponse:  `{"errors": [{"message": "Field 'isInMergeQueue' doesn't exist on type 'PullRequest'"}]}`,
+			expectedQueued:   false,
+			expectedGraphQLs: 1,
+		},
+		{
+			name:             "GraphQL unavailable",
+			pullRequest:      `{"head": {"sha": "abc"}, "mergeable_state": "clean", "auto_merge": {"merge_method": "merge"}}`,
+			graphQLStatus:    http.StatusBadGateway,
+			expectedQueued:   true,
+			expectedGraphQLs: 2, // Asked again, as the failure may be temporary
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graphQLs := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/repos/acme/api/pulls/7", "/repos/acme/api/pulls/8":
+					_, _ = w.Write([]byte(tt.pullRequest))
+				case "/repos/acme/api/commits/abc/check-runs":
+					_, _ = w.Write([]byte(`{"total_count": 0, "check_runs": []}`))
+				case "/graphql":
+					graphQLs++
+					var body struct {
+						Variables map[string]any `json:"variables"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("Failed to decode GraphQL request: %v", err)
+					}
+					if body.Variables["owner"] != "acme" || body.Variables["name"] != "api" {
+						t.Errorf("Expected the acme/api repository, got %v", body.Variables)
+					}
+					if tt.graphQLStatus != 0 {
+						w.WriteHeader(tt.graphQLStatus)
+						return
+					}
+					_, _ = w.Write([]byte(tt.graphQLResponse))
+				default:
+					t.Errorf("Unexpected request to %s", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+			p.baseURL = server.URL
+
+			for _, number := range []int{7, 8} {
+				item := provider.ReviewItem{
+					TodoItem:   provider.TodoItem{ID: "github-review", Tags: []string{"acme/api"}},
+					Repository: "acme/api",
+					Number:     number,
+				}
+				enriched, err := p.EnrichReview(context.Background(), item)
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				if enriched.InMergeQueue != tt.expectedQueued {
+					t.Errorf("Expected in merge queue %v, got %v", tt.expectedQueued, enriched.InMergeQueue)
+				}
+				if slices.Contains(enriched.Tags, mergeQueueTag) != tt.expectedQueued {
+					t.Errorf("Expected tag %s only when queued, got %v", mergeQueueTag, enriched.Tags)
+				}
+			}
+			if graphQLs != tt.expectedGraphQLs {
+				t.Errorf("Expected %d GraphQL requests, got %d", tt.expectedGraphQLs, graphQLs)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ReadyToMergeTag tags the open pull requests of the user that are approved and can be merged
+const ReadyToMergeTag = "ready-to-merge"
+
+// prReview is a review of a pull request
+type prReview struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State string `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED or PENDING
+}
+
+// mergeReadiness is what tells whether a pull request can be merged
+type mergeReadiness struct {
+	mergeableState    string // Mergeable state of the pull request, e.g. clean or dirty
+	approvals         int    // Reviewers whose latest review approves the pull request
+	changesRequested  bool   // A reviewer's latest review requests changes
+	requiredApprovals int    // Approving reviews required by the branch protection, -1 when it can't be read
+	ciState           string // Overall state of the check runs, empty without checks
+}
+
+// ready reports whether a pull request is approved and can be merged: it has at least one
+// approval and the approving reviews its branch protection requires when it can be read, no
+// change is requested, its checks passed and GitHub reports no conflict, missing requirement
+// or outdated branch. A mergeable state GitHub hasn't computed yet is ignored.
+func (r mergeReadiness) ready() bool {
+	switch r.mergeableState {
+	case "dirty", "blocked", "behind", "unstable", "draft":
+		return false
+	}
+	if r.changesRequested || r.approvals < max(1, r.requiredApprovals) {
+		return false
+	}
+	return r.ciState == "success" || r.ciState == ""
+}
+
+// countApprovals returns how many reviewers approve a pull request, and whether one requests
+// changes, from the latest review of each reviewer that approved, requested changes or had
+// their review dismissed. Comments leave a reviewer's verdict unchanged.
+func countApprovals(reviews []prReview) (int, bool) {
+	verdicts := make(map[string]string)
+	for _, review := range reviews {
+		switch review.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			verdicts[review.User.Login] = review.State
+		}
+	}
+
+	approvals, changesRequested := 0, false
+	for _, verdict := range verdicts {
+		switch verdict {
+		case "APPROVED":
+			approvals++
+		case "CHANGES_REQUESTED":
+			changesRequested = true
+		}
+	}
+	return approvals, changesRequested
+}
+
+// IsReadyToMerge reports whether an open pull request of repo is approved and can be merged,
+// see mergeReadiness.ready. The required reviews are read from the protection of the base
+// branch, which needs admin access to the repository: without it, one approval is enough.
+func (p *Provider) IsReadyToMerge(ctx context.Context, repo string, prNumber int) (bool, error) {
+	pr, err := p.getPullRequest(ctx, repo, prNumber)
+	if err != nil {
+		return false, err
+	}
+
+	var reviews []prReview
+	reviewsURL := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews?per_page=100", p.baseURL, repo, prNumber)
+	if err := p.makeRequest(ctx, reviewsURL, &reviews); err != nil {
+		return false, fmt.Errorf("failed to get PR reviews: %w", err)
+	}
+	readiness := mergeReadiness{mergeableState: pr.MergeableState, requiredApprovals: -1}
+	readiness.approvals, readiness.changesRequested = countApprovals(reviews)
+	// No need to look further at a PR that can't be ready whatever its branch and checks
+	if !readiness.ready() {
+		return false, nil
+	}
+
+	base := pr.baseRepository(repo)
+	if required, err := p.getRequiredApprovals(ctx, base, pr.Base.Ref); err == nil {
+		readiness.requiredApprovals = required
+	}
+
+	ciStatus, err := p.getCheckRuns(ctx, base, pr.Head.SHA)
+	if err != nil {
+		return false, err
+	}
+	readiness.ciState = ciStatus.State
+	return readiness.ready(), nil
+}
+
+// getRequiredApprovals returns the approving reviews the protection of a branch of repo
+// requires. GitHub answers 404 both for branches without protection and for tokens that can't
+// read it, so any error leaves the requirement unknown.
+func (p *Provider) getRequiredApprovals(ctx context.Context, repo, branch string) (int, error) {
+	if branch == "" {
+		return 0, fmt.Errorf("branch is required")
+	}
+
+	var protection struct {
+		RequiredApprovingReviewCount int `json:"required_approving_review_count"`
+	}
+	protectionURL := fmt.Sprintf("%s/repos/%s/branches/%s/protection/required_pull_request_reviews", p.baseURL, repo, url.PathEscape(branch))
+	if err := p.makeRequest(ctx, protectionURL, &protection); err != nil {
+		return 0, fmt.Errorf("failed to get branch protection: %w", err)
+	}
+	return protection.RequiredApprovingReviewCount, nil
+}
@@ -0,0 +1,153 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+func TestCountApprovals(t *testing.T) {
+	review := func(login, state string) prReview {
+		var r prReview
+		r.User.Login = login
+		r.State = state
+		return r
+	}
+
+	tests := []struct {
+		name             string
+		reviews          []prReview
+		approvals        int
+		changesRequested bool
+	}{
+		{"no review", nil, 0, false},
+		{"approvals", []prReview{review("ana", "APPROVED"), review("bo", "APPROVED")}, 2, false},
+		{"approval repeated by a reviewer", []prReview{review("ana", "APPROVED"), review("ana", "APPROVED")}, 1, false},
+		{"comment after an approval", []prReview{review("ana", "APPROVED"), review("ana", "COMMENTED")}, 1, false},
+		{"approval after requested changes", []prReview{review("ana", "CHANGES_REQUESTED"), review("ana", "APPROVED")}, 1, false},
+		{"requested changes after an approval", []prReview{review("ana", "APPROVED"), review("ana", "CHANGES_REQUESTED")}, 0, true},
+		{"dismissed approval", []prReview{review("ana", "APPROVED"), review("ana", "DISMISSED")}, 0, false},
+		{"pending review", []prReview{review("ana", "PENDING")}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			approvals, changesRequested := countApprovals(tt.reviews)
+			if approvals != tt.approvals || changesRequested != tt.changesRequested {
+				t.Errorf("Expected %d approvals and changes requested %v, got %d and %v", tt.approvals, tt.changesRequested, approvals, changesRequested)
+			}
+		})
+	}
+}
+
+func TestMergeReadiness_Ready(t *testing.T) {
+	approved := mergeReadiness{approvals: 1, requiredApprovals: -1, ciState: "success"}
+	with := func(change func(r *mergeReadiness)) mergeReadiness {
+		r := approved
+		change(&r)
+		return r
+	}
+
+	type testCase struct {
+		name      string
+		readiness mergeReadiness
+		expected  bool
+	}
+	tests := []testCase{
+		{"approved with passing checks", approved, true},
+		{"without checks", with(func(r *mergeReadiness) { r.ciState = "" }), true},
+		{"failing checks", with(func(r *mergeReadiness) { r.ciState = "failure" }), false},
+		{"pending checks", with(func(r *mergeReadiness) { r.ciState = "pending" }), false},
+		{"not approved", with(func(r *mergeReadiness) { r.approvals = 0 }), false},
+		{"changes requested", with(func(r *mergeReadiness) { r.approvals, r.changesRequested = 2, true }), false},
+		{"required approvals met", with(func(r *mergeReadiness) { r.approvals, r.requiredApprovals = 2, 2 }), true},
+		{"required approvals missing", with(func(r *mergeReadiness) { r.requiredApprovals = 2 }), false},
+		{"no required approval", with(func(r *mergeReadiness) { r.approvals, r.requiredApprovals = 0, 0 }), false},
+	}
+	for _, state := range []string{"clean", "has_hooks", "unknown", ""} {
+		tests = append(tests, testCase{"mergeable state " + state, with(func(r *mergeReadiness) { r.mergeableState = state }), true})
+	}
+	for _, state := range []string{"dirty", "blocked", "behind", "unstable", "draft"} {
+		tests = append(tests, testCase{"mergeable state " + state, with(func(r *mergeReadiness) { r.mergeableState = state }), false})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ready := tt.readiness.ready(); ready != tt.expected {
+				t.Errorf("Expected ready %v, got %v", tt.expected, ready)
+			}
+		})
+	}
+}
+
+func TestProvider_IsReadyToMerge(t *testing.T) {
+	const approvedByAna = `[{"user": {"login": "ana"}, "state": "COMMENTED"}, {"user": {"login": "ana"}, "state": "APPROVED"}]`
+	const passingChecks = `{"total_count": 1, "check_runs": [{"name": "test", "status": "completed", "conclusion": "success"}]}`
+
+	tests := []struct {
+		name           string
+		mergeableState string
+		reviews        string
+		protection     string // Empty when the token can't read the branch protection
+		checks         string
+		expected       bool
+	}{
+		{name: "clean", mergeableState: "clean", reviews: approvedByAna, checks: passingChecks, expected: true},
+		{name: "clean with hooks", mergeableState: "has_hooks", reviews: approvedByAna, checks: passingChecks, expected: true},
+		{name: "mergeability not computed yet", mergeableState: "unknown", reviews: approvedByAna, checks: passingChecks, expected: true},
+		{name: "conflicts", mergeableState: "dirty", reviews: approvedByAna, checks: passingChecks},
+		{name: "missing requirements", mergeableState: "blocked", reviews: approvedByAna, checks: passingChecks},
+		{name: "out of date", mergeableState: "behind", reviews: approvedByAna, checks: passingChecks},
+		{name: "failing checks", mergeableState: "unstable", reviews: approvedByAna, checks: `{"total_count": 1, "check_runs": [{"name": "test", "status": "completed", "conclusion": "failure"}]}`},
+		{name: "draft", mergeableState: "draft", reviews: approvedByAna, checks: passingChecks},
+		{name: "not approved", mergeableState: "clean", reviews: `[{"user": {"login": "ana"}, "state": "COMMENTED"}]`, checks: passingChecks},
+		{name: "required approvals met", mergeableState: "clean", reviews: approvedByAna, protection: `{"required_approving_review_count": 1}`, checks: passingChecks, expected: true},
+		{name: "required approvals missing", mergeableState: "clean", reviews: approvedByAna, protection: `{"required_approving_review_count": 2}`, checks: passingChecks},
+		{name: "pending checks", mergeableState: "clean", reviews: approvedByAna, checks: `{"total_count": 1, "check_runs": [{"name": "test", "status": "in_progress"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/repos/acme/api/pulls/7":
+					_ = json.NewEncoder(w).Encode(map[string]any{
+						"mergeable_state": tt.mergeableState,
+						"head":            map[string]any{"sha": "abc"},
+						"base":            map[string]any{"ref": "main", "repo": map[string]any{"full_name": "acme/api"}},
+					})
+				case "/repos/acme/api/pulls/7/reviews":
+					_, _ = w.Write([]byte(tt.reviews))
+				case "/repos/acme/api/branches/main/protection/required_pull_request_reviews":
+					if tt.protection == "" {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					_, _ = w.Write([]byte(tt.protection))
+				case "/repos/acme/api/commits/abc/check-runs":
+					_, _ = w.Write([]byte(tt.checks))
+				default:
+					t.Errorf("Unexpected request to %s", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+			p.baseURL = server.URL
+
+			ready, err := p.IsReadyToMerge(context.Background(), "acme/api", 7)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if ready != tt.expected {
+				t.Errorf("Expected ready %v, got %v", tt.expected, ready)
+			}
+		})
+	}
+}
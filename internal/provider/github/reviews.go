@@ -0,0 +1,107 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"daily/internal/provider"
+)
+
+// Ensure the provider can be used as an enriched review source
+var (
+	_ provider.ReviewSource   = (*Provider)(nil)
+	_ provider.ReviewEnricher = (*Provider)(nil)
+)
+
+// GetReviewRequests retrieves the pull requests awaiting review from the user, then from their teams
+func (p *Provider) GetReviewRequests(ctx context.Context) ([]provider.ReviewItem, error) {
+	userRequests, err := p.GetUserReviewRequests(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user review requests: %w", err)
+	}
+
+	teamRequests, err := p.GetTeamReviewRequests(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team review requests: %w", err)
+	}
+
+	items := make([]provider.ReviewItem, 0, len(userRequests)+len(teamRequests))
+	for _, pr := range userRequests {
+		items = append(items, newReviewItem(pr, provider.ReviewRequestUser))
+	}
+	for _, pr := range teamRequests {
+		items = append(items, newReviewItem(pr, provider.ReviewRequestTeam))
+	}
+
+	return items, nil
+}
+
+// EnrichReview adds the CI status, change size, base branch and merge queue state of a pull
+// request. The checks are read from the base repository, where they are reported for fork
+// PRs too.
+// Details that could be fetched are kept when the check runs request fails.
+func (p *Provider) EnrichReview(ctx context.Context, item provider.ReviewItem) (provider.ReviewItem, error) {
+	// Items found without a repository can't be fetched, they are listed as is
+	if item.Repository == "" {
+		return item, nil
+	}
+
+	pr, err := p.getPullRequest(ctx, item.Repository, item.Number)
+	if err != nil {
+		return item, err
+	}
+
+	details := newPRDetails(pr)
+	item.PRDetails = provider.PRDetails{
+		Additions:    details.Additions,
+		Deletions:    details.Deletions,
+		ChangedFiles: details.ChangedFiles,
+	}
+	item.Base = details.Base
+	if details.Fork {
+		item.Tags = append(slices.Clone(item.Tags), "fork")
+		item.Description += forkDescription(details.ForkOwner)
+	}
+
+	if p.inMergeQueue(ctx, pr.baseRepository(item.Repository), item.Number, pr) {
+		item.InMergeQueue = true
+		item.Tags = append(slices.Clone(item.Tags), mergeQueueTag)
+	}
+
+	ciStatus, err := p.getCheckRuns(ctx, pr.baseRepository(item.Repository), pr.Head.SHA)
+	if err != nil {
+		return item, err
+	}
+	item.CIStatus = ciStatus
+	return item, nil
+}
+
+// forkDescription describes the fork a PR comes from, appended to its description
+func forkDescription(owner string) string {
+	if owner == "" {
+		return " · from a deleted fork"
+	}
+	return fmt.Sprintf(" · from a fork by %s", owner)
+}
+
+// newReviewItem converts a pull request into a review item without CI status or PR details
+func newReviewItem(pr TodoItem, requestType string) provider.ReviewItem {
+	return provider.ReviewItem{
+		TodoItem: provider.TodoItem{
+			ID:          pr.ID,
+			Title:       pr.Title,
+			Description: pr.Description,
+			URL:         pr.URL,
+			UpdatedAt:   pr.UpdatedAt,
+			Tags:        pr.Tags,
+			Details:     pr.Details,
+			Provenance:  pr.Provenance,
+		},
+		Platform:    "github",
+		RequestType: requestType,
+		Repository:  pr.Repository,
+		Number:      pr.Number,
+		Milestone:   pr.Milestone,
+	}
+}
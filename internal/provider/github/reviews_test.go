@@ -0,0 +1,267 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"daily/internal/activity"
+	"daily/internal/cache"
+	"daily/internal/provider"
+)
+
+func TestProvider_GetReviewRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/user/teams":
+			_, _ = w.Write([]byte(`[{"slug": "platform", "organization": {"login": "acme"}}]`))
+		case strings.Contains(r.URL.Query().Get("q"), "team-review-requested:acme/platform"):
+			_, _ = w.Write([]byte(`{"items": [{"number": 2, "title": "Team PR", "html_url": "https://github.com/owner/repo/pull/2",
+				"updated_at": "2024-01-15T09:00:00Z", "repository_url": "https://api.github.com/repos/owner/repo"}]}`))
+		case strings.Contains(r.URL.Query().Get("q"), "review-requested:testuser"):
+			_, _ = w.Write([]byte(`{"items": [{"number": 1, "title": "User PR", "html_url": "https://github.com/owner/repo/pull/1",
+				"updated_at": "2024-01-15T10:00:00Z", "repository_url": "https://api.github.com/repos/owner/repo",
+				"milestone": {"title": "v1.0"}}]}`))
+		default:
+			t.Errorf("Unexpected request: %s?%s", r.URL.Path, r.URL.RawQuery)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+	p.baseURL = server.URL
+	p.teamCache = cache.NewTTLStoreAt(filepath.Join(t.TempDir(), "teams.json"))
+
+	items, err := p.GetReviewRequests(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 review requests, got %d", len(items))
+	}
+
+	user, team := items[0], items[1]
+	if user.Title != "User PR" || user.RequestType != provider.ReviewRequestUser {
+		t.Errorf("Expected user request first, got %+v", user)
+	}
+	if team.Title != "Team PR" || team.RequestType != provider.ReviewRequestTeam {
+		t.Errorf("Expected team request second, got %+v", team)
+	}
+	if user.Platform != "github" || user.Repository != "owner/repo" || user.Number != 1 {
+		t.Errorf("Expected platform, repository and number to be set, got %+v", user)
+	}
+	if user.Milestone != "v1.0" {
+		t.Errorf("Expected milestone 'v1.0', got '%s'", user.Milestone)
+	}
+	if repo := team.Details[activity.DetailRepo]; repo != "owner/repo" {
+		t.Errorf("Expected repo detail 'owner/repo', got '%s'", repo)
+	}
+}
+
+func TestProvider_GetReviewRequests_Unconfigured(t *testing.T) {
+	p := NewProvider(provider.Config{})
+
+	_, err := p.GetReviewRequests(context.Background())
+	if err == nil || err.Error() != "failed to get user review requests: GitHub provider not configured" {
+		t.Errorf("Expected unconfigured error, got: %v", err)
+	}
+}
+
+func TestProvider_EnrichReview(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/pulls/123":
+			_, _ = w.Write([]byte(`{"head": {"sha": "abc"}, "additions": 10, "deletions": 2, "changed_files": 3, "base": {"ref": "release/1.2"}}`))
+		case "/repos/owner/repo/commits/abc/check-runs":
+			_, _ = w.Write([]byte(`{"total_count": 1, "check_runs": [{"name": "CI", "status": "completed", "conclusion": "success"}]}`))
+		case "/graphql":
+			_, _ = w.Write([]byte(`{"data": {"repository": {"pullRequest": {"isInMergeQueue": false}}}}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+	p.baseURL = server.URL
+
+	item := provider.ReviewItem{
+		TodoItem:    provider.TodoItem{ID: "github-review-owner/repo-123", Title: "Backport fix"},
+		Platform:    "github",
+		RequestType: provider.ReviewRequestUser,
+		Repository:  "owner/repo",
+		Number:      123,
+	}
+
+	enriched, err := p.EnrichReview(context.Background(), item)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if enriched.Title != item.Title || enriched.RequestType != item.RequestType {
+		t.Errorf("Expected the item fields to be kept, got %+v", enriched)
+	}
+	if enriched.CIStatus.State != "success" || enriched.CIStatus.TotalCount != 1 {
+		t.Errorf("Expected successful CI status with 1 check, got %+v", enriched.CIStatus)
+	}
+	if enriched.PRDetails.ChangedFiles != 3 || enriched.PRDetails.Additions != 10 {
+		t.Errorf("Expected PR details to be set, got %+v", enriched.PRDetails)
+	}
+	if enriched.Base != "release/1.2" {
+		t.Errorf("Expected base 'release/1.2', got '%s'", enriched.Base)
+	}
+	if enriched.InMergeQueue {
+		t.Error("Expected the PR not to be in the merge queue")
+	}
+
+	// Errors keep the original item
+	enriched, err = NewProvider(provider.Config{}).EnrichReview(context.Background(), item)
+	if err == nil {
+		t.Error("Expected error for unconfigured provider, got nil")
+	}
+	if enriched.ID != item.ID {
+		t.Errorf("Expected ID %s, got %s", item.ID, enriched.ID)
+	}
+}
+
+func TestProvider_EnrichReview_Forks(t *testing.T) {
+	tests := []struct {
+		name                string
+		payload             string
+		expectedChecksPath  string
+		expectedTags        []string
+		expectedDescription string
+	}{
+		{
+			name: "same repository",
+			payload: `{"head": {"sha": "abc", "repo": {"full_name": "acme/api", "owner": {"login": "acme"}}},
+				"base": {"ref": "main", "repo": {"full_name": "acme/api", "owner": {"login": "acme"}}}}`,
+			expectedChecksPath:  "/repos/acme/api/commits/abc/check-runs",
+			expectedTags:        []string{"acme/api", "review-requested"},
+			expectedDescription: "Review requested in acme/api (by bob)",
+		},
+		{
+			name: "fork",
+			payload: `{"head": {"sha": "def", "repo": {"full_name": "bob/api", "owner": {"login": "bob"}}},
+				"base": {"ref": "main", "repo": {"full_name": "acme/api", "owner": {"login": "acme"}}}}`,
+			expectedChecksPath:  "/repos/acme/api/commits/def/check-runs",
+			expectedTags:        []string{"acme/api", "review-requested", "fork"},
+			expectedDescription: "Review requested in acme/api (by bob) · from a fork by bob",
+		},
+		{
+			name: "deleted fork",
+			payload: `{"head": {"sha": "ghi", "repo": null},
+				"base": {"ref": "main", "repo": {"full_name": "acme/api", "owner": {"login": "acme"}}}}`,
+			expectedChecksPath:  "/repos/acme/api/commits/ghi/check-runs",
+			expectedTags:        []string{"acme/api", "review-requested", "fork"},
+			expectedDescription: "Review requested in acme/api (by bob) · from a deleted fork",
+		},
+		{
+			name: "renamed base repository",
+			payload: `{"head": {"sha": "jkl", "repo": {"full_name": "acme/api-v2", "owner": {"login": "acme"}}},
+				"base": {"ref": "main", "repo": {"full_name": "acme/api-v2", "owner": {"login": "acme"}}}}`,
+			expectedChecksPath:  "/repos/acme/api-v2/commits/jkl/check-runs",
+			expectedTags:        []string{"acme/api", "review-requested"},
+			expectedDescription: "Review requested in acme/api (by bob)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/repos/acme/api/pulls/7":
+					_, _ = w.Write([]byte(tt.payload))
+				case tt.expectedChecksPath:
+					_, _ = w.Write([]byte(`{"total_count": 1, "check_runs": [{"name": "CI", "status": "completed", "conclusion": "success"}]}`))
+				case "/graphql":
+					_, _ = w.Write([]byte(`{"data": {"repository": {"pullRequest": {"isInMergeQueue": false}}}}`))
+				default:
+					t.Errorf("Unexpected request to %s", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+			p.baseURL = server.URL
+
+			tags := []string{"acme/api", "review-requested"}
+			item := provider.ReviewItem{
+				TodoItem:   provider.TodoItem{ID: "github-review-7", Description: "Review requested in acme/api (by bob)", Tags: tags},
+				Repository: "acme/api",
+				Number:     7,
+			}
+
+			enriched, err := p.EnrichReview(context.Background(), item)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if enriched.CIStatus.State != "success" {
+				t.Errorf("Expected the checks of the base repository, got %+v", enriched.CIStatus)
+			}
+			if strings.Join(enriched.Tags, ",") != strings.Join(tt.expectedTags, ",") {
+				t.Errorf("Expected tags %v, got %v", tt.expectedTags, enriched.Tags)
+			}
+			if enriched.Description != tt.expectedDescription {
+				t.Errorf("Expected description %q, got %q", tt.expectedDescription, enriched.Description)
+			}
+			if len(tags) != 2 {
+				t.Errorf("Expected the tags of the original item to be left alone, got %v", tags)
+			}
+		})
+	}
+}
+
+func TestProvider_ReviewRequests_UnknownRepository(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/search/issues" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"items": [
+			{"number": 7, "title": "From URL", "repository_url": "https://api.github.com/repos/acme/api", "user": {"login": "bob"}},
+			{"number": 8, "title": "Unknown", "user": {"login": "bob"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+	p.baseURL = server.URL
+
+	todos, err := p.GetUserReviewRequests(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 review requests, got %d", len(todos))
+	}
+
+	if todos[0].Repository != "acme/api" || todos[0].Description != "Review requested in acme/api (by bob)" {
+		t.Errorf("Expected the repository of the API URL, got %+v", todos[0])
+	}
+	if todos[1].Repository != "" || todos[1].Description != "Review requested in an unknown repository (by bob)" {
+		t.Errorf("Expected an unknown repository, got %+v", todos[1])
+	}
+	if strings.Join(todos[1].Tags, ",") != "review-requested" {
+		t.Errorf("Expected no repository tag, got %v", todos[1].Tags)
+	}
+
+	// Nothing is fetched for items without a repository
+	enriched, err := p.EnrichReview(context.Background(), newReviewItem(todos[1], provider.ReviewRequestUser))
+	if err != nil {
+		t.Errorf("Expected no error for an unknown repository, got: %v", err)
+	}
+	if enriched.Title != "Unknown" {
+		t.Errorf("Expected the item to be kept, got %+v", enriched)
+	}
+}
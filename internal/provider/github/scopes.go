@@ -0,0 +1,244 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Capabilities of the GitHub provider, each backed by API calls needing their own permissions
+const (
+	CapabilityCommitSearch  = "commit_search"
+	CapabilityPRSearch      = "pr_search"
+	CapabilityTeams         = "teams"
+	CapabilityNotifications = "notifications"
+	CapabilityCheckRuns     = "check_runs"
+	CapabilityWorkflowRuns  = "workflow_runs"
+)
+
+// Permission is the least privilege a token needs for a capability
+type Permission struct {
+	Capability  string `json:"capability"`
+	Usage       string `json:"usage"`        // What daily uses the capability for
+	Classic     string `json:"classic"`      // Scope of a classic personal access token
+	FineGrained string `json:"fine_grained"` // Permission of a fine-grained personal access token
+	Degraded    string `json:"degraded"`     // What is left out when the token lacks the permission
+}
+
+// Permissions maps every capability to the permissions it needs, in the order they are listed
+var Permissions = []Permission{
+	{
+		Capability:  CapabilityCommitSearch,
+		Usage:       "Commits of the activity summary (sum)",
+		Classic:     "repo for private repositories, none for public ones",
+		FineGrained: "Contents: read",
+		Degraded:    "Commits are left out of the summary",
+	},
+	{
+		Capability:  CapabilityPRSearch,
+		Usage:       "Pull requests of the summary, open PRs and review requests (sum, todo, reviews)",
+		Classic:     "repo for private repositories, none for public ones",
+		FineGrained: "Pull requests: read",
+		Degraded:    "Pull requests and review requests are left out",
+	},
+	{
+		Capability:  CapabilityTeams,
+		Usage:       "Teams searched for review requests, unless review_teams is set (todo, reviews)",
+		Classic:     "read:org",
+		FineGrained: "Members: read (organization)",
+		Degraded:    "Only the review requests of the user are listed, set review_teams to search teams",
+	},
+	{
+		Capability:  CapabilityNotifications,
+		Usage:       "Mentions (todo, mentions)",
+		Classic:     "notifications",
+		FineGrained: "not supported, use a classic token",
+		Degraded:    "Mentions are left out",
+	},
+	{
+		Capability:  CapabilityCheckRuns,
+		Usage:       "CI status of review requests and PR readiness (reviews, todo)",
+		Classic:     "repo for private repositories, none for public ones",
+		FineGrained: "Checks: read",
+		Degraded:    "The CI status of pull requests is unknown",
+	},
+	{
+		Capability:  CapabilityWorkflowRuns,
+		Usage:       "Workflow runs of the activity summary (sum)",
+		Classic:     "repo for private repositories, none for public ones",
+		FineGrained: "Actions: read",
+		Degraded:    "Workflow runs are left out of the summary",
+	},
+}
+
+// PermissionOf returns the permissions needed by a capability
+func PermissionOf(capability string) (Permission, bool) {
+	for _, permission := range Permissions {
+		if permission.Capability == capability {
+			return permission, true
+		}
+	}
+	return Permission{}, false
+}
+
+// apiError is returned when the GitHub API answers with an unexpected status
+type apiError struct {
+	StatusCode  int
+	GraphQL     bool
+	RateLimited bool // GitHub also answers 403 when the rate limit is exceeded
+}
+
+func (e *apiError) Error() string {
+	if e.GraphQL {
+		return fmt.Sprintf("GitHub GraphQL request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("GitHub API request failed with status %d", e.StatusCode)
+}
+
+// newAPIError describes the unexpected status of resp
+func newAPIError(resp *http.Response, graphQL bool) *apiError {
+	return &apiError{
+		StatusCode:  resp.StatusCode,
+		GraphQL:     graphQL,
+		RateLimited: resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != "",
+	}
+}
+
+// CapabilityError is returned when the token isn't allowed to use a capability
+type CapabilityError struct {
+	Capability string
+	Err        error
+}
+
+func (e *CapabilityError) Error() string {
+	return fmt.Sprintf("token lacks the %s permission: %v", e.Capability, e.Err)
+}
+
+func (e *CapabilityError) Unwrap() error {
+	return e.Err
+}
+
+// Permission returns the permissions the token lacks
+func (e *CapabilityError) Permission() Permission {
+	permission, _ := PermissionOf(e.Capability)
+	return permission
+}
+
+// classify returns err as a CapabilityError of capability when the API refused the call to the
+// token, other errors (including rate limits) are returned as is
+func classify(capability string, err error) error {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden && !apiErr.RateLimited {
+		return &CapabilityError{Capability: capability, Err: err}
+	}
+	return err
+}
+
+// IsForbidden reports whether err is due to a capability the token lacks
+func IsForbidden(err error) bool {
+	var capErr *CapabilityError
+	return errors.As(err, &capErr)
+}
+
+// forbidden classifies err for capability and records the capability as degraded when the token
+// lacks it, so that the callers able to go on without it can
+func (p *Provider) forbidden(capability string, err error) error {
+	err = classify(capability, err)
+	var capErr *CapabilityError
+	if errors.As(err, &capErr) {
+		p.degradedMu.Lock()
+		defer p.degradedMu.Unlock()
+		if p.degraded == nil {
+			p.degraded = make(map[string]*CapabilityError)
+		}
+		p.degraded[capability] = capErr
+	}
+	return err
+}
+
+// Degraded returns the capabilities refused to the token since the provider was created, in the
+// order of Permissions
+func (p *Provider) Degraded() []*CapabilityError {
+	p.degradedMu.Lock()
+	defer p.degradedMu.Unlock()
+
+	var degraded []*CapabilityError
+	for _, permission := range Permissions {
+		if capErr, ok := p.degraded[permission.Capability]; ok {
+			degraded = append(degraded, capErr)
+		}
+	}
+	return degraded
+}
+
+// Capability check statuses
+const (
+	CheckOK        = "ok"
+	CheckForbidden = "forbidden"
+	CheckFailed    = "failed"
+	CheckSkipped   = "skipped"
+)
+
+// CapabilityCheck is the outcome of probing a capability with the configured token
+type CapabilityCheck struct {
+	Capability string `json:"capability"`
+	Status     string `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// capabilityProbe is the request made to check a capability
+type capabilityProbe struct {
+	url     string
+	headers map[string]string
+}
+
+// CheckCapabilities probes each capability with the cheapest call it makes. Check and workflow
+// runs are probed on the first configured repository and skipped without one.
+func (p *Provider) CheckCapabilities(ctx context.Context) ([]CapabilityCheck, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("GitHub provider not configured")
+	}
+
+	user := url.QueryEscape(p.config.Username)
+	probes := map[string]capabilityProbe{
+		CapabilityCommitSearch: {
+			url:     fmt.Sprintf("%s/search/commits?q=%s&per_page=1", p.baseURL, url.QueryEscape("author:"+p.config.Username)),
+			headers: map[string]string{"Accept": "application/vnd.github.cloak-preview+json"},
+		},
+		CapabilityPRSearch:      {url: fmt.Sprintf("%s/search/issues?q=%s&per_page=1", p.baseURL, url.QueryEscape("type:pr author:"+p.config.Username))},
+		CapabilityTeams:         {url: p.baseURL + "/user/teams?per_page=1"},
+		CapabilityNotifications: {url: p.baseURL + "/notifications?per_page=1"},
+	}
+	if len(p.config.Repos) > 0 {
+		repo := p.config.Repos[0]
+		probes[CapabilityCheckRuns] = capabilityProbe{url: fmt.Sprintf("%s/repos/%s/commits/HEAD/check-runs?per_page=1", p.baseURL, repo)}
+		probes[CapabilityWorkflowRuns] = capabilityProbe{url: fmt.Sprintf("%s/repos/%s/actions/runs?actor=%s&per_page=1", p.baseURL, repo, user)}
+	}
+
+	checks := make([]CapabilityCheck, len(Permissions))
+	for i, permission := range Permissions {
+		checks[i] = CapabilityCheck{Capability: permission.Capability}
+		probe, ok := probes[permission.Capability]
+		if !ok {
+			checks[i].Status = CheckSkipped
+			checks[i].Detail = "no repository configured in repos"
+			continue
+		}
+
+		var result any
+		err := p.forbidden(permission.Capability, p.makeRequestWithHeaders(ctx, probe.url, probe.headers, &result))
+		switch {
+		case err == nil:
+			checks[i].Status = CheckOK
+		case IsForbidden(err):
+			checks[i].Status = CheckForbidden
+			checks[i].Detail = permission.Degraded
+		default:
+			checks[i].Status = CheckFailed
+			checks[i].Detail = err.Error()
+		}
+	}
+	return checks, nil
+}
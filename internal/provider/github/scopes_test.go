@@ -0,0 +1,237 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestPermissions(t *testing.T) {
+	capabilities := []string{CapabilityCommitSearch, CapabilityPRSearch, CapabilityTeams,
+		CapabilityNotifications, CapabilityCheckRuns, CapabilityWorkflowRuns}
+
+	if len(Permissions) != len(capabilities) {
+		t.Errorf("Expected %d permissions, got %d", len(capabilities), len(Permissions))
+	}
+	for _, capability := range capabilities {
+		permission, ok := PermissionOf(capability)
+		if !ok {
+			t.Errorf("Expected a permission for %s", capability)
+			continue
+		}
+		if permission.Usage == "" || permission.Classic == "" || permission.FineGrained == "" || permission.Degraded == "" {
+			t.Errorf("Expected every field of the %s permission to be set, got %+v", capability, permission)
+		}
+	}
+	if _, ok := PermissionOf("unknown"); ok {
+		t.Error("Expected no permission for an unknown capability")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		forbidden bool
+	}{
+		{"forbidden", &apiError{StatusCode: http.StatusForbidden}, true},
+		{"forbidden GraphQL", &apiError{StatusCode: http.StatusForbidden, GraphQL: true}, true},
+		{"wrapped forbidden", fmt.Errorf("failed to get check runs: %w", &apiError{StatusCode: http.StatusForbidden}), true},
+		{"rate limited", &apiError{StatusCode: http.StatusForbidden, RateLimited: true}, false},
+		{"unauthorized", &apiError{StatusCode: http.StatusUnauthorized}, false},
+		{"not found", &apiError{StatusCode: http.StatusNotFound}, false},
+		{"other error", errors.New("connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classify(CapabilityTeams, tt.err)
+			if IsForbidden(err) != tt.forbidden {
+				t.Errorf("Expected forbidden %v, got %v (%v)", tt.forbidden, IsForbidden(err), err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected the original error to be wrapped, got %v", err)
+			}
+		})
+	}
+
+	err := classify(CapabilityTeams, &apiError{StatusCode: http.StatusForbidden})
+	if !strings.Contains(err.Error(), "teams") || !strings.Contains(err.Error(), "status 403") {
+		t.Errorf("Expected the capability and the status in the message, got %q", err.Error())
+	}
+}
+
+func TestNewAPIError_RateLimited(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      http.Header
+		rateLimited bool
+	}{
+		{"missing permission", http.Header{"X-Ratelimit-Remaining": {"4999"}}, false},
+		{"primary rate limit", http.Header{"X-Ratelimit-Remaining": {"0"}}, true},
+		{"secondary rate limit", http.Header{"Retry-After": {"60"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newAPIError(&http.Response{StatusCode: http.StatusForbidden, Header: tt.header}, false)
+			if err.RateLimited != tt.rateLimited {
+				t.Errorf("Expected rate limited %v, got %v", tt.rateLimited, err.RateLimited)
+			}
+			if err.Error() != "GitHub API request failed with status 403" {
+				t.Errorf("Expected the status in the message, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestProvider_DegradesForbiddenCapabilities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/search/commits", "/user/teams", "/notifications", "/repos/acme/app/actions/runs":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "Resource not accessible by personal access token"}`))
+		case "/search/issues":
+			_, _ = w.Write([]byte(`{"items": [{"number": 1, "title": "Add login",
+				"html_url": "https://github.com/acme/app/pull/1", "repository_url": "https://api.github.com/repos/acme/app",
+				"created_at": "2024-01-15T10:00:00Z", "updated_at": "2024-01-15T10:00:00Z"}]}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "token", Enabled: true})
+	p.baseURL = server.URL
+	p.teamCache = nil
+	ctx := context.Background()
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(ctx, from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error without commit search, got: %v", err)
+	}
+	if len(activities) != 1 {
+		t.Errorf("Expected the pull request without the commits and workflow runs, got %d activities", len(activities))
+	}
+
+	mentions, err := p.GetMentions(ctx, "1d")
+	if err != nil || len(mentions) != 0 {
+		t.Errorf("Expected no mentions and no error without notifications, got %d and %v", len(mentions), err)
+	}
+
+	if _, err := p.GetTeamReviewRequests(ctx); err != nil {
+		t.Errorf("Expected no error without teams, got: %v", err)
+	}
+
+	var degraded []string
+	for _, capErr := range p.Degraded() {
+		degraded = append(degraded, capErr.Capability)
+	}
+	expected := []string{CapabilityCommitSearch, CapabilityTeams, CapabilityNotifications, CapabilityWorkflowRuns}
+	if strings.Join(degraded, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected degraded capabilities %v, got %v", expected, degraded)
+	}
+}
+
+func TestProvider_DegradedIgnoresRateLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Username: "testuser", Token: "token", Enabled: true})
+	p.baseURL = server.URL
+
+	if _, err := p.GetMentions(context.Background(), "1d"); err == nil || IsForbidden(err) {
+		t.Errorf("Expected the rate limit to be reported as an error, got: %v", err)
+	}
+	if degraded := p.Degraded(); len(degraded) != 0 {
+		t.Errorf("Expected no degraded capability on rate limits, got %v", degraded)
+	}
+}
+
+func TestProvider_CheckCapabilities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/search/commits", "/search/issues":
+			_, _ = w.Write([]byte(`{"items": []}`))
+		case "/user/teams":
+			w.WriteHeader(http.StatusForbidden)
+		case "/notifications":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/repos/acme/app/commits/HEAD/check-runs":
+			_, _ = w.Write([]byte(`{"total_count": 0, "check_runs": []}`))
+		case "/repos/acme/app/actions/runs":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		repos    []string
+		expected map[string]string
+	}{
+		{
+			name:  "with a repository",
+			repos: []string{"acme/app"},
+			expected: map[string]string{
+				CapabilityCommitSearch:  CheckOK,
+				CapabilityPRSearch:      CheckOK,
+				CapabilityTeams:         CheckForbidden,
+				CapabilityNotifications: CheckFailed,
+				CapabilityCheckRuns:     CheckOK,
+				CapabilityWorkflowRuns:  CheckForbidden,
+			},
+		},
+		{
+			name: "without repository",
+			expected: map[string]string{
+				CapabilityCommitSearch:  CheckOK,
+				CapabilityPRSearch:      CheckOK,
+				CapabilityTeams:         CheckForbidden,
+				CapabilityNotifications: CheckFailed,
+				CapabilityCheckRuns:     CheckSkipped,
+				CapabilityWorkflowRuns:  CheckSkipped,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(provider.Config{Username: "testuser", Token: "token", Enabled: true, Repos: tt.repos})
+			p.baseURL = server.URL
+
+			checks, err := p.CheckCapabilities(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(checks) != len(Permissions) {
+				t.Fatalf("Expected one check per capability, got %d", len(checks))
+			}
+			for _, check := range checks {
+				if check.Status != tt.expected[check.Capability] {
+					t.Errorf("Expected %s to be %s, got %s (%s)", check.Capability, tt.expected[check.Capability], check.Status, check.Detail)
+				}
+				if check.Status == CheckForbidden && check.Detail == "" {
+					t.Errorf("Expected %s to describe what is left out", check.Capability)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"daily/internal/metrics"
+)
+
+// teamsCacheTTL is how long team memberships are cached, they rarely change
+const teamsCacheTTL = 24 * time.Hour
+
+// TeamSelection lists the teams (org/slug) searched for review requests, and the ones left out
+type TeamSelection struct {
+	Queried []string
+	Skipped []string
+}
+
+// ReviewTeams returns the teams searched for review requests: the review_teams allowlist when
+// configured, without looking up the user's teams, or else every team of the user.
+// Teams listed in exclude_teams are skipped either way.
+func (p *Provider) ReviewTeams(ctx context.Context) (TeamSelection, error) {
+	teams := p.config.ReviewTeams
+	if len(teams) == 0 {
+		var err error
+		teams, err = p.getUserTeams(ctx)
+		if IsForbidden(err) {
+			// Without read:org, only the review requests of the user are searched
+			return TeamSelection{}, nil
+		}
+		if err != nil {
+			return TeamSelection{}, fmt.Errorf("failed to get user teams: %w", err)
+		}
+	}
+
+	var selection TeamSelection
+	for _, team := range teams {
+		if containsTeam(p.config.ExcludeTeams, team) {
+			selection.Skipped = append(selection.Skipped, team)
+		} else {
+			selection.Queried = append(selection.Queried, team)
+		}
+	}
+	return selection, nil
+}
+
+// containsTeam reports whether teams lists team, ignoring case as GitHub does
+func containsTeam(teams []string, team string) bool {
+	for _, candidate := range teams {
+		if strings.EqualFold(candidate, team) {
+			return true
+		}
+	}
+	return false
+}
+
+// getUserTeams retrieves the teams that the user belongs to, cached for a day
+func (p *Provider) getUserTeams(ctx context.Context) ([]string, error) {
+	// Tokens of the same user can target several GitHub instances
+	cacheKey := fmt.Sprintf("%s@%s", p.config.Username, p.baseURL)
+
+	var teamNames []string
+	if p.teamCache != nil {
+		found, err := p.teamCache.Get(cacheKey, teamsCacheTTL, &teamNames)
+		metrics.FromContext(ctx).Cache("github_teams", err == nil && found)
+		if err == nil && found {
+			return teamNames, nil
+		}
+	}
+
+	var teams []struct {
+		Slug         string `json:"slug"`
+		Organization struct {
+			Login string `json:"login"`
+		} `json:"organization"`
+	}
+
+	if err := p.makeRequest(ctx, p.baseURL+"/user/teams", &teams); err != nil {
+		return nil, p.forbidden(CapabilityTeams, err)
+	}
+
+	for _, team := range teams {
+		// Format as "org/team"
+		teamNames = append(teamNames, fmt.Sprintf("%s/%s", team.Organization.Login, team.Slug))
+	}
+
+	if p.teamCache != nil {
+		// A cache that can't be written only costs a request on the next run
+		_ = p.teamCache.Set(cacheKey, teamNames)
+	}
+
+	return teamNames, nil
+}
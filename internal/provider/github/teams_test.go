@@ -0,0 +1,185 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"daily/internal/cache"
+	"daily/internal/provider"
+)
+
+// newTeamsServer serves the teams of the user and team review searches, counting the membership requests
+func newTeamsServer(t *testing.T, teamRequests *int, searched *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/user/teams":
+			*teamRequests++
+			_, _ = w.Write([]byte(`[{"slug": "platform", "organization": {"login": "acme"}},
+				{"slug": "everyone", "organization": {"login": "acme"}},
+				{"slug": "design", "organization": {"login": "acme"}}]`))
+		case "/search/issues":
+			query := r.URL.Query().Get("q")
+			team, _, _ := strings.Cut(strings.TrimPrefix(query, "team-review-requested:"), " ")
+			*searched = append(*searched, team)
+			_, _ = w.Write([]byte(`{"items": []}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestProvider_ReviewTeams(t *testing.T) {
+	tests := []struct {
+		name                 string
+		reviewTeams          []string
+		excludeTeams         []string
+		expectedQueried      []string
+		expectedSkipped      []string
+		expectedTeamRequests int
+	}{
+		{
+			name:                 "all teams of the user",
+			expectedQueried:      []string{"acme/platform", "acme/everyone", "acme/design"},
+			expectedTeamRequests: 1,
+		},
+		{
+			name:                 "excluded teams",
+			excludeTeams:         []string{"ACME/Everyone"},
+			expectedQueried:      []string{"acme/platform", "acme/design"},
+			expectedSkipped:      []string{"acme/everyone"},
+			expectedTeamRequests: 1,
+		},
+		{
+			name:            "allowlist skips the membership request",
+			reviewTeams:     []string{"acme/platform", "acme/api"},
+			excludeTeams:    []string{"acme/api"},
+			expectedQueried: []string{"acme/platform"},
+			expectedSkipped: []string{"acme/api"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var teamRequests int
+			var searched []string
+			server := newTeamsServer(t, &teamRequests, &searched)
+			defer server.Close()
+
+			p := NewProvider(provider.Config{
+				Username:     "testuser",
+				Token:        "testtoken",
+				Enabled:      true,
+				ReviewTeams:  tt.reviewTeams,
+				ExcludeTeams: tt.excludeTeams,
+			})
+			p.baseURL = server.URL
+			p.teamCache = nil
+
+			selection, err := p.ReviewTeams(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if fmt.Sprint(selection.Queried) != fmt.Sprint(tt.expectedQueried) {
+				t.Errorf("Expected queried teams %v, got %v", tt.expectedQueried, selection.Queried)
+			}
+			if fmt.Sprint(selection.Skipped) != fmt.Sprint(tt.expectedSkipped) {
+				t.Errorf("Expected skipped teams %v, got %v", tt.expectedSkipped, selection.Skipped)
+			}
+
+			// Only the selected teams are searched
+			if _, err := p.GetTeamReviewRequests(context.Background()); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if fmt.Sprint(searched) != fmt.Sprint(tt.expectedQueried) {
+				t.Errorf("Expected searched teams %v, got %v", tt.expectedQueried, searched)
+			}
+			if teamRequests != 2*tt.expectedTeamRequests {
+				t.Errorf("Expected %d membership requests, got %d", 2*tt.expectedTeamRequests, teamRequests)
+			}
+		})
+	}
+}
+
+func TestProvider_GetTeamReviewRequests_Provenance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items": [{"title": "Add login", "html_url": "https://github.com/acme/api/pull/7", "number": 7,
+			"repository_url": "https://api.github.com/repos/acme/api", "updated_at": "2024-03-11T09:00:00Z"}]}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Username:    "testuser",
+		Token:       "testtoken",
+		Enabled:     true,
+		Filter:      "org:acme",
+		ReviewTeams: []string{"acme/platform"},
+	})
+	p.baseURL = server.URL
+	p.teamCache = nil
+
+	todos, err := p.GetTeamReviewRequests(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos) != 1 {
+		t.Fatalf("Expected 1 review request, got %d", len(todos))
+	}
+
+	provenance := todos[0].Provenance
+	if provenance == nil {
+		t.Fatal("Expected provenance, got nil")
+	}
+	if provenance.Provider != "github" || provenance.Team != "acme/platform" || provenance.Filter != "org:acme" {
+		t.Errorf("Expected the team and filter of the search, got %+v", provenance)
+	}
+	if !strings.Contains(provenance.Query, "team-review-requested:acme/platform") {
+		t.Errorf("Expected the team search query, got %q", provenance.Query)
+	}
+}
+
+func TestProvider_GetUserTeams_Cache(t *testing.T) {
+	var teamRequests int
+	var searched []string
+	server := newTeamsServer(t, &teamRequests, &searched)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "teams.json")
+
+	// Each run creates its own provider, the memberships are shared through the cache file
+	for run := 1; run <= 2; run++ {
+		p := NewProvider(provider.Config{Username: "testuser", Token: "testtoken", Enabled: true})
+		p.baseURL = server.URL
+		p.teamCache = cache.NewTTLStoreAt(path)
+
+		teams, err := p.getUserTeams(context.Background())
+		if err != nil {
+			t.Fatalf("Run %d: expected no error, got: %v", run, err)
+		}
+		if fmt.Sprint(teams) != "[acme/platform acme/everyone acme/design]" {
+			t.Errorf("Run %d: expected the teams of the user, got %v", run, teams)
+		}
+	}
+
+	if teamRequests != 1 {
+		t.Errorf("Expected a single membership request, got %d", teamRequests)
+	}
+
+	// Another user doesn't get the cached teams
+	p := NewProvider(provider.Config{Username: "otheruser", Token: "testtoken", Enabled: true})
+	p.baseURL = server.URL
+	p.teamCache = cache.NewTTLStoreAt(path)
+	if _, err := p.getUserTeams(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if teamRequests != 2 {
+		t.Errorf("Expected a membership request for another user, got %d requests", teamRequests)
+	}
+}
@@ -0,0 +1,367 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/metrics"
+	"daily/internal/provider"
+)
+
+// defaultURL is the GitLab instance used when none is configured
+const defaultURL = "https://gitlab.com"
+
+// defaultMaxResults is the number of items fetched per list when max_results isn't set
+const defaultMaxResults = 500
+
+// perPage is the page size of list requests, the largest GitLab allows
+const perPage = 100
+
+type Provider struct {
+	config provider.Config
+	client *http.Client
+
+	userOnce sync.Once
+	user     user // Authenticated user, see currentUser
+	userErr  error
+
+	projectsMu sync.Mutex
+	projects   map[int]project // Projects of the events, by ID
+
+	warnings []string // Parts of the last GetActivities that failed
+}
+
+func NewProvider(config provider.Config) *Provider {
+	return &Provider{
+		config: config,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: metrics.NewTransport("gitlab"),
+		},
+		projects: make(map[int]project),
+	}
+}
+
+func (p *Provider) Name() string {
+	return "gitlab"
+}
+
+func (p *Provider) IsConfigured() bool {
+	return p.config.Enabled && p.config.Token != ""
+}
+
+// ConfigSpec describes the configuration fields of the GitLab provider
+func (p *Provider) ConfigSpec() []provider.ConfigField {
+	return []provider.ConfigField{
+		{Name: "url", Description: "GitLab instance URL (default https://gitlab.com)"},
+		{Name: "token", Required: true, Secret: true, Description: "GitLab Personal Access Token with the read_api scope"},
+		{Name: "token_cmd", Description: "Command printing the token, overriding token, e.g. pass show gitlab/daily-pat"},
+		{Name: "groups", Description: "Groups (or group/subgroup paths) whose projects are reported, e.g. [\"acme/platform\"] (default every project)"},
+		{Name: "max_results", Description: "Maximum number of events, merge requests or issues fetched per list (default 500)"},
+	}
+}
+
+// siteURL returns the URL of the GitLab instance, without trailing slash
+func (p *Provider) siteURL() string {
+	if p.config.URL == "" {
+		return defaultURL
+	}
+	return strings.TrimRight(p.config.URL, "/")
+}
+
+// apiURL returns the URL of a REST API v4 endpoint, e.g. "/events"
+func (p *Provider) apiURL(path string, query url.Values) string {
+	apiURL := p.siteURL() + "/api/v4" + path
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+	return apiURL
+}
+
+// maxResults returns the number of items fetched per list
+func (p *Provider) maxResults() int {
+	if p.config.MaxResults > 0 {
+		return p.config.MaxResults
+	}
+	return defaultMaxResults
+}
+
+// inGroups reports whether a project (full path, e.g. "acme/platform/api") belongs to one of
+// the configured groups, or to any group when none is configured
+func (p *Provider) inGroups(projectPath string) bool {
+	if len(p.config.Groups) == 0 {
+		return true
+	}
+	for _, group := range p.config.Groups {
+		group = strings.Trim(group, "/")
+		if group != "" && strings.HasPrefix(projectPath, group+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// GetActivities retrieves the pushes, merge requests opened and merged, comments and
+// approvals of the user in the time range (for summary)
+func (p *Provider) GetActivities(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("GitLab provider not configured")
+	}
+
+	// The events API filters by day, exclusive on both ends, so the range is widened to whole
+	// days around it and the events outside are dropped below
+	query := url.Values{
+		"after":  {from.AddDate(0, 0, -1).Format("2006-01-02")},
+		"before": {to.AddDate(0, 0, 1).Format("2006-01-02")},
+		"sort":   {"asc"},
+	}
+	events, err := getList[event](ctx, p, p.apiURL("/events", query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitLab events: %w", err)
+	}
+
+	activities := make([]activity.Activity, 0, len(events))
+	p.warnings = nil
+	failed := make(map[int]bool) // Projects that couldn't be fetched, e.g. deleted since
+	for _, evt := range events {
+		if evt.CreatedAt.Before(from) || !evt.CreatedAt.Before(to) {
+			continue
+		}
+
+		proj, err := p.getProject(ctx, evt.ProjectID)
+		if err != nil {
+			// The events of the project are skipped, warning once per project
+			if !failed[evt.ProjectID] {
+				failed[evt.ProjectID] = true
+				p.warnings = append(p.warnings, fmt.Sprintf("Skipped the events of project %d: %v", evt.ProjectID, err))
+			}
+			continue
+		}
+		if !p.inGroups(proj.PathWithNamespace) {
+			continue
+		}
+
+		if act, ok := newEventActivity(evt, proj); ok {
+			activities = append(activities, act)
+		}
+	}
+
+	return activities, nil
+}
+
+// Warnings describes the projects whose events the last GetActivities skipped
+func (p *Provider) Warnings() []string {
+	return p.warnings
+}
+
+// event is an entry of the events API, the contributions of the user
+type event struct {
+	ID          int       `json:"id"`
+	ProjectID   int       `json:"project_id"`
+	ActionName  string    `json:"action_name"` // e.g. "pushed to", "opened", "accepted", "commented on", "approved"
+	TargetID    int       `json:"target_id"`
+	TargetIID   int       `json:"target_iid"`
+	TargetType  string    `json:"target_type"` // e.g. "MergeRequest", "Issue", "Note", "DiffNote"
+	TargetTitle string    `json:"target_title"`
+	CreatedAt   time.Time `json:"created_at"`
+	PushData    *struct {
+		CommitCount int    `json:"commit_count"`
+		Action      string `json:"action"`   // "pushed", "created" or "removed"
+		RefType     string `json:"ref_type"` // "branch" or "tag"
+		CommitTo    string `json:"commit_to"`
+		Ref         string `json:"ref"`
+		CommitTitle string `json:"commit_title"`
+	} `json:"push_data"`
+	Note *struct {
+		NoteableType string `json:"noteable_type"` // "MergeRequest", "Issue", "Commit"...
+		NoteableIID  int    `json:"noteable_iid"`
+	} `json:"note"`
+}
+
+// project is the part of a GitLab project the activities use
+type project struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"` // e.g. "acme/platform/api"
+	WebURL            string `json:"web_url"`
+}
+
+// newEventActivity converts an event into an activity, reporting false for the events that
+// aren't reported, e.g. branches deleted or issues closed
+func newEventActivity(evt event, proj project) (activity.Activity, bool) {
+	act := activity.Activity{
+		Platform:  "gitlab",
+		Timestamp: evt.CreatedAt,
+		Tags:      []string{proj.PathWithNamespace},
+		Details:   activity.NewDetails(activity.DetailRepo, proj.PathWithNamespace),
+	}
+
+	switch {
+	case strings.HasPrefix(evt.ActionName, "pushed") && evt.PushData != nil:
+		push := evt.PushData
+		if push.RefType != "branch" || push.CommitCount == 0 || push.CommitTo == "" {
+			return act, false
+		}
+		act.ID = fmt.Sprintf("gitlab-push-%d", evt.ID)
+		act.Type = activity.ActivityTypeCommit
+		act.Title = push.CommitTitle
+		act.Description = fmt.Sprintf("Pushed to %s in %s", push.Ref, proj.PathWithNamespace)
+		if push.CommitCount > 1 {
+			act.Description = fmt.Sprintf("Pushed %d commits to %s in %s", push.CommitCount, push.Ref, proj.PathWithNamespace)
+		}
+		act.URL = fmt.Sprintf("%s/-/commit/%s", proj.WebURL, push.CommitTo)
+
+	case evt.TargetType == "MergeRequest" && (evt.ActionName == "opened" || evt.ActionName == "accepted"):
+		state := "opened"
+		if evt.ActionName == "accepted" {
+			state = "merged"
+		}
+		act.ID = fmt.Sprintf("gitlab-mr-%d-%s", evt.TargetID, state)
+		act.Type = activity.ActivityTypePR
+		act.Title = evt.TargetTitle
+		act.Description = fmt.Sprintf("Merge request !%d %s in %s", evt.TargetIID, state, proj.PathWithNamespace)
+		act.URL = mergeRequestURL(proj.WebURL, evt.TargetIID)
+		act.Details = activity.NewDetails(activity.DetailRepo, proj.PathWithNamespace, activity.DetailState, state)
+
+	case evt.TargetType == "MergeRequest" && evt.ActionName == "approved":
+		act.ID = fmt.Sprintf("gitlab-approval-%d", evt.ID)
+		act.Type = activity.ActivityTypeReview
+		act.Title = evt.TargetTitle
+		act.Description = fmt.Sprintf("Approved merge request !%d in %s", evt.TargetIID, proj.PathWithNamespace)
+		act.URL = mergeRequestURL(proj.WebURL, evt.TargetIID)
+
+	case evt.ActionName == "commented on" && evt.Note != nil:
+		act.ID = fmt.Sprintf("gitlab-note-%d", evt.TargetID)
+		act.Type = activity.ActivityTypeComment
+		act.Title = evt.TargetTitle
+		switch evt.Note.NoteableType {
+		case "MergeRequest":
+			act.Description = fmt.Sprintf("Comment on merge request !%d in %s", evt.Note.NoteableIID, proj.PathWithNamespace)
+			act.URL = fmt.Sprintf("%s#note_%d", mergeRequestURL(proj.WebURL, evt.Note.NoteableIID), evt.TargetID)
+		case "Issue":
+			act.Description = fmt.Sprintf("Comment on issue #%d in %s", evt.Note.NoteableIID, proj.PathWithNamespace)
+			act.URL = fmt.Sprintf("%s/-/issues/%d#note_%d", proj.WebURL, evt.Note.NoteableIID, evt.TargetID)
+		default:
+			act.Description = fmt.Sprintf("Comment in %s", proj.PathWithNamespace)
+			act.URL = proj.WebURL
+		}
+
+	default:
+		return act, false
+	}
+
+	return act, true
+}
+
+// mergeRequestURL returns the page of a merge request of a project
+func mergeRequestURL(projectURL string, iid int) string {
+	return fmt.Sprintf("%s/-/merge_requests/%d", projectURL, iid)
+}
+
+// getProject returns a project, fetched once per provider
+func (p *Provider) getProject(ctx context.Context, id int) (project, error) {
+	p.projectsMu.Lock()
+	proj, ok := p.projects[id]
+	p.projectsMu.Unlock()
+	if ok {
+		return proj, nil
+	}
+
+	if err := p.get(ctx, p.apiURL("/projects/"+strconv.Itoa(id), nil), &proj, nil); err != nil {
+		return project{}, err
+	}
+
+	p.projectsMu.Lock()
+	p.projects[id] = proj
+	p.projectsMu.Unlock()
+	return proj, nil
+}
+
+// user is the authenticated GitLab user
+type user struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+// currentUser returns the user the token belongs to, fetched once per provider
+func (p *Provider) currentUser(ctx context.Context) (user, error) {
+	p.userOnce.Do(func() {
+		p.userErr = p.get(ctx, p.apiURL("/user", nil), &p.user, nil)
+	})
+	return p.user, p.userErr
+}
+
+// getList fetches the items of a list endpoint page by page, up to the max results
+func getList[T any](ctx context.Context, p *Provider, listURL string) ([]T, error) {
+	separator := "?"
+	if strings.Contains(listURL, "?") {
+		separator = "&"
+	}
+
+	var items []T
+	for page := "1"; page != "" && len(items) < p.maxResults(); {
+		var pageItems []T
+		header := make(http.Header)
+		pageURL := fmt.Sprintf("%s%sper_page=%d&page=%s", listURL, separator, perPage, page)
+		if err := p.get(ctx, pageURL, &pageItems, header); err != nil {
+			return nil, err
+		}
+		items = append(items, pageItems...)
+		page = header.Get("X-Next-Page")
+	}
+
+	if len(items) > p.maxResults() {
+		items = items[:p.maxResults()]
+	}
+	return items, nil
+}
+
+// get decodes the JSON response of a GET request into result, copying the response headers
+// to header when not nil
+func (p *Provider) get(ctx context.Context, getURL string, result any, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", getURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", p.config.Token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message any `json:"message"` // A string, or the invalid fields
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Message != nil {
+			return fmt.Errorf("GitLab API returned status %d: %v", resp.StatusCode, apiErr.Message)
+		}
+		return fmt.Errorf("GitLab API returned status %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	if header != nil {
+		for key, values := range resp.Header {
+			header[key] = values
+		}
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// provenance records the list a todo item was fetched from
+func (p *Provider) provenance(query string) *provider.Provenance {
+	return &provider.Provenance{
+		Provider:  "gitlab",
+		Query:     query,
+		FetchedAt: time.Now(),
+	}
+}
@@ -0,0 +1,249 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+func TestProvider_IsConfigured(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   provider.Config
+		expected bool
+	}{
+		{"enabled with token", provider.Config{Enabled: true, Token: "glpat-secret"}, true},
+		{"without token", provider.Config{Enabled: true}, false},
+		{"disabled", provider.Config{Token: "glpat-secret"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := NewProvider(tt.config).IsConfigured(); result != tt.expected {
+				t.Errorf("Expected %t, got %t", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestProvider_GetActivities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.Header.Get("PRIVATE-TOKEN"); token != "glpat-secret" {
+			t.Errorf("Expected the token in PRIVATE-TOKEN, got %q", token)
+		}
+		switch r.URL.Path {
+		case "/api/v4/events":
+			// Two pages, linked by X-Next-Page
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = w.Write([]byte(`[
+					{"id": 1004, "project_id": 77, "action_name": "commented on", "target_id": 9001, "target_iid": 9001, "target_type": "DiffNote", "target_title": "Migrate the billing jobs", "created_at": "2024-01-15T11:00:00Z",
+					 "note": {"id": 9001, "body": "Shouldn't this be retried?", "noteable_type": "MergeRequest", "noteable_iid": 7}},
+					{"id": 1005, "project_id": 77, "action_name": "approved", "target_id": 6601, "target_iid": 7, "target_type": "MergeRequest", "target_title": "Migrate the billing jobs", "created_at": "2024-01-15T11:30:00Z"},
+					{"id": 1006, "project_id": 42, "action_name": "accepted", "target_id": 5400, "target_iid": 11, "target_type": "MergeRequest", "target_title": "Fix the session timeout", "created_at": "2024-01-15T15:00:00Z"},
+					{"id": 1007, "project_id": 99, "action_name": "opened", "target_id": 7001, "target_iid": 3, "target_type": "MergeRequest", "target_title": "Update the dotfiles", "created_at": "2024-01-15T16:00:00Z"},
+					{"id": 1008, "project_id": 42, "action_name": "opened", "target_id": 5600, "target_iid": 13, "target_type": "MergeRequest", "target_title": "Tomorrow's work", "created_at": "2024-01-16T08:00:00Z"}
+				]`))
+				return
+			}
+			w.Header().Set("X-Next-Page", "2")
+			_, _ = w.Write([]byte(`[
+				{"id": 1001, "project_id": 42, "action_name": "pushed to", "created_at": "2024-01-15T09:30:00Z",
+				 "push_data": {"commit_count": 3, "action": "pushed", "ref_type": "branch", "commit_from": "0a1b2c3d", "commit_to": "4e5f6a7b", "ref": "feature/login", "commit_title": "Add the login form"}},
+				{"id": 1002, "project_id": 42, "action_name": "opened", "target_id": 5501, "target_iid": 12, "target_type": "MergeRequest", "target_title": "Add the login form", "created_at": "2024-01-15T10:00:00Z"},
+				{"id": 1003, "project_id": 42, "action_name": "deleted", "created_at": "2024-01-15T10:05:00Z",
+				 "push_data": {"commit_count": 0, "action": "removed", "ref_type": "branch", "commit_to": null, "ref": "old-branch"}}
+			]`))
+		case "/api/v4/projects/42":
+			_, _ = w.Write([]byte(`{"id": 42, "path_with_namespace": "acme/platform/api", "web_url": "https://gitlab.example.com/acme/platform/api"}`))
+		case "/api/v4/projects/77":
+			_, _ = w.Write([]byte(`{"id": 77, "path_with_namespace": "acme/billing", "web_url": "https://gitlab.example.com/acme/billing"}`))
+		case "/api/v4/projects/99":
+			_, _ = w.Write([]byte(`{"id": 99, "path_with_namespace": "jdoe/dotfiles", "web_url": "https://gitlab.example.com/jdoe/dotfiles"}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true})
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The deleted branch and the event of the next day are left out
+	expected := []struct {
+		id          string
+		actType     activity.ActivityType
+		title       string
+		description string
+		url         string
+	}{
+		{"gitlab-push-1001", activity.ActivityTypeCommit, "Add the login form",
+			"Pushed 3 commits to feature/login in acme/platform/api", "/acme/platform/api/-/commit/4e5f6a7b"},
+		{"gitlab-mr-5501-opened", activity.ActivityTypePR, "Add the login form",
+			"Merge request !12 opened in acme/platform/api", "/acme/platform/api/-/merge_requests/12"},
+		{"gitlab-note-9001", activity.ActivityTypeComment, "Migrate the billing jobs",
+			"Comment on merge request !7 in acme/billing", "/acme/billing/-/merge_requests/7#note_9001"},
+		{"gitlab-approval-1005", activity.ActivityTypeReview, "Migrate the billing jobs",
+			"Approved merge request !7 in acme/billing", "/acme/billing/-/merge_requests/7"},
+		{"gitlab-mr-5400-merged", activity.ActivityTypePR, "Fix the session timeout",
+			"Merge request !11 merged in acme/platform/api", "/acme/platform/api/-/merge_requests/11"},
+		{"gitlab-mr-7001-opened", activity.ActivityTypePR, "Update the dotfiles",
+			"Merge request !3 opened in jdoe/dotfiles", "/jdoe/dotfiles/-/merge_requests/3"},
+	}
+	if len(activities) != len(expected) {
+		t.Fatalf("Expected %d activities, got %+v", len(expected), activities)
+	}
+	for i, want := range expected {
+		act := activities[i]
+		if act.ID != want.id || act.Type != want.actType || act.Title != want.title || act.Description != want.description {
+			t.Errorf("Activity %d: expected %+v, got %+v", i, want, act)
+		}
+		if act.URL != "https://gitlab.example.com"+want.url {
+			t.Errorf("Activity %d: expected URL %s, got %s", i, want.url, act.URL)
+		}
+		if act.Platform != "gitlab" || act.Detail(activity.DetailRepo) == "" {
+			t.Errorf("Activity %d: expected the GitLab platform and project, got %+v", i, act)
+		}
+	}
+	if state := activities[4].Detail(activity.DetailState); state != "merged" {
+		t.Errorf("Expected the merged state, got %q", state)
+	}
+}
+
+func TestProvider_GetActivities_Groups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/events":
+			_, _ = w.Write([]byte(`[
+				{"id": 1, "project_id": 42, "action_name": "opened", "target_id": 5501, "target_iid": 12, "target_type": "MergeRequest", "target_title": "Add the login form", "created_at": "2024-01-15T10:00:00Z"},
+				{"id": 2, "project_id": 77, "action_name": "opened", "target_id": 6601, "target_iid": 7, "target_type": "MergeRequest", "target_title": "Migrate the billing jobs", "created_at": "2024-01-15T11:00:00Z"},
+				{"id": 3, "project_id": 42, "action_name": "accepted", "target_id": 5400, "target_iid": 11, "target_type": "MergeRequest", "target_title": "Fix the session timeout", "created_at": "2024-01-15T15:00:00Z"}
+			]`))
+		case "/api/v4/projects/42":
+			_, _ = w.Write([]byte(`{"id": 42, "path_with_namespace": "acme/platform/api", "web_url": "https://gitlab.example.com/acme/platform/api"}`))
+		case "/api/v4/projects/77":
+			_, _ = w.Write([]byte(`{"id": 77, "path_with_namespace": "acme/billing", "web_url": "https://gitlab.example.com/acme/billing"}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true, Groups: []string{"acme/platform"}})
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 2 {
+		t.Fatalf("Expected the 2 activities of acme/platform, got %+v", activities)
+	}
+	for _, act := range activities {
+		if act.Detail(activity.DetailRepo) != "acme/platform/api" {
+			t.Errorf("Expected only projects of acme/platform, got %s", act.Detail(activity.DetailRepo))
+		}
+	}
+}
+
+func TestProvider_GetActivities_MissingProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/events":
+			_, _ = w.Write([]byte(`[
+				{"id": 1, "project_id": 404, "action_name": "opened", "target_id": 1, "target_iid": 1, "target_type": "MergeRequest", "created_at": "2024-01-15T09:00:00Z"},
+				{"id": 2, "project_id": 404, "action_name": "accepted", "target_id": 1, "target_iid": 1, "target_type": "MergeRequest", "created_at": "2024-01-15T10:00:00Z"},
+				{"id": 3, "project_id": 42, "action_name": "opened", "target_id": 2, "target_iid": 2, "target_type": "MergeRequest", "target_title": "Add the login form", "created_at": "2024-01-15T11:00:00Z"}
+			]`))
+		case "/api/v4/projects/42":
+			_, _ = w.Write([]byte(`{"id": 42, "path_with_namespace": "acme/api", "web_url": "https://gitlab.example.com/acme/api"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "404 Project Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true})
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The events of the deleted project are skipped with a single warning
+	if len(activities) != 1 || activities[0].ID != "gitlab-mr-2-opened" {
+		t.Errorf("Expected the event of the project found, got %+v", activities)
+	}
+	if warnings := p.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "project 404") {
+		t.Errorf("Expected a warning for the missing project, got %v", warnings)
+	}
+}
+
+func TestProvider_GetActivities_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message": "401 Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Token: "revoked", URL: server.URL, Enabled: true})
+	_, err := p.GetActivities(context.Background(), time.Now().Add(-24*time.Hour), time.Now())
+	if err == nil || !strings.Contains(err.Error(), "GitLab API returned status 401: 401 Unauthorized") {
+		t.Errorf("Expected the API error message, got %v", err)
+	}
+}
+
+func TestProvider_MaxResults(t *testing.T) {
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		w.Header().Set("X-Next-Page", "2")
+		_, _ = w.Write([]byte(`[{"id": 1}, {"id": 2}, {"id": 3}]`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true, MaxResults: 2})
+	events, err := getList[event](context.Background(), p, p.apiURL("/events", nil))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(events) != 2 || pages != 1 {
+		t.Errorf("Expected the list capped at 2 events of the first page, got %d events over %d pages", len(events), pages)
+	}
+}
+
+func TestProvider_InGroups(t *testing.T) {
+	p := NewProvider(provider.Config{Groups: []string{"acme/platform/", "tools"}})
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"acme/platform/api", true},
+		{"acme/platform/web/app", true},
+		{"tools/ci", true},
+		{"acme/platformer", false},
+		{"acme/billing", false},
+	}
+	for _, tt := range tests {
+		if result := p.inGroups(tt.path); result != tt.expected {
+			t.Errorf("inGroups(%q): expected %t, got %t", tt.path, tt.expected, result)
+		}
+	}
+
+	if !NewProvider(provider.Config{}).inGroups("anything/at-all") {
+		t.Error("Expected every project without groups")
+	}
+}
@@ -0,0 +1,241 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/concurrency"
+	"daily/internal/provider"
+)
+
+// Ensure the provider can be used as a review source
+var _ provider.ReviewSource = (*Provider)(nil)
+
+// TodoItem represents a single todo item
+type TodoItem = provider.TodoItem
+
+// approvalLookups bounds the approval requests of the pending reviews
+var approvalLookups = concurrency.Options{Workers: 4}
+
+// mergeRequest is the part of a merge request the todos use
+type mergeRequest struct {
+	ID         int       `json:"id"`
+	IID        int       `json:"iid"`
+	ProjectID  int       `json:"project_id"`
+	Title      string    `json:"title"`
+	WebURL     string    `json:"web_url"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Draft      bool      `json:"draft"`
+	Labels     []string  `json:"labels"`
+	References struct {
+		Full string `json:"full"` // e.g. "acme/platform/api!12"
+	} `json:"references"`
+}
+
+// issue is the part of an issue the todos use
+type issue struct {
+	ID         int       `json:"id"`
+	IID        int       `json:"iid"`
+	Title      string    `json:"title"`
+	WebURL     string    `json:"web_url"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Labels     []string  `json:"labels"`
+	References struct {
+		Full string `json:"full"` // e.g. "acme/platform/api#3"
+	} `json:"references"`
+}
+
+// referencePath returns the project path of a full reference, e.g. "acme/api" for "acme/api!12"
+func referencePath(reference string) string {
+	if i := strings.LastIndexAny(reference, "!#"); i >= 0 {
+		return reference[:i]
+	}
+	return reference
+}
+
+// GetOpenMRs retrieves open merge requests created by the user
+func (p *Provider) GetOpenMRs(ctx context.Context) ([]TodoItem, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("GitLab provider not configured")
+	}
+
+	query := url.Values{"state": {"opened"}, "scope": {"created_by_me"}, "order_by": {"updated_at"}}
+	mrs, err := p.listMergeRequests(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	provenance := p.provenance(query.Encode())
+
+	todos := make([]TodoItem, 0, len(mrs))
+	for _, mr := range mrs {
+		todos = append(todos, newMergeRequestTodo(mr, fmt.Sprintf("gitlab-mr-%d", mr.ID), "Open MR in %s", "open", provenance))
+	}
+	return todos, nil
+}
+
+// GetPendingReviews retrieves open merge requests where the user is a reviewer and hasn't
+// approved yet
+func (p *Provider) GetPendingReviews(ctx context.Context) ([]TodoItem, error) {
+	mrs, query, err := p.pendingReviews(ctx)
+	if err != nil {
+		return nil, err
+	}
+	provenance := p.provenance(query)
+
+	todos := make([]TodoItem, 0, len(mrs))
+	for _, mr := range mrs {
+		todos = append(todos, newMergeRequestTodo(mr, fmt.Sprintf("gitlab-review-%d", mr.ID), "Review requested in %s", "review-requested", provenance))
+	}
+	return todos, nil
+}
+
+// GetReviewRequests retrieves the merge requests awaiting review from the user
+func (p *Provider) GetReviewRequests(ctx context.Context) ([]provider.ReviewItem, error) {
+	mrs, query, err := p.pendingReviews(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitLab review requests: %w", err)
+	}
+	provenance := p.provenance(query)
+
+	items := make([]provider.ReviewItem, 0, len(mrs))
+	for _, mr := range mrs {
+		items = append(items, provider.ReviewItem{
+			TodoItem:    newMergeRequestTodo(mr, fmt.Sprintf("gitlab-review-%d", mr.ID), "Review requested in %s", "review-requested", provenance),
+			Platform:    "gitlab",
+			RequestType: provider.ReviewRequestUser,
+			Repository:  referencePath(mr.References.Full),
+			Number:      mr.IID,
+		})
+	}
+	return items, nil
+}
+
+// pendingReviews returns the open merge requests the user reviews and hasn't approved, with
+// the query listing them. Merge requests whose approvals can't be fetched are kept as pending.
+func (p *Provider) pendingReviews(ctx context.Context) ([]mergeRequest, string, error) {
+	if !p.IsConfigured() {
+		return nil, "", fmt.Errorf("GitLab provider not configured")
+	}
+
+	me, err := p.currentUser(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get the GitLab user: %w", err)
+	}
+
+	query := url.Values{"state": {"opened"}, "scope": {"all"}, "reviewer_username": {me.Username}, "order_by": {"updated_at"}}
+	mrs, err := p.listMergeRequests(ctx, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Drafts aren't ready for review yet
+	ready := make([]mergeRequest, 0, len(mrs))
+	for _, mr := range mrs {
+		if !mr.Draft {
+			ready = append(ready, mr)
+		}
+	}
+
+	results := concurrency.Map(ctx, ready, approvalLookups, func(ctx context.Context, _ int, mr mergeRequest) (bool, error) {
+		return p.approvedBy(ctx, mr, me.Username)
+	})
+
+	pending := make([]mergeRequest, 0, len(ready))
+	for i, mr := range ready {
+		if results[i].Err != nil || !results[i].Value {
+			pending = append(pending, mr)
+		}
+	}
+	return pending, query.Encode(), nil
+}
+
+// approvedBy reports whether a merge request was approved by the user
+func (p *Provider) approvedBy(ctx context.Context, mr mergeRequest, username string) (bool, error) {
+	var approvals struct {
+		ApprovedBy []struct {
+			User struct {
+				Username string `json:"username"`
+			} `json:"user"`
+		} `json:"approved_by"`
+	}
+	approvalsURL := p.apiURL(fmt.Sprintf("/projects/%d/merge_requests/%d/approvals", mr.ProjectID, mr.IID), nil)
+	if err := p.get(ctx, approvalsURL, &approvals, nil); err != nil {
+		return false, err
+	}
+
+	for _, approval := range approvals.ApprovedBy {
+		if approval.User.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetAssignedIssues retrieves open issues assigned to the user
+func (p *Provider) GetAssignedIssues(ctx context.Context) ([]TodoItem, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("GitLab provider not configured")
+	}
+
+	query := url.Values{"state": {"opened"}, "scope": {"assigned_to_me"}, "order_by": {"updated_at"}}
+	issues, err := getList[issue](ctx, p, p.apiURL("/issues", query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitLab issues: %w", err)
+	}
+	provenance := p.provenance(query.Encode())
+
+	var todos []TodoItem
+	for _, item := range issues {
+		projectPath := referencePath(item.References.Full)
+		if !p.inGroups(projectPath) {
+			continue
+		}
+		todos = append(todos, TodoItem{
+			ID:          fmt.Sprintf("gitlab-issue-%d", item.ID),
+			Title:       item.Title,
+			Description: fmt.Sprintf("Issue #%d in %s", item.IID, projectPath),
+			URL:         item.WebURL,
+			UpdatedAt:   item.UpdatedAt,
+			Tags:        append([]string{projectPath, "assigned"}, item.Labels...),
+			Details:     activity.NewDetails(activity.DetailRepo, projectPath, activity.DetailState, "open"),
+			Provenance:  provenance,
+		})
+	}
+	return todos, nil
+}
+
+// listMergeRequests lists the merge requests matching a query, in the configured groups
+func (p *Provider) listMergeRequests(ctx context.Context, query url.Values) ([]mergeRequest, error) {
+	mrs, err := getList[mergeRequest](ctx, p, p.apiURL("/merge_requests", query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitLab merge requests: %w", err)
+	}
+
+	filtered := make([]mergeRequest, 0, len(mrs))
+	for _, mr := range mrs {
+		if p.inGroups(referencePath(mr.References.Full)) {
+			filtered = append(filtered, mr)
+		}
+	}
+	return filtered, nil
+}
+
+// newMergeRequestTodo converts a merge request into a todo item, described by a format of
+// its project path and tagged with tag
+func newMergeRequestTodo(mr mergeRequest, id, description, tag string, provenance *provider.Provenance) TodoItem {
+	projectPath := referencePath(mr.References.Full)
+	return TodoItem{
+		ID:          id,
+		Title:       mr.Title,
+		Description: fmt.Sprintf(description, projectPath),
+		URL:         mr.WebURL,
+		UpdatedAt:   mr.UpdatedAt,
+		Tags:        append([]string{projectPath, tag}, mr.Labels...),
+		Details:     activity.NewDetails(activity.DetailRepo, projectPath),
+		Provenance:  provenance,
+	}
+}
@@ -0,0 +1,226 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+func TestProvider_GetOpenMRs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/merge_requests" || r.URL.Query().Get("scope") != "created_by_me" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`[
+			{"id": 5501, "iid": 12, "project_id": 42, "title": "Add the login form", "web_url": "https://gitlab.example.com/acme/platform/api/-/merge_requests/12",
+			 "updated_at": "2024-01-15T10:00:00.000Z", "labels": ["frontend"], "references": {"full": "acme/platform/api!12"}},
+			{"id": 7001, "iid": 3, "project_id": 99, "title": "Update the dotfiles", "web_url": "https://gitlab.example.com/jdoe/dotfiles/-/merge_requests/3",
+			 "updated_at": "2024-01-15T16:00:00.000Z", "labels": [], "references": {"full": "jdoe/dotfiles!3"}}
+		]`))
+	}))
+	defer server.Close()
+
+	mrs, err := NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true}).GetOpenMRs(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(mrs) != 2 {
+		t.Fatalf("Expected 2 open MRs, got %+v", mrs)
+	}
+
+	mr := mrs[0]
+	if mr.ID != "gitlab-mr-5501" || mr.Title != "Add the login form" || mr.Description != "Open MR in acme/platform/api" {
+		t.Errorf("Expected the merge request, got %+v", mr)
+	}
+	if mr.URL != "https://gitlab.example.com/acme/platform/api/-/merge_requests/12" {
+		t.Errorf("Expected the merge request URL, got %s", mr.URL)
+	}
+	if len(mr.Tags) != 3 || mr.Tags[0] != "acme/platform/api" || mr.Tags[1] != "open" || mr.Tags[2] != "frontend" {
+		t.Errorf("Expected the project, open and label tags, got %v", mr.Tags)
+	}
+	if mr.Details[activity.DetailRepo] != "acme/platform/api" || mr.Provenance == nil || mr.Provenance.Provider != "gitlab" {
+		t.Errorf("Expected the project and provenance, got %v and %+v", mr.Details, mr.Provenance)
+	}
+
+	// Groups filter the merge requests by project
+	mrs, err = NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true, Groups: []string{"jdoe"}}).GetOpenMRs(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(mrs) != 1 || mrs[0].ID != "gitlab-mr-7001" {
+		t.Errorf("Expected only the MR of the jdoe group, got %+v", mrs)
+	}
+}
+
+func TestProvider_GetPendingReviews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/user":
+			_, _ = w.Write([]byte(`{"id": 5, "username": "jdoe"}`))
+		case "/api/v4/merge_requests":
+			if reviewer := r.URL.Query().Get("reviewer_username"); reviewer != "jdoe" {
+				t.Errorf("Expected the merge requests reviewed by jdoe, got %q", reviewer)
+			}
+			_, _ = w.Write([]byte(`[
+				{"id": 6601, "iid": 7, "project_id": 77, "title": "Migrate the billing jobs", "web_url": "https://gitlab.example.com/acme/billing/-/merge_requests/7", "draft": false, "references": {"full": "acme/billing!7"}},
+				{"id": 6602, "iid": 8, "project_id": 77, "title": "Add invoice exports", "web_url": "https://gitlab.example.com/acme/billing/-/merge_requests/8", "draft": false, "references": {"full": "acme/billing!8"}},
+				{"id": 6603, "iid": 9, "project_id": 77, "title": "Draft: Rework the ledger", "web_url": "https://gitlab.example.com/acme/billing/-/merge_requests/9", "draft": true, "references": {"full": "acme/billing!9"}}
+			]`))
+		case "/api/v4/projects/77/merge_requests/7/approvals":
+			_, _ = w.Write([]byte(`{"approved_by": [{"user": {"username": "jdoe"}}]}`))
+		case "/api/v4/projects/77/merge_requests/8/approvals":
+			_, _ = w.Write([]byte(`{"approved_by": [{"user": {"username": "bob"}}]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true})
+	reviews, err := p.GetPendingReviews(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// !7 is approved by the user and !9 is a draft
+	if len(reviews) != 1 {
+		t.Fatalf("Expected 1 pending review, got %+v", reviews)
+	}
+	review := reviews[0]
+	if review.ID != "gitlab-review-6602" || review.Title != "Add invoice exports" || review.Description != "Review requested in acme/billing" {
+		t.Errorf("Expected the MR awaiting the user's approval, got %+v", review)
+	}
+	if review.Provenance == nil || review.Provenance.Query == "" {
+		t.Errorf("Expected the query in the provenance, got %+v", review.Provenance)
+	}
+}
+
+func TestProvider_GetPendingReviews_ApprovalsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/user":
+			_, _ = w.Write([]byte(`{"id": 5, "username": "jdoe"}`))
+		case "/api/v4/merge_requests":
+			_, _ = w.Write([]byte(`[
+				{"id": 1, "iid": 7, "project_id": 77, "title": "Migrate the billing jobs", "references": {"full": "acme/billing!7"}},
+				{"id": 2, "iid": 8, "project_id": 77, "title": "Add invoice exports", "references": {"full": "acme/billing!8"}}
+			]`))
+		case "/api/v4/projects/77/merge_requests/7/approvals":
+			_, _ = w.Write([]byte(`{"approved_by": [{"user": {"username": "jdoe"}}]}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true})
+	reviews, err := p.GetPendingReviews(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// !8 whose approvals can't be read is kept as pending
+	if len(reviews) != 1 || reviews[0].ID != "gitlab-review-2" {
+		t.Errorf("Expected the MR without approvals kept, got %+v", reviews)
+	}
+}
+
+func TestProvider_GetReviewRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/user":
+			_, _ = w.Write([]byte(`{"id": 5, "username": "jdoe"}`))
+		case "/api/v4/merge_requests":
+			if reviewer := r.URL.Query().Get("reviewer_username"); reviewer != "jdoe" {
+				t.Errorf("Expected the merge requests reviewed by jdoe, got %q", reviewer)
+			}
+			_, _ = w.Write([]byte(`[
+				{"id": 6601, "iid": 7, "project_id": 77, "title": "Migrate the billing jobs", "web_url": "https://gitlab.example.com/acme/billing/-/merge_requests/7", "draft": false, "references": {"full": "acme/billing!7"}},
+				{"id": 6602, "iid": 8, "project_id": 77, "title": "Add invoice exports", "web_url": "https://gitlab.example.com/acme/billing/-/merge_requests/8", "draft": false, "references": {"full": "acme/billing!8"}},
+				{"id": 6603, "iid": 9, "project_id": 77, "title": "Draft: Rework the ledger", "web_url": "https://gitlab.example.com/acme/billing/-/merge_requests/9", "draft": true, "references": {"full": "acme/billing!9"}}
+			]`))
+		case "/api/v4/projects/77/merge_requests/7/approvals":
+			_, _ = w.Write([]byte(`{"approved_by": [{"user": {"username": "jdoe"}}]}`))
+		case "/api/v4/projects/77/merge_requests/8/approvals":
+			_, _ = w.Write([]byte(`{"approved_by": [{"user": {"username": "bob"}}]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true})
+	items, err := p.GetReviewRequests(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 review request, got %+v", items)
+	}
+
+	item := items[0]
+	if item.Platform != "gitlab" || item.RequestType != provider.ReviewRequestUser {
+		t.Errorf("Expected a GitLab review requested from the user, got %s and %s", item.Platform, item.RequestType)
+	}
+	if item.Repository != "acme/billing" || item.Number != 8 {
+		t.Errorf("Expected the project and number of the MR, got %s and %d", item.Repository, item.Number)
+	}
+}
+
+func TestProvider_GetAssignedIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/issues" || r.URL.Query().Get("scope") != "assigned_to_me" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`[
+			{"id": 8801, "iid": 31, "project_id": 42, "title": "Login fails with SSO", "web_url": "https://gitlab.example.com/acme/platform/api/-/issues/31",
+			 "updated_at": "2024-01-12T14:00:00.000Z", "labels": ["bug"], "references": {"full": "acme/platform/api#31"}}
+		]`))
+	}))
+	defer server.Close()
+
+	issues, err := NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true}).GetAssignedIssues(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 issue, got %+v", issues)
+	}
+
+	issue := issues[0]
+	if issue.ID != "gitlab-issue-8801" || issue.Title != "Login fails with SSO" || issue.Description != "Issue #31 in acme/platform/api" {
+		t.Errorf("Expected the assigned issue, got %+v", issue)
+	}
+	if issue.URL != "https://gitlab.example.com/acme/platform/api/-/issues/31" || issue.UpdatedAt.IsZero() {
+		t.Errorf("Expected the issue URL and update time, got %s and %v", issue.URL, issue.UpdatedAt)
+	}
+
+	issues, err = NewProvider(provider.Config{Token: "glpat-secret", URL: server.URL, Enabled: true, Groups: []string{"acme/billing"}}).GetAssignedIssues(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Expected no issue outside the groups, got %+v", issues)
+	}
+}
+
+func TestReferencePath(t *testing.T) {
+	tests := []struct {
+		reference, expected string
+	}{
+		{"acme/platform/api!12", "acme/platform/api"},
+		{"acme/api#3", "acme/api"},
+		{"acme/api", "acme/api"},
+	}
+	for _, tt := range tests {
+		if result := referencePath(tt.reference); result != tt.expected {
+			t.Errorf("referencePath(%q): expected %q, got %q", tt.reference, tt.expected, result)
+		}
+	}
+}
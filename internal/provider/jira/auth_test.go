@@ -0,0 +1,165 @@
+package jira
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestProvider_MakeRequest_AuthHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   provider.Config
+		expected string
+	}{
+		{
+			name:     "basic auth by default",
+			config:   provider.Config{Email: "test@example.com", Token: "testtoken"},
+			expected: "Basic " + base64.StdEncoding.EncodeToString([]byte("test@example.com:testtoken")),
+		},
+		{
+			name:     "explicit basic auth",
+			config:   provider.Config{Email: "test@example.com", Token: "testtoken", AuthType: "basic"},
+			expected: "Basic " + base64.StdEncoding.EncodeToString([]byte("test@example.com:testtoken")),
+		},
+		{
+			name:     "bearer auth",
+			config:   provider.Config{Token: "pat-token", AuthType: "bearer"},
+			expected: "Bearer pat-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authorization string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorization = r.Header.Get("Authorization")
+				_, _ = fmt.Fprint(w, `{}`)
+			}))
+			defer server.Close()
+
+			tt.config.URL = server.URL
+			p := NewProvider(tt.config)
+
+			var result struct{}
+			if err := p.makeRequest(context.Background(), p.apiURL("myself"), &result); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if authorization != tt.expected {
+				t.Errorf("Expected Authorization '%s', got '%s'", tt.expected, authorization)
+			}
+		})
+	}
+}
+
+func TestProvider_MakeRequest_UnknownAuthType(t *testing.T) {
+	p := NewProvider(provider.Config{Token: "testtoken", URL: "https://jira.example.com", AuthType: "oauth"})
+
+	var result struct{}
+	if err := p.makeRequest(context.Background(), p.apiURL("myself"), &result); err == nil {
+		t.Error("Expected error for unknown auth type, got none")
+	}
+}
+
+func TestProvider_APIURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		serverMode bool
+		expected   string
+	}{
+		{"cloud", false, "https://jira.example.com/rest/api/3/myself"},
+		{"server", true, "https://jira.example.com/rest/api/2/myself"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(provider.Config{URL: "https://jira.example.com/", ServerMode: tt.serverMode})
+
+			if result := p.apiURL("myself"); result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestProvider_ServerMode_Endpoints(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer pat-token" {
+			t.Errorf("Expected bearer token, got '%s'", r.Header.Get("Authorization"))
+		}
+
+		switch r.URL.Path {
+		case "/rest/api/2/search":
+			_, _ = fmt.Fprintf(w, `{"issues":[%s],"total":1}`, newTestIssue("OPS-1"))
+		case "/rest/api/2/myself":
+			_, _ = fmt.Fprint(w, `{"key":"jdoe","name":"jdoe"}`)
+		case "/rest/api/2/issue/OPS-1":
+			if r.URL.Query().Get("expand") != "changelog" {
+				t.Errorf("Expected changelog to be expanded, got '%s'", r.URL.RawQuery)
+			}
+			_, _ = fmt.Fprint(w, `{"changelog":{"histories":[
+				{"id":"10","author":{"key":"jdoe","name":"jdoe"},"created":"2024-01-15T10:00:00.000+0000","items":[{"field":"status","toString":"Done"}]}
+			]}}`)
+		case "/rest/api/2/issue/OPS-1/worklog":
+			_, _ = fmt.Fprint(w, `{"worklogs":[]}`)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Token:              "pat-token",
+		URL:                server.URL,
+		Enabled:            true,
+		AuthType:           "bearer",
+		ServerMode:         true,
+		IncludeTransitions: true,
+	})
+
+	todos, err := p.GetAssignedTickets(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos) != 1 {
+		t.Errorf("Expected 1 ticket, got %d", len(todos))
+	}
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	transitions := 0
+	for _, act := range activities {
+		if act.Description == "Transitioned OPS-1 to Done" {
+			transitions++
+		}
+	}
+	if transitions != 1 {
+		t.Errorf("Expected 1 transition matched by user key, got %d", transitions)
+	}
+
+	for _, path := range paths {
+		if path == "/rest/api/2/search/jql" || strings.HasPrefix(path, "/rest/api/3") {
+			t.Errorf("Expected only v2 endpoints in server mode, got request to %s", path)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+const (
+	// changelogPageSize is the number of history entries requested per changelog page, the API maximum
+	changelogPageSize = 100
+
+	// maxChangelogPages caps the pages read per issue on tickets with a very long history
+	maxChangelogPages = 10
+
+	// maxChangeValue is the longest field value quoted in a change description, longer values
+	// such as descriptions are only named
+	maxChangeValue = 60
+)
+
+// issueChangelog is the change history expanded on an issue. Cloud only expands up to
+// 100 entries, the rest has to be read from the changelog endpoint.
+type issueChangelog struct {
+	StartAt   int              `json:"startAt"`
+	Total     int              `json:"total"`
+	Histories []changelogEntry `json:"histories"`
+}
+
+// complete reports whether the expanded history holds every entry of the issue
+func (c *issueChangelog) complete() bool {
+	return c.StartAt == 0 && len(c.Histories) >= c.Total
+}
+
+// changelogPage is a page of the changelog endpoint, oldest entries first
+type changelogPage struct {
+	StartAt int              `json:"startAt"`
+	Total   int              `json:"total"`
+	IsLast  bool             `json:"isLast"`
+	Values  []changelogEntry `json:"values"`
+}
+
+// getChangelog returns the change history of an issue created since from, oldest first.
+// Jira Server / Data Center has no changelog endpoint, so the history is expanded on the issue instead.
+func (p *Provider) getChangelog(ctx context.Context, key string, from time.Time) ([]changelogEntry, error) {
+	if p.config.ServerMode {
+		var issue struct {
+			Changelog issueChangelog `json:"changelog"`
+		}
+		if err := p.makeRequest(ctx, p.apiURL(fmt.Sprintf("issue/%s?expand=changelog&fields=summary", url.PathEscape(key))), &issue); err != nil {
+			return nil, err
+		}
+		return p.changesSince(issue.Changelog.Histories, from), nil
+	}
+
+	first, err := p.getChangelogPage(ctx, key, 0, changelogPageSize)
+	if err != nil {
+		return nil, err
+	}
+	if first.IsLast || len(first.Values) >= first.Total {
+		return p.changesSince(first.Values, from), nil
+	}
+
+	// Recent changes are on the last pages of long histories: read backwards from the end
+	// until reaching a page that starts before from
+	var newer []changelogEntry
+	end := first.Total
+	for pages := 1; end > len(first.Values); pages++ {
+		if pages == maxChangelogPages {
+			return p.changesSince(newer, from), nil
+		}
+
+		start := max(end-changelogPageSize, len(first.Values))
+		page, err := p.getChangelogPage(ctx, key, start, end-start)
+		if err != nil {
+			return nil, err
+		}
+		newer = append(page.Values, newer...)
+		if len(page.Values) == 0 || p.startsBefore(page.Values, from) {
+			return p.changesSince(newer, from), nil
+		}
+		end = start
+	}
+
+	return p.changesSince(append(first.Values, newer...), from), nil
+}
+
+// getChangelogPage reads count history entries of an issue starting at startAt
+func (p *Provider) getChangelogPage(ctx context.Context, key string, startAt, count int) (changelogPage, error) {
+	var page changelogPage
+	pageURL := p.apiURL(fmt.Sprintf("issue/%s/changelog?startAt=%d&maxResults=%d", url.PathEscape(key), startAt, count))
+	if err := p.makeRequest(ctx, pageURL, &page); err != nil {
+		return page, err
+	}
+	return page, nil
+}
+
+// startsBefore reports whether the oldest entry of a page was created before from
+func (p *Provider) startsBefore(entries []changelogEntry, from time.Time) bool {
+	created, err := p.parseJIRATime(entries[0].Created)
+	return err == nil && created.Before(from)
+}
+
+// changesSince returns the entries created since from, dropping the ones with unparseable times
+func (p *Provider) changesSince(entries []changelogEntry, from time.Time) []changelogEntry {
+	return slices.DeleteFunc(slices.Clone(entries), func(entry changelogEntry) bool {
+		created, err := p.parseJIRATime(entry.Created)
+		return err != nil || created.Before(from)
+	})
+}
+
+// userChange returns the latest change the current user made to the issue in the time range.
+// Nothing is found when the search didn't return the changelog, or it can't be read.
+func (p *Provider) userChange(ctx context.Context, issue jiraIssue, currentAccountID accountIDFunc, from, to time.Time) (changelogEntry, time.Time, bool) {
+	if issue.Changelog == nil {
+		return changelogEntry{}, time.Time{}, false
+	}
+
+	accountID, err := currentAccountID()
+	if err != nil {
+		return changelogEntry{}, time.Time{}, false
+	}
+
+	entries := issue.Changelog.Histories
+	if !issue.Changelog.complete() {
+		if entries, err = p.getChangelog(ctx, issue.Key, from); err != nil {
+			return changelogEntry{}, time.Time{}, false
+		}
+	}
+
+	var latest changelogEntry
+	var latestAt time.Time
+	for _, entry := range entries {
+		if entry.Author.id() != accountID {
+			continue
+		}
+
+		created, err := p.parseJIRATime(entry.Created)
+		if err != nil || created.Before(from) || created.After(to) {
+			continue
+		}
+		// Entries aren't in the same order on every endpoint
+		if created.After(latestAt) {
+			latest, latestAt = entry, created
+		}
+	}
+
+	return latest, latestAt, !latestAt.IsZero()
+}
+
+// describeChange describes a history entry by its status change, or its first changed field,
+// e.g. "Changed status from In Progress to In Review"
+func describeChange(entry changelogEntry) string {
+	if len(entry.Items) == 0 {
+		return ""
+	}
+
+	item := entry.Items[0]
+	for _, candidate := range entry.Items {
+		if candidate.Field == "status" {
+			item = candidate
+			break
+		}
+	}
+
+	var description string
+	switch {
+	case len(item.FromString) > maxChangeValue || len(item.ToString) > maxChangeValue:
+		description = fmt.Sprintf("Changed %s", item.Field)
+	case item.FromString == "" && item.ToString == "":
+		description = fmt.Sprintf("Changed %s", item.Field)
+	case item.FromString == "":
+		description = fmt.Sprintf("Set %s to %s", item.Field, item.ToString)
+	case item.ToString == "":
+		description = fmt.Sprintf("Cleared %s", item.Field)
+	default:
+		description = fmt.Sprintf("Changed %s from %s to %s", item.Field, item.FromString, item.ToString)
+	}
+
+	if others := len(entry.Items) - 1; others > 0 {
+		description = fmt.Sprintf("%s (+%d more)", description, others)
+	}
+	return description
+}
@@ -0,0 +1,263 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+// fixedAccountID returns an accountIDFunc resolving to id
+func fixedAccountID(id string) accountIDFunc {
+	return func() (string, error) { return id, nil }
+}
+
+// testChangelogEntry is a changelog entry fixture changing the status of an issue
+func testChangelogEntry(id int, author string, created time.Time, from, to string) map[string]any {
+	return map[string]any{
+		"id":      strconv.Itoa(id),
+		"author":  map[string]string{"accountId": author},
+		"created": created.Format("2006-01-02T15:04:05.000-0700"),
+		"items":   []map[string]string{{"field": "status", "fromString": from, "toString": to}},
+	}
+}
+
+// longChangelog returns a history of count entries one minute apart starting at start,
+// alternately authored by "me" and "teammate"
+func longChangelog(count int, start time.Time) []map[string]any {
+	entries := make([]map[string]any, count)
+	for i := range entries {
+		author := "me"
+		if i%2 == 1 {
+			author = "teammate"
+		}
+		entries[i] = testChangelogEntry(i, author, start.Add(time.Duration(i)*time.Minute), "Step "+strconv.Itoa(i), "Step "+strconv.Itoa(i+1))
+	}
+	return entries
+}
+
+// changelogPageRequest records the range of a changelog page request
+type changelogPageRequest struct {
+	startAt, maxResults int
+}
+
+// newChangelogServer serves the changelog of an issue page by page and records the pages read
+func newChangelogServer(t *testing.T, entries []map[string]any, requests *[]changelogPageRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-1/changelog" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+		*requests = append(*requests, changelogPageRequest{startAt, maxResults})
+
+		end := min(startAt+maxResults, len(entries))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"startAt": startAt,
+			"total":   len(entries),
+			"isLast":  end == len(entries),
+			"values":  entries[startAt:end],
+		})
+	}))
+}
+
+func TestProvider_GetChangelog_ReadsRecentPages(t *testing.T) {
+	start := time.Date(2024, 1, 15, 6, 0, 0, 0, time.UTC)
+	entries := longChangelog(250, start)
+
+	tests := []struct {
+		name             string
+		from             time.Time
+		expectedCount    int
+		expectedRequests []changelogPageRequest
+	}{
+		{
+			name:             "stops at the page starting before from",
+			from:             start.Add(200 * time.Minute),
+			expectedCount:    50,
+			expectedRequests: []changelogPageRequest{{0, 100}, {150, 100}},
+		},
+		{
+			name:             "reads the whole history",
+			from:             start,
+			expectedCount:    250,
+			expectedRequests: []changelogPageRequest{{0, 100}, {150, 100}, {100, 50}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []changelogPageRequest
+			server := newChangelogServer(t, entries, &requests)
+			defer server.Close()
+
+			p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+
+			changelog, err := p.getChangelog(context.Background(), "PROJ-1", tt.from)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if len(changelog) != tt.expectedCount {
+				t.Fatalf("Expected %d entries, got %d", tt.expectedCount, len(changelog))
+			}
+			// Entries stay in chronological order across pages
+			if changelog[len(changelog)-1].ID != "249" || changelog[0].ID != strconv.Itoa(250-tt.expectedCount) {
+				t.Errorf("Expected entries %d to 249, got %s to %s", 250-tt.expectedCount, changelog[0].ID, changelog[len(changelog)-1].ID)
+			}
+			if fmt.Sprint(requests) != fmt.Sprint(tt.expectedRequests) {
+				t.Errorf("Expected page requests %v, got %v", tt.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestProvider_GetChangelog_MaxPages(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := longChangelog(2000, start)
+
+	var requests []changelogPageRequest
+	server := newChangelogServer(t, entries, &requests)
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+
+	changelog, err := p.getChangelog(context.Background(), "PROJ-1", start)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(requests) != maxChangelogPages {
+		t.Errorf("Expected %d page requests, got %d", maxChangelogPages, len(requests))
+	}
+	// The most recent entries are kept
+	if len(changelog) == 0 || changelog[len(changelog)-1].ID != "1999" {
+		t.Errorf("Expected the latest entries to be read, got %d entries", len(changelog))
+	}
+}
+
+func TestProvider_GetUpdatedIssues_UserChanges(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+
+	issue := func(key string, updated time.Time, changelog map[string]any) map[string]any {
+		return map[string]any{
+			"key": key,
+			"fields": map[string]any{
+				"summary": "Summary " + key,
+				"updated": updated.Format("2006-01-02T15:04:05.000-0700"),
+				"status":  map[string]string{"name": "Done"},
+			},
+			"changelog": changelog,
+		}
+	}
+	complete := func(entries ...map[string]any) map[string]any {
+		return map[string]any{"startAt": 0, "total": len(entries), "histories": entries}
+	}
+
+	// PROJ-3 has a long history: the search only returns its first entries
+	long := longChangelog(150, at(6))
+	long = append(long, testChangelogEntry(150, "teammate", at(16), "Step 150", "Done"))
+
+	issues := []map[string]any{
+		// Changed by me in the morning, then by a teammate
+		issue("PROJ-1", at(17), complete(
+			testChangelogEntry(1, "me", at(9), "To Do", "In Progress"),
+			testChangelogEntry(2, "me", at(10), "In Progress", "In Review"),
+			testChangelogEntry(3, "teammate", at(17), "In Review", "Done"),
+		)),
+		// Only changed by teammates
+		issue("PROJ-2", at(15), complete(testChangelogEntry(4, "teammate", at(15), "To Do", "Done"))),
+		issue("PROJ-3", at(16), map[string]any{"startAt": 0, "total": len(long), "histories": long[:100]}),
+		// Changed by me after the range
+		issue("PROJ-4", at(12), complete(testChangelogEntry(5, "me", day.Add(30*time.Hour), "To Do", "Done"))),
+		// No changelog returned
+		issue("PROJ-5", at(14), nil),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/search/jql":
+			if r.URL.Query().Get("expand") != "changelog" {
+				t.Errorf("Expected changelog to be expanded, got '%s'", r.URL.RawQuery)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"issues": issues, "isLast": true})
+		case "/rest/api/3/issue/PROJ-3/changelog":
+			startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+			maxResults, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+			end := min(startAt+maxResults, len(long))
+			_ = json.NewEncoder(w).Encode(map[string]any{"startAt": startAt, "total": len(long), "isLast": end == len(long), "values": long[startAt:end]})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+
+	activities, err := p.getUpdatedIssues(context.Background(), fixedAccountID("me"), day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != len(issues) {
+		t.Fatalf("Expected %d activities, got %d", len(issues), len(activities))
+	}
+
+	expected := []struct {
+		timestamp   time.Time
+		description string
+	}{
+		{at(10), "Changed status from In Progress to In Review"},
+		{at(15), "Status: Done"},
+		{at(6).Add(148 * time.Minute), "Changed status from Step 148 to Step 149"},
+		{at(12), "Status: Done"},
+		{at(14), "Status: Done"},
+	}
+	for i, act := range activities {
+		if !act.Timestamp.Equal(expected[i].timestamp) {
+			t.Errorf("%s: expected timestamp %v, got %v", act.ID, expected[i].timestamp, act.Timestamp)
+		}
+		if act.Description != expected[i].description {
+			t.Errorf("%s: expected description %q, got %q", act.ID, expected[i].description, act.Description)
+		}
+	}
+}
+
+func TestDescribeChange(t *testing.T) {
+	type item = struct {
+		Field      string `json:"field"`
+		FromString string `json:"fromString"`
+		ToString   string `json:"toString"`
+	}
+	longText := "A description long enough that quoting it would drown the rest of the summary line"
+
+	tests := []struct {
+		name     string
+		items    []item
+		expected string
+	}{
+		{"status", []item{{"status", "To Do", "In Progress"}}, "Changed status from To Do to In Progress"},
+		{"status preferred", []item{{"labels", "", "backend"}, {"status", "To Do", "Done"}}, "Changed status from To Do to Done (+1 more)"},
+		{"set", []item{{"assignee", "", "Alice"}}, "Set assignee to Alice"},
+		{"cleared", []item{{"Sprint", "Sprint 4", ""}}, "Cleared Sprint"},
+		{"long value", []item{{"description", longText, longText + "!"}}, "Changed description"},
+		{"no items", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeChange(changelogEntry{Items: tt.items}); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
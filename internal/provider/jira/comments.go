@@ -0,0 +1,63 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// maxCommentSnippet caps the length of the comment text attached to ticket details
+	maxCommentSnippet = 200
+
+	// DefaultRecentComments is the number of comments attached to ticket details
+	DefaultRecentComments = 3
+)
+
+// Comment is a snippet of a comment on an issue
+type Comment struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"` // Plain text, truncated to maxCommentSnippet characters
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetRecentComments returns the latest comments of an issue, newest first.
+// Atlassian Document Format bodies are flattened to plain text.
+func (p *Provider) GetRecentComments(ctx context.Context, key string, limit int) ([]Comment, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("JIRA provider not configured")
+	}
+
+	var result struct {
+		Comments []struct {
+			Author  jiraAuthor      `json:"author"`
+			Body    json.RawMessage `json:"body"`
+			Created string          `json:"created"`
+		} `json:"comments"`
+	}
+
+	commentsURL := p.apiURL(fmt.Sprintf("issue/%s/comment?orderBy=-created&maxResults=%d", url.PathEscape(key), limit))
+	if err := p.makeRequest(ctx, commentsURL, &result); err != nil {
+		return nil, fmt.Errorf("failed to get comments of %s: %w", key, err)
+	}
+
+	comments := make([]Comment, 0, len(result.Comments))
+	for _, comment := range result.Comments {
+		created, err := p.parseJIRATime(comment.Created)
+		if err != nil {
+			continue // Skip comments with unparseable times
+		}
+		comments = append(comments, Comment{
+			Author:    comment.Author.DisplayName,
+			Body:      truncateExcerpt(displayText(adfText(comment.Body), nil), maxCommentSnippet),
+			CreatedAt: created,
+		})
+	}
+
+	if len(comments) > limit {
+		comments = comments[:limit]
+	}
+	return comments, nil
+}
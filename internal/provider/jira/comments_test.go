@@ -0,0 +1,87 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+func TestProvider_GetRecentComments(t *testing.T) {
+	long := strings.Repeat("word ", 60)
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-1/comment" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.RawQuery
+		_, _ = fmt.Fprintf(w, `{"comments":[
+			{"author":{"displayName":"Alice"},"created":"2024-01-15T12:00:00.000+0000",
+			 "body":{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Deployed to"},{"type":"text","text":" staging"}]}]}},
+			{"author":{"displayName":"Bob"},"created":"2024-01-15T10:00:00.000+0000","body":"Plain   text\nbody"},
+			{"author":{"displayName":"Carol"},"created":"2024-01-15T09:00:00.000+0000","body":%q}
+		]}`, long)
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	comments, err := p.GetRecentComments(context.Background(), "PROJ-1", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(query, "orderBy=-created") || !strings.Contains(query, "maxResults=3") {
+		t.Errorf("Expected newest comments to be requested, got query '%s'", query)
+	}
+	if len(comments) != 3 {
+		t.Fatalf("Expected 3 comments, got %d", len(comments))
+	}
+
+	if comments[0].Author != "Alice" || comments[0].Body != "Deployed to staging" {
+		t.Errorf("Expected ADF body to be flattened, got %+v", comments[0])
+	}
+	if comments[0].CreatedAt.Hour() != 12 {
+		t.Errorf("Expected creation time to be parsed, got %v", comments[0].CreatedAt)
+	}
+	if comments[1].Body != "Plain text body" {
+		t.Errorf("Expected whitespace to be collapsed, got '%s'", comments[1].Body)
+	}
+
+	runes := []rune(comments[2].Body)
+	if len(runes) != maxCommentSnippet || !strings.HasSuffix(comments[2].Body, "…") {
+		t.Errorf("Expected body truncated to %d characters, got %d: '%s'", maxCommentSnippet, len(runes), comments[2].Body)
+	}
+}
+
+func TestProvider_GetRecentComments_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	if _, err := p.GetRecentComments(context.Background(), "PROJ-1", 3); err == nil || !strings.Contains(err.Error(), "PROJ-1") {
+		t.Errorf("Expected error mentioning the issue, got: %v", err)
+	}
+	if _, err := NewProvider(provider.Config{}).GetRecentComments(context.Background(), "PROJ-1", 3); err == nil {
+		t.Error("Expected error for unconfigured provider, got nil")
+	}
+}
@@ -0,0 +1,108 @@
+package jira
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// mentionMarkup matches wiki-markup user mentions: [~accountid:ID], [Jane Doe|~accountid:ID]
+	// and the [~username] of Jira Server
+	mentionMarkup = regexp.MustCompile(`\[(?:([^\[\]|]+)\|)?~(accountid:)?([^\[\]|\s]+)\]`)
+
+	// linkMarkup matches wiki-markup links with a text, e.g. [the spec|https://example.com/spec]
+	linkMarkup = regexp.MustCompile(`\[([^\[\]|]+)\|[^\[\]]+\]`)
+
+	// monospaceMarkup matches {{monospaced}} text
+	monospaceMarkup = regexp.MustCompile(`\{\{(.*?)\}\}`)
+
+	// macroMarkup matches the tags of the macros wrapping text, e.g. {color:red} or {code:java}
+	macroMarkup = regexp.MustCompile(`\{(?:color|noformat|code|quote|panel)(?::[^{}]*)?\}`)
+
+	// headingMarkup matches a leading heading marker, e.g. "h2. "
+	headingMarkup = regexp.MustCompile(`^h[1-6]\.\s+`)
+
+	// shortcodeMarkup matches emoji shortcodes, e.g. :warning:
+	shortcodeMarkup = regexp.MustCompile(`:[a-z0-9_+-]+:`)
+)
+
+// emoticons maps the Atlassian wiki-markup emoticons to their unicode equivalent. They are
+// only replaced as words of their own, so that e.g. "f(x)" or "step (i)" stay as written.
+var emoticons = map[string]string{
+	"(!)":    "⚠️",
+	"(?)":    "❓",
+	"(/)":    "✅",
+	"(x)":    "❌",
+	"(i)":    "ℹ️",
+	"(y)":    "👍",
+	"(n)":    "👎",
+	"(on)":   "💡",
+	"(*)":    "⭐",
+	"(+)":    "➕",
+	"(-)":    "➖",
+	"(flag)": "🚩",
+}
+
+// shortcodes maps the common emoji shortcodes of Jira to their unicode equivalent
+var shortcodes = map[string]string{
+	":warning:":            "⚠️",
+	":white_check_mark:":   "✅",
+	":heavy_check_mark:":   "✔️",
+	":x:":                  "❌",
+	":exclamation:":        "❗",
+	":question:":           "❓",
+	":info:":               "ℹ️",
+	":information_source:": "ℹ️",
+	":fire:":               "🔥",
+	":rocket:":             "🚀",
+	":bug:":                "🐛",
+	":tada:":               "🎉",
+	":thumbsup:":           "👍",
+	":+1:":                 "👍",
+	":thumbsdown:":         "👎",
+	":-1:":                 "👎",
+	":star:":               "⭐",
+	":no_entry:":           "⛔",
+	":construction:":       "🚧",
+	":red_circle:":         "🔴",
+	":lock:":               "🔒",
+	":eyes:":               "👀",
+}
+
+// displayText cleans a JIRA string for display: user mentions become @DisplayName, using names
+// (account IDs to display names) when the markup doesn't hold the name and dropping the mention
+// when neither does, emoticons and known shortcodes become emoji, the markup of monospaced text,
+// links, macros and headings is removed, and whitespace is collapsed
+func displayText(text string, names map[string]string) string {
+	text = mentionMarkup.ReplaceAllStringFunc(text, func(mention string) string {
+		parts := mentionMarkup.FindStringSubmatch(mention)
+		name, isAccountID, id := strings.TrimSpace(parts[1]), parts[2] != "", parts[3]
+		switch {
+		case name != "":
+			return "@" + strings.TrimPrefix(name, "@")
+		case names[id] != "":
+			return "@" + names[id]
+		case isAccountID:
+			return ""
+		default:
+			return "@" + id // Jira Server usernames are readable
+		}
+	})
+	text = linkMarkup.ReplaceAllString(text, "$1")
+	text = monospaceMarkup.ReplaceAllString(text, "$1")
+	text = macroMarkup.ReplaceAllString(text, "")
+	text = headingMarkup.ReplaceAllString(strings.TrimSpace(text), "")
+	text = shortcodeMarkup.ReplaceAllStringFunc(text, func(code string) string {
+		if emoji, ok := shortcodes[code]; ok {
+			return emoji
+		}
+		return code
+	})
+	words := strings.Fields(text)
+	for i, word := range words {
+		if emoji, ok := emoticons[word]; ok {
+			words[i] = emoji
+		}
+	}
+	return strings.Join(words, " ")
+}
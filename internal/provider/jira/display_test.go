@@ -0,0 +1,61 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDisplayText(t *testing.T) {
+	names := map[string]string{"5b10ac8d82e05b22cc7d4ef5": "Jane Doe"}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain summary", "Fix the billing export", "Fix the billing export"},
+		{"mention with a known name", "Ask [~accountid:5b10ac8d82e05b22cc7d4ef5] about the rollout", "Ask @Jane Doe about the rollout"},
+		{"mention with its name", "Follow-up from [John Smith|~accountid:712020:0f1a2b3c] review", "Follow-up from @John Smith review"},
+		{"mention with an unknown account", "[~accountid:557058:f58131cb-b67d-43c7-b30d-6b58d40bd077] Broken login on Safari", "Broken login on Safari"},
+		{"server username", "Pair with [~jdoe] on the parser", "Pair with @jdoe on the parser"},
+		{"emoticons", "(!) Prod outage (x) rollback done (/)", "⚠️ Prod outage ❌ rollback done ✅"},
+		{"emoticons in words", "Plot f(x) for option(n) and step(i)", "Plot f(x) for option(n) and step(i)"},
+		{"shortcodes", ":warning: Flaky test :bug: :not_an_emoji:", "⚠️ Flaky test 🐛 :not_an_emoji:"},
+		{"time is not a shortcode", "Cron runs at 10:30:00 daily", "Cron runs at 10:30:00 daily"},
+		{"monospace and link", "Bump {{golang.org/x/net}} per [the advisory|https://example.com/GHSA-1234]", "Bump golang.org/x/net per the advisory"},
+		{"color and heading", "h2. {color:#de350b}URGENT{color}:   migrate  the  DB", "URGENT: migrate the DB"},
+		{"code macro", "Crash in {code:java}Parser.parse(){code}", "Crash in Parser.parse()"},
+		{
+			"copy-pasted description",
+			"h3. (i) [~accountid:5b10ac8d82e05b22cc7d4ef5] said: {{NullPointerException}} in [checkout|https://acme.atlassian.net/wiki/x] :fire:\n\t",
+			"ℹ️ @Jane Doe said: NullPointerException in checkout 🔥",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := displayText(tt.input, names); result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestJiraIssue_SummaryForDisplay(t *testing.T) {
+	var issue jiraIssue
+	data := `{"key": "PROJ-1", "fields": {
+		"summary": "(!) Review [~accountid:abc123] patch",
+		"assignee": {"accountId": "abc123", "displayName": "Jane Doe"},
+		"parent": {"key": "PROJ-0", "fields": {"summary": ":rocket: {{v2}} launch"}}
+	}}`
+	if err := json.Unmarshal([]byte(data), &issue); err != nil {
+		t.Fatalf("Failed to parse issue: %v", err)
+	}
+
+	if issue.Fields.Summary != "⚠️ Review @Jane Doe patch" {
+		t.Errorf("Expected the summary cleaned for display, got '%s'", issue.Fields.Summary)
+	}
+	if issue.Fields.Parent.Fields.Summary != "🚀 v2 launch" {
+		t.Errorf("Expected the parent summary cleaned for display, got '%s'", issue.Fields.Parent.Fields.Summary)
+	}
+}
@@ -0,0 +1,46 @@
+package jira
+
+import (
+	"encoding/json"
+
+	"daily/internal/activity"
+)
+
+// defaultEpicLinkField is the custom field older JIRA instances use to link an issue to its epic
+const defaultEpicLinkField = "customfield_10014"
+
+// epicLinkField returns the configured epic link custom field ID
+func (p *Provider) epicLinkField() string {
+	if p.config.EpicLinkField != "" {
+		return p.config.EpicLinkField
+	}
+	return defaultEpicLinkField
+}
+
+// issueEpic returns the epic of an issue: the issue linked through the epic link field on older
+// instances, or its parent otherwise. The epic link field only holds the key, so no name is known.
+func (p *Provider) issueEpic(issue jiraIssue) *activity.Epic {
+	var epicKey string
+	if raw := issue.rawFields[p.epicLinkField()]; len(raw) > 0 {
+		_ = json.Unmarshal(raw, &epicKey)
+	}
+	if epicKey != "" {
+		if issue.Fields.Parent != nil && issue.Fields.Parent.Key == epicKey {
+			return &activity.Epic{Key: epicKey, Name: issue.Fields.Parent.Fields.Summary}
+		}
+		return &activity.Epic{Key: epicKey}
+	}
+
+	if issue.Fields.Parent != nil && issue.Fields.Parent.Key != "" {
+		return &activity.Epic{Key: issue.Fields.Parent.Key, Name: issue.Fields.Parent.Fields.Summary}
+	}
+	return nil
+}
+
+// withEpicTag appends an "epic:KEY" tag when the issue has an epic
+func withEpicTag(tags []string, epic *activity.Epic) []string {
+	if epic == nil {
+		return tags
+	}
+	return append(tags, "epic:"+epic.Key)
+}
@@ -0,0 +1,117 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestProvider_IssueEpic(t *testing.T) {
+	tests := []struct {
+		name     string
+		issue    string
+		expected string // "KEY|Name", or "" for no epic
+	}{
+		{
+			name:     "parent",
+			issue:    `{"key":"PROJ-1","fields":{"parent":{"key":"PROJ-100","fields":{"summary":"Checkout revamp"}}}}`,
+			expected: "PROJ-100|Checkout revamp",
+		},
+		{
+			name:     "epic link field",
+			issue:    `{"key":"PROJ-1","fields":{"customfield_10014":"PROJ-200"}}`,
+			expected: "PROJ-200|",
+		},
+		{
+			name:     "epic link field wins over a sub-task parent",
+			issue:    `{"key":"PROJ-1","fields":{"customfield_10014":"PROJ-200","parent":{"key":"PROJ-2","fields":{"summary":"Story"}}}}`,
+			expected: "PROJ-200|",
+		},
+		{
+			name:     "no epic",
+			issue:    `{"key":"PROJ-1","fields":{"customfield_10014":null}}`,
+			expected: "",
+		},
+	}
+
+	p := NewProvider(provider.Config{})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var issue jiraIssue
+			if err := json.Unmarshal([]byte(tt.issue), &issue); err != nil {
+				t.Fatalf("Failed to parse issue: %v", err)
+			}
+
+			epic := p.issueEpic(issue)
+			got := ""
+			if epic != nil {
+				got = epic.Key + "|" + epic.Name
+			}
+			if got != tt.expected {
+				t.Errorf("Expected epic '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProvider_GetActivities_EpicTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields := r.URL.Query().Get("fields")
+		if !strings.Contains(fields, "parent") || !strings.Contains(fields, "customfield_10014") {
+			t.Errorf("Expected fields to contain parent and the epic link field, got '%s'", fields)
+		}
+		if !strings.Contains(r.URL.Query().Get("jql"), "assignee = currentUser()") {
+			_, _ = fmt.Fprint(w, `{"issues":[],"isLast":true}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"issues":[
+			{"key":"PROJ-1","fields":{"summary":"With epic","updated":"2024-01-15T10:00:00.000+0000","status":{"name":"To Do"},
+			 "parent":{"key":"PROJ-100","fields":{"summary":"Checkout revamp"}}}},
+			{"key":"PROJ-2","fields":{"summary":"Without epic","updated":"2024-01-15T11:00:00.000+0000","status":{"name":"To Do"}}}
+		],"isLast":true}`)
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:    "test@example.com",
+		Token:    "testtoken",
+		URL:      server.URL,
+		Enabled:  true,
+		Timezone: "UTC",
+	})
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.getUpdatedIssues(context.Background(), fixedAccountID("me"), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 2 {
+		t.Fatalf("Expected 2 activities, got %d", len(activities))
+	}
+
+	if activities[0].Epic == nil || activities[0].Epic.Name != "Checkout revamp" {
+		t.Errorf("Expected epic 'Checkout revamp', got %+v", activities[0].Epic)
+	}
+	if !strings.Contains(strings.Join(activities[0].Tags, ","), "epic:PROJ-100") {
+		t.Errorf("Expected epic tag, got %v", activities[0].Tags)
+	}
+	if activities[1].Epic != nil || strings.Contains(strings.Join(activities[1].Tags, ","), "epic:") {
+		t.Errorf("Expected no epic, got %+v %v", activities[1].Epic, activities[1].Tags)
+	}
+
+	todos, err := p.GetAssignedTickets(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos) != 2 || !strings.Contains(strings.Join(todos[0].Tags, ","), "epic:PROJ-100") {
+		t.Errorf("Expected assigned ticket to carry the epic tag, got %+v", todos)
+	}
+}
@@ -0,0 +1,113 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// summaryFilter returns the JQL filter applied to summary queries
+func (p *Provider) summaryFilter() string {
+	if p.config.SummaryFilter != "" {
+		return p.config.SummaryFilter
+	}
+	return p.config.Filter
+}
+
+// todoFilter returns the JQL filter applied to todo queries (assigned tickets and mentions)
+func (p *Provider) todoFilter() string {
+	if p.config.TodoFilter != "" {
+		return p.config.TodoFilter
+	}
+	return p.config.Filter
+}
+
+// openStatusClause returns the JQL clause leaving out done tickets: the configured excluded
+// statuses, or else every status of the Done category, whatever the workflow names it
+func (p *Provider) openStatusClause() string {
+	if len(p.config.ExcludedStatuses) == 0 {
+		return "statusCategory != Done"
+	}
+
+	statuses := make([]string, len(p.config.ExcludedStatuses))
+	for i, status := range p.config.ExcludedStatuses {
+		statuses[i] = quoteJQL(status)
+	}
+	return fmt.Sprintf("status NOT IN (%s)", strings.Join(statuses, ", "))
+}
+
+// jqlEscaper escapes the characters that end or escape a quoted JQL string
+var jqlEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quoteJQL quotes a value for use in a JQL query, e.g. Won't "Fix" becomes "Won't \"Fix\""
+func quoteJQL(value string) string {
+	return `"` + jqlEscaper.Replace(value) + `"`
+}
+
+// FilterError is returned by ValidateFilters for a filter JIRA rejects as invalid JQL
+type FilterError struct {
+	Name string // Name of the setting, e.g. "todo_filter"
+	JQL  string
+	Err  error
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("invalid JIRA %s %q: %v", e.Name, e.JQL, e.Err)
+}
+
+func (e *FilterError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateFilters checks every configured JQL filter with a dry-run search, so invalid JQL
+// is reported with the JIRA error message instead of silently yielding no results. Filters
+// JIRA rejects with a 400 fail with a *FilterError, other errors, e.g. JIRA being down, are
+// returned as is.
+func (p *Provider) ValidateFilters(ctx context.Context) error {
+	filters := []struct {
+		name string
+		jql  string
+	}{
+		{"filter", p.config.Filter},
+		{"summary_filter", p.config.SummaryFilter},
+		{"todo_filter", p.config.TodoFilter},
+	}
+
+	for _, filter := range filters {
+		if filter.jql == "" {
+			continue
+		}
+		err := p.dryRunSearch(ctx, filter.jql)
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+			return &FilterError{Name: filter.name, JQL: filter.jql, Err: err}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check JIRA %s: %w", filter.name, err)
+		}
+	}
+
+	return nil
+}
+
+// dryRunSearch runs a search returning no issues, only to check that the JQL is accepted
+func (p *Provider) dryRunSearch(ctx context.Context, jql string) error {
+	params := url.Values{}
+	params.Set("jql", jql)
+	params.Set("maxResults", "0")
+
+	var result struct{}
+	if !p.config.ServerMode {
+		err := p.makeRequest(ctx, p.apiURL("search/jql?"+params.Encode()), &result)
+		var apiErr *apiError
+		if err == nil || !errors.As(err, &apiErr) || (apiErr.StatusCode != http.StatusNotFound && apiErr.StatusCode != http.StatusGone) {
+			return err
+		}
+	}
+
+	// Jira Server / Data Center and older sites only have the legacy endpoint
+	return p.makeRequest(ctx, p.apiURL("search?"+params.Encode()), &result)
+}
@@ -0,0 +1,191 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestProvider_FilterPrecedence(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          provider.Config
+		expectedSummary string
+		expectedTodo    string
+	}{
+		{"shared filter", provider.Config{Filter: "project = WEB"}, "project = WEB", "project = WEB"},
+		{"summary override", provider.Config{Filter: "project = WEB", SummaryFilter: "project in (WEB, API)"}, "project in (WEB, API)", "project = WEB"},
+		{"todo override", provider.Config{Filter: "project = WEB", TodoFilter: "status != Blocked"}, "project = WEB", "status != Blocked"},
+		{"overrides without shared filter", provider.Config{SummaryFilter: "a = 1", TodoFilter: "b = 2"}, "a = 1", "b = 2"},
+		{"no filters", provider.Config{}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(tt.config)
+			if got := p.summaryFilter(); got != tt.expectedSummary {
+				t.Errorf("Expected summary filter '%s', got '%s'", tt.expectedSummary, got)
+			}
+			if got := p.todoFilter(); got != tt.expectedTodo {
+				t.Errorf("Expected todo filter '%s', got '%s'", tt.expectedTodo, got)
+			}
+		})
+	}
+}
+
+func TestProvider_Queries_UseCommandFilters(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("jql"))
+		_, _ = fmt.Fprint(w, `{"issues":[],"isLast":true}`)
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:         "test@example.com",
+		Token:         "testtoken",
+		URL:           server.URL,
+		Enabled:       true,
+		Filter:        "shared = 1",
+		SummaryFilter: "summary = 1",
+		TodoFilter:    "status != Blocked",
+	})
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	if _, err := p.getUpdatedIssues(context.Background(), fixedAccountID("me"), from, from.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := p.GetAssignedTickets(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("Expected 2 queries, got %d", len(queries))
+	}
+	if !strings.Contains(queries[0], "AND (summary = 1)") || strings.Contains(queries[0], "shared") {
+		t.Errorf("Expected summary query to use the summary filter, got: %s", queries[0])
+	}
+	if !strings.Contains(queries[1], "AND (status != Blocked)") || strings.Contains(queries[1], "shared") {
+		t.Errorf("Expected todo query to use the todo filter, got: %s", queries[1])
+	}
+}
+
+func TestProvider_GetAssignedTickets_ExcludedStatuses(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []string
+		expectedJQL string
+	}{
+		{
+			name:        "done status category by default",
+			expectedJQL: "assignee = currentUser() AND statusCategory != Done ORDER BY updated DESC",
+		},
+		{
+			name:        "custom statuses",
+			statuses:    []string{"Released", "Closed"},
+			expectedJQL: `assignee = currentUser() AND status NOT IN ("Released", "Closed") ORDER BY updated DESC`,
+		},
+		{
+			name:        "spaces and quotes",
+			statuses:    []string{"Won't Do", `Shipped "to prod"`, `Back\slash`},
+			expectedJQL: `assignee = currentUser() AND status NOT IN ("Won't Do", "Shipped \"to prod\"", "Back\\slash") ORDER BY updated DESC`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var jql string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				jql = r.URL.Query().Get("jql")
+				_, _ = fmt.Fprint(w, `{"issues":[],"isLast":true}`)
+			}))
+			defer server.Close()
+
+			p := NewProvider(provider.Config{
+				Email:            "test@example.com",
+				Token:            "testtoken",
+				URL:              server.URL,
+				Enabled:          true,
+				ExcludedStatuses: tt.statuses,
+			})
+
+			if _, err := p.GetAssignedTickets(context.Background()); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if jql != tt.expectedJQL {
+				t.Errorf("Expected JQL %s, got %s", tt.expectedJQL, jql)
+			}
+		})
+	}
+}
+
+func TestProvider_ValidateFilters(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		if r.URL.Query().Get("maxResults") != "0" {
+			t.Errorf("Expected a dry-run search with maxResults=0, got %s", r.URL.RawQuery)
+		}
+		if strings.Contains(r.URL.Query().Get("jql"), "unauthorized") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.Contains(r.URL.Query().Get("jql"), "statuss") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"errorMessages":["Field 'statuss' does not exist or you do not have permission to view it."],"errors":{}}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"issues":[],"isLast":true}`)
+	}))
+	defer server.Close()
+
+	config := provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+		Filter:  "project = WEB",
+	}
+
+	if err := NewProvider(config).ValidateFilters(context.Background()); err != nil {
+		t.Fatalf("Expected valid filters, got: %v", err)
+	}
+
+	config.TodoFilter = "statuss != Blocked"
+	err := NewProvider(config).ValidateFilters(context.Background())
+	if err == nil {
+		t.Fatal("Expected error for invalid JQL, got nil")
+	}
+	for _, want := range []string{"todo_filter", "status 400", "Field 'statuss' does not exist"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain '%s', got: %v", want, err)
+		}
+	}
+	var filterErr *FilterError
+	if !errors.As(err, &filterErr) || filterErr.Name != "todo_filter" {
+		t.Errorf("Expected a filter error for todo_filter, got: %v", err)
+	}
+
+	// Other errors don't mean the filter is invalid
+	config.TodoFilter = "project = unauthorized"
+	err = NewProvider(config).ValidateFilters(context.Background())
+	if err == nil || errors.As(err, &filterErr) {
+		t.Errorf("Expected an error other than a filter error, got: %v", err)
+	}
+
+	// No filters means no requests
+	requests = nil
+	if err := NewProvider(provider.Config{Email: "a", Token: "b", URL: server.URL, Enabled: true}).ValidateFilters(context.Background()); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("Expected no requests without filters, got %d", len(requests))
+	}
+}
@@ -0,0 +1,188 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"daily/internal/activity"
+)
+
+// jiraAuthor identifies the author of a comment or changelog entry
+type jiraAuthor struct {
+	AccountID   string `json:"accountId"`
+	Key         string `json:"key"`  // Jira Server / Data Center user key
+	Name        string `json:"name"` // Jira Server / Data Center username
+	DisplayName string `json:"displayName"`
+}
+
+// id returns the identifier used to match users: the Cloud account ID, or the
+// user key (then username) on Jira Server / Data Center which has no account IDs
+func (a jiraAuthor) id() string {
+	switch {
+	case a.AccountID != "":
+		return a.AccountID
+	case a.Key != "":
+		return a.Key
+	default:
+		return a.Name
+	}
+}
+
+// changelogEntry is a single change history entry of an issue
+type changelogEntry struct {
+	ID      string     `json:"id"`
+	Author  jiraAuthor `json:"author"`
+	Created string     `json:"created"`
+	Items   []struct {
+		Field      string `json:"field"`
+		FromString string `json:"fromString"`
+		ToString   string `json:"toString"`
+	} `json:"items"`
+}
+
+// accountIDFunc lazily resolves the current user's account ID
+type accountIDFunc func() (string, error)
+
+// getCurrentAccountID returns the account ID (or user key on Jira Server) of the authenticated user
+func (p *Provider) getCurrentAccountID(ctx context.Context) (string, error) {
+	var myself jiraAuthor
+	if err := p.makeRequest(ctx, p.apiURL("myself"), &myself); err != nil {
+		return "", err
+	}
+
+	return myself.id(), nil
+}
+
+// durationClause formats a JQL date range covering from and to
+func (p *Provider) durationClause(from, to time.Time) string {
+	fromDate, toDate := p.jqlDateRange(from, to)
+	return fmt.Sprintf("\"%s\", \"%s\"", fromDate, toDate)
+}
+
+// withFilter appends a filter (see summaryFilter and todoFilter) to a JQL query. The ordering
+// is left to the caller, as JQL only accepts a single ORDER BY.
+func withFilter(jql, filter string) string {
+	if filter == "" {
+		return jql
+	}
+	return fmt.Sprintf("%s AND (%s)", jql, filter)
+}
+
+// getTransitions returns status changes made by the current user in the time range
+func (p *Provider) getTransitions(ctx context.Context, currentAccountID accountIDFunc, from, to time.Time) ([]activity.Activity, error) {
+	jql := withFilter(fmt.Sprintf("status CHANGED BY currentUser() DURING (%s)", p.durationClause(from, to)), p.summaryFilter()) + " ORDER BY updated DESC"
+
+	issues, err := p.searchIssues(ctx, jql)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	accountID, err := currentAccountID()
+	if err != nil {
+		return nil, err
+	}
+
+	var activities []activity.Activity
+	for _, issue := range issues {
+		changelog, err := p.getChangelog(ctx, issue.Key, from)
+		if err != nil {
+			continue // Skip issues whose history can't be read
+		}
+
+		for _, entry := range changelog {
+			if entry.Author.id() != accountID {
+				continue
+			}
+
+			created, err := p.parseJIRATime(entry.Created)
+			if err != nil || created.Before(from) || created.After(to) {
+				continue
+			}
+
+			for _, item := range entry.Items {
+				if item.Field != "status" {
+					continue
+				}
+
+				activities = append(activities, activity.Activity{
+					ID:          fmt.Sprintf("jira-%s-transition-%s", issue.Key, entry.ID),
+					Type:        activity.ActivityTypeJiraTicket,
+					Title:       fmt.Sprintf("%s: %s", issue.Key, issue.Fields.Summary),
+					Description: fmt.Sprintf("Transitioned %s to %s", issue.Key, item.ToString),
+					URL:         fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(p.config.URL, "/"), issue.Key),
+					Platform:    "jira",
+					Timestamp:   created,
+					Tags:        []string{issue.Key, item.ToString, "transition"},
+					Details:     issueDetails(issue.Key, item.ToString),
+				})
+			}
+		}
+	}
+
+	return activities, nil
+}
+
+// getComments returns comments written by the current user in the time range
+func (p *Provider) getComments(ctx context.Context, currentAccountID accountIDFunc, from, to time.Time) ([]activity.Activity, error) {
+	jql := withFilter(fmt.Sprintf("issuekey IN updatedBy(currentUser(), %s)", p.durationClause(from, to)), p.summaryFilter()) + " ORDER BY updated DESC"
+
+	issues, err := p.searchIssues(ctx, jql)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	accountID, err := currentAccountID()
+	if err != nil {
+		return nil, err
+	}
+
+	var activities []activity.Activity
+	for _, issue := range issues {
+		var comments struct {
+			Comments []struct {
+				ID      string     `json:"id"`
+				Author  jiraAuthor `json:"author"`
+				Created string     `json:"created"`
+			} `json:"comments"`
+		}
+
+		commentsURL := p.apiURL(fmt.Sprintf("issue/%s/comment?orderBy=-created&maxResults=100", url.PathEscape(issue.Key)))
+		if err := p.makeRequest(ctx, commentsURL, &comments); err != nil {
+			continue // Skip issues whose comments can't be read
+		}
+
+		for _, comment := range comments.Comments {
+			if comment.Author.id() != accountID {
+				continue
+			}
+
+			created, err := p.parseJIRATime(comment.Created)
+			if err != nil || created.Before(from) || created.After(to) {
+				continue
+			}
+
+			activities = append(activities, activity.Activity{
+				ID:          fmt.Sprintf("jira-%s-comment-%s", issue.Key, comment.ID),
+				Type:        activity.ActivityTypeJiraTicket,
+				Title:       fmt.Sprintf("%s: %s", issue.Key, issue.Fields.Summary),
+				Description: fmt.Sprintf("Commented on %s", issue.Key),
+				URL:         fmt.Sprintf("%s/browse/%s?focusedCommentId=%s", strings.TrimSuffix(p.config.URL, "/"), issue.Key, comment.ID),
+				Platform:    "jira",
+				Timestamp:   created,
+				Tags:        []string{issue.Key, "comment"},
+				Details:     issueDetails(issue.Key, ""),
+			})
+		}
+	}
+
+	return activities, nil
+}
@@ -0,0 +1,118 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+// newInteractionsServer serves a JIRA instance where "me" transitioned PROJ-12 and commented on PROJ-34
+func newInteractionsServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/myself":
+			_, _ = fmt.Fprint(w, `{"accountId":"me"}`)
+		case "/rest/api/3/search/jql":
+			jql := r.URL.Query().Get("jql")
+			switch {
+			case strings.Contains(jql, "status CHANGED BY currentUser()"):
+				_, _ = fmt.Fprintf(w, `{"issues":[%s],"isLast":true}`, newTestIssue("PROJ-12"))
+			case strings.Contains(jql, "updatedBy(currentUser()"):
+				_, _ = fmt.Fprintf(w, `{"issues":[%s],"isLast":true}`, newTestIssue("PROJ-34"))
+			default:
+				_, _ = fmt.Fprint(w, `{"issues":[],"isLast":true}`)
+			}
+		case "/rest/api/3/issue/PROJ-12/changelog":
+			_, _ = fmt.Fprint(w, `{"values":[
+				{"id":"100","author":{"accountId":"me"},"created":"2024-01-15T10:00:00.000+0000","items":[{"field":"status","toString":"In Review"}]},
+				{"id":"101","author":{"accountId":"someone-else"},"created":"2024-01-15T11:00:00.000+0000","items":[{"field":"status","toString":"Done"}]},
+				{"id":"102","author":{"accountId":"me"},"created":"2024-01-15T12:00:00.000+0000","items":[{"field":"assignee","toString":"Bob"}]},
+				{"id":"103","author":{"accountId":"me"},"created":"2024-01-10T12:00:00.000+0000","items":[{"field":"status","toString":"In Progress"}]}
+			]}`)
+		case "/rest/api/3/issue/PROJ-34/comment":
+			_, _ = fmt.Fprint(w, `{"comments":[
+				{"id":"200","author":{"accountId":"me"},"created":"2024-01-15T09:30:00.000+0000"},
+				{"id":"201","author":{"accountId":"someone-else"},"created":"2024-01-15T09:45:00.000+0000"}
+			]}`)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestProvider_GetActivities_TransitionsAndComments(t *testing.T) {
+	server := newInteractionsServer(t)
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:              "test@example.com",
+		Token:              "testtoken",
+		URL:                server.URL,
+		Enabled:            true,
+		IncludeTransitions: true,
+		IncludeComments:    true,
+	})
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	activities, err := p.GetActivities(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	descriptions := make(map[string]bool)
+	for _, act := range activities {
+		descriptions[act.Description] = true
+		if project := act.Detail(activity.DetailProject); project != "PROJ" {
+			t.Errorf("Expected project detail 'PROJ' for '%s', got '%s'", act.Description, project)
+		}
+	}
+
+	if len(activities) != 2 {
+		t.Errorf("Expected 2 activities, got %d: %v", len(activities), descriptions)
+	}
+	if !descriptions["Transitioned PROJ-12 to In Review"] {
+		t.Error("Expected transition activity for PROJ-12")
+	}
+	if !descriptions["Commented on PROJ-34"] {
+		t.Error("Expected comment activity for PROJ-34")
+	}
+}
+
+func TestProvider_GetActivities_InteractionsDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/search/jql" {
+			t.Errorf("Expected only the search endpoint to be called, got %s", r.URL.Path)
+		}
+		if strings.Contains(r.URL.Query().Get("jql"), "CHANGED BY") {
+			t.Error("Expected transitions search to be skipped when disabled")
+		}
+		_, _ = fmt.Fprint(w, `{"issues":[],"isLast":true}`)
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 0 {
+		t.Errorf("Expected no activities, got %d", len(activities))
+	}
+}
@@ -3,26 +3,43 @@ package jira
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"daily/internal/activity"
+	"daily/internal/metrics"
 	"daily/internal/provider"
 )
 
 type Provider struct {
-	config provider.Config
-	client *http.Client
+	config   provider.Config
+	client   *http.Client
+	loc      *time.Location // Timezone of JIRA dates, loaded once from the config
+	warnings []string       // Parts of the last GetActivities that failed
 }
 
 func NewProvider(config provider.Config) *Provider {
+	loc := time.Local
+	if config.Timezone != "" {
+		// Unknown timezones are rejected by the config validation
+		if configured, err := time.LoadLocation(config.Timezone); err == nil {
+			loc = configured
+		}
+	}
+
 	return &Provider{
 		config: config,
+		loc:    loc,
 		client: &http.Client{
-			Timeout: 60 * time.Second, // Increased timeout for API calls
+			Timeout:   60 * time.Second, // Increased timeout for API calls
+			Transport: metrics.NewTransport("jira"),
 		},
 	}
 }
@@ -32,10 +49,69 @@ func (p *Provider) Name() string {
 }
 
 func (p *Provider) IsConfigured() bool {
-	return p.config.Enabled &&
-		p.config.Token != "" &&
-		p.config.Email != "" &&
-		p.config.URL != ""
+	if !p.config.Enabled || p.config.Token == "" || p.config.URL == "" {
+		return false
+	}
+
+	switch p.authType() {
+	case authTypeBasic:
+		return p.config.Email != ""
+	case authTypeBearer:
+		// Personal Access Tokens identify the user on their own
+		return true
+	default:
+		return false
+	}
+}
+
+// ConfigSpec describes the configuration fields of the JIRA provider
+func (p *Provider) ConfigSpec() []provider.ConfigField {
+	return []provider.ConfigField{
+		{Name: "url", Required: true, Description: "JIRA instance URL"},
+		{Name: "email", Required: true, Description: "JIRA account email (not needed with bearer auth)"},
+		{Name: "token", Required: true, Secret: true, Description: "JIRA API token, or Personal Access Token with bearer auth"},
+		{Name: "token_cmd", Description: "Command printing the token, overriding token, e.g. pass show jira/token"},
+		{Name: "filter", Description: "JQL filter added to every query"},
+		{Name: "summary_filter", Description: "JQL filter for summaries, overriding filter"},
+		{Name: "todo_filter", Description: "JQL filter for todos and mentions, overriding filter"},
+		{Name: "auth_type", Description: "basic (default) or bearer"},
+		{Name: "server_mode", Description: "Use the v2 REST API of Jira Server / Data Center"},
+		{Name: "max_results", Description: "Maximum number of issues fetched per search (default 200)"},
+		{Name: "include_transitions", Description: "Include status transitions made by the current user"},
+		{Name: "include_comments", Description: "Include comments written by the current user"},
+		{Name: "include_watched", Description: "List recently updated issues you watch in todos"},
+		{Name: "include_reported", Description: "List recently updated issues you reported in todos"},
+		{Name: "rollup_subtasks", Description: "Group assigned subtasks under their parent issue in todos"},
+		{Name: "excluded_statuses", Description: "Statuses of tickets left out of todos (default the Done status category)"},
+		{Name: "sprint_field", Description: "Custom field holding the sprint (default customfield_10020)"},
+		{Name: "status_sections", Description: "Status names or categories mapped to todo sections"},
+		{Name: "epic_link_field", Description: "Custom field linking issues to their epic on older instances (default customfield_10014)"},
+		{Name: "timezone", Description: "IANA timezone used for JIRA dates (default local)"},
+	}
+}
+
+// Supported authentication schemes
+const (
+	authTypeBasic  = "basic"
+	authTypeBearer = "bearer"
+)
+
+// authType returns the configured authentication scheme, defaulting to basic auth
+func (p *Provider) authType() string {
+	if p.config.AuthType == "" {
+		return authTypeBasic
+	}
+	return strings.ToLower(p.config.AuthType)
+}
+
+// apiURL builds a REST API URL for the given path, using the v2 API on Jira Server / Data Center
+// and the v3 API on Jira Cloud
+func (p *Provider) apiURL(path string) string {
+	version := "3"
+	if p.config.ServerMode {
+		version = "2"
+	}
+	return fmt.Sprintf("%s/rest/api/%s/%s", strings.TrimSuffix(p.config.URL, "/"), version, path)
 }
 
 func (p *Provider) GetActivities(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
@@ -44,61 +120,105 @@ func (p *Provider) GetActivities(ctx context.Context, from, to time.Time) ([]act
 	}
 
 	activities := make([]activity.Activity, 0)
+	p.warnings = nil
+
+	// Days of the range are days of the configured timezone
+	from, to = p.dayBoundary(from), p.dayBoundary(to)
+
+	// The current user's account ID is only fetched once a sub-fetch needs it
+	var accountID string
+	var accountErr error
+	currentAccountID := func() (string, error) {
+		if accountID == "" && accountErr == nil {
+			id, err := p.getCurrentAccountID(ctx)
+			if err != nil {
+				accountErr = fmt.Errorf("failed to fetch current user: %w", err)
+			}
+			accountID = id
+		}
+		return accountID, accountErr
+	}
 
 	// Get issues updated in the time range - continue even if this fails
-	issues, err := p.getUpdatedIssues(ctx, from, to)
+	issues, err := p.getUpdatedIssues(ctx, currentAccountID, from, to)
 	if err != nil {
-		// Log error but continue with empty results - warning handled by aggregator
-		fmt.Printf("JIRA: error fetching updated issues: %v", err)
+		p.warn("updated issues", err)
 	} else {
 		activities = append(activities, issues...)
 	}
 
+	// Get issues resolved in the time range - continue even if this fails
+	resolved, err := p.getResolvedIssues(ctx, from, to)
+	if err != nil {
+		p.warn("resolved issues", err)
+	} else {
+		// A resolved issue is reported once, as resolved rather than updated
+		activities = withoutResolvedIssues(activities, resolved)
+		activities = append(activities, resolved...)
+	}
+
+	// Get work logged in the time range - continue even if this fails
+	worklogs, err := p.getWorklogs(ctx, currentAccountID, from, to)
+	if err != nil {
+		p.warn("worklogs", err)
+	} else {
+		activities = append(activities, worklogs...)
+	}
+
+	// Comments and transitions rely on JQL functions not every instance supports, so they are opt-in
+	if p.config.IncludeTransitions {
+		transitions, err := p.getTransitions(ctx, currentAccountID, from, to)
+		if err != nil {
+			p.warn("transitions", err)
+		} else {
+			activities = append(activities, transitions...)
+		}
+	}
+
+	if p.config.IncludeComments {
+		comments, err := p.getComments(ctx, currentAccountID, from, to)
+		if err != nil {
+			p.warn("comments", err)
+		} else {
+			activities = append(activities, comments...)
+		}
+	}
+
 	return activities, nil
 }
 
-func (p *Provider) getUpdatedIssues(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
+// warn records that a part of GetActivities failed, reported by the aggregator
+func (p *Provider) warn(part string, err error) {
+	p.warnings = append(p.warnings, fmt.Sprintf("Failed to fetch %s: %v", part, err))
+}
+
+// Warnings describes the parts of the last GetActivities that failed
+func (p *Provider) Warnings() []string {
+	return p.warnings
+}
+
+// getUpdatedIssues returns the assigned issues updated in the time range. Each issue is dated
+// and described by the latest change the current user made to it when the changelog shows
+// one, so changes made by others later in the day don't move it.
+func (p *Provider) getUpdatedIssues(ctx context.Context, currentAccountID accountIDFunc, from, to time.Time) ([]activity.Activity, error) {
 	// Build JQL query to find issues updated in the time range
-	// Use proper from and to dates - to is exclusive so it's the next day
-	jql := fmt.Sprintf("assignee = currentUser() AND updated >= \"%s\" AND updated < \"%s\"",
-		from.Format("2006-01-02"),
-		to.Format("2006-01-02"))
+	fromDate, toDate := p.jqlDateRange(from, to)
+	jql := fmt.Sprintf("assignee = currentUser() AND updated >= \"%s\" AND updated < \"%s\"", fromDate, toDate)
 
 	// Add filter if configured
-	if p.config.Filter != "" {
-		jql = fmt.Sprintf("%s AND (%s)", jql, p.config.Filter)
+	if filter := p.summaryFilter(); filter != "" {
+		jql = fmt.Sprintf("%s AND (%s)", jql, filter)
 	}
 
 	jql = fmt.Sprintf("%s ORDER BY updated DESC", jql)
 
-	// URL encode the JQL query
-	searchURL := fmt.Sprintf("%s/rest/api/3/search?jql=%s&fields=key,summary,status,updated,assignee",
-		strings.TrimSuffix(p.config.URL, "/"),
-		url.QueryEscape(jql))
-
-	var searchResult struct {
-		Issues []struct {
-			Key    string `json:"key"`
-			Fields struct {
-				Summary string `json:"summary"`
-				Updated string `json:"updated"` // Keep as string to handle different timezone formats
-				Status  struct {
-					Name string `json:"name"`
-				} `json:"status"`
-				Assignee struct {
-					DisplayName string `json:"displayName"`
-				} `json:"assignee"`
-			} `json:"fields"`
-		} `json:"issues"`
-		Total int `json:"total"`
-	}
-
-	if err := p.makeRequest(ctx, searchURL, &searchResult); err != nil {
+	issues, err := p.searchIssuesExpand(ctx, jql, "changelog")
+	if err != nil {
 		return nil, err
 	}
 
 	var activities []activity.Activity
-	for _, issue := range searchResult.Issues {
+	for _, issue := range issues {
 		// Parse the updated time with flexible timezone formats
 		updatedTime, err := p.parseJIRATime(issue.Fields.Updated)
 		if err != nil {
@@ -110,26 +230,278 @@ func (p *Provider) getUpdatedIssues(ctx context.Context, from, to time.Time) ([]
 			continue
 		}
 
+		// Fall back to the update time when the changelog doesn't show a change of the user
+		timestamp := updatedTime
+		description := fmt.Sprintf("Status: %s", issue.Fields.Status.Name)
+		if change, changedAt, ok := p.userChange(ctx, issue, currentAccountID, from, to); ok {
+			timestamp = changedAt
+			if changeDescription := describeChange(change); changeDescription != "" {
+				description = changeDescription
+			}
+		}
+
+		epic := p.issueEpic(issue)
 		activities = append(activities, activity.Activity{
 			ID:          fmt.Sprintf("jira-%s", issue.Key),
 			Type:        activity.ActivityTypeJiraTicket,
 			Title:       fmt.Sprintf("%s: %s", issue.Key, issue.Fields.Summary),
-			Description: fmt.Sprintf("Status: %s", issue.Fields.Status.Name),
+			Description: description,
 			URL:         fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(p.config.URL, "/"), issue.Key),
 			Platform:    "jira",
-			Timestamp:   updatedTime,
-			Tags:        []string{issue.Key, issue.Fields.Status.Name},
+			Timestamp:   timestamp,
+			Tags:        withEpicTag(withIssueType([]string{issue.Key, issue.Fields.Status.Name}, issue), epic),
+			Epic:        epic,
+			IssueType:   issue.Fields.IssueType.Name,
+			Details:     issueDetails(issue.Key, issue.Fields.Status.Name),
 		})
 	}
 
 	return activities, nil
 }
 
+const (
+	// searchFields lists the issue fields requested from the search API
+	searchFields = "key,summary,status,issuetype,updated,assignee,priority,duedate,parent,resolution,resolutiondate"
+
+	// pageSize is the number of issues requested per search page
+	pageSize = 50
+
+	// defaultMaxResults caps the total number of issues fetched per search
+	defaultMaxResults = 200
+)
+
+// jiraIssue is the subset of a JIRA issue returned by the search endpoints
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Updated string `json:"updated"` // Keep as string to handle different timezone formats
+		Status  struct {
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"` // new, indeterminate or done
+			} `json:"statusCategory"`
+		} `json:"status"`
+		IssueType struct {
+			Name    string `json:"name"` // e.g. Bug, Story, Task
+			Subtask bool   `json:"subtask"`
+		} `json:"issuetype"`
+		Assignee struct {
+			AccountID   string `json:"accountId"`
+			DisplayName string `json:"displayName"`
+		} `json:"assignee"`
+		Priority struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+		DueDate    string `json:"duedate"`
+		Resolution *struct {
+			Name string `json:"name"` // e.g. Fixed, Won't Do
+		} `json:"resolution"`
+		ResolutionDate string `json:"resolutiondate"`
+		Parent         *struct {
+			Key    string `json:"key"`
+			Fields struct {
+				Summary string `json:"summary"`
+				Status  struct {
+					Name           string `json:"name"`
+					StatusCategory struct {
+						Key string `json:"key"`
+					} `json:"statusCategory"`
+				} `json:"status"`
+				IssueType struct {
+					Name string `json:"name"`
+				} `json:"issuetype"`
+			} `json:"fields"`
+		} `json:"parent"`
+	} `json:"fields"`
+
+	// Changelog is only returned when expanded, and may hold only part of the history
+	Changelog *issueChangelog `json:"changelog"`
+
+	// rawFields keeps every returned field so custom fields such as the sprint can be read
+	rawFields map[string]json.RawMessage
+}
+
+func (i *jiraIssue) UnmarshalJSON(data []byte) error {
+	type plainIssue jiraIssue
+	if err := json.Unmarshal(data, (*plainIssue)(i)); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	i.rawFields = raw.Fields
+
+	// Summaries are only displayed, so their markup is cleaned once here
+	names := map[string]string{i.Fields.Assignee.AccountID: i.Fields.Assignee.DisplayName}
+	i.Fields.Summary = displayText(i.Fields.Summary, names)
+	if i.Fields.Parent != nil {
+		i.Fields.Parent.Fields.Summary = displayText(i.Fields.Parent.Fields.Summary, names)
+	}
+	return nil
+}
+
+// apiError is returned when the JIRA API responds with a non-200 status
+type apiError struct {
+	StatusCode int
+	Messages   []string // Error messages returned by JIRA, e.g. JQL syntax errors
+}
+
+func (e *apiError) Error() string {
+	if len(e.Messages) > 0 {
+		return fmt.Sprintf("JIRA API request failed with status %d: %s", e.StatusCode, strings.Join(e.Messages, "; "))
+	}
+	return fmt.Sprintf("JIRA API request failed with status %d", e.StatusCode)
+}
+
+// parseAPIError builds an apiError from an error response, keeping the messages JIRA returns
+func parseAPIError(resp *http.Response) *apiError {
+	apiErr := &apiError{StatusCode: resp.StatusCode}
+
+	var body struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&body); err != nil {
+		return apiErr
+	}
+
+	apiErr.Messages = append(apiErr.Messages, body.ErrorMessages...)
+	fields := make([]string, 0, len(body.Errors))
+	for field := range body.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		apiErr.Messages = append(apiErr.Messages, fmt.Sprintf("%s: %s", field, body.Errors[field]))
+	}
+	return apiErr
+}
+
+// maxResults returns the configured cap on fetched issues
+func (p *Provider) maxResults() int {
+	if p.config.MaxResults > 0 {
+		return p.config.MaxResults
+	}
+	return defaultMaxResults
+}
+
+// searchIssues runs a JQL search using the search/jql endpoint, falling back to
+// the legacy search endpoint when it is not available (e.g. Jira Server)
+func (p *Provider) searchIssues(ctx context.Context, jql string) ([]jiraIssue, error) {
+	return p.searchIssuesExpand(ctx, jql, "")
+}
+
+// searchIssuesExpand runs searchIssues, expanding the given entities of each issue (e.g. "changelog")
+func (p *Provider) searchIssuesExpand(ctx context.Context, jql, expand string) ([]jiraIssue, error) {
+	// Jira Server / Data Center only has the legacy endpoint
+	if p.config.ServerMode {
+		return p.searchLegacy(ctx, jql, expand)
+	}
+
+	issues, err := p.searchJQL(ctx, jql, expand)
+	if err == nil {
+		return issues, nil
+	}
+
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusGone) {
+		return p.searchLegacy(ctx, jql, expand)
+	}
+	return nil, err
+}
+
+// searchJQL paginates through the search/jql endpoint using nextPageToken
+func (p *Provider) searchJQL(ctx context.Context, jql, expand string) ([]jiraIssue, error) {
+	limit := p.maxResults()
+	var issues []jiraIssue
+	nextPageToken := ""
+
+	for len(issues) < limit {
+		params := url.Values{}
+		params.Set("jql", jql)
+		params.Set("fields", p.searchFieldList())
+		params.Set("maxResults", strconv.Itoa(min(pageSize, limit-len(issues))))
+		if expand != "" {
+			params.Set("expand", expand)
+		}
+		if nextPageToken != "" {
+			params.Set("nextPageToken", nextPageToken)
+		}
+
+		var page struct {
+			Issues        []jiraIssue `json:"issues"`
+			NextPageToken string      `json:"nextPageToken"`
+			IsLast        bool        `json:"isLast"`
+		}
+		searchURL := p.apiURL("search/jql?" + params.Encode())
+		if err := p.makeRequest(ctx, searchURL, &page); err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, page.Issues...)
+		if page.IsLast || page.NextPageToken == "" || len(page.Issues) == 0 {
+			break
+		}
+		nextPageToken = page.NextPageToken
+	}
+
+	return truncateIssues(issues, limit), nil
+}
+
+// searchLegacy paginates through the legacy search endpoint using startAt
+func (p *Provider) searchLegacy(ctx context.Context, jql, expand string) ([]jiraIssue, error) {
+	limit := p.maxResults()
+	var issues []jiraIssue
+
+	for len(issues) < limit {
+		requested := min(pageSize, limit-len(issues))
+		params := url.Values{}
+		params.Set("jql", jql)
+		params.Set("fields", p.searchFieldList())
+		params.Set("startAt", strconv.Itoa(len(issues)))
+		params.Set("maxResults", strconv.Itoa(requested))
+		if expand != "" {
+			params.Set("expand", expand)
+		}
+
+		var page struct {
+			Issues []jiraIssue `json:"issues"`
+			Total  int         `json:"total"`
+		}
+		searchURL := p.apiURL("search?" + params.Encode())
+		if err := p.makeRequest(ctx, searchURL, &page); err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, page.Issues...)
+		// Some sites omit total, so a short page also marks the end
+		if len(page.Issues) < requested || (page.Total > 0 && len(issues) >= page.Total) {
+			break
+		}
+	}
+
+	return truncateIssues(issues, limit), nil
+}
+
+func truncateIssues(issues []jiraIssue, limit int) []jiraIssue {
+	if len(issues) > limit {
+		return issues[:limit]
+	}
+	return issues
+}
+
+// parseJIRATime parses a JIRA timestamp and returns it in the configured timezone
 func (p *Provider) parseJIRATime(timeStr string) (time.Time, error) {
 	// Try different time formats that JIRA might use
 	formats := []string{
 		"2006-01-02T15:04:05.000Z0700", // "2025-08-20T18:41:17.540+0200"
 		"2006-01-02T15:04:05.000-0700", // "2025-08-20T18:41:17.540-0200"
+		"2006-01-02T15:04:05Z0700",     // "2025-08-20T18:41:17+0200", any fraction digits (Jira Server)
 		time.RFC3339,                   // "2006-01-02T15:04:05Z07:00"
 		"2006-01-02T15:04:05.000Z",     // "2025-08-20T18:41:17.540Z"
 		"2006-01-02T15:04:05Z",         // "2025-08-20T18:41:17Z"
@@ -137,21 +509,56 @@ func (p *Provider) parseJIRATime(timeStr string) (time.Time, error) {
 
 	for _, format := range formats {
 		if t, err := time.Parse(format, timeStr); err == nil {
-			return t, nil
+			return t.In(p.location()), nil
 		}
 	}
 
 	return time.Time{}, fmt.Errorf("unable to parse time: %s", timeStr)
 }
 
+// location returns the timezone used for JIRA dates: the configured timezone, or local time
+func (p *Provider) location() *time.Location {
+	if p.loc == nil {
+		return time.Local
+	}
+	return p.loc
+}
+
+// dayBoundary returns a day boundary of a range, i.e. a midnight computed in local time, as
+// the same midnight in the configured timezone. Other times, e.g. now, are instants and are
+// returned as they are.
+func (p *Provider) dayBoundary(t time.Time) time.Time {
+	year, month, day := t.Date()
+	if midnight := time.Date(year, month, day, 0, 0, 0, 0, t.Location()); !t.Equal(midnight) {
+		return t
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, p.location())
+}
+
+// jqlDateRange returns the JQL dates bounding the time range. JQL dates are interpreted in the
+// timezone of the JIRA user profile, which may differ from ours, so the range is widened by a
+// day on each side; callers must check the exact range on the returned timestamps.
+func (p *Provider) jqlDateRange(from, to time.Time) (string, string) {
+	loc := p.location()
+	return from.In(loc).AddDate(0, 0, -1).Format("2006-01-02"), to.In(loc).AddDate(0, 0, 1).Format("2006-01-02")
+}
+
 func (p *Provider) makeRequest(ctx context.Context, url string, result any) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	// JIRA uses basic auth with email and API token
-	req.SetBasicAuth(p.config.Email, p.config.Token)
+	switch p.authType() {
+	case authTypeBasic:
+		// Jira Cloud uses basic auth with email and API token
+		req.SetBasicAuth(p.config.Email, p.config.Token)
+	case authTypeBearer:
+		// Jira Server / Data Center Personal Access Tokens are sent as bearer tokens
+		req.Header.Set("Authorization", "Bearer "+p.config.Token)
+	default:
+		return fmt.Errorf("unsupported JIRA auth type: %s", p.config.AuthType)
+	}
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
@@ -162,7 +569,7 @@ func (p *Provider) makeRequest(ctx context.Context, url string, result any) erro
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("JIRA API request failed with status %d", resp.StatusCode)
+		return parseAPIError(resp)
 	}
 
 	return json.NewDecoder(resp.Body).Decode(result)
@@ -174,40 +581,24 @@ func (p *Provider) GetAssignedTickets(ctx context.Context) ([]TodoItem, error) {
 		return nil, fmt.Errorf("JIRA provider not configured")
 	}
 
-	// JQL query to find tickets assigned to current user that are not in done/closed states
-	jql := "assignee = currentUser() AND status NOT IN (Done, Closed, Resolved)"
+	// JQL query to find tickets assigned to current user that are not done
+	jql := fmt.Sprintf("assignee = currentUser() AND %s", p.openStatusClause())
 
 	// Add filter if configured
-	if p.config.Filter != "" {
-		jql = fmt.Sprintf("%s AND (%s)", jql, p.config.Filter)
+	if filter := p.todoFilter(); filter != "" {
+		jql = fmt.Sprintf("%s AND (%s)", jql, filter)
 	}
 
 	jql = fmt.Sprintf("%s ORDER BY updated DESC", jql)
 
-	// URL encode the JQL query
-	searchURL := fmt.Sprintf("%s/rest/api/3/search?jql=%s&fields=key,summary,status,updated,assignee&maxResults=50",
-		strings.TrimSuffix(p.config.URL, "/"),
-		url.QueryEscape(jql))
-
-	var searchResult struct {
-		Issues []struct {
-			Key    string `json:"key"`
-			Fields struct {
-				Summary string `json:"summary"`
-				Updated string `json:"updated"`
-				Status  struct {
-					Name string `json:"name"`
-				} `json:"status"`
-			} `json:"fields"`
-		} `json:"issues"`
-	}
-
-	if err := p.makeRequest(ctx, searchURL, &searchResult); err != nil {
+	issues, err := p.searchIssues(ctx, jql)
+	if err != nil {
 		return nil, err
 	}
+	provenance := p.provenance(jql)
 
 	var todos []TodoItem
-	for _, issue := range searchResult.Issues {
+	for _, issue := range issues {
 		// Parse the updated time
 		updatedTime, err := p.parseJIRATime(issue.Fields.Updated)
 		if err != nil {
@@ -215,25 +606,110 @@ func (p *Provider) GetAssignedTickets(ctx context.Context) ([]TodoItem, error) {
 			updatedTime = time.Now()
 		}
 
+		// A malformed due date is treated as no due date
+		dueDate, _ := parseDueDate(issue.Fields.DueDate)
+
+		// A parent is listed for its assigned subtask, not by the search
+		parent := p.subtaskParent(issue)
+		if parent != nil {
+			parentProvenance := *provenance
+			parentProvenance.Source = fmt.Sprintf("Parent of the assigned subtask %s", issue.Key)
+			parent.Provenance = &parentProvenance
+		}
+
 		todos = append(todos, TodoItem{
 			ID:          fmt.Sprintf("jira-%s", issue.Key),
+			Key:         issue.Key,
 			Title:       fmt.Sprintf("%s: %s", issue.Key, issue.Fields.Summary),
 			Description: fmt.Sprintf("Status: %s", issue.Fields.Status.Name),
 			URL:         fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(p.config.URL, "/"), issue.Key),
 			UpdatedAt:   updatedTime,
-			Tags:        []string{issue.Key, issue.Fields.Status.Name},
+			Tags:        withEpicTag(withIssueType([]string{issue.Key, issue.Fields.Status.Name}, issue), p.issueEpic(issue)),
+			Priority:    issue.Fields.Priority.Name,
+			DueDate:     dueDate,
+			Sprint:      sprintName(issue.rawFields[p.sprintField()]),
+			IssueType:   issue.Fields.IssueType.Name,
+			Details:     issueDetails(issue.Key, issue.Fields.Status.Name),
+
+			StatusCategory: issue.Fields.Status.StatusCategory.Key,
+			StatusSection:  p.statusSection(issue.Fields.Status.Name, issue.Fields.Status.StatusCategory.Key),
+			Parent:         parent,
+			Provenance:     provenance,
 		})
 	}
 
 	return todos, nil
 }
 
+// provenance describes a todo search of the provider
+func (p *Provider) provenance(jql string) *provider.Provenance {
+	return &provider.Provenance{
+		Provider:  "jira",
+		Query:     jql,
+		Filter:    p.todoFilter(),
+		FetchedAt: time.Now(),
+	}
+}
+
+// subtaskParent returns the parent issue of a subtask as a todo item, nil for other issues.
+// Only the key, summary, status and type of the parent are returned by searches.
+func (p *Provider) subtaskParent(issue jiraIssue) *TodoItem {
+	parent := issue.Fields.Parent
+	if !issue.Fields.IssueType.Subtask || parent == nil || parent.Key == "" {
+		return nil
+	}
+
+	status := parent.Fields.Status
+	return &TodoItem{
+		ID:          fmt.Sprintf("jira-%s", parent.Key),
+		Key:         parent.Key,
+		Title:       fmt.Sprintf("%s: %s", parent.Key, parent.Fields.Summary),
+		Description: fmt.Sprintf("Status: %s", status.Name),
+		URL:         fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(p.config.URL, "/"), parent.Key),
+		Tags:        []string{parent.Key, status.Name},
+		IssueType:   parent.Fields.IssueType.Name,
+		Details:     issueDetails(parent.Key, status.Name),
+
+		StatusCategory: status.StatusCategory.Key,
+		StatusSection:  p.statusSection(status.Name, status.StatusCategory.Key),
+	}
+}
+
+// issueDetails returns the structured details of an issue: its project, from the prefix of
+// its key, and its status when known
+func issueDetails(key, status string) map[string]string {
+	project, _, _ := strings.Cut(key, "-")
+	return activity.NewDetails(activity.DetailProject, project, activity.DetailStatus, status)
+}
+
+// withIssueType appends the issue type of an issue, e.g. "Bug", to its tags
+func withIssueType(tags []string, issue jiraIssue) []string {
+	if issue.Fields.IssueType.Name == "" {
+		return tags
+	}
+	return append(tags, issue.Fields.IssueType.Name)
+}
+
 // TodoItem represents a single todo item (avoiding import cycles)
 type TodoItem struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	URL         string    `json:"url,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Tags        []string  `json:"tags,omitempty"`
+	ID          string     `json:"id"`
+	Key         string     `json:"key"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	URL         string     `json:"url,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Tags        []string   `json:"tags,omitempty"`
+	Priority    string     `json:"priority,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Sprint      string     `json:"sprint,omitempty"`
+	IssueType   string     `json:"issue_type,omitempty"` // e.g. Bug, Story, Task
+
+	Details map[string]string `json:"details,omitempty"` // Project and status, see activity.DetailKeys
+
+	StatusCategory string `json:"status_category,omitempty"` // new, indeterminate or done
+	StatusSection  string `json:"status_section,omitempty"`  // Todo section, e.g. "In Progress"
+
+	Parent *TodoItem `json:"parent,omitempty"` // Parent issue of a subtask
+
+	Provenance *provider.Provenance `json:"provenance,omitempty"` // Search that listed the item
 }
@@ -2,6 +2,10 @@ package jira
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -79,6 +83,36 @@ func TestProvider_IsConfigured(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "bearer auth without email",
+			config: provider.Config{
+				Token:    "testtoken",
+				URL:      "https://jira.example.com",
+				Enabled:  true,
+				AuthType: "bearer",
+			},
+			expected: true,
+		},
+		{
+			name: "bearer auth missing token",
+			config: provider.Config{
+				URL:      "https://jira.example.com",
+				Enabled:  true,
+				AuthType: "bearer",
+			},
+			expected: false,
+		},
+		{
+			name: "unknown auth type",
+			config: provider.Config{
+				Email:    "test@example.com",
+				Token:    "testtoken",
+				URL:      "https://example.atlassian.net",
+				Enabled:  true,
+				AuthType: "oauth",
+			},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -263,3 +297,220 @@ func TestProvider_ParseJIRATime(t *testing.T) {
 		})
 	}
 }
+
+func newTestIssue(key string) string {
+	return fmt.Sprintf(`{"key":%q,"fields":{"summary":"Summary %s","updated":"2024-01-15T10:00:00.000+0000","status":{"name":"In Progress"}}}`, key, key)
+}
+
+func TestProvider_GetAssignedTickets_Pagination(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/search/jql" {
+			t.Errorf("Expected search/jql endpoint, got %s", r.URL.Path)
+		}
+		requests = append(requests, r.URL.Query().Get("nextPageToken"))
+
+		switch r.URL.Query().Get("nextPageToken") {
+		case "":
+			_, _ = fmt.Fprintf(w, `{"issues":[%s,%s],"nextPageToken":"page2","isLast":false}`, newTestIssue("PROJ-1"), newTestIssue("PROJ-2"))
+		case "page2":
+			_, _ = fmt.Fprintf(w, `{"issues":[%s],"isLast":true}`, newTestIssue("PROJ-3"))
+		default:
+			t.Errorf("Unexpected page token %q", r.URL.Query().Get("nextPageToken"))
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	todos, err := p.GetAssignedTickets(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("Expected 2 page requests, got %d", len(requests))
+	}
+	if len(todos) != 3 {
+		t.Fatalf("Expected 3 tickets, got %d", len(todos))
+	}
+	if todos[2].ID != "jira-PROJ-3" {
+		t.Errorf("Expected last ticket 'jira-PROJ-3', got '%s'", todos[2].ID)
+	}
+}
+
+func TestProvider_GetAssignedTickets_Provenance(t *testing.T) {
+	var jql string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jql = r.URL.Query().Get("jql")
+		_, _ = fmt.Fprintf(w, `{"issues":[%s],"isLast":true}`, newTestIssue("PROJ-1"))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+		Filter:  "project = PROJ",
+	})
+
+	todos, err := p.GetAssignedTickets(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos) != 1 {
+		t.Fatalf("Expected 1 ticket, got %d", len(todos))
+	}
+
+	provenance := todos[0].Provenance
+	if provenance == nil {
+		t.Fatal("Expected provenance, got nil")
+	}
+	if provenance.Provider != "jira" || provenance.Query != jql || provenance.Filter != "project = PROJ" {
+		t.Errorf("Expected the JQL %q and filter of the search, got %+v", jql, provenance)
+	}
+	if provenance.FetchedAt.IsZero() {
+		t.Error("Expected the fetch time to be set")
+	}
+}
+
+func TestProvider_GetAssignedTickets_MaxResults(t *testing.T) {
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		if got := r.URL.Query().Get("maxResults"); got != "2" {
+			t.Errorf("Expected maxResults=2, got %s", got)
+		}
+		_, _ = fmt.Fprintf(w, `{"issues":[%s,%s],"nextPageToken":"more","isLast":false}`, newTestIssue("PROJ-1"), newTestIssue("PROJ-2"))
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:      "test@example.com",
+		Token:      "testtoken",
+		URL:        server.URL,
+		Enabled:    true,
+		MaxResults: 2,
+	})
+
+	todos, err := p.GetAssignedTickets(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if pages != 1 {
+		t.Errorf("Expected pagination to stop at the cap after 1 page, got %d", pages)
+	}
+	if len(todos) != 2 {
+		t.Errorf("Expected 2 tickets, got %d", len(todos))
+	}
+}
+
+func TestProvider_GetAssignedTickets_LegacyFallback(t *testing.T) {
+	var startAts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/search/jql":
+			http.NotFound(w, r)
+		case "/rest/api/3/search":
+			startAt := r.URL.Query().Get("startAt")
+			startAts = append(startAts, startAt)
+			if startAt == "0" {
+				issues := make([]string, 50)
+				for i := range issues {
+					issues[i] = newTestIssue(fmt.Sprintf("PROJ-%d", i+1))
+				}
+				_, _ = fmt.Fprintf(w, `{"issues":[%s],"total":51}`, strings.Join(issues, ","))
+			} else {
+				_, _ = fmt.Fprintf(w, `{"issues":[%s],"total":51}`, newTestIssue("PROJ-51"))
+			}
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	todos, err := p.GetAssignedTickets(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(startAts) != 2 || startAts[1] != "50" {
+		t.Errorf("Expected legacy requests at startAt 0 and 50, got %v", startAts)
+	}
+	if len(todos) != 51 {
+		t.Errorf("Expected 51 tickets, got %d", len(todos))
+	}
+}
+
+func TestProvider_GetAssignedTickets_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/search/jql" {
+			t.Errorf("Expected no fallback on server errors, got request to %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	if _, err := p.GetAssignedTickets(context.Background()); err == nil {
+		t.Error("Expected error for server failure, got nil")
+	}
+}
+
+func TestProvider_IssueType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Query().Get("fields"), "issuetype") {
+			t.Errorf("Expected the issue type to be requested, got fields %q", r.URL.Query().Get("fields"))
+		}
+		_, _ = fmt.Fprint(w, `{"issues":[
+			{"key":"PROJ-1","fields":{"summary":"Crash on login","updated":"2024-01-15T10:00:00.000+0000","status":{"name":"To Do"},"issuetype":{"name":"Bug"}}},
+			{"key":"PROJ-2","fields":{"summary":"No type","updated":"2024-01-15T11:00:00.000+0000","status":{"name":"To Do"}}}
+		],"isLast":true}`)
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: server.URL, Enabled: true})
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.getUpdatedIssues(context.Background(), fixedAccountID("me"), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 2 {
+		t.Fatalf("Expected 2 activities, got %d", len(activities))
+	}
+	if activities[0].IssueType != "Bug" || activities[0].Tags[len(activities[0].Tags)-1] != "Bug" {
+		t.Errorf("Expected Bug issue type and tag, got %q and %v", activities[0].IssueType, activities[0].Tags)
+	}
+	if activities[1].IssueType != "" || len(activities[1].Tags) != 2 {
+		t.Errorf("Expected no issue type tag, got %q and %v", activities[1].IssueType, activities[1].Tags)
+	}
+
+	tickets, err := p.GetAssignedTickets(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tickets) != 2 || tickets[0].IssueType != "Bug" || tickets[0].Tags[len(tickets[0].Tags)-1] != "Bug" {
+		t.Errorf("Expected assigned Bug ticket tagged with its type, got %+v", tickets)
+	}
+}
@@ -0,0 +1,145 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"daily/internal/provider"
+)
+
+// Ensure the provider can be used as a mention source
+var _ provider.MentionSource = (*Provider)(nil)
+
+// maxMentionExcerpt is the maximum length of the comment excerpt shown for a mention
+const maxMentionExcerpt = 120
+
+// GetMentions retrieves issues where someone mentioned the current user in a comment,
+// linking to the most recent mentioning comment of each issue
+func (p *Provider) GetMentions(ctx context.Context, since string) ([]provider.TodoItem, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("JIRA provider not configured")
+	}
+
+	sinceTime, err := provider.ParseSinceDuration(since)
+	if err != nil {
+		return nil, err
+	}
+
+	jql := withFilter(fmt.Sprintf("comment ~ currentUser() AND updated >= \"%s\"", sinceTime.Format("2006-01-02 15:04")), p.todoFilter()) + " ORDER BY updated DESC"
+
+	issues, err := p.searchIssues(ctx, jql)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	accountID, err := p.getCurrentAccountID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	provenance := p.provenance(jql)
+
+	var mentions []provider.TodoItem
+	for _, issue := range issues {
+		var comments struct {
+			Comments []struct {
+				ID      string          `json:"id"`
+				Author  jiraAuthor      `json:"author"`
+				Body    json.RawMessage `json:"body"`
+				Created string          `json:"created"`
+			} `json:"comments"`
+		}
+
+		commentsURL := p.apiURL(fmt.Sprintf("issue/%s/comment?orderBy=-created&maxResults=100", url.PathEscape(issue.Key)))
+		if err := p.makeRequest(ctx, commentsURL, &comments); err != nil {
+			continue // Skip issues whose comments can't be read
+		}
+
+		// Keep only the latest mention per issue so each issue is listed once
+		var latest *provider.TodoItem
+		for _, comment := range comments.Comments {
+			if comment.Author.id() == accountID || !mentionsUser(comment.Body, accountID) {
+				continue
+			}
+
+			created, err := p.parseJIRATime(comment.Created)
+			if err != nil || created.Before(sinceTime) {
+				continue
+			}
+			if latest != nil && !created.After(latest.UpdatedAt) {
+				continue
+			}
+
+			description := fmt.Sprintf("%s mentioned you", comment.Author.DisplayName)
+			if excerpt := truncateExcerpt(displayText(adfText(comment.Body), nil), maxMentionExcerpt); excerpt != "" {
+				description = fmt.Sprintf("%s: %s", description, excerpt)
+			}
+
+			latest = &provider.TodoItem{
+				ID:          fmt.Sprintf("jira-%s-mention-%s", issue.Key, comment.ID),
+				Title:       fmt.Sprintf("%s: %s", issue.Key, issue.Fields.Summary),
+				Description: description,
+				URL:         fmt.Sprintf("%s/browse/%s?focusedCommentId=%s", strings.TrimSuffix(p.config.URL, "/"), issue.Key, comment.ID),
+				UpdatedAt:   created,
+				Tags:        []string{issue.Key, "mention"},
+				Details:     issueDetails(issue.Key, issue.Fields.Status.Name),
+				Provenance:  provenance,
+			}
+		}
+
+		if latest != nil {
+			mentions = append(mentions, *latest)
+		}
+	}
+
+	return mentions, nil
+}
+
+// mentionsUser reports whether a comment body mentions the user. Jira Cloud comments are
+// Atlassian Document Format with mention nodes; Jira Server comments are wiki markup
+// where mentions are written [~username] or [~accountid:id].
+func mentionsUser(body json.RawMessage, userID string) bool {
+	if len(body) == 0 || userID == "" {
+		return false
+	}
+
+	var plain string
+	if err := json.Unmarshal(body, &plain); err == nil {
+		return strings.Contains(plain, "[~"+userID+"]") || strings.Contains(plain, "[~accountid:"+userID+"]")
+	}
+
+	var node struct {
+		Type  string `json:"type"`
+		Attrs struct {
+			ID string `json:"id"`
+		} `json:"attrs"`
+		Content []json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(body, &node); err != nil {
+		return false
+	}
+
+	if node.Type == "mention" && node.Attrs.ID == userID {
+		return true
+	}
+	for _, child := range node.Content {
+		if mentionsUser(child, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateExcerpt shortens text to at most maxLen runes, adding an ellipsis when cut
+func truncateExcerpt(text string, maxLen int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxLen-1])) + "…"
+}
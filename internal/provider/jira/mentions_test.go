@@ -0,0 +1,110 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestMentionsUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		userID   string
+		expected bool
+	}{
+		{"adf mention", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"mention","attrs":{"id":"me","text":"@Me"}},{"type":"text","text":" can you check?"}]}]}`, "me", true},
+		{"adf other mention", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"mention","attrs":{"id":"someone"}}]}]}`, "me", false},
+		{"server username", `"Hey [~jdoe], please review"`, "jdoe", true},
+		{"server account id", `"Hey [~accountid:me], please review"`, "me", true},
+		{"plain text without mention", `"Mentioning jdoe without markup"`, "jdoe", false},
+		{"empty body", ``, "me", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := mentionsUser(json.RawMessage(tt.body), tt.userID); result != tt.expected {
+				t.Errorf("Expected %t, got %t", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestProvider_GetMentions(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).UTC().Format("2006-01-02T15:04:05.000-0700")
+	latest := time.Now().Add(-30 * time.Minute).UTC().Format("2006-01-02T15:04:05.000-0700")
+	old := time.Now().Add(-30 * 24 * time.Hour).UTC().Format("2006-01-02T15:04:05.000-0700")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/search/jql":
+			if !strings.Contains(r.URL.Query().Get("jql"), "comment ~ currentUser()") {
+				t.Errorf("Expected mention JQL, got '%s'", r.URL.Query().Get("jql"))
+			}
+			_, _ = fmt.Fprintf(w, `{"issues":[%s,%s],"isLast":true}`, newTestIssue("PROJ-1"), newTestIssue("PROJ-2"))
+		case "/rest/api/3/myself":
+			_, _ = fmt.Fprint(w, `{"accountId":"me"}`)
+		case "/rest/api/3/issue/PROJ-1/comment":
+			mention := `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"mention","attrs":{"id":"me"}},{"type":"text","text":"%s"}]}]}`
+			_, _ = fmt.Fprintf(w, `{"comments":[
+				{"id":"100","author":{"accountId":"alice","displayName":"Alice"},"created":%q,"body":%s},
+				{"id":"101","author":{"accountId":"bob","displayName":"Bob"},"created":%q,"body":%s},
+				{"id":"102","author":{"accountId":"me","displayName":"Me"},"created":%q,"body":%s},
+				{"id":"103","author":{"accountId":"carol","displayName":"Carol"},"created":%q,"body":%s}
+			]}`,
+				recent, fmt.Sprintf(mention, "first ping"),
+				latest, fmt.Sprintf(mention, "second ping"),
+				latest, fmt.Sprintf(mention, "self mention"),
+				old, fmt.Sprintf(mention, "too old"))
+		case "/rest/api/3/issue/PROJ-2/comment":
+			_, _ = fmt.Fprintf(w, `{"comments":[{"id":"200","author":{"accountId":"alice"},"created":%q,"body":{"type":"doc","content":[]}}]}`, recent)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	mentions, err := p.GetMentions(context.Background(), "1w")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(mentions) != 1 {
+		t.Fatalf("Expected 1 mention (one per issue), got %d", len(mentions))
+	}
+
+	mention := mentions[0]
+	if mention.ID != "jira-PROJ-1-mention-101" {
+		t.Errorf("Expected latest mention 'jira-PROJ-1-mention-101', got '%s'", mention.ID)
+	}
+	if mention.URL != server.URL+"/browse/PROJ-1?focusedCommentId=101" {
+		t.Errorf("Expected URL to link to the comment, got '%s'", mention.URL)
+	}
+	if mention.Description != "Bob mentioned you: second ping" {
+		t.Errorf("Expected description 'Bob mentioned you: second ping', got '%s'", mention.Description)
+	}
+}
+
+func TestTruncateExcerpt(t *testing.T) {
+	if result := truncateExcerpt("short", 10); result != "short" {
+		t.Errorf("Expected 'short', got '%s'", result)
+	}
+	if result := truncateExcerpt("a much longer comment", 10); result != "a much lo…" {
+		t.Errorf("Expected 'a much lo…', got '%s'", result)
+	}
+}
@@ -0,0 +1,87 @@
+package jira
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+)
+
+// defaultSprintField is the custom field JIRA Cloud uses for sprints on most sites
+const defaultSprintField = "customfield_10020"
+
+// dueDateLayout is the date-only format of the duedate field
+const dueDateLayout = "2006-01-02"
+
+// legacySprintNameRe extracts the name from the serialized sprint strings returned by older JIRA Server versions
+// e.g., "com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=1,rapidViewId=2,state=ACTIVE,name=Sprint 5,...]"
+var legacySprintNameRe = regexp.MustCompile(`[\[,]name=([^,\]]*)`)
+var legacySprintStateRe = regexp.MustCompile(`[\[,]state=([^,\]]*)`)
+
+// sprintField returns the configured sprint custom field ID
+func (p *Provider) sprintField() string {
+	if p.config.SprintField != "" {
+		return p.config.SprintField
+	}
+	return defaultSprintField
+}
+
+// searchFieldList returns the issue fields requested from the search API, including the sprint
+// and epic link custom fields
+func (p *Provider) searchFieldList() string {
+	return searchFields + "," + p.sprintField() + "," + p.epicLinkField()
+}
+
+// parseDueDate parses a JIRA due date (e.g., "2024-01-20") as a local calendar date.
+// An empty value means the issue has no due date.
+func parseDueDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	dueDate, err := time.ParseInLocation(dueDateLayout, s, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &dueDate, nil
+}
+
+// sprintName returns the name of the active sprint in a sprint field value, or of the
+// most recent sprint when none is active
+func sprintName(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	type sprint struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	}
+
+	var sprints []sprint
+	if err := json.Unmarshal(raw, &sprints); err != nil {
+		var legacy []string
+		if err := json.Unmarshal(raw, &legacy); err != nil {
+			return ""
+		}
+		for _, value := range legacy {
+			var s sprint
+			if match := legacySprintNameRe.FindStringSubmatch(value); match != nil {
+				s.Name = match[1]
+			}
+			if match := legacySprintStateRe.FindStringSubmatch(value); match != nil {
+				s.State = match[1]
+			}
+			sprints = append(sprints, s)
+		}
+	}
+
+	if len(sprints) == 0 {
+		return ""
+	}
+	for _, s := range sprints {
+		if s.State == "active" || s.State == "ACTIVE" {
+			return s.Name
+		}
+	}
+	return sprints[len(sprints)-1].Name
+}
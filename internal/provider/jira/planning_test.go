@@ -0,0 +1,130 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestParseDueDate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected *time.Time
+		hasError bool
+	}{
+		{"2024-01-20", func() *time.Time { d := time.Date(2024, 1, 20, 0, 0, 0, 0, time.Local); return &d }(), false},
+		{"", nil, false},
+		{"20/01/2024", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseDueDate(tt.input)
+
+			if tt.hasError && err == nil {
+				t.Errorf("Expected error for input '%s', but got none", tt.input)
+			}
+			if !tt.hasError && err != nil {
+				t.Errorf("Expected no error for input '%s', but got: %v", tt.input, err)
+			}
+			if tt.expected == nil && result != nil {
+				t.Errorf("Expected no due date, got %v", result)
+			}
+			if tt.expected != nil && (result == nil || !result.Equal(*tt.expected)) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSprintName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"missing", ``, ""},
+		{"null", `null`, ""},
+		{"active sprint", `[{"id":1,"name":"Sprint 4","state":"closed"},{"id":2,"name":"Sprint 5","state":"active"}]`, "Sprint 5"},
+		{"no active sprint", `[{"id":1,"name":"Sprint 4","state":"closed"},{"id":2,"name":"Sprint 6","state":"future"}]`, "Sprint 6"},
+		{"server format", `["com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=7,rapidViewId=2,state=ACTIVE,name=Team Sprint 7,startDate=2024-01-08]"]`, "Team Sprint 7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := sprintName(json.RawMessage(tt.input)); result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestProvider_GetAssignedTickets_Planning(t *testing.T) {
+	tests := []struct {
+		name        string
+		sprintField string
+		wantField   string
+	}{
+		{"default sprint field", "", "customfield_10020"},
+		{"custom sprint field", "customfield_12345", "customfield_12345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fields := r.URL.Query().Get("fields")
+				for _, field := range []string{"priority", "duedate", tt.wantField} {
+					if !strings.Contains(fields, field) {
+						t.Errorf("Expected fields to contain '%s', got '%s'", field, fields)
+					}
+				}
+				_, _ = fmt.Fprintf(w, `{"issues":[
+					{"key":"PROJ-1","fields":{"summary":"Planned","updated":"2024-01-15T10:00:00.000+0000","status":{"name":"To Do"},
+					 "priority":{"name":"High"},"duedate":"2024-01-20","%s":[{"name":"Sprint 5","state":"active"}]}},
+					{"key":"PROJ-2","fields":{"summary":"Unplanned","updated":"2024-01-15T10:00:00.000+0000","status":{"name":"To Do"}}}
+				],"isLast":true}`, tt.wantField)
+			}))
+			defer server.Close()
+
+			p := NewProvider(provider.Config{
+				Email:       "test@example.com",
+				Token:       "testtoken",
+				URL:         server.URL,
+				Enabled:     true,
+				SprintField: tt.sprintField,
+			})
+
+			todos, err := p.GetAssignedTickets(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(todos) != 2 {
+				t.Fatalf("Expected 2 tickets, got %d", len(todos))
+			}
+
+			planned := todos[0]
+			if planned.Priority != "High" {
+				t.Errorf("Expected priority 'High', got '%s'", planned.Priority)
+			}
+			if planned.DueDate == nil || planned.DueDate.Format("2006-01-02") != "2024-01-20" {
+				t.Errorf("Expected due date 2024-01-20, got %v", planned.DueDate)
+			}
+			if planned.Sprint != "Sprint 5" {
+				t.Errorf("Expected sprint 'Sprint 5', got '%s'", planned.Sprint)
+			}
+
+			unplanned := todos[1]
+			if unplanned.Priority != "" || unplanned.DueDate != nil || unplanned.Sprint != "" {
+				t.Errorf("Expected no planning fields, got priority '%s', due %v, sprint '%s'",
+					unplanned.Priority, unplanned.DueDate, unplanned.Sprint)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"daily/internal/activity"
+)
+
+// resolvedIDSuffix distinguishes resolved-issue activities from updated-issue ones ("jira-KEY")
+const resolvedIDSuffix = "-resolved"
+
+// getResolvedIssues fetches the issues resolved in the time range that were assigned to the
+// user at some point, so they are reported even when someone else updated them since
+func (p *Provider) getResolvedIssues(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
+	fromDate, toDate := p.jqlDateRange(from, to)
+	jql := fmt.Sprintf("resolutiondate >= \"%s\" AND resolutiondate < \"%s\" AND assignee was currentUser()", fromDate, toDate)
+	jql = withFilter(jql, p.summaryFilter()) + " ORDER BY resolutiondate DESC"
+
+	issues, err := p.searchIssues(ctx, jql)
+	if err != nil {
+		return nil, err
+	}
+
+	var activities []activity.Activity
+	for _, issue := range issues {
+		resolvedTime, err := p.parseJIRATime(issue.Fields.ResolutionDate)
+		if err != nil {
+			continue // Skip issues with unparseable or missing resolution dates
+		}
+
+		// The JQL date range is widened, so keep only the resolutions in the time range
+		if resolvedTime.Before(from) || resolvedTime.After(to) {
+			continue
+		}
+
+		resolution := "Resolved"
+		tags := []string{issue.Key}
+		if issue.Fields.Resolution != nil && issue.Fields.Resolution.Name != "" {
+			resolution = fmt.Sprintf("Resolved as %s", issue.Fields.Resolution.Name)
+			tags = append(tags, issue.Fields.Resolution.Name)
+		}
+
+		epic := p.issueEpic(issue)
+		activities = append(activities, activity.Activity{
+			ID:          fmt.Sprintf("jira-%s%s", issue.Key, resolvedIDSuffix),
+			Type:        activity.ActivityTypeJiraResolved,
+			Title:       fmt.Sprintf("%s: %s", issue.Key, issue.Fields.Summary),
+			Description: resolution,
+			URL:         fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(p.config.URL, "/"), issue.Key),
+			Platform:    "jira",
+			Timestamp:   resolvedTime,
+			Tags:        withEpicTag(withIssueType(tags, issue), epic),
+			Epic:        epic,
+			IssueType:   issue.Fields.IssueType.Name,
+			Details:     issueDetails(issue.Key, issue.Fields.Status.Name),
+		})
+	}
+
+	return activities, nil
+}
+
+// withoutResolvedIssues drops the updated-issue activities of issues that are also reported as resolved
+func withoutResolvedIssues(activities, resolved []activity.Activity) []activity.Activity {
+	if len(resolved) == 0 {
+		return activities
+	}
+
+	resolvedIDs := make(map[string]bool, len(resolved))
+	for _, act := range resolved {
+		resolvedIDs[strings.TrimSuffix(act.ID, resolvedIDSuffix)] = true
+	}
+
+	filtered := make([]activity.Activity, 0, len(activities))
+	for _, act := range activities {
+		if !resolvedIDs[act.ID] {
+			filtered = append(filtered, act)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,118 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+func TestProvider_GetActivities_ResolvedIssues(t *testing.T) {
+	var resolvedJQL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/3/myself" {
+			_, _ = fmt.Fprint(w, `{"accountId":"user-1"}`)
+			return
+		}
+
+		jql := r.URL.Query().Get("jql")
+		switch {
+		case strings.Contains(jql, "resolutiondate"):
+			resolvedJQL = jql
+			_, _ = fmt.Fprint(w, `{"issues":[
+				{"key":"PROJ-1","fields":{"summary":"Fix login","updated":"2024-01-15T16:00:00.000+0000","status":{"name":"Done"},
+				 "resolution":{"name":"Fixed"},"resolutiondate":"2024-01-15T09:30:00.000+0000"}},
+				{"key":"PROJ-3","fields":{"summary":"Old idea","updated":"2024-01-15T12:00:00.000+0000","status":{"name":"Closed"},
+				 "resolution":{"name":"Won't Do"},"resolutiondate":"2024-01-15T11:00:00.000+0000"}},
+				{"key":"PROJ-4","fields":{"summary":"Resolved the day before","status":{"name":"Done"},
+				 "resolution":{"name":"Fixed"},"resolutiondate":"2024-01-14T23:00:00.000+0000"}}
+			],"isLast":true}`)
+		case strings.Contains(jql, "assignee = currentUser() AND updated"):
+			_, _ = fmt.Fprint(w, `{"issues":[
+				{"key":"PROJ-1","fields":{"summary":"Fix login","updated":"2024-01-15T16:00:00.000+0000","status":{"name":"Done"}}},
+				{"key":"PROJ-2","fields":{"summary":"Add logout","updated":"2024-01-15T10:00:00.000+0000","status":{"name":"In Progress"}}}
+			],"isLast":true}`)
+		default:
+			_, _ = fmt.Fprint(w, `{"issues":[],"isLast":true}`)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:         "test@example.com",
+		Token:         "testtoken",
+		URL:           server.URL,
+		Enabled:       true,
+		Timezone:      "UTC",
+		SummaryFilter: "project = PROJ",
+	})
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	for _, want := range []string{"assignee was currentUser()", "AND (project = PROJ)", "ORDER BY resolutiondate DESC"} {
+		if !strings.Contains(resolvedJQL, want) {
+			t.Errorf("Expected resolved JQL to contain '%s', got: %s", want, resolvedJQL)
+		}
+	}
+	// JQL only accepts a single ORDER BY
+	if strings.Count(resolvedJQL, "ORDER BY") != 1 {
+		t.Errorf("Expected a single ORDER BY, got: %s", resolvedJQL)
+	}
+
+	byID := make(map[string]activity.Activity)
+	for _, act := range activities {
+		byID[act.ID] = act
+	}
+	if len(activities) != 3 {
+		t.Fatalf("Expected 3 activities, got %d: %+v", len(activities), activities)
+	}
+
+	// PROJ-1 is reported once, as resolved
+	if _, ok := byID["jira-PROJ-1"]; ok {
+		t.Error("Expected resolved issue not to be reported as updated too")
+	}
+	fixed, ok := byID["jira-PROJ-1-resolved"]
+	if !ok {
+		t.Fatal("Expected PROJ-1 to be reported as resolved")
+	}
+	if fixed.Type != activity.ActivityTypeJiraResolved {
+		t.Errorf("Expected type '%s', got '%s'", activity.ActivityTypeJiraResolved, fixed.Type)
+	}
+	if fixed.Description != "Resolved as Fixed" {
+		t.Errorf("Expected description 'Resolved as Fixed', got '%s'", fixed.Description)
+	}
+	if expected := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC); !fixed.Timestamp.Equal(expected) {
+		t.Errorf("Expected resolution time %v, got %v", expected, fixed.Timestamp)
+	}
+
+	if wontDo := byID["jira-PROJ-3-resolved"]; wontDo.Description != "Resolved as Won't Do" {
+		t.Errorf("Expected description 'Resolved as Won't Do', got '%s'", wontDo.Description)
+	}
+	if _, ok := byID["jira-PROJ-2"]; !ok {
+		t.Error("Expected unresolved updated issue to be kept")
+	}
+}
+
+func TestWithoutResolvedIssues(t *testing.T) {
+	activities := []activity.Activity{
+		{ID: "jira-PROJ-1"},
+		{ID: "jira-PROJ-1-worklog-10"},
+		{ID: "jira-PROJ-2"},
+	}
+	resolved := []activity.Activity{{ID: "jira-PROJ-1-resolved"}}
+
+	filtered := withoutResolvedIssues(activities, resolved)
+	if len(filtered) != 2 || filtered[0].ID != "jira-PROJ-1-worklog-10" || filtered[1].ID != "jira-PROJ-2" {
+		t.Errorf("Expected only the updated PROJ-1 activity to be dropped, got %+v", filtered)
+	}
+}
@@ -0,0 +1,44 @@
+package jira
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Status category keys returned in status.statusCategory.key
+const (
+	statusCategoryNew           = "new"
+	statusCategoryIndeterminate = "indeterminate"
+	statusCategoryDone          = "done"
+)
+
+// defaultStatusSections maps status names and category keys to todo sections.
+// Status names take precedence over category keys.
+var defaultStatusSections = map[string]string{
+	"Blocked":                   "Blocked",
+	statusCategoryNew:           "To Do",
+	statusCategoryIndeterminate: "In Progress",
+	statusCategoryDone:          "Done",
+}
+
+// statusSection returns the todo section of a ticket from its status name and category key.
+// Configured mappings override the defaults; status names are matched exactly first, then
+// case-insensitively in the order of the names, so that the same section is always picked.
+func (p *Provider) statusSection(status, category string) string {
+	for _, sections := range []map[string]string{p.config.StatusSections, defaultStatusSections} {
+		if section, ok := sections[status]; ok {
+			return section
+		}
+		for _, name := range slices.Sorted(maps.Keys(sections)) {
+			if strings.EqualFold(name, status) {
+				return sections[name]
+			}
+		}
+	}
+
+	if section, ok := p.config.StatusSections[category]; ok {
+		return section
+	}
+	return defaultStatusSections[category]
+}
@@ -0,0 +1,82 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+func TestProvider_StatusSection(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections map[string]string
+		status   string
+		category string
+		expected string
+	}{
+		{"new category", nil, "Open", "new", "To Do"},
+		{"indeterminate category", nil, "In Review", "indeterminate", "In Progress"},
+		{"blocked status", nil, "Blocked", "indeterminate", "Blocked"},
+		{"blocked status is case-insensitive", nil, "BLOCKED", "indeterminate", "Blocked"},
+		{"unknown category", nil, "Open", "", ""},
+		{"configured status", map[string]string{"code review": "In Review"}, "Code Review", "indeterminate", "In Review"},
+		{"configured category", map[string]string{"new": "Backlog"}, "Open", "new", "Backlog"},
+		{"default status wins over configured category", map[string]string{"indeterminate": "Doing"}, "Blocked", "indeterminate", "Blocked"},
+		{"configured status overrides default status", map[string]string{"Blocked": "In Progress"}, "Blocked", "indeterminate", "In Progress"},
+		{"exact status over case-insensitive", map[string]string{"QA": "In Review", "qa": "Testing"}, "qa", "indeterminate", "Testing"},
+		{"case-insensitive status in name order", map[string]string{"QA": "In Review", "qa": "Testing"}, "Qa", "indeterminate", "In Review"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(provider.Config{StatusSections: tt.sections})
+			if got := p.statusSection(tt.status, tt.category); got != tt.expected {
+				t.Errorf("Expected section '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProvider_GetAssignedTickets_StatusCategory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"issues":[
+			{"key":"PROJ-1","fields":{"summary":"Started","updated":"2024-01-15T10:00:00.000+0000",
+			 "status":{"name":"In Progress","statusCategory":{"key":"indeterminate"}}}},
+			{"key":"PROJ-2","fields":{"summary":"Stuck","updated":"2024-01-15T10:00:00.000+0000",
+			 "status":{"name":"Blocked","statusCategory":{"key":"indeterminate"}}}}
+		],"isLast":true}`)
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	todos, err := p.GetAssignedTickets(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 tickets, got %d", len(todos))
+	}
+
+	for i, expected := range []string{"In Progress", "Blocked"} {
+		if todos[i].StatusCategory != "indeterminate" {
+			t.Errorf("Expected status category 'indeterminate', got '%s'", todos[i].StatusCategory)
+		}
+		if todos[i].StatusSection != expected {
+			t.Errorf("Expected section '%s', got '%s'", expected, todos[i].StatusSection)
+		}
+		if todos[i].Details[activity.DetailProject] != "PROJ" || todos[i].Details[activity.DetailStatus] != expected {
+			t.Errorf("Expected project and status details, got %v", todos[i].Details)
+		}
+	}
+}
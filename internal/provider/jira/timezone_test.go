@@ -0,0 +1,149 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestProvider_ParseJIRATime_Timezone(t *testing.T) {
+	p := NewProvider(provider.Config{Timezone: "UTC"})
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"2024-01-15T23:50:00.000+1000", time.Date(2024, 1, 15, 13, 50, 0, 0, time.UTC)},
+		{"2024-01-15T23:50:00+1000", time.Date(2024, 1, 15, 13, 50, 0, 0, time.UTC)},
+		{"2024-01-15T23:50:00.5-0800", time.Date(2024, 1, 16, 7, 50, 0, 500000000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := p.parseJIRATime(tt.input)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+			if result.Location() != time.UTC {
+				t.Errorf("Expected time in the configured timezone, got %v", result.Location())
+			}
+		})
+	}
+}
+
+func TestProvider_GetUpdatedIssues_MidnightBoundary(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		updated  []string // Issue update times, keyed PROJ-1, PROJ-2, ...
+		expected []string
+	}{
+		{
+			name:     "+10:00",
+			timezone: "Etc/GMT-10",
+			updated: []string{
+				"2024-01-15T23:50:00.000+1000", // Late evening on the 15th
+				"2024-01-16T00:10:00.000+1000", // Just after midnight, next day
+				"2024-01-14T14:10:00.000+0000", // 00:10 on the 15th, reported in UTC
+			},
+			expected: []string{"PROJ-1", "PROJ-3"},
+		},
+		{
+			name:     "-08:00",
+			timezone: "Etc/GMT+8",
+			updated: []string{
+				"2024-01-15T23:50:00.000-0800", // Late evening on the 15th
+				"2024-01-16T00:10:00.000-0800", // Just after midnight, next day
+				"2024-01-16T07:50:00.000+0000", // 23:50 on the 15th, reported in UTC
+			},
+			expected: []string{"PROJ-1", "PROJ-3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var jql string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				jql = r.URL.Query().Get("jql")
+
+				var issues []map[string]any
+				for i, updated := range tt.updated {
+					issues = append(issues, map[string]any{
+						"key":    "PROJ-" + string(rune('1'+i)),
+						"fields": map[string]any{"summary": "Issue", "updated": updated, "status": map[string]any{"name": "To Do"}},
+					})
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{"issues": issues, "isLast": true})
+			}))
+			defer server.Close()
+
+			p := NewProvider(provider.Config{
+				Email:    "test@example.com",
+				Token:    "testtoken",
+				URL:      server.URL,
+				Enabled:  true,
+				Timezone: tt.timezone,
+			})
+
+			loc, err := time.LoadLocation(tt.timezone)
+			if err != nil {
+				t.Fatalf("Failed to load timezone: %v", err)
+			}
+			from := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
+			to := from.Add(24 * time.Hour)
+
+			activities, err := p.getUpdatedIssues(context.Background(), fixedAccountID("me"), from, to)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			// The query is widened so issues near midnight in another timezone are returned
+			if !strings.Contains(jql, `updated >= "2024-01-14" AND updated < "2024-01-17"`) {
+				t.Errorf("Expected JQL to cover a day on each side, got: %s", jql)
+			}
+
+			var keys []string
+			for _, act := range activities {
+				keys = append(keys, act.Tags[0])
+				if act.Timestamp.Location().String() != tt.timezone || act.Timestamp.Day() != 15 {
+					t.Errorf("Expected %s on Jan 15 in %s, got %v", act.Tags[0], tt.timezone, act.Timestamp)
+				}
+			}
+			if strings.Join(keys, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected issues %v, got %v", tt.expected, keys)
+			}
+		})
+	}
+}
+
+func TestProvider_DayBoundary(t *testing.T) {
+	p := NewProvider(provider.Config{Timezone: "Etc/GMT-10"})
+	loc := p.location()
+	local := time.FixedZone("CET", 3600)
+
+	// Midnights computed in local time become midnights of the configured timezone
+	midnight := time.Date(2024, 1, 15, 0, 0, 0, 0, local)
+	if got := p.dayBoundary(midnight); !got.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, loc)) {
+		t.Errorf("Expected midnight in the configured timezone, got %v", got)
+	}
+
+	// Other times are instants
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, local)
+	if got := p.dayBoundary(now); !got.Equal(now) {
+		t.Errorf("Expected the instant unchanged, got %v", got)
+	}
+
+	// The timezone is loaded once, by NewProvider
+	if loc.String() != "Etc/GMT-10" || NewProvider(provider.Config{}).location() != time.Local {
+		t.Errorf("Expected the configured timezone, or local time, got %v", loc)
+	}
+}
@@ -0,0 +1,72 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"daily/internal/provider"
+)
+
+// Tags of the issues followed without being assigned
+const (
+	tagWatching = "watching"
+	tagReported = "reported"
+)
+
+// GetWatchedIssues retrieves issues watched by the current user that were updated within the since range
+func (p *Provider) GetWatchedIssues(ctx context.Context, since string) ([]TodoItem, error) {
+	return p.getFollowedIssues(ctx, "watcher = currentUser()", tagWatching, since)
+}
+
+// GetReportedIssues retrieves issues reported by the current user that were updated within the since range
+func (p *Provider) GetReportedIssues(ctx context.Context, since string) ([]TodoItem, error) {
+	return p.getFollowedIssues(ctx, "reporter = currentUser()", tagReported, since)
+}
+
+// getFollowedIssues retrieves the issues matching the JQL clause updated within the since range,
+// tagged with tag so that issues listed in several todo sections can be told apart
+func (p *Provider) getFollowedIssues(ctx context.Context, clause, tag, since string) ([]TodoItem, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("JIRA provider not configured")
+	}
+
+	sinceTime, err := provider.ParseSinceDuration(since)
+	if err != nil {
+		return nil, err
+	}
+
+	jql := withFilter(fmt.Sprintf("%s AND updated >= \"%s\"", clause, sinceTime.In(p.location()).Format("2006-01-02 15:04")), p.todoFilter()) + " ORDER BY updated DESC"
+
+	issues, err := p.searchIssues(ctx, jql)
+	if err != nil {
+		return nil, err
+	}
+	provenance := p.provenance(jql)
+
+	var todos []TodoItem
+	for _, issue := range issues {
+		updatedTime, err := p.parseJIRATime(issue.Fields.Updated)
+		if err != nil {
+			continue // Skip issues with unparseable times
+		}
+
+		todos = append(todos, TodoItem{
+			ID:          fmt.Sprintf("jira-%s", issue.Key),
+			Key:         issue.Key,
+			Title:       fmt.Sprintf("%s: %s", issue.Key, issue.Fields.Summary),
+			Description: fmt.Sprintf("Status: %s", issue.Fields.Status.Name),
+			URL:         fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(p.config.URL, "/"), issue.Key),
+			UpdatedAt:   updatedTime,
+			Tags:        withIssueType([]string{issue.Key, issue.Fields.Status.Name, tag}, issue),
+			Priority:    issue.Fields.Priority.Name,
+			IssueType:   issue.Fields.IssueType.Name,
+			Details:     issueDetails(issue.Key, issue.Fields.Status.Name),
+
+			StatusCategory: issue.Fields.Status.StatusCategory.Key,
+			Provenance:     provenance,
+		})
+	}
+
+	return todos, nil
+}
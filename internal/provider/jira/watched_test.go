@@ -0,0 +1,98 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+func TestProvider_GetFollowedIssues(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("jql"))
+		_, _ = fmt.Fprint(w, `{"issues":[
+			{"key":"PROJ-7","fields":{"summary":"Flaky build","updated":"2024-01-15T10:00:00.000+0000",
+			 "status":{"name":"In Review","statusCategory":{"key":"indeterminate"}},"priority":{"name":"High"}}},
+			{"key":"PROJ-8","fields":{"summary":"Bad time","updated":"not a time","status":{"name":"Open"}}}
+		],"isLast":true}`)
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:      "test@example.com",
+		Token:      "testtoken",
+		URL:        server.URL,
+		Enabled:    true,
+		TodoFilter: "project = PROJ",
+	})
+
+	tests := []struct {
+		name   string
+		fetch  func(ctx context.Context, since string) ([]TodoItem, error)
+		clause string
+		tag    string
+	}{
+		{"watched", p.GetWatchedIssues, "watcher = currentUser()", "watching"},
+		{"reported", p.GetReportedIssues, "reporter = currentUser()", "reported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries = nil
+
+			items, err := tt.fetch(context.Background(), "1w")
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if len(queries) != 1 {
+				t.Fatalf("Expected 1 search, got %d", len(queries))
+			}
+			jql := queries[0]
+			for _, want := range []string{tt.clause, "updated >= ", "(project = PROJ)", "ORDER BY updated DESC"} {
+				if !strings.Contains(jql, want) {
+					t.Errorf("Expected JQL to contain '%s', got '%s'", want, jql)
+				}
+			}
+			// JQL only accepts a single ORDER BY
+			if strings.Count(jql, "ORDER BY") != 1 {
+				t.Errorf("Expected a single ORDER BY, got '%s'", jql)
+			}
+
+			// Issues with unparseable times are skipped
+			if len(items) != 1 {
+				t.Fatalf("Expected 1 issue, got %d", len(items))
+			}
+			item := items[0]
+			if item.ID != "jira-PROJ-7" || item.Key != "PROJ-7" || item.Title != "PROJ-7: Flaky build" {
+				t.Errorf("Unexpected issue: %+v", item)
+			}
+			if item.StatusCategory != "indeterminate" || item.Priority != "High" {
+				t.Errorf("Expected status category and priority to be set, got %+v", item)
+			}
+			if !strings.HasSuffix(item.URL, "/browse/PROJ-7") {
+				t.Errorf("Expected issue URL, got '%s'", item.URL)
+			}
+			if len(item.Tags) != 3 || item.Tags[2] != tt.tag {
+				t.Errorf("Expected tags to end with '%s', got %v", tt.tag, item.Tags)
+			}
+		})
+	}
+}
+
+func TestProvider_GetFollowedIssues_Errors(t *testing.T) {
+	p := NewProvider(provider.Config{})
+	if _, err := p.GetWatchedIssues(context.Background(), "1w"); err == nil {
+		t.Error("Expected error for unconfigured provider, got nil")
+	}
+
+	p = NewProvider(provider.Config{Email: "test@example.com", Token: "testtoken", URL: "https://example.atlassian.net", Enabled: true})
+	if _, err := p.GetReportedIssues(context.Background(), "soon"); err == nil {
+		t.Error("Expected error for invalid since value, got nil")
+	}
+}
@@ -0,0 +1,190 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"daily/internal/activity"
+)
+
+// JIRA's default time tracking settings: 8 hour days and 5 day weeks
+const (
+	secondsPerMinute = 60
+	secondsPerHour   = 60 * secondsPerMinute
+	secondsPerDay    = 8 * secondsPerHour
+	secondsPerWeek   = 5 * secondsPerDay
+)
+
+// getWorklogs returns work logged by the current user in the time range
+func (p *Provider) getWorklogs(ctx context.Context, currentAccountID accountIDFunc, from, to time.Time) ([]activity.Activity, error) {
+	fromDate, toDate := p.jqlDateRange(from, to)
+	jql := withFilter(fmt.Sprintf("worklogAuthor = currentUser() AND worklogDate >= \"%s\" AND worklogDate < \"%s\"", fromDate, toDate), p.summaryFilter()) + " ORDER BY updated DESC"
+
+	issues, err := p.searchIssues(ctx, jql)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	accountID, err := currentAccountID()
+	if err != nil {
+		return nil, err
+	}
+
+	var activities []activity.Activity
+	for _, issue := range issues {
+		var result struct {
+			Worklogs []struct {
+				ID               string          `json:"id"`
+				Author           jiraAuthor      `json:"author"`
+				Started          string          `json:"started"`
+				TimeSpent        string          `json:"timeSpent"`
+				TimeSpentSeconds int             `json:"timeSpentSeconds"`
+				Comment          json.RawMessage `json:"comment"`
+			} `json:"worklogs"`
+		}
+
+		worklogURL := p.apiURL(fmt.Sprintf("issue/%s/worklog?startedAfter=%d&startedBefore=%d",
+			url.PathEscape(issue.Key), from.UnixMilli(), to.UnixMilli()))
+		if err := p.makeRequest(ctx, worklogURL, &result); err != nil {
+			continue // Skip issues whose worklogs can't be read
+		}
+
+		for _, worklog := range result.Worklogs {
+			if worklog.Author.id() != accountID {
+				continue
+			}
+
+			started, err := p.parseJIRATime(worklog.Started)
+			if err != nil || started.Before(from) || started.After(to) {
+				continue
+			}
+
+			seconds := worklog.TimeSpentSeconds
+			if seconds == 0 {
+				if seconds, err = parseWorklogDuration(worklog.TimeSpent); err != nil {
+					continue
+				}
+			}
+
+			description := fmt.Sprintf("Logged %s on %s", formatWorklogDuration(seconds), issue.Key)
+			if comment := displayText(adfText(worklog.Comment), nil); comment != "" {
+				description = fmt.Sprintf("%s: %s", description, comment)
+			}
+
+			activities = append(activities, activity.Activity{
+				ID:               fmt.Sprintf("jira-%s-worklog-%s", issue.Key, worklog.ID),
+				Type:             activity.ActivityTypeWorklog,
+				Title:            fmt.Sprintf("%s: %s", issue.Key, issue.Fields.Summary),
+				Description:      description,
+				URL:              fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(p.config.URL, "/"), issue.Key),
+				Platform:         "jira",
+				Timestamp:        started,
+				Tags:             []string{issue.Key, "worklog"},
+				Details:          issueDetails(issue.Key, ""),
+				TimeSpentSeconds: seconds,
+			})
+		}
+	}
+
+	return activities, nil
+}
+
+// parseWorklogDuration parses JIRA durations such as "1w 2d 3h 30m" into seconds
+func parseWorklogDuration(s string) (int, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	total := 0
+	for _, field := range fields {
+		if len(field) < 2 {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+
+		value, err := strconv.ParseFloat(field[:len(field)-1], 64)
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+
+		var unit int
+		switch field[len(field)-1] {
+		case 'w':
+			unit = secondsPerWeek
+		case 'd':
+			unit = secondsPerDay
+		case 'h':
+			unit = secondsPerHour
+		case 'm':
+			unit = secondsPerMinute
+		default:
+			return 0, fmt.Errorf("invalid duration unit in: %s", s)
+		}
+
+		total += int(value * float64(unit))
+	}
+
+	return total, nil
+}
+
+// formatWorklogDuration formats seconds as hours and minutes, e.g. "2h 30m"
+func formatWorklogDuration(seconds int) string {
+	hours := seconds / secondsPerHour
+	minutes := (seconds % secondsPerHour) / secondsPerMinute
+
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// adfText extracts plain text from an Atlassian Document Format value (or a plain string on older APIs)
+func adfText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var plain string
+	if err := json.Unmarshal(raw, &plain); err == nil {
+		return strings.TrimSpace(plain)
+	}
+
+	var node struct {
+		Type  string `json:"type"`
+		Text  string `json:"text"`
+		Attrs struct {
+			Text string `json:"text"` // Shown text of mentions and emoji, e.g. "@Jane Doe"
+		} `json:"attrs"`
+		Content []json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return ""
+	}
+
+	parts := []string{}
+	if node.Text != "" {
+		parts = append(parts, node.Text)
+	}
+	if (node.Type == "mention" || node.Type == "emoji") && node.Attrs.Text != "" {
+		parts = append(parts, node.Attrs.Text)
+	}
+	for _, child := range node.Content {
+		if text := adfText(child); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
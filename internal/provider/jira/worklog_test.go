@@ -0,0 +1,152 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+func TestParseWorklogDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		hasError bool
+	}{
+		{"2h", 2 * 3600, false},
+		{"30m", 30 * 60, false},
+		{"2h 30m", 2*3600 + 30*60, false},
+		{"1d", 8 * 3600, false},
+		{"1w 2d 3h 30m", 5*8*3600 + 2*8*3600 + 3*3600 + 30*60, false},
+		{"1.5h", 90 * 60, false},
+		{"", 0, true},
+		{"2x", 0, true},
+		{"h", 0, true},
+		{"abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseWorklogDuration(tt.input)
+
+			if tt.hasError && err == nil {
+				t.Errorf("Expected error for input '%s', but got none", tt.input)
+			}
+			if !tt.hasError && err != nil {
+				t.Errorf("Expected no error for input '%s', but got: %v", tt.input, err)
+			}
+			if !tt.hasError && result != tt.expected {
+				t.Errorf("Expected %d seconds, got %d", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatWorklogDuration(t *testing.T) {
+	tests := []struct {
+		seconds  int
+		expected string
+	}{
+		{7200, "2h"},
+		{9000, "2h 30m"},
+		{900, "15m"},
+		{0, "0m"},
+		{36000, "10h"},
+	}
+
+	for _, tt := range tests {
+		if result := formatWorklogDuration(tt.seconds); result != tt.expected {
+			t.Errorf("Expected '%s' for %d seconds, got '%s'", tt.expected, tt.seconds, result)
+		}
+	}
+}
+
+func TestADFText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", ``, ""},
+		{"plain string", `"Pairing on the parser"`, "Pairing on the parser"},
+		{"document", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Fixed"},{"type":"text","text":"the build"}]}]}`, "Fixed the build"},
+		{"mention and emoji", `{"type":"paragraph","content":[{"type":"mention","attrs":{"id":"5b10","text":"@Jane Doe"}},{"type":"text","text":"can you check"},{"type":"emoji","attrs":{"shortName":":eyes:","text":"👀"}}]}`, "@Jane Doe can you check 👀"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := adfText(json.RawMessage(tt.input)); result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestProvider_GetActivities_Worklogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/myself":
+			_, _ = fmt.Fprint(w, `{"accountId":"me"}`)
+		case "/rest/api/3/search/jql":
+			if strings.Contains(r.URL.Query().Get("jql"), "worklogAuthor = currentUser()") {
+				_, _ = fmt.Fprintf(w, `{"issues":[%s],"isLast":true}`, newTestIssue("PROJ-99"))
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"issues":[],"isLast":true}`)
+		case "/rest/api/3/issue/PROJ-99/worklog":
+			_, _ = fmt.Fprint(w, `{"worklogs":[
+				{"id":"1","author":{"accountId":"me"},"started":"2024-01-15T09:00:00.000+0000","timeSpent":"2h","timeSpentSeconds":7200,
+				 "comment":{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Code review"}]}]}},
+				{"id":"2","author":{"accountId":"me"},"started":"2024-01-15T14:00:00.000+0000","timeSpent":"30m"},
+				{"id":"3","author":{"accountId":"someone-else"},"started":"2024-01-15T15:00:00.000+0000","timeSpent":"1h","timeSpentSeconds":3600}
+			]}`)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{
+		Email:   "test@example.com",
+		Token:   "testtoken",
+		URL:     server.URL,
+		Enabled: true,
+	})
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := p.GetActivities(context.Background(), from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(activities) != 2 {
+		t.Fatalf("Expected 2 worklog activities, got %d", len(activities))
+	}
+
+	first := activities[0]
+	if first.Type != activity.ActivityTypeWorklog {
+		t.Errorf("Expected type '%s', got '%s'", activity.ActivityTypeWorklog, first.Type)
+	}
+	if first.Description != "Logged 2h on PROJ-99: Code review" {
+		t.Errorf("Expected description 'Logged 2h on PROJ-99: Code review', got '%s'", first.Description)
+	}
+	if first.TimeSpentSeconds != 7200 {
+		t.Errorf("Expected 7200 seconds, got %d", first.TimeSpentSeconds)
+	}
+
+	// timeSpentSeconds is missing, so the duration string is parsed instead
+	if activities[1].TimeSpentSeconds != 1800 {
+		t.Errorf("Expected 1800 seconds, got %d", activities[1].TimeSpentSeconds)
+	}
+	if activities[1].Description != "Logged 30m on PROJ-99" {
+		t.Errorf("Expected description 'Logged 30m on PROJ-99', got '%s'", activities[1].Description)
+	}
+}
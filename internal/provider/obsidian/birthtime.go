@@ -0,0 +1,21 @@
+package obsidian
+
+import (
+	"os"
+	"time"
+)
+
+// fileBirthTime returns when a file was created, when the platform and file system record
+// it, overridden in tests
+var fileBirthTime = func(path string, info os.FileInfo) (time.Time, bool) {
+	return birthTime(path, info)
+}
+
+// noteCreated returns when a note was created: the created property of its frontmatter,
+// which survives copies and syncs, else the birth time of the file
+func noteCreated(path string, info os.FileInfo, meta frontmatter) (time.Time, bool) {
+	if !meta.created.IsZero() {
+		return meta.created, true
+	}
+	return fileBirthTime(path, info)
+}
@@ -0,0 +1,16 @@
+package obsidian
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// birthTime reads the birth time of a file from its stat result
+func birthTime(_ string, info os.FileInfo) (time.Time, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(stat.Birthtimespec.Unix()), true
+}
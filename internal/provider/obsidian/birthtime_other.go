@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package obsidian
+
+import (
+	"os"
+	"time"
+)
+
+// birthTime is unknown on the other platforms
+func birthTime(_ string, _ os.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
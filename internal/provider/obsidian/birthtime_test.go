@@ -0,0 +1,132 @@
+package obsidian
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestProvider_FindRecentNotes_Created(t *testing.T) {
+	from := time.Now().Add(-time.Hour)
+	to := time.Now().Add(time.Hour)
+	inRange := from.Add(30 * time.Minute)
+	lastWeek := from.AddDate(0, 0, -7)
+
+	tests := []struct {
+		name                string
+		content             string
+		birthTime           time.Time // Unknown when zero
+		expectedDescription string
+		expectedTags        []string
+	}{
+		{
+			name:                "birth time in range",
+			content:             "# Ideas\n",
+			birthTime:           inRange,
+			expectedDescription: "Created note: note.md",
+			expectedTags:        []string{"created"},
+		},
+		{
+			name:                "birth time before range",
+			content:             "# Ideas\n",
+			birthTime:           lastWeek,
+			expectedDescription: "Updated note: note.md",
+			expectedTags:        []string{"updated"},
+		},
+		{
+			name:                "unknown creation time",
+			content:             "# Ideas\n",
+			expectedDescription: "Updated note: note.md",
+			expectedTags:        []string{"updated"},
+		},
+		{
+			name:                "created property in range",
+			content:             "---\ncreated: " + inRange.Format("2006-01-02T15:04:05") + "\ntags: [idea]\n---\n# Ideas\n",
+			expectedDescription: "Created note: note.md",
+			expectedTags:        []string{"created", "idea"},
+		},
+		{
+			name:                "created property before the birth time",
+			content:             "---\ncreated: " + lastWeek.Format("2006-01-02") + "\n---\n# Ideas\n",
+			birthTime:           inRange, // e.g. the note was synced to this machine today
+			expectedDescription: "Updated note: note.md",
+			expectedTags:        []string{"updated"},
+		},
+	}
+
+	originalBirthTime := fileBirthTime
+	defer func() { fileBirthTime = originalBirthTime }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileBirthTime = func(string, os.FileInfo) (time.Time, bool) {
+				return tt.birthTime, !tt.birthTime.IsZero()
+			}
+
+			vault := t.TempDir()
+			if err := os.WriteFile(filepath.Join(vault, "note.md"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write note: %v", err)
+			}
+
+			notes, err := NewProvider(provider.Config{URL: vault, Enabled: true}).findRecentNotes(context.Background(), from, to)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(notes) != 1 {
+				t.Fatalf("Expected 1 note, got %d", len(notes))
+			}
+			if notes[0].Description != tt.expectedDescription {
+				t.Errorf("Expected description '%s', got '%s'", tt.expectedDescription, notes[0].Description)
+			}
+			if !slices.Equal(notes[0].Tags, tt.expectedTags) {
+				t.Errorf("Expected tags %v, got %v", tt.expectedTags, notes[0].Tags)
+			}
+		})
+	}
+}
+
+func TestProvider_FindRecentNotes_SkipsEmptyNotes(t *testing.T) {
+	vault := t.TempDir()
+	for name, content := range map[string]string{"Empty.md": "", "Ideas.md": "# Ideas\n"} {
+		if err := os.WriteFile(filepath.Join(vault, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write note: %v", err)
+		}
+	}
+
+	notes, err := NewProvider(provider.Config{URL: vault, Enabled: true}).findRecentNotes(context.Background(), time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Title != "Ideas" {
+		t.Errorf("Expected only the Ideas note, got %+v", notes)
+	}
+}
+
+func TestParseFrontmatterCreated(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2024-05-12", time.Date(2024, 5, 12, 0, 0, 0, 0, time.Local)},
+		{"2024-05-12T09:30", time.Date(2024, 5, 12, 9, 30, 0, 0, time.Local)},
+		{"\"2024-05-12 09:30:15\"", time.Date(2024, 5, 12, 9, 30, 15, 0, time.Local)},
+		{"2024-05-12T09:30:15+02:00", time.Date(2024, 5, 12, 7, 30, 15, 0, time.UTC)},
+		{"last tuesday", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		got := parseFrontmatterCreated([]string{"title: Retro", "created: " + tt.value})
+		if !got.Equal(tt.expected) {
+			t.Errorf("Expected %v for '%s', got %v", tt.expected, tt.value, got)
+		}
+	}
+
+	if got := parseFrontmatterCreated([]string{"meta:", "  created: 2024-05-12"}); !got.IsZero() {
+		t.Errorf("Expected nested properties to be ignored, got %v", got)
+	}
+}
@@ -0,0 +1,16 @@
+package obsidian
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// birthTime reads the creation time of a file from its attributes
+func birthTime(_ string, info os.FileInfo) (time.Time, bool) {
+	attributes, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(0, attributes.CreationTime.Nanoseconds()), true
+}
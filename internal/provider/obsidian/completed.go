@@ -0,0 +1,73 @@
+package obsidian
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"daily/internal/activity"
+)
+
+// completionPattern matches the completion date the Tasks plugin appends to done tasks,
+// e.g. "✅ 2024-05-30"
+var completionPattern = regexp.MustCompile(`\s*✅\s*(\d{4}-\d{2}-\d{2})`)
+
+// isCompletedState reports whether a checkbox state marks a done task
+func isCompletedState(state string) bool {
+	return state == "x" || state == "X"
+}
+
+// findCompletedTasks finds the done tasks whose completion date is within the time range,
+// whenever their note was last modified
+func (p *Provider) findCompletedTasks(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
+	return scanNotes(ctx, p, func(_ context.Context, path string, info os.FileInfo) ([]activity.Activity, error) {
+		return p.parseCompletedTasksFromFile(path, info, from, to)
+	})
+}
+
+// parseCompletedTasksFromFile extracts the done tasks of a markdown file completed within the
+// time range, dated at midday of their completion date
+func (p *Provider) parseCompletedTasksFromFile(filePath string, fileInfo os.FileInfo, from, to time.Time) ([]activity.Activity, error) {
+	note, err := p.readNote(filePath, fileInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	var activities []activity.Activity
+	for _, line := range note.tasks {
+		if !isCompletedState(line.state) {
+			continue
+		}
+		matches := completionPattern.FindStringSubmatch(line.text)
+		if matches == nil {
+			continue
+		}
+		completedAt, err := time.ParseInLocation("2006-01-02", matches[1], time.Local)
+		if err != nil {
+			continue
+		}
+		timestamp, ok := dayInRange(completedAt.Add(12*time.Hour), from, to)
+		if !ok {
+			continue
+		}
+
+		// The completion date is shown by the timestamp, not repeated in the title
+		line.text = strings.Replace(line.text, matches[0], "", 1)
+		task := p.newTask(line, filePath, fileInfo, note.meta)
+		activities = append(activities, activity.Activity{
+			ID:          task.ID,
+			Type:        activity.ActivityTypeTaskCompleted,
+			Title:       task.Title,
+			Description: "Completed" + strings.TrimPrefix(task.Description, "Task"),
+			URL:         task.URL,
+			Platform:    "obsidian",
+			Timestamp:   timestamp,
+			Tags:        task.Tags,
+			Details:     task.Details,
+		})
+	}
+	return activities, nil
+}
@@ -0,0 +1,142 @@
+package obsidian
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+const completedContent = `---
+tags: [project/web]
+---
+## Release
+- [x] Ship the release ✅ 2024-05-30
+- [X] Write the changelog ✅2024-05-29
+- [x] Plan the release ✅ 2024-05-20
+- [x] Done without a date
+- [-] Cancelled ✅ 2024-05-30
+- [ ] Announce the release
+`
+
+func TestProvider_findCompletedTasks(t *testing.T) {
+	tempDir := t.TempDir()
+	// Modified long after the tasks were completed
+	files := map[string]string{
+		"Project.md": completedContent,
+		"Inbox.md":   "- [x] Answer emails ✅ 2024-05-30\n",
+	}
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatalf("Failed to set mod time: %v", err)
+		}
+	}
+
+	p := NewProvider(provider.Config{URL: tempDir, Enabled: true})
+	from := time.Date(2024, 5, 29, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 5, 30, 9, 0, 0, 0, time.Local)
+
+	activities, err := p.GetActivities(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var titles []string
+	for _, act := range activities {
+		if act.Type != activity.ActivityTypeTaskCompleted {
+			t.Errorf("Expected only completed tasks, got %s '%s'", act.Type, act.Title)
+			continue
+		}
+		titles = append(titles, act.Title)
+
+		if act.Timestamp.Before(from) || act.Timestamp.After(to) {
+			t.Errorf("Expected '%s' to be dated in the range, got %v", act.Title, act.Timestamp)
+		}
+	}
+	slices.Sort(titles)
+	expected := []string{"Answer emails", "Ship the release", "Write the changelog"}
+	if !slices.Equal(titles, expected) {
+		t.Errorf("Expected %v, got %v", expected, titles)
+	}
+
+	for _, act := range activities {
+		if act.Title != "Write the changelog" {
+			continue
+		}
+		if act.Description != "Completed in Project › Release" {
+			t.Errorf("Expected the note and heading in the description, got '%s'", act.Description)
+		}
+		if !act.Timestamp.Equal(time.Date(2024, 5, 29, 12, 0, 0, 0, time.Local)) {
+			t.Errorf("Expected midday of the completion date, got %v", act.Timestamp)
+		}
+		if !slices.Contains(act.Tags, "project/web") {
+			t.Errorf("Expected the frontmatter tags, got %v", act.Tags)
+		}
+		if state := act.Detail(activity.DetailState); state != "X" {
+			t.Errorf("Expected the checkbox state in the details, got '%s'", state)
+		}
+	}
+
+	// Completed tasks are never todos
+	tasks, err := p.GetTasks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "Announce the release" {
+		t.Errorf("Expected only the open task, got %+v", tasks)
+	}
+}
+
+func TestProvider_findCompletedTasks_IncludeTypes(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "Project.md"), []byte(completedContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	p := NewProvider(provider.Config{URL: tempDir, Enabled: true, IncludeTypes: []string{"task"}})
+	from := time.Date(2024, 5, 29, 0, 0, 0, 0, time.Local)
+	activities, err := p.GetActivities(context.Background(), from, from.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 0 {
+		t.Errorf("Expected completed tasks to be excluded, got %+v", activities)
+	}
+}
+
+func TestDayInRange(t *testing.T) {
+	midday := time.Date(2024, 5, 30, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		from, to time.Time
+		expected time.Time // Zero when the day is out of range
+	}{
+		{name: "whole day", from: midday.Add(-24 * time.Hour), to: midday.Add(24 * time.Hour), expected: midday},
+		{name: "range ending this morning", from: midday.Add(-24 * time.Hour), to: midday.Add(-3 * time.Hour), expected: midday.Add(-3 * time.Hour)},
+		{name: "range starting this evening", from: midday.Add(6 * time.Hour), to: midday.Add(48 * time.Hour), expected: midday.Add(6 * time.Hour)},
+		{name: "day before", from: midday.Add(12 * time.Hour), to: midday.Add(48 * time.Hour)},
+		{name: "day after", from: midday.Add(-48 * time.Hour), to: midday.Add(-12*time.Hour - time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timestamp, ok := dayInRange(midday, tt.from, tt.to)
+			if ok != !tt.expected.IsZero() {
+				t.Fatalf("Expected in range %v, got %v", !tt.expected.IsZero(), ok)
+			}
+			if ok && !timestamp.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, timestamp)
+			}
+		})
+	}
+}
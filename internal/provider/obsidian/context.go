@@ -0,0 +1,101 @@
+package obsidian
+
+import (
+	"regexp"
+	"strings"
+)
+
+// contextSeparator joins the note name and the headings a task is under
+const contextSeparator = " › "
+
+// headingPattern matches ATX headings (# Title, ## Title ##), capturing the level and text
+var headingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.+?)(?:\s+#+)?\s*$`)
+
+// listItemPattern matches list items, capturing their indentation
+var listItemPattern = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+\.)\s`)
+
+type heading struct {
+	level int
+	text  string
+}
+
+type parentTask struct {
+	indent int
+	text   string
+}
+
+// taskContext tracks the headings and the parent tasks of the lines of a note, as they are scanned
+type taskContext struct {
+	headings []heading
+	tasks    []parentTask
+}
+
+// heading updates the heading stack when the line is a heading, reporting whether it is one.
+// A heading replaces the headings of the same or a lower level, and ends the current list.
+func (c *taskContext) heading(line string) bool {
+	matches := headingPattern.FindStringSubmatch(line)
+	if matches == nil {
+		return false
+	}
+
+	level := len(matches[1])
+	for len(c.headings) > 0 && c.headings[len(c.headings)-1].level >= level {
+		c.headings = c.headings[:len(c.headings)-1]
+	}
+	c.headings = append(c.headings, heading{level: level, text: strings.TrimSpace(matches[2])})
+	c.tasks = nil
+	return true
+}
+
+// path returns the headings the current line is under, e.g. "Client X › Meeting 2024-05-12"
+func (c *taskContext) path() string {
+	texts := make([]string, len(c.headings))
+	for i, h := range c.headings {
+		texts[i] = h.text
+	}
+	return strings.Join(texts, contextSeparator)
+}
+
+// listItem updates the parent tasks with a line, returning the text of the task the line is
+// nested under. taskText is empty when the line isn't a task. Tasks are pushed whatever their
+// state, so subtasks of done tasks keep their parent. Lines outside lists end the current
+// list, blank and indented lines don't.
+func (c *taskContext) listItem(line, taskText string) string {
+	matches := listItemPattern.FindStringSubmatch(line)
+	if matches == nil {
+		if strings.TrimSpace(line) != "" && indentation(line) == 0 {
+			c.tasks = nil
+		}
+		return ""
+	}
+
+	indent := indentation(matches[1])
+	for len(c.tasks) > 0 && c.tasks[len(c.tasks)-1].indent >= indent {
+		c.tasks = c.tasks[:len(c.tasks)-1]
+	}
+
+	parent := ""
+	if len(c.tasks) > 0 {
+		parent = c.tasks[len(c.tasks)-1].text
+	}
+	if taskText != "" {
+		c.tasks = append(c.tasks, parentTask{indent: indent, text: normalizeTaskTitle(taskText)})
+	}
+	return parent
+}
+
+// indentation returns the width of the leading whitespace of a line, tabs counting as 4 spaces
+func indentation(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
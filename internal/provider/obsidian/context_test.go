@@ -0,0 +1,87 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+const contextContent = "- [ ] Before any heading\n" +
+	"# Clients\n" +
+	"## Client X\n" +
+	"### Meeting 2024-05-12 ###\n" +
+	"- [ ] Follow up\n" +
+	"    - [ ] Send the slides\n" +
+	"\t\t- [ ] Book a room\n" +
+	"    - [/] Draft the proposal\n" +
+	"- [x] Sign the contract\n" +
+	"  - [ ] Countersign\n" +
+	"## Client Y\n" +
+	"```\n" +
+	"# Not a heading\n" +
+	"- [ ] Not a task\n" +
+	"```\n" +
+	"- [ ] Call back\n" +
+	"Some text\n" +
+	"  - [ ] Not nested\n" +
+	"# Personal\n" +
+	"#groceries are not a heading\n" +
+	"- [ ] Buy milk\n"
+
+func TestProvider_parseTasksFromFile_Context(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "Meeting Notes.md")
+	if err := os.WriteFile(filePath, []byte(contextContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("Failed to stat test file: %v", err)
+	}
+
+	p := NewProvider(provider.Config{URL: tempDir, Enabled: true})
+	tasks, err := p.parseTasksFromFile(filePath, fileInfo)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	meeting := "Clients › Client X › Meeting 2024-05-12"
+	expected := []struct {
+		title, context, parent string
+	}{
+		{"Before any heading", "", ""},
+		{"Follow up", meeting, ""},
+		{"Send the slides", meeting, "Follow up"},
+		{"Book a room", meeting, "Send the slides"},
+		{"Draft the proposal", meeting, "Follow up"},
+		{"Countersign", meeting, "Sign the contract"},
+		{"Call back", "Clients › Client Y", ""},
+		{"Not nested", "Clients › Client Y", ""},
+		{"Buy milk", "Personal", ""},
+	}
+
+	if len(tasks) != len(expected) {
+		t.Fatalf("Expected %d tasks, got %d: %+v", len(expected), len(tasks), tasks)
+	}
+	for i, want := range expected {
+		task := tasks[i]
+		if task.Title != want.title {
+			t.Errorf("Expected task %d to be '%s', got '%s'", i, want.title, task.Title)
+		}
+		if task.Context != want.context {
+			t.Errorf("Expected context of '%s' to be '%s', got '%s'", want.title, want.context, task.Context)
+		}
+		if task.ParentTask != want.parent {
+			t.Errorf("Expected parent of '%s' to be '%s', got '%s'", want.title, want.parent, task.ParentTask)
+		}
+	}
+
+	if tasks[0].Description != "Task in Meeting Notes" {
+		t.Errorf("Expected description without context, got '%s'", tasks[0].Description)
+	}
+	if tasks[1].Description != "Task in Meeting Notes › "+meeting {
+		t.Errorf("Expected description with context, got '%s'", tasks[1].Description)
+	}
+}
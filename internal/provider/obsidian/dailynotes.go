@@ -0,0 +1,69 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultDailyNoteFormat is the name layout of the notes created by the Daily notes core plugin
+const defaultDailyNoteFormat = "2006-01-02"
+
+// dailyNoteDate returns the date a daily note is named after, at midday local time, for a
+// path relative to the vault root. The layout may contain slashes for notes stored in dated
+// subfolders, e.g. "2006/01/2006-01-02".
+func (p *Provider) dailyNoteDate(relPath string) (time.Time, bool) {
+	name := strings.TrimSuffix(filepath.ToSlash(relPath), ".md")
+	if folder := strings.Trim(filepath.ToSlash(p.config.DailyNotesFolder), "/"); folder != "" {
+		var ok bool
+		if name, ok = strings.CutPrefix(name, folder+"/"); !ok {
+			return time.Time{}, false
+		}
+	}
+
+	layout := p.config.DailyNoteFormat
+	if layout == "" {
+		layout = defaultDailyNoteFormat
+	}
+
+	// Parse as many trailing path segments as the layout has
+	segments := strings.Split(name, "/")
+	count := strings.Count(layout, "/") + 1
+	if len(segments) < count {
+		return time.Time{}, false
+	}
+	date, err := time.ParseInLocation(layout, strings.Join(segments[len(segments)-count:], "/"), time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.Local), true
+}
+
+// activityTime returns the timestamp of the activities of a note, and whether it falls in
+// the range: the date daily notes are named after, so that editing yesterday's note today
+// doesn't move it to today, and the modification time of other notes
+func (p *Provider) activityTime(path string, info os.FileInfo, from, to time.Time) (time.Time, bool) {
+	relPath, _ := filepath.Rel(p.vaultPath, path)
+	if date, ok := p.dailyNoteDate(relPath); ok {
+		return dayInRange(date, from, to)
+	}
+	modTime := info.ModTime()
+	return modTime, !modTime.Before(from) && !modTime.After(to)
+}
+
+// dayInRange reports whether the day of date overlaps the range, returning date moved into
+// the range, so that a note named after today is in a range ending this morning
+func dayInRange(date, from, to time.Time) (time.Time, bool) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	if !start.AddDate(0, 0, 1).After(from) || start.After(to) {
+		return time.Time{}, false
+	}
+	switch {
+	case date.Before(from):
+		return from, true
+	case date.After(to):
+		return to, true
+	}
+	return date, true
+}
@@ -0,0 +1,98 @@
+package obsidian
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+func TestProvider_dailyNoteDate(t *testing.T) {
+	may12 := time.Date(2024, time.May, 12, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		format   string
+		folder   string
+		relPath  string
+		expected time.Time // Zero when the note isn't a daily note
+	}{
+		{name: "default format", relPath: "2024-05-12.md", expected: may12},
+		{name: "default format in a folder", relPath: "Journal/2024-05-12.md", expected: may12},
+		{name: "regular note", relPath: "Meeting Notes.md"},
+		{name: "date with a suffix", relPath: "2024-05-12 Retro.md"},
+		{name: "invalid date", relPath: "2024-13-45.md"},
+		{name: "in the configured folder", folder: "Daily", relPath: "Daily/2024-05-12.md", expected: may12},
+		{name: "outside the configured folder", folder: "Daily", relPath: "Archive/2024-05-12.md"},
+		{name: "custom format", format: "02.01.2006", relPath: "12.05.2024.md", expected: may12},
+		{name: "custom format with weekday", format: "2006-01-02 Monday", relPath: "Journal/2024-05-12 Sunday.md", expected: may12},
+		{name: "custom format mismatch", format: "02.01.2006", relPath: "2024-05-12.md"},
+		{name: "dated subfolders", format: "2006/01/2006-01-02", folder: "Daily/", relPath: "Daily/2024/05/2024-05-12.md", expected: may12},
+		{name: "dated subfolders missing", format: "2006/01/2006-01-02", relPath: "2024-05-12.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(provider.Config{URL: "/vault", Enabled: true, DailyNoteFormat: tt.format, DailyNotesFolder: tt.folder})
+
+			date, ok := p.dailyNoteDate(tt.relPath)
+			if ok != !tt.expected.IsZero() {
+				t.Fatalf("Expected daily note %v, got %v", !tt.expected.IsZero(), ok)
+			}
+			if ok && !date.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, date)
+			}
+		})
+	}
+}
+
+func TestProvider_GetActivities_DailyNotes(t *testing.T) {
+	tempDir := t.TempDir()
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	lastWeek := now.AddDate(0, 0, -7)
+
+	// Daily notes are dated by name whatever their modification time
+	notes := []struct {
+		name    string
+		modTime time.Time
+	}{
+		{name: yesterday.Format("2006-01-02") + ".md", modTime: lastWeek},
+		{name: lastWeek.Format("2006-01-02") + ".md", modTime: now},
+		{name: "Project.md", modTime: now},
+	}
+	for _, note := range notes {
+		path := filepath.Join(tempDir, note.name)
+		if err := os.WriteFile(path, []byte("- [ ] Follow up\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		if err := os.Chtimes(path, note.modTime, note.modTime); err != nil {
+			t.Fatalf("Failed to set mod time: %v", err)
+		}
+	}
+
+	p := NewProvider(provider.Config{URL: tempDir, Enabled: true})
+	activities, err := p.GetActivities(context.Background(), now.AddDate(0, 0, -2), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	midday := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 12, 0, 0, 0, time.Local)
+	found := make(map[string]time.Time)
+	for _, act := range activities {
+		found[string(act.Type)+" "+act.Title] = act.Timestamp
+	}
+	if len(activities) != 4 {
+		t.Errorf("Expected a note and a task from yesterday's note and the project, got %v", found)
+	}
+	if timestamp, ok := found[string(activity.ActivityTypeNote)+" "+yesterday.Format("2006-01-02")]; !ok || !timestamp.Equal(midday) {
+		t.Errorf("Expected yesterday's note at %v, got %v (found %v)", midday, timestamp, ok)
+	}
+	if _, ok := found[string(activity.ActivityTypeNote)+" "+lastWeek.Format("2006-01-02")]; ok {
+		t.Error("Expected last week's note edited today to be left out")
+	}
+}
@@ -0,0 +1,197 @@
+package obsidian
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// skipStats counts the paths left out of the last vault scan
+type skipStats struct {
+	dirs    int
+	files   int
+	ignored int // Folders and files among them left out by the ignore file
+	broken  int // Symlinks whose target is missing, with follow_symlinks
+	loops   int // Symlinked folders already walked, with follow_symlinks
+	binary  int // Notes that look binary, whose tasks can't be read
+}
+
+// isExcluded reports whether a path relative to the vault root matches one of the
+// exclude_paths patterns
+func (p *Provider) isExcluded(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	for _, pattern := range p.config.ExcludePaths {
+		if matchGlob(strings.Trim(pattern, "/"), relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash separated path against a pattern where "*" matches within a
+// path segment and a "**" segment matches any number of segments, e.g. "**/node_modules"
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// walkNotes calls fn for every markdown file of the vault, in lexical order. Hidden
+// directories (e.g. .obsidian and .trash) and the paths matching exclude_paths or the ignore
+// file of the vault are skipped, and counted for Skipped. With follow_symlinks, symlinked
+// folders are walked too, each folder once so a link to a parent can't loop forever, and
+// broken symlinks are skipped. The walk stops with the context error when ctx is cancelled.
+func (p *Provider) walkNotes(ctx context.Context, fn func(path string, info os.FileInfo) error) error {
+	p.skipped = skipStats{}
+
+	ignore, err := loadIgnoreFile(p.vaultPath)
+	if err != nil {
+		return err
+	}
+	// isIgnored reports whether the ignore file leaves out a path not excluded by the config
+	isIgnored := func(relPath string, isDir bool) bool {
+		if !ignore.ignored(relPath, isDir) {
+			return false
+		}
+		p.skipped.ignored++
+		return true
+	}
+
+	// visited holds the folders walked so far when following symlinks
+	var visited map[fileID]bool
+	if p.config.FollowSymlinks {
+		info, err := os.Stat(p.vaultPath)
+		if err != nil {
+			return err
+		}
+		visited = make(map[fileID]bool)
+		if id, ok := folderID(p.vaultPath, info); ok {
+			visited[id] = true
+		}
+	}
+
+	var walk func(dir, relDir string) error
+	walk = func(dir, relDir string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			relPath := filepath.Join(relDir, entry.Name())
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+
+			if info.Mode()&os.ModeSymlink != 0 && p.config.FollowSymlinks {
+				target, err := os.Stat(path)
+				if err != nil {
+					p.skipped.broken++
+					continue
+				}
+				info = target
+			}
+
+			if info.IsDir() {
+				if strings.HasPrefix(info.Name(), ".") || p.isExcluded(relPath) || isIgnored(relPath, true) {
+					p.skipped.dirs++
+					continue
+				}
+				if visited != nil {
+					if id, ok := folderID(path, info); ok {
+						if visited[id] {
+							p.skipped.loops++
+							continue
+						}
+						visited[id] = true
+					}
+				}
+				if err := walk(path, relPath); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// Only process .md files
+			if !strings.HasSuffix(info.Name(), ".md") {
+				continue
+			}
+
+			if p.isExcluded(relPath) || isIgnored(relPath, false) {
+				p.skipped.files++
+				continue
+			}
+
+			if err := fn(path, info); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return walk(p.vaultPath, "")
+}
+
+// Skipped describes the folders and files left out of the last vault scan, e.g.
+// "2 folders and 1 file (1 by .dailyignore)", or "" when nothing was skipped. Broken
+// symlinks, symlinks to folders already walked and notes that look binary are told apart,
+// e.g. "0 folders and 0 files, 1 broken symlink and 1 symlink loop".
+func (p *Provider) Skipped() string {
+	if p.skipped == (skipStats{}) {
+		return ""
+	}
+	skipped := fmt.Sprintf("%s and %s", countNoun(p.skipped.dirs, "folder"), countNoun(p.skipped.files, "file"))
+	if p.skipped.ignored > 0 {
+		skipped += fmt.Sprintf(" (%d by %s)", p.skipped.ignored, ignoreFileName)
+	}
+
+	var links []string
+	if p.skipped.binary > 0 {
+		links = append(links, countNoun(p.skipped.binary, "binary file"))
+	}
+	if p.skipped.broken > 0 {
+		links = append(links, countNoun(p.skipped.broken, "broken symlink"))
+	}
+	if p.skipped.loops > 0 {
+		links = append(links, countNoun(p.skipped.loops, "symlink loop"))
+	}
+	if len(links) > 0 {
+		skipped += ", " + strings.Join(links, " and ")
+	}
+	return skipped
+}
+
+func countNoun(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,184 @@
+package obsidian
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+// newExcludeVault creates a vault with a task in every note, in folders to scan and to skip
+func newExcludeVault(t *testing.T) string {
+	vault := t.TempDir()
+	for _, note := range []string{
+		"Inbox.md",
+		"Projects/API.md",
+		"Projects/Drafts/Idea.md",
+		"Templates/Daily.md",
+		"Archive/2023/Old.md",
+		"plugins/kanban/node_modules/pkg/README.md",
+		".obsidian/snippets.md",
+		".trash/Deleted.md",
+	} {
+		path := filepath.Join(vault, note)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create test folder: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("- [ ] Task in "+note+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	return vault
+}
+
+func TestProvider_ExcludePaths(t *testing.T) {
+	tests := []struct {
+		name            string
+		excludePaths    []string
+		expectedNotes   []string
+		expectedSkipped string
+	}{
+		{
+			name:            "hidden folders only",
+			expectedNotes:   []string{"Archive/2023/Old.md", "Inbox.md", "Projects/API.md", "Projects/Drafts/Idea.md", "Templates/Daily.md", "plugins/kanban/node_modules/pkg/README.md"},
+			expectedSkipped: "2 folders and 0 files",
+		},
+		{
+			name:            "folders and globs",
+			excludePaths:    []string{"Templates/", "Archive", "**/node_modules", "Projects/*/Idea.md"},
+			expectedNotes:   []string{"Inbox.md", "Projects/API.md"},
+			expectedSkipped: "5 folders and 1 file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := newExcludeVault(t)
+			p := NewProvider(provider.Config{URL: vault, Enabled: true, ExcludePaths: tt.excludePaths})
+
+			tasks, err := p.GetTasks(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			var taskNotes []string
+			for _, task := range tasks {
+				taskNotes = append(taskNotes, strings.TrimPrefix(task.Title, "Task in "))
+			}
+			slices.Sort(taskNotes)
+			if !slices.Equal(taskNotes, tt.expectedNotes) {
+				t.Errorf("Expected tasks from %v, got %v", tt.expectedNotes, taskNotes)
+			}
+			if got := p.Skipped(); got != tt.expectedSkipped {
+				t.Errorf("Expected skipped %q, got %q", tt.expectedSkipped, got)
+			}
+
+			activities, err := p.GetActivities(context.Background(), time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			var notes []string
+			for _, act := range activities {
+				if act.Type == activity.ActivityTypeNote {
+					notes = append(notes, filepath.ToSlash(strings.TrimPrefix(act.ID, "obsidian-")))
+				}
+			}
+			slices.Sort(notes)
+			if !slices.Equal(notes, tt.expectedNotes) {
+				t.Errorf("Expected notes %v, got %v", tt.expectedNotes, notes)
+			}
+		})
+	}
+}
+
+func TestProvider_FollowSymlinks(t *testing.T) {
+	vault := t.TempDir()
+	shared := t.TempDir()
+	for path, content := range map[string]string{
+		filepath.Join(vault, "Inbox.md"): "- [ ] Task in Inbox.md\n",
+		filepath.Join(shared, "Plan.md"): "- [ ] Task in Shared/Plan.md\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	links := map[string]string{
+		filepath.Join(vault, "Shared"):  shared,                                // Valid link to a folder out of the vault
+		filepath.Join(vault, "Missing"): filepath.Join(t.TempDir(), "missing"), // Broken link
+		filepath.Join(vault, "Loop"):    vault,                                 // Link to the vault itself
+		filepath.Join(shared, "Back"):   vault,                                 // Cycle through the shared folder
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("Symlinks not supported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name            string
+		followSymlinks  bool
+		expectedNotes   []string
+		expectedSkipped string
+	}{
+		{name: "symlinks not followed", expectedNotes: []string{"Inbox.md"}},
+		{
+			name:            "symlinks followed",
+			followSymlinks:  true,
+			expectedNotes:   []string{"Inbox.md", "Shared/Plan.md"},
+			expectedSkipped: "0 folders and 0 files, 1 broken symlink and 2 symlink loops",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(provider.Config{URL: vault, Enabled: true, FollowSymlinks: tt.followSymlinks})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			tasks, err := p.GetTasks(ctx)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			var taskNotes []string
+			for _, task := range tasks {
+				taskNotes = append(taskNotes, strings.TrimPrefix(task.Title, "Task in "))
+			}
+			slices.Sort(taskNotes)
+			if !slices.Equal(taskNotes, tt.expectedNotes) {
+				t.Errorf("Expected tasks from %v, got %v", tt.expectedNotes, taskNotes)
+			}
+			if got := p.Skipped(); got != tt.expectedSkipped {
+				t.Errorf("Expected skipped %q, got %q", tt.expectedSkipped, got)
+			}
+		})
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"Templates", "Templates", true},
+		{"Templates", "Projects/Templates", false},
+		{"**/Templates", "Projects/Templates", true},
+		{"**/node_modules", "node_modules", true},
+		{"Archive/**", "Archive/2023/Old.md", true},
+		{"*.excalidraw.md", "Drawing.excalidraw.md", true},
+		{"*.excalidraw.md", "Projects/Drawing.excalidraw.md", false},
+		{"Projects/*/Idea.md", "Projects/Drafts/Idea.md", true},
+		{"Projects/*/Idea.md", "Projects/Idea.md", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.expected {
+			t.Errorf("matchGlob(%q, %q): expected %v, got %v", tt.pattern, tt.name, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package obsidian
+
+// fileID identifies a folder whatever the path it is reached by, to walk it once when
+// following symlinks: its device and inode where the platform has them, else its path with
+// the symlinks resolved
+type fileID struct {
+	dev, ino uint64
+	path     string
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// folderID returns the path of a folder with its symlinks resolved, as inodes aren't
+// available on the other platforms
+func folderID(path string, _ os.FileInfo) (fileID, bool) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return fileID{}, false
+	}
+	return fileID{path: resolved}, true
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package obsidian
+
+import (
+	"os"
+	"syscall"
+)
+
+// folderID returns the device and inode of a folder from its stat result
+func folderID(_ string, info os.FileInfo) (fileID, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}, true
+}
@@ -0,0 +1,128 @@
+package obsidian
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// frontmatter holds the properties of the YAML block at the top of a note
+type frontmatter struct {
+	lines   int       // Number of lines of the block, delimiters included, 0 without frontmatter
+	tags    []string  // Tags without their leading #
+	created time.Time // Creation date of the created property, zero when missing or invalid
+	kind    string    // Value of the type property, e.g. "meeting"
+}
+
+// readFrontmatter reads the frontmatter of a note: a block starting with "---" on the first
+// line and ending with "---" or "...". A block that is never closed isn't frontmatter.
+func readFrontmatter(filePath string, maxLineLength int) (frontmatter, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return frontmatter{}, err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner, err := newLineScanner(file, maxLineLength)
+	if err != nil {
+		return frontmatter{}, err
+	}
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "---" {
+		return frontmatter{}, scanner.Err()
+	}
+
+	var lines []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if trimmed := strings.TrimSpace(line); trimmed == "---" || trimmed == "..." {
+			return frontmatter{
+				lines:   len(lines) + 2,
+				tags:    parseFrontmatterTags(lines),
+				created: parseFrontmatterCreated(lines),
+				kind:    frontmatterValue(lines, "type"),
+			}, nil
+		}
+		lines = append(lines, line)
+	}
+	return frontmatter{}, scanner.Err()
+}
+
+// parseFrontmatterTags returns the tags of the tags (or tag) property, written as a flow
+// list (tags: [a, b]), a block list (tags:\n  - a) or a string (tags: a, b)
+func parseFrontmatterTags(lines []string) []string {
+	var tags []string
+	add := func(tag string) {
+		tag = strings.TrimPrefix(strings.Trim(strings.TrimSpace(tag), `"'`), "#")
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], " ") || strings.HasPrefix(lines[i], "\t") {
+			continue
+		}
+		key, value, ok := strings.Cut(lines[i], ":")
+		if key = strings.TrimSpace(key); !ok || (key != "tags" && key != "tag") {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			for i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "-") {
+				i++
+				add(strings.TrimPrefix(strings.TrimSpace(lines[i]), "-"))
+			}
+			continue
+		}
+
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+		for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+			add(tag)
+		}
+	}
+	return tags
+}
+
+// createdLayouts are the layouts of the created property: a date, a local date and time as
+// written by the Obsidian date properties, or a timestamp with its offset
+var createdLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// parseFrontmatterCreated returns the date of the created property, in local time unless
+// it has an offset
+func parseFrontmatterCreated(lines []string) time.Time {
+	value := frontmatterValue(lines, "created")
+	for _, layout := range createdLayouts {
+		if created, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return created
+		}
+	}
+	return time.Time{}
+}
+
+// frontmatterValue returns the unquoted value of a top-level property written on one line,
+// empty when missing
+func frontmatterValue(lines []string, name string) string {
+	for _, line := range lines {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.TrimSpace(key) == name {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// appendTags appends the tags missing from existing
+func appendTags(existing []string, tags ...string) []string {
+	for _, tag := range tags {
+		if !slices.Contains(existing, tag) {
+			existing = append(existing, tag)
+		}
+	}
+	return existing
+}
@@ -0,0 +1,105 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+func TestReadFrontmatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		lines    int
+		expected []string
+	}{
+		{
+			name:     "flow list",
+			content:  "---\ntitle: Retro\ntags: [project/web, \"#meeting\"]\n---\n- [ ] Task\n",
+			lines:    4,
+			expected: []string{"project/web", "meeting"},
+		},
+		{
+			name:     "block list",
+			content:  "---\ntags:\n  - project/web\n  - 'meeting'\naliases:\n  - Retro\n...\n",
+			lines:    7,
+			expected: []string{"project/web", "meeting"},
+		},
+		{
+			name:     "string",
+			content:  "---\ntag: project/web, meeting\n---\n",
+			lines:    3,
+			expected: []string{"project/web", "meeting"},
+		},
+		{
+			name:    "no tags",
+			content: "---\ntitle: Retro\n---\n",
+			lines:   3,
+		},
+		{
+			name:    "no frontmatter",
+			content: "# Retro\ntags: [meeting]\n",
+		},
+		{
+			name:    "horizontal rule never closed",
+			content: "---\ntags: [meeting]\n- [ ] Task\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "note.md")
+			if err := os.WriteFile(filePath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			meta, err := readFrontmatter(filePath, defaultMaxLineLength)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if meta.lines != tt.lines {
+				t.Errorf("Expected %d frontmatter lines, got %d", tt.lines, meta.lines)
+			}
+			if !slices.Equal(meta.tags, tt.expected) {
+				t.Errorf("Expected tags %v, got %v", tt.expected, meta.tags)
+			}
+		})
+	}
+}
+
+func TestProvider_parseTasksFromFile_Frontmatter(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "Retro.md")
+	content := "---\ntags: [meeting, urgent]\n# not a heading\n- [ ] not a task\n---\n## Actions\n- [ ] Share notes #urgent\n"
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("Failed to stat test file: %v", err)
+	}
+
+	p := NewProvider(provider.Config{URL: tempDir, Enabled: true})
+	tasks, err := p.parseTasksFromFile(filePath, fileInfo)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("Expected 1 task, got %d: %+v", len(tasks), tasks)
+	}
+
+	task := tasks[0]
+	if !slices.Equal(task.Tags, []string{"urgent", "high-priority", "state:space", "meeting"}) {
+		t.Errorf("Expected the note tags after the task tags, got %v", task.Tags)
+	}
+	if task.Context != "Actions" || task.ID != "obsidian-task-Retro.md:7" {
+		t.Errorf("Expected the task under Actions on line 7, got '%s' and '%s'", task.Context, task.ID)
+	}
+	if state := task.Details[activity.DetailState]; state != "space" {
+		t.Errorf("Expected the checkbox state in the details, got '%s'", state)
+	}
+}
@@ -0,0 +1,172 @@
+package obsidian
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"daily/internal/activity"
+)
+
+// commitMarker prefixes each commit header in the git log output so commits can be counted
+const commitMarker = "@@commit "
+
+// isGitVault reports whether the vault is a git repository and git is available
+func (p *Provider) isGitVault() bool {
+	if _, err := os.Stat(filepath.Join(p.vaultPath, ".git")); err != nil {
+		return false
+	}
+	_, err := exec.LookPath("git")
+	return err == nil
+}
+
+// gitHistory is what the git history of the vault tells about the notes changed in a range
+type gitHistory struct {
+	committed map[string]time.Time            // Time of the last commit in the range, by slash separated path
+	changes   map[string]activity.ChangeStats // Lines changed in the range, uncommitted changes included, by slash separated path
+	pending   map[string]bool                 // Notes with uncommitted changes, when the range reaches the present
+}
+
+// gitNoteHistory reads the notes committed between from and to and the lines they changed
+// from a single git log, then the uncommitted changes when the range reaches the present
+func (p *Provider) gitNoteHistory(ctx context.Context, from, to time.Time) (*gitHistory, error) {
+	out, err := p.runGit(ctx, "log",
+		"--since="+from.Format(time.RFC3339),
+		"--until="+to.Format(time.RFC3339),
+		"--numstat",
+		"--no-renames",
+		"--format="+commitMarker+"%ct",
+		"--", "*.md")
+	if err != nil {
+		return nil, err
+	}
+	committed, changes := parseNumstatLog(out)
+	history := &gitHistory{committed: committed, changes: changes, pending: make(map[string]bool)}
+
+	if !to.Before(time.Now()) {
+		status, err := p.runGit(ctx, "status", "--porcelain", "-z", "--untracked-files=all")
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range parseStatusPaths(status) {
+			history.pending[path] = true
+		}
+
+		// A repository without commits has no HEAD to diff against
+		if diff, err := p.runGit(ctx, "diff", "HEAD", "--numstat", "--no-renames", "--", "*.md"); err == nil {
+			_, uncommitted := parseNumstatLog(diff)
+			for path, pending := range uncommitted {
+				stats := history.changes[path]
+				stats.Additions += pending.Additions
+				stats.Deletions += pending.Deletions
+				history.changes[path] = stats
+			}
+		}
+	}
+	return history, nil
+}
+
+// noteTime returns the time of the last commit of a note in the range, or its modification
+// time for a note with uncommitted changes, and whether the note changed in the range
+func (h *gitHistory) noteTime(p *Provider, relPath, path string, info os.FileInfo, from, to time.Time) (time.Time, bool) {
+	relPath = filepath.ToSlash(relPath)
+	if h.pending[relPath] {
+		if timestamp, ok := p.activityTime(path, info, from, to); ok {
+			return timestamp, true
+		}
+	}
+	timestamp, ok := h.committed[relPath]
+	return timestamp, ok
+}
+
+// changeStats returns the lines added and removed in a note in the range, or nil without any
+func (h *gitHistory) changeStats(relPath string) *activity.ChangeStats {
+	stats, ok := h.changes[filepath.ToSlash(relPath)]
+	if !ok || (stats.Additions == 0 && stats.Deletions == 0) {
+		return nil
+	}
+	return &stats
+}
+
+// parseNumstatLog returns the time of the latest commit of each file of git log --numstat
+// output, whose commits are listed newest first, and the lines changed in each file. Lines
+// of git diff --numstat output, outside of any commit, only count as changes.
+func parseNumstatLog(out string) (map[string]time.Time, map[string]activity.ChangeStats) {
+	committed := make(map[string]time.Time)
+	changes := make(map[string]activity.ChangeStats)
+	var commitTime time.Time
+	inCommit := false
+	for _, line := range strings.Split(out, "\n") {
+		if header, ok := strings.CutPrefix(line, commitMarker); ok {
+			inCommit = true
+			commitTime = time.Time{}
+			if seconds, err := strconv.ParseInt(strings.TrimSpace(header), 10, 64); err == nil {
+				commitTime = time.Unix(seconds, 0)
+			}
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		path := fields[2]
+		if _, ok := committed[path]; !ok && !commitTime.IsZero() {
+			committed[path] = commitTime
+		}
+
+		stats := changes[path]
+		if inCommit {
+			stats.Commits++
+		}
+		// Binary files report "-" for both columns
+		added, errAdd := strconv.Atoi(fields[0])
+		deleted, errDel := strconv.Atoi(fields[1])
+		if errAdd == nil && errDel == nil {
+			stats.Additions += added
+			stats.Deletions += deleted
+		}
+		changes[path] = stats
+	}
+	return committed, changes
+}
+
+// parseStatusPaths returns the paths of git status --porcelain -z output. Renamed and copied
+// entries are followed by their original path, which is left out.
+func parseStatusPaths(out string) []string {
+	var paths []string
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		paths = append(paths, entry[3:])
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+	}
+	return paths
+}
+
+func (p *Provider) runGit(ctx context.Context, args ...string) (string, error) {
+	// Paths are listed as is rather than quoted when they aren't ASCII
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", p.vaultPath, "-c", "core.quotePath=false"}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// describeChanges describes the line changes of a note, e.g. "+2/-1 lines in 1 commit"
+func describeChanges(changes activity.ChangeStats) string {
+	if changes.Commits == 0 {
+		return changes.String()
+	}
+	return fmt.Sprintf("%s in %s", changes.String(), countNoun(changes.Commits, "commit"))
+}
@@ -0,0 +1,283 @@
+package obsidian
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+// setupGitVault creates a vault with two commits touching note.md at the given times
+func setupGitVault(t *testing.T, first, second time.Time) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	git := func(date time.Time, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_AUTHOR_DATE="+date.Format(time.RFC3339),
+			"GIT_COMMITTER_DATE="+date.Format(time.RFC3339),
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	notePath := filepath.Join(dir, "note.md")
+	git(first, "init", "-q")
+	if err := os.WriteFile(notePath, []byte("# Note\nline one\nline two\n"), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+	git(first, "add", "note.md")
+	git(first, "commit", "-q", "-m", "first")
+
+	if err := os.WriteFile(notePath, []byte("# Note\nline one\nline three\nline four\n"), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+	git(second, "commit", "-q", "-am", "second")
+
+	return dir
+}
+
+func TestProvider_GitNoteHistory(t *testing.T) {
+	now := time.Now()
+	dir := setupGitVault(t, now.Add(-48*time.Hour), now.Add(-2*time.Hour))
+	p := NewProvider(provider.Config{URL: dir, Enabled: true})
+
+	if !p.isGitVault() {
+		t.Fatal("Expected vault to be detected as a git repository")
+	}
+
+	// Only the second commit falls within the last day
+	history, err := p.gitNoteHistory(context.Background(), now.Add(-24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	stats := history.changeStats("note.md")
+	if stats == nil {
+		t.Fatal("Expected change stats, got nil")
+	}
+	if stats.Additions != 2 || stats.Deletions != 1 {
+		t.Errorf("Expected +2/-1, got +%d/-%d", stats.Additions, stats.Deletions)
+	}
+	if stats.Commits != 1 {
+		t.Errorf("Expected 1 commit, got %d", stats.Commits)
+	}
+	if stats.String() != "+2/-1 lines" {
+		t.Errorf("Expected '+2/-1 lines', got '%s'", stats.String())
+	}
+
+	// Uncommitted changes count when the range reaches the present
+	if err := os.WriteFile(filepath.Join(dir, "note.md"), []byte("# Note\nline one\nline three\nline four\nline five\n"), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+	history, err = p.gitNoteHistory(context.Background(), now.Add(-24*time.Hour), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if stats := history.changeStats("note.md"); stats == nil || stats.Additions != 3 || stats.Commits != 1 || !history.pending["note.md"] {
+		t.Errorf("Expected the uncommitted line added to the commit, got %+v", stats)
+	}
+
+	// No commits in a range before the history starts
+	history, err = p.gitNoteHistory(context.Background(), now.Add(-96*time.Hour), now.Add(-72*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if stats := history.changeStats("note.md"); stats != nil {
+		t.Errorf("Expected nil stats for empty range, got %+v", stats)
+	}
+}
+
+func TestProvider_GetActivities_GitChanges(t *testing.T) {
+	now := time.Now()
+	dir := setupGitVault(t, now.Add(-48*time.Hour), now.Add(-2*time.Hour))
+	p := NewProvider(provider.Config{URL: dir, Enabled: true})
+
+	// The note was written just now, so extend the range past its mtime
+	activities, err := p.GetActivities(context.Background(), now.Add(-24*time.Hour), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 1 {
+		t.Fatalf("Expected 1 activity, got %d", len(activities))
+	}
+	if activities[0].Changes == nil {
+		t.Fatal("Expected note activity to carry change stats")
+	}
+	if activities[0].Changes.Additions != 2 {
+		t.Errorf("Expected 2 additions, got %d", activities[0].Changes.Additions)
+	}
+}
+
+func TestProvider_GetActivities_BrokenGitRepo(t *testing.T) {
+	dir := t.TempDir()
+	// A bogus .git entry makes git fail, which must not fail the provider
+	if err := os.WriteFile(filepath.Join(dir, ".git"), []byte("not a repo"), 0644); err != nil {
+		t.Fatalf("Failed to write .git: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "note.md"), []byte("# Note\n"), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	p := NewProvider(provider.Config{URL: dir, Enabled: true})
+	activities, err := p.GetActivities(context.Background(), time.Now().Add(-time.Hour), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 1 {
+		t.Fatalf("Expected 1 activity, got %d", len(activities))
+	}
+	if activities[0].Changes != nil {
+		t.Errorf("Expected no change stats, got %+v", activities[0].Changes)
+	}
+	if warnings := p.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "git history") {
+		t.Errorf("Expected a warning for the git history, got %v", warnings)
+	}
+}
+
+func TestParseNumstatLog(t *testing.T) {
+	out := commitMarker + "1715590800\n\n3\t1\tDaily/2024-05-13.md\n1\t0\tnote.md\n" +
+		commitMarker + "1715504400\n\n2\t2\tnote.md\n-\t-\tMeetings/Platform sync.md\n"
+	committed, changes := parseNumstatLog(out)
+
+	expected := map[string]int64{
+		"Daily/2024-05-13.md":       1715590800,
+		"note.md":                   1715590800, // The latest commit wins
+		"Meetings/Platform sync.md": 1715504400,
+	}
+	if len(committed) != len(expected) {
+		t.Fatalf("Expected %d notes, got %v", len(expected), committed)
+	}
+	for path, seconds := range expected {
+		if got := committed[path]; got.Unix() != seconds {
+			t.Errorf("Expected %s committed at %d, got %v", path, seconds, got)
+		}
+	}
+
+	// Changes add up over the commits, binary files only counting commits
+	if stats := changes["note.md"]; stats.Additions != 3 || stats.Deletions != 2 || stats.Commits != 2 {
+		t.Errorf("Expected +3/-2 in 2 commits, got %+v", stats)
+	}
+	if stats := changes["Meetings/Platform sync.md"]; stats.Additions != 0 || stats.Commits != 1 {
+		t.Errorf("Expected 1 commit without lines, got %+v", stats)
+	}
+
+	// git diff output has no commits
+	committed, changes = parseNumstatLog("4\t0\tnote.md\n")
+	if len(committed) != 0 || changes["note.md"].Additions != 4 || changes["note.md"].Commits != 0 {
+		t.Errorf("Expected uncommitted changes only, got %v and %v", committed, changes)
+	}
+}
+
+func TestParseStatusPaths(t *testing.T) {
+	out := " M note.md\x00?? Inbox/New idea.md\x00R  Renamed.md\x00Old.md\x00A  Projects/API.md\x00"
+	paths := parseStatusPaths(out)
+
+	expected := []string{"note.md", "Inbox/New idea.md", "Renamed.md", "Projects/API.md"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+}
+
+func TestProvider_GetActivities_UseGit(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	first, second := now.Add(-48*time.Hour), now.Add(-2*time.Hour)
+	dir := setupGitVault(t, first, second)
+	// An uncommitted note, modified just now
+	if err := os.WriteFile(filepath.Join(dir, "draft.md"), []byte("# Draft\n"), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		useGit      bool
+		from, to    time.Time
+		expected    map[string]time.Time
+		description string
+	}{
+		{
+			name:        "second commit",
+			useGit:      true,
+			from:        now.Add(-24 * time.Hour),
+			to:          now.Add(-time.Hour),
+			expected:    map[string]time.Time{"obsidian-note.md": second},
+			description: "note: note.md (+2/-1 lines in 1 commit)",
+		},
+		{
+			name:        "first commit",
+			useGit:      true,
+			from:        now.Add(-72 * time.Hour),
+			to:          now.Add(-24 * time.Hour),
+			expected:    map[string]time.Time{"obsidian-note.md": first},
+			description: "note: note.md (+3/-0 lines in 1 commit)",
+		},
+		{
+			name:     "uncommitted notes up to now",
+			useGit:   true,
+			from:     now.Add(-time.Hour),
+			to:       now.Add(time.Minute),
+			expected: map[string]time.Time{"obsidian-draft.md": {}},
+		},
+		{
+			name:     "modification times without use_git",
+			from:     now.Add(-time.Hour),
+			to:       now.Add(time.Minute),
+			expected: map[string]time.Time{"obsidian-note.md": {}, "obsidian-draft.md": {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(provider.Config{URL: dir, Enabled: true, UseGit: tt.useGit, IncludeTypes: []string{"note"}})
+			activities, err := p.GetActivities(context.Background(), tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(activities) != len(tt.expected) {
+				t.Fatalf("Expected %d activities, got %d: %+v", len(tt.expected), len(activities), activities)
+			}
+			for _, act := range activities {
+				timestamp, ok := tt.expected[act.ID]
+				if !ok {
+					t.Errorf("Unexpected activity %s", act.ID)
+					continue
+				}
+				if !timestamp.IsZero() && !act.Timestamp.Equal(timestamp) {
+					t.Errorf("Expected %s dated by its commit at %v, got %v", act.ID, timestamp, act.Timestamp)
+				}
+				if tt.description != "" && !strings.HasSuffix(act.Description, tt.description) {
+					t.Errorf("Expected description ending with %q, got %q", tt.description, act.Description)
+				}
+			}
+		})
+	}
+}
+
+func TestProvider_GetActivities_UseGitWithoutRepo(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "note.md"), []byte("# Note\n"), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	// Without a repository, notes are dated by modification time
+	p := NewProvider(provider.Config{URL: dir, Enabled: true, UseGit: true})
+	activities, err := p.GetActivities(context.Background(), time.Now().Add(-time.Hour), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 1 || activities[0].ID != "obsidian-note.md" {
+		t.Errorf("Expected the note modified just now, got %+v", activities)
+	}
+}
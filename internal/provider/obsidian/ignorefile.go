@@ -0,0 +1,90 @@
+package obsidian
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreFileName is the file at the vault root listing paths never scanned, so that the
+// rules travel with the vault
+const ignoreFileName = ".dailyignore"
+
+// ignoreRule is a pattern of the ignore file
+type ignoreRule struct {
+	pattern  string // Slash separated glob, see matchGlob
+	negate   bool   // "!pattern" scans the paths an earlier pattern ignored
+	dirOnly  bool   // "pattern/" only matches folders
+	anchored bool   // A pattern with a slash is matched from the vault root, others at any depth
+}
+
+// ignoreRules are the rules of an ignore file, in order
+type ignoreRules []ignoreRule
+
+// parseIgnoreFile parses an ignore file in the .gitignore style: one glob per line, blank
+// lines and "#" comments are skipped, "!" negates a pattern, a trailing "/" only matches
+// folders and a leading "\" escapes a "#" or "!" starting the pattern
+func parseIgnoreFile(content string) ignoreRules {
+	var rules ignoreRules
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		rule.anchored = strings.Contains(line, "/")
+		rule.pattern = strings.TrimPrefix(line, "/")
+		if rule.pattern == "" {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// loadIgnoreFile reads the ignore file of a vault. A vault without one has no rules.
+func loadIgnoreFile(vaultPath string) (ignoreRules, error) {
+	content, err := os.ReadFile(filepath.Join(vaultPath, ignoreFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ignoreFileName, err)
+	}
+	return parseIgnoreFile(string(content)), nil
+}
+
+// ignored reports whether a path relative to the vault root is ignored: the last rule
+// matching it decides. As the paths of an ignored folder are never walked, a negated
+// pattern can't scan a note again once its folder is ignored.
+func (r ignoreRules) ignored(relPath string, isDir bool) bool {
+	relPath = filepath.ToSlash(relPath)
+	ignored := false
+	for _, rule := range r {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		pattern := rule.pattern
+		if !rule.anchored {
+			pattern = "**/" + pattern
+		}
+		if matchGlob(pattern, relPath) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
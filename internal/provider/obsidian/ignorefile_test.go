@@ -0,0 +1,153 @@
+package obsidian
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+func TestIgnoreRules_Ignored(t *testing.T) {
+	rules := parseIgnoreFile(`# Vault ignore rules
+Templates/
+/Archive
+*.excalidraw.md
+Projects/**/Drafts
+!Projects/Web/Drafts
+
+Journal/*.md
+!Journal/Keep.md
+\#hashtag.md
+`)
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"Templates", true, true},
+		{"Projects/Templates", true, true}, // Patterns without a slash match at any depth
+		{"Templates.md", false, false},     // Folder patterns don't match files
+		{"Archive", true, true},
+		{"Projects/Archive", true, false}, // A leading slash anchors the pattern to the vault root
+		{"Drawing.excalidraw.md", false, true},
+		{"Projects/API/Drawing.excalidraw.md", false, true},
+		{"Projects/Drafts", true, true},
+		{"Projects/API/v2/Drafts", true, true},
+		{"Projects/Web/Drafts", true, false}, // Negated by a later pattern
+		{"Journal/2024-05-12.md", false, true},
+		{"Journal/Keep.md", false, false},
+		{"Journal/2024/Old.md", false, false},
+		{"#hashtag.md", false, true},
+		{"Inbox.md", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := rules.ignored(tt.path, tt.isDir); got != tt.expected {
+			t.Errorf("ignored(%q): expected %v, got %v", tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestParseIgnoreFile(t *testing.T) {
+	rules := parseIgnoreFile("# comment\n\n  Archive/  \n!/Inbox.md\r\n\\!important.md\n/\n")
+	expected := ignoreRules{
+		{pattern: "Archive", dirOnly: true},
+		{pattern: "Inbox.md", negate: true, anchored: true},
+		{pattern: "!important.md"},
+	}
+	if !slices.Equal(rules, expected) {
+		t.Errorf("Expected rules %+v, got %+v", expected, rules)
+	}
+}
+
+func TestProvider_IgnoreFile(t *testing.T) {
+	tests := []struct {
+		name            string
+		ignoreFile      string // No ignore file when empty
+		excludePaths    []string
+		expectedNotes   []string
+		expectedSkipped string
+	}{
+		{
+			name:            "missing ignore file",
+			expectedNotes:   []string{"Archive/2023/Old.md", "Inbox.md", "Projects/API.md", "Projects/Drafts/Idea.md", "Templates/Daily.md", "plugins/kanban/node_modules/pkg/README.md"},
+			expectedSkipped: "2 folders and 0 files",
+		},
+		{
+			name:            "nested patterns and negation",
+			ignoreFile:      "# Not for daily\nnode_modules/\nArchive/**/*.md\nProjects/**/*.md\n!Projects/API.md\n",
+			expectedNotes:   []string{"Inbox.md", "Projects/API.md", "Templates/Daily.md"},
+			expectedSkipped: "3 folders and 2 files (3 by .dailyignore)",
+		},
+		{
+			name:            "with exclude_paths",
+			ignoreFile:      "Templates\nArchive\n",
+			excludePaths:    []string{"Templates", "plugins"},
+			expectedNotes:   []string{"Inbox.md", "Projects/API.md", "Projects/Drafts/Idea.md"},
+			expectedSkipped: "5 folders and 0 files (1 by .dailyignore)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := newExcludeVault(t)
+			if tt.ignoreFile != "" {
+				if err := os.WriteFile(filepath.Join(vault, ignoreFileName), []byte(tt.ignoreFile), 0644); err != nil {
+					t.Fatalf("Failed to write ignore file: %v", err)
+				}
+			}
+			p := NewProvider(provider.Config{URL: vault, Enabled: true, ExcludePaths: tt.excludePaths})
+
+			tasks, err := p.GetTasks(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			var taskNotes []string
+			for _, task := range tasks {
+				taskNotes = append(taskNotes, strings.TrimPrefix(task.Title, "Task in "))
+			}
+			slices.Sort(taskNotes)
+			if !slices.Equal(taskNotes, tt.expectedNotes) {
+				t.Errorf("Expected tasks from %v, got %v", tt.expectedNotes, taskNotes)
+			}
+			if got := p.Skipped(); got != tt.expectedSkipped {
+				t.Errorf("Expected skipped %q, got %q", tt.expectedSkipped, got)
+			}
+
+			activities, err := p.GetActivities(context.Background(), time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			var notes []string
+			for _, act := range activities {
+				if act.Type == activity.ActivityTypeNote {
+					notes = append(notes, filepath.ToSlash(strings.TrimPrefix(act.ID, "obsidian-")))
+				}
+			}
+			slices.Sort(notes)
+			if !slices.Equal(notes, tt.expectedNotes) {
+				t.Errorf("Expected notes %v, got %v", tt.expectedNotes, notes)
+			}
+		})
+	}
+}
+
+func TestProvider_IgnoreFileUnreadable(t *testing.T) {
+	vault := newExcludeVault(t)
+	// A folder can't be read as a file
+	if err := os.Mkdir(filepath.Join(vault, ignoreFileName), 0755); err != nil {
+		t.Fatalf("Failed to create folder: %v", err)
+	}
+
+	_, err := NewProvider(provider.Config{URL: vault, Enabled: true}).GetTasks(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to read .dailyignore") {
+		t.Errorf("Expected ignore file error, got: %v", err)
+	}
+}
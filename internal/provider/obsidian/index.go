@@ -0,0 +1,267 @@
+package obsidian
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// indexVersion is the version of the index file format, an index of another version is
+// rebuilt. Version 2 added the created property of the frontmatter, version 3 the type
+// property and the headings, version 4 reparses the notes with a byte order mark or CR line
+// endings, version 5 added the snippets of the tasks.
+const indexVersion = 5
+
+// parsedNote is what is extracted from a note: its frontmatter, its tasks in every state and
+// its headings
+type parsedNote struct {
+	meta     frontmatter
+	tasks    []taskLine
+	headings []heading
+}
+
+// noteParsed is called with the path of every note actually read, overridden in tests
+var noteParsed = func(filePath string) {}
+
+// parseNote reads the frontmatter, the tasks and the headings of a note, skipping its lines
+// longer than maxLineLength bytes. It returns errBinaryNote for a note that looks binary.
+func parseNote(filePath string, maxLineLength int) (parsedNote, error) {
+	noteParsed(filePath)
+
+	meta, err := readFrontmatter(filePath, maxLineLength)
+	if err != nil {
+		return parsedNote{}, err
+	}
+
+	note := parsedNote{meta: meta}
+	err = scanTasks(filePath, meta.lines, maxLineLength, func(line taskLine) {
+		note.tasks = append(note.tasks, line)
+	}, func(h heading) {
+		note.headings = append(note.headings, h)
+	})
+	if err != nil {
+		return parsedNote{}, err
+	}
+	return note, nil
+}
+
+// readNote returns the parsed note, from the index when the note is unchanged since it
+// was indexed
+func (p *Provider) readNote(filePath string, fileInfo os.FileInfo) (parsedNote, error) {
+	relPath, _ := filepath.Rel(p.vaultPath, filePath)
+	if p.index != nil {
+		if note, ok := p.index.lookup(relPath, fileInfo); ok {
+			return note, nil
+		}
+	}
+
+	note, err := parseNote(filePath, p.maxLineLength())
+	if errors.Is(err, errBinaryNote) {
+		p.skippedMu.Lock()
+		p.skipped.binary++
+		p.skippedMu.Unlock()
+	}
+	if err != nil {
+		return parsedNote{}, err
+	}
+	if p.index != nil {
+		p.index.store(relPath, fileInfo, note)
+	}
+	return note, nil
+}
+
+// noteIndex keeps the parsed notes of a vault by relative path, with the modification time
+// and size they were parsed at. It is safe for concurrent use.
+type noteIndex struct {
+	path          string
+	vault         string
+	maxLineLength int // Setting the notes were parsed with
+
+	mu    sync.Mutex
+	notes map[string]indexedNote
+	dirty bool // Changed since it was loaded or saved
+}
+
+// indexFile is the JSON document of an index. Its header holds the settings changing how
+// notes are parsed, so that notes parsed with other settings aren't reused.
+type indexFile struct {
+	Version       int                    `json:"version"`
+	Vault         string                 `json:"vault"`
+	MaxLineLength int                    `json:"max_line_length"`
+	Notes         map[string]indexedNote `json:"notes"`
+}
+
+// indexedNote is a parsed note as stored in the index
+type indexedNote struct {
+	ModTime          time.Time     `json:"mod_time"`
+	Size             int64         `json:"size"`
+	FrontmatterLines int           `json:"frontmatter_lines,omitempty"`
+	Tags             []string      `json:"tags,omitempty"`
+	Created          time.Time     `json:"created,omitzero"`
+	Type             string        `json:"type,omitempty"`
+	Tasks            []indexedTask `json:"tasks,omitempty"`
+
+	Headings []indexedHeading `json:"headings,omitempty"`
+}
+
+// indexedTask is a task line as stored in the index
+type indexedTask struct {
+	State   string `json:"state"`
+	Text    string `json:"text"`
+	Raw     string `json:"raw"`
+	Line    int    `json:"line"`
+	Context string `json:"context,omitempty"`
+	Parent  string `json:"parent,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// indexedHeading is a heading as stored in the index
+type indexedHeading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+}
+
+// defaultIndexPath returns the index file of a vault in the cache directory, named after a
+// hash of the vault path so that every vault has its own
+func defaultIndexPath(vaultPath string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	if abs, err := filepath.Abs(vaultPath); err == nil {
+		vaultPath = abs
+	}
+	sum := sha256.Sum256([]byte(vaultPath))
+	name := fmt.Sprintf("obsidian_index_%s.json", hex.EncodeToString(sum[:8]))
+	return filepath.Join(homeDir, ".config", "daily", "cache", name), nil
+}
+
+// loadNoteIndex reads the index of a vault. A missing, unreadable or outdated index, or one
+// written for another vault or with another max line length, is rebuilt from scratch.
+func loadNoteIndex(path, vault string, maxLineLength int) *noteIndex {
+	index := &noteIndex{path: path, vault: vault, maxLineLength: maxLineLength, notes: make(map[string]indexedNote)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return index
+	}
+	var file indexFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Version != indexVersion || file.Vault != vault || file.MaxLineLength != maxLineLength {
+		index.dirty = true
+		return index
+	}
+	if file.Notes != nil {
+		index.notes = file.Notes
+	}
+	return index
+}
+
+// lookup returns the indexed note when its modification time and size are unchanged
+func (ix *noteIndex) lookup(relPath string, info os.FileInfo) (parsedNote, bool) {
+	ix.mu.Lock()
+	entry, ok := ix.notes[relPath]
+	ix.mu.Unlock()
+	if !ok || !entry.ModTime.Equal(info.ModTime()) || entry.Size != info.Size() {
+		return parsedNote{}, false
+	}
+
+	note := parsedNote{meta: frontmatter{lines: entry.FrontmatterLines, tags: entry.Tags, created: entry.Created, kind: entry.Type}}
+	for _, task := range entry.Tasks {
+		note.tasks = append(note.tasks, taskLine{
+			state:   task.State,
+			text:    task.Text,
+			raw:     task.Raw,
+			lineNum: task.Line,
+			context: task.Context,
+			parent:  task.Parent,
+			snippet: task.Snippet,
+		})
+	}
+	for _, h := range entry.Headings {
+		note.headings = append(note.headings, heading{level: h.Level, text: h.Text})
+	}
+	return note, true
+}
+
+// store indexes a parsed note
+func (ix *noteIndex) store(relPath string, info os.FileInfo, note parsedNote) {
+	entry := indexedNote{
+		ModTime:          info.ModTime(),
+		Size:             info.Size(),
+		FrontmatterLines: note.meta.lines,
+		Tags:             note.meta.tags,
+		Created:          note.meta.created,
+		Type:             note.meta.kind,
+	}
+	for _, task := range note.tasks {
+		entry.Tasks = append(entry.Tasks, indexedTask{
+			State:   task.state,
+			Text:    task.text,
+			Raw:     task.raw,
+			Line:    task.lineNum,
+			Context: task.context,
+			Parent:  task.parent,
+			Snippet: task.snippet,
+		})
+	}
+	for _, h := range note.headings {
+		entry.Headings = append(entry.Headings, indexedHeading{Level: h.level, Text: h.text})
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.notes[relPath] = entry
+	ix.dirty = true
+}
+
+// retain drops the notes that are no longer in the vault, or are now excluded
+func (ix *noteIndex) retain(relPaths []string) {
+	listed := make(map[string]bool, len(relPaths))
+	for _, relPath := range relPaths {
+		listed[relPath] = true
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for relPath := range ix.notes {
+		if !listed[relPath] {
+			delete(ix.notes, relPath)
+			ix.dirty = true
+		}
+	}
+}
+
+// save writes the index when it changed. The file is replaced at once, so that a run
+// reading it at the same time never sees half of it.
+func (ix *noteIndex) save() error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if !ix.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(indexFile{Version: indexVersion, Vault: ix.vault, MaxLineLength: ix.maxLineLength, Notes: ix.notes})
+	if err != nil {
+		return fmt.Errorf("failed to marshal note index: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmpPath := ix.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write note index: %w", err)
+	}
+	if err := os.Rename(tmpPath, ix.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write note index: %w", err)
+	}
+	ix.dirty = false
+	return nil
+}
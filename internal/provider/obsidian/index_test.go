@@ -0,0 +1,206 @@
+package obsidian
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+// TestMain keeps the indexes of the test vaults out of the cache directory of the user
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "daily-obsidian-test")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("HOME", home)
+	code := m.Run()
+	_ = os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// countParses records the notes parsed until the test ends
+func countParses(t *testing.T) func() []string {
+	var mu sync.Mutex
+	var parsed []string
+	noteParsed = func(filePath string) {
+		mu.Lock()
+		defer mu.Unlock()
+		parsed = append(parsed, filepath.Base(filePath))
+	}
+	t.Cleanup(func() { noteParsed = func(string) {} })
+
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		names := slices.Clone(parsed)
+		slices.Sort(names)
+		parsed = nil
+		return names
+	}
+}
+
+func TestProvider_GetTasks_Index(t *testing.T) {
+	vault := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+	modTime := time.Date(2024, 5, 1, 9, 0, 0, 0, time.Local)
+	writeNote := func(name, content string, modTime time.Time) {
+		path := filepath.Join(vault, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write note: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set mod time: %v", err)
+		}
+	}
+	writeNote("a.md", "---\ntags: [home]\ncreated: 2024-04-30\n---\n## Chores\n- [ ] Water plants\n", modTime)
+	writeNote("b.md", "- [ ] Call the bank\n- [x] Pay rent\n", modTime)
+	writeNote("c.md", "- [/] Write the report\n", modTime)
+
+	parsed := countParses(t)
+	getTasks := func() []TodoItem {
+		// A new provider per run, as each run of daily reads the index from disk
+		p := NewProvider(provider.Config{URL: vault, Enabled: true})
+		p.indexPath = indexPath
+		tasks, err := p.GetTasks(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return tasks
+	}
+
+	first := getTasks()
+	if names := parsed(); !slices.Equal(names, []string{"a.md", "b.md", "c.md"}) {
+		t.Errorf("Expected every note to be parsed on the first run, got %v", names)
+	}
+
+	second := getTasks()
+	if names := parsed(); len(names) != 0 {
+		t.Errorf("Expected unchanged notes not to be parsed, got %v", names)
+	}
+	if len(second) != 3 || second[0].Title != "Water plants" || second[0].Context != "Chores" || !slices.Contains(second[0].Tags, "home") {
+		t.Errorf("Expected the indexed tasks with their context and tags, got %+v", second)
+	}
+	for i := range first {
+		if first[i].ID != second[i].ID || first[i].Title != second[i].Title || !slices.Equal(first[i].Tags, second[i].Tags) {
+			t.Errorf("Expected the same tasks from the index, got %+v and %+v", first[i], second[i])
+		}
+	}
+
+	// Change one note, add one and delete one
+	writeNote("b.md", "- [ ] Call the bank\n- [ ] Cancel the gym\n", modTime.Add(time.Hour))
+	writeNote("d.md", "- [ ] Book flights\n", modTime)
+	if err := os.Remove(filepath.Join(vault, "c.md")); err != nil {
+		t.Fatalf("Failed to delete note: %v", err)
+	}
+
+	third := getTasks()
+	if names := parsed(); !slices.Equal(names, []string{"b.md", "d.md"}) {
+		t.Errorf("Expected only the changed and new notes to be parsed, got %v", names)
+	}
+	var titles []string
+	for _, task := range third {
+		titles = append(titles, task.Title)
+	}
+	if !slices.Equal(titles, []string{"Water plants", "Call the bank", "Cancel the gym", "Book flights"}) {
+		t.Errorf("Expected the tasks of the current notes, got %v", titles)
+	}
+
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	var file indexFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("Failed to parse index: %v", err)
+	}
+	if _, ok := file.Notes["c.md"]; ok || len(file.Notes) != 3 {
+		t.Errorf("Expected the deleted note to be dropped from the index, got %v", file.Notes)
+	}
+
+	info, err := os.Stat(filepath.Join(vault, "a.md"))
+	if err != nil {
+		t.Fatalf("Failed to stat note: %v", err)
+	}
+	note, ok := loadNoteIndex(indexPath, vault, defaultMaxLineLength).lookup("a.md", info)
+	if !ok || !note.meta.created.Equal(time.Date(2024, 4, 30, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected the created property in the index, got %+v", note.meta)
+	}
+}
+
+func TestProvider_GetTasks_CorruptIndex(t *testing.T) {
+	vault := t.TempDir()
+	if err := os.WriteFile(filepath.Join(vault, "a.md"), []byte("- [ ] Water plants\n"), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid JSON", content: `{"version": 1, "notes": {"a.md": `},
+		{name: "other version", content: `{"version": 99, "vault": "` + vault + `", "notes": {"a.md": {"size": 1}}}`},
+		{name: "other vault", content: `{"version": 1, "vault": "/elsewhere", "notes": {}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexPath := filepath.Join(t.TempDir(), "index.json")
+			if err := os.WriteFile(indexPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write index: %v", err)
+			}
+			parsed := countParses(t)
+
+			p := NewProvider(provider.Config{URL: vault, Enabled: true})
+			p.indexPath = indexPath
+			tasks, err := p.GetTasks(context.Background())
+			if err != nil {
+				t.Fatalf("Expected the index to be rebuilt, got: %v", err)
+			}
+			if len(tasks) != 1 || len(parsed()) != 1 {
+				t.Errorf("Expected the note to be parsed again, got %+v", tasks)
+			}
+
+			index := loadNoteIndex(indexPath, vault, defaultMaxLineLength)
+			if _, ok := index.notes["a.md"]; !ok || index.dirty {
+				t.Errorf("Expected a valid index to be saved, got %+v", index.notes)
+			}
+		})
+	}
+}
+
+func TestProvider_GetTasks_IndexMaxLineLength(t *testing.T) {
+	vault := t.TempDir()
+	if err := os.WriteFile(filepath.Join(vault, "a.md"), []byte("- [ ] Water plants\n"), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+	parsed := countParses(t)
+
+	getTasks := func(maxLineLength int) {
+		t.Helper()
+		p := NewProvider(provider.Config{URL: vault, Enabled: true, MaxLineLength: maxLineLength})
+		p.indexPath = indexPath
+		if _, err := p.GetTasks(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	getTasks(0)
+	getTasks(0)
+	if names := parsed(); len(names) != 1 {
+		t.Fatalf("Expected the note parsed once, got %v", names)
+	}
+
+	// Notes parsed with another max line length are parsed again
+	getTasks(10)
+	if names := parsed(); len(names) != 1 {
+		t.Errorf("Expected the note parsed again, got %v", names)
+	}
+}
@@ -0,0 +1,107 @@
+package obsidian
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// utf8BOM is the byte order mark some Windows editors write at the start of UTF-8 files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// defaultMaxLineLength is the longest line of a note read without max_line_length, enough
+// for embedded base64 images
+const defaultMaxLineLength = 1 << 20
+
+// binarySniffLength is how many bytes at the start of a note are looked at for a NUL byte
+// telling binary content, like git does
+const binarySniffLength = 8000
+
+// errBinaryNote is returned for notes that look binary, e.g. an attachment renamed to .md
+var errBinaryNote = errors.New("binary content")
+
+// newLineScanner returns a scanner of the lines of a note whatever its line endings (LF,
+// CRLF or a lone CR, even mixed in one file, as in notes synced from Windows or old Macs),
+// skipping the byte order mark of the file. Lines longer than maxLineLength bytes are read as
+// empty lines, so the lines after them keep their numbers. It returns errBinaryNote when a
+// NUL byte starts the file.
+func newLineScanner(r io.Reader, maxLineLength int) (*bufio.Scanner, error) {
+	reader := bufio.NewReaderSize(r, binarySniffLength)
+	start, _ := reader.Peek(binarySniffLength) // Shorter at the end of the file
+	if bytes.IndexByte(start, 0) >= 0 {
+		return nil, errBinaryNote
+	}
+	if bytes.HasPrefix(start, utf8BOM) {
+		_, _ = reader.Discard(len(utf8BOM))
+	}
+
+	splitter := &lineSplitter{max: maxLineLength}
+	scanner := bufio.NewScanner(reader)
+	// Room for the line ending after the longest line, to tell a CRLF from a lone CR
+	scanner.Buffer(nil, maxLineLength+2)
+	scanner.Split(splitter.split)
+	return scanner, nil
+}
+
+// lineSplitter splits lines like scanLines, replacing the lines longer than max bytes with
+// empty lines
+type lineSplitter struct {
+	max      int
+	skipping bool // In a line longer than max, discarded until its end
+}
+
+// split is the bufio.SplitFunc of a lineSplitter
+func (s *lineSplitter) split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	advance, token, err = scanLines(data, atEOF)
+	if advance == 0 {
+		if atEOF && s.skipping {
+			// The file ends with the long line
+			s.skipping = false
+			return 0, []byte{}, nil
+		}
+		// No line end within max bytes: the line is discarded as it is read, except a
+		// trailing CR which may start its CRLF
+		if len(data) > s.max && bytes.IndexAny(data[:s.max+1], "\r\n") < 0 {
+			s.skipping = true
+			return len(bytes.TrimSuffix(data, []byte("\r"))), nil, nil
+		}
+		return 0, nil, err
+	}
+
+	if s.skipping || len(token) > s.max {
+		s.skipping = false
+		return advance, data[:0], nil
+	}
+	return advance, token, nil
+}
+
+// scanLines is a bufio.SplitFunc like bufio.ScanLines, which also ends lines at a lone CR
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		// A CR ends the line, with the LF following it when it is a CRLF
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		if !atEOF {
+			// Read more to tell a CRLF from a lone CR
+			return 0, nil, nil
+		}
+		return i + 1, data[:i], nil
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
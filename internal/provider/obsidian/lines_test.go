@@ -0,0 +1,203 @@
+package obsidian
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"daily/internal/provider"
+)
+
+func TestNewLineScanner(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{"LF", "one\ntwo\n", []string{"one", "two"}},
+		{"CRLF", "one\r\ntwo\r\n", []string{"one", "two"}},
+		{"CR only", "one\rtwo\r", []string{"one", "two"}},
+		{"mixed", "one\r\ntwo\rthree\nfour", []string{"one", "two", "three", "four"}},
+		{"empty lines", "one\r\n\r\n\r\rtwo", []string{"one", "", "", "", "two"}},
+		{"BOM", "\uFEFFone\ntwo", []string{"one", "two"}},
+		{"BOM only", "\uFEFF", nil},
+		{"BOM in the middle is kept", "one\n\uFEFFtwo", []string{"one", "\uFEFFtwo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, err := newLineScanner(strings.NewReader(tt.content), defaultMaxLineLength)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			var lines []string
+			for scanner.Scan() {
+				lines = append(lines, scanner.Text())
+			}
+			if err := scanner.Err(); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !reflect.DeepEqual(lines, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, lines)
+			}
+		})
+	}
+}
+
+func TestNewLineScanner_LongLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{"lines within the limit", "12345678\nshort\n", []string{"12345678", "short"}},
+		{"long line skipped", "before\n123456789\nafter\n", []string{"before", "", "after"}},
+		{"very long line skipped", "before\n" + strings.Repeat("x", 100) + "\nafter", []string{"before", "", "after"}},
+		{"long line with CRLF", "before\r\n" + strings.Repeat("x", 20) + "\r\nafter\r\n", []string{"before", "", "after"}},
+		{"long line with CR", "before\r" + strings.Repeat("x", 20) + "\rafter", []string{"before", "", "after"}},
+		{"CRLF after the longest line", "12345678\r\nafter", []string{"12345678", "after"}},
+		{"CRLF after a line one byte too long", "123456789\r\nafter", []string{"", "after"}},
+		{"long last line", "before\n" + strings.Repeat("x", 20), []string{"before", ""}},
+	}
+
+	readers := map[string]func(string) io.Reader{
+		"whole":    func(s string) io.Reader { return strings.NewReader(s) },
+		"one byte": func(s string) io.Reader { return iotest.OneByteReader(strings.NewReader(s)) },
+	}
+
+	for _, tt := range tests {
+		for readerName, reader := range readers {
+			t.Run(tt.name+" "+readerName, func(t *testing.T) {
+				scanner, err := newLineScanner(reader(tt.content), 8)
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				var lines []string
+				for scanner.Scan() {
+					lines = append(lines, scanner.Text())
+				}
+				if err := scanner.Err(); err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				if !reflect.DeepEqual(lines, tt.expected) {
+					t.Errorf("Expected %q, got %q", tt.expected, lines)
+				}
+			})
+		}
+	}
+}
+
+func TestNewLineScanner_Binary(t *testing.T) {
+	if _, err := newLineScanner(strings.NewReader("PNG\x00\x01\x02- [ ] not a task"), defaultMaxLineLength); !errors.Is(err, errBinaryNote) {
+		t.Errorf("Expected errBinaryNote, got: %v", err)
+	}
+
+	// Only the start of the file is looked at
+	late := strings.Repeat("text\n", binarySniffLength) + "\x00"
+	if _, err := newLineScanner(strings.NewReader(late), defaultMaxLineLength); err != nil {
+		t.Errorf("Expected a NUL byte after the start to be read as text, got: %v", err)
+	}
+}
+
+func TestProvider_GetTasks_LargeAndBinaryNotes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // The note index is written under the home directory
+	vault := t.TempDir()
+	blob := base64.StdEncoding.EncodeToString(make([]byte, 150*1024)) // 200KB on one line
+	notes := map[string]string{
+		"attachment.md": "- [ ] In a binary file\n\x00\x89PNG",
+		"large.md":      "- [ ] Before the image\n![image](data:image/png;base64," + blob + ")\n- [ ] After the image\n",
+	}
+	for name, content := range notes {
+		if err := os.WriteFile(filepath.Join(vault, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	for _, maxLineLength := range []int{0, 1024} {
+		t.Run(fmt.Sprintf("max line length %d", maxLineLength), func(t *testing.T) {
+			p := NewProvider(provider.Config{URL: vault, Enabled: true, MaxLineLength: maxLineLength})
+			p.indexPath = ""
+			tasks, err := p.GetTasks(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			var found []string
+			for _, task := range tasks {
+				found = append(found, fmt.Sprintf("%s:%d", task.Title, task.Line))
+			}
+			expected := []string{"Before the image:1", "After the image:3"}
+			if !reflect.DeepEqual(found, expected) {
+				t.Errorf("Expected tasks %v, got %v", expected, found)
+			}
+			if skipped := p.Skipped(); !strings.Contains(skipped, "1 binary file") {
+				t.Errorf("Expected the binary note to be reported, got %q", skipped)
+			}
+		})
+	}
+}
+
+func TestParseNote_LineEndings(t *testing.T) {
+	lf := "---\ntags: [work]\ntype: meeting\n---\n# Sync with Sam\n- [ ] Send the notes #followup\n  - [/] Draft the summary\n## Later\n- [x] Book the room\n"
+
+	variants := map[string]string{
+		"CRLF":         strings.ReplaceAll(lf, "\n", "\r\n"),
+		"CR only":      strings.ReplaceAll(lf, "\n", "\r"),
+		"BOM":          "\uFEFF" + lf,
+		"BOM and CRLF": "\uFEFF" + strings.ReplaceAll(lf, "\n", "\r\n"),
+		"mixed":        mixEndings(lf),
+	}
+
+	dir := t.TempDir()
+	parse := func(name, content string) parsedNote {
+		path := filepath.Join(dir, name+".md")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write note: %v", err)
+		}
+		note, err := parseNote(path, defaultMaxLineLength)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return note
+	}
+
+	expected := parse("lf", lf)
+	if len(expected.tasks) != 3 || expected.meta.kind != "meeting" || len(expected.headings) != 2 {
+		t.Fatalf("Unexpected parse of the LF note: %+v", expected)
+	}
+
+	for name, content := range variants {
+		t.Run(name, func(t *testing.T) {
+			note := parse(name, content)
+			if !reflect.DeepEqual(note, expected) {
+				t.Errorf("Expected the parse of the LF note %+v, got %+v", expected, note)
+			}
+			for _, task := range note.tasks {
+				if strings.ContainsAny(task.text+task.raw, "\r\uFEFF") {
+					t.Errorf("Expected no CR nor BOM in task %q", task.raw)
+				}
+			}
+		})
+	}
+}
+
+// mixEndings ends the lines of content with LF, CRLF and CR in turn
+func mixEndings(content string) string {
+	endings := []string{"\n", "\r\n", "\r"}
+	var mixed strings.Builder
+	for i, line := range strings.SplitAfter(content, "\n") {
+		if text, ok := strings.CutSuffix(line, "\n"); ok {
+			line = text + endings[i%len(endings)]
+		}
+		mixed.WriteString(line)
+	}
+	return mixed.String()
+}
@@ -0,0 +1,61 @@
+package obsidian
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// wikiLinkPattern matches wiki-links and embeds, e.g. [[Note]], [[Note|display]],
+// [[Note#^block]] or ![[Note]]
+var wikiLinkPattern = regexp.MustCompile(`!?\[\[([^\[\]]+)\]\]`)
+
+// codeSpanPattern matches inline code, where links are shown as written
+var codeSpanPattern = regexp.MustCompile("`[^`]*`")
+
+// extractWikiLinks replaces the wiki-links of task text with the text Obsidian shows for
+// them, and returns the notes they link to in order, once each. Links in code spans are left
+// as they are, and links to a heading or block of the same note don't link to another note.
+func extractWikiLinks(text string) (string, []string) {
+	var notes []string
+	var result strings.Builder
+	last := 0
+	for _, span := range codeSpanPattern.FindAllStringIndex(text, -1) {
+		result.WriteString(replaceWikiLinks(text[last:span[0]], &notes))
+		result.WriteString(text[span[0]:span[1]])
+		last = span[1]
+	}
+	result.WriteString(replaceWikiLinks(text[last:], &notes))
+	return result.String(), notes
+}
+
+// replaceWikiLinks replaces the wiki-links of text outside code spans with their display
+// text, appending the notes they link to to notes
+func replaceWikiLinks(text string, notes *[]string) string {
+	return wikiLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		note, display := parseWikiLink(wikiLinkPattern.FindStringSubmatch(match)[1])
+		if note != "" && !slices.Contains(*notes, note) {
+			*notes = append(*notes, note)
+		}
+		return display
+	})
+}
+
+// parseWikiLink returns the note a link target like "Note#^block|display" refers to, without
+// its heading or block reference, and the text shown for it: the alias, else the note, else
+// the heading or block of the same note
+func parseWikiLink(target string) (note, display string) {
+	target, alias, hasAlias := strings.Cut(target, "|")
+	note, fragment, _ := strings.Cut(target, "#")
+	note = strings.TrimSuffix(strings.TrimSpace(note), ".md")
+
+	switch {
+	case hasAlias && strings.TrimSpace(alias) != "":
+		display = strings.TrimSpace(alias)
+	case note != "":
+		display = note
+	default:
+		display = strings.TrimPrefix(strings.TrimSpace(fragment), "^")
+	}
+	return note, display
+}
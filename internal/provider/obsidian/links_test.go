@@ -0,0 +1,90 @@
+package obsidian
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+func TestExtractWikiLinks(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		expectedTitle string
+		expectedNotes []string
+	}{
+		{"no links", "Call the bank", "Call the bank", nil},
+		{"link", "Review [[Project Phoenix]] spec", "Review Project Phoenix spec", []string{"Project Phoenix"}},
+		{"alias", "Read [[2024-05-12 Meeting|the meeting notes]]", "Read the meeting notes", []string{"2024-05-12 Meeting"}},
+		{"embed", "Check ![[Architecture.png]] and ![[Budget]]", "Check Architecture.png and Budget", []string{"Architecture.png", "Budget"}},
+		{"heading", "Update [[Roadmap#Q3 goals]]", "Update Roadmap", []string{"Roadmap"}},
+		{"block reference", "Answer [[Inbox#^a1b2c3]] first", "Answer Inbox first", []string{"Inbox"}},
+		{"block reference with alias", "Answer [[Inbox#^a1b2c3|Anna's question]]", "Answer Anna's question", []string{"Inbox"}},
+		{"same note block", "See [[#^summary]]", "See summary", nil},
+		{"file extension", "Fill [[folder/Template.md]]", "Fill folder/Template", []string{"folder/Template"}},
+		{"duplicate links", "Merge [[A]] into [[B]], then [[A|archive it]]", "Merge A into B, then archive it", []string{"A", "B"}},
+		{"code span", "Document the `[[link]]` syntax for [[Wiki]]", "Document the `[[link]]` syntax for Wiki", []string{"Wiki"}},
+		{"embed in code span", "Explain `![[image.png]]` embeds", "Explain `![[image.png]]` embeds", nil},
+		{"unbalanced backtick", "Fix the ` in [[Parser]]", "Fix the ` in Parser", []string{"Parser"}},
+		{"unclosed link", "Start [[Draft", "Start [[Draft", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, notes := extractWikiLinks(tt.text)
+			if title != tt.expectedTitle {
+				t.Errorf("Expected title '%s', got '%s'", tt.expectedTitle, title)
+			}
+			if !slices.Equal(notes, tt.expectedNotes) {
+				t.Errorf("Expected notes %q, got %q", tt.expectedNotes, notes)
+			}
+		})
+	}
+}
+
+func TestProvider_GetTasks_WikiLinks(t *testing.T) {
+	vault := t.TempDir()
+	content := "- [ ] Review [[Project Phoenix]] spec #work\n- [ ] Summarize ![[Meeting|the meeting]] in `[[Notes]]`\n"
+	if err := os.WriteFile(filepath.Join(vault, "tasks.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	tasks, err := NewProvider(provider.Config{URL: vault, Enabled: true}).GetTasks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %d", len(tasks))
+	}
+
+	expected := []struct {
+		title string
+		notes []string
+		tag   string
+	}{
+		{"Review Project Phoenix spec #work", []string{"Project Phoenix"}, "ref:Project Phoenix"},
+		{"Summarize the meeting in `[[Notes]]`", []string{"Meeting"}, "ref:Meeting"},
+	}
+	for i, want := range expected {
+		task := tasks[i]
+		if task.Title != want.title {
+			t.Errorf("Expected title '%s', got '%s'", want.title, task.Title)
+		}
+		if !slices.Equal(task.RelatedNotes, want.notes) {
+			t.Errorf("Expected related notes %q, got %q", want.notes, task.RelatedNotes)
+		}
+		if !slices.Contains(task.Tags, want.tag) {
+			t.Errorf("Expected tag '%s', got %v", want.tag, task.Tags)
+		}
+		if slices.Contains(task.Tags, "ref:Notes") {
+			t.Errorf("Expected no tag for the link in a code span, got %v", task.Tags)
+		}
+	}
+	if task := tasks[0]; task.RawLine != "- [ ] Review [[Project Phoenix]] spec #work" {
+		t.Errorf("Expected the raw line to keep the link, got '%s'", task.RawLine)
+	}
+}
@@ -0,0 +1,152 @@
+package obsidian
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"daily/internal/provider"
+)
+
+// attendeeTagPrefix prefixes the attendee tags of meetings, e.g. "attendee:Sam"
+const attendeeTagPrefix = "attendee:"
+
+// attendeesPattern matches the attendees of a meeting title, e.g. "1:1 with Sam" or
+// "Sync with Alice, Bob and Carol"
+var attendeesPattern = regexp.MustCompile(`(?i)\bwith\s+(.+)$`)
+
+// attendeesEndPattern matches what follows the attendees of a title, e.g. a date in
+// "Sync with Alice - 2024-05-12" or a remark in "1:1 with Sam (rescheduled)"
+var attendeesEndPattern = regexp.MustCompile(`\s+[-–—|:]\s|\s*[(\[]|\s+\d{4}-\d{2}-\d{2}`)
+
+// attendeesSeparatorPattern separates the attendees of a title
+var attendeesSeparatorPattern = regexp.MustCompile(`(?i)\s*(?:,|&|\+|/|;|\band\b)\s*`)
+
+// meetingRules are the compiled meeting rules of the provider
+type meetingRules struct {
+	folders []string
+	titles  []*regexp.Regexp
+	types   []string
+}
+
+// meeting is a meeting detected in a note
+type meeting struct {
+	title     string
+	heading   bool // Detected from a heading, when the note isn't a meeting as a whole
+	attendees []string
+}
+
+// compileMeetingRules compiles configured meeting rules, DefaultMeetingRules when nil
+func compileMeetingRules(rules *provider.MeetingRules) (meetingRules, error) {
+	if rules == nil {
+		rules = &provider.DefaultMeetingRules
+	}
+
+	compiled := meetingRules{types: rules.Types}
+	for _, folder := range rules.Folders {
+		folder = strings.Trim(filepath.ToSlash(folder), "/")
+		if !strings.Contains(folder, "/") {
+			folder = "**/" + folder
+		}
+		compiled.folders = append(compiled.folders, folder+"/**")
+	}
+	for _, title := range rules.Titles {
+		pattern, err := regexp.Compile(title)
+		if err != nil {
+			return meetingRules{}, fmt.Errorf("invalid title pattern %q: %w", title, err)
+		}
+		compiled.titles = append(compiled.titles, pattern)
+	}
+	return compiled, nil
+}
+
+// meetings returns the meetings of a note. A note in a meeting folder, of a meeting type or
+// named like a meeting is one meeting, titled by its first heading named like a meeting, else
+// its first top-level heading, else its name. In other notes, each heading named like a
+// meeting is a meeting.
+func (r meetingRules) meetings(relPath, name string, note parsedNote) []meeting {
+	var matching []string
+	for _, h := range note.headings {
+		if text, _ := extractWikiLinks(h.text); r.matchesTitle(text) {
+			matching = append(matching, h.text)
+		}
+	}
+
+	isType := note.meta.kind != "" && slices.Contains(r.types, note.meta.kind)
+	if isType || r.inFolder(relPath) || r.matchesTitle(name) {
+		title := name
+		if len(matching) > 0 {
+			title = matching[0]
+		} else if i := slices.IndexFunc(note.headings, func(h heading) bool { return h.level == 1 }); i >= 0 {
+			title = note.headings[i].text
+		}
+		return []meeting{newMeeting(title, false)}
+	}
+
+	var meetings []meeting
+	for _, h := range matching {
+		meetings = append(meetings, newMeeting(h, true))
+	}
+	return meetings
+}
+
+// inFolder reports whether a note is in one of the meeting folders
+func (r meetingRules) inFolder(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	for _, folder := range r.folders {
+		if matchGlob(folder, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTitle reports whether a note name or heading matches one of the title patterns
+func (r meetingRules) matchesTitle(title string) bool {
+	for _, pattern := range r.titles {
+		if pattern.MatchString(title) {
+			return true
+		}
+	}
+	return false
+}
+
+// newMeeting returns the meeting of a heading or note name, whose wiki-links are replaced
+// with their display text
+func newMeeting(title string, heading bool) meeting {
+	title, _ = extractWikiLinks(title)
+	return meeting{title: title, heading: heading, attendees: extractAttendees(title)}
+}
+
+// extractAttendees returns the people a meeting title is with, e.g. Alice and Bob for
+// "Sync with Alice & Bob - 2024-05-12"
+func extractAttendees(title string) []string {
+	matches := attendeesPattern.FindStringSubmatch(title)
+	if matches == nil {
+		return nil
+	}
+	names := matches[1]
+	if end := attendeesEndPattern.FindStringIndex(names); end != nil {
+		names = names[:end[0]]
+	}
+
+	var attendees []string
+	for _, name := range attendeesSeparatorPattern.Split(names, -1) {
+		name = strings.TrimPrefix(strings.Trim(strings.TrimSpace(name), `"'*_.`), "@")
+		if name != "" && !slices.Contains(attendees, name) {
+			attendees = append(attendees, name)
+		}
+	}
+	return attendees
+}
+
+// attendeeTags returns the tags of the attendees of a meeting
+func attendeeTags(attendees []string) []string {
+	tags := make([]string, len(attendees))
+	for i, attendee := range attendees {
+		tags[i] = attendeeTagPrefix + attendee
+	}
+	return tags
+}
@@ -0,0 +1,248 @@
+package obsidian
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+func TestExtractAttendees(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected []string
+	}{
+		{"1:1 with Sam", []string{"Sam"}},
+		{"Platform sync with Alice, Bob and Carol", []string{"Alice", "Bob", "Carol"}},
+		{"Team meeting with Alice & Bob (rescheduled)", []string{"Alice", "Bob"}},
+		{"Sync with Alice - 2024-05-13", []string{"Alice"}},
+		{"Design review with @dana + @lee 2024-05-13", []string{"dana", "lee"}},
+		{"Retro WITH Alice, Alice and Bob", []string{"Alice", "Bob"}},
+		{"Weekly planning", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			attendees := extractAttendees(tt.title)
+			if !reflect.DeepEqual(attendees, tt.expected) {
+				t.Errorf("Expected attendees %v, got %v", tt.expected, attendees)
+			}
+		})
+	}
+}
+
+func TestMeetingRules_Meetings(t *testing.T) {
+	defaults, err := compileMeetingRules(nil)
+	if err != nil {
+		t.Fatalf("Failed to compile default rules: %v", err)
+	}
+	custom, err := compileMeetingRules(&provider.MeetingRules{
+		Folders: []string{"Work/Calls"},
+		Titles:  []string{`^Standup\b`},
+	})
+	if err != nil {
+		t.Fatalf("Failed to compile custom rules: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		rules    meetingRules
+		relPath  string
+		note     parsedNote
+		expected []meeting
+	}{
+		{
+			name:     "note in a meetings folder titled by its top-level heading",
+			rules:    defaults,
+			relPath:  "Work/Meetings/2024-05-13.md",
+			note:     parsedNote{headings: []heading{{level: 1, text: "Roadmap review with Dana"}, {level: 2, text: "Notes"}}},
+			expected: []meeting{{title: "Roadmap review with Dana", attendees: []string{"Dana"}}},
+		},
+		{
+			name:     "note of the meeting type titled by its name",
+			rules:    defaults,
+			relPath:  "Hiring/Candidate debrief.md",
+			note:     parsedNote{meta: frontmatter{kind: "meeting"}, headings: []heading{{level: 2, text: "Feedback"}}},
+			expected: []meeting{{title: "Candidate debrief"}},
+		},
+		{
+			name:     "note named like a meeting",
+			rules:    defaults,
+			relPath:  "1-on-1 with Lee.md",
+			expected: []meeting{{title: "1-on-1 with Lee", attendees: []string{"Lee"}}},
+		},
+		{
+			name:    "each meeting heading of another note",
+			rules:   defaults,
+			relPath: "Daily/2024-05-13.md",
+			note: parsedNote{headings: []heading{
+				{level: 2, text: "1:1 with [[Sam]]"},
+				{level: 2, text: "Reading"},
+				{level: 2, text: "Team Meeting"},
+			}},
+			expected: []meeting{
+				{title: "1:1 with Sam", heading: true, attendees: []string{"Sam"}},
+				{title: "Team Meeting", heading: true},
+			},
+		},
+		{
+			name:    "note without meeting",
+			rules:   defaults,
+			relPath: "Projects/API Migration.md",
+			note:    parsedNote{meta: frontmatter{kind: "project"}, headings: []heading{{level: 1, text: "API Migration"}}},
+		},
+		{
+			name:     "folder of custom rules",
+			rules:    custom,
+			relPath:  "Work/Calls/Vendor.md",
+			expected: []meeting{{title: "Vendor"}},
+		},
+		{
+			name:    "folder of custom rules only matched from the vault root",
+			rules:   custom,
+			relPath: "Archive/Work/Calls/Vendor.md",
+		},
+		{
+			name:     "title of custom rules",
+			rules:    custom,
+			relPath:  "Daily/2024-05-14.md",
+			note:     parsedNote{headings: []heading{{level: 2, text: "Standup"}, {level: 2, text: "1:1 with Sam"}}},
+			expected: []meeting{{title: "Standup", heading: true}},
+		},
+		{
+			name:    "custom rules replace the default ones",
+			rules:   custom,
+			relPath: "Meetings/2024-05-13.md",
+			note:    parsedNote{meta: frontmatter{kind: "meeting"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := strings.TrimSuffix(filepath.Base(tt.relPath), ".md")
+			meetings := tt.rules.meetings(tt.relPath, name, tt.note)
+			if !reflect.DeepEqual(meetings, tt.expected) {
+				t.Errorf("Expected meetings %+v, got %+v", tt.expected, meetings)
+			}
+		})
+	}
+}
+
+func TestProvider_GetActivities_Meetings(t *testing.T) {
+	vaultPath := "./testdata/meetings-vault"
+	p := NewProvider(provider.Config{URL: vaultPath, Enabled: true, IncludeTypes: []string{"note", "meeting"}})
+
+	activities, err := p.GetActivities(context.Background(), time.Time{}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	byID := make(map[string]activity.Activity)
+	for _, act := range activities {
+		byID[act.ID] = act
+	}
+	if len(byID) != 5 {
+		t.Fatalf("Expected 5 activities, got %d: %v", len(activities), activities)
+	}
+
+	expected := []struct {
+		id        string
+		actType   activity.ActivityType
+		title     string
+		attendees []string
+	}{
+		{"obsidian-Meetings/2024-05-13 Platform sync.md", activity.ActivityTypeMeeting, "Platform sync with Alice, Bob and Carol - 2024-05-13", []string{"Alice", "Bob", "Carol"}},
+		{"obsidian-Daily/2024-05-13.md#1:1 with Sam", activity.ActivityTypeMeeting, "1:1 with Sam", []string{"Sam"}},
+		{"obsidian-Daily/2024-05-13.md#Team meeting with Alice & Bob (rescheduled)", activity.ActivityTypeMeeting, "Team meeting with Alice & Bob (rescheduled)", []string{"Alice", "Bob"}},
+		{"obsidian-Hiring/Candidate debrief.md", activity.ActivityTypeMeeting, "Candidate debrief", nil},
+		{"obsidian-Projects/API Migration.md", activity.ActivityTypeNote, "API Migration", nil},
+	}
+	for _, want := range expected {
+		act, ok := byID[want.id]
+		if !ok {
+			t.Errorf("Expected activity %s", want.id)
+			continue
+		}
+		if act.Type != want.actType || act.Title != want.title {
+			t.Errorf("Expected %s %q, got %s %q", want.actType, want.title, act.Type, act.Title)
+		}
+		for _, attendee := range want.attendees {
+			if !slices.Contains(act.Tags, attendeeTagPrefix+attendee) {
+				t.Errorf("Expected attendee tag for %s in %v", attendee, act.Tags)
+			}
+		}
+	}
+
+	platformSync := byID["obsidian-Meetings/2024-05-13 Platform sync.md"]
+	if !strings.HasSuffix(platformSync.Description, " meeting notes: Meetings/2024-05-13 Platform sync.md") {
+		t.Errorf("Unexpected description: %s", platformSync.Description)
+	}
+	if !slices.Contains(platformSync.Tags, "team/platform") {
+		t.Errorf("Expected frontmatter tags on the meeting, got %v", platformSync.Tags)
+	}
+	if debrief := byID["obsidian-Hiring/Candidate debrief.md"]; !slices.Contains(debrief.Tags, "created") {
+		t.Errorf("Expected the debrief created in the range, got tags %v", debrief.Tags)
+	}
+}
+
+func TestProvider_GetActivities_MeetingsIncludeTypes(t *testing.T) {
+	tempDir := t.TempDir()
+	notes := map[string]string{
+		"Meetings/Roadmap review.md": "# Roadmap review with Dana\n",
+		"Projects/API.md":            "# API\n",
+	}
+	for name, content := range notes {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create folder: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		includeTypes []string
+		expected     []activity.ActivityType
+	}{
+		{[]string{"meeting"}, []activity.ActivityType{activity.ActivityTypeMeeting}},
+		{[]string{"note"}, []activity.ActivityType{activity.ActivityTypeNote}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.includeTypes[0], func(t *testing.T) {
+			p := NewProvider(provider.Config{URL: tempDir, Enabled: true, IncludeTypes: tt.includeTypes})
+			activities, err := p.GetActivities(context.Background(), time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			var types []activity.ActivityType
+			for _, act := range activities {
+				types = append(types, act.Type)
+			}
+			if !reflect.DeepEqual(types, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, types)
+			}
+		})
+	}
+}
+
+func TestProvider_GetActivities_InvalidMeetingRules(t *testing.T) {
+	p := NewProvider(provider.Config{
+		URL:          t.TempDir(),
+		Enabled:      true,
+		MeetingRules: &provider.MeetingRules{Titles: []string{"(unclosed"}},
+	})
+
+	if _, err := p.GetActivities(context.Background(), time.Now().Add(-time.Hour), time.Now()); err == nil {
+		t.Error("Expected an error for an invalid title pattern, got nil")
+	}
+}
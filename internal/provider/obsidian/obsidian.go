@@ -1,13 +1,15 @@
 package obsidian
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"daily/internal/activity"
@@ -17,12 +19,23 @@ import (
 type Provider struct {
 	config    provider.Config
 	vaultPath string
+	skipped   skipStats  // Paths left out of the last vault scan
+	skippedMu sync.Mutex // Guards the binary notes of skipped, counted by the workers parsing notes
+
+	indexPath string     // Index of the parsed notes, notes are parsed on every scan when empty
+	index     *noteIndex // Loaded by the first scan
+
+	warnings []string // Parts of the last GetActivities that failed
 }
 
 func NewProvider(config provider.Config) *Provider {
+	// The index is optional: every note is parsed when it can't be located
+	indexPath, _ := defaultIndexPath(config.URL)
+
 	return &Provider{
 		config:    config,
 		vaultPath: config.URL, // Using URL field to store vault path
+		indexPath: indexPath,
 	}
 }
 
@@ -34,93 +47,194 @@ func (p *Provider) IsConfigured() bool {
 	return p.config.Enabled && p.vaultPath != ""
 }
 
+// ConfigSpec describes the configuration fields of the Obsidian provider
+func (p *Provider) ConfigSpec() []provider.ConfigField {
+	return []provider.ConfigField{
+		{Name: "url", Required: true, Description: "Path to the Obsidian vault directory"},
+		{Name: "exclude_paths", Description: "Glob patterns of vault paths never scanned, e.g. Templates or **/node_modules"},
+		{Name: "pending_states", Description: "Checkbox states of tasks listed as todos (default \" \" and \"/\")"},
+		{Name: "ignored_states", Description: "Checkbox states of tasks never listed, even when pending (default every other state, e.g. x, - and >)"},
+		{Name: "daily_note_format", Description: "Go layout of daily note names, whose activities are dated by name instead of modification time (default 2006-01-02)"},
+		{Name: "daily_notes_folder", Description: "Vault folder holding the daily notes (default anywhere in the vault)"},
+		{Name: "scan_workers", Description: "Notes parsed at the same time (default the number of CPUs)"},
+		{Name: "max_line_length", Description: "Longest line of a note read, in bytes, longer lines such as embedded images are skipped (default 1 MiB)"},
+		{Name: "use_advanced_uri", Description: "Link tasks to their line with the Advanced URI plugin instead of opening their note"},
+		{Name: "use_git", Description: "Report the notes committed in the range of a git vault, dated by their commits, instead of the notes modified in it"},
+		{Name: "follow_symlinks", Description: "Scan the folders symlinked in the vault, e.g. a synced Shared folder"},
+		{Name: "meeting_rules", Description: "Folders, title patterns and type properties of meeting notes, reported as meetings (default Meetings folders, \"type: meeting\" and 1:1 or meeting titles)"},
+	}
+}
+
 func (p *Provider) GetActivities(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
 	if !p.IsConfigured() {
 		return nil, fmt.Errorf("Obsidian provider not configured")
 	}
+	p.warnings = nil
 
 	var activities []activity.Activity
 
-	// Find notes created or modified in the time range
-	notes, err := p.findRecentNotes(from, to)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find recent notes: %w", err)
+	// Find notes and meetings created or modified in the time range, unless both are excluded
+	if p.config.IncludesType(activity.ActivityTypeNote) || p.config.IncludesType(activity.ActivityTypeMeeting) {
+		notes, err := p.findRecentNotes(ctx, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find recent notes: %w", err)
+		}
+		activities = append(activities, notes...)
 	}
-	activities = append(activities, notes...)
 
-	// Find tasks created or modified in the time range
-	tasks, err := p.findRecentTasks(ctx, from, to)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find recent tasks: %w", err)
+	// Find tasks created or modified in the time range, unless tasks are excluded
+	if p.config.IncludesType(activity.ActivityTypeTask) {
+		tasks, err := p.findRecentTasks(ctx, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find recent tasks: %w", err)
+		}
+		activities = append(activities, tasks...)
+	}
+
+	// Find tasks completed in the time range, whenever their note was modified
+	if p.config.IncludesType(activity.ActivityTypeTaskCompleted) {
+		completed, err := p.findCompletedTasks(ctx, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find completed tasks: %w", err)
+		}
+		activities = append(activities, completed...)
 	}
-	activities = append(activities, tasks...)
 
 	return activities, nil
 }
 
-func (p *Provider) findRecentNotes(from, to time.Time) ([]activity.Activity, error) {
-	var activities []activity.Activity
+// Warnings describes the parts of the last GetActivities that failed, such as reading the git
+// history of the vault
+func (p *Provider) Warnings() []string {
+	return p.warnings
+}
 
-	err := filepath.Walk(p.vaultPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// findRecentNotes finds notes created or modified within the specified time range, reporting
+// the notes detected as meetings by the meeting rules as meetings
+func (p *Provider) findRecentNotes(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
+	rules, err := compileMeetingRules(p.config.MeetingRules)
+	if err != nil {
+		return nil, fmt.Errorf("meeting_rules: %w", err)
+	}
+	includeNotes := p.config.IncludesType(activity.ActivityTypeNote)
+	includeMeetings := p.config.IncludesType(activity.ActivityTypeMeeting)
+	useGit := p.isGitVault()
+
+	// The git history of the vault is read once for every note: it tells the lines each note
+	// changed, and with use_git, the notes changed in the range are the ones committed in it.
+	// Without it, notes are dated by modification time.
+	var history *gitHistory
+	if useGit {
+		if history, err = p.gitNoteHistory(ctx, from, to); err != nil {
+			p.warnings = append(p.warnings, fmt.Sprintf("Could not read the git history of the vault, dating notes by modification time: %v", err))
 		}
+	}
+	datedByGit := p.config.UseGit && history != nil
 
-		// Only process .md files
-		if !strings.HasSuffix(info.Name(), ".md") {
-			return nil
+	return scanNotes(ctx, p, func(ctx context.Context, path string, info os.FileInfo) ([]activity.Activity, error) {
+		// Empty notes, e.g. created by following a link to a missing note, aren't activity
+		if info.Size() == 0 {
+			return nil, nil
 		}
+		relPath, _ := filepath.Rel(p.vaultPath, path)
 
-		// Check if file was modified in our time range
-		if info.ModTime().Before(from) || info.ModTime().After(to) {
-			return nil
+		// Check if the note was committed, modified, or is a daily note named after a day, in our time range
+		var timestamp time.Time
+		var ok bool
+		if datedByGit {
+			timestamp, ok = history.noteTime(p, relPath, path, info, from, to)
+		} else {
+			timestamp, ok = p.activityTime(path, info, from, to)
+		}
+		if !ok {
+			return nil, nil
 		}
 
 		// Create activity for this note
-		relPath, _ := filepath.Rel(p.vaultPath, path)
 		title := strings.TrimSuffix(info.Name(), ".md")
 
-		activities = append(activities, activity.Activity{
-			ID:          fmt.Sprintf("obsidian-%s", relPath),
-			Type:        activity.ActivityTypeNote,
-			Title:       title,
-			Description: fmt.Sprintf("Note: %s", relPath),
-			Platform:    "obsidian",
-			Timestamp:   info.ModTime(),
-		})
-
-		return nil
-	})
-
-	return activities, err
-}
+		// Notes that can't be parsed are reported without their metadata, unless they look binary
+		var note parsedNote
+		parsed, err := p.readNote(path, info)
+		if errors.Is(err, errBinaryNote) {
+			return nil, nil
+		} else if err == nil {
+			note = parsed
+		}
+		meta := note.meta
 
-// findRecentTasks finds tasks that were created or modified within the specified time range
-func (p *Provider) findRecentTasks(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
-	var activities []activity.Activity
+		// Notes are updated unless known to be created in the time range
+		verb, tag := "Updated", "updated"
+		if created, ok := noteCreated(path, info, meta); ok && !created.Before(from) && !created.After(to) {
+			verb, tag = "Created", "created"
+		}
 
-	err := filepath.Walk(p.vaultPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+		var activities []activity.Activity
+		if meetings := rules.meetings(relPath, title, note); len(meetings) > 0 {
+			if !includeMeetings {
+				return nil, nil
+			}
+			for _, m := range meetings {
+				id := fmt.Sprintf("obsidian-%s", relPath)
+				if m.heading {
+					id += "#" + m.title
+				}
+				activities = append(activities, activity.Activity{
+					ID:          id,
+					Type:        activity.ActivityTypeMeeting,
+					Title:       m.title,
+					Description: fmt.Sprintf("%s meeting notes: %s", verb, relPath),
+					Platform:    "obsidian",
+					Timestamp:   timestamp,
+					Tags:        appendTags(appendTags([]string{tag}, attendeeTags(m.attendees)...), meta.tags...),
+				})
+			}
+		} else {
+			if !includeNotes {
+				return nil, nil
+			}
+			activities = append(activities, activity.Activity{
+				ID:          fmt.Sprintf("obsidian-%s", relPath),
+				Type:        activity.ActivityTypeNote,
+				Title:       title,
+				Description: fmt.Sprintf("%s note: %s", verb, relPath),
+				Platform:    "obsidian",
+				Timestamp:   timestamp,
+				Tags:        appendTags([]string{tag}, meta.tags...),
+			})
 		}
 
-		// Only process .md files
-		if !strings.HasSuffix(info.Name(), ".md") {
-			return nil
+		if history != nil {
+			if changes := history.changeStats(relPath); changes != nil {
+				activities[0].Changes = changes
+				// With use_git, the description tells how much the commits changed
+				if datedByGit {
+					activities[0].Description += " (" + describeChanges(*changes) + ")"
+				}
+			}
 		}
 
-		// Check if file was modified in our time range
-		if info.ModTime().Before(from) || info.ModTime().After(to) {
-			return nil
+		return activities, nil
+	})
+}
+
+// findRecentTasks finds tasks that were created or modified within the specified time range
+func (p *Provider) findRecentTasks(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
+	return scanNotes(ctx, p, func(_ context.Context, path string, info os.FileInfo) ([]activity.Activity, error) {
+		// Check if the note was modified, or is a daily note named after a day, in our time range
+		timestamp, ok := p.activityTime(path, info, from, to)
+		if !ok {
+			return nil, nil
 		}
 
 		// Parse tasks from this file and convert to activities
 		fileTasks, err := p.parseTasksFromFile(path, info)
 		if err != nil {
-			return nil // Skip files we can't read
+			return nil, err
 		}
 
 		// Convert TodoItems to Activities
+		var activities []activity.Activity
 		for _, task := range fileTasks {
 			activities = append(activities, activity.Activity{
 				ID:          task.ID,
@@ -129,15 +243,13 @@ func (p *Provider) findRecentTasks(ctx context.Context, from, to time.Time) ([]a
 				Description: task.Description,
 				URL:         task.URL,
 				Platform:    "obsidian",
-				Timestamp:   task.UpdatedAt,
+				Timestamp:   timestamp,
 				Tags:        task.Tags,
+				Details:     task.Details,
 			})
 		}
-
-		return nil
+		return activities, nil
 	})
-
-	return activities, err
 }
 
 // GetTasks retrieves pending tasks from Obsidian markdown files
@@ -146,36 +258,62 @@ func (p *Provider) GetTasks(ctx context.Context) ([]TodoItem, error) {
 		return nil, fmt.Errorf("Obsidian provider not configured")
 	}
 
-	var tasks []TodoItem
-
-	err := filepath.Walk(p.vaultPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	// Files that can't be read are skipped
+	return scanNotes(ctx, p, func(_ context.Context, path string, info os.FileInfo) ([]TodoItem, error) {
+		return p.parseTasksFromFile(path, info)
+	})
+}
 
-		// Only process .md files
-		if !strings.HasSuffix(info.Name(), ".md") {
-			return nil
-		}
+// parseTasksFromFile extracts incomplete tasks from a markdown file
+func (p *Provider) parseTasksFromFile(filePath string, fileInfo os.FileInfo) ([]TodoItem, error) {
+	note, err := p.readNote(filePath, fileInfo)
+	if err != nil {
+		return nil, err
+	}
 
-		// Parse tasks from this file
-		fileTasks, err := p.parseTasksFromFile(path, info)
-		if err != nil {
-			return nil // Skip files we can't read
+	var tasks []TodoItem
+	for _, line := range note.tasks {
+		if p.isPendingState(line.state) {
+			tasks = append(tasks, p.newTask(line, filePath, fileInfo, note.meta))
 		}
+	}
+	return tasks, nil
+}
 
-		tasks = append(tasks, fileTasks...)
-		return nil
-	})
+// newTask creates the TodoItem of a task, tagged with its checkbox state and the tags of
+// its note, and described with the headings it is under
+func (p *Provider) newTask(line taskLine, filePath string, fileInfo os.FileInfo, meta frontmatter) TodoItem {
+	task := p.createTodoItem(line.text, line.raw, filePath, fileInfo, line.lineNum)
+	task.Tags = append(task.Tags, stateTag(line.state))
+	task.Details = activity.NewDetails(activity.DetailState, strings.TrimPrefix(stateTag(line.state), "state:"))
+	task.Tags = appendTags(task.Tags, meta.tags...)
+	if line.context != "" {
+		task.Context = line.context
+		task.Description += contextSeparator + line.context
+	}
+	task.ParentTask = line.parent
+	task.Snippet = line.snippet
+	return task
+}
 
-	return tasks, err
+// taskLine is a task as written in a note
+type taskLine struct {
+	state   string // Checkbox state, e.g. " " or "x"
+	text    string
+	raw     string
+	lineNum int
+	context string // Headings the task is under
+	parent  string // Text of the task it is nested under
+	snippet string // Lines around the task, see taskSnippet
 }
 
-// parseTasksFromFile extracts incomplete tasks from a markdown file
-func (p *Provider) parseTasksFromFile(filePath string, fileInfo os.FileInfo) ([]TodoItem, error) {
+// scanTasks calls visit with the tasks of a markdown file in every checkbox state, and
+// visitHeading with its headings, skipping the frontmatter lines, code blocks and blockquotes,
+// and the lines longer than maxLineLength bytes
+func scanTasks(filePath string, skipLines, maxLineLength int, visit func(taskLine), visitHeading func(heading)) error {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer func() {
 		if closeErr := file.Close(); closeErr != nil {
@@ -184,21 +322,28 @@ func (p *Provider) parseTasksFromFile(filePath string, fileInfo os.FileInfo) ([]
 		}
 	}()
 
-	var tasks []TodoItem
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
-
-	// Regex patterns for supported task formats: [ ], [/], [x]
-	todoTaskPattern := regexp.MustCompile(`^\s*[-*+]\s*\[\s\]\s*(.+)$`)
-	ongoingTaskPattern := regexp.MustCompile(`^\s*[-*+]\s*\[/\]\s*(.+)$`)
-	numberedTodoPattern := regexp.MustCompile(`^\s*\d+\.\s*\[\s\]\s*(.+)$`)
-	numberedOngoingPattern := regexp.MustCompile(`^\s*\d+\.\s*\[/\]\s*(.+)$`)
+	// The lines are read first, as the snippet of a task has the lines after it
+	var lines []string
+	scanner, err := newLineScanner(file, maxLineLength)
+	if err != nil {
+		return err
+	}
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
 	inCodeBlock := false
 	inBlockQuote := false
+	var nesting taskContext
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		lineNum++
+	for i, line := range lines {
+		lineNum := i + 1
+		if lineNum <= skipLines {
+			continue
+		}
 
 		// Skip tasks in code blocks
 		if strings.HasPrefix(strings.TrimSpace(line), "```") {
@@ -215,50 +360,100 @@ func (p *Provider) parseTasksFromFile(filePath string, fileInfo os.FileInfo) ([]
 			continue
 		}
 
-		// Match todo tasks (- [ ] or * [ ] or + [ ])
-		if matches := todoTaskPattern.FindStringSubmatch(line); len(matches) > 1 {
-			taskText := strings.TrimSpace(matches[1])
-			tasks = append(tasks, p.createTodoItem(taskText, filePath, fileInfo, lineNum))
-		}
-
-		// Match ongoing tasks (- [/] or * [/] or + [/])
-		if matches := ongoingTaskPattern.FindStringSubmatch(line); len(matches) > 1 {
-			taskText := strings.TrimSpace(matches[1])
-			tasks = append(tasks, p.createTodoItem(taskText, filePath, fileInfo, lineNum))
+		// Track the headings the next tasks are under
+		if nesting.heading(line) {
+			visitHeading(nesting.headings[len(nesting.headings)-1])
+			continue
 		}
 
-		// Match numbered todo tasks (1. [ ])
-		if matches := numberedTodoPattern.FindStringSubmatch(line); len(matches) > 1 {
-			taskText := strings.TrimSpace(matches[1])
-			tasks = append(tasks, p.createTodoItem(taskText, filePath, fileInfo, lineNum))
+		// Match tasks (- [ ], * [x], 1. [/]...)
+		matches := taskPattern.FindStringSubmatch(line)
+		text := ""
+		if len(matches) > 2 {
+			text = matches[2]
 		}
-
-		// Match numbered ongoing tasks (1. [/])
-		if matches := numberedOngoingPattern.FindStringSubmatch(line); len(matches) > 1 {
-			taskText := strings.TrimSpace(matches[1])
-			tasks = append(tasks, p.createTodoItem(taskText, filePath, fileInfo, lineNum))
+		parent := nesting.listItem(line, text)
+		if text != "" {
+			visit(taskLine{
+				state:   matches[1],
+				text:    text,
+				raw:     line,
+				lineNum: lineNum,
+				context: nesting.path(),
+				parent:  parent,
+				snippet: taskSnippet(lines, i, skipLines),
+			})
 		}
 	}
 
-	return tasks, scanner.Err()
+	return nil
 }
 
-// createTodoItem creates a TodoItem from task text and file info
-func (p *Provider) createTodoItem(taskText, filePath string, fileInfo os.FileInfo, lineNum int) TodoItem {
+// createTodoItem creates a TodoItem from task text, the line it was found on and file info
+func (p *Provider) createTodoItem(taskText, rawLine, filePath string, fileInfo os.FileInfo, lineNum int) TodoItem {
 	relPath, _ := filepath.Rel(p.vaultPath, filePath)
 	fileName := strings.TrimSuffix(fileInfo.Name(), ".md")
+	taskText, related := extractWikiLinks(normalizeTaskTitle(taskText))
 
-	// Extract tags from task text
-	tags := extractTags(taskText)
+	// Extract tags, priority and due date from task text, then tag the notes it links to
+	taskText, meta := extractTaskMetadata(taskText)
+	tags := meta.tags
+	for _, note := range related {
+		tags = append(tags, "ref:"+note)
+	}
 
 	return TodoItem{
-		ID:          fmt.Sprintf("obsidian-task-%s:%d", relPath, lineNum),
-		Title:       taskText,
-		Description: fmt.Sprintf("Task in %s", fileName),
-		URL:         fmt.Sprintf("obsidian://open?vault=%s&file=%s", filepath.Base(p.vaultPath), relPath),
-		UpdatedAt:   fileInfo.ModTime(),
-		Tags:        tags,
+		ID:           fmt.Sprintf("obsidian-task-%s:%d", relPath, lineNum),
+		Title:        taskText,
+		Description:  fmt.Sprintf("Task in %s", fileName),
+		URL:          p.taskURL(relPath, lineNum),
+		UpdatedAt:    fileInfo.ModTime(),
+		Tags:         tags,
+		RelatedNotes: related,
+		Priority:     meta.priority,
+		DueDate:      meta.due,
+		Line:         lineNum,
+		RawLine:      rawLine,
+		Provenance: &provider.Provenance{
+			Provider:  "obsidian",
+			Source:    fmt.Sprintf("%s:%d", relPath, lineNum),
+			FetchedAt: time.Now(),
+		},
+	}
+}
+
+// taskURL links to the note of a task, at the line of the task when the Advanced URI plugin
+// is enabled
+func (p *Provider) taskURL(relPath string, lineNum int) string {
+	vault := uriComponent(filepath.Base(p.vaultPath))
+	file := uriComponent(filepath.ToSlash(relPath))
+	if p.config.UseAdvancedURI {
+		return fmt.Sprintf("obsidian://advanced-uri?vault=%s&filepath=%s&line=%d", vault, file, lineNum)
 	}
+	return fmt.Sprintf("obsidian://open?vault=%s&file=%s", vault, file)
+}
+
+// uriComponent escapes a parameter of an obsidian:// URL the way Obsidian does, with %20
+// for spaces
+func uriComponent(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+// Invisible characters dropped from task titles. Zero-width joiners are kept as they
+// are part of emoji sequences and some scripts.
+var invisibleRunes = strings.NewReplacer(
+	"\u200b", "", // Zero-width space
+	"\u2060", "", // Word joiner
+	"\ufeff", "", // Zero-width no-break space (BOM)
+)
+
+// normalizeTaskTitle canonicalizes the whitespace of a task title, as tasks pasted from
+// other tools come with tabs, non-breaking spaces and markdown line breaks: invisible
+// characters are dropped, whitespace runs collapse to a single space, and trailing
+// backslash line breaks are stripped
+func normalizeTaskTitle(text string) string {
+	text = strings.Join(strings.Fields(invisibleRunes.Replace(text)), " ")
+	return strings.TrimRight(text, "\\ ")
 }
 
 // extractTags extracts hashtags and other markers from task text
@@ -294,4 +489,18 @@ type TodoItem struct {
 	URL         string    `json:"url,omitempty"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	Tags        []string  `json:"tags,omitempty"`
+	Line        int       `json:"line,omitempty"`        // Line number of the task in its note, from 1
+	RawLine     string    `json:"raw_line,omitempty"`    // Line of the task as written in the file, before normalization
+	Context     string    `json:"context,omitempty"`     // Headings the task is under, e.g. "Client X › Meeting 2024-05-12"
+	ParentTask  string    `json:"parent_task,omitempty"` // Task an indented subtask is nested under
+	Snippet     string    `json:"snippet,omitempty"`     // Lines around the task in its note, other tasks left out
+
+	RelatedNotes []string `json:"related_notes,omitempty"` // Notes the task links to, e.g. "Project Phoenix" for [[Project Phoenix]]
+
+	Priority Priority   `json:"priority,omitempty"` // Priority of the Tasks plugin emoji, e.g. "high" for ⏫
+	DueDate  *time.Time `json:"due_date,omitempty"` // Due date of the Tasks plugin, e.g. 📅 2024-05-30
+
+	Details map[string]string `json:"details,omitempty"` // Checkbox state, e.g. "space" or "/"
+
+	Provenance *provider.Provenance `json:"provenance,omitempty"` // File and line the task was found on
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"daily/internal/activity"
 	"daily/internal/provider"
 )
 
@@ -162,3 +163,30 @@ func TestProvider_GetActivities_NotConfigured(t *testing.T) {
 		t.Error("Expected error for unconfigured provider, got nil")
 	}
 }
+
+func TestProvider_GetActivities_IncludeTypes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	content := "# Planning\n- [ ] Write the report\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "planning.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	p := NewProvider(provider.Config{
+		URL:          tempDir,
+		Enabled:      true,
+		IncludeTypes: []string{"task"},
+	})
+
+	activities, err := p.GetActivities(context.Background(), time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(activities) != 1 {
+		t.Fatalf("Expected 1 activity, got %d", len(activities))
+	}
+	if activities[0].Type != activity.ActivityTypeTask {
+		t.Errorf("Expected only task activities, got %s", activities[0].Type)
+	}
+}
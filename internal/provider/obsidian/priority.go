@@ -0,0 +1,61 @@
+package obsidian
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Priority is the priority of a task, set with the priority emojis of the Tasks plugin
+type Priority string
+
+const (
+	PriorityHighest Priority = "highest" // 🔺
+	PriorityHigh    Priority = "high"    // ⏫
+	PriorityMedium  Priority = "medium"  // 🔼
+	PriorityNone    Priority = ""        // No emoji, ranked between medium and low like in the Tasks plugin
+	PriorityLow     Priority = "low"     // 🔽
+	PriorityLowest  Priority = "lowest"  // ⏬
+)
+
+// priorityEmojis maps the priority emojis of the Tasks plugin to their priority
+var priorityEmojis = map[string]Priority{
+	"🔺": PriorityHighest,
+	"⏫": PriorityHigh,
+	"🔼": PriorityMedium,
+	"🔽": PriorityLow,
+	"⏬": PriorityLowest,
+}
+
+// priorityPattern matches the priority emojis of task text, with the spaces before them and
+// the variation selector some keyboards append
+var priorityPattern = regexp.MustCompile("\\s*(🔺|⏫|🔼|🔽|⏬)\uFE0F?")
+
+// duePattern matches the due date of the Tasks plugin, e.g. "📅 2024-05-30"
+var duePattern = regexp.MustCompile("(?:📅|📆|🗓\uFE0F?)\\s*(\\d{4}-\\d{2}-\\d{2})")
+
+// taskMetadata is what the markers of task text tell about the task
+type taskMetadata struct {
+	tags     []string
+	priority Priority
+	due      *time.Time // Due date at midnight local time, nil without one
+}
+
+// extractTaskMetadata returns the tags, the priority and the due date of task text, and the
+// text without its priority emojis. The first priority emoji wins.
+func extractTaskMetadata(text string) (string, taskMetadata) {
+	meta := taskMetadata{tags: extractTags(text)}
+
+	if matches := priorityPattern.FindStringSubmatch(text); matches != nil {
+		meta.priority = priorityEmojis[matches[1]]
+		text = strings.TrimSpace(priorityPattern.ReplaceAllString(text, ""))
+	}
+
+	if matches := duePattern.FindStringSubmatch(text); matches != nil {
+		if due, err := time.ParseInLocation("2006-01-02", matches[1], time.Local); err == nil {
+			meta.due = &due
+		}
+	}
+
+	return text, meta
+}
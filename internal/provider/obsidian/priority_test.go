@@ -0,0 +1,102 @@
+package obsidian
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestExtractTaskMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		title    string
+		priority Priority
+		tags     []string
+		due      string
+	}{
+		{name: "highest", text: "Fix the outage 🔺", title: "Fix the outage", priority: PriorityHighest},
+		{name: "high", text: "Ship the release ⏫", title: "Ship the release", priority: PriorityHigh},
+		{name: "medium", text: "Review the spec 🔼", title: "Review the spec", priority: PriorityMedium},
+		{name: "none", text: "Water the plants", title: "Water the plants", priority: PriorityNone},
+		{name: "low", text: "Tidy the wiki 🔽", title: "Tidy the wiki", priority: PriorityLow},
+		{name: "lowest", text: "Read the backlog ⏬", title: "Read the backlog", priority: PriorityLowest},
+		{name: "variation selector", text: "Ship it ⏫️", title: "Ship it", priority: PriorityHigh},
+		{name: "emoji first", text: "🔼 Review the spec", title: "Review the spec", priority: PriorityMedium},
+		{
+			name:     "with hashtags",
+			text:     "Deploy #backend ⏫ #release",
+			title:    "Deploy #backend #release",
+			priority: PriorityHigh,
+			tags:     []string{"backend", "release"},
+		},
+		{
+			name:     "with hashtags and due date",
+			text:     "Send the invoice #admin 🔽 📅 2024-05-30",
+			title:    "Send the invoice #admin 📅 2024-05-30",
+			priority: PriorityLow,
+			tags:     []string{"admin", "scheduled"},
+			due:      "2024-05-30",
+		},
+		{name: "first emoji wins", text: "Plan the offsite 🔽 ⏫", title: "Plan the offsite", priority: PriorityLow},
+		{name: "due date without priority", text: "Renew the domain 📅 2024-06-01", title: "Renew the domain 📅 2024-06-01", tags: []string{"scheduled"}, due: "2024-06-01"},
+		{name: "invalid due date", text: "Renew the domain 📅 2024-13-01", title: "Renew the domain 📅 2024-13-01", tags: []string{"scheduled"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, meta := extractTaskMetadata(tt.text)
+			if title != tt.title {
+				t.Errorf("Expected title %q, got %q", tt.title, title)
+			}
+			if meta.priority != tt.priority {
+				t.Errorf("Expected priority %q, got %q", tt.priority, meta.priority)
+			}
+			if !reflect.DeepEqual(meta.tags, tt.tags) {
+				t.Errorf("Expected tags %v, got %v", tt.tags, meta.tags)
+			}
+
+			due := ""
+			if meta.due != nil {
+				due = meta.due.Format("2006-01-02")
+			}
+			if due != tt.due {
+				t.Errorf("Expected due date %q, got %q", tt.due, due)
+			}
+		})
+	}
+}
+
+func TestProvider_GetTasks_Priority(t *testing.T) {
+	tempDir := t.TempDir()
+	content := "- [ ] Ship the release ⏫ 📅 2024-05-30\n- [ ] Water the plants\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "tasks.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	p := NewProvider(provider.Config{URL: tempDir, Enabled: true})
+	tasks, err := p.GetTasks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %d", len(tasks))
+	}
+
+	release := tasks[0]
+	if release.Title != "Ship the release 📅 2024-05-30" || release.Priority != PriorityHigh {
+		t.Errorf("Expected high priority task without its emoji, got %q (%q)", release.Title, release.Priority)
+	}
+	expectedDue := time.Date(2024, 5, 30, 0, 0, 0, 0, time.Local)
+	if release.DueDate == nil || !release.DueDate.Equal(expectedDue) {
+		t.Errorf("Expected due date %v, got %v", expectedDue, release.DueDate)
+	}
+	if plants := tasks[1]; plants.Priority != PriorityNone || plants.DueDate != nil {
+		t.Errorf("Expected no priority nor due date, got %q and %v", plants.Priority, plants.DueDate)
+	}
+}
@@ -0,0 +1,90 @@
+package obsidian
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"daily/internal/concurrency"
+)
+
+// note is a markdown file of the vault
+type note struct {
+	path string
+	info os.FileInfo
+}
+
+// listNotes returns the notes of the vault sorted by path, stopping when ctx is cancelled
+func (p *Provider) listNotes(ctx context.Context) ([]note, error) {
+	var notes []note
+	err := p.walkNotes(ctx, func(path string, info os.FileInfo) error {
+		notes = append(notes, note{path: path, info: info})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(notes, func(a, b note) int { return strings.Compare(a.path, b.path) })
+	return notes, nil
+}
+
+// maxLineLength returns the longest line of a note read
+func (p *Provider) maxLineLength() int {
+	if p.config.MaxLineLength > 0 {
+		return p.config.MaxLineLength
+	}
+	return defaultMaxLineLength
+}
+
+// scanWorkers returns how many notes are parsed at the same time
+func (p *Provider) scanWorkers() int {
+	if p.config.ScanWorkers > 0 {
+		return p.config.ScanWorkers
+	}
+	return runtime.NumCPU()
+}
+
+// scanNotes calls parse for every note of the vault with a bounded pool of workers and
+// returns the results in path order, whatever order the notes were parsed in. Notes that
+// can't be parsed are skipped. A cancelled ctx stops the scan with its error.
+func scanNotes[T any](ctx context.Context, p *Provider, parse func(ctx context.Context, path string, info os.FileInfo) ([]T, error)) ([]T, error) {
+	notes, err := p.listNotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.index == nil && p.indexPath != "" {
+		p.index = loadNoteIndex(p.indexPath, p.vaultPath, p.maxLineLength())
+	}
+	if p.index != nil {
+		relPaths := make([]string, len(notes))
+		for i, n := range notes {
+			relPaths[i], _ = filepath.Rel(p.vaultPath, n.path)
+		}
+		p.index.retain(relPaths)
+		// The index is best effort: notes are parsed again on the next scan when it can't be saved
+		defer func() { _ = p.index.save() }()
+	}
+
+	results := concurrency.Map(ctx, notes, concurrency.Options{Workers: p.scanWorkers()}, func(ctx context.Context, _ int, n note) ([]T, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return parse(ctx, n.path, n.info)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var values []T
+	for _, result := range results {
+		if result.Err == nil {
+			values = append(values, result.Value...)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,119 @@
+package obsidian
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+// writeVault creates a vault of count notes spread over nested folders, each with two
+// pending tasks
+func writeVault(t testing.TB, count int) string {
+	t.Helper()
+	vault := t.TempDir()
+	for i := 0; i < count; i++ {
+		dir := filepath.Join(vault, fmt.Sprintf("area-%d", i%3), fmt.Sprintf("project-%d", i%5))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create folder: %v", err)
+		}
+		content := fmt.Sprintf("# Note %d\n- [ ] First task of %d\n- [x] Done\n- [/] Second task of %d #urgent\n", i, i, i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("note-%03d.md", i)), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create note: %v", err)
+		}
+	}
+	// A note next to a folder of the same name, sorted before the notes of the folder
+	if err := os.WriteFile(filepath.Join(vault, "area-0.md"), []byte("- [ ] Top-level task\n"), 0644); err != nil {
+		t.Fatalf("Failed to create note: %v", err)
+	}
+	return vault
+}
+
+func TestProvider_GetTasks_ParallelMatchesSerial(t *testing.T) {
+	vault := writeVault(t, 60)
+
+	taskIDs := func(workers int) []string {
+		p := NewProvider(provider.Config{URL: vault, Enabled: true, ScanWorkers: workers})
+		tasks, err := p.GetTasks(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error with %d workers, got: %v", workers, err)
+		}
+		ids := make([]string, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+		return ids
+	}
+
+	serial := taskIDs(1)
+	if len(serial) != 121 {
+		t.Fatalf("Expected 121 tasks, got %d", len(serial))
+	}
+	if serial[0] != "obsidian-task-area-0.md:1" {
+		t.Errorf("Expected the tasks sorted by path, got %s first", serial[0])
+	}
+
+	for _, workers := range []int{4, 16} {
+		if parallel := taskIDs(workers); !slices.Equal(parallel, serial) {
+			t.Errorf("Expected the same tasks in the same order with %d workers, got %v", workers, parallel)
+		}
+	}
+}
+
+func TestScanNotes_Cancellation(t *testing.T) {
+	vault := writeVault(t, 30)
+
+	t.Run("before the walk", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		p := NewProvider(provider.Config{URL: vault, Enabled: true})
+		if _, err := p.GetTasks(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the context error, got: %v", err)
+		}
+	})
+
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("while parsing with %d workers", workers), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			p := NewProvider(provider.Config{URL: vault, Enabled: true, ScanWorkers: workers})
+			var parsed atomic.Int32
+			_, err := scanNotes(ctx, p, func(_ context.Context, path string, info os.FileInfo) ([]TodoItem, error) {
+				parsed.Add(1)
+				cancel()
+				return p.parseTasksFromFile(path, info)
+			})
+
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected the context error, got: %v", err)
+			}
+			// Only the notes already handed to a worker are parsed
+			if count := int(parsed.Load()); count > workers {
+				t.Errorf("Expected at most %d of 31 notes to be parsed, got %d", workers, count)
+			}
+		})
+	}
+}
+
+func BenchmarkProvider_GetTasks(b *testing.B) {
+	vault := writeVault(b, 2000)
+
+	for _, workers := range []int{1, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			p := NewProvider(provider.Config{URL: vault, Enabled: true, ScanWorkers: workers})
+			for i := 0; i < b.N; i++ {
+				if _, err := p.GetTasks(context.Background()); err != nil {
+					b.Fatalf("Expected no error, got: %v", err)
+				}
+			}
+		})
+	}
+}
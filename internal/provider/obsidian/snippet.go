@@ -0,0 +1,36 @@
+package obsidian
+
+import (
+	"strings"
+)
+
+const (
+	// snippetLines is how many lines before and after a task its snippet has, at most
+	snippetLines = 2
+	// maxSnippetLength is the length snippets are cut to, in characters
+	maxSnippetLength = 300
+)
+
+// taskSnippet returns the lines around the task on line i of a note: the snippetLines lines
+// before and after it, without the frontmatter (its first skipLines lines), blank lines,
+// code block fences and other tasks, cut to maxSnippetLength characters
+func taskSnippet(lines []string, i, skipLines int) string {
+	var snippet []string
+	for j := max(i-snippetLines, skipLines); j <= min(i+snippetLines, len(lines)-1); j++ {
+		line := strings.TrimSpace(lines[j])
+		if j == i || line == "" || strings.HasPrefix(line, "```") || taskPattern.MatchString(lines[j]) {
+			continue
+		}
+		snippet = append(snippet, line)
+	}
+	return truncateSnippet(strings.Join(snippet, "\n"))
+}
+
+// truncateSnippet cuts a snippet to maxSnippetLength characters, ending it with "…" when cut
+func truncateSnippet(snippet string) string {
+	runes := []rune(snippet)
+	if len(runes) <= maxSnippetLength {
+		return snippet
+	}
+	return strings.TrimSpace(string(runes[:maxSnippetLength-1])) + "…"
+}
@@ -0,0 +1,93 @@
+package obsidian
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+func TestTaskSnippet(t *testing.T) {
+	lines := []string{
+		"---",                  // 0
+		"tags: [work]",         // 1
+		"---",                  // 2
+		"- [ ] First task",     // 3
+		"Notes from the sync",  // 4
+		"",                     // 5
+		"- [ ] Middle task",    // 6
+		"- [x] Done task",      // 7
+		"```",                  // 8
+		"  Indented follow-up", // 9
+		"- [ ] Last task",      // 10
+	}
+
+	tests := []struct {
+		name     string
+		line     int
+		expected string
+	}{
+		{"start of the note after the frontmatter", 3, "Notes from the sync"},
+		{"blank lines and other tasks left out", 6, "Notes from the sync"},
+		{"code block fences left out", 7, "Indented follow-up"},
+		{"end of the note", 10, "Indented follow-up"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if snippet := taskSnippet(lines, tt.line, 3); snippet != tt.expected {
+				t.Errorf("Expected snippet %q, got %q", tt.expected, snippet)
+			}
+		})
+	}
+
+	// Without frontmatter, the snippet starts at the first line
+	noFrontmatter := []string{"Intro", "- [ ] Task", "Outro one", "Outro two", "Too far"}
+	if snippet := taskSnippet(noFrontmatter, 1, 0); snippet != "Intro\nOutro one\nOutro two" {
+		t.Errorf("Expected the lines around the task, got %q", snippet)
+	}
+	if snippet := taskSnippet([]string{"- [ ] Alone"}, 0, 0); snippet != "" {
+		t.Errorf("Expected no snippet for a note of one task, got %q", snippet)
+	}
+}
+
+func TestTruncateSnippet(t *testing.T) {
+	short := "Short context"
+	if got := truncateSnippet(short); got != short {
+		t.Errorf("Expected a short snippet to be kept, got %q", got)
+	}
+
+	long := strings.Repeat("é", maxSnippetLength+10)
+	got := truncateSnippet(long)
+	if runes := []rune(got); len(runes) != maxSnippetLength || !strings.HasSuffix(got, "…") {
+		t.Errorf("Expected %d characters ending with an ellipsis, got %d: %q", maxSnippetLength, len(runes), got)
+	}
+}
+
+func TestProvider_GetTasks_Snippet(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // The note index is written under the home directory
+	vault := t.TempDir()
+	content := "# Sync with Sam\nSam asked for the deck\n- [ ] Send the slides\nDue before the board meeting\n"
+	if err := os.WriteFile(filepath.Join(vault, "note.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	p := NewProvider(provider.Config{URL: vault, Enabled: true})
+	// The second scan reads the note from the index
+	for range 2 {
+		tasks, err := p.GetTasks(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(tasks) != 1 {
+			t.Fatalf("Expected 1 task, got %d", len(tasks))
+		}
+		expected := "# Sync with Sam\nSam asked for the deck\nDue before the board meeting"
+		if tasks[0].Snippet != expected {
+			t.Errorf("Expected snippet %q, got %q", expected, tasks[0].Snippet)
+		}
+	}
+}
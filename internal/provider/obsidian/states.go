@@ -0,0 +1,53 @@
+package obsidian
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"daily/internal/provider"
+)
+
+// defaultPendingStates are the checkbox states of not started and in progress tasks.
+// Every other state, e.g. done (x), cancelled (-) or forwarded (>), is ignored.
+var defaultPendingStates = []string{" ", "/"}
+
+// taskPattern matches bulleted (- [ ]) and numbered (1. [ ]) tasks, capturing the
+// checkbox state and the task text
+var taskPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+\.)\s*\[(.)\]\s*(.+)$`)
+
+// taskStates returns the configured states, or defaults when none are configured.
+// States that can't be parsed are left out, config validation reports them.
+func taskStates(configured, defaults []string) []string {
+	if len(configured) == 0 {
+		return defaults
+	}
+	states := make([]string, 0, len(configured))
+	for _, s := range configured {
+		if state, err := provider.ParseTaskState(s); err == nil {
+			states = append(states, state)
+		}
+	}
+	return states
+}
+
+// isPendingState reports whether tasks with the checkbox state are listed as todos.
+// Ignored states win over pending ones.
+func (p *Provider) isPendingState(state string) bool {
+	if strings.TrimSpace(state) == "" {
+		state = " "
+	}
+	if slices.Contains(taskStates(p.config.IgnoredStates, nil), state) {
+		return false
+	}
+	return slices.Contains(taskStates(p.config.PendingStates, defaultPendingStates), state)
+}
+
+// stateTag returns the tag exposing the checkbox state of a task, e.g. "state:/".
+// Not started tasks are tagged "state:space".
+func stateTag(state string) string {
+	if strings.TrimSpace(state) == "" {
+		return "state:space"
+	}
+	return "state:" + state
+}
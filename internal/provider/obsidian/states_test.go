@@ -0,0 +1,79 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"daily/internal/provider"
+)
+
+const statesContent = `- [ ] Not started
+- [/] In progress
+- [x] Done
+- [X] Done too
+- [-] Cancelled
+- [>] Forwarded
+- [?] Question
+1. [>] Numbered forwarded
+`
+
+func TestProvider_parseTasksFromFile_States(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "states.md")
+	if err := os.WriteFile(filePath, []byte(statesContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("Failed to stat test file: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		pendingStates []string
+		ignoredStates []string
+		expected      []string // Title and state tag of the listed tasks
+	}{
+		{
+			name:     "default states",
+			expected: []string{"Not started state:space", "In progress state:/"},
+		},
+		{
+			name:          "custom pending states",
+			pendingStates: []string{"[ ]", ">", "?"},
+			expected:      []string{"Not started state:space", "Forwarded state:>", "Question state:?", "Numbered forwarded state:>"},
+		},
+		{
+			name:          "ignored states win",
+			pendingStates: []string{" ", "/", "-"},
+			ignoredStates: []string{"/", "-"},
+			expected:      []string{"Not started state:space"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(provider.Config{
+				URL:           tempDir,
+				Enabled:       true,
+				PendingStates: tt.pendingStates,
+				IgnoredStates: tt.ignoredStates,
+			})
+
+			tasks, err := p.parseTasksFromFile(filePath, fileInfo)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			var listed []string
+			for _, task := range tasks {
+				listed = append(listed, task.Title+" "+task.Tags[len(task.Tags)-1])
+			}
+			if strings.Join(listed, ", ") != strings.Join(tt.expected, ", ") {
+				t.Errorf("Expected %v, got %v", tt.expected, listed)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package obsidian
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -392,7 +393,7 @@ func TestProvider_createTodoItem(t *testing.T) {
 	taskText := "Review #urgent document with 🔥 priority"
 	lineNum := 5
 
-	item := p.createTodoItem(taskText, filePath, fileInfo, lineNum)
+	item := p.createTodoItem(taskText, "- [ ] "+taskText, filePath, fileInfo, lineNum)
 
 	// Verify basic fields
 	if item.Title != taskText {
@@ -421,6 +422,73 @@ func TestProvider_createTodoItem(t *testing.T) {
 	if len(item.Tags) != len(expectedTags) {
 		t.Errorf("Expected %d tags, got %d: %v", len(expectedTags), len(item.Tags), item.Tags)
 	}
+
+	// The task is listed for the file and line it was found on
+	if item.Provenance == nil || item.Provenance.Provider != "obsidian" || item.Provenance.Source != "test.md:5" {
+		t.Errorf("Expected obsidian provenance from test.md:5, got %+v", item.Provenance)
+	}
+}
+
+func TestProvider_TaskURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		useAdvancedURI bool
+		relPath        string
+		expected       string
+	}{
+		{
+			name:     "open note",
+			relPath:  "Projects/Client X/Meeting & notes.md",
+			expected: "obsidian://open?vault=My%20Vault&file=Projects%2FClient%20X%2FMeeting%20%26%20notes.md",
+		},
+		{
+			name:           "advanced uri",
+			useAdvancedURI: true,
+			relPath:        "Projects/Client X/Meeting & notes.md",
+			expected:       "obsidian://advanced-uri?vault=My%20Vault&filepath=Projects%2FClient%20X%2FMeeting%20%26%20notes.md&line=42",
+		},
+		{
+			name:           "plus and question mark",
+			useAdvancedURI: true,
+			relPath:        "C++ / why?.md",
+			expected:       "obsidian://advanced-uri?vault=My%20Vault&filepath=C%2B%2B%20%2F%20why%3F.md&line=42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(provider.Config{URL: "/home/me/My Vault", Enabled: true, UseAdvancedURI: tt.useAdvancedURI})
+			if got := p.taskURL(tt.relPath, 42); got != tt.expected {
+				t.Errorf("Expected URL '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProvider_GetTasks_Line(t *testing.T) {
+	tempDir := t.TempDir()
+	content := "# Notes\n\nSome text\n- [ ] First task\n- [ ] Second task\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "note.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	p := NewProvider(provider.Config{URL: tempDir, Enabled: true, UseAdvancedURI: true})
+	tasks, err := p.GetTasks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %d", len(tasks))
+	}
+
+	for i, expected := range []int{4, 5} {
+		if tasks[i].Line != expected {
+			t.Errorf("Expected task %d on line %d, got %d", i, expected, tasks[i].Line)
+		}
+		if suffix := fmt.Sprintf("&filepath=note.md&line=%d", expected); !strings.HasSuffix(tasks[i].URL, suffix) {
+			t.Errorf("Expected URL ending with '%s', got '%s'", suffix, tasks[i].URL)
+		}
+	}
 }
 
 func TestProvider_parseTasksFromFile_EdgeCases(t *testing.T) {
@@ -642,3 +710,88 @@ func TestExtractTags(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeTaskTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"clean title", "Write the report", "Write the report"},
+		{"tabs and double spaces", "Write\tthe  report", "Write the report"},
+		{"non-breaking spaces", "Call\u00a0Alice back", "Call Alice back"},
+		{"zero-width characters", "\ufeffFix\u200b the\u2060 build", "Fix the build"},
+		{"markdown line break", "Review PR #42  ", "Review PR #42"},
+		{"backslash line break", "Review PR #42 \\", "Review PR #42"},
+		{"emoji sequence kept", "Pair with 👩\u200d💻 team", "Pair with 👩\u200d💻 team"},
+		{"only whitespace", " \t\u00a0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeTaskTitle(tt.input); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProvider_parseTasksFromFile_RawLine(t *testing.T) {
+	tempDir := t.TempDir()
+	lines := []string{
+		"# Pasted",
+		"- [ ] Call\u00a0Alice\t about  the #budget  ",
+		"\t- [/] Fix\u200b the build \\",
+	}
+	filePath := filepath.Join(tempDir, "pasted.md")
+	if err := os.WriteFile(filePath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("Failed to stat test file: %v", err)
+	}
+
+	p := NewProvider(provider.Config{URL: tempDir, Enabled: true})
+
+	tasks, err := p.parseTasksFromFile(filePath, fileInfo)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %d", len(tasks))
+	}
+
+	if tasks[0].Title != "Call Alice about the #budget" || tasks[1].Title != "Fix the build" {
+		t.Errorf("Expected normalized titles, got %q and %q", tasks[0].Title, tasks[1].Title)
+	}
+	if len(tasks[0].Tags) != 2 || tasks[0].Tags[0] != "budget" {
+		t.Errorf("Expected tags from the normalized title and the state, got %v", tasks[0].Tags)
+	}
+
+	// The raw line matches the file byte for byte, so the task can be completed in place
+	if tasks[0].RawLine != lines[1] || tasks[1].RawLine != lines[2] {
+		t.Fatalf("Expected the raw lines of the file, got %q and %q", tasks[0].RawLine, tasks[1].RawLine)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	completed := strings.Replace(tasks[0].RawLine, "[ ]", "[x]", 1)
+	updated := strings.Replace(string(content), tasks[0].RawLine, completed, 1)
+	if updated == string(content) {
+		t.Fatal("Expected the raw line to be found in the file")
+	}
+	if err := os.WriteFile(filePath, []byte(updated), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+
+	tasks, err = p.parseTasksFromFile(filePath, fileInfo)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "Fix the build" {
+		t.Errorf("Expected only the other task to be left, got %+v", tasks)
+	}
+}
@@ -3,9 +3,11 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"daily/internal/activity"
+	"daily/internal/verboselog"
 )
 
 // Provider defines the interface that all activity providers must implement
@@ -18,6 +20,72 @@ type Provider interface {
 
 	// IsConfigured returns true if the provider is properly configured
 	IsConfigured() bool
+
+	// ConfigSpec describes the configuration fields the provider reads
+	ConfigSpec() []ConfigField
+}
+
+// SkipReporter is optionally implemented by providers that leave some of their sources out,
+// e.g. the excluded folders of a vault
+type SkipReporter interface {
+	// Skipped describes what the last query left out, "" when nothing was skipped
+	Skipped() string
+}
+
+// WarningReporter is optionally implemented by providers whose queries go on when part of
+// them fails, e.g. the resolved issues of JIRA
+type WarningReporter interface {
+	// Warnings describes the parts of the last query that failed, none when all succeeded
+	Warnings() []string
+}
+
+// ProgressObserver is told when the aggregator queries its providers, e.g. the metrics
+// recorder streaming the progress of the run
+type ProgressObserver interface {
+	ProviderStarted(provider string)
+	// ProviderFinished is called with the number of activities kept, or the error of the provider
+	ProviderFinished(provider string, count int, err error)
+}
+
+// ConfigField describes a single provider configuration field
+type ConfigField struct {
+	Name        string `json:"name"` // JSON key in the provider's config section
+	Required    bool   `json:"required"`
+	Secret      bool   `json:"secret,omitempty"` // Credentials that must never be displayed
+	Description string `json:"description"`
+}
+
+// MentionSource is implemented by providers that can report where the user was mentioned
+type MentionSource interface {
+	Name() string
+	IsConfigured() bool
+
+	// GetMentions retrieves mentions of the user within the since range (e.g., "3d", "2w")
+	GetMentions(ctx context.Context, since string) ([]TodoItem, error)
+}
+
+// TodoItem represents a single item needing the user's attention, shared across providers
+type TodoItem struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	URL         string    `json:"url,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Tags        []string  `json:"tags,omitempty"`
+	Excerpt     string    `json:"excerpt,omitempty"` // Text around a mention, e.g. the sentence of a Confluence page, or of a saved message
+
+	Details    map[string]string `json:"details,omitempty"`    // Structured facts by activity.Detail* key
+	Provenance *Provenance       `json:"provenance,omitempty"` // Why the item was listed
+}
+
+// Provenance records why an item was listed: where it was fetched from and with which query
+type Provenance struct {
+	Provider  string    `json:"provider"`
+	Query     string    `json:"query,omitempty"`  // Search query, JQL or CQL that returned the item
+	Team      string    `json:"team,omitempty"`   // Team (org/slug) whose review request listed the item
+	Filter    string    `json:"filter,omitempty"` // Configured filter included in the query
+	Source    string    `json:"source,omitempty"` // Where an item not returned by a query was found, e.g. a vault file
+	FetchedAt time.Time `json:"fetched_at"`
 }
 
 // Config holds common configuration for providers
@@ -26,14 +94,186 @@ type Config struct {
 	Username string `json:"username,omitempty"`
 	Email    string `json:"email,omitempty"`
 	Token    string `json:"token,omitempty"`
+	TokenCmd string `json:"token_cmd,omitempty"` // Command printing the token, e.g. "pass show github/daily-pat", overriding Token
 	URL      string `json:"url,omitempty"`
 	Enabled  bool   `json:"enabled"`
 	Filter   string `json:"filter,omitempty"` // Additional filter string for customizing queries
+
+	// IncludeTypes limits the activity types shown in summaries (e.g., ["task"]); all types when empty
+	IncludeTypes []string `json:"include_types,omitempty"`
+
+	// GitHub-specific settings
+	StaleAfterDays int      `json:"stale_after_days,omitempty"` // Days before a pending review is highlighted as stale (default 3)
+	Repos          []string `json:"repos,omitempty"`            // Repositories (owner/repo) always checked for workflow runs
+	ReviewTeams    []string `json:"review_teams,omitempty"`     // Teams (org/slug) searched for review requests, instead of every team of the user
+	ExcludeTeams   []string `json:"exclude_teams,omitempty"`    // Teams (org/slug) never searched for review requests
+
+	// JIRA-specific settings
+	SummaryFilter      string   `json:"summary_filter,omitempty"`      // JQL filter for summaries, overriding Filter
+	TodoFilter         string   `json:"todo_filter,omitempty"`         // JQL filter for todos and mentions, overriding Filter
+	AuthType           string   `json:"auth_type,omitempty"`           // "basic" (email + API token, default) or "bearer" (Personal Access Token), also for Confluence
+	ServerMode         bool     `json:"server_mode,omitempty"`         // Use the v2 REST API of Jira Server / Data Center, or Confluence Data Center URLs
+	MaxResults         int      `json:"max_results,omitempty"`         // Maximum number of issues, or Confluence results, fetched per search (default 200), or GitLab items per list, or Slack messages and saved items (default 500)
+	IncludeTransitions bool     `json:"include_transitions,omitempty"` // Include status transitions made by the current user
+	IncludeComments    bool     `json:"include_comments,omitempty"`    // Include comments written by the current user
+	IncludeWatched     bool     `json:"include_watched,omitempty"`     // List recently updated issues, or Confluence pages, the current user watches in todos
+	IncludeReported    bool     `json:"include_reported,omitempty"`    // List recently updated issues the current user reported in todos
+	ExcludedStatuses   []string `json:"excluded_statuses,omitempty"`   // Statuses of tickets left out of todos, instead of the Done status category
+	RollupSubtasks     bool     `json:"rollup_subtasks,omitempty"`     // Group assigned subtasks under their parent issue in todos
+	SprintField        string   `json:"sprint_field,omitempty"`        // Custom field holding the sprint (default customfield_10020)
+	EpicLinkField      string   `json:"epic_link_field,omitempty"`     // Custom field linking issues to their epic on older instances (default customfield_10014)
+	Timezone           string   `json:"timezone,omitempty"`            // IANA timezone used for JIRA dates, e.g. "Europe/Paris" (default local)
+
+	// StatusSections maps status names or status category keys (new, indeterminate, done)
+	// to the todo sections assigned tickets are grouped under, e.g. {"Code Review": "In Review"}
+	StatusSections map[string]string `json:"status_sections,omitempty"`
+
+	// Obsidian-specific settings
+	ExcludePaths  []string `json:"exclude_paths,omitempty"`  // Glob patterns of vault paths never scanned, relative to the vault root
+	PendingStates []string `json:"pending_states,omitempty"` // Checkbox states of tasks listed as todos, e.g. [" ", "/"] (default)
+	IgnoredStates []string `json:"ignored_states,omitempty"` // Checkbox states of tasks never listed, even when pending (default every state not pending)
+	ScanWorkers   int      `json:"scan_workers,omitempty"`   // Notes parsed at the same time (default the number of CPUs)
+	// MaxLineLength is the longest line of a note read, in bytes, longer lines are skipped
+	// (default 1 MiB)
+	MaxLineLength int `json:"max_line_length,omitempty"`
+	// UseAdvancedURI links tasks to their line with the Advanced URI plugin instead of opening their note
+	UseAdvancedURI bool `json:"use_advanced_uri,omitempty"`
+	// UseGit reports the notes committed in the range of a git vault, dated by their last
+	// commit, instead of the notes modified in it
+	UseGit bool `json:"use_git,omitempty"`
+	// FollowSymlinks walks the folders the symlinks of the vault point to, each folder once
+	FollowSymlinks bool `json:"follow_symlinks,omitempty"`
+
+	// DailyNoteFormat is the Go layout of daily note names, e.g. "2006-01-02" (default), and
+	// DailyNotesFolder the vault folder holding them (default anywhere in the vault)
+	DailyNoteFormat  string `json:"daily_note_format,omitempty"`
+	DailyNotesFolder string `json:"daily_notes_folder,omitempty"`
+	// MeetingRules detect the meeting notes reported as meetings instead of note edits
+	// (default DefaultMeetingRules), an empty object detects none
+	MeetingRules *MeetingRules `json:"meeting_rules,omitempty"`
+
+	// Confluence-specific settings
+	// TodoSince is the time range of the mentions and page comments listed by todo when
+	// --since isn't given, e.g. "1w" (default 2w)
+	TodoSince string `json:"todo_since,omitempty"`
+	// Spaces are the keys of the spaces searched, e.g. ["ENG", "PLAT"] (default every space)
+	Spaces []string `json:"spaces,omitempty"`
+	// ContextPath is the path Confluence is served under on its site, e.g. "/confluence"
+	// (default /wiki on Confluence Cloud, none with server_mode)
+	ContextPath string `json:"context_path,omitempty"`
+
+	// GitLab-specific settings
+	// Groups are the groups, or group/subgroup paths, whose projects are reported, e.g.
+	// ["acme/platform"] (default every project)
+	Groups []string `json:"groups,omitempty"`
+
+	// Google Calendar-specific settings
+	Calendars         []string `json:"calendars,omitempty"`           // Calendar IDs read, e.g. ["primary", "team@group.calendar.google.com"] (default primary)
+	ServiceAccountKey string   `json:"service_account_key,omitempty"` // Path to the JSON key of a service account, instead of signing in with the device flow
+	ClientID          string   `json:"client_id,omitempty"`           // OAuth client ID used to sign in with the device flow
+	ClientSecret      string   `json:"client_secret,omitempty"`       // OAuth client secret used to sign in with the device flow
+	IncludeDeclined   bool     `json:"include_declined,omitempty"`    // Report the events the user declined
+	IncludeAllDay     bool     `json:"include_all_day,omitempty"`     // Report all-day events, without duration
+
+	// Slack-specific settings
+	Channels        []string `json:"channels,omitempty"`         // Channel names or IDs reported, e.g. ["platform", "C024BE91L"] (default every channel)
+	ExcludeChannels []string `json:"exclude_channels,omitempty"` // Channel names or IDs never reported, even when in Channels
+
+	// Saved query-specific settings
+	Queries []SavedQuery `json:"queries,omitempty"` // Endpoints whose counts are watched for changes
+}
+
+// SavedQuery is a named HTTP GET endpoint returning JSON with a count to watch,
+// e.g. the number of matches of a saved code search
+type SavedQuery struct {
+	Name      string            `json:"name"`
+	URL       string            `json:"url"`
+	CountPath string            `json:"count_path,omitempty"` // gjson-style path to the count, e.g. "data.search.results.matchCount"
+	Headers   map[string]string `json:"headers,omitempty"`    // Request headers, e.g. {"Authorization": "token ..."}
+	Link      string            `json:"link,omitempty"`       // Page linked from the activity, e.g. the search in a browser
+}
+
+// MeetingRules tell meeting notes from other notes: a note is a meeting when it is in one of
+// the folders, its type property is one of the types, or its name matches one of the titles.
+// Each heading matching one of the titles in other notes, e.g. daily notes, is a meeting.
+type MeetingRules struct {
+	Folders []string `json:"folders,omitempty"` // Glob patterns of vault folders, at any depth unless they have a slash, e.g. "Meetings"
+	Titles  []string `json:"titles,omitempty"`  // Regular expressions matching note names or headings, e.g. "^1:1 with"
+	Types   []string `json:"types,omitempty"`   // Values of the type frontmatter property, e.g. "meeting"
+}
+
+// DefaultMeetingRules are the meeting rules used when none are configured
+var DefaultMeetingRules = MeetingRules{
+	Folders: []string{"Meetings"},
+	Titles:  []string{`(?i)^(1:1|1-1|1-on-1|one-on-one)\b`, `(?i)\bmeeting\b`},
+	Types:   []string{"meeting"},
+}
+
+// IncludedTypes returns the activity types listed in IncludeTypes, failing on unknown names
+func (c Config) IncludedTypes() ([]activity.ActivityType, error) {
+	types := make([]activity.ActivityType, 0, len(c.IncludeTypes))
+	for _, name := range c.IncludeTypes {
+		t, err := activity.ParseActivityType(name)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+// IncludesType reports whether activities of the given type should be collected.
+// Providers use it to skip work for excluded types.
+func (c Config) IncludesType(t activity.ActivityType) bool {
+	if len(c.IncludeTypes) == 0 {
+		return true
+	}
+	for _, name := range c.IncludeTypes {
+		if included, err := activity.ParseActivityType(name); err == nil && included == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTaskState returns the character of an Obsidian checkbox state, written either as
+// the character ("/") or as the checkbox ("[/]")
+func ParseTaskState(s string) (string, error) {
+	state := s
+	if len(s) > 2 && strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		state = s[1 : len(s)-1]
+	}
+	if len([]rune(state)) != 1 {
+		return "", fmt.Errorf("invalid checkbox state %q (expected a single character, e.g. \"/\" or \"[/]\")", s)
+	}
+	if strings.TrimSpace(state) == "" {
+		return " ", nil
+	}
+	return state, nil
+}
+
+// ValidateSpaceKeys checks the Confluence space keys of Spaces. Keys are quoted in CQL, so
+// keys holding quotes or backslashes are rejected rather than escaped.
+func ValidateSpaceKeys(keys []string) error {
+	for _, key := range keys {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("empty space key")
+		}
+		if strings.ContainsAny(key, `"'\`) {
+			return fmt.Errorf("invalid space key %q: quotes and backslashes aren't allowed", key)
+		}
+	}
+	return nil
 }
 
 // Aggregator collects activities from multiple providers
 type Aggregator struct {
 	providers []Provider
+
+	// includeTypes holds the activity types kept for each provider, by provider name
+	includeTypes map[string]map[activity.ActivityType]bool
+
+	progress ProgressObserver
 }
 
 // NewAggregator creates a new activity aggregator
@@ -43,113 +283,136 @@ func NewAggregator(providers ...Provider) *Aggregator {
 	}
 }
 
-// AddProvider adds a provider to the aggregator
-func (a *Aggregator) AddProvider(provider Provider) {
+// AddProvider adds a provider to the aggregator. When includeTypes are given, only
+// activities of those types are kept from this provider.
+func (a *Aggregator) AddProvider(provider Provider, includeTypes ...activity.ActivityType) {
 	a.providers = append(a.providers, provider)
-}
 
-// GetSummary retrieves activities from all configured providers for the given date
-func (a *Aggregator) GetSummary(ctx context.Context, date time.Time) (*activity.Summary, error) {
-	// Get activities for the full day
-	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	to := from.Add(24 * time.Hour)
-
-	var allActivities []activity.Activity
-
-	for _, provider := range a.providers {
-		if !provider.IsConfigured() {
-			continue
+	if len(includeTypes) > 0 {
+		if a.includeTypes == nil {
+			a.includeTypes = make(map[string]map[activity.ActivityType]bool)
 		}
-
-		activities, err := provider.GetActivities(ctx, from, to)
-		if err != nil {
-			// Continue with other providers but could add logging here
-			continue
+		types := make(map[activity.ActivityType]bool, len(includeTypes))
+		for _, t := range includeTypes {
+			types[t] = true
 		}
-
-		allActivities = append(allActivities, activities...)
+		a.includeTypes[provider.Name()] = types
 	}
+}
 
-	return &activity.Summary{
-		Date:       date,
-		Activities: allActivities,
-	}, nil
+// SetProgress sets the observer told when each provider is queried
+func (a *Aggregator) SetProgress(observer ProgressObserver) {
+	a.progress = observer
 }
 
-// GetSummaryByTimeRange retrieves activities from all configured providers for a time range
-func (a *Aggregator) GetSummaryByTimeRange(ctx context.Context, from, to time.Time, verbose bool) (*activity.Summary, error) {
-	var allActivities []activity.Activity
+// queryProvider gets the activities of a provider kept by the aggregator, telling the
+// progress observer when set
+func (a *Aggregator) queryProvider(ctx context.Context, provider Provider, from, to time.Time) ([]activity.Activity, error) {
+	if a.progress != nil {
+		a.progress.ProviderStarted(provider.Name())
+	}
 
-	for _, provider := range a.providers {
-		if !provider.IsConfigured() {
-			if verbose {
-				fmt.Printf("⚠️  %s provider not configured, skipping\n", provider.Name())
-			}
-			continue
-		}
+	activities, err := provider.GetActivities(ctx, from, to)
+	if err == nil {
+		activities = a.filterActivities(provider, activities)
+	}
 
-		if verbose {
-			fmt.Printf("🔍 Querying %s provider...\n", provider.Name())
-		}
+	if a.progress != nil {
+		a.progress.ProviderFinished(provider.Name(), len(activities), err)
+	}
+	return activities, err
+}
 
-		activities, err := provider.GetActivities(ctx, from, to)
-		if err != nil {
-			if verbose {
-				fmt.Printf("❌ %s provider failed: %v\n", provider.Name(), err)
-			}
-			continue
-		}
+// filterActivities drops the activities whose type is excluded for the provider
+func (a *Aggregator) filterActivities(provider Provider, activities []activity.Activity) []activity.Activity {
+	types, ok := a.includeTypes[provider.Name()]
+	if !ok {
+		return activities
+	}
 
-		if verbose {
-			fmt.Printf("✅ %s provider returned %d activities\n", provider.Name(), len(activities))
+	filtered := make([]activity.Activity, 0, len(activities))
+	for _, act := range activities {
+		if types[act.Type] {
+			filtered = append(filtered, act)
 		}
-
-		allActivities = append(allActivities, activities...)
 	}
+	return filtered
+}
 
+// GetSummary retrieves activities from all configured providers for the given date
+func (a *Aggregator) GetSummary(ctx context.Context, date time.Time) (*activity.Summary, error) {
+	return a.GetSummaryWithVerbose(ctx, date, nil)
+}
+
+// GetSummaryByTimeRange retrieves activities from all configured providers for a time range,
+// reporting the providers queried to reporter, nil for no report
+func (a *Aggregator) GetSummaryByTimeRange(ctx context.Context, from, to time.Time, reporter *verboselog.Reporter) (*activity.Summary, error) {
 	return &activity.Summary{
 		Date:       from, // Use the start of the range as the summary date
-		Activities: allActivities,
+		Activities: a.collect(ctx, from, to, reporter),
 	}, nil
 }
 
-// GetSummaryWithVerbose is like GetSummary but with verbose logging
-func (a *Aggregator) GetSummaryWithVerbose(ctx context.Context, date time.Time, verbose bool) (*activity.Summary, error) {
+// GetSummaryWithVerbose is like GetSummary, reporting the providers queried to reporter
+func (a *Aggregator) GetSummaryWithVerbose(ctx context.Context, date time.Time, reporter *verboselog.Reporter) (*activity.Summary, error) {
 	// Get activities for the full day
 	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	to := from.Add(24 * time.Hour)
 
+	return &activity.Summary{
+		Date:       date,
+		Activities: a.collect(ctx, from, to, reporter),
+	}, nil
+}
+
+// collect queries the configured providers for the activities of a time range. Providers that
+// fail are skipped.
+func (a *Aggregator) collect(ctx context.Context, from, to time.Time, reporter *verboselog.Reporter) []activity.Activity {
 	var allActivities []activity.Activity
+	seen := make(map[string]bool)
 
 	for _, provider := range a.providers {
 		if !provider.IsConfigured() {
-			if verbose {
-				fmt.Printf("⚠️  %s provider not configured, skipping\n", provider.Name())
-			}
+			reporter.ProviderSkipped(provider.Name(), "not configured")
 			continue
 		}
 
-		if verbose {
-			fmt.Printf("🔍 Querying %s provider...\n", provider.Name())
-		}
-
-		activities, err := provider.GetActivities(ctx, from, to)
+		reporter.ProviderEnabled(provider.Name())
+		start := time.Now()
+		activities, err := a.queryProvider(ctx, provider, from, to)
+		reporter.ProviderResult(provider.Name(), len(activities), time.Since(start), err)
 		if err != nil {
-			if verbose {
-				fmt.Printf("❌ %s provider failed: %v\n", provider.Name(), err)
-			}
 			continue
 		}
-
-		if verbose {
-			fmt.Printf("✅ %s provider returned %d activities\n", provider.Name(), len(activities))
+		if skipReporter, ok := provider.(SkipReporter); ok {
+			if skipped := skipReporter.Skipped(); skipped != "" {
+				reporter.Detail("Skipped %s", skipped)
+			}
+		}
+		if warningReporter, ok := provider.(WarningReporter); ok {
+			for _, warning := range warningReporter.Warnings() {
+				reporter.Warn("%s", warning)
+			}
 		}
 
-		allActivities = append(allActivities, activities...)
+		allActivities = appendUnique(allActivities, seen, activities)
 	}
 
-	return &activity.Summary{
-		Date:       date,
-		Activities: allActivities,
-	}, nil
+	return allActivities
+}
+
+// appendUnique appends the activities of a provider to all, except the ones whose ID was
+// returned by a provider queried before. It is a safety net for the activities two providers
+// would both report, which would otherwise be listed twice.
+func appendUnique(all []activity.Activity, seen map[string]bool, activities []activity.Activity) []activity.Activity {
+	for _, act := range activities {
+		if act.ID == "" || !seen[act.ID] {
+			all = append(all, act)
+		}
+	}
+	// Activities of a single provider sharing an ID are kept, only repeats across providers are dropped
+	for _, act := range activities {
+		seen[act.ID] = true
+	}
+	return all
 }
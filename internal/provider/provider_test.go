@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/verboselog"
+)
+
+type fakeProvider struct {
+	name       string
+	activities []activity.Activity
+	err        error
+}
+
+func (f *fakeProvider) Name() string { return f.name }
+
+func (f *fakeProvider) IsConfigured() bool { return true }
+
+func (f *fakeProvider) ConfigSpec() []ConfigField { return nil }
+
+func (f *fakeProvider) GetActivities(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
+	return f.activities, f.err
+}
+
+func TestAggregator_IncludeTypes(t *testing.T) {
+	now := time.Now()
+	github := &fakeProvider{name: "github", activities: []activity.Activity{
+		{ID: "1", Type: activity.ActivityTypeCommit, Timestamp: now},
+		{ID: "2", Type: activity.ActivityTypePR, Timestamp: now},
+		{ID: "3", Type: activity.ActivityTypeIssue, Timestamp: now},
+	}}
+	obsidian := &fakeProvider{name: "obsidian", activities: []activity.Activity{
+		{ID: "4", Type: activity.ActivityTypeNote, Timestamp: now},
+		{ID: "5", Type: activity.ActivityTypeTask, Timestamp: now},
+	}}
+
+	aggregator := NewAggregator()
+	aggregator.AddProvider(github, activity.ActivityTypePR, activity.ActivityTypeCommit)
+	aggregator.AddProvider(obsidian)
+
+	summary, err := aggregator.GetSummaryByTimeRange(context.Background(), now.Add(-time.Hour), now, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	ids := make(map[string]bool)
+	for _, act := range summary.Activities {
+		ids[act.ID] = true
+	}
+
+	for _, id := range []string{"1", "2", "4", "5"} {
+		if !ids[id] {
+			t.Errorf("Expected activity %s to be kept", id)
+		}
+	}
+	if ids["3"] {
+		t.Error("Expected excluded issue activity to be filtered out")
+	}
+}
+
+// recordingObserver records the progress of the aggregator as strings
+type recordingObserver struct {
+	events []string
+}
+
+func (r *recordingObserver) ProviderStarted(provider string) {
+	r.events = append(r.events, "started "+provider)
+}
+
+func (r *recordingObserver) ProviderFinished(provider string, count int, err error) {
+	if err != nil {
+		r.events = append(r.events, fmt.Sprintf("failed %s: %v", provider, err))
+		return
+	}
+	r.events = append(r.events, fmt.Sprintf("finished %s: %d", provider, count))
+}
+
+func TestAggregator_Progress(t *testing.T) {
+	now := time.Now()
+	github := &fakeProvider{name: "github", activities: []activity.Activity{
+		{ID: "1", Type: activity.ActivityTypeCommit, Timestamp: now},
+		{ID: "2", Type: activity.ActivityTypeIssue, Timestamp: now},
+	}}
+	jira := &fakeProvider{name: "jira", err: errors.New("unauthorized")}
+
+	observer := &recordingObserver{}
+	aggregator := NewAggregator()
+	aggregator.AddProvider(github, activity.ActivityTypeCommit)
+	aggregator.AddProvider(jira)
+	aggregator.SetProgress(observer)
+
+	if _, err := aggregator.GetSummaryByTimeRange(context.Background(), now.Add(-time.Hour), now, nil); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The count is of the activities kept after filtering by type
+	expected := []string{"started github", "finished github: 1", "started jira", "failed jira: unauthorized"}
+	if !reflect.DeepEqual(observer.events, expected) {
+		t.Errorf("Expected %v, got %v", expected, observer.events)
+	}
+}
+
+func TestAggregator_Verbose(t *testing.T) {
+	now := time.Now()
+	github := &fakeProvider{name: "github", activities: []activity.Activity{{ID: "1", Type: activity.ActivityTypeCommit, Timestamp: now}}}
+	jira := &fakeProvider{name: "jira", err: errors.New("unauthorized")}
+
+	var out bytes.Buffer
+	aggregator := NewAggregator(github, jira)
+	if _, err := aggregator.GetSummaryWithVerbose(context.Background(), now, verboselog.New(&out, true)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected a querying and a result line per provider, got:\n%s", out.String())
+	}
+	if lines[0] != "→ Querying GitHub" || !strings.HasPrefix(lines[1], "✓ GitHub: 1 found in ") {
+		t.Errorf("Expected GitHub to be queried and return 1 activity, got:\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[3], "✗ JIRA failed after ") || !strings.HasSuffix(lines[3], ": unauthorized") {
+		t.Errorf("Expected the JIRA error, got %q", lines[3])
+	}
+}
+
+// warningProvider is a fake provider whose queries partly fail
+type warningProvider struct {
+	fakeProvider
+	warnings []string
+}
+
+func (w *warningProvider) Warnings() []string { return w.warnings }
+
+func TestAggregator_Warnings(t *testing.T) {
+	now := time.Now()
+	jira := &warningProvider{
+		fakeProvider: fakeProvider{name: "jira", activities: []activity.Activity{{ID: "1", Type: activity.ActivityTypeIssue, Timestamp: now}}},
+		warnings:     []string{"Failed to fetch resolved issues: bad request"},
+	}
+
+	var out bytes.Buffer
+	summary, err := NewAggregator(jira).GetSummaryWithVerbose(context.Background(), now, verboselog.New(&out, true))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(summary.Activities) != 1 {
+		t.Errorf("Expected the activities of the parts that succeeded, got %+v", summary.Activities)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || lines[2] != "  ⚠ Failed to fetch resolved issues: bad request" {
+		t.Errorf("Expected the warning under the result of JIRA, got:\n%s", out.String())
+	}
+}
+
+func TestAggregator_DeduplicatesAcrossProviders(t *testing.T) {
+	now := time.Now()
+	// The same account configured twice returns the same activities
+	work := &fakeProvider{name: "github", activities: []activity.Activity{
+		{ID: "github-commit-1", Type: activity.ActivityTypeCommit, Timestamp: now},
+		{ID: "github-pr-2", Type: activity.ActivityTypePR, Timestamp: now},
+	}}
+	duplicate := &fakeProvider{name: "github", activities: []activity.Activity{
+		{ID: "github-commit-1", Type: activity.ActivityTypeCommit, Timestamp: now},
+		{ID: "github-commit-3", Type: activity.ActivityTypeCommit, Timestamp: now},
+	}}
+	// Activities of one provider sharing an ID are kept
+	obsidian := &fakeProvider{name: "obsidian", activities: []activity.Activity{
+		{ID: "obsidian-note.md", Type: activity.ActivityTypeNote, Timestamp: now},
+		{ID: "obsidian-note.md", Type: activity.ActivityTypeTask, Timestamp: now},
+	}}
+
+	aggregator := NewAggregator(work, duplicate, obsidian)
+	summary, err := aggregator.GetSummaryByTimeRange(context.Background(), now.Add(-time.Hour), now, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var ids []string
+	for _, act := range summary.Activities {
+		ids = append(ids, act.ID)
+	}
+	expected := []string{"github-commit-1", "github-pr-2", "github-commit-3", "obsidian-note.md", "obsidian-note.md"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+}
+
+func TestConfig_IncludesType(t *testing.T) {
+	tests := []struct {
+		name         string
+		includeTypes []string
+		activityType activity.ActivityType
+		expected     bool
+	}{
+		{"empty includes everything", nil, activity.ActivityTypeNote, true},
+		{"listed type", []string{"task"}, activity.ActivityTypeTask, true},
+		{"unlisted type", []string{"task"}, activity.ActivityTypeNote, false},
+		{"alias", []string{"pr"}, activity.ActivityTypePR, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{IncludeTypes: tt.includeTypes}
+			if got := config.IncludesType(tt.activityType); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseTaskState(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    string
+		expectError bool
+	}{
+		{input: "/", expected: "/"},
+		{input: "[>]", expected: ">"},
+		{input: " ", expected: " "},
+		{input: "[ ]", expected: " "},
+		{input: "\t", expected: " "},
+		{input: "", expectError: true},
+		{input: "[]", expectError: true},
+		{input: "done", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			state, err := ParseTaskState(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got %q", state)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if state != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, state)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package provider
+
+import "context"
+
+// Review request types
+const (
+	ReviewRequestUser = "user" // The user was asked to review directly
+	ReviewRequestTeam = "team" // One of the user's teams was asked to review
+)
+
+// ReviewSource is implemented by providers that can list the changes awaiting the user's review
+type ReviewSource interface {
+	Name() string
+	IsConfigured() bool
+
+	// GetReviewRequests retrieves the open changes the user or their teams were asked to review
+	GetReviewRequests(ctx context.Context) ([]ReviewItem, error)
+}
+
+// ReviewEnricher is optionally implemented by review sources that can add CI status
+// and change details to a review item, at the cost of extra requests per item
+type ReviewEnricher interface {
+	// EnrichReview returns the item with its details filled in. On error the returned
+	// item may be partially enriched.
+	EnrichReview(ctx context.Context, item ReviewItem) (ReviewItem, error)
+}
+
+// ReviewItem represents a change awaiting review, shared across platforms
+type ReviewItem struct {
+	TodoItem
+	Platform    string `json:"platform"`     // Provider name, e.g. "github"
+	RequestType string `json:"request_type"` // ReviewRequestUser or ReviewRequestTeam
+	Repository  string `json:"repository,omitempty"`
+	Number      int    `json:"number,omitempty"`
+	Milestone   string `json:"milestone,omitempty"`
+
+	// Details filled in by EnrichReview
+	CIStatus  CIStatus  `json:"ci_status"`
+	PRDetails PRDetails `json:"pr_details"`
+	Base      string    `json:"base,omitempty"` // Target branch, e.g. release/1.2
+
+	InMergeQueue bool `json:"in_merge_queue,omitempty"` // Queued to be merged, no longer needing a review
+}
+
+// CIStatus represents the CI check status of a change
+type CIStatus struct {
+	State      string     `json:"state"` // success, failure, pending
+	TotalCount int        `json:"total_count"`
+	Checks     []CheckRun `json:"checks"`
+}
+
+// CheckRun represents a single CI check
+type CheckRun struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`     // completed, in_progress, queued
+	Conclusion string `json:"conclusion"` // success, failure, cancelled, etc.
+	URL        string `json:"url,omitempty"`
+}
+
+// PRDetails represents the size of a change
+type PRDetails struct {
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changed_files"`
+}
@@ -0,0 +1,99 @@
+package savedquery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExtractCount returns the number found at a gjson-style path in a JSON document.
+// The path is a dot-separated list of object keys and array indexes (e.g. "data.items.0.total"),
+// where "#" takes the length of an array (e.g. "results.#") and "\." escapes a dot in a key.
+// An empty path reads the whole document. Numeric strings are accepted as counts.
+func ExtractCount(data []byte, path string) (float64, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return 0, fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	traversed := ""
+	for _, segment := range splitPath(path) {
+		switch current := value.(type) {
+		case map[string]any:
+			next, ok := current[segment]
+			if !ok {
+				return 0, fmt.Errorf("no key %q at %s", segment, describePath(traversed))
+			}
+			value = next
+		case []any:
+			if segment == "#" {
+				value = json.Number(strconv.Itoa(len(current)))
+				break
+			}
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(current) {
+				return 0, fmt.Errorf("invalid array index %q at %s (length %d)", segment, describePath(traversed), len(current))
+			}
+			value = current[index]
+		default:
+			return 0, fmt.Errorf("cannot read %q: %s is not an object or array", segment, describePath(traversed))
+		}
+		traversed = joinPath(traversed, segment)
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		return v.Float64()
+	case string:
+		count, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("value at %s is not a number: %q", describePath(traversed), v)
+		}
+		return count, nil
+	default:
+		return 0, fmt.Errorf("value at %s is not a number", describePath(traversed))
+	}
+}
+
+// splitPath splits a path on unescaped dots
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	var segments []string
+	var current strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path):
+			i++
+			current.WriteByte(path[i])
+		case path[i] == '.':
+			segments = append(segments, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(path[i])
+		}
+	}
+	return append(segments, current.String())
+}
+
+func joinPath(path, segment string) string {
+	segment = strings.ReplaceAll(segment, ".", `\.`)
+	if path == "" {
+		return segment
+	}
+	return path + "." + segment
+}
+
+func describePath(path string) string {
+	if path == "" {
+		return "the document root"
+	}
+	return fmt.Sprintf("%q", path)
+}
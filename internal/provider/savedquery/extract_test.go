@@ -0,0 +1,74 @@
+package savedquery
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractCount(t *testing.T) {
+	const doc = `{
+		"data": {"search": {"results": {"matchCount": 42, "approximate": "17"}}},
+		"hits": {"total": {"value": 1234}},
+		"items": [{"total": 3}, {"total": 5}],
+		"meta.total": 7
+	}`
+
+	tests := []struct {
+		name     string
+		data     string
+		path     string
+		expected float64
+	}{
+		{"nested number", doc, "data.search.results.matchCount", 42},
+		{"numeric string", doc, "data.search.results.approximate", 17},
+		{"OpenSearch total", doc, "hits.total.value", 1234},
+		{"array index", doc, "items.1.total", 5},
+		{"array length", doc, "items.#", 2},
+		{"escaped dot", doc, `meta\.total`, 7},
+		{"bare number", `12.5`, "", 12.5},
+		{"root array length", `[1, 2, 3]`, "#", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := ExtractCount([]byte(tt.data), tt.path)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, count)
+			}
+		})
+	}
+}
+
+func TestExtractCount_Errors(t *testing.T) {
+	const doc = `{"data": {"items": [1, 2], "name": "search", "nested": {"a": 1}}}`
+
+	tests := []struct {
+		name     string
+		data     string
+		path     string
+		expected string
+	}{
+		{"invalid JSON", `{`, "data", "invalid JSON response"},
+		{"missing key", doc, "data.count", `no key "count" at "data"`},
+		{"index out of range", doc, "data.items.5", `invalid array index "5" at "data.items"`},
+		{"not a container", doc, "data.name.length", `"data.name" is not an object or array`},
+		{"not a number", doc, "data.name", `value at "data.name" is not a number: "search"`},
+		{"object value", doc, "data.nested", `value at "data.nested" is not a number`},
+		{"missing key at root", doc, "total", "no key \"total\" at the document root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ExtractCount([]byte(tt.data), tt.path)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error to contain '%s', got: %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package savedquery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/metrics"
+	"daily/internal/provider"
+)
+
+// Provider watches the counts returned by saved queries, such as code searches for a
+// deprecated API, and reports when they change
+type Provider struct {
+	config provider.Config
+	client *http.Client
+	store  stateStore
+	now    func() time.Time
+}
+
+func NewProvider(config provider.Config) *Provider {
+	// A missing home directory surfaces as a state error when the queries are checked
+	statePath, _ := defaultStatePath()
+
+	return &Provider{
+		config: config,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: metrics.NewTransport("saved_queries"),
+		},
+		store: stateStore{path: statePath},
+		now:   time.Now,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "saved_queries"
+}
+
+func (p *Provider) IsConfigured() bool {
+	return p.config.Enabled && len(p.config.Queries) > 0
+}
+
+// ConfigSpec describes the configuration fields of the saved query provider
+func (p *Provider) ConfigSpec() []provider.ConfigField {
+	return []provider.ConfigField{
+		{Name: "queries", Required: true, Description: "Named HTTP GET endpoints returning JSON, with the path to the count to watch"},
+	}
+}
+
+// GetActivities checks every saved query and reports the ones whose count changed in the time range.
+// Changes are dated when daily first sees them, so a query is only compared with its previous check.
+func (p *Provider) GetActivities(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("saved query provider not configured")
+	}
+
+	states, err := p.store.load()
+	if err != nil {
+		return nil, err
+	}
+
+	now := p.now()
+	var firstErr error
+	failed := 0
+	for _, query := range p.config.Queries {
+		count, err := p.fetchCount(ctx, query)
+		if err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = fmt.Errorf("saved query %q: %w", query.Name, err)
+			}
+			continue
+		}
+
+		state, seen := states[query.Name]
+		states[query.Name] = state.record(count, now, seen)
+	}
+
+	if err := p.store.save(states); err != nil {
+		return nil, err
+	}
+
+	// Only report an error when no query could be checked
+	if failed == len(p.config.Queries) {
+		return nil, firstErr
+	}
+
+	activities := make([]activity.Activity, 0)
+	for _, query := range p.config.Queries {
+		state, ok := states[query.Name]
+		if !ok || !state.changedWithin(from, to) {
+			continue
+		}
+		activities = append(activities, changeActivity(query, state))
+	}
+
+	return activities, nil
+}
+
+// fetchCount requests a saved query and extracts its count from the JSON response
+func (p *Provider) fetchCount(ctx context.Context, query provider.SavedQuery) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, query.URL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	for name, value := range query.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return ExtractCount(body, query.CountPath)
+}
+
+// changeActivity describes a count change, e.g. "Deprecated API usages: 42 → 38"
+func changeActivity(query provider.SavedQuery, state queryState) activity.Activity {
+	previous := *state.Previous
+
+	description := fmt.Sprintf("Increased by %s", formatCount(state.Count-previous))
+	if state.Count < previous {
+		description = fmt.Sprintf("Decreased by %s", formatCount(previous-state.Count))
+	}
+
+	return activity.Activity{
+		ID:          fmt.Sprintf("saved-query-%s-%d", query.Name, state.ChangedAt.Unix()),
+		Type:        activity.ActivityTypeSavedQuery,
+		Title:       fmt.Sprintf("%s: %s → %s", query.Name, formatCount(previous), formatCount(state.Count)),
+		Description: description,
+		URL:         query.Link,
+		Platform:    "saved_queries",
+		Timestamp:   state.ChangedAt,
+		Tags:        []string{query.Name, "saved-query"},
+	}
+}
+
+// formatCount renders a count without trailing zeros, e.g. "42" or "0.5"
+func formatCount(count float64) string {
+	return strconv.FormatFloat(count, 'f', -1, 64)
+}
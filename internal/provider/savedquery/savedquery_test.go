@@ -0,0 +1,173 @@
+package savedquery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+func newTestProvider(t *testing.T, queries []provider.SavedQuery, now *time.Time) *Provider {
+	t.Helper()
+	p := NewProvider(provider.Config{Enabled: true, Queries: queries})
+	p.store = stateStore{path: filepath.Join(t.TempDir(), "saved_queries.json")}
+	p.now = func() time.Time { return *now }
+	return p
+}
+
+func TestProvider_IsConfigured(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   provider.Config
+		expected bool
+	}{
+		{"enabled with queries", provider.Config{Enabled: true, Queries: []provider.SavedQuery{{Name: "q", URL: "http://x"}}}, true},
+		{"enabled without queries", provider.Config{Enabled: true}, false},
+		{"disabled", provider.Config{Queries: []provider.SavedQuery{{Name: "q", URL: "http://x"}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewProvider(tt.config).IsConfigured(); got != tt.expected {
+				t.Errorf("Expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProvider_GetActivities_CountChanges(t *testing.T) {
+	count := 42
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token sgp_secret" {
+			t.Errorf("Expected configured header, got '%s'", r.Header.Get("Authorization"))
+		}
+		_, _ = fmt.Fprintf(w, `{"data": {"search": {"results": {"matchCount": %d}}}}`, count)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	day := func() (time.Time, time.Time) {
+		from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		return from, from.Add(24 * time.Hour)
+	}
+
+	p := newTestProvider(t, []provider.SavedQuery{{
+		Name:      "Deprecated API usages",
+		URL:       server.URL,
+		CountPath: "data.search.results.matchCount",
+		Headers:   map[string]string{"Authorization": "token sgp_secret"},
+		Link:      "https://sourcegraph.example.com/search?q=oldAPI",
+	}}, &now)
+
+	// The first run records a baseline
+	from, to := day()
+	activities, err := p.GetActivities(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 0 {
+		t.Fatalf("Expected no activity for the baseline, got %+v", activities)
+	}
+
+	// The next day the count went down
+	now = now.Add(24 * time.Hour)
+	count = 38
+	from, to = day()
+	activities, err = p.GetActivities(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 1 {
+		t.Fatalf("Expected 1 activity, got %d", len(activities))
+	}
+
+	act := activities[0]
+	if act.Title != "Deprecated API usages: 42 → 38" {
+		t.Errorf("Expected title 'Deprecated API usages: 42 → 38', got '%s'", act.Title)
+	}
+	if act.Description != "Decreased by 4" {
+		t.Errorf("Expected description 'Decreased by 4', got '%s'", act.Description)
+	}
+	if act.Type != activity.ActivityTypeSavedQuery || act.Platform != "saved_queries" {
+		t.Errorf("Expected saved query activity, got type '%s' platform '%s'", act.Type, act.Platform)
+	}
+	if act.URL != "https://sourcegraph.example.com/search?q=oldAPI" {
+		t.Errorf("Expected link as URL, got '%s'", act.URL)
+	}
+	if !act.Timestamp.Equal(now) {
+		t.Errorf("Expected change to be dated %v, got %v", now, act.Timestamp)
+	}
+
+	// Running again the same day still reports the change
+	now = now.Add(time.Hour)
+	activities, err = p.GetActivities(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 1 || activities[0].Title != "Deprecated API usages: 42 → 38" {
+		t.Errorf("Expected the change to be reported again, got %+v", activities)
+	}
+
+	// The following day nothing changed
+	now = now.Add(24 * time.Hour)
+	from, to = day()
+	activities, err = p.GetActivities(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(activities) != 0 {
+		t.Errorf("Expected no activity without a change, got %+v", activities)
+	}
+}
+
+func TestProvider_GetActivities_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			_, _ = fmt.Fprint(w, `{"hits": {"total": {"value": 3}}}`)
+		case "/bad-path":
+			_, _ = fmt.Fprint(w, `{"hits": {}}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	from, to := now.Add(-time.Hour), now.Add(time.Hour)
+
+	// A failing query doesn't prevent the others from being checked
+	p := newTestProvider(t, []provider.SavedQuery{
+		{Name: "ok", URL: server.URL + "/ok", CountPath: "hits.total.value"},
+		{Name: "broken", URL: server.URL + "/fail", CountPath: "hits.total.value"},
+	}, &now)
+	if _, err := p.GetActivities(context.Background(), from, to); err != nil {
+		t.Errorf("Expected partial failure to be ignored, got: %v", err)
+	}
+	states, _ := p.store.load()
+	if states["ok"].Count != 3 {
+		t.Errorf("Expected working query to be recorded, got %+v", states)
+	}
+	if _, ok := states["broken"]; ok {
+		t.Error("Expected failing query not to be recorded")
+	}
+
+	// When every query fails the first error is returned
+	p = newTestProvider(t, []provider.SavedQuery{
+		{Name: "missing count", URL: server.URL + "/bad-path", CountPath: "hits.total.value"},
+	}, &now)
+	_, err := p.GetActivities(context.Background(), from, to)
+	if err == nil {
+		t.Fatal("Expected error when every query fails, got nil")
+	}
+	if !strings.Contains(err.Error(), `saved query "missing count"`) || !strings.Contains(err.Error(), `no key "total"`) {
+		t.Errorf("Expected error to name the query and the path problem, got: %v", err)
+	}
+}
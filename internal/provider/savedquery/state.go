@@ -0,0 +1,86 @@
+package savedquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"daily/internal/statedir"
+)
+
+// queryState is the last count seen for a saved query and when it last changed
+type queryState struct {
+	Count     float64   `json:"count"`
+	Previous  *float64  `json:"previous,omitempty"`   // Count before the last change; nil until the count changes
+	ChangedAt time.Time `json:"changed_at,omitempty"` // When the last change was seen
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// record returns the state after fetching count at now. A change keeps the previous count
+// so that it is still reported by later runs covering the same period.
+func (s queryState) record(count float64, now time.Time, seen bool) queryState {
+	if !seen {
+		return queryState{Count: count, CheckedAt: now}
+	}
+
+	if count != s.Count {
+		previous := s.Count
+		s.Previous = &previous
+		s.Count = count
+		s.ChangedAt = now
+	}
+	s.CheckedAt = now
+	return s
+}
+
+// changedWithin reports whether the count changed in the time range
+func (s queryState) changedWithin(from, to time.Time) bool {
+	return s.Previous != nil && !s.ChangedAt.Before(from) && !s.ChangedAt.After(to)
+}
+
+// stateStore persists the state of every saved query, by query name, in a JSON file
+type stateStore struct {
+	path string
+}
+
+// defaultStatePath returns the file of the state directory holding the saved query counts
+func defaultStatePath() (string, error) {
+	return statedir.Path("saved_queries.json")
+}
+
+// load returns the stored states, or an empty map when nothing was stored yet
+func (s stateStore) load() (map[string]queryState, error) {
+	states := make(map[string]queryState)
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved query state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("failed to parse saved query state: %w", err)
+	}
+	return states, nil
+}
+
+// save writes the states, creating the cache directory if needed
+func (s stateStore) save(states map[string]queryState) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved query state: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write saved query state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package savedquery
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQueryState_Record(t *testing.T) {
+	day1 := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	day3 := day2.Add(24 * time.Hour)
+
+	// The first check only records a baseline
+	state := queryState{}.record(42, day1, false)
+	if state.Count != 42 || state.Previous != nil {
+		t.Fatalf("Expected baseline of 42 without previous count, got %+v", state)
+	}
+	if state.changedWithin(day1.Add(-time.Hour), day1.Add(time.Hour)) {
+		t.Error("Expected the baseline not to be reported as a change")
+	}
+
+	// A different count is a change
+	state = state.record(38, day2, true)
+	if state.Count != 38 || state.Previous == nil || *state.Previous != 42 || !state.ChangedAt.Equal(day2) {
+		t.Fatalf("Expected change from 42 to 38 on day 2, got %+v", state)
+	}
+
+	// The same count later keeps the last change so reruns still report it
+	state = state.record(38, day2.Add(time.Hour), true)
+	if !state.ChangedAt.Equal(day2) || *state.Previous != 42 {
+		t.Errorf("Expected unchanged count to keep the last change, got %+v", state)
+	}
+	if !state.CheckedAt.Equal(day2.Add(time.Hour)) {
+		t.Errorf("Expected check time to be updated, got %v", state.CheckedAt)
+	}
+
+	if !state.changedWithin(day2.Add(-time.Hour), day3) {
+		t.Error("Expected change to be reported within day 2")
+	}
+	if state.changedWithin(day3, day3.Add(24*time.Hour)) {
+		t.Error("Expected change not to be reported on day 3")
+	}
+}
+
+func TestStateStore_RoundTrip(t *testing.T) {
+	store := stateStore{path: filepath.Join(t.TempDir(), "cache", "saved_queries.json")}
+
+	states, err := store.load()
+	if err != nil {
+		t.Fatalf("Expected no error for missing state, got: %v", err)
+	}
+	if len(states) != 0 {
+		t.Errorf("Expected empty state, got %v", states)
+	}
+
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	states["usages"] = queryState{}.record(42, now, false).record(38, now.Add(time.Hour), true)
+	if err := store.save(states); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	loaded, err := store.load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	state := loaded["usages"]
+	if state.Count != 38 || state.Previous == nil || *state.Previous != 42 || !state.ChangedAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected stored state to round-trip, got %+v", state)
+	}
+}
@@ -0,0 +1,13 @@
+package provider
+
+import (
+	"time"
+
+	"daily/internal/datetime"
+)
+
+// ParseSinceDuration parses a "since" duration string (e.g., "1d", "2w", "3h", "1m")
+// and returns the "from" time (now - duration)
+func ParseSinceDuration(since string) (time.Time, error) {
+	return datetime.SinceDuration(since, time.Now())
+}
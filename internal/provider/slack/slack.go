@@ -0,0 +1,389 @@
+// Package slack reports the Slack threads the user posted in as activities, and lists the
+// messages they saved as todo items.
+package slack
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/metrics"
+	"daily/internal/provider"
+)
+
+// defaultURL is the Slack Web API used when none is configured
+const defaultURL = "https://slack.com/api"
+
+// defaultMaxResults is the number of messages or saved items fetched when max_results isn't set
+const defaultMaxResults = 500
+
+// pageSize is the number of results fetched per request, the most search.messages returns
+const pageSize = 100
+
+// maxTitleLength is the longest title of a message, in characters
+const maxTitleLength = 80
+
+// maxExcerptLength is the longest excerpt of a saved message, in characters
+const maxExcerptLength = 200
+
+type Provider struct {
+	config provider.Config
+	client *http.Client
+
+	channelsMu sync.Mutex
+	channels   map[string]channel // Channels of the saved items, by ID
+}
+
+func NewProvider(config provider.Config) *Provider {
+	return &Provider{
+		config: config,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: metrics.NewTransport("slack"),
+		},
+		channels: make(map[string]channel),
+	}
+}
+
+func (p *Provider) Name() string {
+	return "slack"
+}
+
+func (p *Provider) IsConfigured() bool {
+	return p.config.Enabled && p.config.Token != ""
+}
+
+// ConfigSpec describes the configuration fields of the Slack provider
+func (p *Provider) ConfigSpec() []provider.ConfigField {
+	return []provider.ConfigField{
+		{Name: "token", Required: true, Secret: true, Description: "Slack user token (xoxp-) with the search:read, stars:read and channels:read scopes, plus groups:read, im:read and mpim:read for private channels and direct messages; a bot token (xoxb-) only lists saved items"},
+		{Name: "token_cmd", Description: "Command printing the token, overriding token, e.g. pass show slack/daily-token"},
+		{Name: "channels", Description: "Channel names or IDs reported, e.g. [\"platform\", \"C024BE91L\"] (default every channel, without direct messages)"},
+		{Name: "exclude_channels", Description: "Channel names or IDs never reported, e.g. [\"random\"]"},
+		{Name: "max_results", Description: "Maximum number of messages, or saved items, fetched (default 500)"},
+	}
+}
+
+// apiURL returns the URL of a Web API method, e.g. "search.messages"
+func (p *Provider) apiURL(method string, params url.Values) string {
+	apiURL := strings.TrimRight(cmp.Or(p.config.URL, defaultURL), "/") + "/" + method
+	if len(params) > 0 {
+		apiURL += "?" + params.Encode()
+	}
+	return apiURL
+}
+
+// maxResults returns the number of messages or saved items fetched
+func (p *Provider) maxResults() int {
+	if p.config.MaxResults > 0 {
+		return p.config.MaxResults
+	}
+	return defaultMaxResults
+}
+
+// channel is the part of a conversation the activities and todos use
+type channel struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	IsIM   bool   `json:"is_im"`   // Direct message, named after the other user's ID
+	IsMPIM bool   `json:"is_mpim"` // Group direct message
+}
+
+// label returns how the channel is shown, e.g. "#platform" or "a direct message"
+func (c channel) label() string {
+	switch {
+	case c.IsIM:
+		return "a direct message"
+	case c.IsMPIM:
+		return "a group message"
+	default:
+		return "#" + cmp.Or(c.Name, c.ID)
+	}
+}
+
+// allowed reports whether the messages of a channel are reported: channels of the exclude
+// list never are, and only the channels of the allow list are when it is set. Direct and
+// group messages are private, so they are only reported when in the allow list.
+func (p *Provider) allowed(c channel) bool {
+	if matchesChannel(p.config.ExcludeChannels, c) {
+		return false
+	}
+	if c.IsIM || c.IsMPIM {
+		return matchesChannel(p.config.Channels, c)
+	}
+	return len(p.config.Channels) == 0 || matchesChannel(p.config.Channels, c)
+}
+
+// matchesChannel reports whether a channel is in a list of channel names, with or without
+// "#", or IDs
+func matchesChannel(list []string, c channel) bool {
+	for _, entry := range list {
+		entry = strings.TrimPrefix(strings.TrimSpace(entry), "#")
+		if entry == c.ID || (c.Name != "" && strings.EqualFold(entry, c.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// match is the part of a message returned by search.messages the activities use
+type match struct {
+	TS        string  `json:"ts"`
+	Text      string  `json:"text"`
+	Permalink string  `json:"permalink"`
+	Channel   channel `json:"channel"`
+}
+
+// threadTS returns the timestamp of the thread of the message, its own for a message
+// starting a thread. Search results only link replies to their thread in the permalink.
+func (m match) threadTS() string {
+	if permalink, err := url.Parse(m.Permalink); err == nil {
+		if threadTS := permalink.Query().Get("thread_ts"); threadTS != "" {
+			return threadTS
+		}
+	}
+	return m.TS
+}
+
+// thread gathers the messages of the user in a thread
+type thread struct {
+	channel  channel
+	threadTS string
+	first    match // Earliest message of the user in the range
+	postedAt time.Time
+	count    int
+	replies  bool // Some messages replied to the thread rather than starting it
+}
+
+// GetActivities retrieves the threads the user posted in during the time range (for
+// summary), one activity per thread dated by the first message of the user
+func (p *Provider) GetActivities(ctx context.Context, from, to time.Time) ([]activity.Activity, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("Slack provider not configured")
+	}
+	if !p.config.IncludesType(activity.ActivityTypeMessage) {
+		return nil, nil
+	}
+
+	// Search filters by day, exclusive on both ends, so the range is widened to whole days
+	// around it and the messages outside are dropped below
+	query := fmt.Sprintf("from:me after:%s before:%s", from.AddDate(0, 0, -1).Format("2006-01-02"), to.AddDate(0, 0, 1).Format("2006-01-02"))
+	matches, err := p.searchMessages(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search Slack messages: %w", err)
+	}
+
+	threads := make(map[string]*thread)
+	var order []*thread
+	for _, m := range matches {
+		postedAt, err := parseTS(m.TS)
+		if err != nil {
+			return nil, err
+		}
+		if postedAt.Before(from) || !postedAt.Before(to) || !p.allowed(m.Channel) {
+			continue
+		}
+
+		threadTS := m.threadTS()
+		key := m.Channel.ID + "-" + threadTS
+		t, ok := threads[key]
+		if !ok {
+			t = &thread{channel: m.Channel, threadTS: threadTS, first: m, postedAt: postedAt}
+			threads[key] = t
+			order = append(order, t)
+		}
+		if postedAt.Before(t.postedAt) {
+			t.first, t.postedAt = m, postedAt
+		}
+		t.count++
+		t.replies = t.replies || threadTS != m.TS
+	}
+
+	activities := make([]activity.Activity, 0, len(order))
+	for _, t := range order {
+		activities = append(activities, newThreadActivity(t))
+	}
+	return activities, nil
+}
+
+// newThreadActivity converts the messages of the user in a thread into a message activity,
+// e.g. "3 messages in a thread of #platform"
+func newThreadActivity(t *thread) activity.Activity {
+	where := t.channel.label()
+	if t.replies {
+		where = "a thread of " + where
+	}
+	messages := "1 message"
+	if t.count > 1 {
+		messages = fmt.Sprintf("%d messages", t.count)
+	}
+
+	return activity.Activity{
+		ID:          fmt.Sprintf("slack-%s-%s", t.channel.ID, t.threadTS),
+		Type:        activity.ActivityTypeMessage,
+		Title:       messageTitle(t.first.Text),
+		Description: fmt.Sprintf("%s in %s", messages, where),
+		URL:         t.first.Permalink,
+		Platform:    "slack",
+		Timestamp:   t.postedAt,
+		Tags:        []string{t.channel.label()},
+		Details:     activity.NewDetails(activity.DetailChannel, t.channel.label()),
+	}
+}
+
+// searchMessages returns the messages matching a search query, oldest first, following the
+// cursors up to the max results
+func (p *Provider) searchMessages(ctx context.Context, query string) ([]match, error) {
+	params := url.Values{
+		"query":    {query},
+		"sort":     {"timestamp"},
+		"sort_dir": {"asc"},
+		"count":    {strconv.Itoa(pageSize)},
+		"cursor":   {"*"}, // Cursor pagination instead of page numbers
+	}
+
+	var matches []match
+	for len(matches) < p.maxResults() {
+		var page struct {
+			Messages struct {
+				Matches []match `json:"matches"`
+			} `json:"messages"`
+			ResponseMetadata responseMetadata `json:"response_metadata"`
+		}
+		if err := p.call(ctx, "search.messages", params, &page); err != nil {
+			return nil, err
+		}
+		matches = append(matches, page.Messages.Matches...)
+
+		if page.ResponseMetadata.NextCursor == "" {
+			break
+		}
+		params.Set("cursor", page.ResponseMetadata.NextCursor)
+	}
+
+	if len(matches) > p.maxResults() {
+		matches = matches[:p.maxResults()]
+	}
+	return matches, nil
+}
+
+// parseTS converts the timestamp of a message, e.g. "1705312800.000100", into a time
+func parseTS(ts string) (time.Time, error) {
+	seconds, fraction, _ := strings.Cut(ts, ".")
+	sec, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q of a Slack message", ts)
+	}
+	micro, _ := strconv.ParseInt(fraction, 10, 64)
+	return time.Unix(sec, micro*int64(time.Microsecond)), nil
+}
+
+// linkPattern matches the links, mentions and channel references of message text, e.g.
+// "<https://example.com|the docs>", "<@U024BE7LH>" or "<#C024BE91L|platform>"
+var linkPattern = regexp.MustCompile(`<([^<>|]+)(?:\|([^<>]*))?>`)
+
+// entities are the characters Slack escapes in message text
+var entities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// plainText converts the markup of message text to plain text, links replaced by their label
+func plainText(text string) string {
+	text = linkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		parts := linkPattern.FindStringSubmatch(link)
+		target, label := parts[1], parts[2]
+		switch {
+		case strings.HasPrefix(target, "#"):
+			return "#" + cmp.Or(label, target[1:])
+		case strings.HasPrefix(target, "@"), strings.HasPrefix(target, "!"):
+			// User mentions, and special mentions such as <!here>
+			return "@" + strings.TrimPrefix(cmp.Or(label, target[1:]), "@")
+		default:
+			return cmp.Or(label, target)
+		}
+	})
+	return entities.Replace(text)
+}
+
+// messageTitle returns the first line of the text of a message, shortened to a title
+func messageTitle(text string) string {
+	for _, line := range strings.Split(plainText(text), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return truncate(line, maxTitleLength)
+		}
+	}
+	return "(No text)"
+}
+
+// truncate shortens text to at most maxLen runes, adding an ellipsis when cut
+func truncate(text string, maxLen int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxLen-1])) + "…"
+}
+
+// responseMetadata holds the cursor of the next page of a paginated method
+type responseMetadata struct {
+	NextCursor string `json:"next_cursor"`
+}
+
+// call decodes the response of a Web API method into result. Slack reports errors in the
+// body of responses, so those that aren't ok fail with their error code.
+func (p *Provider) call(ctx context.Context, method string, params url.Values, result any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.apiURL(method, params), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.config.Token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Slack API returned status %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var status struct {
+		OK     bool   `json:"ok"`
+		Error  string `json:"error"`
+		Needed string `json:"needed"` // Scope missing from the token, with missing_scope
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return err
+	}
+	if !status.OK {
+		if status.Needed != "" {
+			return fmt.Errorf("Slack API %s failed: %s (needs %s)", method, status.Error, status.Needed)
+		}
+		return fmt.Errorf("Slack API %s failed: %s", method, status.Error)
+	}
+
+	return json.Unmarshal(body, result)
+}
+
+// provenance records the method a todo item was fetched from
+func (p *Provider) provenance(method string) *provider.Provenance {
+	return &provider.Provenance{
+		Provider:  "slack",
+		Query:     method,
+		FetchedAt: time.Now(),
+	}
+}
@@ -0,0 +1,238 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+// searchMessagesPage1 is the first page of the messages found by search.messages, linked to
+// the second by its cursor
+const searchMessagesPage1 = `{"ok": true, "messages": {"matches": [
+	{"ts": "1705276000.000100", "text": "Good night", "permalink": "https://acme.slack.com/archives/C01/p1705276000000100",
+	 "channel": {"id": "C01", "name": "platform", "is_channel": true}},
+	{"ts": "1705312800.000100", "text": "Deploy of <https://ci.example.com/runs/42|run 42> is done, <@U02|alice> can you check?\nDetails in the run",
+	 "permalink": "https://acme.slack.com/archives/C01/p1705312800000100", "channel": {"id": "C01", "name": "platform", "is_channel": true}},
+	{"ts": "1705316400.000200", "text": "Looking into it", "permalink": "https://acme.slack.com/archives/C01/p1705316400000200?thread_ts=1705310000.000300&cid=C01",
+	 "channel": {"id": "C01", "name": "platform", "is_channel": true}},
+	{"ts": "1705320000.000100", "text": "lunch?", "permalink": "https://acme.slack.com/archives/C02/p1705320000000100",
+	 "channel": {"id": "C02", "name": "random", "is_channel": true}}
+]}, "response_metadata": {"next_cursor": "c2VhcmNoOjI="}}`
+
+// searchMessagesPage2 is the second page of the messages found by search.messages
+const searchMessagesPage2 = `{"ok": true, "messages": {"matches": [
+	{"ts": "1705323600.000400", "text": "Fixed &amp; deployed", "permalink": "https://acme.slack.com/archives/C01/p1705323600000400?thread_ts=1705310000.000300&cid=C01",
+	 "channel": {"id": "C01", "name": "platform", "is_channel": true}},
+	{"ts": "1705327200.000500", "text": "Sure, see <#C01|platform>", "permalink": "https://acme.slack.com/archives/D01/p1705327200000500",
+	 "channel": {"id": "D01", "name": "U02", "is_im": true}}
+]}, "response_metadata": {"next_cursor": ""}}`
+
+// writeSearchMessages answers a search.messages request with the page of its cursor
+func writeSearchMessages(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("cursor") == "c2VhcmNoOjI=" {
+		_, _ = w.Write([]byte(searchMessagesPage2))
+		return
+	}
+	_, _ = w.Write([]byte(searchMessagesPage1))
+}
+
+// newTestProvider returns a provider of the test server, with the settings of config
+func newTestProvider(serverURL string, config provider.Config) *Provider {
+	config.Enabled = true
+	config.URL = serverURL
+	if config.Token == "" {
+		config.Token = "xoxp-secret"
+	}
+	return NewProvider(config)
+}
+
+// testRange is the day of the messages found by the search
+var testRange = struct{ from, to time.Time }{
+	from: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+	to:   time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC),
+}
+
+func TestProvider_GetActivities(t *testing.T) {
+	var queries, cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer xoxp-secret" || r.URL.Path != "/search.messages" {
+			t.Errorf("Unexpected request %s with %q", r.URL, auth)
+		}
+		queries = append(queries, r.URL.Query().Get("query"))
+		cursors = append(cursors, r.URL.Query().Get("cursor"))
+		writeSearchMessages(w, r)
+	}))
+	defer server.Close()
+
+	activities, err := newTestProvider(server.URL, provider.Config{}).GetActivities(context.Background(), testRange.from, testRange.to)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The day around the range is searched, over two pages
+	if !slices.Equal(queries, []string{"from:me after:2024-01-14 before:2024-01-17", "from:me after:2024-01-14 before:2024-01-17"}) {
+		t.Errorf("Expected the search of the days around the range, got %v", queries)
+	}
+	if !slices.Equal(cursors, []string{"*", "c2VhcmNoOjI="}) {
+		t.Errorf("Expected the second page fetched with the cursor of the first, got %v", cursors)
+	}
+
+	// The message of the day before and the direct message are left out, and the two replies
+	// are a single thread
+	if len(activities) != 3 {
+		t.Fatalf("Expected 3 threads, got %+v", activities)
+	}
+
+	posted := activities[0]
+	if posted.ID != "slack-C01-1705312800.000100" || posted.Type != activity.ActivityTypeMessage || posted.Platform != "slack" {
+		t.Errorf("Expected the message activity, got %+v", posted)
+	}
+	if posted.Title != "Deploy of run 42 is done, @alice can you check?" || posted.Description != "1 message in #platform" {
+		t.Errorf("Expected the first line of the message in plain text, got %q and %q", posted.Title, posted.Description)
+	}
+	if posted.URL != "https://acme.slack.com/archives/C01/p1705312800000100" || !posted.Timestamp.Equal(time.Date(2024, 1, 15, 10, 0, 0, 100000, time.UTC)) {
+		t.Errorf("Expected the permalink and time of the message, got %s and %v", posted.URL, posted.Timestamp)
+	}
+	if posted.Details[activity.DetailChannel] != "#platform" || !slices.Equal(posted.Tags, []string{"#platform"}) {
+		t.Errorf("Expected the channel, got %v and %v", posted.Details, posted.Tags)
+	}
+
+	replied := activities[1]
+	if replied.ID != "slack-C01-1705310000.000300" || replied.Title != "Looking into it" || replied.Description != "2 messages in a thread of #platform" {
+		t.Errorf("Expected the replies grouped by thread, got %+v", replied)
+	}
+	if replied.URL != "https://acme.slack.com/archives/C01/p1705316400000200?thread_ts=1705310000.000300&cid=C01" {
+		t.Errorf("Expected the permalink of the first reply, got %s", replied.URL)
+	}
+
+	if activities[2].Description != "1 message in #random" {
+		t.Errorf("Expected the message of #random, got %+v", activities[2])
+	}
+}
+
+func TestProvider_GetActivities_Channels(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   provider.Config
+		expected []string
+	}{
+		{"allowed channels", provider.Config{Channels: []string{"#Platform"}}, []string{"slack-C01-1705312800.000100", "slack-C01-1705310000.000300"}},
+		{"allowed channel IDs", provider.Config{Channels: []string{"C02", "D01"}}, []string{"slack-C02-1705320000.000100", "slack-D01-1705327200.000500"}},
+		{"direct messages left out", provider.Config{ExcludeChannels: []string{"random"}}, []string{"slack-C01-1705312800.000100", "slack-C01-1705310000.000300"}},
+		{"excluded channels", provider.Config{ExcludeChannels: []string{"random", "D01"}}, []string{"slack-C01-1705312800.000100", "slack-C01-1705310000.000300"}},
+		{"excluded over allowed", provider.Config{Channels: []string{"platform", "random"}, ExcludeChannels: []string{"platform"}}, []string{"slack-C02-1705320000.000100"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(writeSearchMessages))
+			defer server.Close()
+
+			activities, err := newTestProvider(server.URL, tt.config).GetActivities(context.Background(), testRange.from, testRange.to)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			var ids []string
+			for _, act := range activities {
+				ids = append(ids, act.ID)
+			}
+			if !slices.Equal(ids, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, ids)
+			}
+
+			// Allowed direct messages don't show who they're with
+			for _, act := range activities {
+				if strings.HasPrefix(act.ID, "slack-D01-") && act.Description != "1 message in a direct message" {
+					t.Errorf("Expected the direct message without its user, got %q", act.Description)
+				}
+			}
+		})
+	}
+}
+
+func TestProvider_GetActivities_MaxResults(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursors = append(cursors, r.URL.Query().Get("cursor"))
+		writeSearchMessages(w, r)
+	}))
+	defer server.Close()
+
+	// The first page holds enough messages
+	activities, err := newTestProvider(server.URL, provider.Config{MaxResults: 3}).GetActivities(context.Background(), testRange.from, testRange.to)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cursors) != 1 {
+		t.Errorf("Expected a single page, got cursors %v", cursors)
+	}
+	if len(activities) != 2 {
+		t.Errorf("Expected the threads of the first 3 messages, got %+v", activities)
+	}
+}
+
+func TestProvider_GetActivities_Errors(t *testing.T) {
+	// Slack answers errors with a 200 and ok false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "invalid_auth"}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestProvider(server.URL, provider.Config{Token: "xoxp-revoked"}).GetActivities(context.Background(), testRange.from, testRange.to)
+	if err == nil || err.Error() != "failed to search Slack messages: Slack API search.messages failed: invalid_auth" {
+		t.Errorf("Expected the error of the API, got %v", err)
+	}
+
+	if _, err := NewProvider(provider.Config{Enabled: true}).GetActivities(context.Background(), testRange.from, testRange.to); err == nil {
+		t.Error("Expected an error without token, got nil")
+	}
+}
+
+func TestProvider_GetActivities_ExcludedType(t *testing.T) {
+	// No request is made when messages aren't reported
+	p := NewProvider(provider.Config{Enabled: true, Token: "xoxp-secret", URL: "http://127.0.0.1:0", IncludeTypes: []string{"commit"}})
+	activities, err := p.GetActivities(context.Background(), testRange.from, testRange.to)
+	if err != nil || len(activities) != 0 {
+		t.Errorf("Expected no activities, got %+v and %v", activities, err)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Deploy of <https://ci.example.com/runs/42|run 42>", "Deploy of run 42"},
+		{"See <https://example.com>", "See https://example.com"},
+		{"Thanks <@U024BE7LH>", "Thanks @U024BE7LH"},
+		{"Ask <!subteam^S01|@platform-team> in <#C024BE91L|platform>", "Ask @platform-team in #platform"},
+		{"<!here> 1 &lt; 2 &amp;&amp; 3 &gt; 2", "@here 1 < 2 && 3 > 2"},
+	}
+
+	for _, tt := range tests {
+		if got := plainText(tt.text); got != tt.expected {
+			t.Errorf("plainText(%q) = %q, expected %q", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestMessageTitle(t *testing.T) {
+	long := strings.Repeat("word ", 30)
+	if title := messageTitle(long); len([]rune(title)) != maxTitleLength || !strings.HasSuffix(title, "…") {
+		t.Errorf("Expected a title cut at %d characters, got %q", maxTitleLength, title)
+	}
+	if title := messageTitle("\n\n  Second line\nThird"); title != "Second line" {
+		t.Errorf("Expected the first line with text, got %q", title)
+	}
+	if title := messageTitle(""); title != "(No text)" {
+		t.Errorf("Expected a title for messages without text, got %q", title)
+	}
+}
@@ -0,0 +1,141 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/concurrency"
+	"daily/internal/provider"
+)
+
+// TodoItem represents a single todo item
+type TodoItem = provider.TodoItem
+
+// channelLookups bounds the requests resolving the channels of the saved items
+var channelLookups = concurrency.Options{Workers: 4}
+
+// savedItem is an item of stars.list, the saved items of the user
+type savedItem struct {
+	Type    string `json:"type"` // "message", "file", "channel"...
+	Channel string `json:"channel"`
+	Message struct {
+		TS        string `json:"ts"`
+		Text      string `json:"text"`
+		Permalink string `json:"permalink"`
+	} `json:"message"`
+	DateCreate int64 `json:"date_create"` // When the item was saved, in seconds
+}
+
+// GetSavedItems retrieves the messages the user saved for later, in the allowed channels
+func (p *Provider) GetSavedItems(ctx context.Context) ([]TodoItem, error) {
+	if !p.IsConfigured() {
+		return nil, fmt.Errorf("Slack provider not configured")
+	}
+
+	items, err := p.listSavedItems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Slack saved items: %w", err)
+	}
+
+	// Saved files and channels aren't todos
+	messages := make([]savedItem, 0, len(items))
+	for _, item := range items {
+		if item.Type == "message" {
+			messages = append(messages, item)
+		}
+	}
+	channels := p.resolveChannels(ctx, messages)
+	provenance := p.provenance("stars.list")
+
+	var todos []TodoItem
+	for _, item := range messages {
+		ch := channels[item.Channel]
+		if !p.allowed(ch) {
+			continue
+		}
+		todos = append(todos, TodoItem{
+			ID:          fmt.Sprintf("slack-saved-%s-%s", item.Channel, item.Message.TS),
+			Title:       messageTitle(item.Message.Text),
+			Description: "Saved from " + ch.label(),
+			URL:         item.Message.Permalink,
+			UpdatedAt:   time.Unix(item.DateCreate, 0),
+			Tags:        []string{ch.label(), "saved"},
+			Excerpt:     truncate(strings.Join(strings.Fields(plainText(item.Message.Text)), " "), maxExcerptLength),
+			Details:     activity.NewDetails(activity.DetailChannel, ch.label()),
+			Provenance:  provenance,
+		})
+	}
+	return todos, nil
+}
+
+// listSavedItems lists the saved items of the user, following the cursors up to the max
+// results
+func (p *Provider) listSavedItems(ctx context.Context) ([]savedItem, error) {
+	params := url.Values{"limit": {strconv.Itoa(pageSize)}}
+
+	var items []savedItem
+	for len(items) < p.maxResults() {
+		var page struct {
+			Items            []savedItem      `json:"items"`
+			ResponseMetadata responseMetadata `json:"response_metadata"`
+		}
+		if err := p.call(ctx, "stars.list", params, &page); err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+
+		if page.ResponseMetadata.NextCursor == "" {
+			break
+		}
+		params.Set("cursor", page.ResponseMetadata.NextCursor)
+	}
+
+	if len(items) > p.maxResults() {
+		items = items[:p.maxResults()]
+	}
+	return items, nil
+}
+
+// resolveChannels returns the channels of saved items by ID, as saved items only have the ID
+// of their channel. Channels are fetched once per provider, the ones that can't be fetched
+// are shown by ID.
+func (p *Provider) resolveChannels(ctx context.Context, items []savedItem) map[string]channel {
+	p.channelsMu.Lock()
+	var missing []string
+	for _, item := range items {
+		if _, ok := p.channels[item.Channel]; !ok && !slices.Contains(missing, item.Channel) {
+			missing = append(missing, item.Channel)
+		}
+	}
+	p.channelsMu.Unlock()
+
+	results := concurrency.Map(ctx, missing, channelLookups, func(ctx context.Context, _ int, id string) (channel, error) {
+		var info struct {
+			Channel channel `json:"channel"`
+		}
+		err := p.call(ctx, "conversations.info", url.Values{"channel": {id}}, &info)
+		return info.Channel, err
+	})
+
+	p.channelsMu.Lock()
+	defer p.channelsMu.Unlock()
+	channels := maps.Clone(p.channels)
+	for i, id := range missing {
+		if results[i].Err != nil {
+			// Not cached, to be fetched again by the next call. IDs of direct messages start
+			// with D, so that they stay private.
+			channels[id] = channel{ID: id, IsIM: strings.HasPrefix(id, "D")}
+			continue
+		}
+		p.channels[id] = results[i].Value
+		channels[id] = results[i].Value
+	}
+	return channels
+}
@@ -0,0 +1,197 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/provider"
+)
+
+// savedItemsPage1 is the first page of stars.list, linked to the second by its cursor
+const savedItemsPage1 = `{"ok": true, "items": [
+	{"type": "message", "channel": "C01", "date_create": 1705400000, "message": {"ts": "1705300000.000100",
+	 "text": "Postmortem draft for the checkout outage:\n<https://docs.example.com/postmortem|Postmortem> needs a review by Friday",
+	 "permalink": "https://acme.slack.com/archives/C01/p1705300000000100"}},
+	{"type": "file", "date_create": 1705390000, "file": {"id": "F01", "name": "diagram.png"}}
+], "response_metadata": {"next_cursor": "c3RhcnM6Mg=="}}`
+
+// savedItemsPage2 is the second page of stars.list
+const savedItemsPage2 = `{"ok": true, "items": [
+	{"type": "message", "channel": "C03", "date_create": 1705410000, "message": {"ts": "1705405000.000200",
+	 "text": "Customer data exposed in the logs, rotate the keys", "permalink": "https://acme.slack.com/archives/C03/p1705405000000200"}},
+	{"type": "message", "channel": "C01", "date_create": 1705420000, "message": {"ts": "1705415000.000300",
+	 "text": "", "permalink": "https://acme.slack.com/archives/C01/p1705415000000300"}}
+], "response_metadata": {"next_cursor": ""}}`
+
+// writeSavedItems answers the stars.list requests with the page of their cursor, and the
+// conversations.info requests with the channels of the saved items
+func writeSavedItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch r.URL.Path {
+	case "/stars.list":
+		if query.Get("cursor") == "c3RhcnM6Mg==" {
+			_, _ = w.Write([]byte(savedItemsPage2))
+			return
+		}
+		_, _ = w.Write([]byte(savedItemsPage1))
+	case "/conversations.info":
+		switch query.Get("channel") {
+		case "C01":
+			_, _ = w.Write([]byte(`{"ok": true, "channel": {"id": "C01", "name": "platform", "is_channel": true}}`))
+		case "C03":
+			_, _ = w.Write([]byte(`{"ok": true, "channel": {"id": "C03", "name": "secret-incident", "is_group": true, "is_private": true}}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok": false, "error": "channel_not_found"}`))
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestProvider_GetSavedItems(t *testing.T) {
+	var mu sync.Mutex
+	var cursors, lookups []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		switch r.URL.Path {
+		case "/stars.list":
+			cursors = append(cursors, r.URL.Query().Get("cursor"))
+		case "/conversations.info":
+			lookups = append(lookups, r.URL.Query().Get("channel"))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		mu.Unlock()
+		writeSavedItems(w, r)
+	}))
+	defer server.Close()
+
+	p := newTestProvider(server.URL, provider.Config{})
+
+	items, err := p.GetSavedItems(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Two pages, linked by the cursor of the first
+	if !slices.Equal(cursors, []string{"", "c3RhcnM6Mg=="}) {
+		t.Errorf("Expected the second page fetched with the cursor of the first, got %v", cursors)
+	}
+
+	// The saved file is left out
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 saved messages, got %+v", items)
+	}
+
+	item := items[0]
+	if item.ID != "slack-saved-C01-1705300000.000100" || item.Title != "Postmortem draft for the checkout outage:" || item.Description != "Saved from #platform" {
+		t.Errorf("Expected the saved message, got %+v", item)
+	}
+	if item.URL != "https://acme.slack.com/archives/C01/p1705300000000100" || !item.UpdatedAt.Equal(time.Unix(1705400000, 0)) {
+		t.Errorf("Expected the permalink and the time it was saved, got %s and %v", item.URL, item.UpdatedAt)
+	}
+	if item.Excerpt != "Postmortem draft for the checkout outage: Postmortem needs a review by Friday" {
+		t.Errorf("Expected the text of the message on one line as excerpt, got %q", item.Excerpt)
+	}
+	if !slices.Equal(item.Tags, []string{"#platform", "saved"}) || item.Details[activity.DetailChannel] != "#platform" {
+		t.Errorf("Expected the channel and saved tags, got %v and %v", item.Tags, item.Details)
+	}
+	if item.Provenance == nil || item.Provenance.Provider != "slack" || item.Provenance.Query != "stars.list" {
+		t.Errorf("Expected the provenance of stars.list, got %+v", item.Provenance)
+	}
+
+	if items[1].Description != "Saved from #secret-incident" || items[2].Title != "(No text)" {
+		t.Errorf("Expected the other saved messages, got %+v and %+v", items[1], items[2])
+	}
+
+	// Each channel is looked up once, and not again by the next calls
+	if _, err := p.GetSavedItems(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	slices.Sort(lookups)
+	if !slices.Equal(lookups, []string{"C01", "C03"}) {
+		t.Errorf("Expected the channels looked up once, got %v", lookups)
+	}
+}
+
+func TestProvider_GetSavedItems_Channels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(writeSavedItems))
+	defer server.Close()
+
+	// Private channels are kept out of the todos by name
+	config := provider.Config{ExcludeChannels: []string{"secret-incident"}}
+	items, err := newTestProvider(server.URL, config).GetSavedItems(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(items) != 2 || items[0].ID != "slack-saved-C01-1705300000.000100" || items[1].ID != "slack-saved-C01-1705415000.000300" {
+		t.Errorf("Expected the messages of #platform, got %+v", items)
+	}
+
+	config = provider.Config{Channels: []string{"C03"}}
+	items, err = newTestProvider(server.URL, config).GetSavedItems(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "slack-saved-C03-1705405000.000200" {
+		t.Errorf("Expected the message of the allowed channel, got %+v", items)
+	}
+}
+
+func TestProvider_GetSavedItems_KnownChannels(t *testing.T) {
+	var lookups []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/conversations.info" {
+			lookups = append(lookups, r.URL.Query().Get("channel"))
+		}
+		writeSavedItems(w, r)
+	}))
+	defer server.Close()
+
+	p := newTestProvider(server.URL, provider.Config{})
+	p.channels["C01"] = channel{ID: "C01", Name: "platform"}
+	p.channels["C03"] = channel{ID: "C03", Name: "secret-incident"}
+
+	// Channels already known aren't looked up
+	if _, err := p.GetSavedItems(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(lookups) != 0 {
+		t.Errorf("Expected no lookup, got %v", lookups)
+	}
+}
+
+func TestProvider_GetSavedItems_ChannelLookupError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/stars.list":
+			_, _ = w.Write([]byte(`{"ok": true, "items": [
+				{"type": "message", "channel": "C09", "date_create": 1705400000, "message": {"ts": "1705300000.000100", "text": "Read later"}},
+				{"type": "message", "channel": "D09", "date_create": 1705400000, "message": {"ts": "1705300000.000200", "text": "Private"}}
+			]}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok": false, "error": "missing_scope"}`))
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(provider.Config{Enabled: true, Token: "xoxp-secret", URL: server.URL})
+	items, err := p.GetSavedItems(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The channel is shown by ID, and the direct message stays private
+	if len(items) != 1 || items[0].Description != "Saved from #C09" {
+		t.Errorf("Expected the saved message shown with the channel ID, got %+v", items)
+	}
+	if len(p.channels) != 0 {
+		t.Errorf("Expected the channels that failed not to be cached, got %v", p.channels)
+	}
+}
@@ -0,0 +1,104 @@
+// Package severity ranks todo items and review requests by the urgency of their JIRA priority or GitHub labels.
+package severity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Level is the normalized severity of an item
+type Level string
+
+const (
+	Critical Level = "critical"
+	High     Level = "high"
+	Normal   Level = "normal"
+	Low      Level = "low"
+)
+
+// Levels lists the levels from the most to the least severe
+var Levels = []Level{Critical, High, Normal, Low}
+
+// colorLevels are the colors a rule can be written with and the level each stands for
+var colorLevels = map[string]Level{
+	"red":    Critical,
+	"orange": High,
+	"yellow": Normal,
+	"gray":   Low,
+	"grey":   Low,
+}
+
+// markers are shown instead of colors on terminals without them
+var markers = map[Level]string{
+	Critical: "[CRIT]",
+	High:     "[HIGH]",
+	Normal:   "[NORM]",
+	Low:      "[LOW]",
+}
+
+// Marker returns the textual marker of the level, e.g. "[CRIT]", or "" for no level
+func (l Level) Marker() string {
+	return markers[l]
+}
+
+// ParseLevel returns the level of a rule, written as a level name (e.g. "critical") or as its
+// color (e.g. "red"), ignoring case
+func ParseLevel(value string) (Level, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if level := Level(value); slices.Contains(Levels, level) {
+		return level, nil
+	}
+	if level, ok := colorLevels[value]; ok {
+		return level, nil
+	}
+	return "", fmt.Errorf("unknown severity %q (expected critical, high, normal, low, red, orange, yellow or gray)", value)
+}
+
+// DefaultRules are used when no severity rules are configured. They cover the JIRA priority
+// schemes and the usual P0/P1 labels.
+var DefaultRules = map[string]string{
+	"blocker":  "red",
+	"highest":  "red",
+	"critical": "red",
+	"p0":       "red",
+	"urgent":   "orange",
+	"high":     "orange",
+	"p1":       "orange",
+	"low":      "gray",
+	"lowest":   "gray",
+	"trivial":  "gray",
+}
+
+// Rules maps lowercase priorities, labels or tags to the level of the items having them
+type Rules map[string]Level
+
+// ParseRules parses configured rules, e.g. {"blocker": "red", "p2": "normal"}
+func ParseRules(config map[string]string) (Rules, error) {
+	rules := make(Rules, len(config))
+	for value, levelName := range config {
+		if strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("rule for %q has no value to match", levelName)
+		}
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", value, err)
+		}
+		rules[strings.ToLower(strings.TrimSpace(value))] = level
+	}
+	return rules, nil
+}
+
+// Resolve returns the level of the first field matching a rule, ignoring case: the priority,
+// then the tags in order. It returns "" when no field matches.
+func (r Rules) Resolve(priority string, tags []string) Level {
+	for _, field := range append([]string{priority}, tags...) {
+		if field == "" {
+			continue
+		}
+		if level, ok := r[strings.ToLower(strings.TrimSpace(field))]; ok {
+			return level
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package severity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Level
+	}{
+		{"critical", Critical},
+		{"High", High},
+		{"red", Critical},
+		{"orange", High},
+		{"yellow", Normal},
+		{" grey ", Low},
+	}
+
+	for _, tt := range tests {
+		level, err := ParseLevel(tt.value)
+		if err != nil {
+			t.Errorf("Expected no error for '%s', got: %v", tt.value, err)
+		}
+		if level != tt.expected {
+			t.Errorf("Expected %s for '%s', got %s", tt.expected, tt.value, level)
+		}
+	}
+
+	if _, err := ParseLevel("purple"); err == nil || !strings.Contains(err.Error(), `unknown severity "purple"`) {
+		t.Errorf("Expected unknown severity error, got: %v", err)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(map[string]string{"Blocker": "red", "p2": "normal"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if rules["blocker"] != Critical || rules["p2"] != Normal {
+		t.Errorf("Expected lowercase values mapped to levels, got %v", rules)
+	}
+
+	if _, err := ParseRules(map[string]string{"p0": "scarlet"}); err == nil || !strings.Contains(err.Error(), "p0:") {
+		t.Errorf("Expected error naming the rule, got: %v", err)
+	}
+	if _, err := ParseRules(map[string]string{" ": "red"}); err == nil {
+		t.Error("Expected error for a rule without value")
+	}
+	if _, err := ParseRules(DefaultRules); err != nil {
+		t.Errorf("Expected the default rules to be valid, got: %v", err)
+	}
+}
+
+func TestRules_Resolve(t *testing.T) {
+	rules, err := ParseRules(map[string]string{"blocker": "red", "p0": "red", "urgent": "orange", "chore": "gray"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		priority string
+		tags     []string
+		expected Level
+	}{
+		{"priority", "Blocker", []string{"web"}, Critical},
+		{"label ignoring case", "", []string{"acme/web", "open", "P0"}, Critical},
+		{"priority before tags", "Urgent", []string{"p0"}, High},
+		{"first tag wins", "", []string{"chore", "urgent"}, Low},
+		{"no match", "Medium", []string{"acme/web"}, ""},
+		{"nothing to match", "", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if level := rules.Resolve(tt.priority, tt.tags); level != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, level)
+			}
+		})
+	}
+}
+
+func TestLevel_Marker(t *testing.T) {
+	if Critical.Marker() != "[CRIT]" || Low.Marker() != "[LOW]" {
+		t.Errorf("Expected [CRIT] and [LOW], got %s and %s", Critical.Marker(), Low.Marker())
+	}
+	if Level("").Marker() != "" {
+		t.Error("Expected no marker without level")
+	}
+}
@@ -0,0 +1,32 @@
+// Package statedir locates the files holding what daily can't rebuild, such as tokens or the
+// items already notified, out of the cache directory, which users treat as disposable.
+package statedir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Path returns the file called name in the state directory, ~/.config/daily/state. A file
+// of the same name left in the cache directory by earlier versions is moved there first.
+func Path(name string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	path := filepath.Join(homeDir, ".config", "daily", "state", name)
+
+	legacy := filepath.Join(homeDir, ".config", "daily", "cache", name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if _, err := os.Stat(legacy); err == nil {
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return "", fmt.Errorf("failed to create state directory: %w", err)
+			}
+			if err := os.Rename(legacy, path); err != nil {
+				return "", fmt.Errorf("failed to move %s to the state directory: %w", name, err)
+			}
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,43 @@
+package statedir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	path, err := Path("notify.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if path != filepath.Join(home, ".config", "daily", "state", "notify.json") {
+		t.Errorf("Expected the file in the state directory, got %s", path)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected no file created, got %v", err)
+	}
+
+	// Files of earlier versions are moved out of the cache directory
+	legacy := filepath.Join(home, ".config", "daily", "cache", "calendar_token.json")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0755); err != nil {
+		t.Fatalf("Failed to create cache directory: %v", err)
+	}
+	if err := os.WriteFile(legacy, []byte(`{"access_token": "abc"}`), 0600); err != nil {
+		t.Fatalf("Failed to write legacy file: %v", err)
+	}
+
+	path, err = Path("calendar_token.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != `{"access_token": "abc"}` {
+		t.Errorf("Expected the legacy file moved, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("Expected the legacy file gone, got %v", err)
+	}
+}
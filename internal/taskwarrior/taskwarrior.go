@@ -0,0 +1,173 @@
+package taskwarrior
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"daily/internal/output"
+)
+
+// UDA is the user defined attribute carrying the ID of the daily item a task was exported
+// from, or its URL for GitHub items. Taskwarrior keeps it without definition, defining it in .taskrc makes it filterable:
+//
+//	uda.daily_id.type=string
+//	uda.daily_id.label=Daily ID
+const UDA = "daily_id"
+
+// dateLayout is the layout of the dates of the taskwarrior JSON format
+const dateLayout = "20060102T150405Z"
+
+// Task statuses used by daily
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+)
+
+// Task is a task of the taskwarrior JSON import and export format, with the attributes daily
+// reads and writes
+type Task struct {
+	UUID        string   `json:"uuid,omitempty"`
+	Description string   `json:"description"`
+	Status      string   `json:"status,omitempty"`
+	Project     string   `json:"project,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Due         string   `json:"due,omitempty"` // UTC, e.g. 20240312T000000Z
+	DailyID     string   `json:"daily_id,omitempty"`
+}
+
+// Project is the todo items of a taskwarrior project, e.g. the items of a platform
+type Project struct {
+	Name  string
+	Items []output.TodoItem
+}
+
+// FromTodoItems maps the todo items of the projects and their subtasks to pending tasks, in
+// order, each item once. The UUID of a task is derived from its item ID, so that importing
+// the export again updates the tasks instead of duplicating them.
+func FromTodoItems(projects []Project) []Task {
+	tasks := []Task{}
+	seen := make(map[string]bool)
+	var add func(project string, items []output.TodoItem)
+	add = func(project string, items []output.TodoItem) {
+		for _, item := range items {
+			if id := itemID(project, item); !seen[id] {
+				seen[id] = true
+				tasks = append(tasks, FromTodoItem(project, item))
+			}
+			add(project, item.Subtasks)
+		}
+	}
+	for _, project := range projects {
+		add(project.Name, project.Items)
+	}
+	return tasks
+}
+
+// FromTodoItem maps a todo item of a project, e.g. "jira", to a pending task. The tags are
+// the item tags without spaces, which taskwarrior doesn't allow.
+func FromTodoItem(project string, item output.TodoItem) Task {
+	id := itemID(project, item)
+	task := Task{
+		UUID:        taskUUID(id),
+		Description: item.Title,
+		Status:      StatusPending,
+		Project:     project,
+		DailyID:     id,
+	}
+	if item.Key != "" && !strings.Contains(item.Title, item.Key) {
+		task.Description = item.Key + ": " + item.Title
+	}
+	for _, tag := range item.Tags {
+		if tag = strings.Join(strings.Fields(tag), "_"); tag != "" {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+	if item.DueDate != nil {
+		task.Due = item.DueDate.UTC().Format(dateLayout)
+	}
+	return task
+}
+
+// itemID returns the ID of an item in taskwarrior: its ID, or its URL for GitHub items, as
+// IDs like github-pr-123 are only unique in a repository. The URL isn't used for the other
+// platforms, as it is shared by the items of a page, e.g. the tasks of an Obsidian note. Both
+// refer to the item when hiding it.
+func itemID(project string, item output.TodoItem) string {
+	if project == "github" && item.URL != "" {
+		return item.URL
+	}
+	return item.ID
+}
+
+// taskUUID returns a name based UUID (version 5) for an item ID
+func taskUUID(id string) string {
+	sum := sha1.Sum([]byte("daily:" + id))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// Completed returns the completed tasks exported from daily, one per item. An item is left
+// out when it also has a pending task, e.g. when it was exported again after being completed.
+func Completed(tasks []Task) []Task {
+	pending := make(map[string]bool)
+	for _, task := range tasks {
+		if task.DailyID != "" && task.Status == StatusPending {
+			pending[task.DailyID] = true
+		}
+	}
+
+	var completed []Task
+	seen := make(map[string]bool)
+	for _, task := range tasks {
+		if task.DailyID == "" || task.Status != StatusCompleted || pending[task.DailyID] || seen[task.DailyID] {
+			continue
+		}
+		seen[task.DailyID] = true
+		completed = append(completed, task)
+	}
+	return completed
+}
+
+// Runner runs the task binary with arguments and returns what it printed on stdout
+type Runner func(ctx context.Context, args ...string) ([]byte, error)
+
+// RunTask runs the task binary, capturing its stderr into the error
+func RunTask(ctx context.Context, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "task", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("task not found: install taskwarrior or add it to the PATH")
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// Export returns every task of taskwarrior, with `task export`. Confirmations and hooks are
+// turned off so that the output is only JSON.
+func Export(ctx context.Context, run Runner) ([]Task, error) {
+	data, err := run(ctx, "rc.confirmation=off", "rc.hooks=off", "rc.json.array=on", "rc.verbose=nothing", "export")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run task export: %w", err)
+	}
+
+	var tasks []Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to parse task export: %w", err)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,151 @@
+package taskwarrior
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/output"
+)
+
+func TestFromTodoItem(t *testing.T) {
+	due := time.Date(2024, 3, 12, 17, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	task := FromTodoItem("jira", output.TodoItem{
+		ID:      "jira-PROJ-1",
+		Key:     "PROJ-1",
+		Title:   "Fix login",
+		Tags:    []string{"backend", "needs review", " "},
+		DueDate: &due,
+	})
+
+	if task.Description != "PROJ-1: Fix login" {
+		t.Errorf("Expected the key before the title, got %q", task.Description)
+	}
+	if task.Project != "jira" || task.Status != StatusPending || task.DailyID != "jira-PROJ-1" {
+		t.Errorf("Expected a pending jira task carrying the item ID, got %+v", task)
+	}
+	if !slices.Equal(task.Tags, []string{"backend", "needs_review"}) {
+		t.Errorf("Expected tags without spaces, got %v", task.Tags)
+	}
+	if task.Due != "20240312T160000Z" {
+		t.Errorf("Expected the due date in UTC, got %q", task.Due)
+	}
+
+	task = FromTodoItem("obsidian", output.TodoItem{ID: "obsidian-task-notes/inbox.md:3", Title: "Water the plants"})
+	if task.Description != "Water the plants" || task.Project != "obsidian" || task.Due != "" || task.Tags != nil {
+		t.Errorf("Expected an obsidian task without due date nor tags, got %+v", task)
+	}
+
+	// Comments on a page share its URL
+	task = FromTodoItem("confluence", output.TodoItem{ID: "12345", Title: "Comment", URL: "https://acme.atlassian.net/wiki/pages/12300"})
+	if task.Project != "confluence" || task.DailyID != "12345" || task.UUID != taskUUID(task.DailyID) {
+		t.Errorf("Expected a confluence task keyed on the ID, got %+v", task)
+	}
+}
+
+func TestFromTodoItems(t *testing.T) {
+	projects := []Project{
+		{Name: "jira", Items: []output.TodoItem{
+			{ID: "jira-PROJ-1", Title: "Epic", Subtasks: []output.TodoItem{{ID: "jira-PROJ-2", Title: "Subtask"}}},
+			{ID: "jira-PROJ-1", Title: "Epic"}, // Also watched
+		}},
+		{Name: "obsidian", Items: []output.TodoItem{
+			{ID: "obsidian-task-inbox.md:3", Title: "Water the plants", URL: "obsidian://open?vault=notes&file=inbox.md"},
+			{ID: "obsidian-task-inbox.md:4", Title: "Call the plumber", URL: "obsidian://open?vault=notes&file=inbox.md"},
+		}},
+		{Name: "github", Items: []output.TodoItem{
+			{ID: "github-pr-3", Title: "Add caching", URL: "https://github.com/acme/api/pull/3"},
+			{ID: "github-pr-3", Title: "Fix docs", URL: "https://github.com/acme/docs/pull/3"},
+		}},
+	}
+
+	tasks := FromTodoItems(projects)
+
+	var ids, names []string
+	for _, task := range tasks {
+		ids = append(ids, task.DailyID)
+		names = append(names, task.Project)
+	}
+	if strings.Join(ids, ",") != "jira-PROJ-1,jira-PROJ-2,obsidian-task-inbox.md:3,obsidian-task-inbox.md:4,https://github.com/acme/api/pull/3,https://github.com/acme/docs/pull/3" {
+		t.Errorf("Expected each item and subtask once, got %v", ids)
+	}
+	if strings.Join(names, ",") != "jira,jira,obsidian,obsidian,github,github" {
+		t.Errorf("Expected the project of each item, subtasks included, got %v", names)
+	}
+	if tasks[2].UUID == tasks[3].UUID {
+		t.Error("Expected different UUIDs for the tasks of a note")
+	}
+	if tasks[4].UUID == tasks[5].UUID {
+		t.Error("Expected different UUIDs for PRs with the same number in different repositories")
+	}
+
+	if tasks := FromTodoItems(nil); tasks == nil || len(tasks) != 0 {
+		t.Errorf("Expected an empty list, got %v", tasks)
+	}
+}
+
+func TestTaskUUID(t *testing.T) {
+	uuid := taskUUID("jira-PROJ-1")
+
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uuid) {
+		t.Errorf("Expected a version 5 UUID, got %q", uuid)
+	}
+	if taskUUID("jira-PROJ-1") != uuid {
+		t.Error("Expected the same UUID for the same item")
+	}
+	if taskUUID("jira-PROJ-2") == uuid {
+		t.Error("Expected different UUIDs for different items")
+	}
+}
+
+func TestCompleted(t *testing.T) {
+	tasks := []Task{
+		{Description: "Fix login", Status: StatusCompleted, DailyID: "jira-PROJ-1"},
+		{Description: "Fix login", Status: StatusCompleted, DailyID: "jira-PROJ-1"},
+		{Description: "Add caching", Status: StatusCompleted, DailyID: "github-pr-3"},
+		{Description: "Add caching", Status: StatusPending, DailyID: "github-pr-3"}, // Exported again
+		{Description: "Rotate keys", Status: StatusPending, DailyID: "jira-OPS-2"},
+		{Description: "Old plan", Status: "deleted", DailyID: "jira-OPS-3"},
+		{Description: "Buy milk", Status: StatusCompleted},
+	}
+
+	completed := Completed(tasks)
+
+	if len(completed) != 1 || completed[0].DailyID != "jira-PROJ-1" {
+		t.Errorf("Expected only the completed item without pending task, got %+v", completed)
+	}
+}
+
+func TestExport(t *testing.T) {
+	var args []string
+	run := func(_ context.Context, a ...string) ([]byte, error) {
+		args = a
+		return []byte(`[{"id":0,"uuid":"8f2c","description":"Fix login","status":"completed","entry":"20240310T090000Z","daily_id":"jira-PROJ-1","urgency":0}]`), nil
+	}
+
+	tasks, err := Export(context.Background(), run)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].DailyID != "jira-PROJ-1" || tasks[0].Status != StatusCompleted {
+		t.Errorf("Expected the completed task with its daily ID, got %+v", tasks)
+	}
+	if args[len(args)-1] != "export" {
+		t.Errorf("Expected task export, got %v", args)
+	}
+
+	failing := func(context.Context, ...string) ([]byte, error) { return nil, errors.New("task not found") }
+	if _, err := Export(context.Background(), failing); err == nil || !strings.Contains(err.Error(), "failed to run task export: task not found") {
+		t.Errorf("Expected the runner error, got %v", err)
+	}
+
+	invalid := func(context.Context, ...string) ([]byte, error) { return []byte("Configuration override"), nil }
+	if _, err := Export(context.Background(), invalid); err == nil || !strings.Contains(err.Error(), "failed to parse task export") {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+}
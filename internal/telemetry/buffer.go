@@ -0,0 +1,213 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Usage is one run of a command
+type Usage struct {
+	Command   string   // Top-level command, e.g. "todo"
+	Output    string   // Output format, empty for commands without one
+	Providers []string // Types of the enabled providers
+	Err       error    // Error the command failed with, nil when it succeeded
+}
+
+// Buffer holds the usage counted locally since the last batch was sent
+type Buffer struct {
+	Since       time.Time      `json:"since,omitzero"`        // When the first usage of the batch was counted
+	LastSent    time.Time      `json:"last_sent,omitzero"`    // When the last batch was sent
+	LastAttempt time.Time      `json:"last_attempt,omitzero"` // When sending the batch last failed
+	Commands    map[string]int `json:"commands,omitempty"`
+	Providers   map[string]int `json:"providers,omitempty"` // Runs with each provider enabled
+	Outputs     map[string]int `json:"outputs,omitempty"`
+	Errors      map[string]int `json:"errors,omitempty"` // Failed runs by error category
+}
+
+// Add counts a run of a command at now
+func (b *Buffer) Add(usage Usage, now time.Time) {
+	if b.Since.IsZero() {
+		b.Since = now
+	}
+	increment(&b.Commands, known(usage.Command, Commands))
+	for _, provider := range usage.Providers {
+		increment(&b.Providers, known(provider, Providers))
+	}
+	if usage.Output != "" {
+		increment(&b.Outputs, known(usage.Output, Outputs))
+	}
+	if usage.Err != nil {
+		increment(&b.Errors, Categorize(usage.Err))
+	}
+}
+
+// increment adds one to the count of key, creating the counts if needed
+func increment(counts *map[string]int, key string) {
+	if *counts == nil {
+		*counts = make(map[string]int)
+	}
+	(*counts)[key]++
+}
+
+// Empty reports whether no usage was counted since the last batch
+func (b Buffer) Empty() bool {
+	return len(b.Commands) == 0
+}
+
+// Due reports whether the buffer holds usage to send, the last batch was sent at least
+// SendInterval ago and no attempt failed in the last RetryInterval
+func (b Buffer) Due(now time.Time) bool {
+	return !b.Empty() && !now.Before(b.NextBatch())
+}
+
+// NextBatch returns when the counted usage can be sent
+func (b Buffer) NextBatch() time.Time {
+	next := b.LastSent.Add(SendInterval)
+	if retry := b.LastAttempt.Add(RetryInterval); retry.After(next) {
+		next = retry
+	}
+	return next
+}
+
+// Sent clears the counts once their batch was sent at now
+func (b *Buffer) Sent(now time.Time) {
+	*b = Buffer{LastSent: now}
+}
+
+// Payload is the batch of usage counts sent to the telemetry endpoint
+type Payload struct {
+	Version   int            `json:"version"`
+	From      string         `json:"from"` // Day the first usage was counted, e.g. 2024-01-15
+	To        string         `json:"to"`   // Day the batch is sent
+	Commands  map[string]int `json:"commands"`
+	Providers map[string]int `json:"providers"`
+	Outputs   map[string]int `json:"outputs"`
+	Errors    map[string]int `json:"errors"`
+}
+
+// Payload returns the batch of the buffer sent at now. Its counts are checked against the
+// known values again, so that a buffer file edited by hand can't send anything else either.
+func (b Buffer) Payload(now time.Time) Payload {
+	from := b.Since
+	if from.IsZero() {
+		from = now
+	}
+	return Payload{
+		Version:   PayloadVersion,
+		From:      from.UTC().Format(time.DateOnly),
+		To:        now.UTC().Format(time.DateOnly),
+		Commands:  knownCounts(b.Commands, Commands),
+		Providers: knownCounts(b.Providers, Providers),
+		Outputs:   knownCounts(b.Outputs, Outputs),
+		Errors:    knownCounts(b.Errors, ErrorCategories),
+	}
+}
+
+// knownCounts returns the positive counts, with the unknown keys added up as Other
+func knownCounts(counts map[string]int, values []string) map[string]int {
+	result := make(map[string]int)
+	for key, count := range counts {
+		if count > 0 {
+			result[known(key, values)] += count
+		}
+	}
+	return result
+}
+
+// Marshal returns the JSON document of the payload, exactly as it is sent
+func (p Payload) Marshal() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// Send POSTs the payload to endpoint
+func Send(ctx context.Context, client *http.Client, endpoint string, payload Payload) error {
+	data, err := payload.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal telemetry: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Store persists the buffer in a JSON file
+type Store struct {
+	path string
+}
+
+// NewStore returns a store writing to path
+func NewStore(path string) Store {
+	return Store{path: path}
+}
+
+// DefaultBufferPath returns the file next to the cached summaries buffering the usage counts
+func DefaultBufferPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "daily", "cache", "telemetry.json"), nil
+}
+
+// Load returns the stored buffer, or an empty buffer when nothing was stored yet
+func (s Store) Load() (Buffer, error) {
+	var buffer Buffer
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return buffer, nil
+	}
+	if err != nil {
+		return buffer, fmt.Errorf("failed to read telemetry buffer: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &buffer); err != nil {
+		return buffer, fmt.Errorf("failed to parse telemetry buffer: %w", err)
+	}
+	return buffer, nil
+}
+
+// Save writes the buffer, creating the cache directory if needed
+func (s Store) Save(buffer Buffer) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(buffer, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal telemetry buffer: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write telemetry buffer: %w", err)
+	}
+	return nil
+}
+
+// Clear removes the buffer file, wiping the usage not sent yet
+func (s Store) Clear() error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove telemetry buffer: %w", err)
+	}
+	return nil
+}
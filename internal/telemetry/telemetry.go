@@ -0,0 +1,123 @@
+// Package telemetry counts which commands, providers and output formats of daily are used,
+// and which kinds of errors they fail with, for users who opted in. Only values from fixed
+// lists are ever counted, anything else is counted as "other": titles, URLs, usernames,
+// tokens and error messages can't end up in what is sent.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PayloadVersion is the version of the payload sent, bumped when its fields change
+const PayloadVersion = 1
+
+// SendInterval is the minimum time between two batches
+const SendInterval = 24 * time.Hour
+
+// RetryInterval is the time before sending a batch again when it failed, so that an
+// unreachable endpoint doesn't slow down every command
+const RetryInterval = time.Hour
+
+// Other counts the values that aren't in the known lists
+const Other = "other"
+
+// Commands are the top-level commands counted by name, other commands and aliases
+// that failed before running their command are counted as Other
+var Commands = []string{
+	"cache", "completion", "config", "explain", "export", "goal", "help", "hide", "mentions", "notify",
+	"providers", "reviews", "schema", "state", "sum", "sync", "telemetry", "todo", "tray", "unhide", "up-next",
+}
+
+// Providers are the provider types counted by name
+var Providers = []string{"calendar", "confluence", "github", "gitlab", "jira", "obsidian", "saved_queries", "slack"}
+
+// Outputs are the output formats counted by name
+var Outputs = []string{"json", "text", "tui"}
+
+// Error categories, the only thing counted about the error a command failed with
+const (
+	ErrorUsage     = "usage"      // Unknown command or flag, invalid flag value
+	ErrorConfig    = "config"     // The config file couldn't be read or is invalid
+	ErrorAuth      = "auth"       // An API answered 401 or 403
+	ErrorNotFound  = "not_found"  // An API answered 404
+	ErrorRateLimit = "rate_limit" // An API answered 429
+	ErrorServer    = "server"     // An API answered 5xx
+	ErrorAPI       = "api"        // An API answered another error status
+	ErrorTimeout   = "timeout"
+	ErrorCanceled  = "canceled"
+	ErrorNetwork   = "network" // A request couldn't reach its server
+)
+
+// ErrorCategories are the error categories counted by name
+var ErrorCategories = []string{
+	ErrorAPI, ErrorAuth, ErrorCanceled, ErrorConfig, ErrorNetwork, ErrorNotFound,
+	ErrorRateLimit, ErrorServer, ErrorTimeout, ErrorUsage,
+}
+
+// statusPattern finds the HTTP status in the errors of the providers, e.g. "GitHub API
+// request failed with status 401"
+var statusPattern = regexp.MustCompile(`status (\d{3})\b`)
+
+// usagePrefixes start the errors of cobra and of the commands about their arguments and flags
+var usagePrefixes = []string{
+	"unknown command", "unknown flag", "unknown shorthand flag", "invalid argument",
+	"accepts ", "requires at least", "invalid output format", "flag needs an argument",
+}
+
+// Categorize returns the category of an error, Other when it has none. The error message
+// is only matched, it is never counted.
+func Categorize(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCanceled
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return ErrorTimeout
+		}
+		return ErrorNetwork
+	}
+
+	message := err.Error()
+	if match := statusPattern.FindStringSubmatch(message); match != nil {
+		status, _ := strconv.Atoi(match[1])
+		switch {
+		case status == 401 || status == 403:
+			return ErrorAuth
+		case status == 404:
+			return ErrorNotFound
+		case status == 429:
+			return ErrorRateLimit
+		case status >= 500:
+			return ErrorServer
+		case status >= 400:
+			return ErrorAPI
+		}
+	}
+	for _, prefix := range usagePrefixes {
+		if strings.HasPrefix(message, prefix) {
+			return ErrorUsage
+		}
+	}
+	if strings.Contains(message, "config") {
+		return ErrorConfig
+	}
+	return Other
+}
+
+// known returns value when it is one of the known values, Other otherwise
+func known(value string, values []string) string {
+	if slices.Contains(values, value) {
+		return value
+	}
+	return Other
+}
@@ -0,0 +1,266 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/provider"
+)
+
+func TestCategorize(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"timeout", fmt.Errorf("failed to get PRs: %w", context.DeadlineExceeded), ErrorTimeout},
+		{"canceled", context.Canceled, ErrorCanceled},
+		{"network", fmt.Errorf("failed: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), ErrorNetwork},
+		{"unauthorized", errors.New("GitHub API request failed with status 401"), ErrorAuth},
+		{"forbidden", errors.New("Confluence API returned status 403: 403 Forbidden"), ErrorAuth},
+		{"not found", errors.New("API request failed with status 404"), ErrorNotFound},
+		{"rate limited", errors.New("GitHub API request failed with status 429"), ErrorRateLimit},
+		{"server error", errors.New("GitHub GraphQL request failed with status 502"), ErrorServer},
+		{"other API error", errors.New("GitHub API request failed with status 422"), ErrorAPI},
+		{"unknown flag", errors.New("unknown flag: --nope"), ErrorUsage},
+		{"invalid output format", errors.New("invalid output format: xml (must be 'text', 'json', or 'tui')"), ErrorUsage},
+		{"config", errors.New("failed to load config: invalid config: jira.timezone: unknown timezone"), ErrorConfig},
+		{"anything else", errors.New("something broke"), Other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Categorize(tt.err); got != tt.expected {
+				t.Errorf("Expected category %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuffer_AddAndPayload(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	var buffer Buffer
+	buffer.Add(Usage{Command: "todo", Output: "tui", Providers: []string{"github", "jira"}}, start)
+	buffer.Add(Usage{Command: "todo", Output: "json", Providers: []string{"github"}, Err: errors.New("GitHub API request failed with status 401")}, start.Add(time.Hour))
+	buffer.Add(Usage{Command: "config"}, start.Add(26*time.Hour))
+
+	payload := buffer.Payload(start.Add(30 * time.Hour))
+	expected := Payload{
+		Version:   PayloadVersion,
+		From:      "2024-01-15",
+		To:        "2024-01-16",
+		Commands:  map[string]int{"todo": 2, "config": 1},
+		Providers: map[string]int{"github": 2, "jira": 1},
+		Outputs:   map[string]int{"tui": 1, "json": 1},
+		Errors:    map[string]int{ErrorAuth: 1},
+	}
+	if !reflect.DeepEqual(payload, expected) {
+		t.Errorf("Expected payload %+v, got %+v", expected, payload)
+	}
+}
+
+func TestBuffer_Due(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	counted := func(change func(b *Buffer)) Buffer {
+		b := Buffer{Commands: map[string]int{"sum": 1}}
+		change(&b)
+		return b
+	}
+
+	tests := []struct {
+		name     string
+		buffer   Buffer
+		expected bool
+	}{
+		{"nothing counted", Buffer{}, false},
+		{"never sent", counted(func(b *Buffer) {}), true},
+		{"sent today", counted(func(b *Buffer) { b.LastSent = now.Add(-2 * time.Hour) }), false},
+		{"sent yesterday", counted(func(b *Buffer) { b.LastSent = now.Add(-SendInterval) }), true},
+		{"failed recently", counted(func(b *Buffer) { b.LastAttempt = now.Add(-time.Minute) }), false},
+		{"failed an hour ago", counted(func(b *Buffer) { b.LastAttempt = now.Add(-RetryInterval) }), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.buffer.Due(now); got != tt.expected {
+				t.Errorf("Expected due %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// configuredSecrets returns the values of a configuration that must never be sent
+func configuredSecrets() (provider.Config, []string) {
+	cfg := provider.Config{
+		Username:     "octo-private-user",
+		Email:        "jane.doe@acme.example",
+		Token:        "ghp_s3cr3tT0k3nValue",
+		URL:          "https://acme-internal.atlassian.net",
+		Filter:       "project = SECRETPROJ",
+		Repos:        []string{"acme/secret-repo"},
+		ReviewTeams:  []string{"acme/secret-team"},
+		ExcludeTeams: []string{"acme/hidden-team"},
+	}
+	secrets := []string{cfg.Username, cfg.Email, cfg.Token, cfg.URL, cfg.Filter, "SECRETPROJ", "acme"}
+	return cfg, secrets
+}
+
+func TestPayload_NeverContainsConfiguredSecrets(t *testing.T) {
+	cfg, secrets := configuredSecrets()
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	var buffer Buffer
+	// Every value a usage can hold is set to a configured value, as when an alias or a flag
+	// is named after one, and errors quote them
+	for _, value := range append(slices.Clone(secrets), cfg.Repos[0], cfg.ReviewTeams[0]) {
+		buffer.Add(Usage{
+			Command:   value,
+			Output:    value,
+			Providers: []string{value, "github"},
+			Err:       fmt.Errorf("failed to get %s for %s with token %s at %s: status 401", value, cfg.Username, cfg.Token, cfg.URL),
+		}, now)
+		buffer.Add(Usage{Command: "todo", Err: errors.New(value)}, now)
+	}
+
+	// A buffer file edited by hand can't smuggle values in either
+	data, err := json.Marshal(buffer)
+	if err != nil {
+		t.Fatalf("Failed to marshal buffer: %v", err)
+	}
+	var tampered Buffer
+	if err := json.Unmarshal(data, &tampered); err != nil {
+		t.Fatalf("Failed to parse buffer: %v", err)
+	}
+	for _, value := range secrets {
+		tampered.Commands[value] = 1
+		tampered.Errors[value] = 1
+		tampered.Outputs[value] = 1
+		tampered.Providers[value] = 1
+	}
+
+	for name, b := range map[string]Buffer{"counted": buffer, "tampered": tampered} {
+		t.Run(name, func(t *testing.T) {
+			payload := b.Payload(now)
+			sent, err := payload.Marshal()
+			if err != nil {
+				t.Fatalf("Failed to marshal payload: %v", err)
+			}
+			for _, secret := range secrets {
+				if strings.Contains(string(sent), secret) {
+					t.Errorf("Expected the payload not to contain %q, got:\n%s", secret, sent)
+				}
+			}
+			assertKnownPayload(t, payload)
+		})
+	}
+}
+
+// assertKnownPayload checks that every field of the payload only holds counts of known values
+// or dates, so that a field added later has to be listed here to be sent
+func assertKnownPayload(t *testing.T, payload Payload) {
+	t.Helper()
+	known := map[string][]string{
+		"Commands":  append(slices.Clone(Commands), Other),
+		"Providers": append(slices.Clone(Providers), Other),
+		"Outputs":   append(slices.Clone(Outputs), Other),
+		"Errors":    append(slices.Clone(ErrorCategories), Other),
+	}
+
+	value := reflect.ValueOf(payload)
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		switch field.Name {
+		case "Version":
+		case "From", "To":
+			if _, err := time.Parse(time.DateOnly, value.Field(i).String()); err != nil {
+				t.Errorf("Expected %s to be a date, got %q", field.Name, value.Field(i).String())
+			}
+		default:
+			values, ok := known[field.Name]
+			if !ok {
+				t.Fatalf("Payload field %s isn't checked for secrets", field.Name)
+			}
+			for key := range value.Field(i).Interface().(map[string]int) {
+				if !slices.Contains(values, key) {
+					t.Errorf("Expected %s keys to be known values, got %q", field.Name, key)
+				}
+			}
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	var received Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected a JSON POST, got %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("Failed to parse payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	payload := Payload{Version: PayloadVersion, From: "2024-01-15", To: "2024-01-16", Commands: map[string]int{"sum": 3}}
+	if err := Send(context.Background(), server.Client(), server.URL, payload); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if received.Commands["sum"] != 3 || received.From != "2024-01-15" {
+		t.Errorf("Expected the payload to be sent, got %+v", received)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	if err := Send(context.Background(), failing.Client(), failing.URL, payload); err == nil {
+		t.Error("Expected an error when the endpoint fails")
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "telemetry.json")
+	store := NewStore(path)
+
+	buffer, err := store.Load()
+	if err != nil || !buffer.Empty() {
+		t.Fatalf("Expected an empty buffer before anything is stored, got %+v, %v", buffer, err)
+	}
+
+	buffer.Add(Usage{Command: "sum", Output: "text"}, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+	if err := store.Save(buffer); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, buffer) {
+		t.Errorf("Expected buffer %+v, got %+v", buffer, loaded)
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the buffer file to be removed, got %v", err)
+	}
+	if err := store.Clear(); err != nil {
+		t.Errorf("Expected clearing a missing buffer to succeed, got: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+// Package terminal detects what the terminal daily writes to can display.
+package terminal
+
+import (
+	"io"
+	"os"
+
+	"github.com/charmbracelet/colorprofile"
+)
+
+// dumbTerm is the TERM of serial consoles and editors' shells that can't handle escape codes
+const dumbTerm = "dumb"
+
+// Capabilities describes what an output terminal can display
+type Capabilities struct {
+	Profile colorprofile.Profile
+	Dumb    bool // TERM=dumb, no cursor movement nor escape codes
+}
+
+// Plain reports whether output should skip styles and borders: on dumb terminals and
+// terminals without color support
+func (c Capabilities) Plain() bool {
+	return c.Dumb || c.Profile == colorprofile.Ascii
+}
+
+// Detect queries the color profile of output from the environment variables and terminfo
+func Detect(output io.Writer, env []string) Capabilities {
+	caps := Capabilities{Profile: colorprofile.Detect(output, env)}
+	for _, kv := range env {
+		if kv == "TERM="+dumbTerm {
+			caps.Dumb = true
+		}
+	}
+	return caps
+}
+
+// Stdout returns the capabilities of the standard output
+func Stdout() Capabilities {
+	return Detect(os.Stdout, os.Environ())
+}
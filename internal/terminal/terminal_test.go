@@ -0,0 +1,55 @@
+package terminal
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/charmbracelet/colorprofile"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name            string
+		env             []string
+		expectedProfile colorprofile.Profile
+		expectedPlain   bool
+	}{
+		{name: "dumb terminal", env: []string{"TTY_FORCE=1", "TERM=dumb"}, expectedProfile: colorprofile.NoTTY, expectedPlain: true},
+		{name: "dumb terminal with forced colors", env: []string{"TTY_FORCE=1", "TERM=dumb", "CLICOLOR_FORCE=1"}, expectedProfile: colorprofile.ANSI, expectedPlain: true},
+		{name: "no color", env: []string{"TTY_FORCE=1", "TERM=xterm-256color", "NO_COLOR=1"}, expectedProfile: colorprofile.Ascii, expectedPlain: true},
+		{name: "256 colors", env: []string{"TTY_FORCE=1", "TERM=xterm-256color"}, expectedProfile: colorprofile.ANSI256},
+		{name: "true color", env: []string{"TTY_FORCE=1", "TERM=xterm", "COLORTERM=truecolor"}, expectedProfile: colorprofile.TrueColor},
+		{name: "not a terminal", env: []string{"TERM=xterm-256color"}, expectedProfile: colorprofile.NoTTY},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caps := Detect(&bytes.Buffer{}, tt.env)
+			if caps.Profile != tt.expectedProfile {
+				t.Errorf("Expected profile %s, got %s", tt.expectedProfile, caps.Profile)
+			}
+			if caps.Plain() != tt.expectedPlain {
+				t.Errorf("Expected plain %v, got %v", tt.expectedPlain, caps.Plain())
+			}
+		})
+	}
+}
+
+func TestCapabilities_Plain(t *testing.T) {
+	tests := []struct {
+		caps     Capabilities
+		expected bool
+	}{
+		{Capabilities{Profile: colorprofile.TrueColor}, false},
+		{Capabilities{Profile: colorprofile.ANSI}, false},
+		{Capabilities{Profile: colorprofile.NoTTY}, false},
+		{Capabilities{Profile: colorprofile.Ascii}, true},
+		{Capabilities{Profile: colorprofile.ANSI256, Dumb: true}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.caps.Plain(); got != tt.expected {
+			t.Errorf("Expected Plain() = %v for %+v, got %v", tt.expected, tt.caps, got)
+		}
+	}
+}
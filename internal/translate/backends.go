@@ -0,0 +1,201 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"daily/internal/metrics"
+)
+
+// Backends, by name in the config
+const (
+	BackendDictionary = "dictionary" // Words and phrases of the config, no translation without any
+	BackendDeepL      = "deepl"
+)
+
+// Dictionary translates the words and phrases it knows, leaving the others as they are
+type Dictionary struct {
+	entries  map[string]string // Translations by lowercase word or phrase
+	patterns []*regexp.Regexp  // Whole-word patterns of the entries, longest first
+}
+
+// NewDictionary returns a dictionary of translations by word or phrase, matched without case
+func NewDictionary(entries map[string]string) *Dictionary {
+	d := &Dictionary{entries: make(map[string]string, len(entries))}
+	keys := make([]string, 0, len(entries))
+	for phrase, translation := range entries {
+		key := strings.ToLower(strings.TrimSpace(phrase))
+		if key == "" {
+			continue
+		}
+		d.entries[key] = translation
+		keys = append(keys, key)
+	}
+	// Longer phrases first, so that "mise à jour" wins over "jour"
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		d.patterns = append(d.patterns, regexp.MustCompile(`(?i)(^|[^\pL\pN_])(`+regexp.QuoteMeta(key)+`)($|[^\pL\pN_])`))
+	}
+	return d
+}
+
+// Translate replaces the known words and phrases of the texts, the target being the one of
+// the dictionary
+func (d *Dictionary) Translate(_ context.Context, texts []string, _ string) ([]string, error) {
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		translated[i] = d.translate(text)
+	}
+	return translated, nil
+}
+
+// translate replaces the known words and phrases of a text, each part of the text once
+func (d *Dictionary) translate(text string) string {
+	// Translations are swapped for placeholders first, so that they aren't translated again
+	var replacements []string
+	for _, pattern := range d.patterns {
+		text = pattern.ReplaceAllStringFunc(text, func(match string) string {
+			parts := pattern.FindStringSubmatch(match)
+			replacements = append(replacements, d.entries[strings.ToLower(parts[2])])
+			return parts[1] + fmt.Sprintf("\x00%d\x00", len(replacements)-1) + parts[3]
+		})
+	}
+	for i, replacement := range replacements {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), replacement, 1)
+	}
+	return text
+}
+
+// DeepL API endpoints, the free API having keys ending with ":fx"
+const (
+	deepLURL     = "https://api.deepl.com/v2/translate"
+	deepLFreeURL = "https://api-free.deepl.com/v2/translate"
+)
+
+// DeepL translates texts with the DeepL API
+type DeepL struct {
+	key    string
+	url    string
+	client *http.Client
+}
+
+// NewDeepL returns a DeepL backend authenticated with an API key, sending requests to apiURL,
+// or to the endpoint of the key when empty
+func NewDeepL(key, apiURL string) *DeepL {
+	if apiURL == "" {
+		apiURL = deepLURL
+		if strings.HasSuffix(key, ":fx") {
+			apiURL = deepLFreeURL
+		}
+	}
+	return &DeepL{
+		key: key,
+		url: apiURL,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: metrics.NewTransport("deepl"),
+		},
+	}
+}
+
+// codeTag marks the code words of the texts sent to DeepL, which leaves them untranslated
+const codeTag = "x"
+
+// Translate sends the texts to DeepL in a single request. Code words within the texts, e.g.
+// repository names, are marked so that DeepL keeps them as they are.
+func (d *DeepL) Translate(ctx context.Context, texts []string, target string) ([]string, error) {
+	request := struct {
+		Text        []string `json:"text"`
+		TargetLang  string   `json:"target_lang"`
+		TagHandling string   `json:"tag_handling"`
+		IgnoreTags  []string `json:"ignore_tags"`
+	}{
+		TargetLang:  strings.ToUpper(target),
+		TagHandling: "xml",
+		IgnoreTags:  []string{codeTag},
+	}
+	for _, text := range texts {
+		request.Text = append(request.Text, markCode(text))
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal DeepL request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", d.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DeepL request: %w", err)
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+d.key)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute DeepL request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DeepL response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("DeepL API returned status %d: %s", resp.StatusCode, apiErr.Message)
+		}
+		return nil, fmt.Errorf("DeepL API returned status %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	var response struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse DeepL response: %w", err)
+	}
+
+	translated := make([]string, len(response.Translations))
+	for i, translation := range response.Translations {
+		translated[i] = unmarkCode(translation.Text)
+	}
+	return translated, nil
+}
+
+// markCode escapes a text for DeepL's XML handling and wraps its code words in codeTag,
+// punctuation left out
+func markCode(text string) string {
+	fields := strings.Split(text, " ")
+	for i, word := range fields {
+		escaped := html.EscapeString(word)
+		if strings.ContainsFunc(word, unicode.IsLetter) && isCodeWord(word) {
+			escaped = "<" + codeTag + ">" + escaped + "</" + codeTag + ">"
+		}
+		fields[i] = escaped
+	}
+	return strings.Join(fields, " ")
+}
+
+// unmarkCode drops the codeTag marks of a translation and unescapes it
+func unmarkCode(text string) string {
+	text = strings.NewReplacer("<"+codeTag+">", "", "</"+codeTag+">", "").Replace(text)
+	return html.UnescapeString(text)
+}
@@ -0,0 +1,142 @@
+package translate
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// stopwords are frequent words of titles by language, which tell the language of a title
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "for", "with", "on", "is", "from", "by", "at", "an", "this", "that",
+		"not", "are", "add", "fix", "update", "remove", "use", "when", "should", "new", "into", "after"},
+	"fr": {"le", "la", "les", "des", "du", "et", "pour", "avec", "sur", "une", "dans", "est", "pas", "au",
+		"aux", "ajouter", "ajout", "corriger", "correction", "mettre", "mise", "jour", "supprimer", "lors",
+		"quand", "ne", "à", "où", "être", "nouveau", "nouvelle", "après"},
+	"de": {"der", "die", "das", "und", "mit", "für", "von", "ist", "nicht", "ein", "eine", "auf", "bei",
+		"zu", "im", "den", "dem", "beheben", "hinzufügen", "aktualisieren", "entfernen", "neue", "nach"},
+	"es": {"el", "la", "los", "las", "y", "para", "con", "por", "una", "que", "añadir", "corregir", "actualizar",
+		"eliminar", "cuando", "nuevo", "nueva", "después", "al"},
+	"it": {"il", "la", "gli", "per", "della", "di", "che", "aggiungere", "correggere", "aggiornare", "rimuovere",
+		"quando", "nuovo", "nuova", "dopo", "non", "nel", "alla"},
+	"pt": {"os", "com", "não", "da", "dos", "das", "em", "adicionar", "corrigir", "atualizar", "remover",
+		"quando", "novo", "nova", "depois", "ao"},
+	"nl": {"het", "een", "van", "voor", "met", "niet", "op", "toevoegen", "verwijderen", "bijwerken",
+		"wanneer", "nieuwe", "na", "bij"},
+}
+
+// letterHints are letters only found in some of the languages, each counting as a stopword
+var letterHints = map[rune]string{
+	'é': "fr", 'è': "fr", 'ê': "fr", 'ç': "fr", 'à': "fr",
+	'ä': "de", 'ö': "de", 'ü': "de", 'ß': "de",
+	'ñ': "es", '¿': "es", '¡': "es",
+	'ã': "pt", 'õ': "pt",
+}
+
+// stopwordLanguages maps each stopword to its languages
+var stopwordLanguages = func() map[string][]string {
+	languages := make(map[string][]string)
+	for language, words := range stopwords {
+		for _, word := range words {
+			languages[word] = append(languages[word], language)
+		}
+	}
+	return languages
+}()
+
+// Detect returns the language code of a text, e.g. "fr", from its stopwords and letters. It
+// returns an empty code when no language stands out.
+func Detect(text string) string {
+	scores := make(map[string]int)
+	for _, word := range words(text) {
+		for _, language := range stopwordLanguages[word] {
+			scores[language]++
+		}
+	}
+	hinted := make(map[rune]bool)
+	for _, r := range strings.ToLower(text) {
+		if language, ok := letterHints[r]; ok && !hinted[r] {
+			hinted[r] = true
+			scores[language]++
+		}
+	}
+
+	best, bestScore, tied := "", 0, false
+	for language, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = language, score, false
+		case score == bestScore:
+			tied = true
+		}
+	}
+	if tied {
+		return ""
+	}
+	return best
+}
+
+// words returns the lowercase words of a text, elisions split, e.g. "l'équipe" as "l" and "équipe"
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// ticketKey matches issue keys, e.g. PROJ-123
+var ticketKey = regexp.MustCompile(`^[A-Z][A-Z0-9]+-\d+$`)
+
+// IsCode reports whether every word of a text looks like code rather than language: ticket
+// keys, repository names and paths, identifiers in snake or camel case, versions...
+func IsCode(text string) bool {
+	for _, word := range strings.Fields(text) {
+		if !isCodeWord(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// isCodeWord reports whether a word looks like code, ignoring the punctuation around it
+func isCodeWord(word string) bool {
+	word = strings.TrimRight(strings.TrimLeft(word, "(["), ":,;)]")
+	if word == "" || ticketKey.MatchString(word) {
+		return true
+	}
+	if !strings.ContainsFunc(word, unicode.IsLetter) {
+		return true // Numbers and symbols, e.g. "#42" or "->"
+	}
+	if strings.ContainsAny(strings.TrimRight(word, ".!?"), "/\\_.#@`=<>{}()[]") {
+		return true
+	}
+	if strings.ContainsFunc(word, unicode.IsDigit) {
+		return true // e.g. "v2" or "k8s"
+	}
+
+	// camelCase identifiers, e.g. "getUser"
+	runes := []rune(word)
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCodePrefix splits the code words starting a text from the rest, e.g. "PROJ-123: " from
+// "Corriger le bug", so that only the rest is translated
+func splitCodePrefix(text string) (prefix, body string) {
+	rest := text
+	for {
+		trimmed := strings.TrimLeft(rest, " ")
+		if trimmed == "" {
+			return text, ""
+		}
+		word, _, _ := strings.Cut(trimmed, " ")
+		if !isCodeWord(word) {
+			offset := len(text) - len(trimmed)
+			return text[:offset], text[offset:]
+		}
+		rest = trimmed[len(word):]
+	}
+}
@@ -0,0 +1,147 @@
+// Package translate shows the titles of items in the configured language: titles detected in
+// another language are translated by a backend, in batches, and the translations are cached
+// by content hash so that each title is only ever sent once. Titles that look like code, e.g.
+// ticket keys or repository names, are never translated.
+package translate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Backend translates texts, e.g. with a translation API
+type Backend interface {
+	// Translate returns the translations of texts into the target language, in order
+	Translate(ctx context.Context, texts []string, target string) ([]string, error)
+}
+
+// Store keeps translations by key, e.g. a cache.TTLStore
+type Store interface {
+	Get(key string, maxAge time.Duration, value any) (bool, error)
+	Set(key string, value any) error
+}
+
+// DefaultBatchSize is the number of texts sent to the backend per request
+const DefaultBatchSize = 50
+
+// CacheMaxAge is how long translations are kept
+const CacheMaxAge = 90 * 24 * time.Hour
+
+// Translator translates the titles of items detected in another language than its target
+type Translator struct {
+	backend   Backend
+	target    string // Language code, e.g. "en"
+	store     Store  // Cached translations, nil to not cache
+	batchSize int
+}
+
+// New returns a translator into target, caching translations in store unless nil and
+// sending at most batchSize texts per request, DefaultBatchSize when not positive
+func New(backend Backend, target string, store Store, batchSize int) *Translator {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Translator{
+		backend:   backend,
+		target:    strings.ToLower(target),
+		store:     store,
+		batchSize: batchSize,
+	}
+}
+
+// Translate returns the texts in the target language, in order. Texts already in the target
+// language, whose language isn't detected or that look like code are returned unchanged. When
+// the backend fails, the texts it didn't translate are returned unchanged with the error.
+func (t *Translator) Translate(ctx context.Context, texts []string) ([]string, error) {
+	translated := make([]string, len(texts))
+	copy(translated, texts)
+
+	// Texts to send, each once, and the indexes of the texts they are the body of
+	var pending []string
+	indexes := make(map[string][]int)
+	for i, text := range texts {
+		prefix, body := splitCodePrefix(text)
+		if !t.needsTranslation(body) {
+			continue
+		}
+		if cached, ok := t.cached(body); ok {
+			translated[i] = prefix + cached
+			continue
+		}
+		if _, ok := indexes[body]; !ok {
+			pending = append(pending, body)
+		}
+		indexes[body] = append(indexes[body], i)
+	}
+
+	for start := 0; start < len(pending); start += t.batchSize {
+		batch := pending[start:min(start+t.batchSize, len(pending))]
+		results, err := t.backend.Translate(ctx, batch, t.target)
+		if err != nil {
+			return translated, fmt.Errorf("failed to translate titles: %w", err)
+		}
+		if len(results) != len(batch) {
+			return translated, fmt.Errorf("failed to translate titles: got %d translations for %d titles", len(results), len(batch))
+		}
+
+		for j, body := range batch {
+			result := strings.TrimSpace(results[j])
+			if result == "" {
+				continue
+			}
+			t.cache(body, result)
+			for _, i := range indexes[body] {
+				prefix, _ := splitCodePrefix(texts[i])
+				translated[i] = prefix + result
+			}
+		}
+	}
+
+	return translated, nil
+}
+
+// needsTranslation reports whether a text is written in a detected language other than the
+// target, and isn't code
+func (t *Translator) needsTranslation(text string) bool {
+	if text == "" || IsCode(text) {
+		return false
+	}
+	language := Detect(text)
+	return language != "" && language != t.baseTarget()
+}
+
+// baseTarget returns the target language without its region, e.g. "en" for "en-gb"
+func (t *Translator) baseTarget() string {
+	language, _, _ := strings.Cut(t.target, "-")
+	return language
+}
+
+// cacheKey returns the key of the translation of text, a hash of the target and the text
+func (t *Translator) cacheKey(text string) string {
+	sum := sha256.Sum256([]byte(t.target + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// cached returns the cached translation of text
+func (t *Translator) cached(text string) (string, bool) {
+	if t.store == nil {
+		return "", false
+	}
+	var translation string
+	found, err := t.store.Get(t.cacheKey(text), CacheMaxAge, &translation)
+	if err != nil || !found {
+		return "", false
+	}
+	return translation, true
+}
+
+// cache stores the translation of text. Failing to cache only costs a request next time.
+func (t *Translator) cache(text, translation string) {
+	if t.store != nil {
+		_ = t.store.Set(t.cacheKey(text), translation)
+	}
+}
@@ -0,0 +1,259 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"daily/internal/cache"
+)
+
+// fakeBackend translates texts to upper case, recording the batches it was sent
+type fakeBackend struct {
+	batches [][]string
+	err     error
+}
+
+func (b *fakeBackend) Translate(_ context.Context, texts []string, target string) ([]string, error) {
+	b.batches = append(b.batches, texts)
+	if b.err != nil {
+		return nil, b.err
+	}
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		translated[i] = strings.ToUpper(text) + " (" + target + ")"
+	}
+	return translated, nil
+}
+
+func TestTranslator_Translate(t *testing.T) {
+	backend := &fakeBackend{}
+	translator := New(backend, "en", nil, 2)
+
+	texts := []string{
+		"Corriger le bug de connexion",
+		"Fix the login bug",                        // Already in English
+		"PROJ-123: Mettre à jour la documentation", // Ticket key kept as is
+		"owner/repo",                               // Code
+		"Corriger le bug de connexion",             // Sent once
+		"Ajouter des tests pour le cache",
+		"Refactoring", // Language not detected
+	}
+	translated, err := translator.Translate(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []string{
+		"CORRIGER LE BUG DE CONNEXION (en)",
+		"Fix the login bug",
+		"PROJ-123: METTRE À JOUR LA DOCUMENTATION (en)",
+		"owner/repo",
+		"CORRIGER LE BUG DE CONNEXION (en)",
+		"AJOUTER DES TESTS POUR LE CACHE (en)",
+		"Refactoring",
+	}
+	if !reflect.DeepEqual(translated, expected) {
+		t.Errorf("Expected %q, got %q", expected, translated)
+	}
+
+	expectedBatches := [][]string{
+		{"Corriger le bug de connexion", "Mettre à jour la documentation"},
+		{"Ajouter des tests pour le cache"},
+	}
+	if !reflect.DeepEqual(backend.batches, expectedBatches) {
+		t.Errorf("Expected batches %q, got %q", expectedBatches, backend.batches)
+	}
+}
+
+func TestTranslator_Cache(t *testing.T) {
+	store := cache.NewTTLStoreAt(filepath.Join(t.TempDir(), "translations.json"))
+	backend := &fakeBackend{}
+	texts := []string{"Corriger le bug de connexion", "Ajouter des tests pour le cache"}
+
+	if _, err := New(backend, "en", store, 0).Translate(context.Background(), texts[:1]); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Another run only sends the title not translated yet
+	translated, err := New(backend, "en", store, 0).Translate(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(backend.batches) != 2 || !reflect.DeepEqual(backend.batches[1], texts[1:]) {
+		t.Errorf("Expected the cached title not to be sent again, got batches %q", backend.batches)
+	}
+	if translated[0] != "CORRIGER LE BUG DE CONNEXION (en)" {
+		t.Errorf("Expected the cached translation, got %q", translated[0])
+	}
+
+	// Translations are cached by target language
+	if _, err := New(backend, "de", store, 0).Translate(context.Background(), texts[:1]); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(backend.batches) != 3 {
+		t.Errorf("Expected the title to be sent again for another language, got batches %q", backend.batches)
+	}
+}
+
+func TestTranslator_BackendError(t *testing.T) {
+	backend := &fakeBackend{err: errors.New("quota exceeded")}
+	texts := []string{"Corriger le bug de connexion", "owner/repo"}
+
+	translated, err := New(backend, "en", nil, 0).Translate(context.Background(), texts)
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("Expected the backend error, got %v", err)
+	}
+	if !reflect.DeepEqual(translated, texts) {
+		t.Errorf("Expected the titles unchanged, got %q", translated)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Fix the login bug", "en"},
+		{"Corriger le bug de connexion", "fr"},
+		{"Mise à jour de l'équipe", "fr"},
+		{"Fehler bei der Anmeldung beheben", "de"},
+		{"Añadir pruebas para el caché", "es"},
+		{"Refactoring", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if result := Detect(tt.text); result != tt.expected {
+			t.Errorf("Detect(%q): expected %q, got %q", tt.text, tt.expected, result)
+		}
+	}
+}
+
+func TestIsCode(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected bool
+	}{
+		{"PROJ-123", true},
+		{"owner/repo", true},
+		{"owner/repo#42", true},
+		{"internal/cache/ttl.go", true},
+		{"snake_case_name", true},
+		{"getUserByID", true},
+		{"v1.2.3", true},
+		{"[PROJ-1] owner/repo", true},
+		{"Corriger le bug", false},
+		{"PROJ-123: Corriger le bug", false},
+		{"API", false},
+		{"peut-être", false},
+	}
+
+	for _, tt := range tests {
+		if result := IsCode(tt.text); result != tt.expected {
+			t.Errorf("IsCode(%q): expected %t, got %t", tt.text, tt.expected, result)
+		}
+	}
+}
+
+func TestSplitCodePrefix(t *testing.T) {
+	tests := []struct {
+		text, prefix, body string
+	}{
+		{"PROJ-123: Corriger le bug", "PROJ-123: ", "Corriger le bug"},
+		{"[owner/repo] PROJ-1 - Ajouter un test", "[owner/repo] PROJ-1 - ", "Ajouter un test"},
+		{"Corriger le bug dans owner/repo", "", "Corriger le bug dans owner/repo"},
+		{"owner/repo", "owner/repo", ""},
+	}
+
+	for _, tt := range tests {
+		prefix, body := splitCodePrefix(tt.text)
+		if prefix != tt.prefix || body != tt.body {
+			t.Errorf("splitCodePrefix(%q): expected %q and %q, got %q and %q", tt.text, tt.prefix, tt.body, prefix, body)
+		}
+	}
+}
+
+func TestDictionary(t *testing.T) {
+	dictionary := NewDictionary(map[string]string{
+		"corriger":    "fix",
+		"mise à jour": "update",
+		"jour":        "day",
+		"de":          "of",
+	})
+
+	translated, err := dictionary.Translate(context.Background(), []string{"Corriger la mise à jour de owner/repo", "Jour de fête"}, "en")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	expected := []string{"fix la update of owner/repo", "day of fête"}
+	if !reflect.DeepEqual(translated, expected) {
+		t.Errorf("Expected %q, got %q", expected, translated)
+	}
+
+	if translated, _ := NewDictionary(nil).Translate(context.Background(), []string{"Corriger le bug"}, "en"); translated[0] != "Corriger le bug" {
+		t.Errorf("Expected an empty dictionary to leave the title as is, got %q", translated[0])
+	}
+}
+
+func TestDeepL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "DeepL-Auth-Key secret" {
+			t.Errorf("Expected the API key, got %q", auth)
+		}
+		var request struct {
+			Text       []string `json:"text"`
+			TargetLang string   `json:"target_lang"`
+			IgnoreTags []string `json:"ignore_tags"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("Expected a JSON request, got: %v", err)
+		}
+		expected := []string{"Corriger le bug de <x>owner/repo</x>", "Tom &amp; Jerry"}
+		if !reflect.DeepEqual(request.Text, expected) || request.TargetLang != "EN" || !reflect.DeepEqual(request.IgnoreTags, []string{"x"}) {
+			t.Errorf("Expected the texts with their code marked, got %+v", request)
+		}
+		_, _ = w.Write([]byte(`{"translations": [
+			{"detected_source_language": "FR", "text": "Fix the bug of <x>owner/repo</x>"},
+			{"detected_source_language": "FR", "text": "Tom &amp; Jerry"}
+		]}`))
+	}))
+	defer server.Close()
+
+	translated, err := NewDeepL("secret", server.URL).Translate(context.Background(), []string{"Corriger le bug de owner/repo", "Tom & Jerry"}, "en")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	expected := []string{"Fix the bug of owner/repo", "Tom & Jerry"}
+	if !reflect.DeepEqual(translated, expected) {
+		t.Errorf("Expected %q, got %q", expected, translated)
+	}
+}
+
+func TestDeepL_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "Wrong endpoint. Use https://api.deepl.com"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewDeepL("secret", server.URL).Translate(context.Background(), []string{"Corriger le bug"}, "en")
+	if err == nil || err.Error() != "DeepL API returned status 403: Wrong endpoint. Use https://api.deepl.com" {
+		t.Errorf("Expected the API error message, got %v", err)
+	}
+}
+
+func TestNewDeepL_FreeKey(t *testing.T) {
+	if backend := NewDeepL("abc:fx", ""); backend.url != deepLFreeURL {
+		t.Errorf("Expected the free API for a free key, got %s", backend.url)
+	}
+	if backend := NewDeepL("abc", ""); backend.url != deepLURL {
+		t.Errorf("Expected the pro API, got %s", backend.url)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !tray
+
+package tray
+
+import (
+	"context"
+	"time"
+)
+
+// Run shows the tray until it is quit or ctx is done. Without the "tray" build tag, it
+// returns ErrUnsupported so that headless builds don't need a desktop toolkit.
+func Run(ctx context.Context, interval time.Duration, refresh func(ctx context.Context) Snapshot, open func(url string)) error {
+	return ErrUnsupported
+}
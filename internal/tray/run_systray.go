@@ -0,0 +1,87 @@
+//go:build tray
+
+package tray
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"time"
+
+	"fyne.io/systray"
+)
+
+// Run shows the tray until it is quit or ctx is done, refreshing its items every interval
+func Run(ctx context.Context, interval time.Duration, refresh func(ctx context.Context) Snapshot, open func(url string)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	onReady := func() {
+		systray.SetIcon(icon())
+		now := make(chan struct{}, 1)
+		actions := Actions{
+			Open: open,
+			Refresh: func() {
+				select {
+				case now <- struct{}{}:
+				default: // A refresh is already requested
+				}
+			},
+			Quit: systray.Quit,
+		}
+
+		go func() {
+			<-ctx.Done()
+			systray.Quit()
+		}()
+		go Watch(ctx, interval, now, refresh, func(snapshot Snapshot) {
+			BuildMenu(nativeSystray{}, snapshot, actions)
+		})
+	}
+
+	systray.Run(onReady, cancel)
+	return nil
+}
+
+// nativeSystray is the Systray of the platform
+type nativeSystray struct{}
+
+func (nativeSystray) SetTitle(title string)     { systray.SetTitle(title) }
+func (nativeSystray) SetTooltip(tooltip string) { systray.SetTooltip(tooltip) }
+func (nativeSystray) ResetMenu()                { systray.ResetMenu() }
+func (nativeSystray) AddSeparator()             { systray.AddSeparator() }
+
+func (nativeSystray) AddItem(title, tooltip string, onClick func()) {
+	item := systray.AddMenuItem(title, tooltip)
+	if onClick == nil {
+		item.Disable()
+		return
+	}
+	// The channel is closed when the menu is reset
+	go func() {
+		for range item.ClickedCh {
+			onClick()
+		}
+	}()
+}
+
+// icon draws the tray icon, a filled circle, as a PNG
+func icon() []byte {
+	const size = 22
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	fill := color.RGBA{R: 0x8a, G: 0xad, B: 0xf4, A: 0xff}
+	for y := range size {
+		for x := range size {
+			dx, dy := x-size/2, y-size/2
+			if dx*dx+dy*dy <= (size/2-2)*(size/2-2) {
+				img.Set(x, y, fill)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
@@ -0,0 +1,156 @@
+// Package tray shows the pending review requests and tickets in the macOS menu bar or the
+// system tray. The menu is built against the Systray interface, so that it can be tested
+// without a desktop; the native tray is only compiled with the "tray" build tag.
+package tray
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"daily/internal/notify"
+)
+
+// maxSectionItems is the number of items listed per section, the others are counted
+const maxSectionItems = 10
+
+// ErrUnsupported is returned by Run when daily was built without the "tray" build tag
+var ErrUnsupported = errors.New("daily was built without tray support, rebuild it with: go build -tags tray")
+
+// Systray is the menu bar or system tray icon and its menu
+type Systray interface {
+	SetTitle(title string)
+	SetTooltip(tooltip string)
+	// ResetMenu removes every item of the menu
+	ResetMenu()
+	// AddItem appends an item to the menu, disabled when onClick is nil
+	AddItem(title, tooltip string, onClick func())
+	AddSeparator()
+}
+
+// Section is the current items of one kind of change, e.g. the pending review requests
+type Section struct {
+	Kind  string // notify.KindReviewRequest, notify.KindJIRAAssigned...
+	Items []notify.Change
+	Err   error // Set when the items couldn't be refreshed
+}
+
+// Snapshot is the content of the tray at one refresh
+type Snapshot []Section
+
+// sectionTitles names the sections in the menu
+var sectionTitles = map[string]string{
+	notify.KindReviewRequest: "Review requests",
+	notify.KindJIRAAssigned:  "Assigned tickets",
+}
+
+// Actions are what the menu items do when clicked
+type Actions struct {
+	Open    func(url string) // Opens the URL of an item
+	Refresh func()           // Refreshes the items now
+	Quit    func()
+}
+
+// Title returns the title of the icon: the number of pending review requests
+func Title(snapshot Snapshot) string {
+	count := 0
+	for _, section := range snapshot {
+		if section.Kind == notify.KindReviewRequest {
+			count += len(section.Items)
+		}
+	}
+	return fmt.Sprintf("👁 %d", count)
+}
+
+// Tooltip counts the items of every section, e.g. "Review requests: 3 · Assigned tickets: 1"
+func Tooltip(snapshot Snapshot) string {
+	if len(snapshot) == 0 {
+		return "daily: no provider configured"
+	}
+	parts := make([]string, len(snapshot))
+	for i, section := range snapshot {
+		parts[i] = fmt.Sprintf("%s: %d", sectionTitle(section.Kind), len(section.Items))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// BuildMenu replaces the title and the menu of the tray with the snapshot: one entry per item
+// opening its URL, grouped by section, then the refresh and quit entries
+func BuildMenu(tray Systray, snapshot Snapshot, actions Actions) {
+	tray.SetTitle(Title(snapshot))
+	tray.SetTooltip(Tooltip(snapshot))
+	tray.ResetMenu()
+
+	for _, section := range snapshot {
+		title := fmt.Sprintf("%s (%d)", sectionTitle(section.Kind), len(section.Items))
+		if section.Err != nil {
+			title = fmt.Sprintf("%s: refresh failed", sectionTitle(section.Kind))
+		}
+		tray.AddItem(title, "", nil)
+
+		for i, item := range section.Items {
+			if i == maxSectionItems {
+				tray.AddItem(fmt.Sprintf("… %d more", len(section.Items)-maxSectionItems), "", nil)
+				break
+			}
+			tray.AddItem(item.Title, item.URL, openAction(item.URL, actions.Open))
+		}
+		tray.AddSeparator()
+	}
+
+	tray.AddItem("Refresh now", "", actions.Refresh)
+	tray.AddItem("Quit", "", actions.Quit)
+}
+
+// sectionTitle returns the name of the section of kind
+func sectionTitle(kind string) string {
+	if title, ok := sectionTitles[kind]; ok {
+		return title
+	}
+	return kind
+}
+
+// openAction returns the click handler opening url, nil when the item has no URL
+func openAction(url string, open func(url string)) func() {
+	if url == "" || open == nil {
+		return nil
+	}
+	return func() { open(url) }
+}
+
+// Changed reports whether the menu built from next differs from the one built from previous
+func Changed(previous, next Snapshot) bool {
+	return !slices.EqualFunc(previous, next, func(a, b Section) bool {
+		return a.Kind == b.Kind && (a.Err == nil) == (b.Err == nil) &&
+			slices.EqualFunc(a.Items, b.Items, func(x, y notify.Change) bool {
+				return x.ID == y.ID && x.Title == y.Title && x.URL == y.URL
+			})
+	})
+}
+
+// Watch calls refresh right away, then every interval and whenever a value is sent on now,
+// until ctx is done. Update is only called with the snapshots that changed the menu.
+func Watch(ctx context.Context, interval time.Duration, now <-chan struct{}, refresh func(ctx context.Context) Snapshot, update func(Snapshot)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous Snapshot
+	first := true
+	for {
+		snapshot := refresh(ctx)
+		if first || Changed(previous, snapshot) {
+			update(snapshot)
+			previous, first = snapshot, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-now:
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package tray
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"daily/internal/notify"
+)
+
+// fakeSystray records the title and the menu built on it
+type fakeSystray struct {
+	title   string
+	tooltip string
+	items   []fakeItem
+	resets  int
+}
+
+// fakeItem is a menu item, "---" for separators
+type fakeItem struct {
+	title   string
+	tooltip string
+	onClick func()
+}
+
+func (f *fakeSystray) SetTitle(title string)     { f.title = title }
+func (f *fakeSystray) SetTooltip(tooltip string) { f.tooltip = tooltip }
+func (f *fakeSystray) AddSeparator()             { f.items = append(f.items, fakeItem{title: "---"}) }
+
+func (f *fakeSystray) ResetMenu() {
+	f.items = nil
+	f.resets++
+}
+
+func (f *fakeSystray) AddItem(title, tooltip string, onClick func()) {
+	f.items = append(f.items, fakeItem{title: title, tooltip: tooltip, onClick: onClick})
+}
+
+func (f *fakeSystray) titles() []string {
+	titles := make([]string, len(f.items))
+	for i, item := range f.items {
+		titles[i] = item.title
+	}
+	return titles
+}
+
+// reviews returns count review requests
+func reviews(count int) []notify.Change {
+	changes := make([]notify.Change, count)
+	for i := range changes {
+		changes[i] = notify.Change{
+			ID:    fmt.Sprintf("github-acme/api#%d", i+1),
+			Kind:  notify.KindReviewRequest,
+			Title: fmt.Sprintf("Review PR %d", i+1),
+			URL:   fmt.Sprintf("https://github.com/acme/api/pull/%d", i+1),
+		}
+	}
+	return changes
+}
+
+func TestBuildMenu(t *testing.T) {
+	snapshot := Snapshot{
+		{Kind: notify.KindReviewRequest, Items: reviews(2)},
+		{Kind: notify.KindJIRAAssigned, Items: []notify.Change{{ID: "PROJ-1", Title: "PROJ-1: Fix login"}}},
+	}
+
+	var opened []string
+	quit := false
+	fake := &fakeSystray{}
+	BuildMenu(fake, snapshot, Actions{
+		Open: func(url string) { opened = append(opened, url) },
+		Quit: func() { quit = true },
+	})
+
+	if fake.title != "👁 2" {
+		t.Errorf("Expected the review request count as title, got '%s'", fake.title)
+	}
+	if fake.tooltip != "Review requests: 2 · Assigned tickets: 1" {
+		t.Errorf("Expected the counts as tooltip, got '%s'", fake.tooltip)
+	}
+	expected := []string{
+		"Review requests (2)", "Review PR 1", "Review PR 2", "---",
+		"Assigned tickets (1)", "PROJ-1: Fix login", "---",
+		"Refresh now", "Quit",
+	}
+	if !slices.Equal(fake.titles(), expected) {
+		t.Fatalf("Expected menu %v, got %v", expected, fake.titles())
+	}
+
+	// Headers and items without URL are disabled, the others open their URL
+	for _, i := range []int{0, 4, 5} {
+		if fake.items[i].onClick != nil {
+			t.Errorf("Expected '%s' to be disabled", fake.items[i].title)
+		}
+	}
+	fake.items[2].onClick()
+	if !slices.Equal(opened, []string{"https://github.com/acme/api/pull/2"}) {
+		t.Errorf("Expected the PR to be opened, got %v", opened)
+	}
+	fake.items[8].onClick()
+	if !quit {
+		t.Error("Expected the quit entry to quit")
+	}
+
+	// Building again replaces the menu
+	BuildMenu(fake, Snapshot{}, Actions{})
+	if fake.resets != 2 || !slices.Equal(fake.titles(), []string{"Refresh now", "Quit"}) || fake.title != "👁 0" {
+		t.Errorf("Expected the menu to be replaced, got %v titled '%s'", fake.titles(), fake.title)
+	}
+}
+
+func TestBuildMenu_LongAndFailedSections(t *testing.T) {
+	snapshot := Snapshot{
+		{Kind: notify.KindReviewRequest, Items: reviews(maxSectionItems + 3)},
+		{Kind: notify.KindJIRAAssigned, Err: errors.New("JIRA API request failed with status 401")},
+	}
+
+	fake := &fakeSystray{}
+	BuildMenu(fake, snapshot, Actions{})
+
+	titles := fake.titles()
+	if len(titles) != maxSectionItems+7 {
+		t.Fatalf("Expected %d items, got %v", maxSectionItems+7, titles)
+	}
+	if titles[maxSectionItems+1] != "… 3 more" {
+		t.Errorf("Expected the other items to be counted, got '%s'", titles[maxSectionItems+1])
+	}
+	if titles[maxSectionItems+3] != "Assigned tickets: refresh failed" {
+		t.Errorf("Expected the failed section, got '%s'", titles[maxSectionItems+3])
+	}
+}
+
+func TestChanged(t *testing.T) {
+	base := Snapshot{{Kind: notify.KindReviewRequest, Items: reviews(2)}}
+	renamed := Snapshot{{Kind: notify.KindReviewRequest, Items: reviews(2)}}
+	renamed[0].Items[1].Title = "Review PR 2 (updated)"
+
+	tests := []struct {
+		name     string
+		next     Snapshot
+		expected bool
+	}{
+		{"same items", Snapshot{{Kind: notify.KindReviewRequest, Items: reviews(2)}}, false},
+		{"new item", Snapshot{{Kind: notify.KindReviewRequest, Items: reviews(3)}}, true},
+		{"renamed item", renamed, true},
+		{"failed", Snapshot{{Kind: notify.KindReviewRequest, Items: reviews(2), Err: errors.New("timeout")}}, true},
+		{"new section", append(base, Section{Kind: notify.KindJIRAAssigned}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Changed(base, tt.next); got != tt.expected {
+				t.Errorf("Expected changed %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The items change at the third refresh only
+	refreshes := 0
+	refresh := func(ctx context.Context) Snapshot {
+		refreshes++
+		if refreshes >= 3 {
+			return Snapshot{{Kind: notify.KindReviewRequest, Items: reviews(1)}}
+		}
+		return Snapshot{{Kind: notify.KindReviewRequest}}
+	}
+
+	var updates []Snapshot
+	now := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		Watch(ctx, time.Hour, now, refresh, func(snapshot Snapshot) {
+			updates = append(updates, snapshot)
+		})
+		close(done)
+	}()
+
+	now <- struct{}{}
+	now <- struct{}{}
+	now <- struct{}{}
+	cancel()
+	<-done
+
+	if refreshes != 4 {
+		t.Errorf("Expected a refresh at start and per request, got %d", refreshes)
+	}
+	if len(updates) != 2 || len(updates[1][0].Items) != 1 {
+		t.Errorf("Expected the first snapshot and the changed one, got %+v", updates)
+	}
+}
@@ -1,15 +1,21 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
 
 	catppuccin "github.com/catppuccin/go"
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/mattn/go-isatty"
+
+	"daily/internal/severity"
+	"daily/internal/terminal"
+	"daily/internal/tui/types"
 )
 
 // CommonStyles contains shared styling for TUI components
@@ -171,6 +177,17 @@ func IsTerminalCapable() bool {
 	return isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
 }
 
+// ErrDumbTerminal is returned when starting a TUI on a terminal that can't handle escape codes
+var ErrDumbTerminal = errors.New("this terminal can't display the interactive view (TERM=dumb), use --output text instead")
+
+// CheckTerminal refuses to start a TUI on dumb terminals
+func CheckTerminal(caps terminal.Capabilities) error {
+	if caps.Dumb {
+		return ErrDumbTerminal
+	}
+	return nil
+}
+
 // MinTerminalSize defines minimum required terminal dimensions
 const (
 	MinTerminalWidth  = 80
@@ -317,6 +334,44 @@ func GetThemeColors() (headerColor, borderColor, helpColor, selectedFg, selected
 		latte.Base().Hex, latte.Blue().Hex, latte.Subtext1().Hex
 }
 
+// severityColor returns the dot color of a severity in the current theme
+func severityColor(level severity.Level) string {
+	var flavor catppuccin.Flavor = catppuccin.Latte
+	if isDarkMode() {
+		flavor = catppuccin.Mocha
+	}
+	switch level {
+	case severity.Critical:
+		return flavor.Red().Hex
+	case severity.High:
+		return flavor.Peach().Hex
+	case severity.Normal:
+		return flavor.Yellow().Hex
+	default:
+		return flavor.Overlay1().Hex
+	}
+}
+
+// severityPrefix returns the prefix of an item title in the lists: a dot colored by its
+// severity, or the marker of the severity (e.g. "[CRIT] ") on terminals without colors.
+// Selected lines keep the dot unstyled so the selection highlight isn't broken. Items
+// without severity have no prefix.
+func severityPrefix(level string, selected, plain bool) string {
+	if level == "" {
+		return ""
+	}
+	if plain {
+		if marker := severity.Level(level).Marker(); marker != "" {
+			return marker + " "
+		}
+		return ""
+	}
+	if selected {
+		return "● "
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(severityColor(severity.Level(level)))).Render("●") + " "
+}
+
 // RenderHeader renders a standard TUI header with theme-appropriate styling
 func RenderHeader(title string, windowWidth int) string {
 	headerColor, _, _, _, _, _ := GetThemeColors()
@@ -382,3 +437,62 @@ func TruncateText(text string, maxWidth int) string {
 
 	return text[:maxWidth-3] + "..."
 }
+
+// FormatRelativeTime renders how long ago t was, e.g. "5m ago", "3h ago" or "2d ago"
+func FormatRelativeTime(t, now time.Time) string {
+	elapsed := now.Sub(t)
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed.Hours()))
+	case elapsed < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(elapsed.Hours()/24))
+	default:
+		return t.Format("Jan 2, 2006")
+	}
+}
+
+// ExplanationMarkdown renders why an item was listed: the provider, the query and filter that
+// returned it, the team or source it came from and when it was fetched
+func ExplanationMarkdown(provenance *types.Provenance) string {
+	var md strings.Builder
+	md.WriteString("## 🔎 Why is this listed?\n\n")
+	if provenance == nil {
+		md.WriteString("No provenance was recorded for this item.\n\n")
+		return md.String()
+	}
+
+	md.WriteString(fmt.Sprintf("- **Provider**: %s\n", provenance.Provider))
+	if provenance.Query != "" {
+		md.WriteString(fmt.Sprintf("- **Query**: `%s`\n", provenance.Query))
+	}
+	if provenance.Team != "" {
+		md.WriteString(fmt.Sprintf("- **Team**: %s\n", provenance.Team))
+	}
+	if provenance.Filter != "" {
+		md.WriteString(fmt.Sprintf("- **Filter**: `%s`\n", provenance.Filter))
+	}
+	if provenance.Source != "" {
+		md.WriteString(fmt.Sprintf("- **Source**: %s\n", provenance.Source))
+	}
+	md.WriteString(fmt.Sprintf("- **Fetched**: %s\n\n", provenance.FetchedAt.Format("Jan 2, 2006 15:04:05")))
+	return md.String()
+}
+
+// explanationLine summarizes why an item was listed on a single line, for narrow terminals
+func explanationLine(provenance *types.Provenance) string {
+	if provenance == nil {
+		return "🔎 No provenance recorded"
+	}
+	switch {
+	case provenance.Team != "":
+		return fmt.Sprintf("🔎 %s: review requested from %s", provenance.Provider, provenance.Team)
+	case provenance.Source != "":
+		return fmt.Sprintf("🔎 %s: %s", provenance.Provider, provenance.Source)
+	default:
+		return fmt.Sprintf("🔎 %s: %s", provenance.Provider, provenance.Query)
+	}
+}
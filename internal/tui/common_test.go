@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/colorprofile"
+
+	"daily/internal/terminal"
+	"daily/internal/tui/types"
+)
+
+func TestCheckTerminal(t *testing.T) {
+	err := CheckTerminal(terminal.Capabilities{Profile: colorprofile.NoTTY, Dumb: true})
+	if !errors.Is(err, ErrDumbTerminal) {
+		t.Fatalf("Expected ErrDumbTerminal on a dumb terminal, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "--output text") {
+		t.Errorf("Expected the error to point at --output text, got: %v", err)
+	}
+
+	for _, profile := range []colorprofile.Profile{colorprofile.Ascii, colorprofile.ANSI, colorprofile.TrueColor} {
+		if err := CheckTerminal(terminal.Capabilities{Profile: profile}); err != nil {
+			t.Errorf("Expected no error for the %s profile, got: %v", profile, err)
+		}
+	}
+}
+
+func TestExplanationMarkdown(t *testing.T) {
+	content := ExplanationMarkdown(&types.Provenance{
+		Provider:  "obsidian",
+		Source:    "Projects/Launch.md:12",
+		FetchedAt: time.Date(2024, 3, 11, 9, 30, 0, 0, time.UTC),
+	})
+	for _, want := range []string{"- **Provider**: obsidian", "- **Source**: Projects/Launch.md:12", "- **Fetched**: Mar 11, 2024 09:30:00"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected the explanation to contain '%s', got: %s", want, content)
+		}
+	}
+	if strings.Contains(content, "Query") || strings.Contains(content, "Team") {
+		t.Errorf("Expected fields that aren't set to be left out, got: %s", content)
+	}
+
+	if content := ExplanationMarkdown(nil); !strings.Contains(content, "No provenance") {
+		t.Errorf("Expected a note for items without provenance, got: %s", content)
+	}
+}
+
+func TestSeverityPrefix(t *testing.T) {
+	if prefix := severityPrefix("", false, false); prefix != "" {
+		t.Errorf("Expected no prefix without severity, got %q", prefix)
+	}
+	if prefix := severityPrefix("critical", false, true); prefix != "[CRIT] " {
+		t.Errorf("Expected the marker without colors, got %q", prefix)
+	}
+	if prefix := severityPrefix("high", true, false); prefix != "● " {
+		t.Errorf("Expected an unstyled dot on the selected line, got %q", prefix)
+	}
+	prefix := severityPrefix("critical", false, false)
+	if !strings.Contains(prefix, "●") || !strings.Contains(prefix, "\x1b[") {
+		t.Errorf("Expected a colored dot, got %q", prefix)
+	}
+}
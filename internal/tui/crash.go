@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrCrashed is returned when a TUI panicked. The terminal is restored and a crash report is
+// written before it is returned, so callers can show the same data as text instead.
+var ErrCrashed = errors.New("the interactive view crashed")
+
+// crashReportDir returns the directory crash reports are written to, overridden in tests
+var crashReportDir = func() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "daily", "cache"), nil
+}
+
+// crashState is what a crash report tells about the TUI besides the stack trace. It is only
+// updated from the event loop, which is where panics are recovered.
+type crashState struct {
+	view   string // Name of the TUI, e.g. "todo"
+	items  int
+	width  int
+	height int
+}
+
+// commandPanic is a panic of a command, sent back to the event loop to be raised there
+type commandPanic struct {
+	value any
+	stack []byte
+}
+
+// crashGuard wraps the model of a TUI to record the terminal size for crash reports, and to
+// raise the panics of its commands in the event loop, as they would otherwise crash the
+// program from the goroutine running them
+type crashGuard struct {
+	model tea.Model
+	state *crashState
+}
+
+func (g crashGuard) Init() tea.Cmd {
+	return guardCmd(g.model.Init())
+}
+
+func (g crashGuard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case commandPanic:
+		panic(msg)
+	case tea.WindowSizeMsg:
+		g.state.width, g.state.height = msg.Width, msg.Height
+	}
+
+	model, cmd := g.model.Update(msg)
+	g.model = model
+	return g, guardCmd(cmd)
+}
+
+func (g crashGuard) View() string {
+	return g.model.View()
+}
+
+// guardCmd returns a command sending the panic of cmd as a message instead of panicking,
+// including the commands of the batches it returns
+func guardCmd(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() (msg tea.Msg) {
+		defer func() {
+			if r := recover(); r != nil {
+				msg = commandPanic{value: r, stack: debug.Stack()}
+			}
+		}()
+
+		msg = cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			guarded := make(tea.BatchMsg, len(batch))
+			for i, c := range batch {
+				guarded[i] = guardCmd(c)
+			}
+			return guarded
+		}
+		return msg
+	}
+}
+
+// runProgram runs the model of a TUI. Bubble Tea's own panic handling is disabled so that a
+// panic is recovered here: the terminal is restored, a crash report is written and its path
+// printed, and ErrCrashed is returned.
+func runProgram(view string, model tea.Model, items int, opts ...tea.ProgramOption) (err error) {
+	state := &crashState{view: view, items: items}
+	p := tea.NewProgram(crashGuard{model: model, state: state}, append(opts, tea.WithoutCatchPanics())...)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		stack := debug.Stack()
+		if panicked, ok := r.(commandPanic); ok {
+			r, stack = panicked.value, panicked.stack
+		}
+
+		_ = p.ReleaseTerminal()
+		p.Kill()
+
+		path, reportErr := writeCrashReport(state, r, stack, time.Now())
+		if reportErr != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  The interactive view crashed and no crash report could be written: %v\n", reportErr)
+		} else {
+			fmt.Fprintf(os.Stderr, "⚠️  The interactive view crashed, crash report written to %s\n", path)
+		}
+		err = fmt.Errorf("%w: %s", ErrCrashed, panicDescription(r))
+	}()
+
+	_, err = p.Run()
+	return err
+}
+
+// panicDescription describes a panic value without leaking item contents: runtime errors
+// (e.g. an index out of range) are described, other values only by their type
+func panicDescription(value any) string {
+	if err, ok := value.(runtime.Error); ok {
+		return err.Error()
+	}
+	return fmt.Sprintf("panic of type %T", value)
+}
+
+// writeCrashReport writes the stack trace of a panic with the size of the TUI, and returns
+// the path of the report
+func writeCrashReport(state *crashState, value any, stack []byte, now time.Time) (string, error) {
+	dir, err := crashReportDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	var report strings.Builder
+	report.WriteString("daily crash report\n\n")
+	report.WriteString(fmt.Sprintf("Time:     %s\n", now.Format(time.RFC3339)))
+	report.WriteString(fmt.Sprintf("View:     %s\n", state.view))
+	report.WriteString(fmt.Sprintf("Items:    %d\n", state.items))
+	report.WriteString(fmt.Sprintf("Terminal: %dx%d\n", state.width, state.height))
+	report.WriteString(fmt.Sprintf("Go:       %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH))
+	report.WriteString(fmt.Sprintf("Panic:    %s\n\n", panicDescription(value)))
+	report.Write(stack)
+
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s-%s.txt", state.view, now.Format("20060102-150405")))
+	if err := os.WriteFile(path, []byte(report.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+	return path, nil
+}
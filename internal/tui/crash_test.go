@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// crashingModel panics in View, or in a command once it knows the terminal size
+type crashingModel struct {
+	panicInView bool
+}
+
+func (m crashingModel) Init() tea.Cmd {
+	return func() tea.Msg { return tea.WindowSizeMsg{Width: 120, Height: 40} }
+}
+
+func (m crashingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); ok && !m.panicInView {
+		return m, tea.Batch(nil, func() tea.Msg { panic("secret item title") })
+	}
+	return m, nil
+}
+
+func (m crashingModel) View() string {
+	if m.panicInView {
+		var items []string
+		return items[3]
+	}
+	return ""
+}
+
+// runCrashing runs a crashing model and returns the crash report
+func runCrashing(t *testing.T, model crashingModel) (string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	originalDir := crashReportDir
+	crashReportDir = func() (string, error) { return dir, nil }
+	defer func() { crashReportDir = originalDir }()
+
+	err := runProgram("todo", model, 3, tea.WithInput(nil), tea.WithOutput(io.Discard))
+
+	reports, _ := filepath.Glob(filepath.Join(dir, "crash-todo-*.txt"))
+	if len(reports) != 1 {
+		t.Fatalf("Expected 1 crash report, got %v", reports)
+	}
+	report, readErr := os.ReadFile(reports[0])
+	if readErr != nil {
+		t.Fatalf("Failed to read crash report: %v", readErr)
+	}
+	return string(report), err
+}
+
+func TestRunProgram_ViewPanic(t *testing.T) {
+	report, err := runCrashing(t, crashingModel{panicInView: true})
+
+	if !errors.Is(err, ErrCrashed) {
+		t.Errorf("Expected ErrCrashed, got: %v", err)
+	}
+	for _, want := range []string{"View:     todo", "Items:    3", "index out of range", "crashingModel.View"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected the report to contain '%s', got: %s", want, report)
+		}
+	}
+}
+
+func TestRunProgram_CommandPanic(t *testing.T) {
+	report, err := runCrashing(t, crashingModel{})
+
+	if !errors.Is(err, ErrCrashed) {
+		t.Errorf("Expected ErrCrashed, got: %v", err)
+	}
+	for _, want := range []string{"Terminal: 120x40", "Panic:    panic of type string", "crashingModel.Update.func1"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected the report to contain '%s', got: %s", want, report)
+		}
+	}
+	if strings.Contains(report, "secret item title") {
+		t.Errorf("Expected no item contents in the report, got: %s", report)
+	}
+}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"slices"
+
+	catppuccin "github.com/catppuccin/go"
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// marks are the vim-style marks of a list: "m<letter>" sets a mark on the selected item,
+// "'<letter>" jumps back to it and "dm<letter>" clears it. Marks point at item IDs rather
+// than positions, so they still find their item once the list is sorted or filtered again,
+// and last for the session.
+type marks struct {
+	ids     map[rune]string // Item ID of each mark letter
+	pending string          // Keys typed so far of a mark command: "m", "'", "d" or "dm"
+}
+
+// handleKey handles a key of a mark command, given the IDs of the listed items and the
+// cursor. It returns the cursor after the key, moved when jumping to a mark, and whether the
+// key was part of a mark command. A key that doesn't complete a pending command cancels it
+// and is handled as usual.
+func (mk *marks) handleKey(key string, ids []string, cursor int) (int, bool) {
+	pending := mk.pending
+	mk.pending = ""
+
+	if letter, ok := markLetter(key); ok && (pending == "m" || pending == "'" || pending == "dm") {
+		switch pending {
+		case "m":
+			if cursor >= 0 && cursor < len(ids) && ids[cursor] != "" {
+				if mk.ids == nil {
+					mk.ids = make(map[rune]string)
+				}
+				mk.ids[letter] = ids[cursor]
+			}
+		case "'":
+			if id, ok := mk.ids[letter]; ok {
+				if i := slices.Index(ids, id); i >= 0 {
+					return i, true
+				}
+			}
+		case "dm":
+			delete(mk.ids, letter)
+		}
+		return cursor, true
+	}
+
+	switch {
+	case pending == "d" && key == "m":
+		mk.pending = "dm"
+		return cursor, true
+	case key == "m" || key == "'" || key == "d":
+		mk.pending = key
+		return cursor, true
+	}
+	return cursor, false
+}
+
+// markLetter returns the letter of a key naming a mark, a to z or A to Z
+func markLetter(key string) (rune, bool) {
+	if len(key) != 1 {
+		return 0, false
+	}
+	letter := rune(key[0])
+	return letter, (letter >= 'a' && letter <= 'z') || (letter >= 'A' && letter <= 'Z')
+}
+
+// letterOf returns the letter of the mark set on an item, the first in alphabetical order
+// when there are several
+func (mk marks) letterOf(id string) (rune, bool) {
+	var found rune
+	for letter, markedID := range mk.ids {
+		if markedID == id && id != "" && (found == 0 || letter < found) {
+			found = letter
+		}
+	}
+	return found, found != 0
+}
+
+// gutter returns the gutter of an item line: the letter of its mark in green, or blanks.
+// There is no gutter until a mark is set. Selected lines keep the letter unstyled so the
+// selection highlight isn't broken.
+func (mk marks) gutter(id string, selected, plain bool) string {
+	if len(mk.ids) == 0 {
+		return ""
+	}
+	letter, ok := mk.letterOf(id)
+	if !ok {
+		return "  "
+	}
+	if selected || plain {
+		return string(letter) + " "
+	}
+	var flavor catppuccin.Flavor = catppuccin.Latte
+	if isDarkMode() {
+		flavor = catppuccin.Mocha
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(flavor.Green().Hex)).Bold(true).Render(string(letter)) + " "
+}
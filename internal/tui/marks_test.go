@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"daily/internal/activity"
+	"daily/internal/tui/types"
+)
+
+// typeKeys feeds the keys of a mark command to marks, starting from cursor, and returns the
+// cursor after the last key
+func typeKeys(t *testing.T, mk *marks, ids []string, cursor int, keys ...string) int {
+	t.Helper()
+	for _, key := range keys {
+		cursor, _ = mk.handleKey(key, ids, cursor)
+	}
+	return cursor
+}
+
+func TestMarks_SetJumpClear(t *testing.T) {
+	ids := []string{"a-1", "a-2", "a-3", "a-4"}
+	var mk marks
+
+	typeKeys(t, &mk, ids, 1, "m", "a")
+	typeKeys(t, &mk, ids, 3, "m", "B")
+	if len(mk.ids) != 2 || mk.ids['a'] != "a-2" || mk.ids['B'] != "a-4" {
+		t.Fatalf("Expected marks a and B on a-2 and a-4, got %v", mk.ids)
+	}
+
+	if cursor := typeKeys(t, &mk, ids, 0, "'", "a"); cursor != 1 {
+		t.Errorf("Expected 'a to jump to 1, got %d", cursor)
+	}
+	if cursor := typeKeys(t, &mk, ids, 0, "'", "B"); cursor != 3 {
+		t.Errorf("Expected 'B to jump to 3, got %d", cursor)
+	}
+	if cursor := typeKeys(t, &mk, ids, 2, "'", "z"); cursor != 2 {
+		t.Errorf("Expected an unset mark to keep the cursor, got %d", cursor)
+	}
+
+	typeKeys(t, &mk, ids, 0, "d", "m", "a")
+	if _, ok := mk.ids['a']; ok || len(mk.ids) != 1 {
+		t.Errorf("Expected mark a to be cleared, got %v", mk.ids)
+	}
+	if cursor := typeKeys(t, &mk, ids, 0, "'", "a"); cursor != 0 {
+		t.Errorf("Expected a cleared mark to keep the cursor, got %d", cursor)
+	}
+}
+
+func TestMarks_HandleKey(t *testing.T) {
+	ids := []string{"a-1", "a-2"}
+
+	tests := []struct {
+		name    string
+		keys    []string
+		handled bool // Whether the last key is part of a mark command
+		pending string
+	}{
+		{"other key", []string{"j"}, false, ""},
+		{"start of a mark", []string{"m"}, true, "m"},
+		{"start of a jump", []string{"'"}, true, "'"},
+		{"start of a clear", []string{"d", "m"}, true, "dm"},
+		{"key cancelling a mark", []string{"m", "down"}, false, ""},
+		{"key cancelling a clear", []string{"d", "j"}, false, ""},
+		{"quit key naming a mark", []string{"m", "q"}, true, ""},
+		{"quit", []string{"m", "ctrl+c"}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mk marks
+			var handled bool
+			for _, key := range tt.keys {
+				_, handled = mk.handleKey(key, ids, 0)
+			}
+			if handled != tt.handled {
+				t.Errorf("Expected handled %v, got %v", tt.handled, handled)
+			}
+			if mk.pending != tt.pending {
+				t.Errorf("Expected pending %q, got %q", tt.pending, mk.pending)
+			}
+		})
+	}
+}
+
+func TestMarks_SurviveResort(t *testing.T) {
+	ids := []string{"a-1", "a-2", "a-3"}
+	var mk marks
+	typeKeys(t, &mk, ids, 0, "m", "x")
+
+	// The list is sorted again, then the marked item is filtered out and back in
+	slices.Reverse(ids)
+	if cursor := typeKeys(t, &mk, ids, 0, "'", "x"); cursor != 2 {
+		t.Errorf("Expected the mark to follow its item to 2, got %d", cursor)
+	}
+	if cursor := typeKeys(t, &mk, ids[:2], 1, "'", "x"); cursor != 1 {
+		t.Errorf("Expected a mark on a hidden item to keep the cursor, got %d", cursor)
+	}
+	if cursor := typeKeys(t, &mk, []string{"a-1", "a-4"}, 1, "'", "x"); cursor != 0 {
+		t.Errorf("Expected the mark to last once its item is listed again, got %d", cursor)
+	}
+}
+
+func TestMarks_Gutter(t *testing.T) {
+	var mk marks
+	if gutter := mk.gutter("a-1", false, true); gutter != "" {
+		t.Errorf("Expected no gutter without marks, got %q", gutter)
+	}
+
+	typeKeys(t, &mk, []string{"a-1"}, 0, "m", "c")
+	typeKeys(t, &mk, []string{"a-1"}, 0, "m", "b")
+	if gutter := mk.gutter("a-1", false, true); gutter != "b " {
+		t.Errorf("Expected the first letter of the item marks, got %q", gutter)
+	}
+	if gutter := mk.gutter("a-2", false, true); gutter != "  " {
+		t.Errorf("Expected a blank gutter for an unmarked item, got %q", gutter)
+	}
+	if gutter := mk.gutter("a-1", false, false); !strings.Contains(gutter, "b") {
+		t.Errorf("Expected the colored letter, got %q", gutter)
+	}
+}
+
+func TestTodoModel_Marks(t *testing.T) {
+	now := time.Now()
+	m := NewTodoModel(types.TodoItems{Obsidian: types.ObsidianTodos{Tasks: []types.TodoItem{
+		{ID: "task-1", Title: "First", UpdatedAt: now},
+		{ID: "task-2", Title: "Second", UpdatedAt: now.Add(-time.Hour)},
+		{ID: "task-3", Title: "Third", UpdatedAt: now.Add(-2 * time.Hour)},
+	}}})
+
+	press := func(keys ...string) {
+		t.Helper()
+		for _, key := range keys {
+			model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+			m = model.(TodoModel)
+		}
+	}
+
+	press("j", "m", "a", "g")
+	if m.selectedItem != 0 {
+		t.Fatalf("Expected the cursor back at the top, got %d", m.selectedItem)
+	}
+
+	// A new priority sorts the marked task last
+	m.todoItems.Obsidian.Tasks[0].Priority = "high"
+	m.todoItems.Obsidian.Tasks[2].Priority = "medium"
+	m.buildItemsList()
+	press("'", "a")
+	if got := m.allItems[m.selectedItem].Item.ID; got != "task-2" || m.selectedItem != 2 {
+		t.Errorf("Expected 'a to jump to task-2 at 2, got %s at %d", got, m.selectedItem)
+	}
+
+	press("d", "m", "a", "g", "'", "a")
+	if m.selectedItem != 0 {
+		t.Errorf("Expected a cleared mark not to move the cursor, got %d", m.selectedItem)
+	}
+}
+
+func TestReviewsModel_Marks(t *testing.T) {
+	m := NewReviewsModel(testReviewItems(), nil)
+	m, _ = pressKey(t, m, "down")
+	m, _ = pressKey(t, m, "m")
+	m, _ = pressKey(t, m, "r")
+	m, _ = pressKey(t, m, "g")
+	m, _ = pressKey(t, m, "'")
+	m, _ = pressKey(t, m, "r")
+	if got := m.allItems[m.selectedItem].Item.TodoItem.ID; got != "github-review-2" {
+		t.Errorf("Expected 'r to jump to github-review-2, got %s", got)
+	}
+}
+
+func TestSummaryModel_Marks(t *testing.T) {
+	m := summaryModel{activities: []activity.Activity{{ID: "act-1"}, {ID: "act-2"}}}
+	for _, key := range []string{"j", "m", "s", "k", "'", "s"} {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = model.(summaryModel)
+	}
+	if m.cursor != 1 {
+		t.Errorf("Expected 's to jump to 1, got %d", m.cursor)
+	}
+}
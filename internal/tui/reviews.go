@@ -1,14 +1,17 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss/v2"
 
+	"daily/internal/terminal"
 	"daily/internal/tui/types"
 )
 
@@ -23,6 +26,26 @@ type ReviewsModel struct {
 	leftViewport  viewportState
 	rightViewport viewportState
 	glamourStyle  *glamour.TermRenderer
+	explain       bool // Show why the selected item is listed
+	plain         bool // No colors, severities are shown as markers such as [CRIT]
+	marks         marks
+
+	// Comment thread previews, loaded lazily per PR and cached for the session
+	fetchComments   types.CommentFetcher
+	showComments    bool
+	comments        map[string][]types.PRComment
+	commentErrors   map[string]error
+	loadingComments map[string]bool
+}
+
+// commentPreviewLimit is the number of recent comments shown in the thread preview
+const commentPreviewLimit = 10
+
+// commentsLoadedMsg delivers the comments fetched for a PR
+type commentsLoadedMsg struct {
+	key      string
+	comments []types.PRComment
+	err      error
 }
 
 // ReviewListItem represents an item in the navigation list
@@ -32,8 +55,9 @@ type ReviewListItem struct {
 	DisplayText string
 }
 
-// NewReviewsModel creates a new reviews TUI model
-func NewReviewsModel(reviewItems types.ReviewItems) ReviewsModel {
+// NewReviewsModel creates a new reviews TUI model. fetchComments loads the comment
+// thread previews and may be nil, in which case the preview is unavailable.
+func NewReviewsModel(reviewItems types.ReviewItems, fetchComments types.CommentFetcher) ReviewsModel {
 	// Initialize glamour renderer
 	var glamourStyle *glamour.TermRenderer
 	var glamourTheme string
@@ -51,6 +75,7 @@ func NewReviewsModel(reviewItems types.ReviewItems) ReviewsModel {
 		reviewItems:  reviewItems,
 		styles:       NewCommonStyles(),
 		glamourStyle: glamourStyle,
+		plain:        terminal.Stdout().Plain(),
 		leftViewport: viewportState{
 			offset: 0,
 			height: 20, // Default height, will be updated on window size msg
@@ -59,6 +84,10 @@ func NewReviewsModel(reviewItems types.ReviewItems) ReviewsModel {
 			offset: 0,
 			height: 20, // Default height, will be updated on window size msg
 		},
+		fetchComments:   fetchComments,
+		comments:        make(map[string][]types.PRComment),
+		commentErrors:   make(map[string]error),
+		loadingComments: make(map[string]bool),
 	}
 	model.buildItemsList()
 	return model
@@ -67,8 +96,15 @@ func NewReviewsModel(reviewItems types.ReviewItems) ReviewsModel {
 func (m *ReviewsModel) buildItemsList() {
 	m.allItems = []ReviewListItem{}
 
-	// Add user requests
-	for _, item := range m.reviewItems.GitHub.UserRequests {
+	for _, item := range m.reviewItems {
+		if item.RequestType == types.ReviewRequestTeam {
+			m.allItems = append(m.allItems, ReviewListItem{
+				Item:        item,
+				Type:        "team_request",
+				DisplayText: fmt.Sprintf("👥 %s", item.TodoItem.Title),
+			})
+			continue
+		}
 		m.allItems = append(m.allItems, ReviewListItem{
 			Item:        item,
 			Type:        "user_request",
@@ -76,17 +112,14 @@ func (m *ReviewsModel) buildItemsList() {
 		})
 	}
 
-	// Add team requests
-	for _, item := range m.reviewItems.GitHub.TeamRequests {
-		m.allItems = append(m.allItems, ReviewListItem{
-			Item:        item,
-			Type:        "team_request",
-			DisplayText: fmt.Sprintf("👥 %s", item.TodoItem.Title),
-		})
-	}
-
-	// Sort by updated time (most recent first)
-	sort.Slice(m.allItems, func(i, j int) bool {
+	// Sort reviews queued to be merged last and stale reviews first, then by updated time (most recent first)
+	sort.SliceStable(m.allItems, func(i, j int) bool {
+		if m.allItems[i].Item.InMergeQueue != m.allItems[j].Item.InMergeQueue {
+			return m.allItems[j].Item.InMergeQueue
+		}
+		if m.allItems[i].Item.IsStale != m.allItems[j].Item.IsStale {
+			return m.allItems[i].Item.IsStale
+		}
 		return m.allItems[i].Item.TodoItem.UpdatedAt.After(m.allItems[j].Item.TodoItem.UpdatedAt)
 	})
 }
@@ -104,22 +137,55 @@ func (m ReviewsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.rightViewport.height = msg.Height - 4 // Reserve space for header
 		m.updateLeftViewport()
 		return m, nil
+	case commentsLoadedMsg:
+		delete(m.loadingComments, msg.key)
+		if msg.err != nil {
+			m.commentErrors[msg.key] = msg.err
+		} else {
+			m.comments[msg.key] = msg.comments
+		}
+		return m, nil
 	case tea.KeyMsg:
+		if cursor, ok := m.marks.handleKey(msg.String(), m.itemIDs(), m.selectedItem); ok {
+			if cursor == m.selectedItem {
+				return m, nil
+			}
+			m.selectedItem = cursor
+			m.updateLeftViewport()
+			return m.selectionChanged()
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
 		case "up", "k":
 			m.selectedItem = ClampCursor(m.selectedItem-1, 0, len(m.allItems)-1)
 			m.updateLeftViewport()
+			return m.selectionChanged()
 		case "down", "j":
 			m.selectedItem = ClampCursor(m.selectedItem+1, 0, len(m.allItems)-1)
 			m.updateLeftViewport()
+			return m.selectionChanged()
 		case "home", "g":
 			m.selectedItem = 0
 			m.updateLeftViewport()
+			return m.selectionChanged()
 		case "end", "G":
 			m.selectedItem = len(m.allItems) - 1
 			m.updateLeftViewport()
+			return m.selectionChanged()
+		case "v":
+			m.showComments = !m.showComments
+			m.rightViewport.offset = 0
+			if m.showComments {
+				return m, m.loadCommentsCmd()
+			}
+		case "x":
+			m.explain = !m.explain
+			m.rightViewport.offset = 0
+		case "pgdown", "ctrl+d":
+			m.scrollRightPanel(max(1, m.rightViewport.height/2))
+		case "pgup", "ctrl+u":
+			m.scrollRightPanel(-max(1, m.rightViewport.height/2))
 		case "enter", " ":
 			if m.selectedItem < len(m.allItems) && m.allItems[m.selectedItem].Item.TodoItem.URL != "" {
 				url := m.allItems[m.selectedItem].Item.TodoItem.URL
@@ -131,6 +197,72 @@ func (m ReviewsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// selectionChanged resets the right panel scroll and loads the comments of the newly
+// selected PR when the comment preview is shown
+func (m ReviewsModel) selectionChanged() (tea.Model, tea.Cmd) {
+	m.rightViewport.offset = 0
+	if m.showComments {
+		return m, m.loadCommentsCmd()
+	}
+	return m, nil
+}
+
+// itemIDs returns the IDs of the listed review requests, which marks point at
+func (m ReviewsModel) itemIDs() []string {
+	ids := make([]string, len(m.allItems))
+	for i, item := range m.allItems {
+		ids[i] = item.Item.TodoItem.ID
+	}
+	return ids
+}
+
+// commentsKey identifies a PR in the comment cache
+func commentsKey(item types.ReviewItem) string {
+	return fmt.Sprintf("%s#%d", item.Repository, item.Number)
+}
+
+// loadCommentsCmd fetches the comments of the selected PR unless they are already
+// cached or being loaded
+func (m ReviewsModel) loadCommentsCmd() tea.Cmd {
+	if m.fetchComments == nil || m.selectedItem >= len(m.allItems) {
+		return nil
+	}
+
+	item := m.allItems[m.selectedItem].Item
+	if item.Repository == "" || item.Number == 0 {
+		return nil
+	}
+
+	key := commentsKey(item)
+	if _, ok := m.comments[key]; ok || m.loadingComments[key] {
+		return nil
+	}
+	delete(m.commentErrors, key) // Retry PRs whose comments failed to load
+	m.loadingComments[key] = true
+
+	fetch := m.fetchComments
+	return func() tea.Msg {
+		comments, err := fetch(context.Background(), item.Repository, item.Number, commentPreviewLimit)
+		return commentsLoadedMsg{key: key, comments: comments, err: err}
+	}
+}
+
+// scrollRightPanel moves the right panel content by delta lines, within its bounds
+func (m *ReviewsModel) scrollRightPanel(delta int) {
+	maxOffset := 0
+	if m.selectedItem < len(m.allItems) {
+		dimensions := CalculatePanelDimensions(m.width)
+		lines := strings.Count(m.renderRightContent(m.allItems[m.selectedItem], dimensions.RightWidth), "\n") + 1
+		maxOffset = max(0, lines-m.rightPanelContentHeight())
+	}
+	m.rightViewport.offset = ClampCursor(m.rightViewport.offset+delta, 0, maxOffset)
+}
+
+// rightPanelContentHeight is the number of content lines visible in the right panel
+func (m ReviewsModel) rightPanelContentHeight() int {
+	return max(1, m.rightViewport.height-2) // Account for the panel padding
+}
+
 func (m *ReviewsModel) updateLeftViewport() {
 	if m.leftViewport.height <= 0 {
 		return
@@ -192,7 +324,7 @@ func (m ReviewsModel) renderLeftPanel(width int) string {
 	var content strings.Builder
 
 	// Navigation help
-	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • q: Quit"
+	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • m/': Mark/Jump • v: Comments • x: Explain • PgUp/PgDn: Scroll • q: Quit"
 	adjustedWidth := max(20, width) // Same adjustment as in CreateBorderedPanel
 	content.WriteString(RenderHelpText(helpText, adjustedWidth-4))
 	content.WriteString("\n\n")
@@ -220,13 +352,22 @@ func (m ReviewsModel) renderLeftPanel(width int) string {
 
 		// Add CI status indicator
 		ciIcon := getCIStatusIcon(item.Item.CIStatus)
+		if item.Item.IsStale {
+			ciIcon += " 🔥"
+		}
+		if item.Item.InMergeQueue {
+			ciIcon += " 🚦"
+		}
 
 		// Truncate title to fit width
-		maxTitleWidth := max(5, adjustedWidth-20) // Account for time, icons, and padding
+		gutter := m.marks.gutter(item.Item.TodoItem.ID, isSelected, m.plain)
+		suffix := baseBranchSuffix(item.Item.Base, isSelected)
+		prefix := severityPrefix(item.Item.TodoItem.Severity, isSelected, m.plain)
+		maxTitleWidth := max(5, adjustedWidth-20-lipgloss.Width(gutter)-lipgloss.Width(prefix)-lipgloss.Width(suffix)) // Account for mark, time, icons, severity, base branch, and padding
 		title := TruncateText(item.Item.TodoItem.Title, maxTitleWidth)
 
 		var line strings.Builder
-		line.WriteString(fmt.Sprintf("%s %s %s %s", timeStr, icon, ciIcon, title))
+		line.WriteString(fmt.Sprintf("%s%s %s %s %s%s%s", gutter, timeStr, icon, ciIcon, prefix, title, suffix))
 
 		if item.Item.TodoItem.URL != "" {
 			line.WriteString(" 🔗")
@@ -257,10 +398,39 @@ func (m ReviewsModel) renderRightPanel(width int) string {
 		return rightStyle.Render("Select a review request to view details")
 	}
 
-	selectedItem := m.allItems[m.selectedItem]
+	content := m.renderRightContent(m.allItems[m.selectedItem], width)
+
+	// Show the scrolled part of the content
+	lines := strings.Split(content, "\n")
+	visible := m.rightPanelContentHeight()
+	offset := ClampCursor(m.rightViewport.offset, 0, max(0, len(lines)-visible))
+	end := min(len(lines), offset+visible)
+	if end < len(lines) {
+		// Keep the last visible line for the scroll hint
+		end = max(offset, end-1)
+		lines = append(lines[offset:end], RenderScrollIndicator(end, len(lines), adjustedWidth-4))
+	} else {
+		lines = lines[offset:end]
+	}
+
+	return rightStyle.Render(strings.Join(lines, "\n"))
+}
+
+// renderRightContent renders the details or the comment thread of a review item,
+// wrapped to the right panel width
+func (m ReviewsModel) renderRightContent(item ReviewListItem, width int) string {
+	adjustedWidth := max(30, width) // Same adjustment as in CreateBorderedPanel
 
 	// Create markdown content for the selected review item
-	markdown := m.createReviewMarkdownContent(selectedItem)
+	var markdown string
+	if m.showComments {
+		markdown = m.createCommentsMarkdownContent(item, time.Now())
+		if m.explain {
+			markdown = ExplanationMarkdown(item.Item.TodoItem.Provenance) + markdown
+		}
+	} else {
+		markdown = m.createReviewMarkdownContent(item)
+	}
 
 	// Render markdown using glamour if available
 	var rendered string
@@ -278,7 +448,45 @@ func (m ReviewsModel) renderRightPanel(width int) string {
 	contentStyle := lipgloss.NewStyle().
 		Width(max(10, adjustedWidth-4)) // Account for padding and border
 
-	return rightStyle.Render(contentStyle.Render(rendered))
+	return contentStyle.Render(rendered)
+}
+
+// createCommentsMarkdownContent renders the latest comments of a PR, newest first
+func (m ReviewsModel) createCommentsMarkdownContent(item ReviewListItem, now time.Time) string {
+	var md strings.Builder
+
+	md.WriteString(fmt.Sprintf("# %s\n\n", item.Item.TodoItem.Title))
+
+	key := commentsKey(item.Item)
+	comments, loaded := m.comments[key]
+	switch {
+	case m.fetchComments == nil || item.Item.Repository == "" || item.Item.Number == 0:
+		md.WriteString("Comments are not available for this PR.\n")
+	case m.loadingComments[key]:
+		md.WriteString("⏳ Loading comments...\n")
+	case m.commentErrors[key] != nil:
+		md.WriteString(fmt.Sprintf("❌ Failed to load comments: %v\n", m.commentErrors[key]))
+	case !loaded:
+		md.WriteString("⏳ Loading comments...\n")
+	case len(comments) == 0:
+		md.WriteString("No comments yet.\n")
+	default:
+		md.WriteString(fmt.Sprintf("## 💬 Latest Comments (%d)\n\n", len(comments)))
+		for i, comment := range comments {
+			if i > 0 {
+				md.WriteString("---\n\n")
+			}
+			md.WriteString(fmt.Sprintf("**@%s** · %s", comment.Author, FormatRelativeTime(comment.CreatedAt, now)))
+			if comment.Path != "" {
+				md.WriteString(fmt.Sprintf(" · on `%s`", comment.Path))
+			}
+			md.WriteString("\n\n")
+			md.WriteString(strings.TrimSpace(comment.Body))
+			md.WriteString("\n\n")
+		}
+	}
+
+	return md.String()
 }
 
 func (m ReviewsModel) createReviewMarkdownContent(item ReviewListItem) string {
@@ -287,6 +495,10 @@ func (m ReviewsModel) createReviewMarkdownContent(item ReviewListItem) string {
 	// Title
 	md.WriteString(fmt.Sprintf("# %s\n\n", item.Item.TodoItem.Title))
 
+	if m.explain {
+		md.WriteString(ExplanationMarkdown(item.Item.TodoItem.Provenance))
+	}
+
 	// Metadata table
 	md.WriteString("## Details\n\n")
 	md.WriteString("| Field | Value |\n")
@@ -310,6 +522,17 @@ func (m ReviewsModel) createReviewMarkdownContent(item ReviewListItem) string {
 		md.WriteString(fmt.Sprintf("| **CI Status** | %s %s |\n", icon, strings.Title(ciStatus.State)))
 	}
 
+	// Review age
+	if item.Item.IsStale {
+		md.WriteString(fmt.Sprintf("| **Waiting** | 🔥 %d days |\n", item.Item.AgeDays))
+	} else {
+		md.WriteString(fmt.Sprintf("| **Waiting** | %d days |\n", item.Item.AgeDays))
+	}
+
+	if item.Item.InMergeQueue {
+		md.WriteString("| **Merge Queue** | 🚦 Queued to be merged |\n")
+	}
+
 	// PR Details
 	prDetails := item.Item.PRDetails
 	if prDetails.Additions > 0 || prDetails.Deletions > 0 || prDetails.ChangedFiles > 0 {
@@ -317,6 +540,14 @@ func (m ReviewsModel) createReviewMarkdownContent(item ReviewListItem) string {
 			prDetails.Additions, prDetails.Deletions, prDetails.ChangedFiles))
 	}
 
+	if item.Item.Base != "" {
+		md.WriteString(fmt.Sprintf("| **Base** | → `%s` |\n", item.Item.Base))
+	}
+
+	if item.Item.TodoItem.Milestone != "" {
+		md.WriteString(fmt.Sprintf("| **Milestone** | 🎯 %s |\n", item.Item.TodoItem.Milestone))
+	}
+
 	if item.Item.TodoItem.URL != "" {
 		md.WriteString(fmt.Sprintf("| **URL** | [🔗 Open PR](%s) |\n", item.Item.TodoItem.URL))
 	}
@@ -367,7 +598,7 @@ func (m ReviewsModel) renderSinglePanelView() string {
 	content.WriteString("\n")
 
 	// Navigation help
-	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • q: Quit"
+	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • m/': Mark/Jump • x: Explain • q: Quit"
 	content.WriteString(RenderHelpText(helpText, m.width))
 	content.WriteString("\n\n")
 
@@ -401,12 +632,21 @@ func (m ReviewsModel) renderSinglePanelView() string {
 
 		// Add CI status indicator
 		ciIcon := getCIStatusIcon(item.Item.CIStatus)
+		if item.Item.IsStale {
+			ciIcon += " 🔥"
+		}
+		if item.Item.InMergeQueue {
+			ciIcon += " 🚦"
+		}
 
 		// Truncate title to fit
-		maxTitleWidth := max(5, m.width-20)
+		gutter := m.marks.gutter(item.Item.TodoItem.ID, isSelected, m.plain)
+		suffix := baseBranchSuffix(item.Item.Base, isSelected)
+		prefix := severityPrefix(item.Item.TodoItem.Severity, isSelected, m.plain)
+		maxTitleWidth := max(5, m.width-20-lipgloss.Width(gutter)-lipgloss.Width(prefix)-lipgloss.Width(suffix))
 		title := TruncateText(item.Item.TodoItem.Title, maxTitleWidth)
 
-		line := fmt.Sprintf("%s %s %s %s", timeStr, icon, ciIcon, title)
+		line := fmt.Sprintf("%s%s %s %s %s%s%s", gutter, timeStr, icon, ciIcon, prefix, title, suffix)
 		if item.Item.TodoItem.URL != "" {
 			line += " 🔗"
 		}
@@ -438,6 +678,10 @@ func (m ReviewsModel) renderSinglePanelView() string {
 			content.WriteString(descStyle.Render(desc))
 			content.WriteString("\n")
 		}
+		if m.explain {
+			content.WriteString(TruncateText(explanationLine(item.Item.TodoItem.Provenance), m.width-4))
+			content.WriteString("\n")
+		}
 	}
 
 	// Scroll indicator
@@ -486,16 +730,26 @@ func getCheckIcon(status, conclusion string) string {
 	return "⚪"
 }
 
-// RunReviewsTUI starts the reviews TUI application
-func RunReviewsTUI(reviewItems types.ReviewItems) error {
-	model := NewReviewsModel(reviewItems)
+// RunReviewsTUI starts the reviews TUI application. It returns ErrCrashed when the TUI panicked.
+func RunReviewsTUI(reviewItems types.ReviewItems, fetchComments types.CommentFetcher) error {
+	if err := CheckTerminal(terminal.Stdout()); err != nil {
+		return err
+	}
 
-	p := tea.NewProgram(
-		model,
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
+	model := NewReviewsModel(reviewItems, fetchComments)
+	return runProgram("reviews", model, len(model.allItems), tea.WithAltScreen(), tea.WithMouseCellMotion())
+}
 
-	_, err := p.Run()
-	return err
+// baseBranchSuffix renders the PR target branch as a dim " → release/1.2" suffix.
+// Selected lines keep it unstyled so the selection highlight isn't broken.
+func baseBranchSuffix(base string, selected bool) string {
+	if base == "" {
+		return ""
+	}
+	suffix := " → " + base
+	if selected {
+		return suffix
+	}
+	_, _, _, _, _, scrollColor := GetThemeColors()
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(scrollColor)).Faint(true).Render(suffix)
 }
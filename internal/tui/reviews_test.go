@@ -0,0 +1,278 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"daily/internal/tui/types"
+)
+
+func testReviewItems() types.ReviewItems {
+	now := time.Now()
+	return types.ReviewItems{
+		{
+			TodoItem:    types.TodoItem{ID: "github-review-1", Title: "Add feature", UpdatedAt: now},
+			Platform:    "github",
+			RequestType: types.ReviewRequestUser,
+			Repository:  "owner/repo",
+			Number:      1,
+		},
+		{
+			TodoItem:    types.TodoItem{ID: "github-review-2", Title: "Fix bug", UpdatedAt: now.Add(-time.Hour)},
+			Platform:    "github",
+			RequestType: types.ReviewRequestTeam,
+			Repository:  "owner/repo",
+			Number:      2,
+		},
+	}
+}
+
+func pressKey(t *testing.T, m ReviewsModel, key string) (ReviewsModel, tea.Cmd) {
+	t.Helper()
+	var msg tea.KeyMsg
+	switch key {
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	model, cmd := m.Update(msg)
+	return model.(ReviewsModel), cmd
+}
+
+// runCmd executes a command and feeds its message back to the model
+func runCmd(m ReviewsModel, cmd tea.Cmd) ReviewsModel {
+	model, _ := m.Update(cmd())
+	return model.(ReviewsModel)
+}
+
+func TestReviewsModel_ToggleComments(t *testing.T) {
+	var calls []string
+	fetch := func(ctx context.Context, repo string, number, limit int) ([]types.PRComment, error) {
+		calls = append(calls, commentsKey(types.ReviewItem{Repository: repo, Number: number}))
+		if limit != commentPreviewLimit {
+			t.Errorf("Expected limit %d, got %d", commentPreviewLimit, limit)
+		}
+		return []types.PRComment{{Author: "alice", Body: "Looks good", CreatedAt: time.Now().Add(-2 * time.Hour)}}, nil
+	}
+
+	m := NewReviewsModel(testReviewItems(), fetch)
+	if m.showComments {
+		t.Fatal("Expected comments to be hidden initially")
+	}
+
+	// Toggling on fetches the comments of the selected PR
+	m, cmd := pressKey(t, m, "v")
+	if !m.showComments {
+		t.Fatal("Expected comments to be shown after pressing 'v'")
+	}
+	if cmd == nil {
+		t.Fatal("Expected a command to fetch comments")
+	}
+	content := m.createCommentsMarkdownContent(m.allItems[m.selectedItem], time.Now())
+	if !strings.Contains(content, "Loading comments") {
+		t.Errorf("Expected loading message while fetching, got: %s", content)
+	}
+
+	m = runCmd(m, cmd)
+	content = m.createCommentsMarkdownContent(m.allItems[m.selectedItem], time.Now())
+	for _, want := range []string{"@alice", "2h ago", "Looks good"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected comments to contain '%s', got: %s", want, content)
+		}
+	}
+
+	// Toggling off goes back to the metadata view
+	m, cmd = pressKey(t, m, "v")
+	if m.showComments {
+		t.Error("Expected comments to be hidden after pressing 'v' again")
+	}
+	if cmd != nil {
+		t.Error("Expected no command when hiding comments")
+	}
+
+	// Comments are cached for the session
+	m, cmd = pressKey(t, m, "v")
+	if cmd != nil {
+		t.Error("Expected cached comments not to be fetched again")
+	}
+
+	// Moving to another PR loads its comments
+	m, cmd = pressKey(t, m, "down")
+	if cmd == nil {
+		t.Fatal("Expected a command to fetch comments of the newly selected PR")
+	}
+	runCmd(m, cmd)
+
+	if len(calls) != 2 || calls[0] != "owner/repo#1" || calls[1] != "owner/repo#2" {
+		t.Errorf("Expected one fetch per PR, got %v", calls)
+	}
+}
+
+func TestReviewsModel_CommentsUnavailable(t *testing.T) {
+	m := NewReviewsModel(testReviewItems(), nil)
+
+	m, cmd := pressKey(t, m, "v")
+	if !m.showComments {
+		t.Error("Expected comments view to be toggled on")
+	}
+	if cmd != nil {
+		t.Error("Expected no command without a comment fetcher")
+	}
+
+	content := m.createCommentsMarkdownContent(m.allItems[m.selectedItem], time.Now())
+	if !strings.Contains(content, "Comments are not available") {
+		t.Errorf("Expected unavailable message, got: %s", content)
+	}
+}
+
+func TestReviewsModel_CommentsError(t *testing.T) {
+	fails := true
+	fetch := func(ctx context.Context, repo string, number, limit int) ([]types.PRComment, error) {
+		if fails {
+			return nil, errors.New("rate limited")
+		}
+		return nil, nil
+	}
+
+	m := NewReviewsModel(testReviewItems(), fetch)
+	m, cmd := pressKey(t, m, "v")
+	m = runCmd(m, cmd)
+
+	content := m.createCommentsMarkdownContent(m.allItems[m.selectedItem], time.Now())
+	if !strings.Contains(content, "Failed to load comments: rate limited") {
+		t.Errorf("Expected error message, got: %s", content)
+	}
+
+	// Failed loads are retried the next time the preview is opened
+	fails = false
+	m, _ = pressKey(t, m, "v")
+	m, cmd = pressKey(t, m, "v")
+	if cmd == nil {
+		t.Fatal("Expected failed comments to be fetched again")
+	}
+	m = runCmd(m, cmd)
+
+	content = m.createCommentsMarkdownContent(m.allItems[m.selectedItem], time.Now())
+	if !strings.Contains(content, "No comments yet") {
+		t.Errorf("Expected empty thread message, got: %s", content)
+	}
+}
+
+func TestReviewsModel_ScrollRightPanel(t *testing.T) {
+	m := NewReviewsModel(testReviewItems(), nil)
+	m.width = 120
+	m.rightViewport.height = 10
+
+	m.scrollRightPanel(-5)
+	if m.rightViewport.offset != 0 {
+		t.Errorf("Expected offset to stay at 0, got %d", m.rightViewport.offset)
+	}
+
+	m.scrollRightPanel(1000)
+	if m.rightViewport.offset <= 0 {
+		t.Errorf("Expected offset to move down, got %d", m.rightViewport.offset)
+	}
+	maxOffset := m.rightViewport.offset
+	m.scrollRightPanel(1)
+	if m.rightViewport.offset != maxOffset {
+		t.Errorf("Expected offset to stop at %d, got %d", maxOffset, m.rightViewport.offset)
+	}
+
+	// Toggling the view starts at the top again
+	m, _ = pressKey(t, m, "v")
+	if m.rightViewport.offset != 0 {
+		t.Errorf("Expected offset to reset when toggling, got %d", m.rightViewport.offset)
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		time     time.Time
+		expected string
+	}{
+		{now.Add(-30 * time.Second), "just now"},
+		{now.Add(-5 * time.Minute), "5m ago"},
+		{now.Add(-3 * time.Hour), "3h ago"},
+		{now.Add(-50 * time.Hour), "2d ago"},
+		{now.AddDate(0, -2, 0), "Jan 10, 2024"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatRelativeTime(tt.time, now); got != tt.expected {
+			t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+		}
+	}
+}
+
+func TestReviewsModel_ToggleExplain(t *testing.T) {
+	items := testReviewItems()
+	items[1].TodoItem.Provenance = &types.Provenance{
+		Provider:  "github",
+		Query:     "is:pr is:open team-review-requested:acme/platform",
+		Team:      "acme/platform",
+		FetchedAt: time.Date(2024, 3, 11, 9, 30, 0, 0, time.UTC),
+	}
+
+	m := NewReviewsModel(items, nil)
+	m, _ = pressKey(t, m, "down")
+	if strings.Contains(m.createReviewMarkdownContent(m.allItems[m.selectedItem]), "Why is this listed?") {
+		t.Fatal("Expected the explanation to be hidden initially")
+	}
+
+	m, _ = pressKey(t, m, "x")
+	content := m.createReviewMarkdownContent(m.allItems[m.selectedItem])
+	for _, want := range []string{"## 🔎 Why is this listed?", "**Team**: acme/platform", "`is:pr is:open team-review-requested:acme/platform`", "Mar 11, 2024 09:30:00"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected the explanation to contain '%s', got: %s", want, content)
+		}
+	}
+}
+
+func TestReviewsModel_SeverityList(t *testing.T) {
+	items := testReviewItems()
+	items[0].TodoItem.Severity = "critical"
+
+	m := NewReviewsModel(items, nil)
+	m.plain = true
+	list := m.renderLeftPanel(100)
+	if !strings.Contains(list, "[CRIT] Add feature") {
+		t.Errorf("Expected the marker before the title, got: %s", list)
+	}
+	if strings.Contains(list, "] Fix bug") {
+		t.Errorf("Expected no marker on reviews without severity, got: %s", list)
+	}
+
+	m.plain = false
+	list = m.renderLeftPanel(100)
+	if strings.Contains(list, "[CRIT]") || !strings.Contains(list, "● Add feature") {
+		t.Errorf("Expected a dot before the title, got: %s", list)
+	}
+}
+
+func TestReviewsModel_MergeQueue(t *testing.T) {
+	items := testReviewItems()
+	items[0].InMergeQueue = true
+	items[1].IsStale = true
+
+	m := NewReviewsModel(items, nil)
+	if m.allItems[0].Item.TodoItem.ID != "github-review-2" || m.allItems[1].Item.TodoItem.ID != "github-review-1" {
+		t.Errorf("Expected the queued review to be listed last, got %s then %s", m.allItems[0].Item.TodoItem.ID, m.allItems[1].Item.TodoItem.ID)
+	}
+
+	list := m.renderLeftPanel(100)
+	if !strings.Contains(list, "🚦") || strings.Count(list, "🚦") != 1 {
+		t.Errorf("Expected one 🚦 marker, got: %s", list)
+	}
+
+	details := m.createReviewMarkdownContent(m.allItems[1])
+	if !strings.Contains(details, "| **Merge Queue** | 🚦 Queued to be merged |") {
+		t.Errorf("Expected the merge queue in the detail panel, got:\n%s", details)
+	}
+}
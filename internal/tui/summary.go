@@ -12,6 +12,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"daily/internal/activity"
+	"daily/internal/terminal"
 )
 
 type urlCommand struct {
@@ -27,15 +28,19 @@ func (c urlCommand) SetStderr(w io.Writer) {}
 func (c urlCommand) SetStdin(r io.Reader)  {}
 
 type summaryModel struct {
-	summary       *activity.Summary
+	title         string
+	emptyMessage  string
 	activities    []activity.Activity
+	multiDay      bool // Activities are listed under a heading per day
 	cursor        int
+	marks         marks
 	leftViewport  viewportState
 	rightViewport viewportState
 	windowHeight  int
 	windowWidth   int
 	styles        *CommonStyles
 	glamourStyle  *glamour.TermRenderer
+	plain         bool // No colors, mark letters are shown unstyled
 }
 
 type viewportState struct {
@@ -50,6 +55,11 @@ func (m summaryModel) Init() tea.Cmd {
 func (m summaryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if cursor, ok := m.marks.handleKey(msg.String(), m.activityIDs(), m.cursor); ok {
+			m.cursor = cursor
+			m.updateLeftViewport()
+			return m, nil
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -87,6 +97,15 @@ func (m summaryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// activityIDs returns the IDs of the listed activities, which marks point at
+func (m summaryModel) activityIDs() []string {
+	ids := make([]string, len(m.activities))
+	for i, act := range m.activities {
+		ids[i] = act.ID
+	}
+	return ids
+}
+
 func (m *summaryModel) updateLeftViewport() {
 	if m.leftViewport.height <= 0 {
 		return
@@ -112,7 +131,7 @@ func (m summaryModel) View() string {
 	}
 
 	if len(m.activities) == 0 {
-		return m.styles.Header.Render("No activities found for this date.") +
+		return m.styles.Header.Render(m.emptyMessage) +
 			"\n\nPress q to quit"
 	}
 
@@ -123,8 +142,7 @@ func (m summaryModel) View() string {
 	}
 
 	// Header
-	title := fmt.Sprintf("📊 Daily Summary for %s", m.summary.Date.Format("January 2, 2006"))
-	header := RenderHeader(title, m.windowWidth)
+	header := RenderHeader(m.title, m.windowWidth)
 
 	// Create left and right panels
 	leftPanel := m.renderLeftPanel(dimensions.LeftWidth)
@@ -145,29 +163,38 @@ func (m summaryModel) renderLeftPanel(width int) string {
 	var content strings.Builder
 
 	// Navigation help
-	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • q: Quit"
+	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • m/': Mark/Jump • q: Quit"
 	adjustedWidth := max(20, width) // Same adjustment as in CreateBorderedPanel
 	content.WriteString(RenderHelpText(helpText, adjustedWidth-4))
 	content.WriteString("\n\n")
 
 	// Activities list
-	end := min(len(m.activities), m.leftViewport.offset+m.leftViewport.height-4) // Account for help text and padding
-
-	for i := m.leftViewport.offset; i < end; i++ {
+	for _, row := range m.listRows(m.leftViewport.offset, m.leftViewport.height-4) { // Account for help text and padding
+		if row.index < 0 {
+			content.WriteString(m.styles.Header.Render(row.heading))
+			content.WriteString("\n")
+			continue
+		}
+		i := row.index
 		act := m.activities[i]
 		isSelected := i == m.cursor
 
 		// Create activity display
 		timeStr := act.Timestamp.Format("15:04")
 		platformIcon := getPlatformIcon(act.Platform)
-		typeIcon := getTypeIcon(act.Type)
+		typeIcon := getActivityIcon(act)
 
 		// Truncate title to fit width
-		maxTitleWidth := max(5, adjustedWidth-15) // Account for time, icons, and padding, minimum 5 chars
+		gutter := m.marks.gutter(act.ID, isSelected, m.plain)
+		maxTitleWidth := max(5, adjustedWidth-15-lipgloss.Width(gutter)) // Account for mark, time, icons, and padding, minimum 5 chars
 		title := TruncateText(act.Title, maxTitleWidth)
 
 		var line strings.Builder
-		line.WriteString(fmt.Sprintf("%s %s %s %s", timeStr, platformIcon, typeIcon, title))
+		line.WriteString(fmt.Sprintf("%s%s %s %s %s", gutter, timeStr, platformIcon, typeIcon, title))
+
+		if act.Changes != nil {
+			line.WriteString(fmt.Sprintf(" %+d/-%d", act.Changes.Additions, act.Changes.Deletions))
+		}
 
 		if act.URL != "" {
 			line.WriteString(" 🔗")
@@ -188,17 +215,46 @@ func (m summaryModel) renderLeftPanel(width int) string {
 	return leftStyle.Render(content.String())
 }
 
+// listRow is a row of the activity list: an activity, or the heading of a day when index is -1
+type listRow struct {
+	index   int
+	heading string
+}
+
+// listRows returns the rows of the activity list fitting in height lines, from the activity at
+// start, or later when the date headings would push the cursor out of view
+func (m summaryModel) listRows(start, height int) []listRow {
+	for {
+		var rows []listRow
+		cursorShown := false
+		for i := start; i < len(m.activities) && len(rows) < height; i++ {
+			if m.multiDay && (i == start || !activity.SameDay(m.activities[i-1].Timestamp, m.activities[i].Timestamp)) {
+				rows = append(rows, listRow{index: -1, heading: "📅 " + m.activities[i].Timestamp.Format(activity.DayHeadingLayout)})
+				if len(rows) == height {
+					break
+				}
+			}
+			rows = append(rows, listRow{index: i})
+			cursorShown = cursorShown || i == m.cursor
+		}
+
+		if cursorShown || start >= m.cursor {
+			return rows
+		}
+		start++
+	}
+}
+
 // renderSinglePanelView renders a simplified single-panel view for narrow terminals
 func (m summaryModel) renderSinglePanelView() string {
 	var content strings.Builder
 
 	// Header
-	title := fmt.Sprintf("📊 Daily Summary for %s", m.summary.Date.Format("January 2, 2006"))
-	content.WriteString(RenderHeader(title, m.windowWidth))
+	content.WriteString(RenderHeader(m.title, m.windowWidth))
 	content.WriteString("\n")
 
 	// Navigation help
-	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • q: Quit"
+	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • m/': Mark/Jump • q: Quit"
 	content.WriteString(RenderHelpText(helpText, m.windowWidth))
 	content.WriteString("\n\n")
 
@@ -212,20 +268,30 @@ func (m summaryModel) renderSinglePanelView() string {
 		start = max(0, end-availableHeight)
 	}
 
-	for i := start; i < end; i++ {
+	for _, row := range m.listRows(start, availableHeight) {
+		if row.index < 0 {
+			content.WriteString(m.styles.Header.Render(row.heading))
+			content.WriteString("\n")
+			continue
+		}
+		i := row.index
 		act := m.activities[i]
 		isSelected := i == m.cursor
 
 		// Simple activity line
 		timeStr := act.Timestamp.Format("15:04")
 		platformIcon := getPlatformIcon(act.Platform)
-		typeIcon := getTypeIcon(act.Type)
+		typeIcon := getActivityIcon(act)
 
 		// Truncate title to fit
-		maxTitleWidth := max(5, m.windowWidth-15)
+		gutter := m.marks.gutter(act.ID, isSelected, m.plain)
+		maxTitleWidth := max(5, m.windowWidth-15-lipgloss.Width(gutter))
 		title := TruncateText(act.Title, maxTitleWidth)
 
-		line := fmt.Sprintf("%s %s %s %s", timeStr, platformIcon, typeIcon, title)
+		line := fmt.Sprintf("%s%s %s %s %s", gutter, timeStr, platformIcon, typeIcon, title)
+		if act.Changes != nil {
+			line += fmt.Sprintf(" %+d/-%d", act.Changes.Additions, act.Changes.Deletions)
+		}
 		if act.URL != "" {
 			line += " 🔗"
 		}
@@ -303,11 +369,26 @@ func (m summaryModel) createMarkdownContent(act activity.Activity) string {
 	md.WriteString(fmt.Sprintf("| **Time** | %s |\n", act.Timestamp.Format("15:04:05")))
 	md.WriteString(fmt.Sprintf("| **Platform** | %s %s |\n", getPlatformIcon(act.Platform), act.Platform))
 	md.WriteString(fmt.Sprintf("| **Type** | %s %s |\n", getTypeIcon(act.Type), string(act.Type)))
+	if act.IssueType != "" {
+		md.WriteString(fmt.Sprintf("| **Issue Type** | %s |\n", strings.TrimSpace(issueTypeIcon(act.IssueType)+" "+act.IssueType)))
+	}
+	for _, key := range activity.DetailKeys {
+		if value := act.Detail(key); value != "" {
+			md.WriteString(fmt.Sprintf("| **%s** | %s |\n", activity.DetailLabel(key), value))
+		}
+	}
 
 	if act.URL != "" {
 		md.WriteString(fmt.Sprintf("| **URL** | [🔗 Open Link](%s) |\n", act.URL))
 	}
 
+	if act.Changes != nil {
+		md.WriteString(fmt.Sprintf("| **Changes** | 📊 %s |\n", act.Changes.String()))
+		if act.Changes.Commits > 0 {
+			md.WriteString(fmt.Sprintf("| **Commits** | %d |\n", act.Changes.Commits))
+		}
+	}
+
 	// Description
 	if act.Description != "" {
 		md.WriteString("\n## Description\n\n")
@@ -342,14 +423,13 @@ func RunTUI(summary *activity.Summary) error {
 	return runTUIInternal(summary, false)
 }
 
-func runTUIInternal(summary *activity.Summary, force bool) error {
-	// Check if we're running in a terminal that supports TUI (unless forced)
-	if !force && !IsTerminalCapable() {
-		// Not in a TTY, fall back to text output
-		// We'll handle the fallback in the calling function
-		return fmt.Errorf("terminal does not support TUI")
-	}
+// RunMentionsTUI starts the TUI for a list of mentions, keeping the given order
+func RunMentionsTUI(mentions []activity.Activity) error {
+	title := fmt.Sprintf("💬 Mentions (%d)", len(mentions))
+	return runActivitiesTUI("mentions", title, "No mentions found.", mentions, false, false)
+}
 
+func runTUIInternal(summary *activity.Summary, force bool) error {
 	// Sort activities by timestamp
 	activities := make([]activity.Activity, len(summary.Activities))
 	copy(activities, summary.Activities)
@@ -357,6 +437,32 @@ func runTUIInternal(summary *activity.Summary, force bool) error {
 		return activities[i].Timestamp.Before(activities[j].Timestamp)
 	})
 
+	title := fmt.Sprintf("📊 Daily Summary for %s", summary.Date.Format("January 2, 2006"))
+	if summary.Period != "" {
+		title += fmt.Sprintf(" (%s)", summary.Period)
+	}
+	if len(summary.Goals) > 0 {
+		goals := make([]string, len(summary.Goals))
+		for i, goal := range summary.Goals {
+			goals[i] = goal.String()
+		}
+		title += "  🎯 " + strings.Join(goals, " · ")
+	}
+	return runActivitiesTUI("summary", title, "No activities found for this date.", activities, summary.SpansMultipleDays(), force)
+}
+
+func runActivitiesTUI(view, title, emptyMessage string, activities []activity.Activity, multiDay, force bool) error {
+	if err := CheckTerminal(terminal.Stdout()); err != nil {
+		return err
+	}
+
+	// Check if we're running in a terminal that supports TUI (unless forced)
+	if !force && !IsTerminalCapable() {
+		// Not in a TTY, fall back to text output
+		// We'll handle the fallback in the calling function
+		return fmt.Errorf("terminal does not support TUI")
+	}
+
 	// Initialize glamour renderer with simple fallback
 	var glamourStyle *glamour.TermRenderer
 	var glamourTheme string
@@ -372,11 +478,14 @@ func runTUIInternal(summary *activity.Summary, force bool) error {
 	}
 
 	m := summaryModel{
-		summary:      summary,
+		title:        title,
+		emptyMessage: emptyMessage,
 		activities:   activities,
+		multiDay:     multiDay,
 		cursor:       0,
 		styles:       NewCommonStyles(),
 		glamourStyle: glamourStyle,
+		plain:        terminal.Stdout().Plain(),
 		leftViewport: viewportState{
 			offset: 0,
 			height: 20, // Default height, will be updated on window size msg
@@ -387,22 +496,22 @@ func runTUIInternal(summary *activity.Summary, force bool) error {
 		},
 	}
 
-	// Run the TUI
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	_, err = p.Run()
-	if err != nil {
-		// If TUI fails for any reason, return error so caller can handle fallback
-		return err
-	}
-	return nil
+	// Run the TUI. If it fails for any reason, including a crash, the error is returned so
+	// the caller can fall back to text output
+	return runProgram(view, m, len(activities), tea.WithAltScreen())
 }
 
 // Icon functions for activities and platforms
 func getPlatformIcon(platform string) string {
 	icons := map[string]string{
-		"github":   "🐙",
-		"jira":     "🎫",
-		"obsidian": "📝",
+		"github":        "🐙",
+		"jira":          "🎫",
+		"obsidian":      "📝",
+		"confluence":    "📚",
+		"gitlab":        "🦊",
+		"calendar":      "🗓️",
+		"slack":         "💬",
+		"saved_queries": "🔎",
 	}
 
 	if icon, exists := icons[platform]; exists {
@@ -411,13 +520,69 @@ func getPlatformIcon(platform string) string {
 	return "📌"
 }
 
+// getActivityIcon returns the icon of an activity: its issue type for JIRA tickets and its
+// content type for Confluence contributions when the type has an icon, its activity type otherwise
+func getActivityIcon(act activity.Activity) string {
+	if act.Type == activity.ActivityTypeJiraTicket {
+		if icon := issueTypeIcon(act.IssueType); icon != "" {
+			return icon
+		}
+	}
+	if act.Type == activity.ActivityTypeConfluenceContribution {
+		if icon := contentTypeIcon(act.Detail(activity.DetailContentType)); icon != "" {
+			return icon
+		}
+	}
+	return getTypeIcon(act.Type)
+}
+
+// contentTypeIcon returns the icon of a Confluence content type, or "" for other types
+func contentTypeIcon(contentType string) string {
+	switch contentType {
+	case "page":
+		return "📄"
+	case "blogpost":
+		return "📰"
+	case "comment":
+		return "💬"
+	default:
+		return ""
+	}
+}
+
+// issueTypeIcon returns the icon of a JIRA issue type, or "" for other types
+func issueTypeIcon(issueType string) string {
+	switch strings.ToLower(issueType) {
+	case "bug":
+		return "🐛"
+	case "story":
+		return "📖"
+	case "task", "sub-task", "subtask":
+		return "✅"
+	default:
+		return ""
+	}
+}
+
 func getTypeIcon(actType activity.ActivityType) string {
 	icons := map[activity.ActivityType]string{
-		activity.ActivityTypeCommit:     "💾",
-		activity.ActivityTypePR:         "🔀",
-		activity.ActivityTypeIssue:      "🐛",
-		activity.ActivityTypeJiraTicket: "🎯",
-		activity.ActivityTypeNote:       "📄",
+		activity.ActivityTypeCommit:            "💾",
+		activity.ActivityTypePR:                "🔀",
+		activity.ActivityTypeIssue:             "🐛",
+		activity.ActivityTypeJiraTicket:        "🎯",
+		activity.ActivityTypeJiraResolved:      "🏁",
+		activity.ActivityTypeNote:              "📄",
+		activity.ActivityTypeMeeting:           "📅",
+		activity.ActivityTypeTaskCompleted:     "☑️",
+		activity.ActivityTypeConfluenceCreated: "🆕",
+		activity.ActivityTypeCI:                "⚙️",
+		activity.ActivityTypeDeployment:        "🚀",
+		activity.ActivityTypeWorklog:           "⏱️",
+		activity.ActivityTypeMention:           "💬",
+		activity.ActivityTypeSavedQuery:        "🔢",
+		activity.ActivityTypeComment:           "🗨️",
+		activity.ActivityTypeReview:            "👍",
+		activity.ActivityTypeMessage:           "🧵",
 	}
 
 	if icon, exists := icons[actType]; exists {
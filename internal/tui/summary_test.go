@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+)
+
+func TestSummaryModel_ListRows(t *testing.T) {
+	at := func(day, hour int) activity.Activity {
+		return activity.Activity{Timestamp: time.Date(2024, 9, day, hour, 0, 0, 0, time.UTC)}
+	}
+	activities := []activity.Activity{at(9, 9), at(9, 10), at(10, 9), at(11, 9), at(11, 10)}
+
+	tests := []struct {
+		name     string
+		multiDay bool
+		start    int
+		height   int
+		cursor   int
+		expected string
+	}{
+		{"single day", false, 0, 10, 0, "[0 1 2 3 4]"},
+		{"day headings", true, 0, 10, 0, "[Monday, Sep 9 0 1 Tuesday, Sep 10 2 Wednesday, Sep 11 3 4]"},
+		{"heading of the first row", true, 1, 10, 1, "[Monday, Sep 9 1 Tuesday, Sep 10 2 Wednesday, Sep 11 3 4]"},
+		{"headings don't hide the cursor", true, 0, 4, 2, "[Monday, Sep 9 1 Tuesday, Sep 10 2]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := summaryModel{activities: activities, multiDay: tt.multiDay, cursor: tt.cursor}
+
+			var rows []string
+			for _, row := range m.listRows(tt.start, tt.height) {
+				if row.index < 0 {
+					rows = append(rows, row.heading[len("📅 "):])
+				} else {
+					rows = append(rows, fmt.Sprint(row.index))
+				}
+			}
+			if got := fmt.Sprint(rows); got != tt.expected {
+				t.Errorf("Expected rows %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
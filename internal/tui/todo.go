@@ -4,17 +4,21 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss/v2"
 
+	"daily/internal/activity"
+	"daily/internal/terminal"
 	"daily/internal/tui/types"
 )
 
 // TodoModel represents the state of the todo TUI
 type TodoModel struct {
 	todoItems     types.TodoItems
+	title         string // e.g. "📋 Todo Items", followed by the number of items in the header
 	selectedItem  int
 	width         int
 	height        int
@@ -23,6 +27,9 @@ type TodoModel struct {
 	leftViewport  viewportState
 	rightViewport viewportState
 	glamourStyle  *glamour.TermRenderer
+	explain       bool // Show why the selected item is listed
+	plain         bool // No colors, severities are shown as markers such as [CRIT]
+	marks         marks
 }
 
 // TodoListItem represents an item in the navigation list
@@ -30,10 +37,36 @@ type TodoListItem struct {
 	Item        types.TodoItem
 	Type        string // "open_pr", "pending_review", "assigned_ticket"
 	DisplayText string
+	Score       int      // Up-next score, set with Reasons
+	Reasons     []string // Signals of the up-next score, e.g. "overdue +40"
 }
 
 // NewTodoModel creates a new todo TUI model
 func NewTodoModel(todoItems types.TodoItems) TodoModel {
+	model := newTodoModel("📋 Todo Items")
+	model.todoItems = todoItems
+	model.buildItemsList()
+	return model
+}
+
+// NewUpNextModel creates a todo TUI model listing the ranked up-next items, keeping their order
+func NewUpNextModel(items []types.UpNextItem) TodoModel {
+	model := newTodoModel("⏭️ Up Next")
+	model.allItems = make([]TodoListItem, len(items))
+	for i, item := range items {
+		model.allItems[i] = TodoListItem{
+			Item:        item.Item,
+			Type:        item.Kind,
+			DisplayText: item.Item.Title,
+			Score:       item.Score,
+			Reasons:     item.Reasons,
+		}
+	}
+	return model
+}
+
+// newTodoModel creates a todo TUI model without items
+func newTodoModel(title string) TodoModel {
 	// Initialize glamour renderer
 	var glamourStyle *glamour.TermRenderer
 	var glamourTheme string
@@ -48,9 +81,10 @@ func NewTodoModel(todoItems types.TodoItems) TodoModel {
 	}
 
 	model := TodoModel{
-		todoItems:    todoItems,
+		title:        title,
 		styles:       NewCommonStyles(),
 		glamourStyle: glamourStyle,
+		plain:        terminal.Stdout().Plain(),
 		leftViewport: viewportState{
 			offset: 0,
 			height: 20, // Default height, will be updated on window size msg
@@ -60,7 +94,6 @@ func NewTodoModel(todoItems types.TodoItems) TodoModel {
 			height: 20, // Default height, will be updated on window size msg
 		},
 	}
-	model.buildItemsList()
 	return model
 }
 
@@ -85,6 +118,33 @@ func (m *TodoModel) buildItemsList() {
 		})
 	}
 
+	// Add open GitLab merge requests
+	for _, item := range m.todoItems.GitLab.OpenMRs {
+		m.allItems = append(m.allItems, TodoListItem{
+			Item:        item,
+			Type:        "open_mr",
+			DisplayText: fmt.Sprintf("🦊 %s", item.Title),
+		})
+	}
+
+	// Add GitLab merge requests awaiting the user's approval
+	for _, item := range m.todoItems.GitLab.PendingReviews {
+		m.allItems = append(m.allItems, TodoListItem{
+			Item:        item,
+			Type:        "pending_mr_review",
+			DisplayText: fmt.Sprintf("👁️ %s", item.Title),
+		})
+	}
+
+	// Add assigned GitLab issues
+	for _, item := range m.todoItems.GitLab.AssignedIssues {
+		m.allItems = append(m.allItems, TodoListItem{
+			Item:        item,
+			Type:        "gitlab_issue",
+			DisplayText: fmt.Sprintf("🦊 %s", item.Title),
+		})
+	}
+
 	// Add assigned tickets
 	for _, item := range m.todoItems.JIRA.AssignedTickets {
 		m.allItems = append(m.allItems, TodoListItem{
@@ -94,6 +154,33 @@ func (m *TodoModel) buildItemsList() {
 		})
 	}
 
+	// Add JIRA mentions
+	for _, item := range m.todoItems.JIRA.Mentions {
+		m.allItems = append(m.allItems, TodoListItem{
+			Item:        item,
+			Type:        "jira_mention",
+			DisplayText: fmt.Sprintf("💬 %s", item.Title),
+		})
+	}
+
+	// Add reported JIRA issues
+	for _, item := range m.todoItems.JIRA.Reported {
+		m.allItems = append(m.allItems, TodoListItem{
+			Item:        item,
+			Type:        "reported_issue",
+			DisplayText: fmt.Sprintf("📣 %s", item.Title),
+		})
+	}
+
+	// Add watched JIRA issues
+	for _, item := range m.todoItems.JIRA.Watched {
+		m.allItems = append(m.allItems, TodoListItem{
+			Item:        item,
+			Type:        "watched_issue",
+			DisplayText: fmt.Sprintf("👀 %s", item.Title),
+		})
+	}
+
 	// Add Obsidian tasks
 	for _, item := range m.todoItems.Obsidian.Tasks {
 		m.allItems = append(m.allItems, TodoListItem{
@@ -103,10 +190,93 @@ func (m *TodoModel) buildItemsList() {
 		})
 	}
 
+	// Add Confluence mentions
+	for _, item := range m.todoItems.Confluence.Mentions {
+		m.allItems = append(m.allItems, TodoListItem{
+			Item:        item,
+			Type:        "confluence_mention",
+			DisplayText: fmt.Sprintf("📋 %s", item.Title),
+		})
+	}
+
+	// Add comments on the user's Confluence pages
+	for _, item := range m.todoItems.Confluence.CommentsOnMyPages {
+		m.allItems = append(m.allItems, TodoListItem{
+			Item:        item,
+			Type:        "confluence_comment",
+			DisplayText: fmt.Sprintf("💭 %s", item.Title),
+		})
+	}
+
+	// Add inline tasks of Confluence pages assigned to the user
+	for _, item := range m.todoItems.Confluence.Tasks {
+		m.allItems = append(m.allItems, TodoListItem{
+			Item:        item,
+			Type:        "confluence_task",
+			DisplayText: fmt.Sprintf("☑️ %s", item.Title),
+		})
+	}
+
+	// Add watched Confluence pages updated by others
+	for _, item := range m.todoItems.Confluence.Watched {
+		m.allItems = append(m.allItems, TodoListItem{
+			Item:        item,
+			Type:        "watched_page",
+			DisplayText: fmt.Sprintf("🔄 %s", item.Title),
+		})
+	}
+
+	// Add Slack messages saved for later
+	for _, item := range m.todoItems.Slack.Saved {
+		m.allItems = append(m.allItems, TodoListItem{
+			Item:        item,
+			Type:        "saved_message",
+			DisplayText: fmt.Sprintf("🔖 %s", item.Title),
+		})
+	}
+
 	// Sort by updated time (most recent first)
 	sort.Slice(m.allItems, func(i, j int) bool {
 		return m.allItems[i].Item.UpdatedAt.After(m.allItems[j].Item.UpdatedAt)
 	})
+	sortObsidianTasks(m.allItems)
+
+	// Open PRs ready to merge come first, as they only wait for the user to merge them
+	sort.SliceStable(m.allItems, func(i, j int) bool {
+		return isReadyToMerge(m.allItems[i]) && !isReadyToMerge(m.allItems[j])
+	})
+}
+
+// isReadyToMerge reports whether an item is an open PR of the user ready to merge
+func isReadyToMerge(item TodoListItem) bool {
+	return item.Type == "open_pr" && item.Item.ReadyToMerge
+}
+
+// sortObsidianTasks orders the Obsidian tasks of a list by priority, then by due date (soonest
+// first, tasks without one last), keeping the places they hold among the other items
+func sortObsidianTasks(items []TodoListItem) {
+	var slots []int
+	var tasks []TodoListItem
+	for i, item := range items {
+		if item.Type == "obsidian_task" {
+			slots = append(slots, i)
+			tasks = append(tasks, item)
+		}
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i].Item, tasks[j].Item
+		if rankA, rankB := types.TaskPriorityRank(a.Priority), types.TaskPriorityRank(b.Priority); rankA != rankB {
+			return rankA < rankB
+		}
+		if a.DueDate == nil || b.DueDate == nil {
+			return a.DueDate != nil && b.DueDate == nil
+		}
+		return a.DueDate.Before(*b.DueDate)
+	})
+	for i, slot := range slots {
+		items[slot] = tasks[i]
+	}
 }
 
 func (m TodoModel) Init() tea.Cmd {
@@ -123,6 +293,11 @@ func (m TodoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateLeftViewport()
 		return m, nil
 	case tea.KeyMsg:
+		if cursor, ok := m.marks.handleKey(msg.String(), m.itemIDs(), m.selectedItem); ok {
+			m.selectedItem = cursor
+			m.updateLeftViewport()
+			return m, nil
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -138,6 +313,8 @@ func (m TodoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "end", "G":
 			m.selectedItem = len(m.allItems) - 1
 			m.updateLeftViewport()
+		case "x":
+			m.explain = !m.explain
 		case "enter", " ":
 			if m.selectedItem < len(m.allItems) && m.allItems[m.selectedItem].Item.URL != "" {
 				url := m.allItems[m.selectedItem].Item.URL
@@ -149,6 +326,15 @@ func (m TodoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// itemIDs returns the IDs of the listed items, which marks point at
+func (m TodoModel) itemIDs() []string {
+	ids := make([]string, len(m.allItems))
+	for i, item := range m.allItems {
+		ids[i] = item.Item.ID
+	}
+	return ids
+}
+
 func (m *TodoModel) updateLeftViewport() {
 	if m.leftViewport.height <= 0 {
 		return
@@ -175,7 +361,7 @@ func (m TodoModel) View() string {
 
 	if len(m.allItems) == 0 {
 		return m.styles.Base.Render(
-			m.styles.Header.Render("📋 Todo Items") + "\n" +
+			m.styles.Header.Render(m.title) + "\n" +
 				"No pending items found.\n\n" +
 				m.styles.Help.Render("Press 'q' to quit"),
 		)
@@ -188,7 +374,7 @@ func (m TodoModel) View() string {
 	}
 
 	// Header
-	title := fmt.Sprintf("📋 Todo Items (%d)", len(m.allItems))
+	title := fmt.Sprintf("%s (%d)", m.title, len(m.allItems))
 	header := RenderHeader(title, m.width)
 
 	// Create left and right panels
@@ -210,7 +396,7 @@ func (m TodoModel) renderLeftPanel(width int) string {
 	var content strings.Builder
 
 	// Navigation help
-	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • q: Quit"
+	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • m/': Mark/Jump • x: Explain • q: Quit"
 	adjustedWidth := max(20, width) // Same adjustment as in CreateBorderedPanel
 	content.WriteString(RenderHelpText(helpText, adjustedWidth-4))
 	content.WriteString("\n\n")
@@ -225,25 +411,16 @@ func (m TodoModel) renderLeftPanel(width int) string {
 		// Create todo item display
 		timeStr := item.Item.UpdatedAt.Format("Jan 2")
 
-		// Get appropriate icon for item type
-		var icon string
-		switch item.Type {
-		case "open_pr":
-			icon = "🔀"
-		case "pending_review":
-			icon = "👁️"
-		case "assigned_ticket":
-			icon = "🎯"
-		default:
-			icon = "📋"
-		}
+		icon := todoItemIcon(item)
 
 		// Truncate title to fit width
-		maxTitleWidth := max(5, adjustedWidth-15) // Account for time, icons, and padding
-		title := TruncateText(item.Item.Title, maxTitleWidth)
+		gutter := m.marks.gutter(item.Item.ID, isSelected, m.plain)
+		prefix := severityPrefix(item.Item.Severity, isSelected, m.plain)
+		maxTitleWidth := max(5, adjustedWidth-15-lipgloss.Width(gutter)-lipgloss.Width(prefix)) // Account for mark, time, icons, severity, and padding
+		title := TruncateText(item.Item.Title+types.SubtasksSuffix(len(item.Item.Subtasks)), maxTitleWidth)
 
 		var line strings.Builder
-		line.WriteString(fmt.Sprintf("%s %s %s", timeStr, icon, title))
+		line.WriteString(fmt.Sprintf("%s%s %s %s%s", gutter, timeStr, icon, prefix, title))
 
 		if item.Item.URL != "" {
 			line.WriteString(" 🔗")
@@ -304,6 +481,18 @@ func (m TodoModel) createTodoMarkdownContent(item TodoListItem) string {
 	// Title
 	md.WriteString(fmt.Sprintf("# %s\n\n", item.Item.Title))
 
+	if m.explain {
+		md.WriteString(ExplanationMarkdown(item.Item.Provenance))
+	}
+
+	if len(item.Reasons) > 0 {
+		md.WriteString(fmt.Sprintf("## Why it's up next\n\nScore **%d**\n\n", item.Score))
+		for _, reason := range item.Reasons {
+			md.WriteString(fmt.Sprintf("- %s\n", reason))
+		}
+		md.WriteString("\n")
+	}
+
 	// Metadata table
 	md.WriteString("## Details\n\n")
 	md.WriteString("| Field | Value |\n")
@@ -312,27 +501,148 @@ func (m TodoModel) createTodoMarkdownContent(item TodoListItem) string {
 
 	// Type-specific information
 	switch item.Type {
+	case "review_request":
+		md.WriteString("| **Type** | 👁️ Review Request |\n")
 	case "open_pr":
 		md.WriteString("| **Type** | 🔀 Open Pull Request |\n")
 	case "pending_review":
 		md.WriteString("| **Type** | 👁️ Pending Review |\n")
+	case "open_mr":
+		md.WriteString("| **Type** | 🔀 Open Merge Request |\n")
+	case "pending_mr_review":
+		md.WriteString("| **Type** | 👁️ Pending MR Review |\n")
+	case "gitlab_issue":
+		md.WriteString("| **Type** | 🦊 Assigned GitLab Issue |\n")
 	case "assigned_ticket":
 		md.WriteString("| **Type** | 🎯 Assigned Ticket |\n")
+	case "jira_mention":
+		md.WriteString("| **Type** | 💬 JIRA Mention |\n")
+	case "reported_issue":
+		md.WriteString("| **Type** | 📣 Reported Issue |\n")
+	case "watched_issue":
+		md.WriteString("| **Type** | 👀 Watched Issue |\n")
+	case "confluence_mention":
+		md.WriteString("| **Type** | 📋 Confluence Mention |\n")
+	case "confluence_comment":
+		md.WriteString("| **Type** | 💭 Comment on My Page |\n")
+	case "confluence_task":
+		md.WriteString("| **Type** | ☑️ Confluence Task |\n")
+	case "watched_page":
+		md.WriteString("| **Type** | 🔄 Watched Page |\n")
+	case "saved_message":
+		md.WriteString("| **Type** | 🔖 Saved Slack Message |\n")
 	default:
 		md.WriteString("| **Type** | 📋 Todo Item |\n")
 	}
 
+	if item.Item.IssueType != "" {
+		md.WriteString(fmt.Sprintf("| **Issue Type** | %s |\n", strings.TrimSpace(issueTypeIcon(item.Item.IssueType)+" "+item.Item.IssueType)))
+	}
+
+	if item.Item.Milestone != "" {
+		md.WriteString(fmt.Sprintf("| **Milestone** | 🎯 %s |\n", item.Item.Milestone))
+	}
+
+	if item.Item.ProjectStatus != "" {
+		md.WriteString(fmt.Sprintf("| **Project Status** | %s |\n", item.Item.ProjectStatus))
+	}
+
+	if isReadyToMerge(item) {
+		md.WriteString("| **Merge** | 🎉 Approved, ready to merge |\n")
+	}
+
+	if item.Item.StatusSection != "" {
+		md.WriteString(fmt.Sprintf("| **Status** | %s %s |\n", statusCategoryIcon(item.Item.StatusCategory), item.Item.StatusSection))
+	}
+
+	for _, key := range activity.DetailKeys {
+		// The status section above already shows the status
+		if key == activity.DetailStatus && item.Item.StatusSection != "" {
+			continue
+		}
+		if value := activity.LookupDetail(item.Item.Details, item.Item.Description, item.Item.URL, key); value != "" {
+			md.WriteString(fmt.Sprintf("| **%s** | %s |\n", activity.DetailLabel(key), value))
+		}
+	}
+
+	if item.Item.Priority != "" {
+		md.WriteString(fmt.Sprintf("| **Priority** | %s |\n", item.Item.Priority))
+	}
+
+	if item.Item.DueDate != nil {
+		due := item.Item.DueDate.Format("Jan 2, 2006")
+		if item.Item.IsOverdue {
+			due = "⚠️ " + due + " (overdue)"
+		}
+		md.WriteString(fmt.Sprintf("| **Due** | %s |\n", due))
+	}
+
+	if item.Item.Sprint != "" {
+		md.WriteString(fmt.Sprintf("| **Sprint** | %s |\n", item.Item.Sprint))
+	}
+
+	if item.Item.Context != "" {
+		md.WriteString(fmt.Sprintf("| **Context** | %s |\n", item.Item.Context))
+	}
+
+	if item.Item.ParentTask != "" {
+		md.WriteString(fmt.Sprintf("| **Parent Task** | %s |\n", item.Item.ParentTask))
+	}
+
 	if item.Item.URL != "" {
 		md.WriteString(fmt.Sprintf("| **URL** | [🔗 Open Link](%s) |\n", item.Item.URL))
 	}
 
-	// Description
-	if item.Item.Description != "" {
+	// Description, left out when it is the excerpt shown below
+	if item.Item.Description != "" && item.Item.Description != item.Item.Excerpt {
 		md.WriteString("\n## Description\n\n")
 		md.WriteString(item.Item.Description)
 		md.WriteString("\n\n")
 	}
 
+	// Text around a Confluence mention
+	if item.Item.Excerpt != "" {
+		md.WriteString("\n## Excerpt\n\n")
+		md.WriteString("> " + item.Item.Excerpt + "\n\n")
+	}
+
+	// Lines around an Obsidian task in its note, quoted line by line
+	if item.Item.Snippet != "" {
+		md.WriteString("## Context\n\n")
+		for _, line := range strings.Split(item.Item.Snippet, "\n") {
+			md.WriteString("> " + line + "  \n")
+		}
+		md.WriteString("\n")
+	}
+
+	// Subtasks rolled up under the ticket
+	if len(item.Item.Subtasks) > 0 {
+		md.WriteString(fmt.Sprintf("## Subtasks (%d pending)\n\n", len(item.Item.Subtasks)))
+		for _, subtask := range item.Item.Subtasks {
+			md.WriteString(fmt.Sprintf("- %s %s · %s\n", statusCategoryIcon(subtask.StatusCategory), subtask.Title, subtask.Description))
+		}
+		md.WriteString("\n")
+	}
+
+	// Notes an Obsidian task links to
+	if len(item.Item.RelatedNotes) > 0 {
+		md.WriteString("## Related Notes\n\n")
+		for _, note := range item.Item.RelatedNotes {
+			md.WriteString(fmt.Sprintf("- 📝 %s\n", note))
+		}
+		md.WriteString("\n")
+	}
+
+	// Latest comments
+	if len(item.Item.Comments) > 0 {
+		md.WriteString("## 💬 Latest Comments\n\n")
+		now := time.Now()
+		for _, comment := range item.Item.Comments {
+			md.WriteString(fmt.Sprintf("**%s** · %s\n\n", comment.Author, FormatRelativeTime(comment.CreatedAt, now)))
+			md.WriteString(fmt.Sprintf("> %s\n\n", comment.Body))
+		}
+	}
+
 	// Tags
 	if len(item.Item.Tags) > 0 {
 		md.WriteString("## Tags\n\n")
@@ -353,12 +663,12 @@ func (m TodoModel) renderSinglePanelView() string {
 	var content strings.Builder
 
 	// Header
-	title := fmt.Sprintf("📋 Todo Items (%d)", len(m.allItems))
+	title := fmt.Sprintf("%s (%d)", m.title, len(m.allItems))
 	content.WriteString(RenderHeader(title, m.width))
 	content.WriteString("\n")
 
 	// Navigation help
-	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • q: Quit"
+	helpText := "↑/↓ j/k: Navigate • Enter: Open URL • m/': Mark/Jump • x: Explain • q: Quit"
 	content.WriteString(RenderHelpText(helpText, m.width))
 	content.WriteString("\n\n")
 
@@ -379,24 +689,15 @@ func (m TodoModel) renderSinglePanelView() string {
 		// Simple todo item line
 		timeStr := item.Item.UpdatedAt.Format("Jan 2")
 
-		// Get appropriate icon for item type
-		var icon string
-		switch item.Type {
-		case "open_pr":
-			icon = "🔀"
-		case "pending_review":
-			icon = "👁️"
-		case "assigned_ticket":
-			icon = "🎯"
-		default:
-			icon = "📋"
-		}
+		icon := todoItemIcon(item)
 
 		// Truncate title to fit
-		maxTitleWidth := max(5, m.width-15)
-		title := TruncateText(item.Item.Title, maxTitleWidth)
+		gutter := m.marks.gutter(item.Item.ID, isSelected, m.plain)
+		prefix := severityPrefix(item.Item.Severity, isSelected, m.plain)
+		maxTitleWidth := max(5, m.width-15-lipgloss.Width(gutter)-lipgloss.Width(prefix))
+		title := TruncateText(item.Item.Title+types.SubtasksSuffix(len(item.Item.Subtasks)), maxTitleWidth)
 
-		line := fmt.Sprintf("%s %s %s", timeStr, icon, title)
+		line := fmt.Sprintf("%s%s %s %s%s", gutter, timeStr, icon, prefix, title)
 		if item.Item.URL != "" {
 			line += " 🔗"
 		}
@@ -416,6 +717,10 @@ func (m TodoModel) renderSinglePanelView() string {
 			content.WriteString(descStyle.Render(desc))
 			content.WriteString("\n")
 		}
+		if m.explain {
+			content.WriteString(TruncateText(explanationLine(item.Item.Provenance), m.width-4))
+			content.WriteString("\n")
+		}
 	}
 
 	// Scroll indicator
@@ -427,16 +732,85 @@ func (m TodoModel) renderSinglePanelView() string {
 	return content.String()
 }
 
-// RunTodoTUI starts the todo TUI application
+// RunTodoTUI starts the todo TUI application. It returns ErrCrashed when the TUI panicked.
 func RunTodoTUI(todoItems types.TodoItems) error {
+	if err := CheckTerminal(terminal.Stdout()); err != nil {
+		return err
+	}
+
 	model := NewTodoModel(todoItems)
+	return runProgram("todo", model, len(model.allItems), tea.WithAltScreen(), tea.WithMouseCellMotion())
+}
 
-	p := tea.NewProgram(
-		model,
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
+// RunUpNextTUI starts the TUI for the ranked up-next items. It returns ErrCrashed when the TUI
+// panicked.
+func RunUpNextTUI(items []types.UpNextItem) error {
+	if err := CheckTerminal(terminal.Stdout()); err != nil {
+		return err
+	}
+
+	model := NewUpNextModel(items)
+	return runProgram("up-next", model, len(model.allItems), tea.WithAltScreen(), tea.WithMouseCellMotion())
+}
 
-	_, err := p.Run()
-	return err
+// todoItemIcon returns the list icon of a todo item: a warning when overdue, the issue type
+// then the status category for JIRA tickets, and the item type otherwise
+func todoItemIcon(item TodoListItem) string {
+	if item.Item.IsOverdue {
+		return "⚠️"
+	}
+
+	switch item.Type {
+	case "open_pr":
+		if item.Item.ReadyToMerge {
+			return "🎉"
+		}
+		return "🔀"
+	case "pending_review", "review_request", "pending_mr_review":
+		return "👁️"
+	case "open_mr":
+		return "🔀"
+	case "gitlab_issue":
+		return "🦊"
+	case "assigned_ticket":
+		if icon := issueTypeIcon(item.Item.IssueType); icon != "" {
+			return icon
+		}
+		if item.Item.StatusCategory != "" {
+			return statusCategoryIcon(item.Item.StatusCategory)
+		}
+		return "🎯"
+	case "jira_mention":
+		return "💬"
+	case "reported_issue":
+		return "📣"
+	case "watched_issue":
+		return "👀"
+	case "confluence_mention":
+		return "📋"
+	case "confluence_comment":
+		return "💭"
+	case "confluence_task":
+		return "☑️"
+	case "watched_page":
+		return "🔄"
+	case "saved_message":
+		return "🔖"
+	default:
+		return "📋"
+	}
+}
+
+// statusCategoryIcon returns the icon of a JIRA status category
+func statusCategoryIcon(category string) string {
+	switch category {
+	case "new":
+		return "⚪"
+	case "indeterminate":
+		return "🔵"
+	case "done":
+		return "🟢"
+	default:
+		return "🎯"
+	}
 }
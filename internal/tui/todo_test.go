@@ -0,0 +1,332 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"daily/internal/activity"
+	"daily/internal/tui/types"
+)
+
+func TestTodoItemIcon(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     TodoListItem
+		expected string
+	}{
+		{"bug", TodoListItem{Type: "assigned_ticket", Item: types.TodoItem{IssueType: "Bug", StatusCategory: "new"}}, "🐛"},
+		{"story", TodoListItem{Type: "assigned_ticket", Item: types.TodoItem{IssueType: "Story"}}, "📖"},
+		{"sub-task", TodoListItem{Type: "assigned_ticket", Item: types.TodoItem{IssueType: "Sub-task"}}, "✅"},
+		{"unknown type falls back to status", TodoListItem{Type: "assigned_ticket", Item: types.TodoItem{IssueType: "Epic", StatusCategory: "indeterminate"}}, "🔵"},
+		{"no type or status", TodoListItem{Type: "assigned_ticket"}, "🎯"},
+		{"overdue wins", TodoListItem{Type: "assigned_ticket", Item: types.TodoItem{IssueType: "Bug", IsOverdue: true}}, "⚠️"},
+		{"open PR", TodoListItem{Type: "open_pr"}, "🔀"},
+		{"open PR ready to merge", TodoListItem{Type: "open_pr", Item: types.TodoItem{ReadyToMerge: true}}, "🎉"},
+		{"Confluence mention", TodoListItem{Type: "confluence_mention"}, "📋"},
+		{"comment on my page", TodoListItem{Type: "confluence_comment"}, "💭"},
+		{"confluence task", TodoListItem{Type: "confluence_task"}, "☑️"},
+		{"watched page", TodoListItem{Type: "watched_page"}, "🔄"},
+		{"open MR", TodoListItem{Type: "open_mr"}, "🔀"},
+		{"pending MR review", TodoListItem{Type: "pending_mr_review"}, "👁️"},
+		{"GitLab issue", TodoListItem{Type: "gitlab_issue"}, "🦊"},
+		{"saved message", TodoListItem{Type: "saved_message"}, "🔖"},
+		{"watched issue keeps its section icon", TodoListItem{Type: "watched_issue", Item: types.TodoItem{IssueType: "Bug"}}, "👀"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := todoItemIcon(tt.item); got != tt.expected {
+				t.Errorf("Expected icon %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetActivityIcon(t *testing.T) {
+	tests := []struct {
+		name     string
+		act      activity.Activity
+		expected string
+	}{
+		{"jira bug", activity.Activity{Type: activity.ActivityTypeJiraTicket, IssueType: "bug"}, "🐛"},
+		{"jira task", activity.Activity{Type: activity.ActivityTypeJiraTicket, IssueType: "Task"}, "✅"},
+		{"unknown issue type", activity.Activity{Type: activity.ActivityTypeJiraTicket, IssueType: "Spike"}, "🎯"},
+		{"resolved keeps its icon", activity.Activity{Type: activity.ActivityTypeJiraResolved, IssueType: "Bug"}, "🏁"},
+		{"confluence blogpost", activity.Activity{Type: activity.ActivityTypeConfluenceContribution,
+			Details: activity.NewDetails(activity.DetailContentType, "blogpost")}, "📰"},
+		{"confluence comment", activity.Activity{Type: activity.ActivityTypeConfluenceContribution,
+			Details: activity.NewDetails(activity.DetailContentType, "comment")}, "💬"},
+		{"other activity", activity.Activity{Type: activity.ActivityTypeCommit}, "💾"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getActivityIcon(tt.act); got != tt.expected {
+				t.Errorf("Expected icon %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCreateTodoMarkdownContent_Subtasks(t *testing.T) {
+	item := TodoListItem{Type: "assigned_ticket", Item: types.TodoItem{
+		ID:    "jira-PROJ-1",
+		Title: "PROJ-1: Checkout",
+		Subtasks: []types.TodoItem{
+			{Title: "PROJ-2: API", Description: "Status: In Review", StatusCategory: "indeterminate"},
+			{Title: "PROJ-3: UI", Description: "Status: Open", StatusCategory: "new"},
+		},
+	}}
+
+	content := TodoModel{}.createTodoMarkdownContent(item)
+	for _, expected := range []string{"## Subtasks (2 pending)", "- 🔵 PROJ-2: API · Status: In Review", "- ⚪ PROJ-3: UI · Status: Open"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected detail panel to contain %q, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestCreateTodoMarkdownContent_ObsidianContext(t *testing.T) {
+	item := TodoListItem{Type: "obsidian_task", Item: types.TodoItem{
+		ID:         "obsidian-task-Meeting Notes.md:5",
+		Title:      "Send the slides",
+		Context:    "Client X › Meeting 2024-05-12",
+		ParentTask: "Follow up",
+	}}
+
+	content := TodoModel{}.createTodoMarkdownContent(item)
+	for _, expected := range []string{"| **Context** | Client X › Meeting 2024-05-12 |", "| **Parent Task** | Follow up |"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected detail panel to contain %q, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestCreateTodoMarkdownContent_ObsidianSnippet(t *testing.T) {
+	item := TodoListItem{Type: "obsidian_task", Item: types.TodoItem{
+		ID:      "obsidian-task-Meeting Notes.md:5",
+		Title:   "Send the slides",
+		Snippet: "Sam asked for the deck\nDue before the board meeting",
+	}}
+
+	content := TodoModel{}.createTodoMarkdownContent(item)
+	expected := "## Context\n\n> Sam asked for the deck  \n> Due before the board meeting  \n"
+	if !strings.Contains(content, expected) {
+		t.Errorf("Expected detail panel to contain %q, got:\n%s", expected, content)
+	}
+
+	item.Item.Snippet = ""
+	if content := (TodoModel{}).createTodoMarkdownContent(item); strings.Contains(content, "## Context") {
+		t.Errorf("Expected no context section, got:\n%s", content)
+	}
+}
+
+func TestCreateTodoMarkdownContent_RelatedNotes(t *testing.T) {
+	item := TodoListItem{Type: "obsidian_task", Item: types.TodoItem{
+		ID:           "obsidian-task-tasks.md:3",
+		Title:        "Review Project Phoenix spec",
+		RelatedNotes: []string{"Project Phoenix", "Roadmap"},
+	}}
+
+	content := TodoModel{}.createTodoMarkdownContent(item)
+	for _, expected := range []string{"## Related Notes", "- 📝 Project Phoenix\n- 📝 Roadmap\n"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected detail panel to contain %q, got:\n%s", expected, content)
+		}
+	}
+
+	item.Item.RelatedNotes = nil
+	if content := (TodoModel{}).createTodoMarkdownContent(item); strings.Contains(content, "Related Notes") {
+		t.Errorf("Expected no related notes section, got:\n%s", content)
+	}
+}
+
+func TestCreateTodoMarkdownContent_Details(t *testing.T) {
+	tests := []struct {
+		name       string
+		item       types.TodoItem
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:     "structured details",
+			item:     types.TodoItem{Title: "Fix login", Details: map[string]string{"repo": "owner/repo", "state": "open"}},
+			expected: []string{"| **Repository** | owner/repo |", "| **State** | open |"},
+		},
+		{
+			name:     "legacy item without details",
+			item:     types.TodoItem{Title: "Fix login", Description: "Open PR in owner/repo", URL: "https://github.com/owner/repo/pull/1"},
+			expected: []string{"| **Repository** | owner/repo |"},
+		},
+		{
+			name:       "status shown by its section",
+			item:       types.TodoItem{Title: "PROJ-1: Checkout", StatusSection: "In Progress", Details: map[string]string{"project": "PROJ", "status": "In Progress"}},
+			expected:   []string{"| **Project** | PROJ |"},
+			unexpected: []string{"| **Status** | In Progress |"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := TodoModel{}.createTodoMarkdownContent(TodoListItem{Type: "open_pr", Item: tt.item})
+			for _, expected := range tt.expected {
+				if !strings.Contains(content, expected) {
+					t.Errorf("Expected detail panel to contain %q, got:\n%s", expected, content)
+				}
+			}
+			for _, unexpected := range tt.unexpected {
+				if strings.Contains(content, unexpected) {
+					t.Errorf("Expected detail panel not to contain %q, got:\n%s", unexpected, content)
+				}
+			}
+		})
+	}
+}
+
+func TestTodoModel_SeverityList(t *testing.T) {
+	m := NewTodoModel(types.TodoItems{
+		JIRA: types.JIRATodos{AssignedTickets: []types.TodoItem{
+			{ID: "jira-OPS-1", Title: "Checkout is down", Priority: "Blocker", Severity: "critical"},
+			{ID: "jira-OPS-2", Title: "Update the docs", Priority: "Low", Severity: "low"},
+		}},
+	})
+	m.plain = true
+	m.width, m.height = 120, 40
+
+	list := m.renderLeftPanel(100)
+	for _, want := range []string{"[CRIT] Checkout is down", "[LOW] Update the docs"} {
+		if !strings.Contains(list, want) {
+			t.Errorf("Expected the list to contain '%s', got: %s", want, list)
+		}
+	}
+	if view := m.renderSinglePanelView(); !strings.Contains(view, "[CRIT] Checkout is down") {
+		t.Errorf("Expected the single panel list to contain the marker, got: %s", view)
+	}
+}
+
+func TestTodoModel_ObsidianTaskPriorities(t *testing.T) {
+	updated := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	due := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+
+	m := NewTodoModel(types.TodoItems{
+		JIRA: types.JIRATodos{AssignedTickets: []types.TodoItem{
+			{ID: "jira-OPS-1", Title: "Checkout is down", UpdatedAt: updated.Add(90 * time.Minute)},
+		}},
+		Obsidian: types.ObsidianTodos{Tasks: []types.TodoItem{
+			{ID: "low", Title: "Tidy the wiki", UpdatedAt: updated.Add(2 * time.Hour), Priority: "low"},
+			{ID: "none", Title: "Water the plants", UpdatedAt: updated.Add(time.Hour)},
+			{ID: "high-undated", Title: "Ship the release", UpdatedAt: updated.Add(time.Hour), Priority: "high"},
+			{ID: "high-due", Title: "Renew the domain", UpdatedAt: updated, Priority: "high", DueDate: &due},
+		}},
+	})
+
+	var ids []string
+	for _, item := range m.allItems {
+		ids = append(ids, item.Item.ID)
+	}
+	expected := []string{"high-due", "jira-OPS-1", "high-undated", "none", "low"}
+	if strings.Join(ids, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected items %v, got %v", expected, ids)
+	}
+}
+
+func TestTodoModel_ReadyToMergeFirst(t *testing.T) {
+	updated := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	m := NewTodoModel(types.TodoItems{
+		GitHub: types.GitHubTodos{
+			OpenPRs: []types.TodoItem{
+				{ID: "github-pr-1", Title: "Add caching", UpdatedAt: updated.Add(time.Hour)},
+				{ID: "github-pr-2", Title: "Fix login", UpdatedAt: updated, ReadyToMerge: true},
+			},
+			PendingReviews: []types.TodoItem{
+				{ID: "github-review-1", Title: "Bump deps", UpdatedAt: updated.Add(2 * time.Hour), ReadyToMerge: true},
+			},
+		},
+	})
+
+	var ids []string
+	for _, item := range m.allItems {
+		ids = append(ids, item.Item.ID)
+	}
+	expected := []string{"github-pr-2", "github-review-1", "github-pr-1"}
+	if strings.Join(ids, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected items %v, got %v", expected, ids)
+	}
+}
+
+func TestTodoModel_ConfluenceItems(t *testing.T) {
+	updated := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	m := NewTodoModel(types.TodoItems{
+		Confluence: types.ConfluenceTodos{
+			Mentions:          []types.TodoItem{{ID: "1", Title: "Design doc", UpdatedAt: updated}},
+			CommentsOnMyPages: []types.TodoItem{{ID: "2", Title: "Re: Runbook", UpdatedAt: updated.Add(time.Hour)}},
+			Tasks:             []types.TodoItem{{ID: "3", Title: "Update the runbook", UpdatedAt: updated.Add(-time.Hour)}},
+			Watched:           []types.TodoItem{{ID: "4", Title: "Updated: Onboarding", UpdatedAt: updated.Add(-2 * time.Hour)}},
+		},
+	})
+
+	if len(m.allItems) != 4 {
+		t.Fatalf("Expected 4 items, got %d", len(m.allItems))
+	}
+	if m.allItems[0].Type != "confluence_comment" || m.allItems[1].Type != "confluence_mention" || m.allItems[2].Type != "confluence_task" || m.allItems[3].Type != "watched_page" {
+		t.Errorf("Expected the comment, the mention, the task then the watched page, got %s, %s, %s and %s",
+			m.allItems[0].Type, m.allItems[1].Type, m.allItems[2].Type, m.allItems[3].Type)
+	}
+	if content := m.createTodoMarkdownContent(m.allItems[3]); !strings.Contains(content, "🔄 Watched Page") {
+		t.Errorf("Expected the watched page type in the details, got:\n%s", content)
+	}
+	if content := m.createTodoMarkdownContent(m.allItems[0]); !strings.Contains(content, "💭 Comment on My Page") {
+		t.Errorf("Expected the item type in the details, got:\n%s", content)
+	}
+}
+
+func TestUpNextModel_KeepsRankOrder(t *testing.T) {
+	updated := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	m := NewUpNextModel([]types.UpNextItem{
+		{Kind: "review_request", Item: types.TodoItem{ID: "github-review-1", Title: "Fix login", UpdatedAt: updated}, Score: 40, Reasons: []string{"review requested +25", "waiting 7 days +15"}},
+		{Kind: "assigned_ticket", Item: types.TodoItem{ID: "jira-OPS-1", Title: "Rotate keys", UpdatedAt: updated.Add(time.Hour)}, Score: 20},
+	})
+
+	if len(m.allItems) != 2 || m.allItems[0].Item.ID != "github-review-1" {
+		t.Fatalf("Expected the items in rank order, got %+v", m.allItems)
+	}
+	if icon := todoItemIcon(m.allItems[0]); icon != "👁️" {
+		t.Errorf("Expected the review request icon, got %s", icon)
+	}
+
+	content := m.createTodoMarkdownContent(m.allItems[0])
+	for _, expected := range []string{"Why it's up next", "Score **40**", "- waiting 7 days +15", "👁️ Review Request"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected the details to contain '%s', got:\n%s", expected, content)
+		}
+	}
+	if content := m.createTodoMarkdownContent(m.allItems[1]); strings.Contains(content, "Why it's up next") {
+		t.Errorf("Expected no score section without reasons, got:\n%s", content)
+	}
+
+	m.width, m.height = 120, 40
+	if view := m.View(); !strings.Contains(view, "Up Next (2)") {
+		t.Errorf("Expected the up-next title in the header, got:\n%s", view)
+	}
+}
+
+func TestCreateTodoMarkdownContent_Excerpt(t *testing.T) {
+	m := TodoModel{}
+	excerpt := `Can Jane review the "cache" section?`
+
+	content := m.createTodoMarkdownContent(TodoListItem{
+		Item: types.TodoItem{ID: "1", Title: "Design doc", Description: excerpt, Excerpt: excerpt},
+		Type: "confluence_mention",
+	})
+
+	if !strings.Contains(content, "## Excerpt\n\n> "+excerpt) {
+		t.Errorf("Expected the excerpt quoted under Excerpt, got:\n%s", content)
+	}
+	if strings.Contains(content, "## Description") {
+		t.Errorf("Expected the excerpt not to be repeated as description, got:\n%s", content)
+	}
+}
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+)
+
+// SubtasksSuffix describes the pending subtasks rolled up under a ticket, e.g. " (3 subtasks pending)",
+// shared by the text output and the TUI
+func SubtasksSuffix(count int) string {
+	switch count {
+	case 0:
+		return ""
+	case 1:
+		return " (1 subtask pending)"
+	default:
+		return fmt.Sprintf(" (%d subtasks pending)", count)
+	}
+}
+
+// taskPriorityOrder lists the priorities of Obsidian tasks from the most urgent, tasks without
+// one ranking between medium and low like in the Tasks plugin
+var taskPriorityOrder = []string{"highest", "high", "medium", "", "low", "lowest"}
+
+// TaskPriorityRank returns the rank of an Obsidian task priority, unknown ones ranking as none
+func TaskPriorityRank(priority string) int {
+	if rank := slices.Index(taskPriorityOrder, priority); rank >= 0 {
+		return rank
+	}
+	return slices.Index(taskPriorityOrder, "")
+}
@@ -1,15 +1,71 @@
 package types
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // TodoItem represents a single todo item (avoiding import cycles)
 type TodoItem struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	URL         string    `json:"url,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Tags        []string  `json:"tags,omitempty"`
+	ID            string     `json:"id"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	URL           string     `json:"url,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Tags          []string   `json:"tags,omitempty"`
+	Milestone     string     `json:"milestone,omitempty"`
+	ProjectStatus string     `json:"project_status,omitempty"`
+	Priority      string     `json:"priority,omitempty"`
+	DueDate       *time.Time `json:"due_date,omitempty"`
+	Sprint        string     `json:"sprint,omitempty"`
+	IssueType     string     `json:"issue_type,omitempty"`     // JIRA issue type, e.g. Bug, Story, Task
+	IsOverdue     bool       `json:"is_overdue,omitempty"`     // Due date has passed
+	ReadyToMerge  bool       `json:"ready_to_merge,omitempty"` // Open PR approved with passing checks and no conflict
+
+	StatusCategory string `json:"status_category,omitempty"` // JIRA status category: new, indeterminate or done
+	StatusSection  string `json:"status_section,omitempty"`  // Todo section of a JIRA ticket, e.g. "In Progress"
+
+	Comments []Comment `json:"comments,omitempty"` // Latest comments, newest first, when details are requested
+
+	Subtasks []TodoItem `json:"subtasks,omitempty"` // Pending subtasks rolled up under a JIRA ticket
+
+	Context    string `json:"context,omitempty"`     // Headings an Obsidian task is under, e.g. "Client X › Meeting 2024-05-12"
+	ParentTask string `json:"parent_task,omitempty"` // Task an indented Obsidian subtask is nested under
+	Snippet    string `json:"snippet,omitempty"`     // Lines around an Obsidian task in its note, other tasks left out
+
+	RelatedNotes []string `json:"related_notes,omitempty"` // Notes an Obsidian task links to, e.g. "Project Phoenix" for [[Project Phoenix]]
+
+	Excerpt string `json:"excerpt,omitempty"` // Text around a Confluence mention, or of a saved Slack message, at most 200 characters
+
+	Details  map[string]string `json:"details,omitempty"`  // Repo, project, space, status or state, see activity.DetailKeys
+	Severity string            `json:"severity,omitempty"` // critical, high, normal or low, from the priority, labels or tags
+
+	Provenance *Provenance `json:"provenance,omitempty"` // Why the item was listed
+}
+
+// UpNextItem represents a todo item or review request ranked in the up-next list
+type UpNextItem struct {
+	Kind    string   `json:"kind"` // review_request, open_pr, assigned_ticket...
+	Item    TodoItem `json:"item"`
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"` // Signals that made the score, e.g. "overdue +40"
+}
+
+// Comment represents a snippet of a comment on a todo item
+type Comment struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Provenance records why an item was listed: where it was fetched from and with which query
+type Provenance struct {
+	Provider  string    `json:"provider"`
+	Query     string    `json:"query,omitempty"`  // Search query, JQL or CQL that returned the item
+	Team      string    `json:"team,omitempty"`   // Team (org/slug) whose review request listed the item
+	Filter    string    `json:"filter,omitempty"` // Configured filter included in the query
+	Source    string    `json:"source,omitempty"` // Where an item not returned by a query was found, e.g. a vault file
+	FetchedAt time.Time `json:"fetched_at"`
 }
 
 // TodoItems represents all pending work items
@@ -18,6 +74,8 @@ type TodoItems struct {
 	JIRA       JIRATodos       `json:"jira"`
 	Obsidian   ObsidianTodos   `json:"obsidian"`
 	Confluence ConfluenceTodos `json:"confluence"`
+	GitLab     GitLabTodos     `json:"gitlab"`
+	Slack      SlackTodos      `json:"slack"`
 }
 
 // GitHubTodos represents pending GitHub work items
@@ -26,9 +84,24 @@ type GitHubTodos struct {
 	PendingReviews []TodoItem `json:"pending_reviews"`
 }
 
+// GitLabTodos represents pending GitLab work items
+type GitLabTodos struct {
+	OpenMRs        []TodoItem `json:"open_mrs"`
+	PendingReviews []TodoItem `json:"pending_reviews"` // Merge requests the user reviews and hasn't approved
+	AssignedIssues []TodoItem `json:"assigned_issues"`
+}
+
+// SlackTodos represents pending Slack work items
+type SlackTodos struct {
+	Saved []TodoItem `json:"saved"` // Messages the user saved for later
+}
+
 // JIRATodos represents pending JIRA work items
 type JIRATodos struct {
 	AssignedTickets []TodoItem `json:"assigned_tickets"`
+	Mentions        []TodoItem `json:"mentions"`
+	Reported        []TodoItem `json:"reported,omitempty"` // Recently updated issues reported by the user, when enabled
+	Watched         []TodoItem `json:"watched,omitempty"`  // Recently updated issues watched by the user, when enabled
 }
 
 // ObsidianTodos represents pending Obsidian work items
@@ -38,27 +111,50 @@ type ObsidianTodos struct {
 
 // ConfluenceTodos represents pending Confluence work items
 type ConfluenceTodos struct {
-	Mentions []TodoItem `json:"mentions"`
+	Mentions          []TodoItem `json:"mentions"`
+	CommentsOnMyPages []TodoItem `json:"comments_on_my_pages"` // Recent comments on pages created by the user
+	Tasks             []TodoItem `json:"tasks"`                // Incomplete inline tasks assigned to the user
+	Watched           []TodoItem `json:"watched,omitempty"`    // Recent updates by others of pages the user watches
 }
 
-// ReviewItems represents all review items
-type ReviewItems struct {
-	GitHub GitHubReviews `json:"github"`
-}
+// Review request types
+const (
+	ReviewRequestUser = "user" // The user was asked to review directly
+	ReviewRequestTeam = "team" // One of the user's teams was asked to review
+)
 
-// GitHubReviews represents review items from GitHub
-type GitHubReviews struct {
-	UserRequests []ReviewItem `json:"user_requests"`
-	TeamRequests []ReviewItem `json:"team_requests"`
-}
+// ReviewItems represents the review items of all platforms
+type ReviewItems []ReviewItem
 
 // ReviewItem represents a pull request awaiting review with additional details
 type ReviewItem struct {
-	TodoItem  TodoItem  `json:"todo_item"`
-	CIStatus  CIStatus  `json:"ci_status"`
-	PRDetails PRDetails `json:"pr_details"`
+	TodoItem    TodoItem  `json:"todo_item"`
+	Platform    string    `json:"platform"`     // Platform of the review, e.g. "github"
+	RequestType string    `json:"request_type"` // ReviewRequestUser or ReviewRequestTeam
+	CIStatus    CIStatus  `json:"ci_status"`
+	PRDetails   PRDetails `json:"pr_details"`
+	AgeDays     int       `json:"age_days"`       // Days since the PR was last updated
+	IsStale     bool      `json:"is_stale"`       // Waiting longer than the configured stale threshold
+	Base        string    `json:"base,omitempty"` // Target branch of the PR, e.g. release/1.2
+
+	InMergeQueue bool `json:"in_merge_queue"` // Queued to be merged, listed last as it no longer needs a review
+
+	Repository string `json:"repository,omitempty"` // owner/repo of the PR
+	Number     int    `json:"number,omitempty"`     // PR number within the repository
 }
 
+// PRComment represents a conversation or inline review comment on a PR
+type PRComment struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Path      string    `json:"path,omitempty"` // File of an inline review comment
+}
+
+// CommentFetcher loads the most recent comments of a PR, newest first
+type CommentFetcher func(ctx context.Context, repo string, number, limit int) ([]PRComment, error)
+
 // CIStatus represents CI check status for a PR
 type CIStatus struct {
 	State      string     `json:"state"` // success, failure, pending
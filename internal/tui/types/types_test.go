@@ -77,3 +77,20 @@ func TestTodoItems_Structure(t *testing.T) {
 		t.Errorf("Expected first open PR ID to be 'test-item', got '%s'", todoItems.GitHub.OpenPRs[0].ID)
 	}
 }
+
+func TestSubtasksSuffix(t *testing.T) {
+	for count, expected := range map[int]string{0: "", 1: " (1 subtask pending)", 3: " (3 subtasks pending)"} {
+		if got := SubtasksSuffix(count); got != expected {
+			t.Errorf("SubtasksSuffix(%d) = %q, expected %q", count, got, expected)
+		}
+	}
+}
+
+func TestTaskPriorityRank(t *testing.T) {
+	if TaskPriorityRank("highest") >= TaskPriorityRank("high") || TaskPriorityRank("medium") >= TaskPriorityRank("") || TaskPriorityRank("") >= TaskPriorityRank("low") {
+		t.Error("Expected priorities ranked from the most urgent, tasks without one between medium and low")
+	}
+	if TaskPriorityRank("urgent") != TaskPriorityRank("") {
+		t.Error("Expected an unknown priority to rank as none")
+	}
+}
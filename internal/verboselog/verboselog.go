@@ -0,0 +1,136 @@
+// Package verboselog prints the progress of commands run with --verbose: the providers queried,
+// what they returned and the steps in between, one line each with the same icons everywhere.
+package verboselog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"daily/internal/terminal"
+)
+
+// displayNames are the names of the providers as shown to the user, by provider name
+var displayNames = map[string]string{
+	"github":        "GitHub",
+	"jira":          "JIRA",
+	"obsidian":      "Obsidian",
+	"confluence":    "Confluence",
+	"gitlab":        "GitLab",
+	"calendar":      "Google Calendar",
+	"slack":         "Slack",
+	"saved_queries": "Saved queries",
+}
+
+// Reporter prints progress lines. A nil Reporter prints nothing, so that commands pass nil
+// rather than checking --verbose before each line.
+type Reporter struct {
+	w io.Writer
+
+	stepStyle    lipgloss.Style
+	successStyle lipgloss.Style
+	errorStyle   lipgloss.Style
+	warnStyle    lipgloss.Style
+	faintStyle   lipgloss.Style
+}
+
+// New returns a reporter printing to w, in color unless plain
+func New(w io.Writer, plain bool) *Reporter {
+	r := &Reporter{w: w}
+	if plain {
+		return r
+	}
+	r.stepStyle = lipgloss.NewStyle().Foreground(lipgloss.Cyan)
+	r.successStyle = lipgloss.NewStyle().Foreground(lipgloss.Green)
+	r.errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Red)
+	r.warnStyle = lipgloss.NewStyle().Foreground(lipgloss.Yellow)
+	r.faintStyle = lipgloss.NewStyle().Faint(true)
+	return r
+}
+
+// Stderr returns a reporter printing to stderr, keeping stdout for the output of the command.
+// Colors are left out when stderr isn't a terminal, on dumb terminals, terminals without
+// colors and with NO_COLOR set.
+func Stderr() *Reporter {
+	caps := terminal.Detect(os.Stderr, os.Environ())
+	return New(os.Stderr, caps.Plain() || caps.Profile == colorprofile.NoTTY)
+}
+
+// ProviderEnabled reports that a provider is being queried
+func (r *Reporter) ProviderEnabled(name string) {
+	if r == nil {
+		return
+	}
+	r.line(r.stepStyle, "→", "Querying "+displayName(name))
+}
+
+// ProviderSkipped reports that a provider isn't queried, e.g. because it is "disabled"
+func (r *Reporter) ProviderSkipped(name, reason string) {
+	if r == nil {
+		return
+	}
+	r.line(r.faintStyle, "–", fmt.Sprintf("%s skipped: %s", displayName(name), reason))
+}
+
+// ProviderResult reports the number of items a provider returned and how long it took, or
+// the error it failed with
+func (r *Reporter) ProviderResult(name string, count int, took time.Duration, err error) {
+	if r == nil {
+		return
+	}
+	if err != nil {
+		r.line(r.errorStyle, "✗", fmt.Sprintf("%s failed after %s: %v", displayName(name), duration(took), err))
+		return
+	}
+	r.line(r.successStyle, "✓", fmt.Sprintf("%s: %d found in %s", displayName(name), count, duration(took)))
+}
+
+// Step reports a step of the command that isn't tied to a provider
+func (r *Reporter) Step(format string, args ...any) {
+	if r == nil {
+		return
+	}
+	r.line(r.stepStyle, "•", fmt.Sprintf(format, args...))
+}
+
+// Detail reports more about the line above, e.g. the kinds of items a provider returned
+func (r *Reporter) Detail(format string, args ...any) {
+	if r == nil {
+		return
+	}
+	r.line(r.faintStyle, "  ·", fmt.Sprintf(format, args...))
+}
+
+// Warn reports a problem that doesn't stop the command, under the line above
+func (r *Reporter) Warn(format string, args ...any) {
+	if r == nil {
+		return
+	}
+	r.line(r.warnStyle, "  ⚠", fmt.Sprintf(format, args...))
+}
+
+// line prints a line starting with its icon rendered in style
+func (r *Reporter) line(style lipgloss.Style, icon, text string) {
+	fmt.Fprintf(r.w, "%s %s\n", style.Render(icon), text)
+}
+
+// displayName returns the name of a provider as shown to the user, e.g. "GitHub" for github
+func displayName(name string) string {
+	if display, ok := displayNames[name]; ok {
+		return display
+	}
+	return name
+}
+
+// duration rounds a duration for display: to the millisecond under a second, e.g. 120ms,
+// to the tenth of a second above, e.g. 1.2s
+func duration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,63 @@
+package verboselog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReporter(t *testing.T) {
+	var out bytes.Buffer
+	reporter := New(&out, true)
+
+	reporter.ProviderSkipped("obsidian", "disabled")
+	reporter.ProviderEnabled("github")
+	reporter.Warn("GitHub token lacks %s", "notifications")
+	reporter.ProviderResult("github", 12, 1234*time.Millisecond, nil)
+	reporter.Detail("%d open PRs, %d pending reviews", 8, 4)
+	reporter.ProviderResult("saved_queries", 0, 42*time.Millisecond, errors.New("unauthorized"))
+	reporter.ProviderResult("bitbucket", 3, 0, nil)
+	reporter.Step("Retrieved %d activities", 15)
+
+	expected := `– Obsidian skipped: disabled
+→ Querying GitHub
+  ⚠ GitHub token lacks notifications
+✓ GitHub: 12 found in 1.2s
+  · 8 open PRs, 4 pending reviews
+✗ Saved queries failed after 42ms: unauthorized
+✓ bitbucket: 3 found in 0s
+• Retrieved 15 activities
+`
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestReporter_Colors(t *testing.T) {
+	var out bytes.Buffer
+	New(&out, false).ProviderResult("github", 1, time.Second, nil)
+
+	if !strings.Contains(out.String(), "\x1b[") || !strings.HasSuffix(out.String(), " GitHub: 1 found in 1s\n") {
+		t.Errorf("Expected a colored icon before plain text, got %q", out.String())
+	}
+
+	out.Reset()
+	New(&out, true).ProviderResult("github", 1, time.Second, nil)
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Errorf("Expected no escape code in plain mode, got %q", out.String())
+	}
+}
+
+func TestReporter_Nil(t *testing.T) {
+	var reporter *Reporter
+
+	// Every method is a no-op, for commands run without --verbose
+	reporter.ProviderEnabled("github")
+	reporter.ProviderSkipped("github", "disabled")
+	reporter.ProviderResult("github", 1, time.Second, errors.New("unauthorized"))
+	reporter.Step("step")
+	reporter.Detail("detail")
+	reporter.Warn("warning")
+}
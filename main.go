@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/charmbracelet/fang"
 	"github.com/spf13/cobra"
 
 	"daily/cmd"
+	"daily/internal/config"
 )
 
 func main() {
@@ -21,8 +23,46 @@ func main() {
 	rootCmd.AddCommand(cmd.ConfigCmd())
 	rootCmd.AddCommand(cmd.TodoCmd())
 	rootCmd.AddCommand(cmd.ReviewsCmd())
+	rootCmd.AddCommand(cmd.UpNextCmd())
+	rootCmd.AddCommand(cmd.MentionsCmd())
+	rootCmd.AddCommand(cmd.CacheCmd())
+	rootCmd.AddCommand(cmd.ProvidersCmd())
+	rootCmd.AddCommand(cmd.GoalCmd())
+	rootCmd.AddCommand(cmd.StateCmd())
+	rootCmd.AddCommand(cmd.NotifyCmd())
+	rootCmd.AddCommand(cmd.TrayCmd())
+	rootCmd.AddCommand(cmd.ExplainCmd())
+	rootCmd.AddCommand(cmd.HideCmd())
+	rootCmd.AddCommand(cmd.UnhideCmd())
+	rootCmd.AddCommand(cmd.ExportCmd())
+	rootCmd.AddCommand(cmd.SyncCmd())
+	rootCmd.AddCommand(cmd.SchemaCmd())
+	rootCmd.AddCommand(cmd.TelemetryCmd())
 
-	if err := fang.Execute(context.Background(), rootCmd); err != nil {
+	// The command that ran is kept to count its usage when telemetry is enabled, including
+	// when its flags couldn't be parsed
+	var ran *cobra.Command
+	rootCmd.PersistentPreRun = func(command *cobra.Command, args []string) {
+		ran = command
+	}
+	rootCmd.SetFlagErrorFunc(func(command *cobra.Command, err error) error {
+		ran = command
+		return err
+	})
+
+	// A broken config must not prevent running 'daily config' to fix it, so commands run
+	// without aliases when they can't be loaded
+	aliases, err := config.LoadAliases()
+	if err == nil {
+		err = cmd.AddAliases(rootCmd, aliases)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: aliases disabled: %v\n", err)
+	}
+
+	err = fang.Execute(context.Background(), rootCmd)
+	cmd.RecordUsage(ran, err)
+	if err != nil {
 		os.Exit(1)
 	}
 }